@@ -0,0 +1,17 @@
+package amqppub_test
+
+import (
+	"testing"
+
+	"github.com/andyle182810/gframework/amqppub"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_NilConnection(t *testing.T) {
+	t.Parallel()
+
+	publisher, err := amqppub.New(nil, amqppub.Options{}) //nolint:exhaustruct
+
+	require.ErrorIs(t, err, amqppub.ErrNilConnection)
+	require.Nil(t, publisher)
+}
@@ -0,0 +1,584 @@
+// Package amqpsub provides a RabbitMQ subscriber with the same API and delivery semantics as redissub.
+//
+// Each subscriber consumes a durable queue named "<consumer group>.<topic>", bound to the topic
+// exchange with the topic as routing key, so instances of a consumer group share the messages of a
+// topic and every consumer group gets its own copy, as with Redis Streams:
+//
+//	subscriber, err := amqpsub.NewSubscriber(conn, "shipping", "orders", handler,
+//	    amqpsub.WithRetry(3, 5*time.Second, "orders-dlq"),
+//	    amqpsub.WithPrefetch(20),
+//	)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	runner.New(runner.WithCoreService(subscriber)).Run()
+//
+// Retries go through the broker instead of blocking the consumer: a failed message is rejected into
+// the dead letter exchange of its queue, which routes it to "<queue>.retry"; once the retry delay, the
+// TTL of that queue, has passed, the broker dead letters it back to the queue. The number of attempts
+// is read from the x-death header. Once retries are exhausted the message is published to the durable
+// queue DLQTopic, keeping its message ID and carrying original_topic, consumer_group, deliveries, error
+// and failed_at headers, and acknowledged.
+//
+// The queue arguments depend on whether retries are enabled and the broker refuses to redeclare a
+// queue with other arguments, so enabling or disabling WithRetry for an existing queue requires
+// deleting the queue first. The retry delay can change freely only while "<queue>.retry" is empty.
+package amqpsub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/andyle182810/gframework/amqppub"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultShutdownTimeout = 20 * time.Second
+	defaultExecTimeout     = 30 * time.Second
+	defaultPrefetch        = 10
+	dlqPublishTimeout      = 5 * time.Second
+
+	retryQueueSuffix = ".retry"
+)
+
+var (
+	ErrNilConnection            = errors.New("amqpsub: connection cannot be nil")
+	ErrEmptyConsumerGroup       = errors.New("amqpsub: consumer group name cannot be empty")
+	ErrEmptyTopicName           = errors.New("amqpsub: topic name cannot be empty")
+	ErrNilMessageHandler        = errors.New("amqpsub: message handler cannot be nil")
+	ErrMessageHandlerNotDefined = errors.New("amqpsub: message handler is not defined")
+	ErrExecTimeout              = errors.New("amqpsub: message handler execution timed out")
+	ErrAlreadyRunning           = errors.New("amqpsub: subscriber already running")
+	ErrDeliveriesClosed         = errors.New("amqpsub: delivery channel was closed by the broker")
+	ErrDLQNotConfirmed          = errors.New("amqpsub: broker did not confirm the dead letter message")
+)
+
+// MessageHandler has the same signature as redissub.MessageHandler.
+type MessageHandler func(ctx context.Context, payload message.Payload) error
+
+type Metrics interface {
+	MessageReceived(topic string)
+	MessageProcessed(topic string, duration time.Duration, err error)
+	MessageAcked(topic string)
+	MessageNacked(topic string)
+	MessageSentToDLQ(topic string)
+}
+
+type RetryConfig struct {
+	MaxRetries int           // Maximum number of retries (0 = no retries)
+	RetryDelay time.Duration // Delay between retries, the TTL of the retry queue
+	DLQTopic   string        // Dead letter queue name (empty = no DLQ)
+}
+
+type SubscriberConfig struct {
+	ShutdownTimeout time.Duration // Maximum time to wait for graceful shutdown before force closing
+	ExecTimeout     time.Duration // Maximum time allowed for message handler execution
+	Metrics         Metrics
+	Retry           *RetryConfig
+	Exchange        string // Topic exchange the queue is bound to (default amqppub.DefaultExchange)
+	Prefetch        int    // Number of unacknowledged messages the broker sends ahead (default 10)
+}
+
+type SubscriberOption func(*SubscriberConfig)
+
+func WithMetrics(m Metrics) SubscriberOption {
+	return func(c *SubscriberConfig) {
+		c.Metrics = m
+	}
+}
+
+func WithRetry(maxRetries int, retryDelay time.Duration, dlqTopic string) SubscriberOption {
+	return func(c *SubscriberConfig) {
+		c.Retry = &RetryConfig{
+			MaxRetries: maxRetries,
+			RetryDelay: retryDelay,
+			DLQTopic:   dlqTopic,
+		}
+	}
+}
+
+func WithShutdownTimeout(d time.Duration) SubscriberOption {
+	return func(c *SubscriberConfig) {
+		c.ShutdownTimeout = d
+	}
+}
+
+func WithExecTimeout(d time.Duration) SubscriberOption {
+	return func(c *SubscriberConfig) {
+		c.ExecTimeout = d
+	}
+}
+
+// WithExchange binds the queue to exchange instead of amqppub.DefaultExchange. It must match the
+// exchange of the publisher.
+func WithExchange(exchange string) SubscriberOption {
+	return func(c *SubscriberConfig) {
+		c.Exchange = exchange
+	}
+}
+
+// WithPrefetch sets how many unacknowledged messages the broker sends ahead. Messages are processed
+// one at a time; a higher prefetch hides the network round trip at the cost of messages waiting on an
+// instance while others are idle.
+func WithPrefetch(count int) SubscriberOption {
+	return func(c *SubscriberConfig) {
+		if count > 0 {
+			c.Prefetch = count
+		}
+	}
+}
+
+type Subscriber struct {
+	conn           *amqp.Connection
+	name           string
+	queue          string
+	topic          string
+	consumerGroup  string
+	shutdownSignal chan struct{}
+	stoppedSignal  chan struct{}
+	messageHandler MessageHandler
+	config         SubscriberConfig
+	healthy        atomic.Bool
+	running        atomic.Bool
+}
+
+func NewSubscriber(
+	conn *amqp.Connection,
+	consumerGroup,
+	topic string,
+	messageHandler MessageHandler,
+	opts ...SubscriberOption,
+) (*Subscriber, error) {
+	if conn == nil {
+		return nil, ErrNilConnection
+	}
+
+	if consumerGroup == "" {
+		return nil, ErrEmptyConsumerGroup
+	}
+
+	if topic == "" {
+		return nil, ErrEmptyTopicName
+	}
+
+	if messageHandler == nil {
+		return nil, ErrNilMessageHandler
+	}
+
+	config := defaultSubscriberConfig()
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	//nolint:exhaustruct
+	sub := &Subscriber{
+		conn:           conn,
+		name:           fmt.Sprintf("amqpsub-%s-%s", consumerGroup, topic),
+		queue:          consumerGroup + "." + topic,
+		topic:          topic,
+		consumerGroup:  consumerGroup,
+		shutdownSignal: make(chan struct{}),
+		stoppedSignal:  make(chan struct{}),
+		messageHandler: messageHandler,
+		config:         config,
+	}
+	sub.healthy.Store(false)
+
+	return sub, nil
+}
+
+func defaultSubscriberConfig() SubscriberConfig {
+	return SubscriberConfig{
+		ShutdownTimeout: defaultShutdownTimeout,
+		ExecTimeout:     defaultExecTimeout,
+		Metrics:         nil,
+		Retry:           nil,
+		Exchange:        amqppub.DefaultExchange,
+		Prefetch:        defaultPrefetch,
+	}
+}
+
+func (s *Subscriber) Name() string {
+	return s.name
+}
+
+func (s *Subscriber) IsHealthy() bool {
+	return s.healthy.Load()
+}
+
+func (s *Subscriber) ConsumerGroup() string {
+	return s.consumerGroup
+}
+
+func (s *Subscriber) Topic() string {
+	return s.topic
+}
+
+// Queue returns the name of the queue the subscriber consumes.
+func (s *Subscriber) Queue() string {
+	return s.queue
+}
+
+func (s *Subscriber) retriesEnabled() bool {
+	return s.config.Retry != nil && s.config.Retry.MaxRetries > 0
+}
+
+// declareTopology declares the exchange, the queue with its binding and, when enabled, the retry and
+// dead letter queues.
+func (s *Subscriber) declareTopology(channel *amqp.Channel) error {
+	if err := amqppub.DeclareExchange(channel, s.config.Exchange); err != nil {
+		return err
+	}
+
+	var queueArgs amqp.Table
+
+	if s.retriesEnabled() {
+		retryQueue := s.queue + retryQueueSuffix
+		queueArgs = amqp.Table{
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": retryQueue,
+		}
+
+		_, err := channel.QueueDeclare(retryQueue, true, false, false, false, amqp.Table{
+			"x-message-ttl":             max(s.config.Retry.RetryDelay.Milliseconds(), 0),
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": s.queue,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to declare retry queue %s: %w", retryQueue, err)
+		}
+	}
+
+	if _, err := channel.QueueDeclare(s.queue, true, false, false, false, queueArgs); err != nil {
+		return fmt.Errorf("failed to declare queue %s: %w", s.queue, err)
+	}
+
+	if err := channel.QueueBind(s.queue, s.topic, s.config.Exchange, false, nil); err != nil {
+		return fmt.Errorf("failed to bind queue %s to %s: %w", s.queue, s.config.Exchange, err)
+	}
+
+	if s.config.Retry != nil && s.config.Retry.DLQTopic != "" {
+		if _, err := channel.QueueDeclare(s.config.Retry.DLQTopic, true, false, false, false, nil); err != nil {
+			return fmt.Errorf("failed to declare dead letter queue %s: %w", s.config.Retry.DLQTopic, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *Subscriber) Start(ctx context.Context) error {
+	if !s.running.CompareAndSwap(false, true) {
+		return ErrAlreadyRunning
+	}
+
+	defer close(s.stoppedSignal)
+
+	log.Info().
+		Str("source", "gframework").
+		Str("queue", s.queue).
+		Str("topic", s.topic).
+		Msg("The subscription is being started")
+
+	channel, err := s.conn.Channel()
+	if err != nil {
+		return fmt.Errorf("failed to open channel: %w", err)
+	}
+
+	// Closing the channel returns the prefetched, unacknowledged messages to the queue.
+	defer func() {
+		if err := channel.Close(); err != nil && !errors.Is(err, amqp.ErrClosed) {
+			log.Warn().
+				Str("source", "gframework").
+				Err(err).
+				Str("queue", s.queue).
+				Msg("Failed to close the channel")
+		}
+	}()
+
+	if err := s.setupChannel(channel); err != nil {
+		return err
+	}
+
+	deliveries, err := channel.ConsumeWithContext(ctx, s.queue, s.name, false, false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("subscription to queue %s failed: %w", s.queue, err)
+	}
+
+	s.healthy.Store(true)
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.healthy.Store(false)
+			log.Info().
+				Str("source", "gframework").
+				Str("service_name", s.Name()).
+				Str("queue", s.queue).
+				Msg("Subscriber stopped: context cancelled")
+
+			return ctx.Err()
+		case <-s.shutdownSignal:
+			s.healthy.Store(false)
+			log.Info().
+				Str("source", "gframework").
+				Str("service_name", s.Name()).
+				Str("queue", s.queue).
+				Msg("Subscriber stopped: graceful shutdown initiated")
+
+			return nil
+		case delivery, ok := <-deliveries:
+			if !ok {
+				s.healthy.Store(false)
+
+				return fmt.Errorf("%w: queue %s", ErrDeliveriesClosed, s.queue)
+			}
+
+			s.handleMessage(ctx, channel, delivery)
+		}
+	}
+}
+
+func (s *Subscriber) setupChannel(channel *amqp.Channel) error {
+	if err := channel.Qos(s.config.Prefetch, 0, false); err != nil {
+		return fmt.Errorf("failed to set prefetch: %w", err)
+	}
+
+	// Confirm mode covers the dead letter messages published on this channel.
+	if err := channel.Confirm(false); err != nil {
+		return fmt.Errorf("failed to enable confirm mode: %w", err)
+	}
+
+	return s.declareTopology(channel)
+}
+
+func (s *Subscriber) Stop() error {
+	if !s.running.CompareAndSwap(true, false) {
+		return nil
+	}
+
+	log.Info().
+		Str("source", "gframework").
+		Str("queue", s.queue).
+		Str("consumer_group", s.consumerGroup).
+		Msg("Stopping subscriber")
+
+	s.healthy.Store(false)
+	close(s.shutdownSignal)
+
+	select {
+	case <-s.stoppedSignal:
+		// Good, subscriber stopped cleanly
+	case <-time.After(s.config.ShutdownTimeout):
+		log.Error().
+			Str("source", "gframework").
+			Str("queue", s.queue).
+			Str("consumer_group", s.consumerGroup).
+			Dur("timeout", s.config.ShutdownTimeout).
+			Msg("Timeout waiting for subscriber to stop")
+	}
+
+	log.Info().
+		Str("source", "gframework").
+		Str("queue", s.queue).
+		Str("consumer_group", s.consumerGroup).
+		Msg("Subscriber stopped")
+
+	return nil
+}
+
+func (s *Subscriber) handleMessage(ctx context.Context, channel *amqp.Channel, delivery amqp.Delivery) {
+	if s.config.Metrics != nil {
+		s.config.Metrics.MessageReceived(s.topic)
+	}
+
+	start := time.Now()
+	processingErr := s.executeWithTimeout(ctx, delivery)
+
+	if s.config.Metrics != nil {
+		s.config.Metrics.MessageProcessed(s.topic, time.Since(start), processingErr)
+	}
+
+	if processingErr != nil {
+		s.handleFailedMessage(ctx, channel, delivery, processingErr)
+
+		return
+	}
+
+	if err := delivery.Ack(false); err != nil {
+		log.Warn().
+			Str("source", "gframework").
+			Err(err).
+			Str("message_id", delivery.MessageId).
+			Msg("Failed to acknowledge the message")
+
+		return
+	}
+
+	if s.config.Metrics != nil {
+		s.config.Metrics.MessageAcked(s.topic)
+	}
+}
+
+func (s *Subscriber) executeWithTimeout(ctx context.Context, delivery amqp.Delivery) error {
+	if s.messageHandler == nil {
+		return ErrMessageHandlerNotDefined
+	}
+
+	if s.config.ExecTimeout <= 0 {
+		return s.messageHandler(ctx, delivery.Body)
+	}
+
+	execCtx, cancel := context.WithTimeout(ctx, s.config.ExecTimeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- s.messageHandler(execCtx, delivery.Body)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-execCtx.Done():
+		if errors.Is(execCtx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("%w: exceeded %v", ErrExecTimeout, s.config.ExecTimeout)
+		}
+
+		return execCtx.Err()
+	}
+}
+
+func (s *Subscriber) handleFailedMessage(
+	ctx context.Context,
+	channel *amqp.Channel,
+	delivery amqp.Delivery,
+	processingErr error,
+) {
+	retries := s.retries(delivery)
+
+	log.Error().
+		Str("source", "gframework").
+		Err(processingErr).
+		Str("queue", s.queue).
+		Str("message_id", delivery.MessageId).
+		Int64("retries", retries).
+		Msg("The message processing has failed")
+
+	if s.config.Metrics != nil {
+		s.config.Metrics.MessageNacked(s.topic)
+	}
+
+	if s.retriesEnabled() && retries < int64(s.config.Retry.MaxRetries) {
+		// Rejecting without requeue dead letters the message into the retry queue.
+		if err := delivery.Nack(false, false); err != nil {
+			log.Warn().
+				Str("source", "gframework").
+				Err(err).
+				Str("message_id", delivery.MessageId).
+				Msg("Failed to reject the message for retry")
+		}
+
+		return
+	}
+
+	if s.config.Retry != nil && s.config.Retry.DLQTopic != "" {
+		if err := s.sendToDLQ(ctx, channel, delivery, retries+1, processingErr); err != nil {
+			log.Error().
+				Str("source", "gframework").
+				Err(err).
+				Str("message_id", delivery.MessageId).
+				Msg("Failed to send message to DLQ")
+		} else if s.config.Metrics != nil {
+			s.config.Metrics.MessageSentToDLQ(s.topic)
+		}
+	}
+
+	// Acknowledge the message to prevent an infinite redelivery loop, as redissub does. It has either
+	// been sent to the DLQ or logged as failed after exhausting retries.
+	if err := delivery.Ack(false); err != nil {
+		log.Warn().
+			Str("source", "gframework").
+			Err(err).
+			Str("message_id", delivery.MessageId).
+			Msg("Failed to acknowledge the failed message")
+	}
+}
+
+// retries returns how often the message was rejected from the queue into the retry queue, from the
+// x-death header the broker maintains when dead lettering.
+func (s *Subscriber) retries(delivery amqp.Delivery) int64 {
+	deaths, _ := delivery.Headers["x-death"].([]any)
+
+	for _, death := range deaths {
+		table, ok := death.(amqp.Table)
+		if !ok || table["queue"] != s.queue || table["reason"] != "rejected" {
+			continue
+		}
+
+		count, _ := table["count"].(int64)
+
+		return count
+	}
+
+	return 0
+}
+
+func (s *Subscriber) sendToDLQ(
+	ctx context.Context,
+	channel *amqp.Channel,
+	delivery amqp.Delivery,
+	deliveries int64,
+	processingErr error,
+) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), dlqPublishTimeout)
+	defer cancel()
+
+	headers := amqp.Table{}
+
+	for key, value := range delivery.Headers {
+		if key != "x-death" {
+			headers[key] = value
+		}
+	}
+
+	headers["original_topic"] = s.topic
+	headers["consumer_group"] = s.consumerGroup
+	headers["deliveries"] = deliveries
+	headers["error"] = processingErr.Error()
+	headers["failed_at"] = time.Now().UTC().Format(time.RFC3339)
+
+	//nolint:exhaustruct
+	confirmation, err := channel.PublishWithDeferredConfirmWithContext(ctx, "", s.config.Retry.DLQTopic, false, false,
+		amqp.Publishing{
+			Headers:         headers,
+			ContentType:     delivery.ContentType,
+			ContentEncoding: delivery.ContentEncoding,
+			DeliveryMode:    amqp.Persistent,
+			MessageId:       delivery.MessageId,
+			Timestamp:       delivery.Timestamp,
+			Body:            delivery.Body,
+		})
+	if err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", s.config.Retry.DLQTopic, err)
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", s.config.Retry.DLQTopic, err)
+	}
+
+	if !acked {
+		return fmt.Errorf("%w: %s", ErrDLQNotConfirmed, s.config.Retry.DLQTopic)
+	}
+
+	return nil
+}
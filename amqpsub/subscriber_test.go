@@ -0,0 +1,173 @@
+//nolint:exhaustruct
+package amqpsub_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/andyle182810/gframework/amqppub"
+	"github.com/andyle182810/gframework/amqpsub"
+	"github.com/andyle182810/gframework/testutil"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/require"
+)
+
+const testTimeout = 15 * time.Second
+
+var errHandlerFailed = errors.New("handler failed")
+
+func noopHandler(context.Context, message.Payload) error {
+	return nil
+}
+
+func startSubscriber(t *testing.T, sub *amqpsub.Subscriber) {
+	t.Helper()
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- sub.Start(context.Background())
+	}()
+
+	require.Eventually(t, sub.IsHealthy, testTimeout, 10*time.Millisecond)
+
+	t.Cleanup(func() {
+		require.NoError(t, sub.Stop())
+		require.NoError(t, <-errCh)
+	})
+}
+
+func TestNewSubscriber(t *testing.T) {
+	t.Parallel()
+
+	conn := &amqp.Connection{}
+
+	tests := []struct {
+		name          string
+		conn          *amqp.Connection
+		consumerGroup string
+		topic         string
+		handler       amqpsub.MessageHandler
+		expectError   error
+	}{
+		{name: "valid configuration", conn: conn, consumerGroup: "shipping", topic: "orders", handler: noopHandler},
+		{name: "nil connection", conn: nil, consumerGroup: "shipping", topic: "orders", handler: noopHandler,
+			expectError: amqpsub.ErrNilConnection},
+		{name: "empty consumer group", conn: conn, consumerGroup: "", topic: "orders", handler: noopHandler,
+			expectError: amqpsub.ErrEmptyConsumerGroup},
+		{name: "empty topic", conn: conn, consumerGroup: "shipping", topic: "", handler: noopHandler,
+			expectError: amqpsub.ErrEmptyTopicName},
+		{name: "nil handler", conn: conn, consumerGroup: "shipping", topic: "orders", handler: nil,
+			expectError: amqpsub.ErrNilMessageHandler},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sub, err := amqpsub.NewSubscriber(tt.conn, tt.consumerGroup, tt.topic, tt.handler)
+			if tt.expectError != nil {
+				require.ErrorIs(t, err, tt.expectError)
+				require.Nil(t, sub)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, "amqpsub-shipping-orders", sub.Name())
+			require.Equal(t, "shipping", sub.ConsumerGroup())
+			require.Equal(t, "orders", sub.Topic())
+			require.Equal(t, "shipping.orders", sub.Queue())
+			require.False(t, sub.IsHealthy())
+			require.NoError(t, sub.Stop(), "stopping a subscriber that is not running is a no-op")
+		})
+	}
+}
+
+func TestSubscriber_DeliversPublishedMessages(t *testing.T) {
+	t.Parallel()
+
+	conn := testutil.SetupRabbitMQContainer(t).Connect(t)
+
+	received := make(chan string, 2)
+	sub, err := amqpsub.NewSubscriber(conn, "shipping", "orders",
+		func(_ context.Context, payload message.Payload) error {
+			received <- string(payload)
+
+			return nil
+		},
+		amqpsub.WithExchange("events"),
+		amqpsub.WithPrefetch(5),
+	)
+	require.NoError(t, err)
+	startSubscriber(t, sub)
+
+	require.ErrorIs(t, sub.Start(t.Context()), amqpsub.ErrAlreadyRunning)
+
+	publisher, err := amqppub.New(conn, amqppub.Options{Exchange: "events"})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = publisher.Close() })
+
+	require.NoError(t, publisher.PublishToTopic(t.Context(), "orders", "first", "second"))
+
+	for _, want := range []string{"first", "second"} {
+		select {
+		case got := <-received:
+			require.Equal(t, want, got)
+		case <-time.After(testTimeout):
+			t.Fatalf("message %q was not delivered", want)
+		}
+	}
+}
+
+func TestSubscriber_RetriesAndSendsToDLQ(t *testing.T) {
+	t.Parallel()
+
+	conn := testutil.SetupRabbitMQContainer(t).Connect(t)
+
+	var attempts atomic.Int32
+
+	sub, err := amqpsub.NewSubscriber(conn, "shipping", "orders",
+		func(context.Context, message.Payload) error {
+			attempts.Add(1)
+
+			return errHandlerFailed
+		},
+		amqpsub.WithRetry(2, 50*time.Millisecond, "orders-dlq"),
+	)
+	require.NoError(t, err)
+	startSubscriber(t, sub)
+
+	publisher, err := amqppub.New(conn, amqppub.Options{})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = publisher.Close() })
+
+	require.NoError(t, publisher.PublishToTopic(t.Context(), "orders", `{"id":1}`))
+
+	channel, err := conn.Channel()
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = channel.Close() })
+
+	var dlqMsg amqp.Delivery
+
+	require.Eventually(t, func() bool {
+		msg, ok, err := channel.Get("orders-dlq", true)
+		require.NoError(t, err)
+
+		dlqMsg = msg
+
+		return ok
+	}, testTimeout, 50*time.Millisecond)
+
+	require.JSONEq(t, `{"id":1}`, string(dlqMsg.Body))
+	require.NotEmpty(t, dlqMsg.MessageId)
+	require.Equal(t, "orders", dlqMsg.Headers["original_topic"])
+	require.Equal(t, "shipping", dlqMsg.Headers["consumer_group"])
+	require.EqualValues(t, 3, dlqMsg.Headers["deliveries"])
+	require.Equal(t, errHandlerFailed.Error(), dlqMsg.Headers["error"])
+	require.EqualValues(t, 3, attempts.Load())
+}
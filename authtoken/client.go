@@ -13,17 +13,39 @@
 //	}
 //	// Use token for API requests
 //
+// The client uses the client_credentials grant by default. WithRefreshTokenGrant, WithPasswordGrant and
+// WithJWTBearerGrant select another grant for providers that do not allow client credentials. Whenever a
+// response contains a refresh token, later tokens are obtained with it, and rotated refresh tokens replace
+// the previous one.
+//
+// GetTokenForScopes and GetTokenForAudience request tokens for other scopes and audiences, e.g. to call two
+// APIs with one client; each scope set and audience is cached separately.
+//
+// WithTokenStore shares the cached tokens through a TokenStore, e.g. NewValkeyStore across the replicas of a
+// service or NewFileStore across the runs of a CLI.
+//
+// Clients that authenticate with a certificate or signed assertions instead of the client secret use
+// WithClientCertificate (mTLS) or WithPrivateKeyJWT.
+//
+// Resource servers receiving opaque tokens call Introspect or Validate, which ask the provider's RFC 7662
+// introspection endpoint and cache the result.
+//
 // The client is safe for concurrent use. Call InvalidateToken() to force a token refresh on the next request.
 package authtoken
 
 import (
 	"context"
+	"crypto"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/Nerzal/gocloak/v13"
+	"github.com/andyle182810/gframework/clock"
 )
 
 var ErrNoAccessToken = errors.New("authtoken: no access token in response")
@@ -32,23 +54,50 @@ const tokenExpiryBuffer = 30 * time.Second
 
 type Client struct {
 	gocloak      *gocloak.GoCloak
+	tokenURL     string
 	realm        string
 	clientID     string
 	clientSecret string
-	expiryBuffer time.Duration
-	mu           sync.RWMutex
-	accessToken  string
-	expiresAt    time.Time
+	grantType    GrantType
+	username     string
+	password     string
+	assertion    AssertionFunc
+	clientKey    crypto.Signer
+	clientKeyID  string
+	tlsConfig    *tls.Config
+	// grantRefreshToken is the refresh token of WithRefreshTokenGrant, replaced when it is rotated.
+	grantRefreshToken string
+	expiryBuffer      time.Duration
+	mu                sync.RWMutex
+	tokens            map[tokenKey]*Token
+	store             TokenStore
+	introspectionTTL  time.Duration
+	introspectionMu   sync.RWMutex
+	introspections    map[string]cachedIntrospection
+	clock             clock.Clock
+}
+
+// tokenKey identifies the tokens cached per scope set and audience.
+type tokenKey struct {
+	scope    string
+	audience string
 }
 
 func New(baseURL, realm, clientID, clientSecret string, opts ...Option) *Client {
 	client := &Client{ //nolint:exhaustruct
 		gocloak:      gocloak.NewClient(baseURL),
+		tokenURL:     tokenURL(baseURL, realm),
 		realm:        realm,
 		clientID:     clientID,
 		clientSecret: clientSecret,
+		grantType:    GrantClientCredentials,
 		expiryBuffer: tokenExpiryBuffer,
 		mu:           sync.RWMutex{},
+		tokens:       make(map[tokenKey]*Token),
+
+		introspectionTTL: defaultIntrospectionCacheTTL,
+		introspections:   make(map[string]cachedIntrospection),
+		clock:            clock.Real(),
 	}
 
 	for _, opt := range opts {
@@ -57,31 +106,66 @@ func New(baseURL, realm, clientID, clientSecret string, opts ...Option) *Client
 		}
 	}
 
+	if client.tlsConfig != nil {
+		client.gocloak.RestyClient().SetTLSClientConfig(client.tlsConfig)
+	}
+
 	return client
 }
 
 func (c *Client) GetToken(ctx context.Context) (string, error) {
+	return c.token(ctx, tokenKey{scope: "", audience: ""})
+}
+
+// GetTokenForScopes returns a token requested with the given scopes. Tokens are cached per scope set,
+// regardless of the order of scopes.
+func (c *Client) GetTokenForScopes(ctx context.Context, scopes ...string) (string, error) {
+	return c.token(ctx, tokenKey{scope: scopeKey(scopes), audience: ""})
+}
+
+// GetTokenForAudience returns a token requested for audience and the given scopes, cached per audience
+// and scope set.
+func (c *Client) GetTokenForAudience(ctx context.Context, audience string, scopes ...string) (string, error) {
+	return c.token(ctx, tokenKey{scope: scopeKey(scopes), audience: audience})
+}
+
+func (c *Client) token(ctx context.Context, key tokenKey) (string, error) {
 	c.mu.RLock()
-	if c.accessToken != "" && time.Now().Before(c.expiresAt) {
-		token := c.accessToken
+	if cached := c.tokens[key]; cached.valid(c.clock.Now()) {
+		token := cached.AccessToken
 		c.mu.RUnlock()
 
 		return token, nil
 	}
 	c.mu.RUnlock()
 
-	return c.getToken(ctx)
+	return c.getToken(ctx, key)
 }
 
-func (c *Client) getToken(ctx context.Context) (string, error) {
+func (c *Client) getToken(ctx context.Context, key tokenKey) (string, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if c.accessToken != "" && time.Now().Before(c.expiresAt) {
-		return c.accessToken, nil
+	cached := c.tokens[key]
+	if cached.valid(c.clock.Now()) {
+		return cached.AccessToken, nil
+	}
+
+	if cached == nil {
+		cached = &Token{} //nolint:exhaustruct
+		c.tokens[key] = cached
+	}
+
+	// Another replica or process may have obtained or refreshed the token in the meantime.
+	if c.store != nil {
+		c.loadStored(ctx, key, cached)
+
+		if cached.valid(c.clock.Now()) {
+			return cached.AccessToken, nil
+		}
 	}
 
-	jwt, err := c.gocloak.LoginClient(ctx, c.clientID, c.clientSecret, c.realm)
+	jwt, err := c.fetchToken(ctx, key, cached)
 	if err != nil {
 		return "", fmt.Errorf("failed to fetch token: %w", err)
 	}
@@ -90,18 +174,49 @@ func (c *Client) getToken(ctx context.Context) (string, error) {
 		return "", ErrNoAccessToken
 	}
 
-	expiresIn := time.Duration(jwt.ExpiresIn) * time.Second
+	c.storeToken(cached, jwt)
+
+	if c.store != nil {
+		c.saveStored(ctx, key, cached)
+	}
+
+	return cached.AccessToken, nil
+}
 
-	c.accessToken = jwt.AccessToken
-	c.expiresAt = time.Now().Add(expiresIn - c.expiryBuffer)
+// SetClientSecret replaces the client secret, e.g. when secrets.Watcher sees it rotated. Cached tokens
+// stay valid until they expire; tokens are requested with the new secret from then on.
+func (c *Client) SetClientSecret(secret string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-	return c.accessToken, nil
+	c.clientSecret = secret
 }
 
+func (c *Client) currentClientSecret() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.clientSecret
+}
+
+// InvalidateToken forces a token refresh on the next request for every scope set and audience.
 func (c *Client) InvalidateToken() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.accessToken = ""
-	c.expiresAt = time.Time{}
+	for _, cached := range c.tokens {
+		cached.AccessToken = ""
+		cached.ExpiresAt = time.Time{}
+	}
+}
+
+func (t *Token) valid(now time.Time) bool {
+	return t != nil && t.AccessToken != "" && now.Before(t.ExpiresAt)
+}
+
+func scopeKey(scopes []string) string {
+	sorted := slices.Clone(scopes)
+	slices.Sort(sorted)
+
+	return strings.Join(slices.Compact(sorted), " ")
 }
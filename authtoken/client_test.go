@@ -77,10 +77,15 @@ func (s *tokenStub) handleToken(t *testing.T, w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	token := s.token
+	if scope, audience := r.Form.Get("scope"), r.Form.Get("audience"); scope != "" || audience != "" {
+		token = fmt.Sprintf("%s|%s|%s", token, scope, audience)
+	}
+
 	_, err = fmt.Fprintf(
 		w,
 		`{"access_token":%q,"token_type":"Bearer","expires_in":%d}`,
-		s.token,
+		token,
 		s.expiresIn,
 	)
 	require.NoError(t, err)
@@ -236,6 +241,34 @@ func TestClient_InvalidateTokenClearsCache(t *testing.T) {
 	require.Equal(t, int32(2), stub.tokenCalls.Load())
 }
 
+func TestClient_SetClientSecretUsesNewSecret(t *testing.T) {
+	t.Parallel()
+
+	secrets := make(chan string, 2)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, secret, _ := r.BasicAuth()
+		secrets <- secret
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"svc-token","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer server.Close()
+
+	client := newClient(server.URL)
+
+	_, err := client.GetToken(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, "test-secret", <-secrets)
+
+	client.SetClientSecret("rotated-secret")
+	client.InvalidateToken()
+
+	_, err = client.GetToken(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, "rotated-secret", <-secrets)
+}
+
 func TestClient_ConcurrentRequestsShareToken(t *testing.T) {
 	t.Parallel()
 
@@ -313,3 +346,38 @@ func TestClient_ReturnsUpdatedTokenAfterExpiry(t *testing.T) {
 	require.NotEqual(t, firstToken, secondToken)
 	require.Equal(t, int32(2), stub.tokenCalls.Load())
 }
+
+func TestClient_CachesTokensPerScopesAndAudience(t *testing.T) {
+	t.Parallel()
+
+	stub := newTokenStub()
+
+	server := stub.newServer(t)
+	defer server.Close()
+
+	client := newClient(server.URL)
+
+	token, err := client.GetTokenForScopes(t.Context(), "orders:write", "orders:read")
+	require.NoError(t, err)
+	require.Equal(t, "svc-token|orders:read orders:write|", token)
+
+	token, err = client.GetTokenForScopes(t.Context(), "orders:read", "orders:write")
+	require.NoError(t, err)
+	require.Equal(t, "svc-token|orders:read orders:write|", token)
+
+	token, err = client.GetTokenForAudience(t.Context(), "billing-api", "invoices:read")
+	require.NoError(t, err)
+	require.Equal(t, "svc-token|invoices:read|billing-api", token)
+
+	token, err = client.GetToken(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, "svc-token", token)
+
+	require.Equal(t, int32(3), stub.tokenCalls.Load())
+
+	client.InvalidateToken()
+
+	_, err = client.GetTokenForScopes(t.Context(), "orders:read", "orders:write")
+	require.NoError(t, err)
+	require.Equal(t, int32(4), stub.tokenCalls.Load())
+}
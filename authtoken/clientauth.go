@@ -0,0 +1,123 @@
+package authtoken
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Nerzal/gocloak/v13"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	clientAssertionType     = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+	clientAssertionLifetime = time.Minute
+)
+
+var ErrUnsupportedClientKey = errors.New("authtoken: unsupported private key type for private_key_jwt")
+
+// WithTLSConfig sets the TLS configuration of the connections to the identity provider, e.g. with a
+// client certificate for mTLS client authentication (tls_client_auth). Pass an empty client secret when
+// the certificate is the only client credential.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(c *Client) {
+		c.tlsConfig = cfg
+	}
+}
+
+// WithClientCertificate authenticates the client with cert over mTLS, see WithTLSConfig.
+func WithClientCertificate(cert tls.Certificate) Option {
+	cfg := new(tls.Config)
+	cfg.Certificates = []tls.Certificate{cert}
+	cfg.MinVersion = tls.VersionTLS12
+
+	return WithTLSConfig(cfg)
+}
+
+// WithPrivateKeyJWT authenticates the client with assertions signed by key (private_key_jwt) instead of
+// the client secret. key is an *rsa.PrivateKey, *ecdsa.PrivateKey or ed25519.PrivateKey; keyID is set as
+// the kid header when not empty, so the provider can pick the matching key of the client's JWKS.
+func WithPrivateKeyJWT(key crypto.Signer, keyID string) Option {
+	return func(c *Client) {
+		c.clientKey = key
+		c.clientKeyID = keyID
+	}
+}
+
+// clientAssertion signs the assertion of RFC 7523 section 2.2 for the token endpoint.
+func (c *Client) clientAssertion() (string, error) {
+	method, err := signingMethod(c.clientKey)
+	if err != nil {
+		return "", err
+	}
+
+	now := c.clock.Now()
+
+	token := jwt.NewWithClaims(method, jwt.RegisteredClaims{
+		Issuer:    c.clientID,
+		Subject:   c.clientID,
+		Audience:  jwt.ClaimStrings{c.tokenURL},
+		ExpiresAt: jwt.NewNumericDate(now.Add(clientAssertionLifetime)),
+		NotBefore: nil,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ID:        uuid.NewString(),
+	})
+
+	if c.clientKeyID != "" {
+		token.Header["kid"] = c.clientKeyID
+	}
+
+	signed, err := token.SignedString(c.clientKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign client assertion: %w", err)
+	}
+
+	return signed, nil
+}
+
+// authenticate adds the client credentials to a token request, either the client secret or a signed
+// client assertion.
+func (c *Client) authenticate(opts *gocloak.TokenOptions) error {
+	if c.clientKey == nil {
+		opts.ClientSecret = &c.clientSecret
+
+		return nil
+	}
+
+	assertion, err := c.clientAssertion()
+	if err != nil {
+		return err
+	}
+
+	opts.ClientAssertionType = gocloak.StringP(clientAssertionType)
+	opts.ClientAssertion = &assertion
+
+	return nil
+}
+
+func signingMethod(key crypto.Signer) (jwt.SigningMethod, error) { //nolint:ireturn
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		return jwt.SigningMethodRS256, nil
+	case *ecdsa.PrivateKey:
+		switch key.Curve {
+		case elliptic.P256():
+			return jwt.SigningMethodES256, nil
+		case elliptic.P384():
+			return jwt.SigningMethodES384, nil
+		case elliptic.P521():
+			return jwt.SigningMethodES512, nil
+		}
+	case ed25519.PrivateKey:
+		return jwt.SigningMethodEdDSA, nil
+	}
+
+	return nil, fmt.Errorf("%w: %T", ErrUnsupportedClientKey, key)
+}
@@ -0,0 +1,172 @@
+package authtoken_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/authtoken"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+type authStub struct {
+	mu         sync.Mutex
+	form       url.Values
+	basicAuth  bool
+	clientCert *x509.Certificate
+}
+
+func newAuthStub() *authStub {
+	return &authStub{
+		mu:         sync.Mutex{},
+		form:       nil,
+		basicAuth:  false,
+		clientCert: nil,
+	}
+}
+
+func (s *authStub) handler(t *testing.T) http.Handler {
+	t.Helper()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+
+		s.mu.Lock()
+		s.form = r.PostForm
+		_, _, s.basicAuth = r.BasicAuth()
+
+		if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+			s.clientCert = r.TLS.PeerCertificates[0]
+		}
+		s.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"svc-token","expires_in":300}`))
+	})
+}
+
+func TestPrivateKeyJWT(t *testing.T) {
+	t.Parallel()
+
+	stub := newAuthStub()
+	server := httptest.NewServer(stub.handler(t))
+	t.Cleanup(server.Close)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	client := authtoken.New(server.URL, testRealm, "test-client", "", authtoken.WithPrivateKeyJWT(key, "key-1"))
+
+	token, err := client.GetToken(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, "svc-token", token)
+
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+
+	require.False(t, stub.basicAuth)
+	require.Equal(t, "urn:ietf:params:oauth:client-assertion-type:jwt-bearer", stub.form.Get("client_assertion_type"))
+
+	claims := &jwt.RegisteredClaims{
+		Issuer:    "",
+		Subject:   "",
+		Audience:  nil,
+		ExpiresAt: nil,
+		NotBefore: nil,
+		IssuedAt:  nil,
+		ID:        "",
+	}
+	parsed, err := jwt.ParseWithClaims(stub.form.Get("client_assertion"), claims, func(*jwt.Token) (any, error) {
+		return &key.PublicKey, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "ES256", parsed.Method.Alg())
+	require.Equal(t, "key-1", parsed.Header["kid"])
+	require.Equal(t, "test-client", claims.Issuer)
+	require.Equal(t, "test-client", claims.Subject)
+	require.Equal(t, jwt.ClaimStrings{server.URL + "/realms/" + testRealm + "/protocol/openid-connect/token"}, claims.Audience)
+	require.NotEmpty(t, claims.ID)
+}
+
+func TestClientCertificate(t *testing.T) {
+	t.Parallel()
+
+	stub := newAuthStub()
+	server := httptest.NewUnstartedServer(stub.handler(t))
+	server.TLS = new(tls.Config)
+	server.TLS.ClientAuth = tls.RequireAnyClientCert
+	server.TLS.MinVersion = tls.VersionTLS12
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	cert := selfSignedCertificate(t, "test-client")
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+
+	tlsConfig := new(tls.Config)
+	tlsConfig.Certificates = []tls.Certificate{cert}
+	tlsConfig.RootCAs = roots
+	tlsConfig.MinVersion = tls.VersionTLS12
+
+	client := authtoken.New(server.URL, testRealm, "test-client", "", authtoken.WithTLSConfig(tlsConfig))
+
+	_, err := client.GetToken(t.Context())
+	require.NoError(t, err)
+
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+
+	require.NotNil(t, stub.clientCert)
+	require.Equal(t, "test-client", stub.clientCert.Subject.CommonName)
+	require.False(t, stub.basicAuth)
+}
+
+func selfSignedCertificate(t *testing.T, commonName string) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := new(x509.Certificate)
+	template.SerialNumber = big.NewInt(1)
+	template.Subject = pkix.Name{
+		Country:            nil,
+		Organization:       nil,
+		OrganizationalUnit: nil,
+		Locality:           nil,
+		Province:           nil,
+		StreetAddress:      nil,
+		PostalCode:         nil,
+		SerialNumber:       "",
+		CommonName:         commonName,
+		Names:              nil,
+		ExtraNames:         nil,
+	}
+	template.NotBefore = time.Now().Add(-time.Minute)
+	template.NotAfter = time.Now().Add(time.Hour)
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	return tls.Certificate{
+		Certificate:                  [][]byte{der},
+		PrivateKey:                   key,
+		SupportedSignatureAlgorithms: nil,
+		OCSPStaple:                   nil,
+		SignedCertificateTimestamps:  nil,
+		Leaf:                         nil,
+	}
+}
@@ -0,0 +1,214 @@
+package authtoken
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Nerzal/gocloak/v13"
+)
+
+type GrantType string
+
+const (
+	GrantClientCredentials GrantType = "client_credentials"
+	GrantRefreshToken      GrantType = "refresh_token"
+	GrantPassword          GrantType = "password"
+	GrantJWTBearer         GrantType = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+)
+
+var (
+	ErrRefreshTokenExpired = errors.New("authtoken: refresh token expired")
+	ErrNoAssertion         = errors.New("authtoken: JWT bearer grant requires an assertion")
+)
+
+// AssertionFunc returns a signed JWT for the JWT bearer grant (RFC 7523). It is called for every
+// token request, since assertions are usually short-lived.
+type AssertionFunc func(ctx context.Context) (string, error)
+
+// WithRefreshTokenGrant obtains tokens with a refresh token, e.g. an offline token issued to the
+// service. Rotated refresh tokens returned by the server replace it.
+func WithRefreshTokenGrant(refreshToken string) Option {
+	return func(c *Client) {
+		c.grantType = GrantRefreshToken
+		c.grantRefreshToken = refreshToken
+	}
+}
+
+// WithPasswordGrant obtains tokens with the resource owner password grant. The client keeps using the
+// returned refresh token and only logs in again once it expires or is rejected.
+func WithPasswordGrant(username, password string) Option {
+	return func(c *Client) {
+		c.grantType = GrantPassword
+		c.username = username
+		c.password = password
+	}
+}
+
+// WithJWTBearerGrant obtains tokens with the JWT bearer grant. The request is sent to the token
+// endpoint of the base URL passed to New, also when WithGoCloakClient is used.
+func WithJWTBearerGrant(assertion AssertionFunc) Option {
+	return func(c *Client) {
+		c.grantType = GrantJWTBearer
+		c.assertion = assertion
+	}
+}
+
+// fetchToken prefers the refresh token of the previous response and falls back to the configured
+// grant when there is none or the server rejects it.
+func (c *Client) fetchToken(ctx context.Context, key tokenKey, cached *Token) (*gocloak.JWT, error) {
+	refreshToken := cached.RefreshToken
+	if refreshToken == "" && c.grantType == GrantRefreshToken {
+		refreshToken = c.grantRefreshToken
+	}
+
+	if refreshToken != "" && (cached.RefreshExpiresAt.IsZero() || c.clock.Now().Before(cached.RefreshExpiresAt)) {
+		opts, err := c.tokenOptions(GrantRefreshToken, key)
+		if err != nil {
+			return nil, err
+		}
+
+		opts.RefreshToken = &refreshToken
+
+		jwt, err := c.gocloak.GetToken(ctx, c.realm, opts)
+		if err == nil || c.grantType == GrantRefreshToken {
+			return jwt, err
+		}
+
+		cached.RefreshToken = ""
+	}
+
+	switch c.grantType {
+	case GrantRefreshToken:
+		return nil, ErrRefreshTokenExpired
+	case GrantPassword:
+		opts, err := c.tokenOptions(GrantPassword, key)
+		if err != nil {
+			return nil, err
+		}
+
+		opts.Username = &c.username
+		opts.Password = &c.password
+
+		return c.gocloak.GetToken(ctx, c.realm, opts)
+	case GrantJWTBearer:
+		return c.loginJWTBearer(ctx, key)
+	case GrantClientCredentials:
+	}
+
+	opts, err := c.tokenOptions(GrantClientCredentials, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.gocloak.GetToken(ctx, c.realm, opts)
+}
+
+func (c *Client) tokenOptions(grantType GrantType, key tokenKey) (gocloak.TokenOptions, error) {
+	opts := gocloak.TokenOptions{ //nolint:exhaustruct
+		ClientID:  &c.clientID,
+		GrantType: gocloak.StringP(string(grantType)),
+	}
+
+	if err := c.authenticate(&opts); err != nil {
+		return opts, err
+	}
+
+	if key.scope != "" {
+		opts.Scope = gocloak.StringP(key.scope)
+	}
+
+	if key.audience != "" {
+		opts.Audience = gocloak.StringP(key.audience)
+	}
+
+	return opts, nil
+}
+
+func (c *Client) loginJWTBearer(ctx context.Context, key tokenKey) (*gocloak.JWT, error) {
+	if c.assertion == nil {
+		return nil, ErrNoAssertion
+	}
+
+	assertion, err := c.assertion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create assertion: %w", err)
+	}
+
+	req := c.gocloak.GetRequest(ctx)
+	if c.clientSecret != "" && c.clientKey == nil {
+		req = c.gocloak.GetRequestWithBasicAuth(ctx, c.clientID, c.clientSecret)
+	}
+
+	form := map[string]string{
+		"grant_type": string(GrantJWTBearer),
+		"assertion":  assertion,
+		"client_id":  c.clientID,
+	}
+
+	if c.clientKey != nil {
+		clientAssertion, err := c.clientAssertion()
+		if err != nil {
+			return nil, err
+		}
+
+		form["client_assertion_type"] = clientAssertionType
+		form["client_assertion"] = clientAssertion
+	}
+
+	if key.scope != "" {
+		form["scope"] = key.scope
+	}
+
+	if key.audience != "" {
+		form["audience"] = key.audience
+	}
+
+	var jwt gocloak.JWT
+
+	resp, err := req.SetFormData(form).SetResult(&jwt).Post(c.tokenURL)
+	if err != nil {
+		return nil, fmt.Errorf("could not get token: %w", err)
+	}
+
+	if resp.IsError() {
+		return nil, &gocloak.APIError{
+			Code:    resp.StatusCode(),
+			Message: "could not get token: " + resp.Status(),
+			Type:    gocloak.APIErrTypeUnknown,
+		}
+	}
+
+	return &jwt, nil
+}
+
+func (c *Client) storeToken(cached *Token, jwt *gocloak.JWT) {
+	now := c.clock.Now()
+
+	cached.AccessToken = jwt.AccessToken
+	cached.ExpiresAt = now.Add(time.Duration(jwt.ExpiresIn)*time.Second - c.expiryBuffer)
+
+	if jwt.RefreshToken == "" {
+		return
+	}
+
+	cached.RefreshToken = jwt.RefreshToken
+	cached.RefreshExpiresAt = time.Time{}
+
+	// Offline tokens report no expiry.
+	if jwt.RefreshExpiresIn > 0 {
+		cached.RefreshExpiresAt = now.Add(time.Duration(jwt.RefreshExpiresIn)*time.Second - c.expiryBuffer)
+	}
+
+	// Servers rotating refresh tokens invalidate the previous one, which the other scopes start from.
+	if c.grantType == GrantRefreshToken {
+		c.grantRefreshToken = jwt.RefreshToken
+	}
+}
+
+func tokenURL(baseURL, realm string) string {
+	return fmt.Sprintf("%s/realms/%s/protocol/openid-connect/token", strings.TrimSuffix(baseURL, "/"), url.PathEscape(realm))
+}
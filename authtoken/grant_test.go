@@ -0,0 +1,142 @@
+package authtoken_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/andyle182810/gframework/authtoken"
+	"github.com/stretchr/testify/require"
+)
+
+// grantStub issues a new access and refresh token for every request and rejects refresh tokens it
+// did not issue last, like a server rotating refresh tokens.
+type grantStub struct {
+	mu           sync.Mutex
+	forms        []url.Values
+	issued       int
+	refreshToken string
+}
+
+func newGrantServer(t *testing.T, stub *grantStub) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+
+		stub.mu.Lock()
+		defer stub.mu.Unlock()
+
+		stub.forms = append(stub.forms, r.PostForm)
+
+		if r.PostForm.Get("grant_type") == "refresh_token" && r.PostForm.Get("refresh_token") != stub.refreshToken {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+
+			return
+		}
+
+		stub.issued++
+		stub.refreshToken = fmt.Sprintf("refresh-%d", stub.issued)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w,
+			`{"access_token":"access-%d","expires_in":0,"refresh_token":%q,"refresh_expires_in":3600}`,
+			stub.issued, stub.refreshToken,
+		)
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func (s *grantStub) grants() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	grants := make([]string, 0, len(s.forms))
+	for _, form := range s.forms {
+		grants = append(grants, form.Get("grant_type"))
+	}
+
+	return grants
+}
+
+func TestPasswordGrantUsesRotatedRefreshTokens(t *testing.T) {
+	t.Parallel()
+
+	stub := &grantStub{} //nolint:exhaustruct
+	server := newGrantServer(t, stub)
+
+	client := authtoken.New(server.URL, testRealm, "test-client", "", authtoken.WithPasswordGrant("alice", "secret"))
+
+	for want := 1; want <= 3; want++ {
+		token, err := client.GetToken(t.Context())
+		require.NoError(t, err)
+		require.Equal(t, fmt.Sprintf("access-%d", want), token)
+	}
+
+	require.Equal(t, []string{"password", "refresh_token", "refresh_token"}, stub.grants())
+
+	stub.mu.Lock()
+	require.Equal(t, "alice", stub.forms[0].Get("username"))
+	require.Equal(t, "refresh-2", stub.forms[2].Get("refresh_token"))
+	stub.refreshToken = "revoked"
+	stub.mu.Unlock()
+
+	// A rejected refresh token falls back to logging in again.
+	token, err := client.GetToken(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, "access-4", token)
+	require.Equal(t, []string{"password", "refresh_token", "refresh_token", "refresh_token", "password"}, stub.grants())
+}
+
+func TestRefreshTokenGrant(t *testing.T) {
+	t.Parallel()
+
+	stub := &grantStub{refreshToken: "offline-token"} //nolint:exhaustruct
+	server := newGrantServer(t, stub)
+
+	client := authtoken.New(server.URL, testRealm, "test-client", "", authtoken.WithRefreshTokenGrant("offline-token"))
+
+	_, err := client.GetToken(t.Context())
+	require.NoError(t, err)
+
+	stub.mu.Lock()
+	stub.refreshToken = "revoked"
+	stub.mu.Unlock()
+
+	_, err = client.GetToken(t.Context())
+	require.Error(t, err)
+	require.Equal(t, []string{"refresh_token", "refresh_token"}, stub.grants())
+}
+
+func TestJWTBearerGrant(t *testing.T) {
+	t.Parallel()
+
+	stub := &grantStub{} //nolint:exhaustruct
+	server := newGrantServer(t, stub)
+
+	client := authtoken.New(server.URL, testRealm, "test-client", "",
+		authtoken.WithJWTBearerGrant(func(context.Context) (string, error) {
+			return "signed-assertion", nil
+		}),
+	)
+
+	token, err := client.GetToken(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, "access-1", token)
+
+	stub.mu.Lock()
+	defer stub.mu.Unlock()
+
+	require.Equal(t, "urn:ietf:params:oauth:grant-type:jwt-bearer", stub.forms[0].Get("grant_type"))
+	require.Equal(t, "signed-assertion", stub.forms[0].Get("assertion"))
+	require.Equal(t, "test-client", stub.forms[0].Get("client_id"))
+}
@@ -0,0 +1,189 @@
+package authtoken
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Nerzal/gocloak/v13"
+)
+
+const (
+	defaultIntrospectionCacheTTL = time.Minute
+	introspectionPruneSize       = 1000
+)
+
+var ErrInactiveToken = errors.New("authtoken: token is not active")
+
+// Introspection is the RFC 7662 introspection response of a token. Claims holds every member of the
+// response, including the ones without a field.
+type Introspection struct {
+	Active    bool           `json:"active"`
+	Scope     string         `json:"scope"`
+	ClientID  string         `json:"client_id"`
+	Username  string         `json:"username"`
+	TokenType string         `json:"token_type"`
+	Subject   string         `json:"sub"`
+	Issuer    string         `json:"iss"`
+	ExpiresAt int64          `json:"exp"`
+	Claims    map[string]any `json:"-"`
+}
+
+func (i *Introspection) HasScope(scope string) bool {
+	return slices.Contains(strings.Fields(i.Scope), scope)
+}
+
+type cachedIntrospection struct {
+	result    *Introspection
+	expiresAt time.Time
+}
+
+// WithIntrospectionCacheTTL caches introspection results for ttl, and never beyond the expiry of an
+// active token. Defaults to 1 minute; a negative ttl disables the cache.
+func WithIntrospectionCacheTTL(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.introspectionTTL = ttl
+	}
+}
+
+// Introspect asks the provider whether token is active, e.g. for opaque tokens that cannot be validated
+// locally with the JWKS. The client authenticates with its own credentials. Inactive tokens are returned
+// with Active false rather than an error; use Validate to require an active token.
+func (c *Client) Introspect(ctx context.Context, token string) (*Introspection, error) {
+	key := introspectionKey(token)
+
+	if result, ok := c.cachedIntrospection(key); ok {
+		return result, nil
+	}
+
+	result, err := c.introspect(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	c.cacheIntrospection(key, result)
+
+	return result, nil
+}
+
+// Validate introspects token and returns ErrInactiveToken unless it is active and has every scope.
+func (c *Client) Validate(ctx context.Context, token string, scopes ...string) (*Introspection, error) {
+	result, err := c.Introspect(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if !result.Active {
+		return nil, ErrInactiveToken
+	}
+
+	for _, scope := range scopes {
+		if !result.HasScope(scope) {
+			return nil, fmt.Errorf("%w: missing scope %q", ErrInactiveToken, scope)
+		}
+	}
+
+	return result, nil
+}
+
+func (c *Client) introspect(ctx context.Context, token string) (*Introspection, error) {
+	form := map[string]string{
+		"token":           token,
+		"token_type_hint": "access_token",
+		"client_id":       c.clientID,
+	}
+
+	req := c.gocloak.GetRequest(ctx)
+	clientSecret := c.currentClientSecret()
+
+	if c.clientKey != nil {
+		clientAssertion, err := c.clientAssertion()
+		if err != nil {
+			return nil, err
+		}
+
+		form["client_assertion_type"] = clientAssertionType
+		form["client_assertion"] = clientAssertion
+	} else if clientSecret != "" {
+		req = c.gocloak.GetRequestWithBasicAuth(ctx, c.clientID, clientSecret)
+	}
+
+	resp, err := req.SetFormData(form).Post(c.tokenURL + "/introspect")
+	if err != nil {
+		return nil, fmt.Errorf("could not introspect token: %w", err)
+	}
+
+	if resp.IsError() {
+		return nil, &gocloak.APIError{
+			Code:    resp.StatusCode(),
+			Message: "could not introspect token: " + resp.Status(),
+			Type:    gocloak.APIErrTypeUnknown,
+		}
+	}
+
+	var result Introspection
+	if err := json.Unmarshal(resp.Body(), &result); err != nil {
+		return nil, fmt.Errorf("failed to decode introspection response: %w", err)
+	}
+
+	if err := json.Unmarshal(resp.Body(), &result.Claims); err != nil {
+		return nil, fmt.Errorf("failed to decode introspection response: %w", err)
+	}
+
+	return &result, nil
+}
+
+func (c *Client) cachedIntrospection(key string) (*Introspection, bool) {
+	if c.introspectionTTL < 0 {
+		return nil, false
+	}
+
+	c.introspectionMu.RLock()
+	defer c.introspectionMu.RUnlock()
+
+	cached, ok := c.introspections[key]
+	if !ok || !c.clock.Now().Before(cached.expiresAt) {
+		return nil, false
+	}
+
+	return cached.result, true
+}
+
+func (c *Client) cacheIntrospection(key string, result *Introspection) {
+	if c.introspectionTTL < 0 {
+		return
+	}
+
+	now := c.clock.Now()
+
+	expiresAt := now.Add(c.introspectionTTL)
+	if tokenExpiry := time.Unix(result.ExpiresAt, 0); result.Active && result.ExpiresAt > 0 && tokenExpiry.Before(expiresAt) {
+		expiresAt = tokenExpiry
+	}
+
+	c.introspectionMu.Lock()
+	defer c.introspectionMu.Unlock()
+
+	if len(c.introspections) >= introspectionPruneSize {
+		for cachedKey, cached := range c.introspections {
+			if !now.Before(cached.expiresAt) {
+				delete(c.introspections, cachedKey)
+			}
+		}
+	}
+
+	c.introspections[key] = cachedIntrospection{result: result, expiresAt: expiresAt}
+}
+
+// introspectionKey avoids keeping the tokens themselves in memory.
+func introspectionKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
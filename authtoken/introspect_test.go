@@ -0,0 +1,93 @@
+package authtoken_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/authtoken"
+	"github.com/stretchr/testify/require"
+)
+
+func newIntrospectionServer(t *testing.T, calls *atomic.Int32) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.True(t, strings.HasSuffix(r.URL.Path, "/protocol/openid-connect/token/introspect"))
+		require.NoError(t, r.ParseForm())
+
+		user, pass, ok := r.BasicAuth()
+		require.True(t, ok)
+		require.Equal(t, "test-client", user)
+		require.Equal(t, "test-secret", pass)
+
+		calls.Add(1)
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.PostForm.Get("token") != "opaque-token" {
+			_, _ = w.Write([]byte(`{"active":false}`))
+
+			return
+		}
+
+		_, _ = fmt.Fprintf(w,
+			`{"active":true,"scope":"orders:read profile","client_id":"web","sub":"user-1","exp":%d,"tenant":"acme"}`,
+			time.Now().Add(time.Hour).Unix(),
+		)
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestIntrospectCachesActiveTokens(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	server := newIntrospectionServer(t, &calls)
+	client := authtoken.New(server.URL, testRealm, "test-client", "test-secret")
+
+	for range 3 {
+		result, err := client.Introspect(t.Context(), "opaque-token")
+		require.NoError(t, err)
+		require.True(t, result.Active)
+		require.Equal(t, "user-1", result.Subject)
+		require.Equal(t, "acme", result.Claims["tenant"])
+		require.True(t, result.HasScope("orders:read"))
+	}
+
+	require.Equal(t, int32(1), calls.Load())
+
+	result, err := client.Introspect(t.Context(), "revoked-token")
+	require.NoError(t, err)
+	require.False(t, result.Active)
+	require.Equal(t, int32(2), calls.Load())
+}
+
+func TestIntrospectValidate(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	server := newIntrospectionServer(t, &calls)
+	client := authtoken.New(server.URL, testRealm, "test-client", "test-secret",
+		authtoken.WithIntrospectionCacheTTL(-1))
+
+	_, err := client.Validate(t.Context(), "opaque-token", "orders:read")
+	require.NoError(t, err)
+
+	_, err = client.Validate(t.Context(), "opaque-token", "orders:write")
+	require.ErrorIs(t, err, authtoken.ErrInactiveToken)
+
+	_, err = client.Validate(t.Context(), "revoked-token")
+	require.ErrorIs(t, err, authtoken.ErrInactiveToken)
+
+	require.Equal(t, int32(3), calls.Load())
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/Nerzal/gocloak/v13"
+	"github.com/andyle182810/gframework/clock"
 )
 
 type Option func(*Client)
@@ -16,6 +17,16 @@ func WithTokenExpiryBuffer(buffer time.Duration) Option {
 	}
 }
 
+// WithClock sets the clock used to decide when cached tokens and introspection results expire. It
+// defaults to clock.Real.
+func WithClock(clk clock.Clock) Option {
+	return func(c *Client) {
+		if clk != nil {
+			c.clock = clk
+		}
+	}
+}
+
 func WithGoCloakClient(client *gocloak.GoCloak) Option {
 	return func(c *Client) {
 		if client != nil {
@@ -0,0 +1,267 @@
+package authtoken
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultStorePrefix = "authtoken:"
+	storeFileMode      = 0o600
+)
+
+var ErrTokenNotFound = errors.New("authtoken: token not found in store")
+
+// Token is a cached token as persisted by a TokenStore. ExpiresAt and RefreshExpiresAt already
+// include the expiry buffer; a zero RefreshExpiresAt means the refresh token does not expire.
+type Token struct {
+	AccessToken      string    `json:"access_token"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshToken     string    `json:"refresh_token,omitempty"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at,omitzero"`
+}
+
+// TokenStore shares cached tokens between clients, e.g. across the replicas of a service or the runs
+// of a CLI. Load returns ErrTokenNotFound when there is no token for key.
+type TokenStore interface {
+	Load(ctx context.Context, key string) (*Token, error)
+	Save(ctx context.Context, key string, token *Token) error
+}
+
+// WithTokenStore loads tokens from store before requesting new ones and saves every new token to it.
+// Store failures are logged and never fail GetToken.
+func WithTokenStore(store TokenStore) Option {
+	return func(c *Client) {
+		c.store = store
+	}
+}
+
+// MemoryStore shares tokens between the clients of one process.
+type MemoryStore struct {
+	mu     sync.RWMutex
+	tokens map[string]Token
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		mu:     sync.RWMutex{},
+		tokens: make(map[string]Token),
+	}
+}
+
+func (s *MemoryStore) Load(_ context.Context, key string) (*Token, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	token, ok := s.tokens[key]
+	if !ok {
+		return nil, ErrTokenNotFound
+	}
+
+	return &token, nil
+}
+
+func (s *MemoryStore) Save(_ context.Context, key string, token *Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tokens[key] = *token
+
+	return nil
+}
+
+// ValkeyStore shares tokens between replicas. Entries expire with the last of the access and refresh
+// token.
+type ValkeyStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewValkeyStore stores tokens under prefix, defaulting to "authtoken:".
+func NewValkeyStore(client redis.UniversalClient, prefix string) *ValkeyStore {
+	if prefix == "" {
+		prefix = defaultStorePrefix
+	}
+
+	return &ValkeyStore{
+		client: client,
+		prefix: prefix,
+	}
+}
+
+func (s *ValkeyStore) Load(ctx context.Context, key string) (*Token, error) {
+	data, err := s.client.Get(ctx, s.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrTokenNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to load token: %w", err)
+	}
+
+	var token Token
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("failed to decode token: %w", err)
+	}
+
+	return &token, nil
+}
+
+func (s *ValkeyStore) Save(ctx context.Context, key string, token *Token) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("failed to encode token: %w", err)
+	}
+
+	var ttl time.Duration
+
+	switch {
+	case token.RefreshToken != "" && token.RefreshExpiresAt.IsZero():
+		// Keep offline tokens until they are replaced.
+	case token.RefreshExpiresAt.After(token.ExpiresAt):
+		ttl = time.Until(token.RefreshExpiresAt)
+	default:
+		ttl = time.Until(token.ExpiresAt)
+	}
+
+	if ttl < 0 {
+		return nil
+	}
+
+	if err := s.client.Set(ctx, s.prefix+key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to save token: %w", err)
+	}
+
+	return nil
+}
+
+// FileStore keeps tokens in a JSON file readable only by the owner, e.g. for short-lived CLI processes.
+type FileStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileStore(path string) *FileStore {
+	return &FileStore{
+		mu:   sync.Mutex{},
+		path: path,
+	}
+}
+
+func (s *FileStore) Load(_ context.Context, key string) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	token, ok := tokens[key]
+	if !ok {
+		return nil, ErrTokenNotFound
+	}
+
+	return &token, nil
+}
+
+func (s *FileStore) Save(_ context.Context, key string, token *Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	tokens[key] = *token
+
+	data, err := json.Marshal(tokens)
+	if err != nil {
+		return fmt.Errorf("failed to encode tokens: %w", err)
+	}
+
+	// Write to a temporary file first so that concurrent processes never read a partial file.
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create token file: %w", err)
+	}
+
+	defer func() {
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+
+	if err := os.Chmod(tmp.Name(), storeFileMode); err != nil {
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+
+	return nil
+}
+
+func (s *FileStore) read() (map[string]Token, error) {
+	tokens := make(map[string]Token)
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return tokens, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, fmt.Errorf("failed to decode token file: %w", err)
+	}
+
+	return tokens, nil
+}
+
+func (c *Client) loadStored(ctx context.Context, key tokenKey, cached *Token) {
+	token, err := c.store.Load(ctx, c.storeKey(key))
+	if errors.Is(err, ErrTokenNotFound) {
+		return
+	}
+
+	if err != nil {
+		log.Warn().Str("source", "gframework").Err(err).Msg("Failed to load token from store")
+
+		return
+	}
+
+	*cached = *token
+}
+
+func (c *Client) saveStored(ctx context.Context, key tokenKey, cached *Token) {
+	if err := c.store.Save(ctx, c.storeKey(key), cached); err != nil {
+		log.Warn().Str("source", "gframework").Err(err).Msg("Failed to save token to store")
+	}
+}
+
+// storeKey identifies the tokens of the same server, client, user, scope set and audience.
+func (c *Client) storeKey(key tokenKey) string {
+	return fmt.Sprintf("%s|%s|%s|%s|%s|%s", c.tokenURL, c.clientID, c.grantType, c.username, key.scope, key.audience)
+}
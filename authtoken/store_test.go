@@ -0,0 +1,111 @@
+package authtoken_test
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/authtoken"
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/andyle182810/gframework/valkey"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_SharesTokensThroughMemoryStore(t *testing.T) {
+	t.Parallel()
+
+	stub := newTokenStub()
+
+	server := stub.newServer(t)
+	defer server.Close()
+
+	store := authtoken.NewMemoryStore()
+
+	first := authtoken.New(server.URL, testRealm, "test-client", "test-secret", authtoken.WithTokenStore(store))
+	second := authtoken.New(server.URL, testRealm, "test-client", "test-secret", authtoken.WithTokenStore(store))
+
+	token, err := first.GetToken(t.Context())
+	require.NoError(t, err)
+
+	shared, err := second.GetToken(t.Context())
+	require.NoError(t, err)
+
+	require.Equal(t, token, shared)
+	require.Equal(t, int32(1), stub.tokenCalls.Load())
+}
+
+func TestFileStore(t *testing.T) {
+	t.Parallel()
+
+	stub := newTokenStub()
+
+	server := stub.newServer(t)
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "tokens.json")
+
+	for range 2 {
+		client := authtoken.New(server.URL, testRealm, "test-client", "test-secret",
+			authtoken.WithTokenStore(authtoken.NewFileStore(path)),
+		)
+
+		token, err := client.GetToken(t.Context())
+		require.NoError(t, err)
+		require.Equal(t, "svc-token", token)
+	}
+
+	require.Equal(t, int32(1), stub.tokenCalls.Load())
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestFileStore_MissingToken(t *testing.T) {
+	t.Parallel()
+
+	store := authtoken.NewFileStore(filepath.Join(t.TempDir(), "tokens.json"))
+
+	_, err := store.Load(t.Context(), "missing")
+	require.ErrorIs(t, err, authtoken.ErrTokenNotFound)
+}
+
+func TestValkeyStore(t *testing.T) {
+	t.Parallel()
+
+	container := testutil.SetupValkeyContainer(t)
+
+	port, err := strconv.Atoi(container.Port.Port())
+	require.NoError(t, err)
+
+	//nolint:exhaustruct
+	valkeyClient, err := valkey.New(&valkey.Config{
+		Host: container.Host,
+		Port: port,
+		DB:   container.DB,
+	})
+	require.NoError(t, err)
+
+	store := authtoken.NewValkeyStore(valkeyClient.Client, "")
+
+	_, err = store.Load(t.Context(), "svc")
+	require.ErrorIs(t, err, authtoken.ErrTokenNotFound)
+
+	saved := &authtoken.Token{
+		AccessToken:      "access",
+		ExpiresAt:        time.Now().Add(time.Minute).UTC().Truncate(time.Second),
+		RefreshToken:     "refresh",
+		RefreshExpiresAt: time.Now().Add(time.Hour).UTC().Truncate(time.Second),
+	}
+	require.NoError(t, store.Save(t.Context(), "svc", saved))
+
+	loaded, err := store.Load(t.Context(), "svc")
+	require.NoError(t, err)
+	require.Equal(t, saved, loaded)
+
+	ttl, err := valkeyClient.Client.TTL(t.Context(), "authtoken:svc").Result()
+	require.NoError(t, err)
+	require.Greater(t, ttl, 59*time.Minute)
+}
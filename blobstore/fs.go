@@ -0,0 +1,294 @@
+package blobstore
+
+import (
+	"context"
+	"crypto/md5" //nolint:gosec // ETags are checksums, as with S3.
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const metaDir = ".blobstore-meta"
+
+type FSOption func(*FSStore)
+
+// WithBaseURL makes presigned URLs point at baseURL, e.g. a static file route of the service.
+// Without it they are file:// URLs. The URLs carry the expiry but no signature, so the local store
+// must not serve untrusted clients.
+func WithBaseURL(baseURL string) FSOption {
+	return func(s *FSStore) {
+		s.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// FSStore stores objects as files under a directory. Attributes such as the content type are kept in
+// sidecar files under the ".blobstore-meta" subdirectory.
+type FSStore struct {
+	dir     string
+	baseURL string
+}
+
+var _ Store = (*FSStore)(nil)
+
+func NewFS(dir string, opts ...FSOption) (*FSStore, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("%w: directory is required", ErrConfig)
+	}
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("blobstore: failed to resolve %s: %w", dir, err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, metaDir), 0o750); err != nil {
+		return nil, fmt.Errorf("blobstore: failed to create %s: %w", dir, err)
+	}
+
+	store := &FSStore{dir: dir, baseURL: ""}
+
+	for _, opt := range opts {
+		opt(store)
+	}
+
+	return store, nil
+}
+
+type fsMeta struct {
+	ContentType        string            `json:"contentType,omitempty"`
+	ContentDisposition string            `json:"contentDisposition,omitempty"`
+	CacheControl       string            `json:"cacheControl,omitempty"`
+	ETag               string            `json:"etag"`
+	Metadata           map[string]string `json:"metadata,omitempty"`
+}
+
+// Put writes the body to a temporary file and renames it into place, so readers never see a
+// partially written object. Encryption options are ignored.
+func (s *FSStore) Put(_ context.Context, key string, body io.Reader, opts ...PutOption) (*Object, error) {
+	name, metaName, err := s.paths(key)
+	if err != nil {
+		return nil, err
+	}
+
+	o := newPutOptions(opts)
+
+	if err := os.MkdirAll(filepath.Dir(name), 0o750); err != nil {
+		return nil, fmt.Errorf("blobstore: failed to create directory for %s: %w", key, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return nil, fmt.Errorf("blobstore: failed to create %s: %w", key, err)
+	}
+	defer os.Remove(tmp.Name()) //nolint:errcheck
+
+	hash := md5.New() //nolint:gosec
+
+	size, err := io.Copy(io.MultiWriter(tmp, hash), body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("blobstore: failed to write %s: %w", key, err)
+	}
+
+	meta := fsMeta{
+		ContentType:        o.contentType,
+		ContentDisposition: o.contentDisposition,
+		CacheControl:       o.cacheControl,
+		ETag:               `"` + hex.EncodeToString(hash.Sum(nil)) + `"`,
+		Metadata:           o.metadata,
+	}
+
+	if err := writeMeta(metaName, &meta); err != nil {
+		return nil, fmt.Errorf("blobstore: failed to write attributes of %s: %w", key, err)
+	}
+
+	if err := os.Rename(tmp.Name(), name); err != nil {
+		return nil, fmt.Errorf("blobstore: failed to write %s: %w", key, err)
+	}
+
+	info, err := os.Stat(name)
+	if err != nil {
+		return nil, fmt.Errorf("blobstore: failed to stat %s: %w", key, err)
+	}
+
+	return meta.object(key, size, info.ModTime()), nil
+}
+
+func (s *FSStore) Get(_ context.Context, key string, opts ...GetOption) (io.ReadCloser, *Object, error) {
+	name, metaName, err := s.paths(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := os.Open(name) //nolint:gosec // The key is validated by paths.
+	if err != nil {
+		return nil, nil, s.wrapNotFound(key, err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+
+		return nil, nil, fmt.Errorf("blobstore: failed to stat %s: %w", key, err)
+	}
+
+	meta := readMeta(metaName)
+	object := meta.object(key, info.Size(), info.ModTime())
+
+	o := newGetOptions(opts)
+	if !o.ranged() {
+		return file, object, nil
+	}
+
+	length := info.Size() - o.offset
+	if o.length > 0 {
+		length = min(o.length, length)
+	}
+
+	object.Size = max(length, 0)
+
+	return struct {
+		io.Reader
+		io.Closer
+	}{io.NewSectionReader(file, o.offset, object.Size), file}, object, nil
+}
+
+func (s *FSStore) Stat(_ context.Context, key string) (*Object, error) {
+	name, metaName, err := s.paths(key)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(name)
+	if err != nil {
+		return nil, s.wrapNotFound(key, err)
+	}
+
+	meta := readMeta(metaName)
+
+	return meta.object(key, info.Size(), info.ModTime()), nil
+}
+
+func (s *FSStore) Delete(_ context.Context, key string) error {
+	name, metaName, err := s.paths(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("blobstore: failed to delete %s: %w", key, err)
+	}
+
+	if err := os.Remove(metaName); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("blobstore: failed to delete attributes of %s: %w", key, err)
+	}
+
+	return nil
+}
+
+func (s *FSStore) PresignGet(_ context.Context, key string, ttl time.Duration) (string, error) {
+	return s.presign(key, ttl)
+}
+
+func (s *FSStore) PresignPut(_ context.Context, key string, ttl time.Duration, _ ...PutOption) (string, error) {
+	return s.presign(key, ttl)
+}
+
+func (s *FSStore) presign(key string, ttl time.Duration) (string, error) {
+	name, _, err := s.paths(key)
+	if err != nil {
+		return "", err
+	}
+
+	expires := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+
+	if s.baseURL == "" {
+		return fileURL("file", filepath.ToSlash(name)).String(), nil
+	}
+
+	return s.baseURL + "/" + fileURL("", key).EscapedPath() + "?expires=" + expires, nil
+}
+
+func fileURL(scheme, filePath string) *url.URL {
+	return &url.URL{
+		Scheme:      scheme,
+		Opaque:      "",
+		User:        nil,
+		Host:        "",
+		Path:        filePath,
+		RawPath:     "",
+		OmitHost:    false,
+		ForceQuery:  false,
+		RawQuery:    "",
+		Fragment:    "",
+		RawFragment: "",
+	}
+}
+
+// paths returns the file of the object and of its attributes, rejecting keys that escape the directory.
+func (s *FSStore) paths(key string) (string, string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key ||
+		key == ".." || strings.HasPrefix(key, "../") || strings.HasPrefix(key, metaDir) {
+		return "", "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+
+	local := filepath.FromSlash(key)
+
+	return filepath.Join(s.dir, local), filepath.Join(s.dir, metaDir, local+".json"), nil
+}
+
+func (s *FSStore) wrapNotFound(key string, err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+
+	return fmt.Errorf("blobstore: failed to open %s: %w", key, err)
+}
+
+func (m *fsMeta) object(key string, size int64, modTime time.Time) *Object {
+	return &Object{
+		Key:          key,
+		Size:         size,
+		ContentType:  m.ContentType,
+		ETag:         m.ETag,
+		LastModified: modTime,
+		Metadata:     m.Metadata,
+	}
+}
+
+func writeMeta(name string, meta *fsMeta) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0o750); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(name, data, 0o600)
+}
+
+// readMeta returns empty attributes for objects written without the store.
+func readMeta(name string) *fsMeta {
+	var meta fsMeta
+
+	data, err := os.ReadFile(name) //nolint:gosec // The name is derived from a validated key.
+	if err == nil {
+		_ = json.Unmarshal(data, &meta)
+	}
+
+	return &meta
+}
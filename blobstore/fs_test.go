@@ -0,0 +1,117 @@
+package blobstore_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/blobstore"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFSStore_PutGetStatDelete(t *testing.T) {
+	t.Parallel()
+
+	store, err := blobstore.NewFS(t.TempDir())
+	require.NoError(t, err)
+
+	obj, err := store.Put(t.Context(), "invoices/2024/42.pdf", strings.NewReader("%PDF-1.7"),
+		blobstore.WithContentType("application/pdf"),
+		blobstore.WithMetadata(map[string]string{"order": "42"}),
+	)
+	require.NoError(t, err)
+	require.Equal(t, int64(8), obj.Size)
+	require.NotEmpty(t, obj.ETag)
+
+	body, got, err := store.Get(t.Context(), "invoices/2024/42.pdf")
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	require.NoError(t, body.Close())
+	require.Equal(t, "%PDF-1.7", string(data))
+	require.Equal(t, "application/pdf", got.ContentType)
+	require.Equal(t, obj.ETag, got.ETag)
+
+	stat, err := store.Stat(t.Context(), "invoices/2024/42.pdf")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"order": "42"}, stat.Metadata)
+
+	require.NoError(t, store.Delete(t.Context(), "invoices/2024/42.pdf"))
+	require.NoError(t, store.Delete(t.Context(), "invoices/2024/42.pdf"))
+
+	_, err = store.Stat(t.Context(), "invoices/2024/42.pdf")
+	require.ErrorIs(t, err, blobstore.ErrNotFound)
+
+	_, _, err = store.Get(t.Context(), "invoices/2024/42.pdf")
+	require.ErrorIs(t, err, blobstore.ErrNotFound)
+}
+
+func TestFSStore_GetRange(t *testing.T) {
+	t.Parallel()
+
+	store, err := blobstore.NewFS(t.TempDir())
+	require.NoError(t, err)
+
+	_, err = store.Put(t.Context(), "alphabet.txt", strings.NewReader("abcdefghij"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name           string
+		offset, length int64
+		want           string
+	}{
+		{name: "middle", offset: 2, length: 3, want: "cde"},
+		{name: "to end", offset: 7, length: 0, want: "hij"},
+		{name: "past end", offset: 8, length: 10, want: "ij"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			body, obj, err := store.Get(t.Context(), "alphabet.txt", blobstore.WithRange(tt.offset, tt.length))
+			require.NoError(t, err)
+
+			data, err := io.ReadAll(body)
+			require.NoError(t, err)
+			require.NoError(t, body.Close())
+			require.Equal(t, tt.want, string(data))
+			require.Equal(t, int64(len(tt.want)), obj.Size)
+		})
+	}
+}
+
+func TestFSStore_RejectsInvalidKeys(t *testing.T) {
+	t.Parallel()
+
+	store, err := blobstore.NewFS(t.TempDir())
+	require.NoError(t, err)
+
+	for _, key := range []string{"", "../etc/passwd", "/abs", "a/../../b", "a//b", ".blobstore-meta/x.json"} {
+		_, err := store.Put(t.Context(), key, strings.NewReader("x"))
+		require.ErrorIs(t, err, blobstore.ErrInvalidKey, key)
+	}
+}
+
+func TestFSStore_Presign(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	store, err := blobstore.NewFS(dir)
+	require.NoError(t, err)
+
+	url, err := store.PresignGet(t.Context(), "avatars/42.png", time.Minute)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(url, "file://"))
+	require.True(t, strings.HasSuffix(url, "/avatars/42.png"))
+
+	store, err = blobstore.NewFS(dir, blobstore.WithBaseURL("https://files.example.com/"))
+	require.NoError(t, err)
+
+	url, err = store.PresignPut(t.Context(), "avatars/my avatar.png", time.Minute)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(url, "https://files.example.com/avatars/my%20avatar.png?expires="), url)
+}
@@ -0,0 +1,367 @@
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+const (
+	// DefaultPartSize is the size of multipart upload parts; S3 requires at least 5 MiB.
+	DefaultPartSize = 8 << 20
+	minPartSize     = 5 << 20
+	maxParts        = 10000
+)
+
+// S3Config configures an S3-compatible store. Credentials default to the AWS default chain
+// (environment, shared config, instance role) when AccessKey is empty.
+type S3Config struct {
+	Region    string
+	Endpoint  string // e.g. "https://fra1.digitaloceanspaces.com"; empty for AWS S3
+	Bucket    string
+	KeyPrefix string // empty, or ends with "/"
+	AccessKey string
+	SecretKey string
+	// UsePathStyle addresses the bucket in the path instead of the host name, as MinIO requires.
+	UsePathStyle bool
+	// PartSize is the size of multipart upload parts, at least 5 MiB. It defaults to DefaultPartSize.
+	PartSize int64
+	// Encryption is applied to every object unless overridden with WithEncryption.
+	Encryption Encryption
+}
+
+// S3API is the part of *s3.Client the store uses.
+type S3API interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	HeadObject(ctx context.Context, params *s3.HeadObjectInput, optFns ...func(*s3.Options)) (*s3.HeadObjectOutput, error)
+	DeleteObject(
+		ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options),
+	) (*s3.DeleteObjectOutput, error)
+	CreateMultipartUpload(
+		ctx context.Context, params *s3.CreateMultipartUploadInput, optFns ...func(*s3.Options),
+	) (*s3.CreateMultipartUploadOutput, error)
+	UploadPart(ctx context.Context, params *s3.UploadPartInput, optFns ...func(*s3.Options)) (*s3.UploadPartOutput, error)
+	CompleteMultipartUpload(
+		ctx context.Context, params *s3.CompleteMultipartUploadInput, optFns ...func(*s3.Options),
+	) (*s3.CompleteMultipartUploadOutput, error)
+	AbortMultipartUpload(
+		ctx context.Context, params *s3.AbortMultipartUploadInput, optFns ...func(*s3.Options),
+	) (*s3.AbortMultipartUploadOutput, error)
+}
+
+type S3Option func(*S3Store)
+
+// WithS3Client replaces the client used for object operations, e.g. with a fake in tests. Presigned
+// URLs are still signed with the credentials of the config.
+func WithS3Client(client S3API) S3Option {
+	return func(s *S3Store) {
+		if client != nil {
+			s.api = client
+		}
+	}
+}
+
+// S3Store stores objects in an S3 bucket.
+type S3Store struct {
+	api        S3API
+	presigner  *s3.PresignClient
+	bucket     string
+	prefix     string
+	partSize   int64
+	encryption Encryption
+}
+
+var _ Store = (*S3Store)(nil)
+
+func NewS3(ctx context.Context, cfg S3Config, opts ...S3Option) (*S3Store, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("%w: bucket is required", ErrConfig)
+	}
+
+	if cfg.PartSize == 0 {
+		cfg.PartSize = DefaultPartSize
+	}
+
+	if cfg.PartSize < minPartSize {
+		return nil, fmt.Errorf("%w: part size must be at least 5 MiB", ErrConfig)
+	}
+
+	loadOpts := []func(*config.LoadOptions) error{config.WithRegion(cfg.Region)}
+	if cfg.AccessKey != "" {
+		loadOpts = append(loadOpts, config.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(cfg.AccessKey, cfg.SecretKey, ""),
+		))
+	}
+
+	awsCfg, err := config.LoadDefaultConfig(ctx, loadOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("blobstore: failed to load aws config: %w", err)
+	}
+
+	client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		if cfg.Endpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.Endpoint)
+		}
+
+		o.UsePathStyle = cfg.UsePathStyle
+	})
+
+	store := &S3Store{
+		api:        client,
+		presigner:  s3.NewPresignClient(client),
+		bucket:     cfg.Bucket,
+		prefix:     cfg.KeyPrefix,
+		partSize:   cfg.PartSize,
+		encryption: cfg.Encryption,
+	}
+
+	for _, opt := range opts {
+		opt(store)
+	}
+
+	return store, nil
+}
+
+// Put uploads bodies up to the part size with a single request and larger ones as a multipart
+// upload, holding one part in memory at a time. A failed multipart upload is aborted.
+func (s *S3Store) Put(ctx context.Context, key string, body io.Reader, opts ...PutOption) (*Object, error) {
+	if key == "" {
+		return nil, ErrInvalidKey
+	}
+
+	o := newPutOptions(opts)
+
+	first := make([]byte, s.partSize)
+
+	n, err := io.ReadFull(body, first)
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return s.putSingle(ctx, key, first[:n], o)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("blobstore: failed to read body: %w", err)
+	}
+
+	return s.putMultipart(ctx, key, first, body, o)
+}
+
+func (s *S3Store) putSingle(ctx context.Context, key string, data []byte, o *putOptions) (*Object, error) {
+	input := s.putObjectInput(key, o)
+	input.Body = bytes.NewReader(data)
+	input.ContentLength = aws.Int64(int64(len(data)))
+	input.ContentDisposition = optional(o.contentDisposition)
+	input.CacheControl = optional(o.cacheControl)
+	input.Metadata = o.metadata
+
+	out, err := s.api.PutObject(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("blobstore: failed to put %s: %w", key, err)
+	}
+
+	return &Object{
+		Key:          key,
+		Size:         int64(len(data)),
+		ContentType:  o.contentType,
+		ETag:         aws.ToString(out.ETag),
+		LastModified: time.Now(),
+		Metadata:     o.metadata,
+	}, nil
+}
+
+func (s *S3Store) putMultipart(ctx context.Context, key string, first []byte, body io.Reader, o *putOptions) (*Object, error) {
+	created, err := s.api.CreateMultipartUpload(ctx, s.createMultipartUploadInput(key, o))
+	if err != nil {
+		return nil, fmt.Errorf("blobstore: failed to start multipart upload of %s: %w", key, err)
+	}
+
+	parts, size, err := s.uploadParts(ctx, key, created.UploadId, first, body)
+	if err != nil {
+		s.abort(ctx, key, created.UploadId)
+
+		return nil, err
+	}
+
+	out, err := s.api.CompleteMultipartUpload(ctx, s.completeMultipartUploadInput(key, created.UploadId, parts))
+	if err != nil {
+		s.abort(ctx, key, created.UploadId)
+
+		return nil, fmt.Errorf("blobstore: failed to complete multipart upload of %s: %w", key, err)
+	}
+
+	return &Object{
+		Key:          key,
+		Size:         size,
+		ContentType:  o.contentType,
+		ETag:         aws.ToString(out.ETag),
+		LastModified: time.Now(),
+		Metadata:     o.metadata,
+	}, nil
+}
+
+func (s *S3Store) uploadParts(
+	ctx context.Context,
+	key string,
+	uploadID *string,
+	part []byte,
+	body io.Reader,
+) ([]types.CompletedPart, int64, error) {
+	var (
+		parts []types.CompletedPart
+		size  int64
+		next  = make([]byte, s.partSize)
+	)
+
+	for number := int32(1); len(part) > 0; number++ {
+		if number > maxParts {
+			return nil, 0, ErrTooManyParts
+		}
+
+		out, err := s.api.UploadPart(ctx, s.uploadPartInput(key, uploadID, number, part))
+		if err != nil {
+			return nil, 0, fmt.Errorf("blobstore: failed to upload part %d of %s: %w", number, key, err)
+		}
+
+		parts = append(parts, completedPart(out.ETag, number))
+		size += int64(len(part))
+
+		n, err := io.ReadFull(body, next)
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, 0, fmt.Errorf("blobstore: failed to read body: %w", err)
+		}
+
+		// The part buffers alternate, as the uploaded part is no longer needed.
+		part, next = next[:n], part[:cap(part)]
+	}
+
+	return parts, size, nil
+}
+
+func (s *S3Store) abort(ctx context.Context, key string, uploadID *string) {
+	_, _ = s.api.AbortMultipartUpload(context.WithoutCancel(ctx), s.abortMultipartUploadInput(key, uploadID))
+}
+
+func (s *S3Store) Get(ctx context.Context, key string, opts ...GetOption) (io.ReadCloser, *Object, error) {
+	o := newGetOptions(opts)
+
+	input := s.getObjectInput(key)
+
+	if o.ranged() {
+		end := ""
+		if o.length > 0 {
+			end = strconv.FormatInt(o.offset+o.length-1, 10)
+		}
+
+		input.Range = aws.String(fmt.Sprintf("bytes=%d-%s", o.offset, end))
+	}
+
+	out, err := s.api.GetObject(ctx, input)
+	if err != nil {
+		return nil, nil, s.wrapNotFound(key, "get", err)
+	}
+
+	return out.Body, &Object{
+		Key:          key,
+		Size:         aws.ToInt64(out.ContentLength),
+		ContentType:  aws.ToString(out.ContentType),
+		ETag:         aws.ToString(out.ETag),
+		LastModified: aws.ToTime(out.LastModified),
+		Metadata:     out.Metadata,
+	}, nil
+}
+
+func (s *S3Store) Stat(ctx context.Context, key string) (*Object, error) {
+	out, err := s.api.HeadObject(ctx, s.headObjectInput(key))
+	if err != nil {
+		return nil, s.wrapNotFound(key, "stat", err)
+	}
+
+	return &Object{
+		Key:          key,
+		Size:         aws.ToInt64(out.ContentLength),
+		ContentType:  aws.ToString(out.ContentType),
+		ETag:         aws.ToString(out.ETag),
+		LastModified: aws.ToTime(out.LastModified),
+		Metadata:     out.Metadata,
+	}, nil
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	_, err := s.api.DeleteObject(ctx, s.deleteObjectInput(key))
+	if err != nil {
+		return fmt.Errorf("blobstore: failed to delete %s: %w", key, err)
+	}
+
+	return nil
+}
+
+func (s *S3Store) PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	req, err := s.presigner.PresignGetObject(ctx, s.getObjectInput(key), s3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", fmt.Errorf("blobstore: failed to presign get of %s: %w", key, err)
+	}
+
+	return req.URL, nil
+}
+
+func (s *S3Store) PresignPut(ctx context.Context, key string, ttl time.Duration, opts ...PutOption) (string, error) {
+	o := newPutOptions(opts)
+
+	req, err := s.presigner.PresignPutObject(ctx, s.putObjectInput(key, o), s3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", fmt.Errorf("blobstore: failed to presign put of %s: %w", key, err)
+	}
+
+	return req.URL, nil
+}
+
+func (s *S3Store) fullKey(key string) string {
+	return s.prefix + key
+}
+
+func (s *S3Store) sse(o *putOptions) (types.ServerSideEncryption, *string) {
+	encryption := s.encryption
+	if o.encryption != nil {
+		encryption = *o.encryption
+	}
+
+	if encryption.Algorithm == "" {
+		return "", nil
+	}
+
+	return types.ServerSideEncryption(encryption.Algorithm), optional(encryption.KMSKeyID)
+}
+
+func (s *S3Store) wrapNotFound(key, operation string, err error) error {
+	var (
+		noSuchKey *types.NoSuchKey
+		notFound  *types.NotFound
+		apiErr    smithy.APIError
+	)
+
+	if errors.As(err, &noSuchKey) || errors.As(err, &notFound) ||
+		(errors.As(err, &apiErr) && apiErr.ErrorCode() == "NotFound") {
+		return fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+
+	return fmt.Errorf("blobstore: failed to %s %s: %w", operation, key, err)
+}
+
+func optional(value string) *string {
+	if value == "" {
+		return nil
+	}
+
+	return aws.String(value)
+}
@@ -0,0 +1,257 @@
+package blobstore
+
+import (
+	"bytes"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// putObjectInput returns the input of an upload of key with the content type and encryption of o, the
+// headers a presigned upload signs.
+func (s *S3Store) putObjectInput(key string, o *putOptions) *s3.PutObjectInput {
+	sse, kmsKeyID := s.sse(o)
+
+	return &s3.PutObjectInput{
+		Bucket:                    aws.String(s.bucket),
+		Key:                       aws.String(s.fullKey(key)),
+		ACL:                       "",
+		Body:                      nil,
+		BucketKeyEnabled:          nil,
+		CacheControl:              nil,
+		ChecksumAlgorithm:         "",
+		ChecksumCRC32:             nil,
+		ChecksumCRC32C:            nil,
+		ChecksumCRC64NVME:         nil,
+		ChecksumMD5:               nil,
+		ChecksumSHA1:              nil,
+		ChecksumSHA256:            nil,
+		ChecksumSHA512:            nil,
+		ChecksumXXHASH128:         nil,
+		ChecksumXXHASH3:           nil,
+		ChecksumXXHASH64:          nil,
+		ContentDisposition:        nil,
+		ContentEncoding:           nil,
+		ContentLanguage:           nil,
+		ContentLength:             nil,
+		ContentMD5:                nil,
+		ContentType:               optional(o.contentType),
+		ExpectedBucketOwner:       nil,
+		Expires:                   nil,
+		GrantFullControl:          nil,
+		GrantRead:                 nil,
+		GrantReadACP:              nil,
+		GrantWriteACP:             nil,
+		IfMatch:                   nil,
+		IfNoneMatch:               nil,
+		Metadata:                  nil,
+		ObjectLockLegalHoldStatus: "",
+		ObjectLockMode:            "",
+		ObjectLockRetainUntilDate: nil,
+		RequestPayer:              "",
+		SSECustomerAlgorithm:      nil,
+		SSECustomerKey:            nil,
+		SSECustomerKeyMD5:         nil,
+		SSEKMSEncryptionContext:   nil,
+		SSEKMSKeyId:               kmsKeyID,
+		ServerSideEncryption:      sse,
+		StorageClass:              "",
+		Tagging:                   nil,
+		WebsiteRedirectLocation:   nil,
+		WriteOffsetBytes:          nil,
+	}
+}
+
+func (s *S3Store) createMultipartUploadInput(key string, o *putOptions) *s3.CreateMultipartUploadInput {
+	sse, kmsKeyID := s.sse(o)
+
+	return &s3.CreateMultipartUploadInput{
+		Bucket:                    aws.String(s.bucket),
+		Key:                       aws.String(s.fullKey(key)),
+		ACL:                       "",
+		BucketKeyEnabled:          nil,
+		CacheControl:              optional(o.cacheControl),
+		ChecksumAlgorithm:         "",
+		ChecksumType:              "",
+		ContentDisposition:        optional(o.contentDisposition),
+		ContentEncoding:           nil,
+		ContentLanguage:           nil,
+		ContentType:               optional(o.contentType),
+		ExpectedBucketOwner:       nil,
+		Expires:                   nil,
+		GrantFullControl:          nil,
+		GrantRead:                 nil,
+		GrantReadACP:              nil,
+		GrantWriteACP:             nil,
+		Metadata:                  o.metadata,
+		ObjectLockLegalHoldStatus: "",
+		ObjectLockMode:            "",
+		ObjectLockRetainUntilDate: nil,
+		RequestPayer:              "",
+		SSECustomerAlgorithm:      nil,
+		SSECustomerKey:            nil,
+		SSECustomerKeyMD5:         nil,
+		SSEKMSEncryptionContext:   nil,
+		SSEKMSKeyId:               kmsKeyID,
+		ServerSideEncryption:      sse,
+		StorageClass:              "",
+		Tagging:                   nil,
+		WebsiteRedirectLocation:   nil,
+	}
+}
+
+func (s *S3Store) uploadPartInput(key string, uploadID *string, number int32, part []byte) *s3.UploadPartInput {
+	return &s3.UploadPartInput{
+		Bucket:               aws.String(s.bucket),
+		Key:                  aws.String(s.fullKey(key)),
+		PartNumber:           aws.Int32(number),
+		UploadId:             uploadID,
+		Body:                 bytes.NewReader(part),
+		ChecksumAlgorithm:    "",
+		ChecksumCRC32:        nil,
+		ChecksumCRC32C:       nil,
+		ChecksumCRC64NVME:    nil,
+		ChecksumMD5:          nil,
+		ChecksumSHA1:         nil,
+		ChecksumSHA256:       nil,
+		ChecksumSHA512:       nil,
+		ChecksumXXHASH128:    nil,
+		ChecksumXXHASH3:      nil,
+		ChecksumXXHASH64:     nil,
+		ContentLength:        aws.Int64(int64(len(part))),
+		ContentMD5:           nil,
+		ExpectedBucketOwner:  nil,
+		RequestPayer:         "",
+		SSECustomerAlgorithm: nil,
+		SSECustomerKey:       nil,
+		SSECustomerKeyMD5:    nil,
+	}
+}
+
+func completedPart(etag *string, number int32) types.CompletedPart {
+	return types.CompletedPart{
+		ChecksumCRC32:     nil,
+		ChecksumCRC32C:    nil,
+		ChecksumCRC64NVME: nil,
+		ChecksumMD5:       nil,
+		ChecksumSHA1:      nil,
+		ChecksumSHA256:    nil,
+		ChecksumSHA512:    nil,
+		ChecksumXXHASH128: nil,
+		ChecksumXXHASH3:   nil,
+		ChecksumXXHASH64:  nil,
+		ETag:              etag,
+		PartNumber:        aws.Int32(number),
+	}
+}
+
+func (s *S3Store) completeMultipartUploadInput(
+	key string,
+	uploadID *string,
+	parts []types.CompletedPart,
+) *s3.CompleteMultipartUploadInput {
+	return &s3.CompleteMultipartUploadInput{
+		Bucket:               aws.String(s.bucket),
+		Key:                  aws.String(s.fullKey(key)),
+		UploadId:             uploadID,
+		ChecksumCRC32:        nil,
+		ChecksumCRC32C:       nil,
+		ChecksumCRC64NVME:    nil,
+		ChecksumMD5:          nil,
+		ChecksumSHA1:         nil,
+		ChecksumSHA256:       nil,
+		ChecksumSHA512:       nil,
+		ChecksumType:         "",
+		ChecksumXXHASH128:    nil,
+		ChecksumXXHASH3:      nil,
+		ChecksumXXHASH64:     nil,
+		ExpectedBucketOwner:  nil,
+		IfMatch:              nil,
+		IfNoneMatch:          nil,
+		MpuObjectSize:        nil,
+		MultipartUpload:      &types.CompletedMultipartUpload{Parts: parts},
+		RequestPayer:         "",
+		SSECustomerAlgorithm: nil,
+		SSECustomerKey:       nil,
+		SSECustomerKeyMD5:    nil,
+	}
+}
+
+func (s *S3Store) abortMultipartUploadInput(key string, uploadID *string) *s3.AbortMultipartUploadInput {
+	return &s3.AbortMultipartUploadInput{
+		Bucket:               aws.String(s.bucket),
+		Key:                  aws.String(s.fullKey(key)),
+		UploadId:             uploadID,
+		ExpectedBucketOwner:  nil,
+		IfMatchInitiatedTime: nil,
+		RequestPayer:         "",
+	}
+}
+
+func (s *S3Store) getObjectInput(key string) *s3.GetObjectInput {
+	return &s3.GetObjectInput{
+		Bucket:                     aws.String(s.bucket),
+		Key:                        aws.String(s.fullKey(key)),
+		ChecksumMode:               "",
+		ExpectedBucketOwner:        nil,
+		IfMatch:                    nil,
+		IfModifiedSince:            nil,
+		IfNoneMatch:                nil,
+		IfUnmodifiedSince:          nil,
+		PartNumber:                 nil,
+		Range:                      nil,
+		RequestPayer:               "",
+		ResponseCacheControl:       nil,
+		ResponseContentDisposition: nil,
+		ResponseContentEncoding:    nil,
+		ResponseContentLanguage:    nil,
+		ResponseContentType:        nil,
+		ResponseExpires:            nil,
+		SSECustomerAlgorithm:       nil,
+		SSECustomerKey:             nil,
+		SSECustomerKeyMD5:          nil,
+		VersionId:                  nil,
+	}
+}
+
+func (s *S3Store) headObjectInput(key string) *s3.HeadObjectInput {
+	return &s3.HeadObjectInput{
+		Bucket:                     aws.String(s.bucket),
+		Key:                        aws.String(s.fullKey(key)),
+		ChecksumMode:               "",
+		ExpectedBucketOwner:        nil,
+		IfMatch:                    nil,
+		IfModifiedSince:            nil,
+		IfNoneMatch:                nil,
+		IfUnmodifiedSince:          nil,
+		PartNumber:                 nil,
+		Range:                      nil,
+		RequestPayer:               "",
+		ResponseCacheControl:       nil,
+		ResponseContentDisposition: nil,
+		ResponseContentEncoding:    nil,
+		ResponseContentLanguage:    nil,
+		ResponseContentType:        nil,
+		ResponseExpires:            nil,
+		SSECustomerAlgorithm:       nil,
+		SSECustomerKey:             nil,
+		SSECustomerKeyMD5:          nil,
+		VersionId:                  nil,
+	}
+}
+
+func (s *S3Store) deleteObjectInput(key string) *s3.DeleteObjectInput {
+	return &s3.DeleteObjectInput{
+		Bucket:                    aws.String(s.bucket),
+		Key:                       aws.String(s.fullKey(key)),
+		BypassGovernanceRetention: nil,
+		ExpectedBucketOwner:       nil,
+		IfMatch:                   nil,
+		IfMatchLastModifiedTime:   nil,
+		IfMatchSize:               nil,
+		MFA:                       nil,
+		RequestPayer:              "",
+		VersionId:                 nil,
+	}
+}
@@ -0,0 +1,482 @@
+package blobstore_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/blobstore"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/stretchr/testify/require"
+)
+
+const partSize = 5 << 20
+
+var errPartFailed = errors.New("part failed")
+
+// fakeS3 keeps objects in memory and records the requests it receives.
+type fakeS3 struct {
+	mu        sync.Mutex
+	objects   map[string][]byte
+	puts      []*s3.PutObjectInput
+	creates   []*s3.CreateMultipartUploadInput
+	parts     map[string][][]byte
+	aborted   []string
+	failPart  int32
+	lastRange string
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{
+		mu:        sync.Mutex{},
+		objects:   map[string][]byte{},
+		puts:      nil,
+		creates:   nil,
+		parts:     map[string][][]byte{},
+		aborted:   nil,
+		failPart:  0,
+		lastRange: "",
+	}
+}
+
+func (f *fakeS3) PutObject(_ context.Context, in *s3.PutObjectInput, _ ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, _ := io.ReadAll(in.Body)
+	f.objects[aws.ToString(in.Key)] = data
+	f.puts = append(f.puts, in)
+
+	return &s3.PutObjectOutput{
+		BucketKeyEnabled:        nil,
+		ChecksumCRC32:           nil,
+		ChecksumCRC32C:          nil,
+		ChecksumCRC64NVME:       nil,
+		ChecksumMD5:             nil,
+		ChecksumSHA1:            nil,
+		ChecksumSHA256:          nil,
+		ChecksumSHA512:          nil,
+		ChecksumType:            "",
+		ChecksumXXHASH128:       nil,
+		ChecksumXXHASH3:         nil,
+		ChecksumXXHASH64:        nil,
+		ETag:                    aws.String(`"single"`),
+		Expiration:              nil,
+		RequestCharged:          "",
+		SSECustomerAlgorithm:    nil,
+		SSECustomerKeyMD5:       nil,
+		SSEKMSEncryptionContext: nil,
+		SSEKMSKeyId:             nil,
+		ServerSideEncryption:    "",
+		Size:                    nil,
+		VersionId:               nil,
+		ResultMetadata:          middleware.Metadata{},
+	}, nil
+}
+
+func (f *fakeS3) GetObject(_ context.Context, in *s3.GetObjectInput, _ ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, ok := f.objects[aws.ToString(in.Key)]
+	if !ok {
+		return nil, &types.NoSuchKey{
+			Message:           nil,
+			ErrorCodeOverride: nil,
+		}
+	}
+
+	f.lastRange = aws.ToString(in.Range)
+
+	return &s3.GetObjectOutput{
+		AcceptRanges:              nil,
+		Body:                      io.NopCloser(bytes.NewReader(data)),
+		BucketKeyEnabled:          nil,
+		CacheControl:              nil,
+		ChecksumCRC32:             nil,
+		ChecksumCRC32C:            nil,
+		ChecksumCRC64NVME:         nil,
+		ChecksumMD5:               nil,
+		ChecksumSHA1:              nil,
+		ChecksumSHA256:            nil,
+		ChecksumSHA512:            nil,
+		ChecksumType:              "",
+		ChecksumXXHASH128:         nil,
+		ChecksumXXHASH3:           nil,
+		ChecksumXXHASH64:          nil,
+		ContentDisposition:        nil,
+		ContentEncoding:           nil,
+		ContentLanguage:           nil,
+		ContentLength:             aws.Int64(int64(len(data))),
+		ContentRange:              nil,
+		ContentType:               nil,
+		DeleteMarker:              nil,
+		ETag:                      nil,
+		Expiration:                nil,
+		Expires:                   nil,
+		ExpiresString:             nil,
+		LastModified:              nil,
+		Metadata:                  nil,
+		MissingMeta:               nil,
+		ObjectLockLegalHoldStatus: "",
+		ObjectLockMode:            "",
+		ObjectLockRetainUntilDate: nil,
+		PartsCount:                nil,
+		ReplicationStatus:         "",
+		RequestCharged:            "",
+		Restore:                   nil,
+		SSECustomerAlgorithm:      nil,
+		SSECustomerKeyMD5:         nil,
+		SSEKMSKeyId:               nil,
+		ServerSideEncryption:      "",
+		StorageClass:              "",
+		TagCount:                  nil,
+		VersionId:                 nil,
+		WebsiteRedirectLocation:   nil,
+		ResultMetadata:            middleware.Metadata{},
+	}, nil
+}
+
+func (f *fakeS3) HeadObject(_ context.Context, in *s3.HeadObjectInput, _ ...func(*s3.Options)) (*s3.HeadObjectOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	data, ok := f.objects[aws.ToString(in.Key)]
+	if !ok {
+		return nil, &types.NotFound{
+			Message:           nil,
+			ErrorCodeOverride: nil,
+		}
+	}
+
+	return &s3.HeadObjectOutput{
+		AcceptRanges:              nil,
+		ArchiveStatus:             "",
+		BucketKeyEnabled:          nil,
+		CacheControl:              nil,
+		ChecksumCRC32:             nil,
+		ChecksumCRC32C:            nil,
+		ChecksumCRC64NVME:         nil,
+		ChecksumMD5:               nil,
+		ChecksumSHA1:              nil,
+		ChecksumSHA256:            nil,
+		ChecksumSHA512:            nil,
+		ChecksumType:              "",
+		ChecksumXXHASH128:         nil,
+		ChecksumXXHASH3:           nil,
+		ChecksumXXHASH64:          nil,
+		ContentDisposition:        nil,
+		ContentEncoding:           nil,
+		ContentLanguage:           nil,
+		ContentLength:             aws.Int64(int64(len(data))),
+		ContentRange:              nil,
+		ContentType:               nil,
+		DeleteMarker:              nil,
+		ETag:                      nil,
+		Expiration:                nil,
+		Expires:                   nil,
+		ExpiresString:             nil,
+		LastModified:              nil,
+		Metadata:                  nil,
+		MissingMeta:               nil,
+		ObjectLockLegalHoldStatus: "",
+		ObjectLockMode:            "",
+		ObjectLockRetainUntilDate: nil,
+		PartsCount:                nil,
+		ReplicationStatus:         "",
+		RequestCharged:            "",
+		Restore:                   nil,
+		SSECustomerAlgorithm:      nil,
+		SSECustomerKeyMD5:         nil,
+		SSEKMSKeyId:               nil,
+		ServerSideEncryption:      "",
+		StorageClass:              "",
+		TagCount:                  nil,
+		VersionId:                 nil,
+		WebsiteRedirectLocation:   nil,
+		ResultMetadata:            middleware.Metadata{},
+	}, nil
+}
+
+func (f *fakeS3) DeleteObject(
+	_ context.Context, in *s3.DeleteObjectInput, _ ...func(*s3.Options),
+) (*s3.DeleteObjectOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.objects, aws.ToString(in.Key))
+
+	return &s3.DeleteObjectOutput{
+		DeleteMarker:   nil,
+		RequestCharged: "",
+		VersionId:      nil,
+		ResultMetadata: middleware.Metadata{},
+	}, nil
+}
+
+func (f *fakeS3) CreateMultipartUpload(
+	_ context.Context, in *s3.CreateMultipartUploadInput, _ ...func(*s3.Options),
+) (*s3.CreateMultipartUploadOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.creates = append(f.creates, in)
+
+	return &s3.CreateMultipartUploadOutput{
+		AbortDate:               nil,
+		AbortRuleId:             nil,
+		Bucket:                  nil,
+		BucketKeyEnabled:        nil,
+		ChecksumAlgorithm:       "",
+		ChecksumType:            "",
+		Key:                     nil,
+		RequestCharged:          "",
+		SSECustomerAlgorithm:    nil,
+		SSECustomerKeyMD5:       nil,
+		SSEKMSEncryptionContext: nil,
+		SSEKMSKeyId:             nil,
+		ServerSideEncryption:    "",
+		UploadId:                aws.String("upload-" + strconv.Itoa(len(f.creates))),
+		ResultMetadata:          middleware.Metadata{},
+	}, nil
+}
+
+func (f *fakeS3) UploadPart(_ context.Context, in *s3.UploadPartInput, _ ...func(*s3.Options)) (*s3.UploadPartOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if aws.ToInt32(in.PartNumber) == f.failPart {
+		return nil, errPartFailed
+	}
+
+	data, _ := io.ReadAll(in.Body)
+	id := aws.ToString(in.UploadId)
+	f.parts[id] = append(f.parts[id], data)
+
+	return &s3.UploadPartOutput{
+		BucketKeyEnabled:     nil,
+		ChecksumCRC32:        nil,
+		ChecksumCRC32C:       nil,
+		ChecksumCRC64NVME:    nil,
+		ChecksumMD5:          nil,
+		ChecksumSHA1:         nil,
+		ChecksumSHA256:       nil,
+		ChecksumSHA512:       nil,
+		ChecksumXXHASH128:    nil,
+		ChecksumXXHASH3:      nil,
+		ChecksumXXHASH64:     nil,
+		ETag:                 aws.String(strconv.Itoa(int(aws.ToInt32(in.PartNumber)))),
+		RequestCharged:       "",
+		SSECustomerAlgorithm: nil,
+		SSECustomerKeyMD5:    nil,
+		SSEKMSKeyId:          nil,
+		ServerSideEncryption: "",
+		ResultMetadata:       middleware.Metadata{},
+	}, nil
+}
+
+func (f *fakeS3) CompleteMultipartUpload(
+	_ context.Context, in *s3.CompleteMultipartUploadInput, _ ...func(*s3.Options),
+) (*s3.CompleteMultipartUploadOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.objects[aws.ToString(in.Key)] = bytes.Join(f.parts[aws.ToString(in.UploadId)], nil)
+
+	return &s3.CompleteMultipartUploadOutput{
+		Bucket:               nil,
+		BucketKeyEnabled:     nil,
+		ChecksumCRC32:        nil,
+		ChecksumCRC32C:       nil,
+		ChecksumCRC64NVME:    nil,
+		ChecksumMD5:          nil,
+		ChecksumSHA1:         nil,
+		ChecksumSHA256:       nil,
+		ChecksumSHA512:       nil,
+		ChecksumType:         "",
+		ChecksumXXHASH128:    nil,
+		ChecksumXXHASH3:      nil,
+		ChecksumXXHASH64:     nil,
+		ETag:                 aws.String(`"multi-` + strconv.Itoa(len(in.MultipartUpload.Parts)) + `"`),
+		Expiration:           nil,
+		Key:                  nil,
+		Location:             nil,
+		RequestCharged:       "",
+		SSEKMSKeyId:          nil,
+		ServerSideEncryption: "",
+		VersionId:            nil,
+		ResultMetadata:       middleware.Metadata{},
+	}, nil
+}
+
+func (f *fakeS3) AbortMultipartUpload(
+	_ context.Context, in *s3.AbortMultipartUploadInput, _ ...func(*s3.Options),
+) (*s3.AbortMultipartUploadOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.aborted = append(f.aborted, aws.ToString(in.UploadId))
+
+	return &s3.AbortMultipartUploadOutput{
+		RequestCharged: "",
+		ResultMetadata: middleware.Metadata{},
+	}, nil
+}
+
+// testS3Config returns a valid config for a store backed by fakeS3.
+func testS3Config() blobstore.S3Config {
+	return blobstore.S3Config{
+		Region:       "us-east-1",
+		Endpoint:     "",
+		Bucket:       "uploads",
+		KeyPrefix:    "",
+		AccessKey:    "key",
+		SecretKey:    "secret",
+		UsePathStyle: false,
+		PartSize:     partSize,
+		Encryption: blobstore.Encryption{
+			Algorithm: "",
+			KMSKeyID:  "",
+		},
+	}
+}
+
+func newS3Store(t *testing.T, fake *fakeS3, cfg blobstore.S3Config) *blobstore.S3Store {
+	t.Helper()
+
+	store, err := blobstore.NewS3(t.Context(), cfg, blobstore.WithS3Client(fake))
+	require.NoError(t, err)
+
+	return store
+}
+
+func TestS3Store_PutSmallObjectWithEncryption(t *testing.T) {
+	t.Parallel()
+
+	cfg := testS3Config()
+	cfg.KeyPrefix = "tenant-a/"
+	cfg.Encryption = blobstore.Encryption{Algorithm: blobstore.EncryptionKMS, KMSKeyID: "key-1"}
+
+	fake := newFakeS3()
+	store := newS3Store(t, fake, cfg)
+
+	obj, err := store.Put(t.Context(), "avatars/42.png", strings.NewReader("png"), blobstore.WithContentType("image/png"))
+	require.NoError(t, err)
+	require.Equal(t, "avatars/42.png", obj.Key)
+	require.Equal(t, int64(3), obj.Size)
+	require.Equal(t, `"single"`, obj.ETag)
+
+	require.Len(t, fake.puts, 1)
+	require.Equal(t, "tenant-a/avatars/42.png", aws.ToString(fake.puts[0].Key))
+	require.Equal(t, "image/png", aws.ToString(fake.puts[0].ContentType))
+	require.Equal(t, types.ServerSideEncryptionAwsKms, fake.puts[0].ServerSideEncryption)
+	require.Equal(t, "key-1", aws.ToString(fake.puts[0].SSEKMSKeyId))
+
+	_, err = store.Put(t.Context(), "plain.txt", strings.NewReader("x"),
+		blobstore.WithEncryption(blobstore.Encryption{
+			Algorithm: blobstore.EncryptionAES256,
+			KMSKeyID:  "",
+		}))
+	require.NoError(t, err)
+	require.Equal(t, types.ServerSideEncryptionAes256, fake.puts[1].ServerSideEncryption)
+	require.Nil(t, fake.puts[1].SSEKMSKeyId)
+}
+
+func TestS3Store_PutLargeObjectUsesMultipart(t *testing.T) {
+	t.Parallel()
+
+	fake := newFakeS3()
+	store := newS3Store(t, fake, testS3Config())
+
+	data := bytes.Repeat([]byte("0123456789"), partSize/10*2+7)
+
+	obj, err := store.Put(t.Context(), "exports/orders.csv", bytes.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, int64(len(data)), obj.Size)
+	require.Equal(t, `"multi-3"`, obj.ETag)
+
+	require.Empty(t, fake.puts)
+	require.Len(t, fake.creates, 1)
+	require.Len(t, fake.parts["upload-1"], 3)
+	require.Len(t, fake.parts["upload-1"][0], partSize)
+	require.Len(t, fake.parts["upload-1"][2], 70)
+	require.Equal(t, data, fake.objects["exports/orders.csv"])
+}
+
+func TestS3Store_FailedMultipartUploadIsAborted(t *testing.T) {
+	t.Parallel()
+
+	fake := newFakeS3()
+	fake.failPart = 2
+	store := newS3Store(t, fake, testS3Config())
+
+	_, err := store.Put(t.Context(), "exports/orders.csv", bytes.NewReader(make([]byte, partSize*2)))
+	require.ErrorIs(t, err, errPartFailed)
+	require.Equal(t, []string{"upload-1"}, fake.aborted)
+}
+
+func TestS3Store_GetStatNotFound(t *testing.T) {
+	t.Parallel()
+
+	fake := newFakeS3()
+	store := newS3Store(t, fake, testS3Config())
+
+	_, _, err := store.Get(t.Context(), "missing")
+	require.ErrorIs(t, err, blobstore.ErrNotFound)
+
+	_, err = store.Stat(t.Context(), "missing")
+	require.ErrorIs(t, err, blobstore.ErrNotFound)
+
+	fake.objects["report.csv"] = []byte("a,b")
+
+	body, _, err := store.Get(t.Context(), "report.csv", blobstore.WithRange(10, 5))
+	require.NoError(t, err)
+	require.NoError(t, body.Close())
+	require.Equal(t, "bytes=10-14", fake.lastRange)
+
+	body, _, err = store.Get(t.Context(), "report.csv", blobstore.WithRange(10, 0))
+	require.NoError(t, err)
+	require.NoError(t, body.Close())
+	require.Equal(t, "bytes=10-", fake.lastRange)
+}
+
+func TestS3Store_Presign(t *testing.T) {
+	t.Parallel()
+
+	store := newS3Store(t, newFakeS3(), testS3Config())
+
+	url, err := store.PresignGet(t.Context(), "avatars/42.png", 15*time.Minute)
+	require.NoError(t, err)
+	require.Contains(t, url, "uploads")
+	require.Contains(t, url, "avatars/42.png")
+	require.Contains(t, url, "X-Amz-Expires=900")
+
+	url, err = store.PresignPut(t.Context(), "avatars/42.png", time.Minute, blobstore.WithContentType("image/png"))
+	require.NoError(t, err)
+	require.Contains(t, url, "X-Amz-Signature=")
+}
+
+func TestNewS3_Validation(t *testing.T) {
+	t.Parallel()
+
+	cfg := testS3Config()
+	cfg.Bucket = ""
+
+	_, err := blobstore.NewS3(t.Context(), cfg)
+	require.ErrorIs(t, err, blobstore.ErrConfig)
+
+	cfg = testS3Config()
+	cfg.PartSize = 1 << 20
+
+	_, err = blobstore.NewS3(t.Context(), cfg)
+	require.ErrorIs(t, err, blobstore.ErrConfig)
+}
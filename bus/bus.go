@@ -0,0 +1,355 @@
+// Package bus is a typed publish/subscribe bus for domain events within one process, so modules of a
+// service can react to each other's events without a Redis round trip or a direct dependency.
+//
+// Basic usage:
+//
+//	events := bus.New()
+//	defer events.Close(ctx)
+//
+//	bus.Subscribe(events, func(ctx context.Context, e OrderPlaced) error {
+//	    return reserveStock(ctx, e.OrderID)
+//	})
+//	bus.Subscribe(events, sendConfirmation, bus.WithAsync())
+//
+//	err := bus.Publish(ctx, events, OrderPlaced{OrderID: id})
+//
+// Handlers are matched on the exact type of the event. Synchronous handlers run in the publishing
+// goroutine, in subscription order, and their errors are returned by Publish. Asynchronous handlers run
+// on their own workers after Publish returns; their errors are passed to the error handler of the bus.
+// A panicking handler is recovered and reported as an error without affecting other handlers.
+//
+// Forward publishes selected events to a redispub topic for other services.
+package bus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultWorkers    = 1
+	defaultBufferSize = 256
+)
+
+var (
+	ErrBusClosed    = errors.New("bus: bus is closed")
+	ErrNilBus       = errors.New("bus: bus is nil")
+	ErrNilHandler   = errors.New("bus: handler is nil")
+	ErrHandlerPanic = errors.New("bus: handler panicked")
+)
+
+// Handler handles events of type T.
+type Handler[T any] func(ctx context.Context, event T) error
+
+// ErrorHandler receives the errors of asynchronous handlers.
+type ErrorHandler func(ctx context.Context, eventType string, err error)
+
+type Option func(*Bus)
+
+// WithErrorHandler replaces the default error handler of asynchronous handlers, which logs the error.
+func WithErrorHandler(handler ErrorHandler) Option {
+	return func(b *Bus) {
+		if handler != nil {
+			b.onError = handler
+		}
+	}
+}
+
+type subscribeConfig struct {
+	async      bool
+	workers    int
+	bufferSize int
+}
+
+type SubscribeOption func(*subscribeConfig)
+
+// WithAsync runs the handler on its own workers instead of in the publishing goroutine. Publish only
+// blocks when the buffer of the handler is full.
+func WithAsync() SubscribeOption {
+	return func(c *subscribeConfig) {
+		c.async = true
+	}
+}
+
+// WithWorkers sets the number of goroutines running an asynchronous handler. It defaults to one,
+// which handles events in publish order.
+func WithWorkers(workers int) SubscribeOption {
+	return func(c *subscribeConfig) {
+		if workers > 0 {
+			c.workers = workers
+		}
+	}
+}
+
+// WithBufferSize sets the number of events an asynchronous handler can fall behind before Publish
+// blocks. It defaults to 256.
+func WithBufferSize(size int) SubscribeOption {
+	return func(c *subscribeConfig) {
+		if size >= 0 {
+			c.bufferSize = size
+		}
+	}
+}
+
+type envelope struct {
+	ctx   context.Context //nolint:containedctx
+	event any
+}
+
+type subscription struct {
+	bus       *Bus
+	eventType reflect.Type
+	handle    func(ctx context.Context, event any) error
+
+	// Async subscriptions only.
+	mu      sync.RWMutex
+	queue   chan envelope
+	closed  bool
+	workers sync.WaitGroup
+}
+
+// Subscription is a registered handler.
+type Subscription struct {
+	sub *subscription
+}
+
+// Unsubscribe removes the handler. An asynchronous handler first handles the events already queued.
+func (s *Subscription) Unsubscribe() {
+	s.sub.bus.remove(s.sub)
+	s.sub.close()
+}
+
+// Bus routes events to the handlers subscribed to their type.
+type Bus struct {
+	onError ErrorHandler
+
+	mu     sync.RWMutex
+	subs   map[reflect.Type][]*subscription
+	closed bool
+}
+
+func New(opts ...Option) *Bus {
+	b := &Bus{
+		onError: logError,
+		mu:      sync.RWMutex{},
+		subs:    make(map[reflect.Type][]*subscription),
+		closed:  false,
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b
+}
+
+// Subscribe registers handler for events of type T.
+func Subscribe[T any](b *Bus, handler Handler[T], opts ...SubscribeOption) (*Subscription, error) {
+	if b == nil {
+		return nil, ErrNilBus
+	}
+
+	if handler == nil {
+		return nil, ErrNilHandler
+	}
+
+	cfg := subscribeConfig{async: false, workers: defaultWorkers, bufferSize: defaultBufferSize}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	sub := &subscription{ //nolint:exhaustruct
+		bus:       b,
+		eventType: reflect.TypeFor[T](),
+		handle: func(ctx context.Context, event any) error {
+			return handler(ctx, event.(T)) //nolint:forcetypeassert
+		},
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil, ErrBusClosed
+	}
+
+	if cfg.async {
+		sub.queue = make(chan envelope, cfg.bufferSize)
+
+		for range cfg.workers {
+			sub.workers.Add(1)
+
+			go sub.work()
+		}
+	}
+
+	b.subs[sub.eventType] = append(b.subs[sub.eventType], sub)
+
+	return &Subscription{sub: sub}, nil
+}
+
+// Publish delivers event to the handlers subscribed to type T. It returns the errors of synchronous
+// handlers, joined, after all of them have run.
+func Publish[T any](ctx context.Context, b *Bus, event T) error {
+	if b == nil {
+		return ErrNilBus
+	}
+
+	eventType := reflect.TypeFor[T]()
+
+	b.mu.RLock()
+
+	if b.closed {
+		b.mu.RUnlock()
+
+		return ErrBusClosed
+	}
+
+	subs := append([]*subscription(nil), b.subs[eventType]...)
+	b.mu.RUnlock()
+
+	var errs []error
+
+	for _, sub := range subs {
+		if sub.queue != nil {
+			if err := sub.enqueue(ctx, event); err != nil {
+				errs = append(errs, err)
+			}
+
+			continue
+		}
+
+		if err := sub.call(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Close stops accepting events and waits until the asynchronous handlers have handled the events
+// already queued, or until ctx is done.
+func (b *Bus) Close(ctx context.Context) error {
+	b.mu.Lock()
+
+	if b.closed {
+		b.mu.Unlock()
+
+		return nil
+	}
+
+	b.closed = true
+
+	var subs []*subscription
+	for _, typed := range b.subs {
+		subs = append(subs, typed...)
+	}
+
+	b.subs = make(map[reflect.Type][]*subscription)
+	b.mu.Unlock()
+
+	done := make(chan struct{})
+
+	go func() {
+		for _, sub := range subs {
+			sub.close()
+		}
+
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("bus: close: %w", ctx.Err())
+	}
+}
+
+func (b *Bus) remove(sub *subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subs := b.subs[sub.eventType]
+
+	for i, candidate := range subs {
+		if candidate == sub {
+			b.subs[sub.eventType] = append(subs[:i:i], subs[i+1:]...)
+
+			break
+		}
+	}
+}
+
+func (s *subscription) enqueue(ctx context.Context, event any) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return nil
+	}
+
+	// The handler outlives the publish call, so it keeps the values of ctx, such as the trace, but not
+	// its cancellation.
+	select {
+	case s.queue <- envelope{ctx: context.WithoutCancel(ctx), event: event}:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("bus: failed to queue %s: %w", s.eventType, ctx.Err())
+	}
+}
+
+func (s *subscription) work() {
+	defer s.workers.Done()
+
+	for env := range s.queue {
+		if err := s.call(env.ctx, env.event); err != nil {
+			s.bus.onError(env.ctx, s.eventType.String(), err)
+		}
+	}
+}
+
+func (s *subscription) close() {
+	if s.queue == nil {
+		return
+	}
+
+	s.mu.Lock()
+
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+
+	s.mu.Unlock()
+
+	s.workers.Wait()
+}
+
+func (s *subscription) call(ctx context.Context, event any) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			log.Error().
+				Str("source", "gframework").
+				Str("event_type", s.eventType.String()).
+				Interface("panic", rec).
+				Bytes("stack", debug.Stack()).
+				Msg("Event handler panicked")
+
+			err = fmt.Errorf("%w: %s: %v", ErrHandlerPanic, s.eventType, rec)
+		}
+	}()
+
+	return s.handle(ctx, event)
+}
+
+func logError(_ context.Context, eventType string, err error) {
+	log.Error().Str("source", "gframework").Err(err).Str("event_type", eventType).Msg("Async event handler failed")
+}
@@ -0,0 +1,233 @@
+//nolint:exhaustruct
+package bus_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/bus"
+	"github.com/stretchr/testify/require"
+)
+
+var errHandler = errors.New("handler failed")
+
+type orderPlaced struct {
+	OrderID  string `json:"orderId"`
+	Internal bool   `json:"internal"`
+}
+
+type orderCanceled struct {
+	OrderID string
+}
+
+func TestPublish_Sync(t *testing.T) {
+	t.Parallel()
+
+	events := bus.New()
+
+	var calls []string
+
+	_, err := bus.Subscribe(events, func(_ context.Context, e orderPlaced) error {
+		calls = append(calls, "first:"+e.OrderID)
+
+		return errHandler
+	})
+	require.NoError(t, err)
+
+	_, err = bus.Subscribe(events, func(_ context.Context, e orderPlaced) error {
+		calls = append(calls, "second:"+e.OrderID)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	_, err = bus.Subscribe(events, func(_ context.Context, _ orderCanceled) error {
+		calls = append(calls, "canceled")
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = bus.Publish(t.Context(), events, orderPlaced{OrderID: "o-1"})
+	require.ErrorIs(t, err, errHandler)
+	require.Equal(t, []string{"first:o-1", "second:o-1"}, calls, "a failing handler does not stop the others")
+
+	require.NoError(t, bus.Publish(t.Context(), events, "no subscribers"))
+}
+
+func TestPublish_IsolatesPanics(t *testing.T) {
+	t.Parallel()
+
+	events := bus.New()
+
+	var called atomic.Bool
+
+	_, err := bus.Subscribe(events, func(context.Context, orderPlaced) error { panic("boom") })
+	require.NoError(t, err)
+
+	_, err = bus.Subscribe(events, func(context.Context, orderPlaced) error {
+		called.Store(true)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	err = bus.Publish(t.Context(), events, orderPlaced{OrderID: "o-1"})
+	require.ErrorIs(t, err, bus.ErrHandlerPanic)
+	require.ErrorContains(t, err, "boom")
+	require.True(t, called.Load())
+}
+
+type ctxKey struct{}
+
+func TestPublish_Async(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		received []string
+		failures []error
+	)
+
+	events := bus.New(bus.WithErrorHandler(func(_ context.Context, eventType string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		require.Equal(t, "bus_test.orderPlaced", eventType)
+		failures = append(failures, err)
+	}))
+
+	release := make(chan struct{})
+
+	_, err := bus.Subscribe(events, func(ctx context.Context, e orderPlaced) error {
+		<-release
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		received = append(received, e.OrderID+":"+ctx.Value(ctxKey{}).(string)) //nolint:forcetypeassert
+
+		if e.OrderID == "o-2" {
+			panic("boom")
+		}
+
+		return nil
+	}, bus.WithAsync())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.WithValue(t.Context(), ctxKey{}, "trace"))
+
+	for _, id := range []string{"o-1", "o-2", "o-3"} {
+		require.NoError(t, bus.Publish(ctx, events, orderPlaced{OrderID: id}), "publish does not wait for async handlers")
+	}
+
+	cancel()
+	close(release)
+
+	require.NoError(t, events.Close(t.Context()))
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	require.Equal(t, []string{"o-1:trace", "o-2:trace", "o-3:trace"}, received, "one worker keeps the publish order")
+	require.Len(t, failures, 1)
+	require.ErrorIs(t, failures[0], bus.ErrHandlerPanic)
+
+	require.ErrorIs(t, bus.Publish(t.Context(), events, orderPlaced{}), bus.ErrBusClosed)
+}
+
+func TestPublish_AsyncBufferFull(t *testing.T) {
+	t.Parallel()
+
+	events := bus.New()
+	release := make(chan struct{})
+
+	_, err := bus.Subscribe(events, func(context.Context, orderPlaced) error {
+		<-release
+
+		return nil
+	}, bus.WithAsync(), bus.WithBufferSize(0))
+	require.NoError(t, err)
+
+	require.NoError(t, bus.Publish(t.Context(), events, orderPlaced{}), "the worker takes the first event")
+
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, bus.Publish(ctx, events, orderPlaced{}), context.DeadlineExceeded)
+
+	close(release)
+	require.NoError(t, events.Close(t.Context()))
+}
+
+func TestSubscription_Unsubscribe(t *testing.T) {
+	t.Parallel()
+
+	events := bus.New()
+
+	var calls atomic.Int32
+
+	sub, err := bus.Subscribe(events, func(context.Context, orderPlaced) error {
+		calls.Add(1)
+
+		return nil
+	}, bus.WithAsync(), bus.WithWorkers(4))
+	require.NoError(t, err)
+
+	for range 10 {
+		require.NoError(t, bus.Publish(t.Context(), events, orderPlaced{}))
+	}
+
+	sub.Unsubscribe()
+	require.Equal(t, int32(10), calls.Load(), "queued events are handled before unsubscribing")
+
+	require.NoError(t, bus.Publish(t.Context(), events, orderPlaced{}))
+	require.Equal(t, int32(10), calls.Load())
+
+	_, err = bus.Subscribe[orderPlaced](events, nil)
+	require.ErrorIs(t, err, bus.ErrNilHandler)
+}
+
+type recordingPublisher struct {
+	mu       sync.Mutex
+	messages map[string][]string
+}
+
+func (p *recordingPublisher) PublishToTopic(_ context.Context, topic string, messageContents ...string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.messages[topic] = append(p.messages[topic], messageContents...)
+
+	return nil
+}
+
+func (p *recordingPublisher) Close() error { return nil }
+
+func TestForward(t *testing.T) {
+	t.Parallel()
+
+	events := bus.New()
+	publisher := &recordingPublisher{messages: map[string][]string{}}
+
+	_, err := bus.Forward(events, publisher, "orders.placed", func(e orderPlaced) bool { return !e.Internal })
+	require.NoError(t, err)
+
+	require.NoError(t, bus.Publish(t.Context(), events, orderPlaced{OrderID: "o-1"}))
+	require.NoError(t, bus.Publish(t.Context(), events, orderPlaced{OrderID: "o-2", Internal: true}))
+	require.NoError(t, events.Close(t.Context()))
+
+	require.Len(t, publisher.messages["orders.placed"], 1)
+
+	var forwarded orderPlaced
+	require.NoError(t, json.Unmarshal([]byte(publisher.messages["orders.placed"][0]), &forwarded))
+	require.Equal(t, "o-1", forwarded.OrderID)
+
+	_, err = bus.Forward[orderPlaced](events, nil, "orders.placed", nil)
+	require.ErrorIs(t, err, bus.ErrNilPublisher)
+}
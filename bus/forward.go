@@ -0,0 +1,44 @@
+package bus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/andyle182810/gframework/redispub"
+)
+
+var ErrNilPublisher = errors.New("bus: publisher is nil")
+
+// Forward publishes events of type T to topic as JSON, for consumers in other services. Only events
+// for which filter returns true are forwarded; a nil filter forwards every event. Events are forwarded
+// by an asynchronous handler, so publishers do not wait for Redis; opts tune its workers and buffer:
+//
+//	bus.Forward(events, publisher, "orders.placed", func(e OrderPlaced) bool { return !e.Internal })
+func Forward[T any](
+	b *Bus,
+	publisher redispub.Publisher,
+	topic string,
+	filter func(T) bool,
+	opts ...SubscribeOption,
+) (*Subscription, error) {
+	if publisher == nil {
+		return nil, ErrNilPublisher
+	}
+
+	handler := func(ctx context.Context, event T) error {
+		if filter != nil && !filter(event) {
+			return nil
+		}
+
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("bus: failed to encode %T for topic %s: %w", event, topic, err)
+		}
+
+		return publisher.PublishToTopic(ctx, topic, string(payload))
+	}
+
+	return Subscribe(b, handler, append([]SubscribeOption{WithAsync()}, opts...)...)
+}
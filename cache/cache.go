@@ -1,7 +1,7 @@
 // Package cache provides a generic, type-safe caching layer using Redis.
 //
 // This package uses generics to provide compile-time type safety for cached values.
-// Cache entries are stored as JSON in Redis with TTL support. Keys can be encoded using
+// Cache entries are stored in Redis hashes with TTL support. Keys can be encoded using
 // custom KeyEncoder implementations for different types (strings, integers, UUIDs).
 //
 // Basic usage:
@@ -39,18 +39,40 @@
 //	    MyCustomKeyEncoder{},
 //	)
 //
-// The cache layer automatically handles JSON marshaling/unmarshaling and provides
-// operations for Set, Get, Delete, and Invalidate (clear all cached values for a hash).
+// Values are encoded as JSON unless another ValueCodec is selected with WithValueCodec, e.g.
+// NewGzipCodec(NewMsgpackCodec()) for large aggregates. The cache layer provides
+// operations for Set, Get, Delete, and Invalidate (clear all cached values for a hash). Keys and Scan
+// iterate over the entries, e.g. for admin UIs, and Touch and TTL manage expiry, e.g. for warmers.
+//
+// Counter provides atomic increments for quota counters and view counts, with a TTL per key:
+//
+//	quota := cache.NewCounter[string](redisClient, "quota", time.Hour, cache.NewStringKeyEncoder())
+//	used, err := quota.Increment(ctx, tenantID, 1)
+//
+// WithMetrics reports hits, misses, sets, deletes and latencies per hash key, e.g. to Prometheus via
+// NewPrometheusMetrics.
+//
+// GetOrLoad reads through the cache, calling the loader once per key however many callers miss
+// concurrently. WithLoadLock extends that to all replicas, and WithNegativeTTL caches not-found results:
+//
+//	users := cache.New[string, User](redisClient, "users", time.Hour, cache.NewStringKeyEncoder(),
+//	    cache.WithNegativeTTL(time.Minute),
+//	)
+//	user, err := users.GetOrLoad(ctx, id, func(ctx context.Context) (*User, error) {
+//	    return repo.FindUser(ctx, id) // return cache.ErrKeyNotFound when the user does not exist
+//	})
 package cache
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
 
+	"github.com/andyle182810/gframework/clock"
+	"github.com/andyle182810/gframework/distlock"
 	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
 )
 
 const (
@@ -73,10 +95,39 @@ var (
 )
 
 type Cache[K any, V any] struct {
-	client     redis.UniversalClient
-	ttl        time.Duration
-	hashKey    string
-	keyEncoder KeyEncoder
+	client      redis.UniversalClient
+	ttl         time.Duration
+	hashKey     string
+	keyEncoder  KeyEncoder
+	codec       ValueCodec
+	negTTL      time.Duration
+	lockTTL     time.Duration
+	loadTimeout time.Duration
+	locker      *distlock.Locker
+	group       singleflight.Group
+	metrics     Metrics
+	clock       clock.Clock
+}
+
+type Option func(*options)
+
+type options struct {
+	codec       ValueCodec
+	negTTL      time.Duration
+	lockTTL     time.Duration
+	loadTimeout time.Duration
+	metrics     Metrics
+	clock       clock.Clock
+}
+
+// WithClock sets the clock used to time operations and to bound waits for the load lock. Entry TTLs
+// are enforced by the server and do not follow it. It defaults to clock.Real.
+func WithClock(clk clock.Clock) Option {
+	return func(o *options) {
+		if clk != nil {
+			o.clock = clk
+		}
+	}
 }
 
 func New[K any, V any](
@@ -84,26 +135,57 @@ func New[K any, V any](
 	hashKey string,
 	ttl time.Duration,
 	keyEncoder KeyEncoder,
+	opts ...Option,
 ) *Cache[K, V] {
 	if ttl == 0 {
 		ttl = DefaultTTL5m
 	}
 
+	cfg := options{
+		codec:       NewJSONCodec(),
+		negTTL:      0,
+		lockTTL:     0,
+		loadTimeout: defaultLoadTimeout,
+		metrics:     nil,
+		clock:       clock.Real(),
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var locker *distlock.Locker
+	if cfg.lockTTL > 0 {
+		locker = distlock.New(client)
+	}
+
 	return &Cache[K, V]{
-		client:     client,
-		ttl:        ttl,
-		hashKey:    hashKey,
-		keyEncoder: keyEncoder,
+		client:      client,
+		ttl:         ttl,
+		hashKey:     hashKey,
+		keyEncoder:  keyEncoder,
+		codec:       cfg.codec,
+		negTTL:      cfg.negTTL,
+		lockTTL:     cfg.lockTTL,
+		loadTimeout: cfg.loadTimeout,
+		locker:      locker,
+		group:       singleflight.Group{},
+		metrics:     cfg.metrics,
+		clock:       cfg.clock,
 	}
 }
 
-func (c *Cache[K, V]) Get(ctx context.Context, key K) (*V, error) {
+func (c *Cache[K, V]) Get(ctx context.Context, key K) (_ *V, err error) {
 	encodedKey, err := c.keyEncoder.Encode(key)
 	if err != nil {
 		return nil, err
 	}
 
-	data, err := c.client.HGet(ctx, c.hashKey, encodedKey).Result()
+	if c.metrics != nil {
+		defer c.observeGet(c.clock.Now(), &err)
+	}
+
+	data, err := c.client.HGet(ctx, c.hashKey, encodedKey).Bytes()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
 			return nil, ErrKeyNotFound
@@ -113,7 +195,7 @@ func (c *Cache[K, V]) Get(ctx context.Context, key K) (*V, error) {
 	}
 
 	var value V
-	if err := json.Unmarshal([]byte(data), &value); err != nil {
+	if err := c.codec.Unmarshal(data, &value); err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrCacheUnmarshal, err)
 	}
 
@@ -126,7 +208,15 @@ func (c *Cache[K, V]) Set(ctx context.Context, key K, value *V) error {
 		return err
 	}
 
-	data, err := json.Marshal(value)
+	return c.store(ctx, encodedKey, value)
+}
+
+func (c *Cache[K, V]) store(ctx context.Context, encodedKey string, value *V) (err error) {
+	if c.metrics != nil {
+		defer c.observeSet(c.clock.Now(), &err)
+	}
+
+	data, err := c.codec.Marshal(value)
 	if err != nil {
 		return fmt.Errorf("%w: %w", ErrCacheMarshal, err)
 	}
@@ -142,16 +232,26 @@ func (c *Cache[K, V]) Set(ctx context.Context, key K, value *V) error {
 	return nil
 }
 
-func (c *Cache[K, V]) Delete(ctx context.Context, key K) error {
+func (c *Cache[K, V]) Delete(ctx context.Context, key K) (err error) {
 	encodedKey, err := c.keyEncoder.Encode(key)
 	if err != nil {
 		return err
 	}
 
+	if c.metrics != nil {
+		defer func() { c.metrics.Deleted(c.hashKey, err) }()
+	}
+
 	if err := c.client.HDel(ctx, c.hashKey, encodedKey).Err(); err != nil {
 		return fmt.Errorf("%w: %w", ErrCacheDelete, err)
 	}
 
+	if c.negTTL > 0 {
+		if err := c.client.Del(ctx, c.missKey(encodedKey)).Err(); err != nil {
+			return fmt.Errorf("%w: %w", ErrCacheDelete, err)
+		}
+	}
+
 	return nil
 }
 
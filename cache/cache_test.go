@@ -7,7 +7,6 @@ import (
 
 	"github.com/andyle182810/gframework/cache"
 	"github.com/andyle182810/gframework/testutil"
-	"github.com/andyle182810/gframework/valkey"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
@@ -15,18 +14,7 @@ import (
 func setupCache[K any, V any](t *testing.T, hashKey string, ttl time.Duration, encoder cache.KeyEncoder) *cache.Cache[K, V] {
 	t.Helper()
 
-	container := testutil.SetupValkeyContainer(t)
-
-	port, err := strconv.Atoi(container.Port.Port())
-	require.NoError(t, err)
-
-	opts := &valkey.Config{ //nolint:exhaustruct
-		Host: container.Host,
-		Port: port,
-	}
-
-	client, err := valkey.New(opts)
-	require.NoError(t, err)
+	client := testutil.SetupValkey(t)
 
 	return cache.New[K, V](client, hashKey, ttl, encoder)
 }
@@ -128,25 +116,14 @@ func TestCache_Invalidate(t *testing.T) {
 func TestCache_DefaultTTL(t *testing.T) {
 	t.Parallel()
 
-	container := testutil.SetupValkeyContainer(t)
-
-	port, err := strconv.Atoi(container.Port.Port())
-	require.NoError(t, err)
-
-	opts := &valkey.Config{ //nolint:exhaustruct
-		Host: container.Host,
-		Port: port,
-	}
-
-	client, err := valkey.New(opts)
-	require.NoError(t, err)
+	client := testutil.SetupValkey(t)
 
 	c := cache.New[string, TestUser](client, "users", 0, cache.NewStringKeyEncoder())
 
 	ctx := t.Context()
 	user := &TestUser{ID: 1, Name: "Test User", Age: 25}
 
-	err = c.Set(ctx, "user:1", user)
+	err := c.Set(ctx, "user:1", user)
 	require.NoError(t, err)
 
 	retrieved, err := c.Get(ctx, "user:1")
@@ -0,0 +1,149 @@
+package cache
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+var ErrCacheInvalidValueType = errors.New("cache: invalid value type")
+
+// ValueCodec encodes cached values. Marshal receives a *V and Unmarshal a *V to decode into.
+type ValueCodec interface {
+	Marshal(value any) ([]byte, error)
+	Unmarshal(data []byte, value any) error
+}
+
+// WithValueCodec selects how values are encoded. It defaults to JSON. Changing the codec of an
+// existing cache makes previously stored values undecodable, so invalidate it or use a new hash key.
+func WithValueCodec(codec ValueCodec) Option {
+	return func(o *options) {
+		if codec != nil {
+			o.codec = codec
+		}
+	}
+}
+
+type JSONCodec struct{}
+
+func NewJSONCodec() *JSONCodec {
+	return &JSONCodec{}
+}
+
+func (c *JSONCodec) Marshal(value any) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (c *JSONCodec) Unmarshal(data []byte, value any) error {
+	return json.Unmarshal(data, value)
+}
+
+type MsgpackCodec struct{}
+
+func NewMsgpackCodec() *MsgpackCodec {
+	return &MsgpackCodec{}
+}
+
+func (c *MsgpackCodec) Marshal(value any) ([]byte, error) {
+	return msgpack.Marshal(value)
+}
+
+func (c *MsgpackCodec) Unmarshal(data []byte, value any) error {
+	return msgpack.Unmarshal(data, value)
+}
+
+// ProtoCodec encodes values with protobuf. V must be a generated message type, so that *V implements
+// proto.Message.
+type ProtoCodec struct{}
+
+func NewProtoCodec() *ProtoCodec {
+	return &ProtoCodec{}
+}
+
+func (c *ProtoCodec) Marshal(value any) ([]byte, error) {
+	message, ok := value.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("%w (expected proto.Message, got %T)", ErrCacheInvalidValueType, value)
+	}
+
+	return proto.Marshal(message)
+}
+
+func (c *ProtoCodec) Unmarshal(data []byte, value any) error {
+	message, ok := value.(proto.Message)
+	if !ok {
+		return fmt.Errorf("%w (expected proto.Message, got %T)", ErrCacheInvalidValueType, value)
+	}
+
+	return proto.Unmarshal(data, message)
+}
+
+// GzipCodec compresses the output of another codec, trading CPU for memory on large values.
+type GzipCodec struct {
+	inner ValueCodec
+	level int
+}
+
+// NewGzipCodec wraps inner with gzip compression at gzip.DefaultCompression. Use NewGzipCodecLevel to
+// pick another level.
+func NewGzipCodec(inner ValueCodec) *GzipCodec {
+	return NewGzipCodecLevel(inner, gzip.DefaultCompression)
+}
+
+// NewGzipCodecLevel wraps inner with gzip compression at level. Invalid levels fall back to
+// gzip.DefaultCompression.
+func NewGzipCodecLevel(inner ValueCodec, level int) *GzipCodec {
+	if level < gzip.HuffmanOnly || level > gzip.BestCompression {
+		level = gzip.DefaultCompression
+	}
+
+	return &GzipCodec{
+		inner: inner,
+		level: level,
+	}
+}
+
+func (c *GzipCodec) Marshal(value any) ([]byte, error) {
+	data, err := c.inner.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+
+	writer, err := gzip.NewWriterLevel(&buf, c.level)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (c *GzipCodec) Unmarshal(data []byte, value any) error {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	decompressed, err := io.ReadAll(reader)
+	if err != nil {
+		return err
+	}
+
+	return c.inner.Unmarshal(decompressed, value)
+}
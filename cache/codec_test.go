@@ -0,0 +1,76 @@
+package cache_test
+
+import (
+	"testing"
+
+	"github.com/andyle182810/gframework/cache"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestValueCodecs_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		codec cache.ValueCodec
+	}{
+		{name: "json", codec: cache.NewJSONCodec()},
+		{name: "msgpack", codec: cache.NewMsgpackCodec()},
+		{name: "gzip json", codec: cache.NewGzipCodec(cache.NewJSONCodec())},
+		{name: "gzip msgpack", codec: cache.NewGzipCodecLevel(cache.NewMsgpackCodec(), 9)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			user := &TestUser{ID: 1, Name: "John Doe", Age: 30}
+
+			data, err := tt.codec.Marshal(user)
+			require.NoError(t, err)
+
+			var decoded TestUser
+			require.NoError(t, tt.codec.Unmarshal(data, &decoded))
+			require.Equal(t, *user, decoded)
+		})
+	}
+}
+
+func TestProtoCodec_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	codec := cache.NewGzipCodec(cache.NewProtoCodec())
+
+	data, err := codec.Marshal(wrapperspb.String("John Doe"))
+	require.NoError(t, err)
+
+	var decoded wrapperspb.StringValue
+	require.NoError(t, codec.Unmarshal(data, &decoded))
+	require.Equal(t, "John Doe", decoded.GetValue())
+}
+
+func TestProtoCodec_RejectsNonProtoValues(t *testing.T) {
+	t.Parallel()
+
+	codec := cache.NewProtoCodec()
+
+	_, err := codec.Marshal(&TestUser{ID: 1, Name: "John Doe", Age: 30})
+	require.ErrorIs(t, err, cache.ErrCacheInvalidValueType)
+
+	var decoded TestUser
+	require.ErrorIs(t, codec.Unmarshal([]byte{}, &decoded), cache.ErrCacheInvalidValueType)
+}
+
+func TestGzipCodec_CompressesRepetitiveValues(t *testing.T) {
+	t.Parallel()
+
+	value := &TestUser{ID: 1, Name: string(make([]byte, 4096)), Age: 30}
+
+	plain, err := cache.NewJSONCodec().Marshal(value)
+	require.NoError(t, err)
+
+	compressed, err := cache.NewGzipCodec(cache.NewJSONCodec()).Marshal(value)
+	require.NoError(t, err)
+	require.Less(t, len(compressed), len(plain))
+}
@@ -0,0 +1,175 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var ErrCacheIncrement = errors.New("cache: failed to increment")
+
+// Counter stores atomic numeric values, e.g. quota counters or view counts. Unlike Cache, every key is
+// a separate Redis key named prefix:key, so each counter expires on its own.
+//
+// The TTL starts with the first increment of a key and is not extended by later increments, which
+// makes a counter a fixed window: a quota counter with a one-hour TTL resets an hour after the first
+// request. A TTL <= 0 keeps counters until they are deleted.
+type Counter[K any] struct {
+	client     redis.UniversalClient
+	prefix     string
+	ttl        time.Duration
+	keyEncoder KeyEncoder
+}
+
+func NewCounter[K any](
+	client redis.UniversalClient,
+	prefix string,
+	ttl time.Duration,
+	keyEncoder KeyEncoder,
+) *Counter[K] {
+	return &Counter[K]{
+		client:     client,
+		prefix:     prefix,
+		ttl:        ttl,
+		keyEncoder: keyEncoder,
+	}
+}
+
+// Increment adds delta to the counter and returns the new value. Missing counters start at zero.
+func (c *Counter[K]) Increment(ctx context.Context, key K, delta int64) (int64, error) {
+	redisKey, err := c.redisKey(key)
+	if err != nil {
+		return 0, err
+	}
+
+	var incr *redis.IntCmd
+
+	_, err = c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.IncrBy(ctx, redisKey, delta)
+		c.expire(ctx, pipe, redisKey)
+
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrCacheIncrement, err)
+	}
+
+	return incr.Val(), nil
+}
+
+// Decrement subtracts delta from the counter and returns the new value.
+func (c *Counter[K]) Decrement(ctx context.Context, key K, delta int64) (int64, error) {
+	return c.Increment(ctx, key, -delta)
+}
+
+// IncrementFloat adds delta to a floating point counter and returns the new value.
+func (c *Counter[K]) IncrementFloat(ctx context.Context, key K, delta float64) (float64, error) {
+	redisKey, err := c.redisKey(key)
+	if err != nil {
+		return 0, err
+	}
+
+	var incr *redis.FloatCmd
+
+	_, err = c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.IncrByFloat(ctx, redisKey, delta)
+		c.expire(ctx, pipe, redisKey)
+
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrCacheIncrement, err)
+	}
+
+	return incr.Val(), nil
+}
+
+// Get returns the value of the counter, or zero when it does not exist.
+func (c *Counter[K]) Get(ctx context.Context, key K) (int64, error) {
+	redisKey, err := c.redisKey(key)
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := c.client.Get(ctx, redisKey).Int64()
+	if err != nil {
+		return 0, counterGetError(err)
+	}
+
+	return value, nil
+}
+
+// GetFloat returns the value of a floating point counter, or zero when it does not exist.
+func (c *Counter[K]) GetFloat(ctx context.Context, key K) (float64, error) {
+	redisKey, err := c.redisKey(key)
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := c.client.Get(ctx, redisKey).Float64()
+	if err != nil {
+		return 0, counterGetError(err)
+	}
+
+	return value, nil
+}
+
+// TTL returns the remaining time until the counter resets, or zero when it does not expire.
+func (c *Counter[K]) TTL(ctx context.Context, key K) (time.Duration, error) {
+	redisKey, err := c.redisKey(key)
+	if err != nil {
+		return 0, err
+	}
+
+	ttl, err := c.client.PTTL(ctx, redisKey).Result()
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrCacheGet, err)
+	}
+
+	return max(ttl, 0), nil
+}
+
+func (c *Counter[K]) Delete(ctx context.Context, key K) error {
+	redisKey, err := c.redisKey(key)
+	if err != nil {
+		return err
+	}
+
+	if err := c.client.Del(ctx, redisKey).Err(); err != nil {
+		return fmt.Errorf("%w: %w", ErrCacheDelete, err)
+	}
+
+	return nil
+}
+
+func (c *Counter[K]) expire(ctx context.Context, pipe redis.Pipeliner, redisKey string) {
+	if c.ttl > 0 {
+		pipe.ExpireNX(ctx, redisKey, c.ttl)
+	}
+}
+
+func (c *Counter[K]) redisKey(key K) (string, error) {
+	encodedKey, err := c.keyEncoder.Encode(key)
+	if err != nil {
+		return "", err
+	}
+
+	return BuildKey(c.prefix, encodedKey), nil
+}
+
+func counterGetError(err error) error {
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		return fmt.Errorf("%w: %w", ErrCacheUnmarshal, err)
+	}
+
+	return fmt.Errorf("%w: %w", ErrCacheGet, err)
+}
@@ -0,0 +1,126 @@
+package cache_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/cache"
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func setupCounter(t *testing.T, ttl time.Duration) *cache.Counter[string] {
+	t.Helper()
+
+	client := testutil.SetupValkey(t)
+
+	return cache.NewCounter[string](client, "quota", ttl, cache.NewStringKeyEncoder())
+}
+
+func TestCounter_IncrementAndDecrement(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	counter := setupCounter(t, time.Minute)
+
+	value, err := counter.Get(ctx, "tenant:1")
+	require.NoError(t, err)
+	require.Zero(t, value)
+
+	value, err = counter.Increment(ctx, "tenant:1", 5)
+	require.NoError(t, err)
+	require.Equal(t, int64(5), value)
+
+	value, err = counter.Decrement(ctx, "tenant:1", 2)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), value)
+
+	value, err = counter.Get(ctx, "tenant:1")
+	require.NoError(t, err)
+	require.Equal(t, int64(3), value)
+
+	require.NoError(t, counter.Delete(ctx, "tenant:1"))
+
+	value, err = counter.Get(ctx, "tenant:1")
+	require.NoError(t, err)
+	require.Zero(t, value)
+}
+
+func TestCounter_IncrementIsAtomic(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	counter := setupCounter(t, time.Minute)
+
+	var wg sync.WaitGroup
+
+	for range 50 {
+		wg.Go(func() {
+			_, err := counter.Increment(ctx, "views", 1)
+			require.NoError(t, err)
+		})
+	}
+
+	wg.Wait()
+
+	value, err := counter.Get(ctx, "views")
+	require.NoError(t, err)
+	require.Equal(t, int64(50), value)
+}
+
+func TestCounter_TTLStartsWithFirstIncrement(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	counter := setupCounter(t, time.Minute)
+
+	_, err := counter.Increment(ctx, "tenant:2", 1)
+	require.NoError(t, err)
+
+	first, err := counter.TTL(ctx, "tenant:2")
+	require.NoError(t, err)
+	require.Greater(t, first, 50*time.Second)
+
+	time.Sleep(1100 * time.Millisecond)
+
+	_, err = counter.Increment(ctx, "tenant:2", 1)
+	require.NoError(t, err)
+
+	second, err := counter.TTL(ctx, "tenant:2")
+	require.NoError(t, err)
+	require.Less(t, second, first)
+}
+
+func TestCounter_WithoutTTL(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	counter := setupCounter(t, 0)
+
+	_, err := counter.Increment(ctx, "views", 1)
+	require.NoError(t, err)
+
+	ttl, err := counter.TTL(ctx, "views")
+	require.NoError(t, err)
+	require.Zero(t, ttl)
+}
+
+func TestCounter_IncrementFloat(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	counter := setupCounter(t, time.Minute)
+
+	value, err := counter.IncrementFloat(ctx, "spend", 1.5)
+	require.NoError(t, err)
+	require.InDelta(t, 1.5, value, 0.0001)
+
+	value, err = counter.IncrementFloat(ctx, "spend", 0.25)
+	require.NoError(t, err)
+	require.InDelta(t, 1.75, value, 0.0001)
+
+	value, err = counter.GetFloat(ctx, "spend")
+	require.NoError(t, err)
+	require.InDelta(t, 1.75, value, 0.0001)
+}
@@ -0,0 +1,105 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const scanBatchSize = 100
+
+var ErrCacheScan = errors.New("cache: failed to scan")
+
+// Keys returns the encoded keys of the entries whose key matches the glob pattern, e.g. "user:*". An
+// empty pattern matches every entry. Keys iterates with HSCAN, so entries written concurrently may or
+// may not be included.
+func (c *Cache[K, V]) Keys(ctx context.Context, match string) ([]string, error) {
+	var keys []string
+
+	err := c.scan(ctx, match, func(field, _ string) error {
+		keys = append(keys, field)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// Scan calls fn with the encoded key and value of every entry whose key matches the glob pattern.
+// Iteration stops at the first error returned by fn, which Scan returns unwrapped.
+func (c *Cache[K, V]) Scan(ctx context.Context, match string, fn func(key string, value *V) error) error {
+	return c.scan(ctx, match, func(field, data string) error {
+		var value V
+		if err := c.codec.Unmarshal([]byte(data), &value); err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrCacheUnmarshal, field, err)
+		}
+
+		return fn(field, &value)
+	})
+}
+
+// Touch resets the TTL of the cache if key exists and returns ErrKeyNotFound otherwise. Entries share
+// the TTL of their hash, so touching one entry extends all of them.
+func (c *Cache[K, V]) Touch(ctx context.Context, key K) error {
+	encodedKey, err := c.keyEncoder.Encode(key)
+	if err != nil {
+		return err
+	}
+
+	exists, err := c.client.HExists(ctx, c.hashKey, encodedKey).Result()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrCacheGet, err)
+	}
+
+	if !exists {
+		return ErrKeyNotFound
+	}
+
+	if err := c.client.Expire(ctx, c.hashKey, c.ttl).Err(); err != nil {
+		return fmt.Errorf("%w: %w", ErrCacheTTL, err)
+	}
+
+	return nil
+}
+
+// TTL returns the remaining time until the cache expires, or zero when it is empty. Warmers can use it
+// to refresh entries shortly before they expire.
+func (c *Cache[K, V]) TTL(ctx context.Context) (time.Duration, error) {
+	ttl, err := c.client.PTTL(ctx, c.hashKey).Result()
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrCacheGet, err)
+	}
+
+	return max(ttl, 0), nil
+}
+
+func (c *Cache[K, V]) scan(ctx context.Context, match string, fn func(field, data string) error) error {
+	if match == "" {
+		match = "*"
+	}
+
+	var cursor uint64
+
+	for {
+		pairs, next, err := c.client.HScan(ctx, c.hashKey, cursor, match, scanBatchSize).Result()
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrCacheScan, err)
+		}
+
+		for i := 0; i+1 < len(pairs); i += 2 {
+			if err := fn(pairs[i], pairs[i+1]); err != nil {
+				return err
+			}
+		}
+
+		if next == 0 {
+			return nil
+		}
+
+		cursor = next
+	}
+}
@@ -0,0 +1,71 @@
+package cache_test
+
+import (
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/cache"
+	"github.com/stretchr/testify/require"
+)
+
+var errStopScan = errors.New("stop scan")
+
+func TestCache_KeysAndScan(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	c := setupLoadingCache(t)
+
+	require.NoError(t, c.Set(ctx, "session:1", &TestUser{ID: 1, Name: "John", Age: 30}))
+	require.NoError(t, c.Set(ctx, "session:2", &TestUser{ID: 2, Name: "Jane", Age: 25}))
+	require.NoError(t, c.Set(ctx, "user:3", &TestUser{ID: 3, Name: "Jim", Age: 40}))
+
+	keys, err := c.Keys(ctx, "session:*")
+	require.NoError(t, err)
+	sort.Strings(keys)
+	require.Equal(t, []string{"session:1", "session:2"}, keys)
+
+	all, err := c.Keys(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+
+	names := map[string]string{}
+
+	require.NoError(t, c.Scan(ctx, "session:*", func(key string, value *TestUser) error {
+		names[key] = value.Name
+
+		return nil
+	}))
+	require.Equal(t, map[string]string{"session:1": "John", "session:2": "Jane"}, names)
+
+	err = c.Scan(ctx, "", func(string, *TestUser) error { return errStopScan })
+	require.ErrorIs(t, err, errStopScan)
+}
+
+func TestCache_TouchAndTTL(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	c := setupLoadingCache(t)
+
+	ttl, err := c.TTL(ctx)
+	require.NoError(t, err)
+	require.Zero(t, ttl)
+
+	require.ErrorIs(t, c.Touch(ctx, "session:1"), cache.ErrKeyNotFound)
+
+	require.NoError(t, c.Set(ctx, "session:1", &TestUser{ID: 1, Name: "John", Age: 30}))
+	time.Sleep(1100 * time.Millisecond)
+
+	before, err := c.TTL(ctx)
+	require.NoError(t, err)
+	require.Less(t, before, time.Minute)
+
+	require.NoError(t, c.Touch(ctx, "session:1"))
+
+	after, err := c.TTL(ctx)
+	require.NoError(t, err)
+	require.Greater(t, after, before)
+}
@@ -0,0 +1,245 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/andyle182810/gframework/distlock"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	loadLockPollInterval = 50 * time.Millisecond
+	defaultLoadTimeout   = 30 * time.Second
+)
+
+var ErrCacheLoad = errors.New("cache: failed to load")
+
+// LoaderFunc loads a value on a cache miss. It returns ErrKeyNotFound when the value does not exist,
+// which is cached for the negative TTL when WithNegativeTTL is set.
+type LoaderFunc[V any] func(ctx context.Context) (*V, error)
+
+// WithNegativeTTL caches not-found results for ttl so that lookups of missing keys do not reach the
+// loader on every call. Invalidate does not clear negative entries; they expire on their own.
+func WithNegativeTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		if ttl > 0 {
+			o.negTTL = ttl
+		}
+	}
+}
+
+// WithLoadLock makes GetOrLoad take a Redis lock per key before calling the loader, so that only one
+// replica loads a missing key. Replicas that do not get the lock wait up to ttl for the value to appear
+// and then load it themselves.
+func WithLoadLock(ttl time.Duration) Option {
+	return func(o *options) {
+		if ttl > 0 {
+			o.lockTTL = ttl
+		}
+	}
+}
+
+// WithLoadTimeout bounds a shared loader call. The load runs detached from the cancellation of the
+// caller that started it, so that other callers waiting for the same key are not failed by it. It
+// defaults to 30 seconds.
+func WithLoadTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		if timeout > 0 {
+			o.loadTimeout = timeout
+		}
+	}
+}
+
+// GetOrLoad returns the cached value for key, calling loader on a miss and caching its result.
+// Concurrent misses for the same key in this process share a single loader call. Every caller gets its
+// own shallow copy of the value. A caller whose ctx ends stops waiting without cancelling the shared
+// load, which is bounded by the load timeout instead.
+func (c *Cache[K, V]) GetOrLoad(ctx context.Context, key K, loader LoaderFunc[V]) (*V, error) {
+	encodedKey, err := c.keyEncoder.Encode(key)
+	if err != nil {
+		return nil, err
+	}
+
+	switch value, state := c.lookup(ctx, key, encodedKey); state {
+	case lookupHit:
+		return value, nil
+	case lookupNotFound:
+		return nil, ErrKeyNotFound
+	case lookupMiss:
+	}
+
+	resultCh := c.group.DoChan(encodedKey, func() (any, error) {
+		loadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.loadTimeout)
+		defer cancel()
+
+		return c.load(loadCtx, key, encodedKey, loader)
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-resultCh:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+
+		loaded, _ := result.Val.(*V)
+		copied := *loaded
+
+		return &copied, nil
+	}
+}
+
+type lookupState int
+
+const (
+	lookupMiss lookupState = iota
+	lookupHit
+	// lookupNotFound is a hit on a negative cache entry.
+	lookupNotFound
+)
+
+// lookup reads key from the cache. Read failures are logged and treated as a miss.
+func (c *Cache[K, V]) lookup(ctx context.Context, key K, encodedKey string) (*V, lookupState) {
+	value, err := c.Get(ctx, key)
+	if err == nil {
+		return value, lookupHit
+	}
+
+	if !errors.Is(err, ErrKeyNotFound) {
+		log.Warn().
+			Str("source", "gframework").
+			Err(err).
+			Str("hash_key", c.hashKey).
+			Msg("Cache read failed, falling back to loader")
+
+		return nil, lookupMiss
+	}
+
+	if c.negTTL <= 0 {
+		return nil, lookupMiss
+	}
+
+	if exists, err := c.client.Exists(ctx, c.missKey(encodedKey)).Result(); err == nil && exists > 0 {
+		return nil, lookupNotFound
+	}
+
+	return nil, lookupMiss
+}
+
+func (c *Cache[K, V]) load(ctx context.Context, key K, encodedKey string, loader LoaderFunc[V]) (*V, error) {
+	if c.locker == nil {
+		return c.loadAndStore(ctx, encodedKey, loader)
+	}
+
+	var value *V
+
+	err := c.locker.WithLock(ctx, c.lockKey(encodedKey), c.lockTTL, func() error {
+		// Another replica may have loaded the value while this one waited for the lock.
+		var state lookupState
+
+		value, state = c.lookup(ctx, key, encodedKey)
+
+		switch state {
+		case lookupHit:
+			return nil
+		case lookupNotFound:
+			return ErrKeyNotFound
+		case lookupMiss:
+		}
+
+		var loadErr error
+
+		value, loadErr = c.loadAndStore(ctx, encodedKey, loader)
+
+		return loadErr
+	})
+	if errors.Is(err, distlock.ErrLockNotObtained) {
+		return c.awaitLoad(ctx, key, encodedKey, loader)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// awaitLoad polls the cache while another replica holds the load lock, and loads the value itself
+// once the lock TTL has passed without a result.
+func (c *Cache[K, V]) awaitLoad(ctx context.Context, key K, encodedKey string, loader LoaderFunc[V]) (*V, error) {
+	ticker := c.clock.NewTicker(loadLockPollInterval)
+	defer ticker.Stop()
+
+	deadline := c.clock.Now().Add(c.lockTTL)
+
+	for c.clock.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C():
+		}
+
+		switch value, state := c.lookup(ctx, key, encodedKey); state {
+		case lookupHit:
+			return value, nil
+		case lookupNotFound:
+			return nil, ErrKeyNotFound
+		case lookupMiss:
+		}
+	}
+
+	return c.loadAndStore(ctx, encodedKey, loader)
+}
+
+func (c *Cache[K, V]) loadAndStore(ctx context.Context, encodedKey string, loader LoaderFunc[V]) (*V, error) {
+	value, err := loader(ctx)
+	if errors.Is(err, ErrKeyNotFound) {
+		c.storeNegative(ctx, encodedKey)
+
+		return nil, ErrKeyNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCacheLoad, err)
+	}
+
+	if value == nil {
+		return nil, fmt.Errorf("%w: loader returned a nil value", ErrCacheLoad)
+	}
+
+	if err := c.store(ctx, encodedKey, value); err != nil {
+		log.Warn().
+			Str("source", "gframework").
+			Err(err).
+			Str("hash_key", c.hashKey).
+			Msg("Failed to cache loaded value")
+	}
+
+	return value, nil
+}
+
+func (c *Cache[K, V]) storeNegative(ctx context.Context, encodedKey string) {
+	if c.negTTL <= 0 {
+		return
+	}
+
+	if err := c.client.Set(ctx, c.missKey(encodedKey), 1, c.negTTL).Err(); err != nil {
+		log.Warn().
+			Str("source", "gframework").
+			Err(err).
+			Str("hash_key", c.hashKey).
+			Msg("Failed to cache not-found result")
+	}
+}
+
+func (c *Cache[K, V]) missKey(encodedKey string) string {
+	return BuildKey(c.hashKey, "miss", encodedKey)
+}
+
+func (c *Cache[K, V]) lockKey(encodedKey string) string {
+	return BuildKey(c.hashKey, "lock", encodedKey)
+}
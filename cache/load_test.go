@@ -0,0 +1,180 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/cache"
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+var errLoader = errors.New("loader error")
+
+func setupLoadingCache(t *testing.T, opts ...cache.Option) *cache.Cache[string, TestUser] {
+	t.Helper()
+
+	client := testutil.SetupValkey(t)
+
+	return cache.New[string, TestUser](client, "users", time.Minute, cache.NewStringKeyEncoder(), opts...)
+}
+
+func TestCache_GetOrLoadStoresLoadedValue(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	c := setupLoadingCache(t)
+
+	var calls atomic.Int32
+
+	loader := func(context.Context) (*TestUser, error) {
+		calls.Add(1)
+
+		return &TestUser{ID: 1, Name: "John Doe", Age: 30}, nil
+	}
+
+	user, err := c.GetOrLoad(ctx, "user:1", loader)
+	require.NoError(t, err)
+	require.Equal(t, "John Doe", user.Name)
+
+	user, err = c.GetOrLoad(ctx, "user:1", loader)
+	require.NoError(t, err)
+	require.Equal(t, "John Doe", user.Name)
+	require.Equal(t, int32(1), calls.Load())
+
+	cached, err := c.Get(ctx, "user:1")
+	require.NoError(t, err)
+	require.Equal(t, 1, cached.ID)
+}
+
+func TestCache_GetOrLoadDeduplicatesConcurrentMisses(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	c := setupLoadingCache(t, cache.WithLoadLock(time.Second))
+
+	var calls atomic.Int32
+
+	loader := func(context.Context) (*TestUser, error) {
+		calls.Add(1)
+		time.Sleep(100 * time.Millisecond)
+
+		return &TestUser{ID: 2, Name: "Jane Doe", Age: 25}, nil
+	}
+
+	var wg sync.WaitGroup
+
+	for range 10 {
+		wg.Go(func() {
+			user, err := c.GetOrLoad(ctx, "user:2", loader)
+			require.NoError(t, err)
+			require.Equal(t, 2, user.ID)
+		})
+	}
+
+	wg.Wait()
+
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestCache_GetOrLoadCachesNotFound(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	c := setupLoadingCache(t, cache.WithNegativeTTL(time.Minute))
+
+	var calls atomic.Int32
+
+	loader := func(context.Context) (*TestUser, error) {
+		calls.Add(1)
+
+		return nil, cache.ErrKeyNotFound
+	}
+
+	_, err := c.GetOrLoad(ctx, "user:missing", loader)
+	require.ErrorIs(t, err, cache.ErrKeyNotFound)
+
+	_, err = c.GetOrLoad(ctx, "user:missing", loader)
+	require.ErrorIs(t, err, cache.ErrKeyNotFound)
+	require.Equal(t, int32(1), calls.Load())
+
+	require.NoError(t, c.Delete(ctx, "user:missing"))
+
+	_, err = c.GetOrLoad(ctx, "user:missing", loader)
+	require.ErrorIs(t, err, cache.ErrKeyNotFound)
+	require.Equal(t, int32(2), calls.Load())
+}
+
+func TestCache_GetOrLoadDoesNotCacheErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	c := setupLoadingCache(t, cache.WithNegativeTTL(time.Minute))
+
+	_, err := c.GetOrLoad(ctx, "user:3", func(context.Context) (*TestUser, error) {
+		return nil, errLoader
+	})
+	require.ErrorIs(t, err, cache.ErrCacheLoad)
+	require.ErrorIs(t, err, errLoader)
+
+	user, err := c.GetOrLoad(ctx, "user:3", func(context.Context) (*TestUser, error) {
+		return &TestUser{ID: 3, Name: "Jim", Age: 40}, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, user.ID)
+}
+
+func TestCache_GetOrLoadSurvivesFirstCallerCancel(t *testing.T) {
+	t.Parallel()
+
+	c := setupLoadingCache(t)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	loader := func(ctx context.Context) (*TestUser, error) {
+		close(started)
+		<-release
+
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		return &TestUser{ID: 4, Name: "Joan", Age: 35}, nil
+	}
+
+	firstCtx, cancelFirst := context.WithCancel(t.Context())
+	firstErr := make(chan error, 1)
+
+	go func() {
+		_, err := c.GetOrLoad(firstCtx, "user:4", loader)
+		firstErr <- err
+	}()
+
+	<-started
+
+	type result struct {
+		user *TestUser
+		err  error
+	}
+
+	second := make(chan result, 1)
+
+	go func() {
+		user, err := c.GetOrLoad(t.Context(), "user:4", loader)
+		second <- result{user: user, err: err}
+	}()
+
+	cancelFirst()
+	require.ErrorIs(t, <-firstErr, context.Canceled)
+
+	close(release)
+
+	res := <-second
+	require.NoError(t, res.err)
+	require.Equal(t, 4, res.user.ID)
+}
@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/andyle182810/gframework/metricserver"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics receives cache operations, labeled by the hash key of the cache. A Get that finds nothing
+// reports ErrKeyNotFound.
+type Metrics interface {
+	GetFinished(hashKey string, duration time.Duration, err error)
+	SetFinished(hashKey string, duration time.Duration, err error)
+	Deleted(hashKey string, err error)
+}
+
+func WithMetrics(m Metrics) Option {
+	return func(o *options) {
+		o.metrics = m
+	}
+}
+
+func (c *Cache[K, V]) observeGet(startedAt time.Time, err *error) {
+	c.metrics.GetFinished(c.hashKey, c.clock.Since(startedAt), *err)
+}
+
+func (c *Cache[K, V]) observeSet(startedAt time.Time, err *error) {
+	c.metrics.SetFinished(c.hashKey, c.clock.Since(startedAt), *err)
+}
+
+// PrometheusMetrics implements Metrics with Prometheus collectors. One instance can be shared by
+// several caches.
+type PrometheusMetrics struct {
+	hits        *prometheus.CounterVec
+	misses      *prometheus.CounterVec
+	sets        *prometheus.CounterVec
+	deletes     *prometheus.CounterVec
+	errors      *prometheus.CounterVec
+	getDuration *prometheus.HistogramVec
+	setDuration *prometheus.HistogramVec
+}
+
+// NewPrometheusMetrics registers the cache metrics with reg, e.g. the registerer of metricserver.
+func NewPrometheusMetrics(reg prometheus.Registerer) (*PrometheusMetrics, error) {
+	const subsystem = "cache"
+
+	m := &PrometheusMetrics{
+		hits: metricserver.NewCounterVec(subsystem, "hits_total",
+			"Number of Get calls that found a value.", "hash_key"),
+		misses: metricserver.NewCounterVec(subsystem, "misses_total",
+			"Number of Get calls that found no value.", "hash_key"),
+		sets: metricserver.NewCounterVec(subsystem, "sets_total",
+			"Number of values stored.", "hash_key"),
+		deletes: metricserver.NewCounterVec(subsystem, "deletes_total",
+			"Number of values deleted.", "hash_key"),
+		errors: metricserver.NewCounterVec(subsystem, "errors_total",
+			"Number of failed cache operations.", "hash_key", "operation"),
+		getDuration: metricserver.NewHistogramVec(subsystem, "get_duration_seconds",
+			"Duration of Get calls.", metricserver.LatencyBuckets(), "hash_key"),
+		setDuration: metricserver.NewHistogramVec(subsystem, "set_duration_seconds",
+			"Duration of Set calls.", metricserver.LatencyBuckets(), "hash_key"),
+	}
+
+	err := metricserver.Register(reg, m.hits, m.misses, m.sets, m.deletes, m.errors, m.getDuration, m.setDuration)
+	if err != nil {
+		return nil, fmt.Errorf("cache: failed to register metrics: %w", err)
+	}
+
+	return m, nil
+}
+
+func (m *PrometheusMetrics) GetFinished(hashKey string, duration time.Duration, err error) {
+	m.getDuration.WithLabelValues(hashKey).Observe(duration.Seconds())
+
+	switch {
+	case err == nil:
+		m.hits.WithLabelValues(hashKey).Inc()
+	case errors.Is(err, ErrKeyNotFound):
+		m.misses.WithLabelValues(hashKey).Inc()
+	default:
+		m.errors.WithLabelValues(hashKey, "get").Inc()
+	}
+}
+
+func (m *PrometheusMetrics) SetFinished(hashKey string, duration time.Duration, err error) {
+	m.setDuration.WithLabelValues(hashKey).Observe(duration.Seconds())
+
+	if err != nil {
+		m.errors.WithLabelValues(hashKey, "set").Inc()
+
+		return
+	}
+
+	m.sets.WithLabelValues(hashKey).Inc()
+}
+
+func (m *PrometheusMetrics) Deleted(hashKey string, err error) {
+	if err != nil {
+		m.errors.WithLabelValues(hashKey, "delete").Inc()
+
+		return
+	}
+
+	m.deletes.WithLabelValues(hashKey).Inc()
+}
@@ -0,0 +1,90 @@
+package cache_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/cache"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusMetrics_RecordsOperations(t *testing.T) {
+	t.Parallel()
+
+	reg := prometheus.NewRegistry()
+
+	metrics, err := cache.NewPrometheusMetrics(reg)
+	require.NoError(t, err)
+
+	metrics.GetFinished("users", time.Millisecond, nil)
+	metrics.GetFinished("users", time.Millisecond, cache.ErrKeyNotFound)
+	metrics.GetFinished("users", time.Millisecond, errors.New("connection refused"))
+	metrics.SetFinished("users", time.Millisecond, nil)
+	metrics.Deleted("users", nil)
+
+	expected := `
+# HELP gframework_cache_hits_total Number of Get calls that found a value.
+# TYPE gframework_cache_hits_total counter
+gframework_cache_hits_total{hash_key="users"} 1
+# HELP gframework_cache_misses_total Number of Get calls that found no value.
+# TYPE gframework_cache_misses_total counter
+gframework_cache_misses_total{hash_key="users"} 1
+# HELP gframework_cache_sets_total Number of values stored.
+# TYPE gframework_cache_sets_total counter
+gframework_cache_sets_total{hash_key="users"} 1
+# HELP gframework_cache_deletes_total Number of values deleted.
+# TYPE gframework_cache_deletes_total counter
+gframework_cache_deletes_total{hash_key="users"} 1
+# HELP gframework_cache_errors_total Number of failed cache operations.
+# TYPE gframework_cache_errors_total counter
+gframework_cache_errors_total{hash_key="users",operation="get"} 1
+`
+
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"gframework_cache_hits_total",
+		"gframework_cache_misses_total",
+		"gframework_cache_sets_total",
+		"gframework_cache_deletes_total",
+		"gframework_cache_errors_total",
+	))
+
+	require.Equal(t, 1, testutil.CollectAndCount(reg, "gframework_cache_get_duration_seconds"))
+	require.Equal(t, 1, testutil.CollectAndCount(reg, "gframework_cache_set_duration_seconds"))
+}
+
+func TestCache_WithMetrics(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	reg := prometheus.NewRegistry()
+
+	metrics, err := cache.NewPrometheusMetrics(reg)
+	require.NoError(t, err)
+
+	c := setupLoadingCache(t, cache.WithMetrics(metrics))
+
+	_, err = c.Get(ctx, "user:1")
+	require.ErrorIs(t, err, cache.ErrKeyNotFound)
+	require.NoError(t, c.Set(ctx, "user:1", &TestUser{ID: 1, Name: "John Doe", Age: 30}))
+
+	_, err = c.Get(ctx, "user:1")
+	require.NoError(t, err)
+
+	expected := `
+# HELP gframework_cache_hits_total Number of Get calls that found a value.
+# TYPE gframework_cache_hits_total counter
+gframework_cache_hits_total{hash_key="users"} 1
+# HELP gframework_cache_misses_total Number of Get calls that found no value.
+# TYPE gframework_cache_misses_total counter
+gframework_cache_misses_total{hash_key="users"} 1
+`
+
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"gframework_cache_hits_total",
+		"gframework_cache_misses_total",
+	))
+}
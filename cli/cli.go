@@ -0,0 +1,209 @@
+// Package cli builds the command-line entrypoint of a service with cobra, so every service offers the
+// same operational commands.
+//
+// The subcommands are generated from a Wiring, which builds the parts of the service from its config.
+// Each command loads the config with the config package first:
+//
+//	type Config struct {
+//	    DatabaseURL string `env:"DATABASE_URL" validate:"required"`
+//	    HTTPPort    int    `env:"HTTP_PORT" envDefault:"8080"`
+//	}
+//
+//	func main() {
+//	    cli.New("orders", cli.Wiring[Config]{
+//	        Serve:   app.NewAPI,       // orders serve
+//	        Worker:  app.NewWorker,    // orders worker
+//	        Consume: app.NewConsumers, // orders consume [topic...]
+//	        Health:  app.NewHealth,    // orders healthcheck
+//	        Migrator: func(cfg *Config) (cli.Migrator, error) { // orders migrate up|down|status
+//	            return cli.PostgresMigrator(cfg.DatabaseURL, "file://migrations"), nil
+//	        },
+//	    }, cli.WithVersion(version)).Execute()
+//	}
+//
+// Commands of unset Wiring fields are not added, except healthcheck, which can always probe a URL. The
+// global --config flag adds YAML or JSON files and --env-file .env files, which default to ".env";
+// WithConfigOptions passes further config options, e.g. config.WithEnvPrefix. Custom commands are added
+// to the root command returned by Command.
+//
+// serve, worker and consume run the returned runner until SIGINT or SIGTERM. healthcheck runs the
+// checks of the Health wiring once, or probes the readiness endpoint of a running process with --url,
+// e.g. for a container HEALTHCHECK, and fails when the service is down.
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/andyle182810/gframework/config"
+	"github.com/andyle182810/gframework/health"
+	"github.com/andyle182810/gframework/runner"
+	"github.com/spf13/cobra"
+)
+
+const defaultEnvFile = ".env"
+
+var ErrUnhealthy = errors.New("cli: service is unhealthy")
+
+// Wiring builds the parts of a service from its config C.
+type Wiring[C any] struct {
+	// Serve returns the runner of the API process, run by the serve command.
+	Serve func(ctx context.Context, cfg *C) (*runner.Runner, error)
+	// Worker returns the runner of the background process, e.g. task queue workers and scheduled jobs,
+	// run by the worker command.
+	Worker func(ctx context.Context, cfg *C) (*runner.Runner, error)
+	// Consume returns the runner of the message subscribers, run by the consume command. topics holds
+	// the arguments of the command; when it is empty, all subscribers should run.
+	Consume func(ctx context.Context, cfg *C, topics []string) (*runner.Runner, error)
+	// Migrator returns the database migrator of the migrate commands.
+	Migrator func(cfg *C) (Migrator, error)
+	// Health returns the checks of the healthcheck command.
+	Health func(ctx context.Context, cfg *C) (*health.Health, error)
+}
+
+type Option func(*options)
+
+type options struct {
+	description   string
+	version       string
+	configOptions []config.Option
+	out           io.Writer
+}
+
+// WithDescription sets the short description shown in the help of the root command.
+func WithDescription(description string) Option {
+	return func(o *options) {
+		o.description = description
+	}
+}
+
+// WithVersion enables the --version flag.
+func WithVersion(version string) Option {
+	return func(o *options) {
+		o.version = version
+	}
+}
+
+// WithConfigOptions adds options to every config.Load, after the files of --config and --env-file.
+func WithConfigOptions(opts ...config.Option) Option {
+	return func(o *options) {
+		o.configOptions = append(o.configOptions, opts...)
+	}
+}
+
+// WithOutput sets where commands print their results, e.g. the migration status. It defaults to
+// os.Stdout.
+func WithOutput(w io.Writer) Option {
+	return func(o *options) {
+		if w != nil {
+			o.out = w
+		}
+	}
+}
+
+type App[C any] struct {
+	wiring      Wiring[C]
+	options     options
+	root        *cobra.Command
+	configFiles []string
+	envFiles    []string
+}
+
+func New[C any](name string, wiring Wiring[C], opts ...Option) *App[C] {
+	o := options{
+		description:   "",
+		version:       "",
+		configOptions: nil,
+		out:           os.Stdout,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	app := &App[C]{
+		wiring:      wiring,
+		options:     o,
+		root:        nil,
+		configFiles: nil,
+		envFiles:    nil,
+	}
+
+	app.root = app.newRootCommand(name)
+
+	return app
+}
+
+// Command returns the root command, e.g. to add service specific commands with AddCommand.
+func (a *App[C]) Command() *cobra.Command {
+	return a.root
+}
+
+// Execute runs the command of the process arguments and exits the process with status 1 when it
+// fails.
+func (a *App[C]) Execute() {
+	if err := a.ExecuteContext(context.Background(), os.Args[1:]); err != nil {
+		os.Exit(1)
+	}
+}
+
+// ExecuteContext runs the command of args and returns its error.
+func (a *App[C]) ExecuteContext(ctx context.Context, args []string) error {
+	a.root.SetArgs(args)
+
+	return a.root.ExecuteContext(ctx)
+}
+
+func (a *App[C]) newRootCommand(name string) *cobra.Command {
+	root := newCommand(name, a.options.description, nil, nil)
+	root.Version = a.options.version
+	root.SilenceUsage = true
+
+	root.SetOut(a.options.out)
+	root.PersistentFlags().StringSliceVarP(&a.configFiles, "config", "c", nil,
+		"YAML or JSON config file, may be repeated")
+	root.PersistentFlags().StringSliceVar(&a.envFiles, "env-file", []string{defaultEnvFile},
+		".env file, skipped when missing, may be repeated")
+
+	if a.wiring.Serve != nil {
+		root.AddCommand(a.runCommand("serve", "Run the API server", a.wiring.Serve))
+	}
+
+	if a.wiring.Worker != nil {
+		root.AddCommand(a.runCommand("worker", "Run the background workers", a.wiring.Worker))
+	}
+
+	if a.wiring.Consume != nil {
+		root.AddCommand(a.consumeCommand())
+	}
+
+	if a.wiring.Migrator != nil {
+		root.AddCommand(a.migrateCommand())
+	}
+
+	root.AddCommand(a.healthcheckCommand())
+
+	return root
+}
+
+// loadConfig loads the config of a command with the files of the global flags.
+func (a *App[C]) loadConfig(ctx context.Context) (*C, error) {
+	opts := make([]config.Option, 0, len(a.configFiles)+1+len(a.options.configOptions))
+
+	for _, path := range a.configFiles {
+		opts = append(opts, config.WithFile(path))
+	}
+
+	opts = append(opts, config.WithDotEnv(a.envFiles...))
+	opts = append(opts, a.options.configOptions...)
+
+	cfg := new(C)
+	if err := config.Load(ctx, cfg, opts...); err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	return cfg, nil
+}
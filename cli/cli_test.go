@@ -0,0 +1,288 @@
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/andyle182810/gframework/cli"
+	"github.com/andyle182810/gframework/config"
+	"github.com/andyle182810/gframework/health"
+	"github.com/andyle182810/gframework/runner"
+	"github.com/stretchr/testify/require"
+)
+
+var errDown = errors.New("database is down")
+
+type testConfig struct {
+	Name        string `env:"NAME" envDefault:"orders" yaml:"name"`
+	DatabaseURL string `env:"DATABASE_URL" validate:"required"`
+}
+
+// testEnv is the environment of the commands, so the tests do not depend on the process environment.
+func testEnv(values map[string]string) cli.Option {
+	return cli.WithConfigOptions(config.WithLookupEnv(func(key string) (string, bool) {
+		value, ok := values[key]
+
+		return value, ok
+	}))
+}
+
+type fakeMigrator struct {
+	calls   []string
+	version uint
+}
+
+func newFakeMigrator() *fakeMigrator {
+	return &fakeMigrator{calls: nil, version: 0}
+}
+
+func (m *fakeMigrator) Up() error {
+	m.calls = append(m.calls, "up")
+	m.version = 3
+
+	return nil
+}
+
+func (m *fakeMigrator) Down(steps int) error {
+	m.calls = append(m.calls, "down")
+
+	if steps == 0 {
+		m.version = 0
+	} else {
+		m.version -= uint(steps) //nolint:gosec
+	}
+
+	return nil
+}
+
+func (m *fakeMigrator) Version() (uint, bool, error) {
+	return m.version, false, nil
+}
+
+type stopService struct {
+	started chan struct{}
+}
+
+func (s *stopService) Start(context.Context) error {
+	close(s.started)
+
+	return nil
+}
+
+func (s *stopService) Stop() error {
+	return nil
+}
+
+func (s *stopService) Name() string {
+	return "stop"
+}
+
+func TestApp_AddsCommandsOfWiring(t *testing.T) {
+	t.Parallel()
+
+	app := cli.New("orders", cli.Wiring[testConfig]{
+		Serve:   nil,
+		Worker:  nil,
+		Consume: nil,
+		Migrator: func(*testConfig) (cli.Migrator, error) {
+			return newFakeMigrator(), nil
+		},
+		Health: nil,
+	})
+
+	var names []string
+	for _, cmd := range app.Command().Commands() {
+		names = append(names, cmd.Name())
+	}
+
+	require.ElementsMatch(t, []string{"migrate", "healthcheck"}, names)
+}
+
+func TestApp_ServeRunsRunnerWithLoadedConfig(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte("name: billing\n"), 0o600))
+
+	service := &stopService{started: make(chan struct{})}
+
+	var loaded *testConfig
+
+	app := cli.New("orders", cli.Wiring[testConfig]{
+		Serve: func(_ context.Context, cfg *testConfig) (*runner.Runner, error) {
+			loaded = cfg
+
+			return runner.New(runner.WithCoreService(service)), nil
+		},
+		Worker:   nil,
+		Consume:  nil,
+		Migrator: nil,
+		Health:   nil,
+	}, testEnv(map[string]string{"DATABASE_URL": "postgres://localhost/orders"}))
+
+	ctx, cancel := context.WithCancel(t.Context())
+
+	go func() {
+		<-service.started
+		cancel()
+	}()
+
+	err := app.ExecuteContext(ctx, []string{"serve", "--config", configFile, "--env-file", filepath.Join(dir, ".env")})
+	require.NoError(t, err, "a cancelled runner is a clean shutdown")
+	require.Equal(t, "billing", loaded.Name)
+	require.Equal(t, "postgres://localhost/orders", loaded.DatabaseURL)
+}
+
+func TestApp_ConsumePassesTopics(t *testing.T) {
+	t.Parallel()
+
+	var topics []string
+
+	app := cli.New("orders", cli.Wiring[testConfig]{
+		Serve:  nil,
+		Worker: nil,
+		Consume: func(_ context.Context, _ *testConfig, args []string) (*runner.Runner, error) {
+			topics = args
+
+			return nil, errDown
+		},
+		Migrator: nil,
+		Health:   nil,
+	}, testEnv(map[string]string{"DATABASE_URL": "postgres://localhost/orders"}))
+	app.Command().SetErr(&bytes.Buffer{})
+
+	err := app.ExecuteContext(t.Context(), []string{"consume", "orders", "payments"})
+	require.ErrorIs(t, err, errDown)
+	require.Equal(t, []string{"orders", "payments"}, topics)
+}
+
+func TestApp_FailsOnInvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	app := cli.New("orders", cli.Wiring[testConfig]{
+		Serve: nil,
+		Worker: func(context.Context, *testConfig) (*runner.Runner, error) {
+			t.Fatal("the worker must not be built without a valid config")
+
+			return nil, nil //nolint:nilnil
+		},
+		Consume:  nil,
+		Migrator: nil,
+		Health:   nil,
+	}, testEnv(nil))
+	app.Command().SetErr(&bytes.Buffer{})
+
+	err := app.ExecuteContext(t.Context(), []string{"worker"})
+	require.ErrorContains(t, err, "failed to load config")
+}
+
+func TestApp_Migrate(t *testing.T) {
+	t.Parallel()
+
+	migrator := newFakeMigrator()
+
+	var out bytes.Buffer
+
+	newApp := func() *cli.App[testConfig] {
+		app := cli.New("orders", cli.Wiring[testConfig]{
+			Serve:   nil,
+			Worker:  nil,
+			Consume: nil,
+			Migrator: func(*testConfig) (cli.Migrator, error) {
+				return migrator, nil
+			},
+			Health: nil,
+		}, testEnv(map[string]string{"DATABASE_URL": "postgres://localhost/orders"}), cli.WithOutput(&out))
+		app.Command().SetErr(&bytes.Buffer{})
+
+		return app
+	}
+
+	require.NoError(t, newApp().ExecuteContext(t.Context(), []string{"migrate", "up"}))
+	require.NoError(t, newApp().ExecuteContext(t.Context(), []string{"migrate", "down"}))
+	require.NoError(t, newApp().ExecuteContext(t.Context(), []string{"migrate", "status"}))
+	require.Equal(t, "version: 2\ndirty: false\n", out.String())
+
+	require.ErrorIs(t, newApp().ExecuteContext(t.Context(), []string{"migrate", "down", "--steps", "0"}),
+		cli.ErrInvalidSteps)
+	require.Error(t, newApp().ExecuteContext(t.Context(), []string{"migrate", "down", "--steps", "2", "--all"}))
+
+	require.NoError(t, newApp().ExecuteContext(t.Context(), []string{"migrate", "down", "--all"}))
+	require.Equal(t, []string{"up", "down", "down"}, migrator.calls)
+	require.Zero(t, migrator.version)
+}
+
+func TestApp_HealthcheckRunsChecks(t *testing.T) {
+	t.Parallel()
+
+	newApp := func(checkErr error, out *bytes.Buffer) *cli.App[testConfig] {
+		app := cli.New("orders", cli.Wiring[testConfig]{
+			Serve:    nil,
+			Worker:   nil,
+			Consume:  nil,
+			Migrator: nil,
+			Health: func(context.Context, *testConfig) (*health.Health, error) {
+				checks := health.New()
+				checks.Register(health.Checker{
+					Name: "postgres",
+					Check: func(context.Context) error {
+						return checkErr
+					},
+					Timeout:  0,
+					Critical: true,
+				})
+
+				return checks, nil
+			},
+		}, testEnv(map[string]string{"DATABASE_URL": "postgres://localhost/orders"}), cli.WithOutput(out))
+		app.Command().SetErr(&bytes.Buffer{})
+
+		return app
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, newApp(nil, &out).ExecuteContext(t.Context(), []string{"healthcheck"}))
+	require.Contains(t, out.String(), `"status": "up"`)
+
+	out.Reset()
+	require.ErrorIs(t, newApp(errDown, &out).ExecuteContext(t.Context(), []string{"healthcheck"}), cli.ErrUnhealthy)
+	require.Contains(t, out.String(), errDown.Error())
+}
+
+func TestApp_HealthcheckProbesURL(t *testing.T) {
+	t.Parallel()
+
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	newApp := func() *cli.App[testConfig] {
+		app := cli.New("orders", cli.Wiring[testConfig]{
+			Serve:    nil,
+			Worker:   nil,
+			Consume:  nil,
+			Migrator: nil,
+			Health:   nil,
+		}, testEnv(nil))
+		app.Command().SetErr(&bytes.Buffer{})
+
+		return app
+	}
+
+	require.NoError(t, newApp().ExecuteContext(t.Context(), []string{"healthcheck", "--url", server.URL + "/ready"}))
+
+	status = http.StatusServiceUnavailable
+	require.ErrorIs(t, newApp().ExecuteContext(t.Context(), []string{"healthcheck", "--url", server.URL + "/ready"}),
+		cli.ErrUnhealthy)
+
+	require.ErrorIs(t, newApp().ExecuteContext(t.Context(), []string{"healthcheck"}), cli.ErrNoHealthChecks)
+}
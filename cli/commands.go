@@ -0,0 +1,196 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/andyle182810/gframework/health"
+	"github.com/andyle182810/gframework/runner"
+	"github.com/spf13/cobra"
+)
+
+const defaultHealthcheckTimeout = 5 * time.Second
+
+var ErrNoHealthChecks = errors.New("cli: no Health wiring, use --url to probe a running process")
+
+// newCommand creates a command that runs runE. args is nil for commands that accept any arguments and
+// runE is nil for commands that only group subcommands.
+func newCommand(use, short string, args cobra.PositionalArgs, runE func(*cobra.Command, []string) error) *cobra.Command {
+	return &cobra.Command{
+		Use:                    use,
+		Aliases:                nil,
+		SuggestFor:             nil,
+		Short:                  short,
+		GroupID:                "",
+		Long:                   "",
+		Example:                "",
+		ValidArgs:              nil,
+		ValidArgsFunction:      nil,
+		Args:                   args,
+		ArgAliases:             nil,
+		BashCompletionFunction: "",
+		Deprecated:             "",
+		Annotations:            nil,
+		Version:                "",
+		PersistentPreRun:       nil,
+		PersistentPreRunE:      nil,
+		PreRun:                 nil,
+		PreRunE:                nil,
+		Run:                    nil,
+		RunE:                   runE,
+		PostRun:                nil,
+		PostRunE:               nil,
+		PersistentPostRun:      nil,
+		PersistentPostRunE:     nil,
+		FParseErrWhitelist: cobra.FParseErrWhitelist{
+			UnknownFlags: false,
+		},
+		CompletionOptions: cobra.CompletionOptions{
+			DisableDefaultCmd:         false,
+			DisableNoDescFlag:         false,
+			DisableDescriptions:       false,
+			HiddenDefaultCmd:          false,
+			DefaultShellCompDirective: nil,
+		},
+		TraverseChildren:           false,
+		Hidden:                     false,
+		SilenceErrors:              false,
+		SilenceUsage:               false,
+		DisableFlagParsing:         false,
+		DisableAutoGenTag:          false,
+		DisableFlagsInUseLine:      false,
+		DisableSuggestions:         false,
+		SuggestionsMinimumDistance: 0,
+	}
+}
+
+func (a *App[C]) runCommand(
+	name string,
+	short string,
+	build func(ctx context.Context, cfg *C) (*runner.Runner, error),
+) *cobra.Command {
+	return newCommand(name, short, cobra.NoArgs, func(cmd *cobra.Command, _ []string) error {
+		cfg, err := a.loadConfig(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		r, err := build(cmd.Context(), cfg)
+		if err != nil {
+			return fmt.Errorf("failed to build %s: %w", name, err)
+		}
+
+		return run(cmd.Context(), r)
+	})
+}
+
+func (a *App[C]) consumeCommand() *cobra.Command {
+	return newCommand("consume [topic...]", "Run the message subscribers of the topics, or all of them", nil,
+		func(cmd *cobra.Command, topics []string) error {
+			cfg, err := a.loadConfig(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			r, err := a.wiring.Consume(cmd.Context(), cfg, topics)
+			if err != nil {
+				return fmt.Errorf("failed to build consume: %w", err)
+			}
+
+			return run(cmd.Context(), r)
+		})
+}
+
+// run runs r until it shuts down. A shutdown on a signal or a cancelled ctx is a success unless the
+// services did not stop in time, as with runner.Run.
+func run(ctx context.Context, r *runner.Runner) error {
+	err := r.RunContext(ctx)
+
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, runner.ErrShutdownTimout):
+		return err
+	case errors.Is(err, runner.ErrSignalReceived), errors.Is(err, context.Canceled):
+		return nil
+	default:
+		return err
+	}
+}
+
+func (a *App[C]) healthcheckCommand() *cobra.Command {
+	var (
+		url     string
+		timeout time.Duration
+	)
+
+	cmd := newCommand("healthcheck",
+		"Check the dependencies of the service, or the readiness of a running process with --url",
+		cobra.NoArgs, func(cmd *cobra.Command, _ []string) error {
+			ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+			defer cancel()
+
+			if url != "" {
+				return probe(ctx, url)
+			}
+
+			if a.wiring.Health == nil {
+				return ErrNoHealthChecks
+			}
+
+			cfg, err := a.loadConfig(ctx)
+			if err != nil {
+				return err
+			}
+
+			checks, err := a.wiring.Health(ctx, cfg)
+			if err != nil {
+				return fmt.Errorf("failed to build health checks: %w", err)
+			}
+
+			report := checks.Check(ctx)
+
+			encoder := json.NewEncoder(cmd.OutOrStdout())
+			encoder.SetIndent("", "  ")
+
+			if err := encoder.Encode(report); err != nil {
+				return fmt.Errorf("failed to print health report: %w", err)
+			}
+
+			if report.Status == health.StatusDown {
+				return ErrUnhealthy
+			}
+
+			return nil
+		})
+
+	cmd.Flags().StringVar(&url, "url", "", "readiness endpoint of a running process, e.g. http://127.0.0.1:8080/ready")
+	cmd.Flags().DurationVar(&timeout, "timeout", defaultHealthcheckTimeout, "timeout of the check")
+
+	return cmd
+}
+
+// probe fails unless url answers with a 2xx status.
+func probe(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
+	if err != nil {
+		return fmt.Errorf("invalid health check URL: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrUnhealthy, err)
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%w: %s returned %d", ErrUnhealthy, url, resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/andyle182810/gframework/clickhouse"
+	"github.com/andyle182810/gframework/postgres"
+	"github.com/spf13/cobra"
+)
+
+var ErrInvalidSteps = errors.New("cli: steps must be positive")
+
+// Migrator applies the schema migrations of a database.
+type Migrator interface {
+	// Up applies all pending migrations.
+	Up() error
+	// Down rolls back the last steps migrations, or all of them when steps is zero.
+	Down(steps int) error
+	// Version returns the current migration version and whether the last migration failed halfway.
+	Version() (version uint, dirty bool, err error)
+}
+
+type postgresMigrator struct {
+	dbURI  string
+	source string
+}
+
+// PostgresMigrator migrates the database at dbURI with the migrations of source, e.g.
+// "file://migrations", using the postgres migration functions.
+func PostgresMigrator(dbURI, source string) Migrator { //nolint:ireturn
+	return postgresMigrator{dbURI: dbURI, source: source}
+}
+
+func (m postgresMigrator) Up() error {
+	return postgres.MigrateUp(m.dbURI, m.source)
+}
+
+func (m postgresMigrator) Down(steps int) error {
+	if steps == 0 {
+		return postgres.MigrateDown(m.dbURI, m.source)
+	}
+
+	return postgres.MigrateSteps(m.dbURI, m.source, -steps)
+}
+
+func (m postgresMigrator) Version() (uint, bool, error) {
+	version, err := postgres.GetMigrationVersion(m.dbURI, m.source)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return version.Version, version.Dirty, nil
+}
+
+type clickhouseMigrator struct {
+	dbURI  string
+	source string
+	cfg    []*clickhouse.MigrationConfig
+}
+
+// ClickHouseMigrator migrates the database at dbURI with the migrations of source using the
+// clickhouse migration functions.
+func ClickHouseMigrator(dbURI, source string, cfg ...*clickhouse.MigrationConfig) Migrator { //nolint:ireturn
+	return clickhouseMigrator{dbURI: dbURI, source: source, cfg: cfg}
+}
+
+func (m clickhouseMigrator) Up() error {
+	return clickhouse.MigrateUp(m.dbURI, m.source, m.cfg...)
+}
+
+func (m clickhouseMigrator) Down(steps int) error {
+	if steps == 0 {
+		return clickhouse.MigrateDown(m.dbURI, m.source, m.cfg...)
+	}
+
+	return clickhouse.MigrateSteps(m.dbURI, m.source, -steps, m.cfg...)
+}
+
+func (m clickhouseMigrator) Version() (uint, bool, error) {
+	version, err := clickhouse.GetMigrationVersion(m.dbURI, m.source, m.cfg...)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return version.Version, version.Dirty, nil
+}
+
+func (a *App[C]) migrateCommand() *cobra.Command {
+	cmd := newCommand("migrate", "Manage the database schema migrations", nil, nil)
+
+	up := newCommand("up", "Apply all pending migrations", cobra.NoArgs, func(cmd *cobra.Command, _ []string) error {
+		migrator, err := a.migrator(cmd)
+		if err != nil {
+			return err
+		}
+
+		return migrator.Up()
+	})
+
+	var (
+		steps int
+		all   bool
+	)
+
+	down := newCommand("down", "Roll back the last migration, or --steps migrations, or --all of them",
+		cobra.NoArgs, func(cmd *cobra.Command, _ []string) error {
+			if !all && steps <= 0 {
+				return ErrInvalidSteps
+			}
+
+			migrator, err := a.migrator(cmd)
+			if err != nil {
+				return err
+			}
+
+			if all {
+				return migrator.Down(0)
+			}
+
+			return migrator.Down(steps)
+		})
+
+	down.Flags().IntVar(&steps, "steps", 1, "number of migrations to roll back")
+	down.Flags().BoolVar(&all, "all", false, "roll back all migrations")
+	down.MarkFlagsMutuallyExclusive("steps", "all")
+
+	status := newCommand("status", "Print the current migration version", cobra.NoArgs,
+		func(cmd *cobra.Command, _ []string) error {
+			migrator, err := a.migrator(cmd)
+			if err != nil {
+				return err
+			}
+
+			version, dirty, err := migrator.Version()
+			if err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "version: %d\ndirty: %t\n", version, dirty)
+
+			return nil
+		})
+
+	cmd.AddCommand(up, down, status)
+
+	return cmd
+}
+
+func (a *App[C]) migrator(cmd *cobra.Command) (Migrator, error) { //nolint:ireturn
+	cfg, err := a.loadConfig(cmd.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	migrator, err := a.wiring.Migrator(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build migrator: %w", err)
+	}
+
+	return migrator, nil
+}
@@ -0,0 +1,91 @@
+//nolint:exhaustruct
+package clickhouse_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/clickhouse"
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/prometheus/client_golang/prometheus"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+const migrationSource = "file://testdata/migrations"
+
+func TestNew_InvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	_, err := clickhouse.New(nil)
+	require.ErrorIs(t, err, clickhouse.ErrConfigNil)
+
+	_, err = clickhouse.New(&clickhouse.Config{})
+	require.ErrorIs(t, err, clickhouse.ErrInvalidURL)
+
+	_, err = clickhouse.New(&clickhouse.Config{URL: "clickhouse://localhost:9000/db?dial_timeout=abc"})
+	require.Error(t, err)
+}
+
+func TestPrometheusMetrics_BatchInserted(t *testing.T) {
+	t.Parallel()
+
+	reg := prometheus.NewRegistry()
+
+	metrics, err := clickhouse.NewPrometheusMetrics(reg)
+	require.NoError(t, err)
+
+	metrics.BatchInserted("page_views", 10, 20*time.Millisecond, nil)
+	metrics.BatchInserted("page_views", 5, 20*time.Millisecond, errServerDown)
+
+	require.Equal(t, 1, promtestutil.CollectAndCount(reg, "gframework_clickhouse_batch_insert_duration_seconds"))
+	require.Equal(t, 1, promtestutil.CollectAndCount(reg, "gframework_clickhouse_inserted_rows_total"))
+	require.Equal(t, 1, promtestutil.CollectAndCount(reg, "gframework_clickhouse_failed_rows_total"))
+}
+
+func TestClickHouse_MigrateAndInsert(t *testing.T) {
+	t.Parallel()
+
+	container := testutil.SetupClickHouseContainer(t)
+	ctx := t.Context()
+
+	require.NoError(t, clickhouse.MigrateUp(container.ConnectionString(), migrationSource))
+
+	version, err := clickhouse.GetMigrationVersion(container.ConnectionString(), migrationSource)
+	require.NoError(t, err)
+	require.Equal(t, &clickhouse.MigrationVersion{Version: 1, Dirty: false}, version)
+
+	reg := prometheus.NewRegistry()
+
+	metrics, err := clickhouse.NewPrometheusMetrics(reg)
+	require.NoError(t, err)
+
+	db, err := clickhouse.New(&clickhouse.Config{URL: container.ConnectionString(), Compression: true, Metrics: metrics})
+	require.NoError(t, err)
+	require.NoError(t, db.Start(ctx))
+
+	t.Cleanup(func() { _ = db.Stop() })
+
+	require.NoError(t, clickhouse.RegisterPoolMetrics(reg, db))
+	require.True(t, db.IsHealthy(ctx))
+
+	writer := newWriter(t, db, clickhouse.WithFlushInterval(time.Hour))
+	require.NoError(t, clickhouse.RegisterWriterMetrics(reg, writer))
+	require.NoError(t, writer.Start(ctx))
+
+	for range 3 {
+		require.NoError(t, writer.Write(time.Now(), "user-1", "/pricing"))
+	}
+
+	require.NoError(t, writer.Flush(ctx))
+	require.NoError(t, writer.Stop())
+
+	var count uint64
+	require.NoError(t, db.Conn.QueryRow(ctx, "SELECT count() FROM page_views").Scan(&count))
+	require.Equal(t, uint64(3), count)
+
+	require.Equal(t, 3, promtestutil.CollectAndCount(reg, "gframework_clickhouse_pool_connections",
+		"gframework_clickhouse_pool_idle_connections", "gframework_clickhouse_pool_max_connections"))
+
+	require.NoError(t, clickhouse.MigrateDown(container.ConnectionString(), migrationSource))
+}
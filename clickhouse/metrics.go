@@ -0,0 +1,134 @@
+package clickhouse
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/andyle182810/gframework/metricserver"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics receives the outcome of every batch insert.
+type Metrics interface {
+	BatchInserted(table string, rows int, duration time.Duration, err error)
+}
+
+// PrometheusMetrics implements Metrics with Prometheus collectors.
+type PrometheusMetrics struct {
+	duration   *prometheus.HistogramVec
+	rows       *prometheus.CounterVec
+	failedRows *prometheus.CounterVec
+}
+
+// NewPrometheusMetrics registers the insert metrics with reg, e.g. the registerer of metricserver.
+// Set it as Config.Metrics.
+func NewPrometheusMetrics(reg prometheus.Registerer) (*PrometheusMetrics, error) {
+	m := &PrometheusMetrics{
+		duration: metricserver.NewHistogramVec("clickhouse", "batch_insert_duration_seconds",
+			"Duration of batch inserts.", metricserver.LatencyBuckets(), "table"),
+		rows: metricserver.NewCounterVec("clickhouse", "inserted_rows_total",
+			"Number of rows inserted.", "table"),
+		failedRows: metricserver.NewCounterVec("clickhouse", "failed_rows_total",
+			"Number of rows of batch inserts that failed.", "table"),
+	}
+
+	if err := metricserver.Register(reg, m.duration, m.rows, m.failedRows); err != nil {
+		return nil, fmt.Errorf("clickhouse: failed to register metrics: %w", err)
+	}
+
+	return m, nil
+}
+
+func (m *PrometheusMetrics) BatchInserted(table string, rows int, duration time.Duration, err error) {
+	m.duration.WithLabelValues(table).Observe(duration.Seconds())
+
+	if err != nil {
+		m.failedRows.WithLabelValues(table).Add(float64(rows))
+
+		return
+	}
+
+	m.rows.WithLabelValues(table).Add(float64(rows))
+}
+
+// RegisterPoolMetrics registers collectors reading c's connection pool stats at scrape time.
+func RegisterPoolMetrics(reg prometheus.Registerer, c *ClickHouse) error {
+	if c == nil || c.Conn == nil {
+		return ErrConnNil
+	}
+
+	if err := reg.Register(newPoolCollector(c)); err != nil {
+		return fmt.Errorf("clickhouse: failed to register pool metrics: %w", err)
+	}
+
+	return nil
+}
+
+// RegisterWriterMetrics registers collectors reading the dropped and failed rows of w at scrape time.
+func RegisterWriterMetrics(reg prometheus.Registerer, w *BatchWriter) error {
+	labels := prometheus.Labels{"table": w.table}
+
+	dropped := prometheus.NewCounterFunc(prometheus.CounterOpts{ //nolint:exhaustruct
+		Namespace:   "gframework",
+		Subsystem:   "clickhouse",
+		Name:        "writer_dropped_rows_total",
+		Help:        "Number of rows discarded because the writer queue was full.",
+		ConstLabels: labels,
+	}, func() float64 { return float64(w.Dropped()) })
+
+	failed := prometheus.NewCounterFunc(prometheus.CounterOpts{ //nolint:exhaustruct
+		Namespace:   "gframework",
+		Subsystem:   "clickhouse",
+		Name:        "writer_failed_rows_total",
+		Help:        "Number of rows the writer could not insert after all attempts.",
+		ConstLabels: labels,
+	}, func() float64 { return float64(w.Failed()) })
+
+	queued := prometheus.NewGaugeFunc(prometheus.GaugeOpts{ //nolint:exhaustruct
+		Namespace:   "gframework",
+		Subsystem:   "clickhouse",
+		Name:        "writer_queued_rows",
+		Help:        "Number of rows waiting in the writer queue.",
+		ConstLabels: labels,
+	}, func() float64 { return float64(len(w.queue)) })
+
+	if err := metricserver.Register(reg, dropped, failed, queued); err != nil {
+		return fmt.Errorf("clickhouse: failed to register writer metrics: %w", err)
+	}
+
+	return nil
+}
+
+type poolCollector struct {
+	clickhouse *ClickHouse
+	openConns  *prometheus.Desc
+	idleConns  *prometheus.Desc
+	maxConns   *prometheus.Desc
+}
+
+func newPoolCollector(c *ClickHouse) *poolCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName("gframework", "clickhouse", name), help, nil, nil)
+	}
+
+	return &poolCollector{
+		clickhouse: c,
+		openConns:  desc("pool_connections", "Number of open connections."),
+		idleConns:  desc("pool_idle_connections", "Number of idle connections."),
+		maxConns:   desc("pool_max_connections", "Maximum number of open connections."),
+	}
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.openConns
+	ch <- c.idleConns
+	ch <- c.maxConns
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.clickhouse.Conn.Stats()
+
+	ch <- prometheus.MustNewConstMetric(c.openConns, prometheus.GaugeValue, float64(stats.Open))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stats.Idle))
+	ch <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(stats.MaxOpenConns))
+}
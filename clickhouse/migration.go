@@ -0,0 +1,221 @@
+//nolint:exhaustruct
+package clickhouse
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	ch "github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/golang-migrate/migrate/v4"
+	chmigrate "github.com/golang-migrate/migrate/v4/database/clickhouse"
+	_ "github.com/golang-migrate/migrate/v4/source/file" //nolint:revive
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	migrationDriverName = "clickhouse"
+	connectionTimeout   = 10 * time.Second
+)
+
+type MigrationVersion struct {
+	Version uint
+	Dirty   bool
+}
+
+// MigrationConfig configures the schema_migrations table of a migration.
+type MigrationConfig struct {
+	// MigrationsTable defaults to "schema_migrations".
+	MigrationsTable string
+	// MigrationsTableEngine defaults to "TinyLog".
+	MigrationsTableEngine string
+	// ClusterName, when set, creates the migrations table ON CLUSTER.
+	ClusterName string
+}
+
+func openAndPingDB(dbURI string) (*sql.DB, error) {
+	options, err := ch.ParseDSN(dbURI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse database URL: %w", err)
+	}
+
+	db := ch.OpenDB(options)
+
+	ctx, cancel := context.WithTimeout(context.Background(), connectionTimeout)
+	defer cancel()
+
+	if err = db.PingContext(ctx); err != nil {
+		_ = db.Close()
+
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return db, nil
+}
+
+func createMigrator(db *sql.DB, source string, cfg *MigrationConfig) (*migrate.Migrate, error) {
+	driver, err := chmigrate.WithInstance(db, &chmigrate.Config{
+		MigrationsTable:       cfg.MigrationsTable,
+		MigrationsTableEngine: cfg.MigrationsTableEngine,
+		ClusterName:           cfg.ClusterName,
+		// ClickHouse runs one statement per query, so migration files are split on semicolons.
+		MultiStatementEnabled: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migration driver instance: %w", err)
+	}
+
+	migrator, err := migrate.NewWithDatabaseInstance(source, migrationDriverName, driver)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migrator instance: %w", err)
+	}
+
+	return migrator, nil
+}
+
+func closeMigrator(migrator *migrate.Migrate) error {
+	sourceErr, databaseErr := migrator.Close()
+	if sourceErr != nil {
+		return fmt.Errorf("migration cleanup error (source): %w", sourceErr)
+	}
+
+	if databaseErr != nil {
+		return fmt.Errorf("migration cleanup error (database): %w", databaseErr)
+	}
+
+	return nil
+}
+
+// withMigrator opens the database, runs fn with a migrator and closes both.
+func withMigrator(dbURI, source string, cfg []*MigrationConfig, fn func(*migrate.Migrate) error) error {
+	migrationCfg := &MigrationConfig{}
+	if len(cfg) > 0 && cfg[0] != nil {
+		migrationCfg = cfg[0]
+	}
+
+	db, err := openAndPingDB(dbURI)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if closeErr := db.Close(); closeErr != nil {
+			log.Error().
+				Str("source", "gframework").
+				Err(closeErr).
+				Msg("The ClickHouse connection failed to close after migration attempt")
+		}
+	}()
+
+	migrator, err := createMigrator(db, source, migrationCfg)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(migrator); err != nil {
+		_ = closeMigrator(migrator)
+
+		return err
+	}
+
+	return closeMigrator(migrator)
+}
+
+func logMigrationResult(migrator *migrate.Migrate, err error, operation, source string) error {
+	switch {
+	case errors.Is(err, migrate.ErrNoChange):
+		log.Info().
+			Str("source", "gframework").
+			Str("operation", operation).
+			Msg("The ClickHouse migration completed with no changes to apply")
+	case err != nil:
+		log.Error().
+			Str("source", "gframework").
+			Err(err).
+			Str("migration_source", source).
+			Str("operation", operation).
+			Msg("The ClickHouse migration has failed")
+
+		return fmt.Errorf("failed to run migration %s: %w", operation, err)
+	default:
+		version, dirty, _ := migrator.Version()
+		log.Info().
+			Str("source", "gframework").
+			Uint("version", version).
+			Bool("dirty", dirty).
+			Str("operation", operation).
+			Msg("The ClickHouse migration has been completed successfully")
+	}
+
+	return nil
+}
+
+// MigrateUp applies all pending migrations of source, e.g. "file://migrations/clickhouse".
+func MigrateUp(dbURI, source string, cfg ...*MigrationConfig) error {
+	log.Info().
+		Str("source", "gframework").
+		Str("migration_source", source).
+		Msg("The ClickHouse migration is being started")
+
+	return withMigrator(dbURI, source, cfg, func(migrator *migrate.Migrate) error {
+		return logMigrationResult(migrator, migrator.Up(), "up", source)
+	})
+}
+
+func MigrateDown(dbURI, source string, cfg ...*MigrationConfig) error {
+	log.Info().
+		Str("source", "gframework").
+		Str("migration_source", source).
+		Msg("The ClickHouse migration rollback is being started")
+
+	return withMigrator(dbURI, source, cfg, func(migrator *migrate.Migrate) error {
+		return logMigrationResult(migrator, migrator.Down(), "down", source)
+	})
+}
+
+func MigrateSteps(dbURI, source string, steps int, cfg ...*MigrationConfig) error {
+	log.Info().
+		Str("source", "gframework").
+		Str("migration_source", source).
+		Int("steps", steps).
+		Msg("The ClickHouse migration steps are being applied")
+
+	return withMigrator(dbURI, source, cfg, func(migrator *migrate.Migrate) error {
+		return logMigrationResult(migrator, migrator.Steps(steps), "steps", source)
+	})
+}
+
+func GetMigrationVersion(dbURI, source string, cfg ...*MigrationConfig) (*MigrationVersion, error) {
+	var result *MigrationVersion
+
+	err := withMigrator(dbURI, source, cfg, func(migrator *migrate.Migrate) error {
+		version, dirty, err := migrator.Version()
+		if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+			return fmt.Errorf("failed to get migration version: %w", err)
+		}
+
+		result = &MigrationVersion{Version: version, Dirty: dirty}
+
+		return nil
+	})
+
+	return result, err
+}
+
+func ForceMigrationVersion(dbURI, source string, version int, cfg ...*MigrationConfig) error {
+	log.Warn().
+		Str("source", "gframework").
+		Str("migration_source", source).
+		Int("version", version).
+		Msg("Forcing ClickHouse migration version - use with caution")
+
+	return withMigrator(dbURI, source, cfg, func(migrator *migrate.Migrate) error {
+		if err := migrator.Force(version); err != nil {
+			return fmt.Errorf("failed to force migration version: %w", err)
+		}
+
+		return nil
+	})
+}
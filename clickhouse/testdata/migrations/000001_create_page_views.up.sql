@@ -0,0 +1,6 @@
+CREATE TABLE page_views (
+    ts DateTime64(3),
+    user_id String,
+    path String
+) ENGINE = MergeTree
+ORDER BY (ts, user_id);
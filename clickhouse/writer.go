@@ -0,0 +1,343 @@
+package clickhouse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultQueueSize       = 100000
+	defaultBatchSize       = 10000
+	defaultFlushInterval   = time.Second
+	defaultMaxAttempts     = 3
+	defaultInitialBackoff  = 500 * time.Millisecond
+	defaultMaxBackoff      = 10 * time.Second
+	defaultShutdownTimeout = 10 * time.Second
+)
+
+var (
+	ErrNilInserter    = errors.New("clickhouse: inserter is nil")
+	ErrQueueFull      = errors.New("clickhouse: batch writer queue is full")
+	ErrAlreadyRunning = errors.New("clickhouse: batch writer is already running")
+	ErrNotRunning     = errors.New("clickhouse: batch writer is not running")
+	ErrColumnCount    = errors.New("clickhouse: row does not match the columns")
+)
+
+// Inserter inserts a batch of rows. *ClickHouse implements it.
+type Inserter interface {
+	InsertBatch(ctx context.Context, table string, columns []string, rows [][]any) error
+}
+
+type BatchWriterOption func(*BatchWriter)
+
+// WithBatchSize inserts the buffered rows once size rows are pending. It defaults to 10000.
+func WithBatchSize(size int) BatchWriterOption {
+	return func(w *BatchWriter) {
+		if size > 0 {
+			w.batchSize = size
+		}
+	}
+}
+
+// WithFlushInterval inserts the buffered rows at least every interval. It defaults to one second.
+func WithFlushInterval(interval time.Duration) BatchWriterOption {
+	return func(w *BatchWriter) {
+		if interval > 0 {
+			w.flushInterval = interval
+		}
+	}
+}
+
+// WithQueueSize bounds the number of rows waiting to be buffered; Write returns ErrQueueFull beyond
+// it. It defaults to 100000.
+func WithQueueSize(size int) BatchWriterOption {
+	return func(w *BatchWriter) {
+		if size > 0 {
+			w.queueSize = size
+		}
+	}
+}
+
+// WithRetry retries failed inserts with exponential backoff before the batch is dropped.
+func WithRetry(maxAttempts int, initialBackoff, maxBackoff time.Duration) BatchWriterOption {
+	return func(w *BatchWriter) {
+		if maxAttempts > 0 {
+			w.maxAttempts = maxAttempts
+		}
+
+		if initialBackoff > 0 {
+			w.initialBackoff = initialBackoff
+		}
+
+		if maxBackoff >= w.initialBackoff {
+			w.maxBackoff = maxBackoff
+		}
+	}
+}
+
+// WithShutdownTimeout bounds how long Stop keeps inserting the buffered rows.
+func WithShutdownTimeout(timeout time.Duration) BatchWriterOption {
+	return func(w *BatchWriter) {
+		if timeout > 0 {
+			w.shutdownTimeout = timeout
+		}
+	}
+}
+
+// BatchWriter buffers rows of one table and inserts them in batches from a background worker, so that
+// callers never wait for ClickHouse. It implements runner.Service; rows are inserted once it is
+// started, and Stop inserts the remaining ones within the shutdown timeout.
+type BatchWriter struct {
+	inserter        Inserter
+	table           string
+	columns         []string
+	queueSize       int
+	batchSize       int
+	flushInterval   time.Duration
+	maxAttempts     int
+	initialBackoff  time.Duration
+	maxBackoff      time.Duration
+	shutdownTimeout time.Duration
+
+	queue    chan []any
+	flushReq chan chan error
+	running  atomic.Bool
+	mu       sync.Mutex
+	stop     chan struct{}
+	done     chan struct{}
+	dropped  atomic.Uint64
+	failed   atomic.Uint64
+}
+
+// NewBatchWriter creates a writer inserting rows with the values of columns, in order, into table.
+func NewBatchWriter(inserter Inserter, table string, columns []string, opts ...BatchWriterOption) (*BatchWriter, error) {
+	if inserter == nil {
+		return nil, ErrNilInserter
+	}
+
+	if table == "" {
+		return nil, ErrInvalidTable
+	}
+
+	if len(columns) == 0 {
+		return nil, ErrInvalidColumn
+	}
+
+	writer := &BatchWriter{ //nolint:exhaustruct
+		inserter:        inserter,
+		table:           table,
+		columns:         append([]string(nil), columns...),
+		queueSize:       defaultQueueSize,
+		batchSize:       defaultBatchSize,
+		flushInterval:   defaultFlushInterval,
+		maxAttempts:     defaultMaxAttempts,
+		initialBackoff:  defaultInitialBackoff,
+		maxBackoff:      defaultMaxBackoff,
+		shutdownTimeout: defaultShutdownTimeout,
+		flushReq:        make(chan chan error),
+	}
+
+	for _, opt := range opts {
+		opt(writer)
+	}
+
+	writer.queue = make(chan []any, writer.queueSize)
+
+	return writer, nil
+}
+
+// Write queues a row without blocking. It returns ErrQueueFull when the row is dropped.
+func (w *BatchWriter) Write(values ...any) error {
+	if len(values) != len(w.columns) {
+		return fmt.Errorf("%w: got %d values for %d columns", ErrColumnCount, len(values), len(w.columns))
+	}
+
+	select {
+	case w.queue <- values:
+		return nil
+	default:
+		w.dropped.Add(1)
+
+		return ErrQueueFull
+	}
+}
+
+// Flush inserts the rows written so far and returns the insert error, if any.
+func (w *BatchWriter) Flush(ctx context.Context) error {
+	w.mu.Lock()
+	running, done := w.running.Load(), w.done
+	w.mu.Unlock()
+
+	if !running {
+		return ErrNotRunning
+	}
+
+	reply := make(chan error, 1)
+
+	select {
+	case w.flushReq <- reply:
+	case <-done:
+		return ErrNotRunning
+	case <-ctx.Done():
+		return fmt.Errorf("clickhouse: flush: %w", ctx.Err())
+	}
+
+	select {
+	case err := <-reply:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("clickhouse: flush: %w", ctx.Err())
+	}
+}
+
+// Dropped returns the number of rows discarded because the queue was full.
+func (w *BatchWriter) Dropped() uint64 {
+	return w.dropped.Load()
+}
+
+// Failed returns the number of rows that could not be inserted after all attempts.
+func (w *BatchWriter) Failed() uint64 {
+	return w.failed.Load()
+}
+
+func (w *BatchWriter) Start(ctx context.Context) error {
+	if !w.running.CompareAndSwap(false, true) {
+		return ErrAlreadyRunning
+	}
+
+	w.mu.Lock()
+	w.stop = make(chan struct{})
+	w.done = make(chan struct{})
+	w.mu.Unlock()
+
+	go w.run(ctx, w.stop, w.done)
+
+	return nil
+}
+
+func (w *BatchWriter) Stop() error {
+	if !w.running.CompareAndSwap(true, false) {
+		return nil
+	}
+
+	w.mu.Lock()
+	close(w.stop)
+	done := w.done
+	w.mu.Unlock()
+
+	<-done
+
+	if dropped := w.Dropped(); dropped > 0 {
+		log.Warn().Str("source", "gframework").Str("table", w.table).Uint64("dropped", dropped).Msg("Rows were dropped")
+	}
+
+	return nil
+}
+
+func (w *BatchWriter) Name() string {
+	return "clickhouse-writer"
+}
+
+func (w *BatchWriter) run(ctx context.Context, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(w.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([][]any, 0, w.batchSize)
+
+	for {
+		select {
+		case row := <-w.queue:
+			batch = append(batch, row)
+			if len(batch) >= w.batchSize {
+				batch, _ = w.flush(ctx, batch)
+			}
+		case <-ticker.C:
+			batch, _ = w.flush(ctx, batch)
+		case reply := <-w.flushReq:
+			var err error
+
+			batch, err = w.flush(ctx, w.drain(batch))
+			reply <- err
+		case <-ctx.Done():
+			w.shutdown(ctx, batch)
+
+			return
+		case <-stop:
+			w.shutdown(ctx, batch)
+
+			return
+		}
+	}
+}
+
+// drain moves the queued rows into batch.
+func (w *BatchWriter) drain(batch [][]any) [][]any {
+	for {
+		select {
+		case row := <-w.queue:
+			batch = append(batch, row)
+		default:
+			return batch
+		}
+	}
+}
+
+func (w *BatchWriter) shutdown(ctx context.Context, batch [][]any) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), w.shutdownTimeout)
+	defer cancel()
+
+	_, _ = w.flush(ctx, w.drain(batch))
+}
+
+// flush inserts batch in chunks of the batch size and returns it emptied.
+func (w *BatchWriter) flush(ctx context.Context, batch [][]any) ([][]any, error) {
+	var errs []error
+
+	for start := 0; start < len(batch); start += w.batchSize {
+		chunk := batch[start:min(start+w.batchSize, len(batch))]
+
+		if err := w.insert(ctx, chunk); err != nil {
+			w.failed.Add(uint64(len(chunk)))
+
+			log.Error().
+				Str("source", "gframework").
+				Err(err).
+				Str("table", w.table).
+				Int("rows", len(chunk)).
+				Msg("Failed to insert rows")
+
+			errs = append(errs, err)
+		}
+	}
+
+	clear(batch)
+
+	return batch[:0], errors.Join(errs...)
+}
+
+func (w *BatchWriter) insert(ctx context.Context, rows [][]any) error {
+	backoff := w.initialBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := w.inserter.InsertBatch(ctx, w.table, w.columns, rows)
+		if err == nil || attempt >= w.maxAttempts {
+			return err
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", err, ctx.Err())
+		}
+
+		backoff = min(backoff*2, w.maxBackoff)
+	}
+}
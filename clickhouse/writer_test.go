@@ -0,0 +1,207 @@
+//nolint:exhaustruct
+package clickhouse_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/clickhouse"
+	"github.com/stretchr/testify/require"
+)
+
+var errServerDown = errors.New("server down")
+
+type recordingInserter struct {
+	mu       sync.Mutex
+	failures int
+	calls    int
+	batches  [][][]any
+}
+
+func (i *recordingInserter) InsertBatch(_ context.Context, _ string, _ []string, rows [][]any) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.calls++
+
+	if i.failures > 0 {
+		i.failures--
+
+		return errServerDown
+	}
+
+	i.batches = append(i.batches, append([][]any(nil), rows...))
+
+	return nil
+}
+
+func (i *recordingInserter) snapshot() (int, []int) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	sizes := make([]int, 0, len(i.batches))
+	for _, batch := range i.batches {
+		sizes = append(sizes, len(batch))
+	}
+
+	return i.calls, sizes
+}
+
+func newWriter(t *testing.T, inserter clickhouse.Inserter, opts ...clickhouse.BatchWriterOption) *clickhouse.BatchWriter {
+	t.Helper()
+
+	writer, err := clickhouse.NewBatchWriter(inserter, "page_views", []string{"ts", "user_id", "path"}, opts...)
+	require.NoError(t, err)
+
+	return writer
+}
+
+func TestNewBatchWriter_InvalidArguments(t *testing.T) {
+	t.Parallel()
+
+	inserter := &recordingInserter{}
+
+	_, err := clickhouse.NewBatchWriter(nil, "page_views", []string{"ts"})
+	require.ErrorIs(t, err, clickhouse.ErrNilInserter)
+
+	_, err = clickhouse.NewBatchWriter(inserter, "", []string{"ts"})
+	require.ErrorIs(t, err, clickhouse.ErrInvalidTable)
+
+	_, err = clickhouse.NewBatchWriter(inserter, "page_views", nil)
+	require.ErrorIs(t, err, clickhouse.ErrInvalidColumn)
+}
+
+func TestBatchWriter_FlushesFullBatches(t *testing.T) {
+	t.Parallel()
+
+	inserter := &recordingInserter{}
+	writer := newWriter(t, inserter, clickhouse.WithBatchSize(2), clickhouse.WithFlushInterval(time.Hour))
+
+	require.NoError(t, writer.Start(t.Context()))
+
+	for range 5 {
+		require.NoError(t, writer.Write(time.Now(), "user-1", "/pricing"))
+	}
+
+	require.Eventually(t, func() bool {
+		_, sizes := inserter.snapshot()
+
+		return len(sizes) == 2
+	}, time.Second, 5*time.Millisecond)
+
+	require.NoError(t, writer.Stop())
+
+	_, sizes := inserter.snapshot()
+	require.Equal(t, []int{2, 2, 1}, sizes)
+}
+
+func TestBatchWriter_FlushesOnInterval(t *testing.T) {
+	t.Parallel()
+
+	inserter := &recordingInserter{}
+	writer := newWriter(t, inserter, clickhouse.WithFlushInterval(10*time.Millisecond))
+
+	require.NoError(t, writer.Start(t.Context()))
+	t.Cleanup(func() { _ = writer.Stop() })
+
+	require.NoError(t, writer.Write(time.Now(), "user-1", "/pricing"))
+
+	require.Eventually(t, func() bool {
+		_, sizes := inserter.snapshot()
+
+		return len(sizes) == 1
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestBatchWriter_Flush(t *testing.T) {
+	t.Parallel()
+
+	inserter := &recordingInserter{}
+	writer := newWriter(t, inserter, clickhouse.WithFlushInterval(time.Hour))
+
+	require.ErrorIs(t, writer.Flush(t.Context()), clickhouse.ErrNotRunning)
+
+	require.NoError(t, writer.Start(t.Context()))
+	t.Cleanup(func() { _ = writer.Stop() })
+
+	require.NoError(t, writer.Write(time.Now(), "user-1", "/pricing"))
+	require.NoError(t, writer.Write(time.Now(), "user-2", "/docs"))
+	require.NoError(t, writer.Flush(t.Context()))
+
+	_, sizes := inserter.snapshot()
+	require.Equal(t, []int{2}, sizes)
+}
+
+func TestBatchWriter_RetriesFailedInserts(t *testing.T) {
+	t.Parallel()
+
+	inserter := &recordingInserter{failures: 2}
+	writer := newWriter(t, inserter,
+		clickhouse.WithFlushInterval(time.Hour),
+		clickhouse.WithRetry(3, time.Millisecond, time.Millisecond),
+	)
+
+	require.NoError(t, writer.Start(t.Context()))
+	t.Cleanup(func() { _ = writer.Stop() })
+
+	require.NoError(t, writer.Write(time.Now(), "user-1", "/pricing"))
+	require.NoError(t, writer.Flush(t.Context()))
+
+	calls, sizes := inserter.snapshot()
+	require.Equal(t, 3, calls)
+	require.Equal(t, []int{1}, sizes)
+	require.Zero(t, writer.Failed())
+}
+
+func TestBatchWriter_CountsFailedRows(t *testing.T) {
+	t.Parallel()
+
+	inserter := &recordingInserter{failures: 2}
+	writer := newWriter(t, inserter,
+		clickhouse.WithFlushInterval(time.Hour),
+		clickhouse.WithRetry(2, time.Millisecond, time.Millisecond),
+	)
+
+	require.NoError(t, writer.Start(t.Context()))
+	t.Cleanup(func() { _ = writer.Stop() })
+
+	require.NoError(t, writer.Write(time.Now(), "user-1", "/pricing"))
+	require.NoError(t, writer.Write(time.Now(), "user-2", "/docs"))
+	require.ErrorIs(t, writer.Flush(t.Context()), errServerDown)
+	require.Equal(t, uint64(2), writer.Failed())
+}
+
+func TestBatchWriter_StopInsertsBufferedRows(t *testing.T) {
+	t.Parallel()
+
+	inserter := &recordingInserter{}
+	writer := newWriter(t, inserter, clickhouse.WithFlushInterval(time.Hour))
+
+	require.NoError(t, writer.Start(t.Context()))
+	require.ErrorIs(t, writer.Start(t.Context()), clickhouse.ErrAlreadyRunning)
+
+	for range 3 {
+		require.NoError(t, writer.Write(time.Now(), "user-1", "/pricing"))
+	}
+
+	require.NoError(t, writer.Stop())
+	require.NoError(t, writer.Stop())
+
+	_, sizes := inserter.snapshot()
+	require.Equal(t, []int{3}, sizes)
+}
+
+func TestBatchWriter_Write(t *testing.T) {
+	t.Parallel()
+
+	writer := newWriter(t, &recordingInserter{}, clickhouse.WithQueueSize(1))
+
+	require.ErrorIs(t, writer.Write("too", "few"), clickhouse.ErrColumnCount)
+
+	require.NoError(t, writer.Write(time.Now(), "user-1", "/pricing"))
+	require.ErrorIs(t, writer.Write(time.Now(), "user-2", "/docs"), clickhouse.ErrQueueFull)
+	require.Equal(t, uint64(1), writer.Dropped())
+}
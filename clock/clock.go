@@ -0,0 +1,95 @@
+// Package clock abstracts time so that code waiting on timers, tickers and deadlines can be tested
+// without sleeping.
+//
+// Framework packages that depend on time accept a Clock through a WithClock option and default to Real.
+// Tests pass testutil.NewFakeClock and move time forward explicitly:
+//
+//	clk := testutil.NewFakeClock(t)
+//	pool := workerpool.New(executor, workerpool.WithClock(clk))
+//	clk.Advance(time.Minute)
+package clock
+
+import "time"
+
+// Clock is the subset of the time package used by the framework.
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	Until(t time.Time) time.Duration
+	After(d time.Duration) <-chan time.Time
+	Sleep(d time.Duration)
+	NewTimer(d time.Duration) Timer
+	NewTicker(d time.Duration) Ticker
+}
+
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+	Reset(d time.Duration)
+}
+
+type realClock struct{}
+
+// Real returns the Clock backed by the time package.
+func Real() Clock { //nolint:ireturn
+	return realClock{}
+}
+
+// OrReal returns c, or Real when c is nil, for optional Clock fields in configs.
+func OrReal(c Clock) Clock { //nolint:ireturn
+	if c == nil {
+		return realClock{}
+	}
+
+	return c
+}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+func (realClock) Until(t time.Time) time.Duration {
+	return time.Until(t)
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+func (realClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+func (realClock) NewTimer(d time.Duration) Timer { //nolint:ireturn
+	return realTimer{Timer: time.NewTimer(d)}
+}
+
+func (realClock) NewTicker(d time.Duration) Ticker { //nolint:ireturn
+	return realTicker{Ticker: time.NewTicker(d)}
+}
+
+type realTimer struct {
+	*time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+type realTicker struct {
+	*time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
@@ -0,0 +1,187 @@
+// Package config loads a service configuration into a struct from defaults, YAML or JSON files, .env
+// files, environment variables and command-line flags, resolves secret references and validates the
+// result.
+//
+// Fields are described with struct tags:
+//
+//	type Config struct {
+//	    LogLevel   string        `env:"LOG_LEVEL"  envDefault:"info" flag:"log-level" validate:"oneof=debug info warn error"`
+//	    HTTPPort   int           `env:"HTTP_PORT"  envDefault:"8080" yaml:"http_port" validate:"min=1,max=65535"`
+//	    DBPassword string        `env:"DB_PASSWORD" secret:"true"    validate:"required"`
+//	    Timeout    time.Duration `env:"TIMEOUT"    envDefault:"30s"`
+//	    Valkey     ValkeyConfig  `envPrefix:"VALKEY_" yaml:"valkey"`
+//	}
+//
+//	var cfg Config
+//	err := config.Load(ctx, &cfg,
+//	    config.WithFile("config.yaml"),
+//	    config.WithDotEnv(".env"),
+//	    config.WithFlags(flag.CommandLine, os.Args[1:]),
+//	)
+//
+// Later sources override earlier ones: envDefault, files in the order given, .env files, the
+// environment, then flags that were set explicitly. File keys are the yaml tag, else the json tag,
+// else the field name, matched case-insensitively; nested structs are nested objects. The env and
+// envDefault tags follow github.com/caarlos0/env, so existing config structs load unchanged; slices are
+// comma-separated.
+//
+// A string value of the form ${scheme:reference} is replaced by the secret it references, e.g.
+// ${file:/run/secrets/db_password} or ${env:DB_PASSWORD_V2}. Other schemes are added with
+// WithSecretResolver.
+//
+// Fields tagged secret:"true", and fields whose name contains one of logutil.DefaultRedactFields, are
+// masked by Redacted, which a config type can use as its String method:
+//
+//	func (c Config) String() string { return config.Redacted(c) }
+package config
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+
+	"github.com/andyle182810/gframework/validator"
+)
+
+var (
+	ErrInvalidTarget       = errors.New("config: target must be a non-nil pointer to a struct")
+	ErrUnsupportedFile     = errors.New("config: unsupported file type, expected .yaml, .yml or .json")
+	ErrUnsupportedType     = errors.New("config: unsupported field type")
+	ErrInvalidValue        = errors.New("config: invalid value")
+	ErrUnknownSecretScheme = errors.New("config: unknown secret scheme")
+)
+
+type options struct {
+	files     []string
+	dotEnv    []string
+	envPrefix string
+	lookupEnv func(string) (string, bool)
+	flagSet   *flag.FlagSet
+	flagArgs  []string
+	resolvers map[string]SecretResolver
+	validator *validator.Validator
+}
+
+type Option func(*options)
+
+// WithFile reads a YAML or JSON file, chosen by its extension. The file must exist.
+func WithFile(path string) Option {
+	return func(o *options) {
+		o.files = append(o.files, path)
+	}
+}
+
+// WithDotEnv reads KEY=VALUE lines from .env files, which are skipped when they do not exist. Variables
+// set in the environment take precedence.
+func WithDotEnv(paths ...string) Option {
+	return func(o *options) {
+		o.dotEnv = append(o.dotEnv, paths...)
+	}
+}
+
+// WithEnvPrefix prepends prefix to every env name, e.g. "ORDERS_".
+func WithEnvPrefix(prefix string) Option {
+	return func(o *options) {
+		o.envPrefix = prefix
+	}
+}
+
+// WithLookupEnv replaces os.LookupEnv, e.g. to load from a map in tests.
+func WithLookupEnv(lookup func(string) (string, bool)) Option {
+	return func(o *options) {
+		o.lookupEnv = lookup
+	}
+}
+
+// WithFlags defines a flag on fs for every field with a flag tag, parses args and applies the flags
+// that were set. The usage tag becomes the flag's usage text.
+func WithFlags(fs *flag.FlagSet, args []string) Option {
+	return func(o *options) {
+		o.flagSet = fs
+		o.flagArgs = args
+	}
+}
+
+// WithSecretResolver resolves ${scheme:reference} values with resolver, replacing any resolver already
+// registered for scheme.
+func WithSecretResolver(scheme string, resolver SecretResolver) Option {
+	return func(o *options) {
+		o.resolvers[scheme] = resolver
+	}
+}
+
+// WithValidator replaces validator.DefaultRestValidator, e.g. to use custom validation tags.
+func WithValidator(v *validator.Validator) Option {
+	return func(o *options) {
+		o.validator = v
+	}
+}
+
+// Load fills cfg, a pointer to a struct, from the configured sources and validates it.
+func Load(ctx context.Context, cfg any, opts ...Option) error {
+	target := reflect.ValueOf(cfg)
+	if target.Kind() != reflect.Pointer || target.IsNil() || target.Elem().Kind() != reflect.Struct {
+		return ErrInvalidTarget
+	}
+
+	o := defaultOptions()
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if _, ok := o.resolvers["env"]; !ok {
+		o.resolvers["env"] = EnvSecretResolver(o.lookupEnv)
+	}
+
+	fields, err := collectFields(target.Elem(), o.envPrefix)
+	if err != nil {
+		return err
+	}
+
+	if err := applyDefaults(fields); err != nil {
+		return err
+	}
+
+	for _, path := range o.files {
+		if err := applyFile(target.Elem(), path); err != nil {
+			return err
+		}
+	}
+
+	if err := applyEnv(fields, &o); err != nil {
+		return err
+	}
+
+	if err := applyFlags(fields, &o); err != nil {
+		return err
+	}
+
+	if err := resolveSecrets(ctx, fields, o.resolvers); err != nil {
+		return err
+	}
+
+	if err := o.validator.Validate(cfg); err != nil {
+		return fmt.Errorf("config: validation failed: %w", err)
+	}
+
+	return nil
+}
+
+func defaultOptions() options {
+	return options{
+		files:     nil,
+		dotEnv:    nil,
+		envPrefix: "",
+		lookupEnv: os.LookupEnv,
+		flagSet:   nil,
+		flagArgs:  nil,
+		resolvers: map[string]SecretResolver{
+			"file": FileSecretResolver(),
+		},
+		validator: validator.DefaultRestValidator(),
+	}
+}
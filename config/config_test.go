@@ -0,0 +1,260 @@
+package config_test
+
+import (
+	"context"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/config"
+	"github.com/andyle182810/gframework/logutil"
+	"github.com/andyle182810/gframework/validator"
+	"github.com/stretchr/testify/require"
+)
+
+type valkeyConfig struct {
+	Host     string `env:"HOST"     envDefault:"localhost" yaml:"host"`
+	Port     int    `env:"PORT"     envDefault:"6379"      yaml:"port"`
+	Password string `env:"PASSWORD" yaml:"password"`
+}
+
+type testConfig struct {
+	LogLevel   string        `env:"LOG_LEVEL"    envDefault:"info" flag:"log-level" validate:"oneof=debug info warn error"`
+	HTTPPort   int           `env:"HTTP_PORT"    envDefault:"8080" yaml:"http_port"  validate:"min=1,max=65535"`
+	Timeout    time.Duration `env:"TIMEOUT"      envDefault:"30s"  yaml:"timeout"`
+	Origins    []string      `env:"ORIGINS"      envDefault:"*"    yaml:"origins"`
+	EnableCORS bool          `env:"ENABLE_CORS"  flag:"cors"       usage:"enable CORS"`
+	DSN        string        `env:"DSN"          secret:"true"`
+	Valkey     valkeyConfig  `envPrefix:"VALKEY_" yaml:"valkey"`
+}
+
+func lookupMap(values map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := values[key]
+
+		return value, ok
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	t.Parallel()
+
+	var cfg testConfig
+
+	require.NoError(t, config.Load(t.Context(), &cfg, config.WithLookupEnv(lookupMap(nil))))
+
+	require.Equal(t, "info", cfg.LogLevel)
+	require.Equal(t, 8080, cfg.HTTPPort)
+	require.Equal(t, 30*time.Second, cfg.Timeout)
+	require.Equal(t, []string{"*"}, cfg.Origins)
+	require.Equal(t, "localhost", cfg.Valkey.Host)
+	require.Equal(t, 6379, cfg.Valkey.Port)
+}
+
+func TestLoad_SourcePrecedence(t *testing.T) {
+	t.Parallel()
+
+	yamlFile := writeFile(t, "config.yaml", `
+http_port: 9000
+timeout: 1m
+origins: [https://a.example, https://b.example]
+valkey:
+  host: valkey.internal
+  port: 6380
+`)
+	jsonFile := writeFile(t, "override.json", `{"http_port": 9100}`)
+	dotEnv := writeFile(t, ".env", `
+# local overrides
+export VALKEY_PORT=6390
+LOG_LEVEL="warn"
+`)
+
+	env := map[string]string{"LOG_LEVEL": "debug"}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+
+	var cfg testConfig
+
+	err := config.Load(t.Context(), &cfg,
+		config.WithFile(yamlFile),
+		config.WithFile(jsonFile),
+		config.WithDotEnv(dotEnv, filepath.Join(t.TempDir(), "missing.env")),
+		config.WithLookupEnv(lookupMap(env)),
+		config.WithFlags(fs, []string{"-cors"}),
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, 9100, cfg.HTTPPort, "later files override earlier ones")
+	require.Equal(t, time.Minute, cfg.Timeout)
+	require.Equal(t, []string{"https://a.example", "https://b.example"}, cfg.Origins)
+	require.Equal(t, "valkey.internal", cfg.Valkey.Host)
+	require.Equal(t, 6390, cfg.Valkey.Port, ".env overrides files")
+	require.Equal(t, "debug", cfg.LogLevel, "the environment overrides .env")
+	require.True(t, cfg.EnableCORS, "flags override the environment")
+}
+
+func TestLoad_FlagOverridesEnv(t *testing.T) {
+	t.Parallel()
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+
+	var cfg testConfig
+
+	err := config.Load(t.Context(), &cfg,
+		config.WithLookupEnv(lookupMap(map[string]string{"LOG_LEVEL": "debug"})),
+		config.WithFlags(fs, []string{"-log-level", "error"}),
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, "error", cfg.LogLevel)
+	require.Equal(t, "enable CORS", fs.Lookup("cors").Usage)
+}
+
+func TestLoad_EnvPrefix(t *testing.T) {
+	t.Parallel()
+
+	var cfg testConfig
+
+	err := config.Load(t.Context(), &cfg,
+		config.WithEnvPrefix("ORDERS_"),
+		config.WithLookupEnv(lookupMap(map[string]string{"ORDERS_VALKEY_HOST": "cache", "HTTP_PORT": "1"})),
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, "cache", cfg.Valkey.Host)
+	require.Equal(t, 8080, cfg.HTTPPort)
+}
+
+func TestLoad_ResolvesSecrets(t *testing.T) {
+	t.Parallel()
+
+	secretFile := writeFile(t, "db_password", "s3cret\n")
+	env := map[string]string{
+		"VALKEY_PASSWORD": "${file:" + secretFile + "}",
+		"DSN":             "${vault:database/creds}",
+	}
+
+	var cfg testConfig
+
+	err := config.Load(t.Context(), &cfg,
+		config.WithLookupEnv(lookupMap(env)),
+		config.WithSecretResolver("vault", config.SecretResolverFunc(
+			func(_ context.Context, reference string) (string, error) {
+				return "resolved:" + reference, nil
+			},
+		)),
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, "s3cret", cfg.Valkey.Password)
+	require.Equal(t, "resolved:database/creds", cfg.DSN)
+}
+
+func TestLoad_UnknownSecretScheme(t *testing.T) {
+	t.Parallel()
+
+	var cfg testConfig
+
+	err := config.Load(t.Context(), &cfg, config.WithLookupEnv(lookupMap(map[string]string{"DSN": "${aws:db}"})))
+
+	require.ErrorIs(t, err, config.ErrUnknownSecretScheme)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		target      any
+		env         map[string]string
+		opts        []config.Option
+		expectError error
+	}{
+		{
+			name:        "nil target",
+			target:      nil,
+			expectError: config.ErrInvalidTarget,
+		},
+		{
+			name:        "non-pointer target",
+			target:      testConfig{},
+			expectError: config.ErrInvalidTarget,
+		},
+		{
+			name:        "invalid env value",
+			target:      &testConfig{},
+			env:         map[string]string{"HTTP_PORT": "eighty"},
+			expectError: config.ErrInvalidValue,
+		},
+		{
+			name:        "validation failure",
+			target:      &testConfig{},
+			env:         map[string]string{"LOG_LEVEL": "verbose"},
+			expectError: validator.ValidationErrors{},
+		},
+		{
+			name:        "unsupported file",
+			target:      &testConfig{},
+			opts:        []config.Option{config.WithFile("config.toml")},
+			expectError: config.ErrUnsupportedFile,
+		},
+		{
+			name: "unsupported field type",
+			target: &struct {
+				Limits map[string]int `env:"LIMITS"`
+			}{},
+			expectError: config.ErrUnsupportedType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := append([]config.Option{config.WithLookupEnv(lookupMap(tt.env))}, tt.opts...)
+			err := config.Load(t.Context(), tt.target, opts...)
+
+			if _, ok := tt.expectError.(validator.ValidationErrors); ok {
+				var validationErrs validator.ValidationErrors
+				require.ErrorAs(t, err, &validationErrs)
+
+				return
+			}
+
+			require.ErrorIs(t, err, tt.expectError)
+		})
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	t.Parallel()
+
+	cfg := testConfig{
+		LogLevel: "info",
+		HTTPPort: 8080,
+		Origins:  []string{"a", "b"},
+		DSN:      "postgres://user:pass@db/app",
+		Valkey:   valkeyConfig{Host: "cache", Port: 6379, Password: ""},
+	}
+
+	got := config.Redacted(&cfg)
+
+	require.Equal(t,
+		"{LogLevel:info HTTPPort:8080 Timeout:0s Origins:a,b EnableCORS:false DSN:"+logutil.DefaultRedactMask+
+			" Valkey:{Host:cache Port:6379 Password:}}",
+		got,
+	)
+
+	cfg.Valkey.Password = "hunter2"
+	require.NotContains(t, config.Redacted(cfg), "hunter2")
+}
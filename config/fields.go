@@ -0,0 +1,232 @@
+package config
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	tagEnv        = "env"
+	tagEnvDefault = "envDefault"
+	tagEnvPrefix  = "envPrefix"
+	tagFlag       = "flag"
+	tagUsage      = "usage"
+	tagSecret     = "secret"
+)
+
+//nolint:gochecknoglobals
+var (
+	durationType        = reflect.TypeFor[time.Duration]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// field is a leaf field of the config struct.
+type field struct {
+	value      reflect.Value
+	path       string // Go path, e.g. "Valkey.Host"
+	env        string // Env name including prefixes, empty without env tag
+	envDefault string
+	hasDefault bool
+	flag       string
+	usage      string
+}
+
+func collectFields(structValue reflect.Value, envPrefix string) ([]field, error) {
+	return appendFields(nil, structValue, "", envPrefix)
+}
+
+func appendFields(fields []field, structValue reflect.Value, pathPrefix, envPrefix string) ([]field, error) {
+	structType := structValue.Type()
+
+	for i := range structType.NumField() {
+		structField := structType.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+
+		value := structValue.Field(i)
+		path := pathPrefix + structField.Name
+
+		if isNested(structField.Type) {
+			var err error
+
+			fields, err = appendFields(fields, value, path+".", envPrefix+structField.Tag.Get(tagEnvPrefix))
+			if err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		f := field{
+			value:      value,
+			path:       path,
+			env:        "",
+			envDefault: "",
+			hasDefault: false,
+			flag:       structField.Tag.Get(tagFlag),
+			usage:      structField.Tag.Get(tagUsage),
+		}
+
+		if env := structField.Tag.Get(tagEnv); env != "" && env != "-" {
+			f.env = envPrefix + strings.Split(env, ",")[0]
+		}
+
+		f.envDefault, f.hasDefault = structField.Tag.Lookup(tagEnvDefault)
+
+		if (f.env != "" || f.hasDefault || f.flag != "") && !supported(structField.Type) {
+			return nil, fmt.Errorf("%w: %s is a %s", ErrUnsupportedType, path, structField.Type)
+		}
+
+		fields = append(fields, f)
+	}
+
+	return fields, nil
+}
+
+// isNested reports whether t is a struct whose fields are configured individually.
+func isNested(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && !reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+func supported(t reflect.Type) bool {
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return true
+	}
+
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() != reflect.Slice && supported(t.Elem())
+	default:
+		return false
+	}
+}
+
+// setString parses raw into value. Slices are comma-separated.
+func setString(value reflect.Value, raw string) error {
+	if value.Kind() == reflect.Slice && !value.Addr().Type().Implements(textUnmarshalerType) {
+		parts := splitList(raw)
+		slice := reflect.MakeSlice(value.Type(), len(parts), len(parts))
+
+		for i, part := range parts {
+			if err := setScalar(slice.Index(i), part); err != nil {
+				return err
+			}
+		}
+
+		value.Set(slice)
+
+		return nil
+	}
+
+	return setScalar(value, raw)
+}
+
+func setScalar(value reflect.Value, raw string) error { //nolint:cyclop
+	if unmarshaler, ok := value.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		if err := unmarshaler.UnmarshalText([]byte(raw)); err != nil {
+			return fmt.Errorf("%w %q: %w", ErrInvalidValue, raw, err)
+		}
+
+		return nil
+	}
+
+	var err error
+
+	switch value.Kind() { //nolint:exhaustive
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Bool:
+		var parsed bool
+
+		parsed, err = strconv.ParseBool(raw)
+		value.SetBool(parsed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var parsed int64
+
+		if value.Type() == durationType {
+			var duration time.Duration
+
+			duration, err = time.ParseDuration(raw)
+			parsed = int64(duration)
+		} else {
+			parsed, err = strconv.ParseInt(raw, 10, value.Type().Bits())
+		}
+
+		value.SetInt(parsed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var parsed uint64
+
+		parsed, err = strconv.ParseUint(raw, 10, value.Type().Bits())
+		value.SetUint(parsed)
+	case reflect.Float32, reflect.Float64:
+		var parsed float64
+
+		parsed, err = strconv.ParseFloat(raw, value.Type().Bits())
+		value.SetFloat(parsed)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedType, value.Type())
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w %q: %w", ErrInvalidValue, raw, err)
+	}
+
+	return nil
+}
+
+func splitList(raw string) []string {
+	if strings.TrimSpace(raw) == "" {
+		return nil
+	}
+
+	parts := strings.Split(raw, ",")
+	for i, part := range parts {
+		parts[i] = strings.TrimSpace(part)
+	}
+
+	return parts
+}
+
+// formatValue renders value the way it is written in an env variable.
+func formatValue(value reflect.Value) string {
+	if value.Kind() == reflect.Slice {
+		parts := make([]string, value.Len())
+		for i := range value.Len() {
+			parts[i] = formatValue(value.Index(i))
+		}
+
+		return strings.Join(parts, ",")
+	}
+
+	if marshaler, ok := value.Interface().(encoding.TextMarshaler); ok {
+		if text, err := marshaler.MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+
+	return fmt.Sprint(value.Interface())
+}
+
+func applyDefaults(fields []field) error {
+	for _, f := range fields {
+		if !f.hasDefault {
+			continue
+		}
+
+		if err := setString(f.value, f.envDefault); err != nil {
+			return fmt.Errorf("config: default of %s: %w", f.path, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/andyle182810/gframework/logutil"
+)
+
+// Redacted renders cfg, a config struct or a pointer to one, like %+v but with secret fields masked
+// with logutil.DefaultRedactMask. A field is secret when it is tagged secret:"true" or its name or env
+// name contains one of logutil.DefaultRedactFields. Empty secrets are shown as empty, so a missing
+// secret can still be spotted.
+func Redacted(cfg any) string {
+	value := reflect.ValueOf(cfg)
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return "<nil>"
+		}
+
+		value = value.Elem()
+	}
+
+	if value.Kind() != reflect.Struct {
+		return formatValue(value)
+	}
+
+	var b strings.Builder
+
+	writeRedacted(&b, value)
+
+	return b.String()
+}
+
+func writeRedacted(b *strings.Builder, structValue reflect.Value) {
+	structType := structValue.Type()
+
+	b.WriteByte('{')
+
+	written := 0
+
+	for i := range structType.NumField() {
+		structField := structType.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+
+		if written > 0 {
+			b.WriteByte(' ')
+		}
+
+		written++
+
+		b.WriteString(structField.Name)
+		b.WriteByte(':')
+
+		value := structValue.Field(i)
+
+		switch {
+		case isNested(structField.Type):
+			writeRedacted(b, value)
+		case isSecret(structField) && !value.IsZero():
+			b.WriteString(logutil.DefaultRedactMask)
+		default:
+			b.WriteString(formatValue(value))
+		}
+	}
+
+	b.WriteByte('}')
+}
+
+func isSecret(structField reflect.StructField) bool {
+	if structField.Tag.Get(tagSecret) == "true" {
+		return true
+	}
+
+	names := []string{strings.ToLower(structField.Name), strings.ToLower(structField.Tag.Get(tagEnv))}
+
+	for _, pattern := range logutil.DefaultRedactFields() {
+		for _, name := range names {
+			if name != "" && strings.Contains(name, pattern) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
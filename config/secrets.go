@@ -0,0 +1,84 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+var ErrSecretNotFound = errors.New("config: secret not found")
+
+//nolint:gochecknoglobals
+var secretReferencePattern = regexp.MustCompile(`^\$\{([a-zA-Z][a-zA-Z0-9_.-]*):(.+)\}$`)
+
+// SecretResolver returns the secret a ${scheme:reference} value refers to, e.g. by reading it from a
+// secrets manager.
+type SecretResolver interface {
+	ResolveSecret(ctx context.Context, reference string) (string, error)
+}
+
+// SecretResolverFunc adapts a function to SecretResolver.
+type SecretResolverFunc func(ctx context.Context, reference string) (string, error)
+
+func (f SecretResolverFunc) ResolveSecret(ctx context.Context, reference string) (string, error) {
+	return f(ctx, reference)
+}
+
+// FileSecretResolver reads the secret from the file at reference, e.g. a Docker or Kubernetes secret
+// mount, without its trailing newline.
+func FileSecretResolver() SecretResolver { //nolint:ireturn
+	return SecretResolverFunc(func(_ context.Context, reference string) (string, error) {
+		data, err := os.ReadFile(reference) //nolint:gosec
+		if err != nil {
+			return "", fmt.Errorf("failed to read secret file: %w", err)
+		}
+
+		return strings.TrimRight(string(data), "\r\n"), nil
+	})
+}
+
+// EnvSecretResolver reads the secret from the environment variable named by reference, with lookup,
+// e.g. os.LookupEnv.
+func EnvSecretResolver(lookup func(string) (string, bool)) SecretResolver { //nolint:ireturn
+	return SecretResolverFunc(func(_ context.Context, reference string) (string, error) {
+		value, ok := lookup(reference)
+		if !ok {
+			return "", fmt.Errorf("%w: environment variable %s is not set", ErrSecretNotFound, reference)
+		}
+
+		return value, nil
+	})
+}
+
+func resolveSecrets(ctx context.Context, fields []field, resolvers map[string]SecretResolver) error {
+	for _, f := range fields {
+		if f.value.Kind() != reflect.String {
+			continue
+		}
+
+		match := secretReferencePattern.FindStringSubmatch(f.value.String())
+		if match == nil {
+			continue
+		}
+
+		scheme, reference := match[1], match[2]
+
+		resolver, ok := resolvers[scheme]
+		if !ok {
+			return fmt.Errorf("%w %q in %s", ErrUnknownSecretScheme, scheme, f.path)
+		}
+
+		secret, err := resolver.ResolveSecret(ctx, reference)
+		if err != nil {
+			return fmt.Errorf("config: failed to resolve secret of %s: %w", f.path, err)
+		}
+
+		f.value.SetString(secret)
+	}
+
+	return nil
+}
@@ -0,0 +1,250 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+func applyFile(structValue reflect.Value, path string) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedFile, path)
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return fmt.Errorf("config: failed to read %s: %w", path, err)
+	}
+
+	// JSON is a subset of YAML, so one decoder reads both.
+	var document map[string]any
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return fmt.Errorf("config: failed to parse %s: %w", path, err)
+	}
+
+	if err := applyMap(structValue, document, ""); err != nil {
+		return fmt.Errorf("config: %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func applyMap(structValue reflect.Value, document map[string]any, pathPrefix string) error {
+	structType := structValue.Type()
+
+	for i := range structType.NumField() {
+		structField := structType.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+
+		raw, ok := lookupKey(document, fileKeys(structField))
+		if !ok || raw == nil {
+			continue
+		}
+
+		value := structValue.Field(i)
+		path := pathPrefix + structField.Name
+
+		if isNested(structField.Type) {
+			nested, ok := raw.(map[string]any)
+			if !ok {
+				return fmt.Errorf("%w: %s must be an object", ErrInvalidValue, path)
+			}
+
+			if err := applyMap(value, nested, path+"."); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if !supported(structField.Type) {
+			return fmt.Errorf("%w: %s is a %s", ErrUnsupportedType, path, structField.Type)
+		}
+
+		if err := setFileValue(value, raw); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+func setFileValue(value reflect.Value, raw any) error {
+	items, isList := raw.([]any)
+	if !isList || value.Kind() != reflect.Slice {
+		return setString(value, fmt.Sprint(raw))
+	}
+
+	slice := reflect.MakeSlice(value.Type(), len(items), len(items))
+
+	for i, item := range items {
+		if err := setScalar(slice.Index(i), fmt.Sprint(item)); err != nil {
+			return err
+		}
+	}
+
+	value.Set(slice)
+
+	return nil
+}
+
+// fileKeys returns the names a field may have in a file: its yaml tag, json tag and Go name.
+func fileKeys(structField reflect.StructField) []string {
+	keys := make([]string, 0, 3) //nolint:mnd
+
+	for _, tag := range []string{"yaml", "json"} {
+		if name := strings.Split(structField.Tag.Get(tag), ",")[0]; name != "" && name != "-" {
+			keys = append(keys, name)
+		}
+	}
+
+	return append(keys, structField.Name)
+}
+
+func lookupKey(document map[string]any, keys []string) (any, bool) {
+	for _, key := range keys {
+		if raw, ok := document[key]; ok {
+			return raw, true
+		}
+	}
+
+	for _, key := range keys {
+		for name, raw := range document {
+			if strings.EqualFold(name, key) {
+				return raw, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+func applyEnv(fields []field, o *options) error {
+	dotEnv, err := readDotEnv(o.dotEnv)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range fields {
+		if f.env == "" {
+			continue
+		}
+
+		raw, ok := o.lookupEnv(f.env)
+		if !ok {
+			raw, ok = dotEnv[f.env]
+		}
+
+		if !ok {
+			continue
+		}
+
+		if err := setString(f.value, raw); err != nil {
+			return fmt.Errorf("config: env %s: %w", f.env, err)
+		}
+	}
+
+	return nil
+}
+
+// readDotEnv reads KEY=VALUE lines; later files override earlier ones. Blank lines, comments and an
+// "export " prefix are ignored, and values may be single- or double-quoted.
+func readDotEnv(paths []string) (map[string]string, error) {
+	values := make(map[string]string)
+
+	for _, path := range paths {
+		file, err := os.Open(path) //nolint:gosec
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("config: failed to open %s: %w", path, err)
+		}
+
+		scanner := bufio.NewScanner(file)
+		for lineNumber := 1; scanner.Scan(); lineNumber++ {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+
+			key, value, found := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+			if !found {
+				_ = file.Close()
+
+				return nil, fmt.Errorf("%w: %s:%d is not KEY=VALUE", ErrInvalidValue, path, lineNumber)
+			}
+
+			values[strings.TrimSpace(key)] = unquote(strings.TrimSpace(value))
+		}
+
+		err = scanner.Err()
+		_ = file.Close()
+
+		if err != nil {
+			return nil, fmt.Errorf("config: failed to read %s: %w", path, err)
+		}
+	}
+
+	return values, nil
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+
+	return value
+}
+
+func applyFlags(fields []field, o *options) error {
+	if o.flagSet == nil {
+		return nil
+	}
+
+	for _, f := range fields {
+		if f.flag != "" {
+			o.flagSet.Var(fieldFlag{field: f}, f.flag, f.usage)
+		}
+	}
+
+	if err := o.flagSet.Parse(o.flagArgs); err != nil {
+		return fmt.Errorf("config: failed to parse flags: %w", err)
+	}
+
+	return nil
+}
+
+// fieldFlag is a flag.Value setting a config field, so only flags that are passed override it.
+type fieldFlag struct {
+	field field
+}
+
+func (f fieldFlag) String() string {
+	if !f.field.value.IsValid() {
+		return ""
+	}
+
+	return formatValue(f.field.value)
+}
+
+func (f fieldFlag) Set(raw string) error {
+	return setString(f.field.value, raw)
+}
+
+// IsBoolFlag lets bool fields be set with -name instead of -name=true.
+func (f fieldFlag) IsBoolFlag() bool {
+	return f.field.value.Kind() == reflect.Bool
+}
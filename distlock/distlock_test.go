@@ -29,6 +29,7 @@ func setupTestLocker(t *testing.T) *distlock.Locker {
 	valkeyClient, err := valkey.New(&valkey.Config{
 		Host: container.Host,
 		Port: port,
+		DB:   container.DB,
 	})
 	require.NoError(t, err)
 
@@ -0,0 +1,112 @@
+package distlock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bsm/redislock"
+)
+
+const defaultLeaderLockTTL = 15 * time.Second
+
+var ErrLockNotHeld = redislock.ErrLockNotHeld
+
+// LeaderLock is a lock on a single key that is kept by refreshing it, e.g. for
+// runner.WithLeaderElectedService. It expires after its TTL unless refreshed, so a crashed holder
+// releases it automatically. It satisfies runner.LeaderLock.
+type LeaderLock struct {
+	client *redislock.Client
+	key    string
+	ttl    time.Duration
+	mu     sync.Mutex
+	lock   *redislock.Lock
+}
+
+// NewLeaderLock creates a lock on key. A ttl <= 0 defaults to 15 seconds.
+func (l *Locker) NewLeaderLock(key string, ttl time.Duration) *LeaderLock {
+	if ttl <= 0 {
+		ttl = defaultLeaderLockTTL
+	}
+
+	return &LeaderLock{
+		client: l.client,
+		key:    key,
+		ttl:    ttl,
+		mu:     sync.Mutex{},
+		lock:   nil,
+	}
+}
+
+// TryAcquire takes the lock if it is free and reports whether this caller now holds it.
+func (l *LeaderLock) TryAcquire(ctx context.Context) (bool, error) {
+	lock, err := l.client.Obtain(ctx, l.key, l.ttl, nil)
+	if errors.Is(err, redislock.ErrNotObtained) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("distlock: failed to acquire lock %s: %w", l.key, err)
+	}
+
+	l.mu.Lock()
+	l.lock = lock
+	l.mu.Unlock()
+
+	return true, nil
+}
+
+// Refresh extends the lock by its TTL and reports whether this caller still holds it.
+func (l *LeaderLock) Refresh(ctx context.Context) (bool, error) {
+	l.mu.Lock()
+	lock := l.lock
+	l.mu.Unlock()
+
+	if lock == nil {
+		return false, nil
+	}
+
+	err := lock.Refresh(ctx, l.ttl, nil)
+	if errors.Is(err, redislock.ErrNotObtained) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("distlock: failed to refresh lock %s: %w", l.key, err)
+	}
+
+	return true, nil
+}
+
+// Release deletes the lock. It returns ErrLockNotHeld when the lock has expired, is held by another
+// caller or was never acquired.
+func (l *LeaderLock) Release(ctx context.Context) error {
+	l.mu.Lock()
+	lock := l.lock
+	l.lock = nil
+	l.mu.Unlock()
+
+	if lock == nil {
+		return ErrLockNotHeld
+	}
+
+	if err := lock.Release(ctx); err != nil {
+		if errors.Is(err, redislock.ErrLockNotHeld) {
+			return ErrLockNotHeld
+		}
+
+		return fmt.Errorf("distlock: failed to release lock %s: %w", l.key, err)
+	}
+
+	return nil
+}
+
+func (l *LeaderLock) Key() string {
+	return l.key
+}
+
+func (l *LeaderLock) TTL() time.Duration {
+	return l.ttl
+}
@@ -0,0 +1,64 @@
+package distlock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/distlock"
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeaderLock(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	client, server := testutil.NewFakeValkeyWithServer(t)
+	locker := distlock.New(client.Client)
+
+	first := locker.NewLeaderLock("lock:scheduler", time.Second)
+	second := locker.NewLeaderLock("lock:scheduler", time.Second)
+
+	acquired, err := first.TryAcquire(ctx)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	acquired, err = second.TryAcquire(ctx)
+	require.NoError(t, err)
+	require.False(t, acquired)
+
+	held, err := second.Refresh(ctx)
+	require.NoError(t, err)
+	require.False(t, held)
+
+	held, err = first.Refresh(ctx)
+	require.NoError(t, err)
+	require.True(t, held)
+
+	require.ErrorIs(t, second.Release(ctx), distlock.ErrLockNotHeld)
+	require.NoError(t, first.Release(ctx))
+
+	acquired, err = second.TryAcquire(ctx)
+	require.NoError(t, err)
+	require.True(t, acquired)
+
+	server.FastForward(2 * time.Second)
+
+	held, err = second.Refresh(ctx)
+	require.NoError(t, err)
+	require.False(t, held, "lock should expire after its TTL")
+
+	acquired, err = first.TryAcquire(ctx)
+	require.NoError(t, err)
+	require.True(t, acquired)
+}
+
+func TestNewLeaderLock_DefaultTTL(t *testing.T) {
+	t.Parallel()
+
+	locker := distlock.New(testutil.NewFakeValkey(t).Client)
+
+	lock := locker.NewLeaderLock("lock", 0)
+	require.Equal(t, "lock", lock.Key())
+	require.Equal(t, 15*time.Second, lock.TTL())
+}
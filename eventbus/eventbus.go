@@ -0,0 +1,155 @@
+// Package eventbus delivers domain events between services without losing or duplicating their
+// effects, using the transactional outbox and inbox patterns on Postgres.
+//
+// Producers add events to the outbox in the same transaction as the state change that caused them,
+// so an event is stored if and only if the change commits. A Relay, run as a runner service, publishes
+// stored events to any Publisher, e.g. redispub, kafkapub, natsjetstream, amqppub or snspub, retrying
+// with backoff:
+//
+//	outbox, err := eventbus.NewOutbox(pg)
+//
+//	err = postgres.BeginCtx(ctx, pg, func(ctx context.Context) error {
+//	    if err := orders.Create(ctx, order); err != nil {
+//	        return err
+//	    }
+//
+//	    event, err := eventbus.NewEvent("orders.created", order.ID, order)
+//	    if err != nil {
+//	        return err
+//	    }
+//
+//	    return outbox.Add(ctx, event)
+//	})
+//
+//	relay, err := eventbus.NewRelay(outbox, publisher)
+//	run.Add(relay)
+//
+// Delivery is at least once, so consumers deduplicate with an Inbox, which records the IDs of
+// processed events in the transaction of the handler's own writes:
+//
+//	inbox, err := eventbus.NewInbox(pg)
+//
+//	subscriber.Subscribe("orders.created", inbox.Handler("billing", func(ctx context.Context, event *eventbus.Event) error {
+//	    return invoices.CreateFor(ctx, event.Key)
+//	}))
+//
+// Events with the same key, e.g. the ID of an aggregate, are published in the order they were added.
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/andyle182810/gframework/postgres"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNilDB            = errors.New("eventbus: database is nil")
+	ErrNilOutbox        = errors.New("eventbus: outbox is nil")
+	ErrNilPublisher     = errors.New("eventbus: publisher is nil")
+	ErrEmptyTopic       = errors.New("eventbus: event topic is empty")
+	ErrEmptyConsumer    = errors.New("eventbus: consumer name is empty")
+	ErrInvalidPayload   = errors.New("eventbus: event payload is not valid JSON")
+	ErrInvalidEvent     = errors.New("eventbus: message is not a valid event")
+	ErrInvalidTableName = errors.New("eventbus: invalid table name")
+)
+
+// Publisher has the same method set as redispub.Publisher, kafkapub.Publisher, natsjetstream.Publisher,
+// amqppub.Publisher and snspub.Publisher, without Close, which stays with the owner of the publisher.
+type Publisher interface {
+	PublishToTopic(ctx context.Context, topic string, messageContents ...string) error
+}
+
+// DB is satisfied by *postgres.Postgres.
+type DB interface {
+	postgres.Conn
+	postgres.TxRunner
+}
+
+// Event is published as its JSON encoding, so consumers receive the ID and key along with the payload.
+type Event struct {
+	// ID identifies the event for deduplication. Add generates a UUIDv7 when it is empty.
+	ID    string `json:"id"`
+	Topic string `json:"topic"`
+	// Key orders events: events with the same key are published in the order they were added.
+	Key     string          `json:"key,omitempty"`
+	Payload json.RawMessage `json:"payload"`
+	// Headers carry metadata such as the trace context of the producer, which Add stores.
+	Headers    map[string]string `json:"headers,omitempty"`
+	OccurredAt time.Time         `json:"occurredAt"`
+}
+
+// NewEvent returns an event with payload encoded as JSON.
+func NewEvent(topic, key string, payload any) (Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, fmt.Errorf("%w: %w", ErrInvalidPayload, err) //nolint:exhaustruct
+	}
+
+	return Event{
+		ID:         "",
+		Topic:      topic,
+		Key:        key,
+		Payload:    data,
+		Headers:    nil,
+		OccurredAt: time.Now().UTC(),
+	}, nil
+}
+
+// Decode unmarshals the payload into v.
+func (e *Event) Decode(v any) error {
+	if err := json.Unmarshal(e.Payload, v); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidPayload, err)
+	}
+
+	return nil
+}
+
+// DecodeEvent parses a message published by a Relay.
+func DecodeEvent(data []byte) (*Event, error) {
+	var event Event
+
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEvent, err)
+	}
+
+	if event.ID == "" || event.Topic == "" {
+		return nil, fmt.Errorf("%w: missing id or topic", ErrInvalidEvent)
+	}
+
+	return &event, nil
+}
+
+// prepare validates the event and fills in the defaults of Add.
+func (e *Event) prepare() error {
+	if e.Topic == "" {
+		return ErrEmptyTopic
+	}
+
+	if len(e.Payload) == 0 {
+		e.Payload = json.RawMessage("null")
+	}
+
+	if !json.Valid(e.Payload) {
+		return ErrInvalidPayload
+	}
+
+	if e.ID == "" {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return fmt.Errorf("eventbus: failed to generate event id: %w", err)
+		}
+
+		e.ID = id.String()
+	}
+
+	if e.OccurredAt.IsZero() {
+		e.OccurredAt = time.Now().UTC()
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+//nolint:exhaustruct
+package eventbus_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/eventbus"
+	"github.com/andyle182810/gframework/postgres"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+type orderCreated struct {
+	OrderID string `json:"orderId"`
+	Total   int    `json:"total"`
+}
+
+func TestNewEvent_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	event, err := eventbus.NewEvent("orders.created", "order-7", orderCreated{OrderID: "order-7", Total: 42})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"orderId": "order-7", "total": 42}`, string(event.Payload))
+	require.False(t, event.OccurredAt.IsZero())
+
+	event.ID = "evt-1"
+
+	data, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	decoded, err := eventbus.DecodeEvent(data)
+	require.NoError(t, err)
+	require.Equal(t, "evt-1", decoded.ID)
+	require.Equal(t, "order-7", decoded.Key)
+
+	var payload orderCreated
+	require.NoError(t, decoded.Decode(&payload))
+	require.Equal(t, orderCreated{OrderID: "order-7", Total: 42}, payload)
+
+	_, err = eventbus.NewEvent("orders.created", "", func() {})
+	require.ErrorIs(t, err, eventbus.ErrInvalidPayload)
+}
+
+func TestDecodeEvent_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, data := range []string{`not json`, `{"topic": "orders.created"}`, `{"id": "evt-1"}`} {
+		_, err := eventbus.DecodeEvent([]byte(data))
+		require.ErrorIs(t, err, eventbus.ErrInvalidEvent, data)
+	}
+}
+
+func TestConstructors_Validate(t *testing.T) {
+	t.Parallel()
+
+	_, err := eventbus.NewOutbox(nil)
+	require.ErrorIs(t, err, eventbus.ErrNilDB)
+
+	_, err = eventbus.NewInbox(nil)
+	require.ErrorIs(t, err, eventbus.ErrNilDB)
+
+	pg := &postgres.Postgres{}
+
+	_, err = eventbus.NewOutbox(pg, eventbus.WithOutboxTable("outbox; DROP TABLE orders"))
+	require.ErrorIs(t, err, eventbus.ErrInvalidTableName)
+
+	outbox, err := eventbus.NewOutbox(pg, eventbus.WithOutboxTable("events.outbox"))
+	require.NoError(t, err)
+	require.Contains(t, outbox.Schema(), `CREATE TABLE IF NOT EXISTS "events"."outbox"`)
+	require.Contains(t, outbox.Schema(), `"outbox_pending_idx"`)
+
+	_, err = eventbus.NewRelay(nil, nil)
+	require.ErrorIs(t, err, eventbus.ErrNilOutbox)
+
+	_, err = eventbus.NewRelay(outbox, nil)
+	require.ErrorIs(t, err, eventbus.ErrNilPublisher)
+}
+
+func TestInboxHandler_RejectsInvalidMessages(t *testing.T) {
+	t.Parallel()
+
+	inbox, err := eventbus.NewInbox(&postgres.Postgres{})
+	require.NoError(t, err)
+
+	handler := inbox.Handler("billing", func(_ context.Context, _ *eventbus.Event) error {
+		t.Fatal("handler must not be called")
+
+		return nil
+	})
+
+	require.ErrorIs(t, handler(t.Context(), []byte(`{"payload": {}}`)), eventbus.ErrInvalidEvent)
+	require.ErrorIs(t, inbox.Process(t.Context(), "", "evt-1", nil), eventbus.ErrEmptyConsumer)
+}
+
+func TestPrometheusMetrics(t *testing.T) {
+	t.Parallel()
+
+	reg := prometheus.NewRegistry()
+
+	metrics, err := eventbus.NewPrometheusMetrics(reg)
+	require.NoError(t, err)
+
+	metrics.EventPublished("orders.created", 30*time.Millisecond)
+	metrics.PublishFailed("orders.created", false)
+	metrics.PublishFailed("orders.created", true)
+	metrics.OutboxBacklog("eventbus_outbox", 3, 1)
+	metrics.DuplicateSkipped("billing")
+
+	expected := `
+# HELP gframework_eventbus_published_total Number of outbox events published.
+# TYPE gframework_eventbus_published_total counter
+gframework_eventbus_published_total{topic="orders.created"} 1
+# HELP gframework_eventbus_publish_failures_total Number of failed attempts to publish an outbox event.
+# TYPE gframework_eventbus_publish_failures_total counter
+gframework_eventbus_publish_failures_total{topic="orders.created"} 2
+# HELP gframework_eventbus_dead_total Number of outbox events that exhausted their attempts.
+# TYPE gframework_eventbus_dead_total counter
+gframework_eventbus_dead_total{topic="orders.created"} 1
+# HELP gframework_eventbus_outbox_pending Number of outbox events waiting to be published.
+# TYPE gframework_eventbus_outbox_pending gauge
+gframework_eventbus_outbox_pending{outbox="eventbus_outbox"} 3
+# HELP gframework_eventbus_inbox_duplicates_total Number of redelivered events skipped by an inbox.
+# TYPE gframework_eventbus_inbox_duplicates_total counter
+gframework_eventbus_inbox_duplicates_total{consumer="billing"} 1
+`
+
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"gframework_eventbus_published_total",
+		"gframework_eventbus_publish_failures_total",
+		"gframework_eventbus_dead_total",
+		"gframework_eventbus_outbox_pending",
+		"gframework_eventbus_inbox_duplicates_total",
+	))
+}
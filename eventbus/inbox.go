@@ -0,0 +1,155 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/andyle182810/gframework/postgres"
+	"github.com/rs/zerolog/log"
+)
+
+const defaultInboxTable = "eventbus_inbox"
+
+type InboxOption func(*Inbox)
+
+// WithInboxTable sets the table of the inbox, optionally schema-qualified. It defaults to
+// "eventbus_inbox".
+func WithInboxTable(name string) InboxOption {
+	return func(i *Inbox) {
+		i.tableName = name
+	}
+}
+
+func WithInboxMetrics(m Metrics) InboxOption {
+	return func(i *Inbox) {
+		i.metrics = m
+	}
+}
+
+// Inbox records the events each consumer has processed, so redelivered events are skipped.
+type Inbox struct {
+	db        DB
+	tableName string
+	table     postgres.Table
+	metrics   Metrics
+}
+
+func NewInbox(db DB, opts ...InboxOption) (*Inbox, error) {
+	if db == nil {
+		return nil, ErrNilDB
+	}
+
+	inbox := &Inbox{db: db, tableName: defaultInboxTable, table: postgres.Table{}, metrics: nil} //nolint:exhaustruct
+
+	for _, opt := range opts {
+		opt(inbox)
+	}
+
+	t, err := postgres.NewTable(inbox.tableName)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTableName, err)
+	}
+
+	inbox.table = t
+
+	return inbox, nil
+}
+
+// Schema returns the DDL of the inbox table, to include in the service's migrations.
+func (i *Inbox) Schema() string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %[1]s (
+	consumer     TEXT NOT NULL,
+	event_id     TEXT NOT NULL,
+	processed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	PRIMARY KEY (consumer, event_id)
+);
+CREATE INDEX IF NOT EXISTS %[2]s ON %[1]s (processed_at);
+`, i.table.Identifier(), i.table.Index("processed_idx"))
+}
+
+// EnsureSchema creates the inbox table when it does not exist, for services without migrations and
+// for tests.
+func (i *Inbox) EnsureSchema(ctx context.Context) error {
+	if _, err := i.db.Exec(ctx, i.Schema()); err != nil {
+		return fmt.Errorf("eventbus: failed to create inbox table %s: %w", i.table.Name(), err)
+	}
+
+	return nil
+}
+
+// Process runs fn unless consumer has already processed the event. The event is recorded in the same
+// transaction as fn, which fn's repositories join through postgres.FromCtx, so the event counts as
+// processed if and only if fn's writes commit. A duplicate returns nil without calling fn.
+func (i *Inbox) Process(ctx context.Context, consumer, eventID string, fn func(ctx context.Context) error) error {
+	if consumer == "" {
+		return ErrEmptyConsumer
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s (consumer, event_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, i.table.Identifier())
+
+	duplicate := false
+
+	err := postgres.BeginCtx(ctx, i.db, func(ctx context.Context) error {
+		tag, err := postgres.FromCtx(ctx, i.db).Exec(ctx, query, consumer, eventID)
+		if err != nil {
+			return fmt.Errorf("eventbus: failed to record event %s: %w", eventID, err)
+		}
+
+		if tag.RowsAffected() == 0 {
+			duplicate = true
+
+			return nil
+		}
+
+		return fn(ctx)
+	})
+	if err != nil {
+		return err
+	}
+
+	if duplicate {
+		log.Debug().Str("source", "gframework").Str("consumer", consumer).Str("event_id", eventID).
+			Msg("Skipped duplicate event")
+
+		if i.metrics != nil {
+			i.metrics.DuplicateSkipped(consumer)
+		}
+	}
+
+	return nil
+}
+
+// Handler returns a message handler that decodes events published by a Relay and processes each once
+// for consumer. Its type is assignable to redissub.MessageHandler, kafkasub.MessageHandler,
+// natsjetstream.MessageHandler, amqpsub.MessageHandler and sqssub.MessageHandler. Messages that are not
+// events fail, so the subscriber's retry and DLQ settings apply.
+func (i *Inbox) Handler(
+	consumer string,
+	handle func(ctx context.Context, event *Event) error,
+) func(ctx context.Context, payload message.Payload) error {
+	return func(ctx context.Context, payload message.Payload) error {
+		event, err := DecodeEvent(payload)
+		if err != nil {
+			return err
+		}
+
+		return i.Process(ctx, consumer, event.ID, func(ctx context.Context) error {
+			return handle(ctx, event)
+		})
+	}
+}
+
+// Prune deletes the records of events processed more than olderThan ago. Keep them for longer than
+// the publisher can redeliver an event.
+func (i *Inbox) Prune(ctx context.Context, olderThan time.Duration) (int64, error) {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE processed_at < now() - make_interval(secs => $1)`, i.table.Identifier())
+
+	tag, err := i.db.Exec(ctx, query, olderThan.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("eventbus: failed to prune inbox %s: %w", i.table.Name(), err)
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,85 @@
+package eventbus
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/andyle182810/gframework/metricserver"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics receives relay and inbox events, see WithRelayMetrics and WithInboxMetrics.
+type Metrics interface {
+	// EventPublished is called with the time from adding the event to publishing it.
+	EventPublished(topic string, lag time.Duration)
+	// PublishFailed is called for every failed attempt; dead is set when the event will not be retried.
+	PublishFailed(topic string, dead bool)
+	// OutboxBacklog is called after every poll with the number of pending and dead events.
+	OutboxBacklog(outbox string, pending, dead int64)
+	DuplicateSkipped(consumer string)
+}
+
+// PrometheusMetrics implements Metrics with Prometheus collectors. One instance can be shared by
+// relays and inboxes.
+type PrometheusMetrics struct {
+	published  *prometheus.CounterVec
+	failures   *prometheus.CounterVec
+	dead       *prometheus.CounterVec
+	lag        *prometheus.HistogramVec
+	pending    *prometheus.GaugeVec
+	deadEvents *prometheus.GaugeVec
+	duplicates *prometheus.CounterVec
+}
+
+var _ Metrics = (*PrometheusMetrics)(nil)
+
+// NewPrometheusMetrics registers the eventbus metrics with reg, e.g. the registerer of metricserver.
+func NewPrometheusMetrics(reg prometheus.Registerer) (*PrometheusMetrics, error) {
+	const subsystem = "eventbus"
+
+	m := &PrometheusMetrics{
+		published: metricserver.NewCounterVec(subsystem, "published_total",
+			"Number of outbox events published.", "topic"),
+		failures: metricserver.NewCounterVec(subsystem, "publish_failures_total",
+			"Number of failed attempts to publish an outbox event.", "topic"),
+		dead: metricserver.NewCounterVec(subsystem, "dead_total",
+			"Number of outbox events that exhausted their attempts.", "topic"),
+		lag: metricserver.NewHistogramVec(subsystem, "relay_lag_seconds",
+			"Time from adding an event to the outbox to publishing it.", metricserver.DurationBuckets(), "topic"),
+		pending: metricserver.NewGaugeVec(subsystem, "outbox_pending",
+			"Number of outbox events waiting to be published.", "outbox"),
+		deadEvents: metricserver.NewGaugeVec(subsystem, "outbox_dead",
+			"Number of dead outbox events waiting to be retried.", "outbox"),
+		duplicates: metricserver.NewCounterVec(subsystem, "inbox_duplicates_total",
+			"Number of redelivered events skipped by an inbox.", "consumer"),
+	}
+
+	err := metricserver.Register(reg, m.published, m.failures, m.dead, m.lag, m.pending, m.deadEvents, m.duplicates)
+	if err != nil {
+		return nil, fmt.Errorf("eventbus: failed to register metrics: %w", err)
+	}
+
+	return m, nil
+}
+
+func (m *PrometheusMetrics) EventPublished(topic string, lag time.Duration) {
+	m.published.WithLabelValues(topic).Inc()
+	m.lag.WithLabelValues(topic).Observe(lag.Seconds())
+}
+
+func (m *PrometheusMetrics) PublishFailed(topic string, dead bool) {
+	m.failures.WithLabelValues(topic).Inc()
+
+	if dead {
+		m.dead.WithLabelValues(topic).Inc()
+	}
+}
+
+func (m *PrometheusMetrics) OutboxBacklog(outbox string, pending, dead int64) {
+	m.pending.WithLabelValues(outbox).Set(float64(pending))
+	m.deadEvents.WithLabelValues(outbox).Set(float64(dead))
+}
+
+func (m *PrometheusMetrics) DuplicateSkipped(consumer string) {
+	m.duplicates.WithLabelValues(consumer).Inc()
+}
@@ -0,0 +1,141 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/andyle182810/gframework/postgres"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const defaultOutboxTable = "eventbus_outbox"
+
+type OutboxOption func(*Outbox)
+
+// WithOutboxTable sets the table of the outbox, optionally schema-qualified. It defaults to
+// "eventbus_outbox".
+func WithOutboxTable(name string) OutboxOption {
+	return func(o *Outbox) {
+		o.tableName = name
+	}
+}
+
+// Outbox stores events in a Postgres table until a Relay publishes them.
+type Outbox struct {
+	db        DB
+	tableName string
+	table     postgres.Table
+}
+
+func NewOutbox(db DB, opts ...OutboxOption) (*Outbox, error) {
+	if db == nil {
+		return nil, ErrNilDB
+	}
+
+	outbox := &Outbox{db: db, tableName: defaultOutboxTable, table: postgres.Table{}} //nolint:exhaustruct
+
+	for _, opt := range opts {
+		opt(outbox)
+	}
+
+	t, err := postgres.NewTable(outbox.tableName)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTableName, err)
+	}
+
+	outbox.table = t
+
+	return outbox, nil
+}
+
+// Schema returns the DDL of the outbox table and its indexes, to include in the service's migrations.
+func (o *Outbox) Schema() string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %[1]s (
+	position        BIGSERIAL PRIMARY KEY,
+	id              TEXT NOT NULL UNIQUE,
+	topic           TEXT NOT NULL,
+	event_key       TEXT NOT NULL DEFAULT '',
+	payload         JSONB NOT NULL,
+	headers         JSONB NOT NULL DEFAULT '{}',
+	occurred_at     TIMESTAMPTZ NOT NULL,
+	attempts        INTEGER NOT NULL DEFAULT 0,
+	next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	last_error      TEXT,
+	published_at    TIMESTAMPTZ,
+	dead_at         TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS %[2]s ON %[1]s (next_attempt_at, position) WHERE published_at IS NULL AND dead_at IS NULL;
+CREATE INDEX IF NOT EXISTS %[3]s ON %[1]s (event_key, position) WHERE published_at IS NULL AND dead_at IS NULL;
+CREATE INDEX IF NOT EXISTS %[4]s ON %[1]s (published_at) WHERE published_at IS NOT NULL;
+`, o.table.Identifier(), o.table.Index("pending_idx"), o.table.Index("key_idx"), o.table.Index("published_idx"))
+}
+
+// EnsureSchema creates the outbox table when it does not exist, for services without migrations and
+// for tests.
+func (o *Outbox) EnsureSchema(ctx context.Context) error {
+	if _, err := o.db.Exec(ctx, o.Schema()); err != nil {
+		return fmt.Errorf("eventbus: failed to create outbox table %s: %w", o.table.Name(), err)
+	}
+
+	return nil
+}
+
+// Add stores events in the transaction of ctx, started with postgres.BeginCtx, so they are published
+// only if it commits. Without a transaction in ctx the events are stored on their own.
+func (o *Outbox) Add(ctx context.Context, events ...Event) error {
+	return o.AddTx(ctx, postgres.FromCtx(ctx, o.db), events...)
+}
+
+// AddTx stores events using conn, typically the pgx.Tx of the state change. The trace context of ctx
+// is stored in the headers of each event and restored when the event is published.
+func (o *Outbox) AddTx(ctx context.Context, conn postgres.Conn, events ...Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s (id, topic, event_key, payload, headers, occurred_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`, o.table.Identifier())
+
+	batch := &pgx.Batch{} //nolint:exhaustruct
+
+	for _, event := range events {
+		if err := event.prepare(); err != nil {
+			return err
+		}
+
+		headers := make(map[string]string, len(event.Headers))
+		for key, value := range event.Headers {
+			headers[key] = value
+		}
+
+		otel.GetTextMapPropagator().Inject(ctx, propagation.MapCarrier(headers))
+
+		batch.Queue(query, event.ID, event.Topic, event.Key, event.Payload, headers, event.OccurredAt)
+	}
+
+	if err := conn.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("eventbus: failed to add events to the outbox: %w", err)
+	}
+
+	return nil
+}
+
+// RetryDead makes events that exhausted their attempts pending again, e.g. after fixing the cause.
+// Without ids it retries every dead event.
+func (o *Outbox) RetryDead(ctx context.Context, ids ...string) (int64, error) {
+	query := fmt.Sprintf(`UPDATE %s SET dead_at = NULL, attempts = 0, next_attempt_at = now()
+		WHERE dead_at IS NOT NULL AND (cardinality($1::text[]) = 0 OR id = ANY($1))`, o.table.Identifier())
+
+	if ids == nil {
+		ids = []string{}
+	}
+
+	tag, err := o.db.Exec(ctx, query, ids)
+	if err != nil {
+		return 0, fmt.Errorf("eventbus: failed to retry dead events: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,245 @@
+//nolint:exhaustruct
+package eventbus_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/eventbus"
+	"github.com/andyle182810/gframework/postgres"
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+var errBrokerDown = errors.New("broker down")
+
+func setupPostgres(t *testing.T) *postgres.Postgres {
+	t.Helper()
+
+	container := testutil.SetupPostgresContainer(t)
+
+	pg, err := postgres.New(&postgres.Config{
+		URL:                   container.ConnectionString(),
+		MaxConnection:         5,
+		MinConnection:         1,
+		MaxConnectionIdleTime: time.Minute,
+		HealthCheckPeriod:     10 * time.Second,
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(pg.Close)
+
+	return pg
+}
+
+// flakyPublisher fails the topics in failing and records the rest.
+type flakyPublisher struct {
+	mu        sync.Mutex
+	failing   map[string]bool
+	published []*eventbus.Event
+}
+
+func (p *flakyPublisher) PublishToTopic(_ context.Context, topic string, messageContents ...string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.failing[topic] {
+		return errBrokerDown
+	}
+
+	for _, content := range messageContents {
+		event, err := eventbus.DecodeEvent([]byte(content))
+		if err != nil {
+			return err
+		}
+
+		p.published = append(p.published, event)
+	}
+
+	return nil
+}
+
+func (p *flakyPublisher) setFailing(topic string, failing bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.failing[topic] = failing
+}
+
+func (p *flakyPublisher) ids() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ids := make([]string, 0, len(p.published))
+	for _, event := range p.published {
+		ids = append(ids, event.ID)
+	}
+
+	return ids
+}
+
+func newEvent(t *testing.T, id, topic, key string) eventbus.Event {
+	t.Helper()
+
+	event, err := eventbus.NewEvent(topic, key, map[string]string{"id": id})
+	require.NoError(t, err)
+
+	event.ID = id
+
+	return event
+}
+
+func TestOutbox_AddIsTransactional(t *testing.T) {
+	t.Parallel()
+
+	pg := setupPostgres(t)
+
+	outbox, err := eventbus.NewOutbox(pg)
+	require.NoError(t, err)
+	require.NoError(t, outbox.EnsureSchema(t.Context()))
+
+	err = postgres.BeginCtx(t.Context(), pg, func(ctx context.Context) error {
+		require.NoError(t, outbox.Add(ctx, newEvent(t, "rolled-back", "orders.created", "")))
+
+		return errBrokerDown
+	})
+	require.ErrorIs(t, err, errBrokerDown)
+
+	err = postgres.BeginCtx(t.Context(), pg, func(ctx context.Context) error {
+		return outbox.Add(ctx, newEvent(t, "committed", "orders.created", ""))
+	})
+	require.NoError(t, err)
+
+	publisher := &flakyPublisher{failing: map[string]bool{}}
+
+	relay, err := eventbus.NewRelay(outbox, publisher)
+	require.NoError(t, err)
+
+	relayed, err := relay.RelayBatch(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, 1, relayed)
+	require.Equal(t, []string{"committed"}, publisher.ids())
+
+	relayed, err = relay.RelayBatch(t.Context())
+	require.NoError(t, err)
+	require.Zero(t, relayed, "published events are not relayed again")
+}
+
+func TestRelay_RetriesAndKeepsKeyOrder(t *testing.T) {
+	t.Parallel()
+
+	pg := setupPostgres(t)
+
+	outbox, err := eventbus.NewOutbox(pg)
+	require.NoError(t, err)
+	require.NoError(t, outbox.EnsureSchema(t.Context()))
+
+	require.NoError(t, outbox.Add(t.Context(),
+		newEvent(t, "a1", "orders.paid", "order-a"),
+		newEvent(t, "b1", "orders.created", "order-b"),
+		newEvent(t, "a2", "orders.created", "order-a"),
+	))
+
+	publisher := &flakyPublisher{failing: map[string]bool{"orders.paid": true}}
+
+	relay, err := eventbus.NewRelay(outbox, publisher,
+		eventbus.WithRetryBackoff(50*time.Millisecond, time.Second),
+		eventbus.WithMaxAttempts(5),
+	)
+	require.NoError(t, err)
+
+	_, err = relay.RelayBatch(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, []string{"b1"}, publisher.ids(), "a2 waits for a1 to be published")
+
+	_, err = relay.RelayBatch(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, []string{"b1"}, publisher.ids(), "a1 is backing off, so a2 still waits")
+
+	publisher.setFailing("orders.paid", false)
+
+	require.Eventually(t, func() bool {
+		_, err := relay.RelayBatch(t.Context())
+
+		return err == nil && len(publisher.ids()) == 3
+	}, 5*time.Second, 20*time.Millisecond)
+
+	require.Equal(t, []string{"b1", "a1", "a2"}, publisher.ids())
+}
+
+func TestRelay_MarksEventsDeadAndRetriesThem(t *testing.T) {
+	t.Parallel()
+
+	pg := setupPostgres(t)
+
+	outbox, err := eventbus.NewOutbox(pg, eventbus.WithOutboxTable("dead_letter_outbox"))
+	require.NoError(t, err)
+	require.NoError(t, outbox.EnsureSchema(t.Context()))
+	require.NoError(t, outbox.Add(t.Context(), newEvent(t, "evt-1", "orders.created", "")))
+
+	publisher := &flakyPublisher{failing: map[string]bool{"orders.created": true}}
+
+	relay, err := eventbus.NewRelay(outbox, publisher, eventbus.WithMaxAttempts(1))
+	require.NoError(t, err)
+
+	_, err = relay.RelayBatch(t.Context())
+	require.NoError(t, err)
+
+	retried, err := outbox.RetryDead(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, int64(1), retried)
+
+	publisher.setFailing("orders.created", false)
+
+	relayed, err := relay.RelayBatch(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, 1, relayed)
+	require.Equal(t, []string{"evt-1"}, publisher.ids())
+}
+
+func TestInbox_ProcessesEachEventOnce(t *testing.T) {
+	t.Parallel()
+
+	pg := setupPostgres(t)
+
+	inbox, err := eventbus.NewInbox(pg)
+	require.NoError(t, err)
+	require.NoError(t, inbox.EnsureSchema(t.Context()))
+
+	calls := 0
+	handler := inbox.Handler("billing", func(_ context.Context, event *eventbus.Event) error {
+		calls++
+
+		if event.Key == "fail" && calls == 1 {
+			return errBrokerDown
+		}
+
+		return nil
+	})
+
+	event := newEvent(t, "evt-1", "orders.created", "fail")
+
+	payload, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, handler(t.Context(), payload), errBrokerDown, "a failed handler does not record the event")
+	require.NoError(t, handler(t.Context(), payload))
+	require.NoError(t, handler(t.Context(), payload))
+	require.Equal(t, 2, calls)
+
+	err = inbox.Process(t.Context(), "shipping", "evt-1", func(context.Context) error {
+		calls++
+
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, calls, "consumers deduplicate independently")
+
+	pruned, err := inbox.Prune(t.Context(), 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), pruned)
+}
@@ -0,0 +1,429 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const (
+	defaultPollInterval  = time.Second
+	defaultBatchSize     = 100
+	defaultMaxAttempts   = 10
+	defaultRetryBackoff  = time.Second
+	defaultMaxBackoff    = 5 * time.Minute
+	defaultRetention     = 7 * 24 * time.Hour
+	defaultPruneInterval = time.Hour
+	maxErrorLength       = 1024
+)
+
+type RelayOption func(*Relay)
+
+// WithPollInterval sets how often the outbox is polled when the previous batch was not full. It
+// defaults to one second.
+func WithPollInterval(d time.Duration) RelayOption {
+	return func(r *Relay) {
+		if d > 0 {
+			r.pollInterval = d
+		}
+	}
+}
+
+// WithBatchSize sets the maximum number of events claimed per transaction. It defaults to 100.
+func WithBatchSize(n int) RelayOption {
+	return func(r *Relay) {
+		if n > 0 {
+			r.batchSize = n
+		}
+	}
+}
+
+// WithMaxAttempts sets how often an event is published before it is marked dead. It defaults to 10.
+func WithMaxAttempts(n int) RelayOption {
+	return func(r *Relay) {
+		if n > 0 {
+			r.maxAttempts = n
+		}
+	}
+}
+
+// WithRetryBackoff sets the delay before the first retry, doubled after every failed attempt up to
+// maxBackoff. It defaults to one second, up to five minutes.
+func WithRetryBackoff(initial, maxBackoff time.Duration) RelayOption {
+	return func(r *Relay) {
+		if initial > 0 {
+			r.retryBackoff = initial
+		}
+
+		if maxBackoff >= r.retryBackoff {
+			r.maxBackoff = maxBackoff
+		}
+	}
+}
+
+// WithRetention sets how long published events are kept before they are deleted. It defaults to
+// seven days; dead events are kept until retried or deleted by hand.
+func WithRetention(d time.Duration) RelayOption {
+	return func(r *Relay) {
+		if d > 0 {
+			r.retention = d
+		}
+	}
+}
+
+func WithRelayMetrics(m Metrics) RelayOption {
+	return func(r *Relay) {
+		r.metrics = m
+	}
+}
+
+// Relay publishes the events of an outbox. Several relays can run against the same outbox for
+// availability; a transaction-scoped advisory lock lets one relay at a time publish, which keeps
+// events with the same key in order. An event waiting for a retry holds back later events with its
+// key until it is published or dead.
+type Relay struct {
+	outbox        *Outbox
+	publisher     Publisher
+	pollInterval  time.Duration
+	batchSize     int
+	maxAttempts   int
+	retryBackoff  time.Duration
+	maxBackoff    time.Duration
+	retention     time.Duration
+	pruneInterval time.Duration
+	metrics       Metrics
+	running       atomic.Bool
+	mu            sync.Mutex
+	stop          chan struct{}
+	stopped       chan struct{}
+	ready         chan struct{}
+	readyOnce     sync.Once
+}
+
+func NewRelay(outbox *Outbox, publisher Publisher, opts ...RelayOption) (*Relay, error) {
+	if outbox == nil {
+		return nil, ErrNilOutbox
+	}
+
+	if publisher == nil {
+		return nil, ErrNilPublisher
+	}
+
+	//nolint:exhaustruct
+	relay := &Relay{
+		outbox:        outbox,
+		publisher:     publisher,
+		pollInterval:  defaultPollInterval,
+		batchSize:     defaultBatchSize,
+		maxAttempts:   defaultMaxAttempts,
+		retryBackoff:  defaultRetryBackoff,
+		maxBackoff:    defaultMaxBackoff,
+		retention:     defaultRetention,
+		pruneInterval: defaultPruneInterval,
+		ready:         make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(relay)
+	}
+
+	return relay, nil
+}
+
+func (r *Relay) Name() string {
+	return "eventbus-relay"
+}
+
+// Ready is closed once the first batch has been relayed.
+func (r *Relay) Ready() <-chan struct{} {
+	return r.ready
+}
+
+// Start relays events until ctx is cancelled or Stop is called. Full batches are followed by the next
+// one immediately, so a backlog drains without waiting for the poll interval.
+func (r *Relay) Start(ctx context.Context) error {
+	if !r.running.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	r.mu.Lock()
+	r.stop = make(chan struct{})
+	r.stopped = make(chan struct{})
+	stop, stopped := r.stop, r.stopped
+	r.mu.Unlock()
+
+	defer close(stopped)
+
+	log.Info().Str("source", "gframework").Str("outbox", r.outbox.table.Name()).Msg("Event relay is starting")
+
+	lastPrune := time.Time{}
+
+	for {
+		relayed, err := r.RelayBatch(ctx)
+		if err != nil {
+			log.Error().Str("source", "gframework").Err(err).Str("outbox", r.outbox.table.Name()).Msg("Failed to relay events")
+		}
+
+		r.readyOnce.Do(func() { close(r.ready) })
+
+		if time.Since(lastPrune) >= r.pruneInterval {
+			lastPrune = time.Now()
+			r.prune(ctx)
+		}
+
+		r.reportBacklog(ctx)
+
+		delay := r.pollInterval
+		if err == nil && relayed == r.batchSize {
+			delay = 0
+		}
+
+		select {
+		case <-stop:
+			return nil
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (r *Relay) Stop() error {
+	if !r.running.CompareAndSwap(true, false) {
+		return nil
+	}
+
+	r.mu.Lock()
+	stop, stopped := r.stop, r.stopped
+	r.mu.Unlock()
+
+	close(stop)
+	<-stopped
+
+	log.Info().Str("source", "gframework").Str("outbox", r.outbox.table.Name()).Msg("Event relay stopped")
+
+	return nil
+}
+
+type outboxRow struct {
+	position int64
+	event    Event
+	attempts int
+}
+
+// RelayBatch claims up to the batch size of pending events in one transaction, publishes them and
+// records the outcome. It returns the number of events claimed, zero while another relay holds the
+// lock. An event that fails to publish is retried after a backoff.
+func (r *Relay) RelayBatch(ctx context.Context) (int, error) {
+	var claimed int
+
+	err := r.outbox.db.WithTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		var locked bool
+
+		err := tx.QueryRow(ctx, `SELECT pg_try_advisory_xact_lock(hashtext($1))`, r.outbox.table.Name()).Scan(&locked)
+		if err != nil {
+			return fmt.Errorf("failed to lock outbox: %w", err)
+		}
+
+		if !locked {
+			return nil
+		}
+
+		rows, err := r.claim(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		claimed = len(rows)
+
+		var (
+			published   []int64
+			blockedKeys = make(map[string]bool)
+		)
+
+		for _, row := range rows {
+			if row.event.Key != "" && blockedKeys[row.event.Key] {
+				continue
+			}
+
+			if err := r.publish(ctx, &row.event); err != nil {
+				if row.event.Key != "" {
+					blockedKeys[row.event.Key] = true
+				}
+
+				if err := r.recordFailure(ctx, tx, row, err); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			published = append(published, row.position)
+
+			if r.metrics != nil {
+				r.metrics.EventPublished(row.event.Topic, time.Since(row.event.OccurredAt))
+			}
+		}
+
+		return r.markPublished(ctx, tx, published)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("eventbus: failed to relay outbox %s: %w", r.outbox.table.Name(), err)
+	}
+
+	return claimed, nil
+}
+
+func (r *Relay) claim(ctx context.Context, tx pgx.Tx) ([]outboxRow, error) {
+	query := fmt.Sprintf(`SELECT o.position, o.id, o.topic, o.event_key, o.payload, o.headers, o.occurred_at, o.attempts
+		FROM %[1]s o
+		WHERE o.published_at IS NULL AND o.dead_at IS NULL AND o.next_attempt_at <= now()
+			AND NOT EXISTS (
+				SELECT 1 FROM %[1]s p
+				WHERE o.event_key <> '' AND p.event_key = o.event_key AND p.position < o.position
+					AND p.published_at IS NULL AND p.dead_at IS NULL AND p.next_attempt_at > now()
+			)
+		ORDER BY o.position
+		LIMIT $1
+		FOR UPDATE`, r.outbox.table.Identifier())
+
+	rows, err := tx.Query(ctx, query, r.batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim events: %w", err)
+	}
+
+	claimed, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (outboxRow, error) {
+		var out outboxRow
+
+		err := row.Scan(
+			&out.position, &out.event.ID, &out.event.Topic, &out.event.Key, &out.event.Payload,
+			&out.event.Headers, &out.event.OccurredAt, &out.attempts,
+		)
+
+		return out, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read claimed events: %w", err)
+	}
+
+	return claimed, nil
+}
+
+// publish sends the event with the trace context stored by Add, so the publisher links the consumer
+// to the producer's trace rather than to the relay.
+func (r *Relay) publish(ctx context.Context, event *Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(event.Headers))
+
+	return r.publisher.PublishToTopic(ctx, event.Topic, string(data))
+}
+
+func (r *Relay) recordFailure(ctx context.Context, tx pgx.Tx, row outboxRow, publishErr error) error {
+	attempts := row.attempts + 1
+	dead := attempts >= r.maxAttempts
+
+	message := publishErr.Error()
+	if len(message) > maxErrorLength {
+		message = message[:maxErrorLength]
+	}
+
+	query := fmt.Sprintf(`UPDATE %s SET attempts = $2, last_error = $3,
+		next_attempt_at = now() + make_interval(secs => $4), dead_at = CASE WHEN $5 THEN now() END
+		WHERE position = $1`, r.outbox.table.Identifier())
+
+	_, err := tx.Exec(ctx, query, row.position, attempts, message, r.backoff(attempts).Seconds(), dead)
+	if err != nil {
+		return fmt.Errorf("failed to record publish failure: %w", err)
+	}
+
+	logEvent := log.Warn()
+	if dead {
+		logEvent = log.Error()
+	}
+
+	logEvent.Str("source", "gframework").Err(publishErr).
+		Str("event_id", row.event.ID).
+		Str("topic", row.event.Topic).
+		Int("attempts", attempts).
+		Bool("dead", dead).
+		Msg("Failed to publish event")
+
+	if r.metrics != nil {
+		r.metrics.PublishFailed(row.event.Topic, dead)
+	}
+
+	return nil
+}
+
+func (r *Relay) markPublished(ctx context.Context, tx pgx.Tx, positions []int64) error {
+	if len(positions) == 0 {
+		return nil
+	}
+
+	query := fmt.Sprintf(`UPDATE %s SET published_at = now(), attempts = attempts + 1, last_error = NULL
+		WHERE position = ANY($1)`, r.outbox.table.Identifier())
+
+	if _, err := tx.Exec(ctx, query, positions); err != nil {
+		return fmt.Errorf("failed to mark events as published: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Relay) backoff(attempts int) time.Duration {
+	delay := r.retryBackoff
+
+	for range attempts - 1 {
+		delay *= 2
+
+		if delay >= r.maxBackoff {
+			return r.maxBackoff
+		}
+	}
+
+	return delay
+}
+
+func (r *Relay) prune(ctx context.Context) {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE published_at < now() - make_interval(secs => $1)`, r.outbox.table.Identifier())
+
+	tag, err := r.outbox.db.Exec(ctx, query, r.retention.Seconds())
+	if err != nil {
+		log.Error().Str("source", "gframework").Err(err).Str("outbox", r.outbox.table.Name()).Msg("Failed to prune published events")
+
+		return
+	}
+
+	if tag.RowsAffected() > 0 {
+		log.Debug().Str("source", "gframework").Int64("events", tag.RowsAffected()).Msg("Pruned published events")
+	}
+}
+
+func (r *Relay) reportBacklog(ctx context.Context) {
+	if r.metrics == nil {
+		return
+	}
+
+	query := fmt.Sprintf(`SELECT count(*) FILTER (WHERE dead_at IS NULL), count(*) FILTER (WHERE dead_at IS NOT NULL)
+		FROM %s WHERE published_at IS NULL`, r.outbox.table.Identifier())
+
+	var pending, dead int64
+
+	if err := r.outbox.db.QueryRow(ctx, query).Scan(&pending, &dead); err != nil {
+		return
+	}
+
+	r.metrics.OutboxBacklog(r.outbox.table.Name(), pending, dead)
+}
@@ -17,7 +17,7 @@ const docTemplate = `{
     "paths": {
         "/health": {
             "get": {
-                "description": "Returns the health status of the service.",
+                "description": "Returns 200 while the service is alive, without checking its dependencies.",
                 "produces": [
                     "application/json"
                 ],
@@ -27,9 +27,9 @@ const docTemplate = `{
                 "summary": "Health check",
                 "responses": {
                     "200": {
-                        "description": "Service is healthy",
+                        "description": "Service is alive",
                         "schema": {
-                            "$ref": "#/definitions/httpserver.APIResponse-service_HealthCheckResponse"
+                            "$ref": "#/definitions/health.Report"
                         }
                     }
                 }
@@ -37,7 +37,7 @@ const docTemplate = `{
         },
         "/ready": {
             "get": {
-                "description": "Returns the readiness status of the service and its dependencies.",
+                "description": "Returns the status of postgres, valkey and the subscriber; degraded dependencies that are not\ncritical still report the service as ready.",
                 "produces": [
                     "application/json"
                 ],
@@ -49,13 +49,13 @@ const docTemplate = `{
                     "200": {
                         "description": "Service is ready",
                         "schema": {
-                            "$ref": "#/definitions/httpserver.APIResponse-service_ReadinessCheckResponse"
+                            "$ref": "#/definitions/health.Report"
                         }
                     },
                     "503": {
                         "description": "Service not ready",
                         "schema": {
-                            "$ref": "#/definitions/echo.HTTPError"
+                            "$ref": "#/definitions/health.Report"
                         }
                     }
                 }
@@ -262,41 +262,61 @@ const docTemplate = `{
                 }
             }
         },
-        "httpserver.APIResponse-service_CreateUserResponse": {
+        "health.Report": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/service.CreateUserResponse"
-                },
-                "pagination": {
-                    "$ref": "#/definitions/httpserver.Pagination"
+                "checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/health.Result"
+                    }
                 },
-                "requestId": {
-                    "type": "string",
-                    "example": "3bf74527-8097-4217-8485-ffe05d16f82e"
+                "status": {
+                    "$ref": "#/definitions/health.Status"
                 }
             }
         },
-        "httpserver.APIResponse-service_GetUserResponse": {
+        "health.Result": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/service.GetUserResponse"
+                "checkedAt": {
+                    "type": "string"
                 },
-                "pagination": {
-                    "$ref": "#/definitions/httpserver.Pagination"
+                "critical": {
+                    "type": "boolean"
                 },
-                "requestId": {
-                    "type": "string",
-                    "example": "3bf74527-8097-4217-8485-ffe05d16f82e"
+                "duration": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/health.Status"
                 }
             }
         },
-        "httpserver.APIResponse-service_HealthCheckResponse": {
+        "health.Status": {
+            "type": "string",
+            "enum": [
+                "up",
+                "degraded",
+                "down"
+            ],
+            "x-enum-varnames": [
+                "StatusUp",
+                "StatusDegraded",
+                "StatusDown"
+            ]
+        },
+        "httpserver.APIResponse-service_CreateUserResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/service.HealthCheckResponse"
+                    "$ref": "#/definitions/service.CreateUserResponse"
                 },
                 "pagination": {
                     "$ref": "#/definitions/httpserver.Pagination"
@@ -307,11 +327,11 @@ const docTemplate = `{
                 }
             }
         },
-        "httpserver.APIResponse-service_ListUsersResponse": {
+        "httpserver.APIResponse-service_GetUserResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/service.ListUsersResponse"
+                    "$ref": "#/definitions/service.GetUserResponse"
                 },
                 "pagination": {
                     "$ref": "#/definitions/httpserver.Pagination"
@@ -322,11 +342,11 @@ const docTemplate = `{
                 }
             }
         },
-        "httpserver.APIResponse-service_ReadinessCheckResponse": {
+        "httpserver.APIResponse-service_ListUsersResponse": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/service.ReadinessCheckResponse"
+                    "$ref": "#/definitions/service.ListUsersResponse"
                 },
                 "pagination": {
                     "$ref": "#/definitions/httpserver.Pagination"
@@ -424,26 +444,6 @@ const docTemplate = `{
                 }
             }
         },
-        "service.HealthCheckResponse": {
-            "type": "object",
-            "properties": {
-                "status": {
-                    "type": "string",
-                    "example": "healthy"
-                }
-            }
-        },
-        "service.Info": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "type": "string"
-                },
-                "status": {
-                    "type": "string"
-                }
-            }
-        },
         "service.ListUsersResponse": {
             "type": "object",
             "properties": {
@@ -455,20 +455,6 @@ const docTemplate = `{
                 }
             }
         },
-        "service.ReadinessCheckResponse": {
-            "type": "object",
-            "properties": {
-                "services": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "$ref": "#/definitions/service.Info"
-                    }
-                },
-                "status": {
-                    "type": "string"
-                }
-            }
-        },
         "service.UpdateUserRequest": {
             "type": "object",
             "required": [
@@ -1,10 +1,11 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"time"
 
-	"github.com/caarlos0/env/v11"
+	gconfig "github.com/andyle182810/gframework/config"
 )
 
 type Config struct {
@@ -34,7 +35,7 @@ type Config struct {
 	PostgresHost     string `env:"POSTGRES_HOST"     envDefault:"localhost"`
 	PostgresPort     int    `env:"POSTGRES_PORT"     envDefault:"5441"`
 	PostgresUser     string `env:"POSTGRES_USER"     envDefault:"postgres"`
-	PostgresPassword string `env:"POSTGRES_PASSWORD" envDefault:"password"`
+	PostgresPassword string `env:"POSTGRES_PASSWORD" envDefault:"password" secret:"true"`
 	PostgresDatabase string `env:"POSTGRES_DB"       envDefault:"admindb"`
 	PostgresSSLMode  string `env:"POSTGRES_SSL_MODE" envDefault:"disable"`
 
@@ -70,13 +71,17 @@ type Config struct {
 func New() (*Config, error) {
 	var cfg Config
 
-	if err := env.Parse(&cfg); err != nil {
+	if err := gconfig.Load(context.Background(), &cfg, gconfig.WithDotEnv(".env")); err != nil {
 		return nil, fmt.Errorf("failed to parse config: %w", err)
 	}
 
 	return &cfg, nil
 }
 
+func (c Config) String() string {
+	return gconfig.Redacted(c)
+}
+
 func (c *Config) PostgresDSN() string {
 	return fmt.Sprintf(
 		"host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
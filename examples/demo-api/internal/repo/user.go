@@ -27,6 +27,12 @@ func NewUserRepo(pool postgres.DBPool) *UserRepo {
 	}
 }
 
+// conn returns the transaction started by postgres.BeginCtx when there is one, so callers can group
+// repository calls into a unit of work without passing a pgx.Tx around.
+func (r *UserRepo) conn(ctx context.Context) postgres.Conn {
+	return postgres.FromCtx(ctx, r.pool)
+}
+
 func (r *UserRepo) CreateUser(ctx context.Context, name, email string) (*User, error) {
 	user := &User{
 		ID:        uuid.NewString(),
@@ -42,7 +48,7 @@ func (r *UserRepo) CreateUser(ctx context.Context, name, email string) (*User, e
 		RETURNING id, name, email, created_at, updated_at
 	`
 
-	err := pgxscan.Get(ctx, r.pool, user, query, user.ID, user.Name, user.Email, user.CreatedAt, user.UpdatedAt)
+	err := pgxscan.Get(ctx, r.conn(ctx), user, query, user.ID, user.Name, user.Email, user.CreatedAt, user.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -60,7 +66,7 @@ func (r *UserRepo) GetUserByID(ctx context.Context, id string) (*User, error) {
 
 	var user User
 
-	err := pgxscan.Get(ctx, r.pool, &user, query, id)
+	err := pgxscan.Get(ctx, r.conn(ctx), &user, query, id)
 	if err != nil {
 		return nil, err
 	}
@@ -78,7 +84,7 @@ func (r *UserRepo) GetUserByEmail(ctx context.Context, email string) (*User, err
 
 	var user User
 
-	err := pgxscan.Get(ctx, r.pool, &user, query, email)
+	err := pgxscan.Get(ctx, r.conn(ctx), &user, query, email)
 	if err != nil {
 		return nil, err
 	}
@@ -96,7 +102,7 @@ func (r *UserRepo) ListUsers(ctx context.Context, limit, offset int) ([]*User, e
 
 	var users []*User
 
-	err := pgxscan.Select(ctx, r.pool, &users, query, limit, offset)
+	err := pgxscan.Select(ctx, r.conn(ctx), &users, query, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -109,7 +115,7 @@ func (r *UserRepo) CountUsers(ctx context.Context) (int, error) {
 
 	var count int
 
-	err := r.pool.QueryRow(ctx, query).Scan(&count)
+	err := r.conn(ctx).QueryRow(ctx, query).Scan(&count)
 	if err != nil {
 		return 0, err
 	}
@@ -127,7 +133,7 @@ func (r *UserRepo) UpdateUser(ctx context.Context, id, name, email string) (*Use
 
 	var user User
 
-	err := pgxscan.Get(ctx, r.pool, &user, query, name, email, time.Now(), id)
+	err := pgxscan.Get(ctx, r.conn(ctx), &user, query, name, email, time.Now(), id)
 	if err != nil {
 		return nil, err
 	}
@@ -138,7 +144,7 @@ func (r *UserRepo) UpdateUser(ctx context.Context, id, name, email string) (*Use
 func (r *UserRepo) DeleteUser(ctx context.Context, id string) error {
 	query := `DELETE FROM users WHERE id = $1`
 
-	_, err := r.pool.Exec(ctx, query, id)
+	_, err := r.conn(ctx).Exec(ctx, query, id)
 
 	return err
 }
@@ -9,7 +9,6 @@ import (
 
 	"github.com/andyle182810/gframework/cache"
 	"github.com/andyle182810/gframework/examples/demo-api/internal/service"
-	"github.com/andyle182810/gframework/httpserver"
 	"github.com/andyle182810/gframework/testutil"
 	"github.com/stretchr/testify/require"
 )
@@ -18,6 +17,7 @@ func TestService_CreateUser(t *testing.T) { //nolint:funlen
 	testutil.SkipIfShort(t)
 
 	svc, _, _ := setupTestService(t)
+	srv := startTestServer(t, svc)
 
 	tests := []struct {
 		name           string
@@ -51,7 +51,7 @@ func TestService_CreateUser(t *testing.T) { //nolint:funlen
 			},
 			expectedStatus: http.StatusBadRequest,
 			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
-				require.Contains(t, rec.Body.String(), "name must be at least 2")
+				testutil.AssertGoldenJSON(t, "create_user/name_too_short", rec)
 			},
 		},
 		{
@@ -62,7 +62,7 @@ func TestService_CreateUser(t *testing.T) { //nolint:funlen
 			},
 			expectedStatus: http.StatusBadRequest,
 			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
-				require.Contains(t, rec.Body.String(), "email must be a valid email address")
+				testutil.AssertGoldenJSON(t, "create_user/invalid_email", rec)
 			},
 		},
 		{
@@ -73,7 +73,7 @@ func TestService_CreateUser(t *testing.T) { //nolint:funlen
 			},
 			expectedStatus: http.StatusBadRequest,
 			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
-				require.Contains(t, rec.Body.String(), "name is required")
+				testutil.AssertGoldenJSON(t, "create_user/missing_name", rec)
 			},
 		},
 		{
@@ -84,26 +84,14 @@ func TestService_CreateUser(t *testing.T) { //nolint:funlen
 			},
 			expectedStatus: http.StatusBadRequest,
 			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
-				require.Contains(t, rec.Body.String(), "email is required")
+				testutil.AssertGoldenJSON(t, "create_user/missing_email", rec)
 			},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			echoCtx, rec, _ := testutil.SetupEchoContextWithJSON(
-				t,
-				http.MethodPost,
-				"/v1/users",
-				tt.requestBody,
-			)
-
-			handler := httpserver.Wrapper(svc.CreateUser)
-
-			err := handler(echoCtx)
-			if err != nil {
-				echoCtx.Echo().HTTPErrorHandler(echoCtx, err)
-			}
+			rec := srv.Request(t, http.MethodPost, "/v1/users", tt.requestBody)
 
 			testutil.AssertStatusCode(t, rec, tt.expectedStatus)
 
@@ -118,6 +106,7 @@ func TestService_CreateUser_DuplicateEmail(t *testing.T) {
 	testutil.SkipIfShort(t)
 
 	svc, _, _ := setupTestService(t)
+	srv := startTestServer(t, svc)
 
 	email := testutil.RandomEmail()
 
@@ -126,19 +115,7 @@ func TestService_CreateUser_DuplicateEmail(t *testing.T) {
 		Email: email,
 	}
 
-	echoCtx1, rec1, _ := testutil.SetupEchoContextWithJSON(
-		t,
-		http.MethodPost,
-		"/v1/users",
-		req1,
-	)
-
-	handler := httpserver.Wrapper(svc.CreateUser)
-
-	err := handler(echoCtx1)
-	if err != nil {
-		echoCtx1.Echo().HTTPErrorHandler(echoCtx1, err)
-	}
+	rec1 := srv.Request(t, http.MethodPost, "/v1/users", req1)
 
 	testutil.AssertStatusCode(t, rec1, http.StatusOK)
 
@@ -147,17 +124,7 @@ func TestService_CreateUser_DuplicateEmail(t *testing.T) {
 		Email: email,
 	}
 
-	echoCtx2, rec2, _ := testutil.SetupEchoContextWithJSON(
-		t,
-		http.MethodPost,
-		"/v1/users",
-		req2,
-	)
-
-	err = handler(echoCtx2)
-	if err != nil {
-		echoCtx2.Echo().HTTPErrorHandler(echoCtx2, err)
-	}
+	rec2 := srv.Request(t, http.MethodPost, "/v1/users", req2)
 
 	require.NotEqual(t, http.StatusOK, rec2.Code)
 }
@@ -167,25 +134,14 @@ func TestService_CreateUser_WithCache(t *testing.T) {
 
 	ctx := testutil.Context(t)
 	svc, _, redis := setupTestService(t)
+	srv := startTestServer(t, svc)
 
 	createReq := service.CreateUserRequest{
 		Name:  "Cached User",
 		Email: testutil.RandomEmail(),
 	}
 
-	echoCtxCreate, recCreate, _ := testutil.SetupEchoContextWithJSON(
-		t,
-		http.MethodPost,
-		"/v1/users",
-		createReq,
-	)
-
-	createHandler := httpserver.Wrapper(svc.CreateUser)
-
-	err := createHandler(echoCtxCreate)
-	if err != nil {
-		echoCtxCreate.Echo().HTTPErrorHandler(echoCtxCreate, err)
-	}
+	recCreate := srv.Request(t, http.MethodPost, "/v1/users", createReq)
 
 	testutil.AssertStatusCode(t, recCreate, http.StatusOK)
 
@@ -7,7 +7,6 @@ import (
 	"testing"
 
 	"github.com/andyle182810/gframework/examples/demo-api/internal/service"
-	"github.com/andyle182810/gframework/httpserver"
 	"github.com/andyle182810/gframework/testutil"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
@@ -18,6 +17,7 @@ func TestService_GetUser(t *testing.T) {
 	ctx := t.Context()
 
 	svc, repository, _ := setupTestService(t)
+	srv := startTestServer(t, svc)
 
 	email := testutil.RandomEmail()
 	user, err := repository.User.CreateUser(ctx, "Test User", email)
@@ -60,27 +60,14 @@ func TestService_GetUser(t *testing.T) {
 			userID:         "invalid-uuid",
 			expectedStatus: http.StatusBadRequest,
 			checkResponse: func(t *testing.T, rec *httptest.ResponseRecorder) {
-				require.Contains(t, rec.Body.String(), "UserID must be a valid UUID")
+				testutil.AssertGoldenJSON(t, "get_user/invalid_user_id", rec)
 			},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			echoCtx, rec, _ := testutil.SetupEchoContext(t, &testutil.Options{ //nolint:exhaustruct
-				Method: http.MethodGet,
-				Path:   "/v1/users/:userId",
-				PathParams: map[string]string{
-					"userId": tt.userID,
-				},
-			})
-
-			handler := httpserver.Wrapper(svc.GetUser)
-
-			err := handler(echoCtx)
-			if err != nil {
-				echoCtx.Echo().HTTPErrorHandler(echoCtx, err)
-			}
+			rec := srv.Request(t, http.MethodGet, "/v1/users/"+tt.userID, nil)
 
 			testutil.AssertStatusCode(t, rec, tt.expectedStatus)
 
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
@@ -20,6 +21,7 @@ func TestService_ListUsers(t *testing.T) { //nolint:funlen
 	ctx := t.Context()
 
 	svc, repository, _ := setupTestService(t)
+	srv := startTestServer(t, svc)
 
 	for i := range 15 {
 		_, err := repository.User.CreateUser(ctx, fmt.Sprintf("User %d", i), testutil.RandomEmail())
@@ -110,19 +112,13 @@ func TestService_ListUsers(t *testing.T) { //nolint:funlen
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			echoCtx, rec, _ := testutil.SetupEchoContext(t, &testutil.Options{ //nolint:exhaustruct
-				Method:      http.MethodGet,
-				Path:        "/v1/users",
-				QueryParams: tt.queryParams,
-			})
-
-			handler := httpserver.Wrapper(svc.ListUsers)
-
-			err := handler(echoCtx)
-			if err != nil {
-				echoCtx.Echo().HTTPErrorHandler(echoCtx, err)
+			query := url.Values{}
+			for key, value := range tt.queryParams {
+				query.Set(key, value)
 			}
 
+			rec := srv.Request(t, http.MethodGet, "/v1/users?"+query.Encode(), nil)
+
 			testutil.AssertStatusCode(t, rec, tt.expectedStatus)
 
 			if tt.checkResponse != nil {
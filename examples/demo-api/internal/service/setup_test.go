@@ -6,10 +6,13 @@ import (
 
 	"github.com/andyle182810/gframework/examples/demo-api/internal/repo"
 	"github.com/andyle182810/gframework/examples/demo-api/internal/service"
+	"github.com/andyle182810/gframework/httpserver"
+	"github.com/andyle182810/gframework/middleware"
 	"github.com/andyle182810/gframework/postgres"
 	"github.com/andyle182810/gframework/testutil"
 	"github.com/andyle182810/gframework/valkey"
 	"github.com/jackc/pgx/v5/tracelog"
+	"github.com/labstack/echo/v5"
 	"github.com/stretchr/testify/require"
 )
 
@@ -53,3 +56,18 @@ func setupTestService(t *testing.T) (*service.Service, *repo.Repository, *valkey
 
 	return service, repository, valkey
 }
+
+// startTestServer serves svc with the same routes and middleware as the demo-api binary.
+func startTestServer(t *testing.T, svc *service.Service) *testutil.TestServer {
+	t.Helper()
+
+	return testutil.StartTestServer(t, func(_ *echo.Echo, root *echo.Group) {
+		v1 := root.Group("/v1")
+		v1.Use(middleware.RequestID(httpserver.RequestIDSkipper(false)))
+
+		v1.POST("/users", httpserver.Wrapper(svc.CreateUser))
+		v1.GET("/users/:userId", httpserver.Wrapper(svc.GetUser))
+		v1.PATCH("/users/:userId", httpserver.Wrapper(svc.UpdateUser))
+		v1.GET("/users", httpserver.Wrapper(svc.ListUsers))
+	})
+}
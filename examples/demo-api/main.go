@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/andyle182810/gframework/distlock"
 	"github.com/andyle182810/gframework/examples/demo-api/internal/config"
 	"github.com/andyle182810/gframework/examples/demo-api/internal/executor/consumer"
 	"github.com/andyle182810/gframework/examples/demo-api/internal/executor/worker"
@@ -11,6 +12,7 @@ import (
 	"github.com/andyle182810/gframework/examples/demo-api/internal/publisher"
 	"github.com/andyle182810/gframework/examples/demo-api/internal/repo"
 	"github.com/andyle182810/gframework/examples/demo-api/internal/service"
+	"github.com/andyle182810/gframework/health"
 	"github.com/andyle182810/gframework/httpserver"
 	"github.com/andyle182810/gframework/logutil"
 	"github.com/andyle182810/gframework/metricserver"
@@ -79,6 +81,13 @@ func run() error {
 		return err
 	}
 
+	checks := health.New()
+	checks.Register(
+		health.Postgres(db),
+		health.Valkey(valkey),
+		health.Subscriber("subscriber", multiSubscriber),
+	)
+
 	app := &application{
 		cfg:       cfg,
 		svc:       svc,
@@ -86,20 +95,33 @@ func run() error {
 		valkey:    valkey,
 		taskQueue: taskQueue,
 		publisher: publisher,
+		health:    checks,
 	}
 
+	httpServer := app.newHTTPServer()
+
+	recoveryLock := initRecoveryLock(valkey)
+
+	producerPool := app.newTaskProducerPool()
+
 	appRunner := runner.New(
 		runner.WithInfrastructureService(db),
 		runner.WithInfrastructureService(valkey),
 		runner.WithCoreService(app.newMetricServer()),
-		runner.WithCoreService(app.newHTTPServer()),
-		runner.WithCoreService(app.newTaskProducerPool()),
+		runner.WithCoreService(httpServer),
+		runner.WithCoreService(producerPool),
 		runner.WithCoreService(taskQueue),
 		runner.WithCoreService(app.newMessagePublisherPool()),
-		runner.WithCoreService(app.newTaskRecoveryPool()),
+		runner.WithCoreService(runner.WithLeaderElectedService(app.newTaskRecoveryPool(), recoveryLock)),
 		runner.WithCoreService(multiSubscriber),
 	)
 
+	// Operational endpoints, e.g. POST /admin/restart?service=demo-api-subscriber after a config change.
+	admin := httpServer.Root.Group("/admin")
+	admin.POST("/restart", echo.WrapHandler(appRunner.RestartHandler()))
+	admin.POST("/workers/task-producer", echo.WrapHandler(producerPool.ResizeHandler()))
+	admin.POST("/workers/task-producer/control", echo.WrapHandler(producerPool.ControlHandler()))
+
 	appRunner.Run()
 
 	return nil
@@ -112,6 +134,7 @@ type application struct {
 	valkey    *valkey.Valkey
 	taskQueue *taskqueue.Queue
 	publisher *redispub.RedisPublisher
+	health    *health.Health
 }
 
 func (app *application) newHTTPServer() *httpserver.Server {
@@ -141,7 +164,10 @@ func (app *application) newMetricServer() *metricserver.Server {
 		GracePeriod:  app.cfg.GracefulShutdownPeriod,
 	}
 
-	return metricserver.New(metricCfg)
+	svr := metricserver.New(metricCfg)
+	svr.AddReadinessCheck("dependencies", app.health.Ready)
+
+	return svr
 }
 
 func (app *application) newTaskProducerPool() *workerpool.WorkerPool {
@@ -189,8 +215,8 @@ func (app *application) newTaskRecoveryPool() *workerpool.WorkerPool {
 }
 
 func (app *application) registerRoutes(_ *echo.Echo, root *echo.Group) {
-	root.GET("/health", httpserver.Wrapper(app.svc.CheckHealth))
-	root.GET("/ready", httpserver.Wrapper(app.svc.CheckReadiness))
+	root.GET("/health", livenessHandler(app.health))
+	root.GET("/ready", readinessHandler(app.health))
 
 	v1 := root.Group("/v1")
 	v1.Use(middleware.RequestID(httpserver.RequestIDSkipper(app.cfg.HTTPSkipRequestID)))
@@ -201,6 +227,32 @@ func (app *application) registerRoutes(_ *echo.Echo, root *echo.Group) {
 	v1.GET("/users", httpserver.Wrapper(app.svc.ListUsers))
 }
 
+// livenessHandler godoc
+//
+//	@Summary		Health check
+//	@Description	Returns 200 while the service is alive, without checking its dependencies.
+//	@Tags			health
+//	@Produce		json
+//	@Success		200	{object}	health.Report	"Service is alive"
+//	@Router			/health [get]
+func livenessHandler(checks *health.Health) echo.HandlerFunc {
+	return checks.LivenessHandler()
+}
+
+// readinessHandler godoc
+//
+//	@Summary		Readiness check
+//	@Description	Returns the status of postgres, valkey and the subscriber; degraded dependencies that are not
+//	@Description	critical still report the service as ready.
+//	@Tags			health
+//	@Produce		json
+//	@Success		200	{object}	health.Report	"Service is ready"
+//	@Failure		503	{object}	health.Report	"Service not ready"
+//	@Router			/ready [get]
+func readinessHandler(checks *health.Health) echo.HandlerFunc {
+	return checks.ReadinessHandler()
+}
+
 func initPostgres(cfg *config.Config) (*postgres.Postgres, error) {
 	maxConn := cfg.PostgresMaxConnection
 	minConn := cfg.PostgresMinConnection
@@ -302,6 +354,11 @@ func initValkeyPublisher(valkeyClient *valkey.Valkey) (*redispub.RedisPublisher,
 	return publisher, nil
 }
 
+// initRecoveryLock guards the task recovery pool so that only one replica recovers stale tasks.
+func initRecoveryLock(valkeyClient *valkey.Valkey) *distlock.LeaderLock {
+	return distlock.New(valkeyClient.Client).NewLeaderLock("demo-api:leader:task-recovery", 15*time.Second) //nolint:mnd
+}
+
 func initMultiSubscriber(valkeyClient *valkey.Valkey) (*redissub.MultiSubscriber, error) {
 	multiSub := redissub.NewMultiSubscriber(
 		"demo-api-subscriber",
@@ -0,0 +1,173 @@
+package featureflag
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Client evaluates flags with typed defaults. A nil *Client returns the defaults, so handlers can use
+// FromContext without checking whether Middleware ran.
+type Client struct {
+	provider Provider
+}
+
+func New(provider Provider) (*Client, error) {
+	if provider == nil {
+		return nil, ErrNilProvider
+	}
+
+	return &Client{provider: provider}, nil
+}
+
+type clientKey struct{}
+
+func ContextWithClient(ctx context.Context, client *Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+// FromContext returns the client stored by Middleware or ContextWithClient, or nil.
+func FromContext(ctx context.Context) *Client {
+	client, _ := ctx.Value(clientKey{}).(*Client)
+
+	return client
+}
+
+// Bool returns the value of the flag for the target in ctx, or defaultValue.
+func (c *Client) Bool(ctx context.Context, key string, defaultValue bool) bool {
+	value, ok := c.evaluate(ctx, key, KindBool)
+	if !ok {
+		return defaultValue
+	}
+
+	parsed, err := toBool(value)
+	if err != nil {
+		logMismatch(key, KindBool, value)
+
+		return defaultValue
+	}
+
+	return parsed
+}
+
+// String returns the value of the flag for the target in ctx, or defaultValue.
+func (c *Client) String(ctx context.Context, key string, defaultValue string) string {
+	value, ok := c.evaluate(ctx, key, KindString)
+	if !ok {
+		return defaultValue
+	}
+
+	parsed, ok := value.(string)
+	if !ok {
+		logMismatch(key, KindString, value)
+
+		return defaultValue
+	}
+
+	return parsed
+}
+
+// Int returns the value of the flag for the target in ctx, or defaultValue.
+func (c *Client) Int(ctx context.Context, key string, defaultValue int) int {
+	value, ok := c.evaluate(ctx, key, KindInt)
+	if !ok {
+		return defaultValue
+	}
+
+	parsed, err := toInt(value)
+	if err != nil {
+		logMismatch(key, KindInt, value)
+
+		return defaultValue
+	}
+
+	return parsed
+}
+
+func (c *Client) evaluate(ctx context.Context, key string, kind Kind) (any, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	target := TargetFromContext(ctx)
+	if override, ok := target.Overrides[key]; ok {
+		// Overrides arrive as text; typed parsing turns "true" or "3" into the expected kind.
+		return override, true
+	}
+
+	value, err := c.provider.Evaluate(ctx, key, kind, target)
+	if err != nil {
+		event := log.Warn()
+		if errors.Is(err, ErrFlagNotFound) {
+			event = log.Debug()
+		}
+
+		event.Str("source", "gframework").Err(err).Str("flag", key).Msg("Feature flag evaluation failed, using default")
+
+		return nil, false
+	}
+
+	return value, true
+}
+
+func logMismatch(key string, kind Kind, value any) {
+	log.Warn().
+		Str("source", "gframework").
+		Str("flag", key).
+		Str("expected", string(kind)).
+		Str("type", fmt.Sprintf("%T", value)).
+		Msg("Feature flag has the wrong type, using default")
+}
+
+func toBool(value any) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			return false, fmt.Errorf("%w: %w", ErrTypeMismatch, err)
+		}
+
+		return parsed, nil
+	default:
+		return false, ErrTypeMismatch
+	}
+}
+
+// toInt accepts the integer types YAML and OpenFeature produce, and floats and json.Number without
+// a fraction as JSON decodes numbers.
+func toInt(value any) (int, error) {
+	switch v := value.(type) {
+	case int:
+		return v, nil
+	case int64:
+		return int(v), nil
+	case float64:
+		if v != math.Trunc(v) {
+			return 0, ErrTypeMismatch
+		}
+
+		return int(v), nil
+	case json.Number:
+		return parseInt(v.String())
+	case string:
+		return parseInt(v)
+	default:
+		return 0, ErrTypeMismatch
+	}
+}
+
+func parseInt(raw string) (int, error) {
+	parsed, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrTypeMismatch, err)
+	}
+
+	return parsed, nil
+}
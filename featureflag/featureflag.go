@@ -0,0 +1,151 @@
+// Package featureflag evaluates feature flags from a pluggable Provider: a static file, flags stored in
+// Valkey and updated over pub/sub, or any OpenFeature provider.
+//
+// A flag has a default value and targeting rules matching users, tenants or a percentage of them:
+//
+//	new-checkout:
+//	  value: false
+//	  rules:
+//	    - tenants: [acme]
+//	      value: true
+//	    - percentage: 10
+//	      value: true
+//
+// Flags are evaluated for the Target carried by the context, which Middleware fills from the tenant
+// and JWT subject of the request:
+//
+//	flags, err := featureflag.New(provider)
+//	e.Use(featureflag.Middleware(flags))
+//
+//	func (h *Handler) Checkout(c *echo.Context) error {
+//	    if featureflag.FromContext(c.Request().Context()).Bool(c.Request().Context(), "new-checkout", false) {
+//	        ...
+//	    }
+//	}
+//
+// Evaluation never fails: a missing flag, a provider error or a value of the wrong type yields the
+// default passed by the caller.
+package featureflag
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+	"slices"
+)
+
+const percentageBuckets = 100
+
+var (
+	ErrNilProvider  = errors.New("featureflag: provider cannot be nil")
+	ErrFlagNotFound = errors.New("featureflag: flag not found")
+	ErrTypeMismatch = errors.New("featureflag: flag value has the wrong type")
+)
+
+// Kind is the type a caller expects a flag to have. Providers that resolve values by type, such as
+// OpenFeature, use it; the others may ignore it.
+type Kind string
+
+const (
+	KindBool   Kind = "bool"
+	KindString Kind = "string"
+	KindInt    Kind = "int"
+)
+
+// Provider resolves the value of a flag for a target. It returns ErrFlagNotFound for unknown flags.
+type Provider interface {
+	Evaluate(ctx context.Context, key string, kind Kind, target Target) (any, error)
+}
+
+// Flag is a flag definition, as stored in files and in Valkey.
+type Flag struct {
+	Value any    `json:"value"           yaml:"value"`
+	Rules []Rule `json:"rules,omitempty" yaml:"rules,omitempty"`
+}
+
+// Rule serves Value to the targets it matches. A target matches when its user or tenant is listed,
+// or, for rules without lists, when it falls into the first Percentage of 100 buckets; with both, the
+// listed targets are rolled out to gradually. Rules are tried in order and the first match wins.
+type Rule struct {
+	Users      []string `json:"users,omitempty"      yaml:"users,omitempty"`
+	Tenants    []string `json:"tenants,omitempty"    yaml:"tenants,omitempty"`
+	Percentage int      `json:"percentage,omitempty" yaml:"percentage,omitempty"`
+	Value      any      `json:"value"                yaml:"value"`
+}
+
+// Resolve returns the value of the flag named key for target.
+func (f Flag) Resolve(key string, target Target) any {
+	for _, rule := range f.Rules {
+		if rule.matches(key, target) {
+			return rule.Value
+		}
+	}
+
+	return f.Value
+}
+
+func (r Rule) matches(key string, target Target) bool {
+	if len(r.Users) > 0 || len(r.Tenants) > 0 {
+		listed := (target.UserID != "" && slices.Contains(r.Users, target.UserID)) ||
+			(target.TenantID != "" && slices.Contains(r.Tenants, target.TenantID))
+		if !listed {
+			return false
+		}
+
+		if r.Percentage == 0 {
+			return true
+		}
+	}
+
+	return r.Percentage > 0 && bucket(key, target) < r.Percentage
+}
+
+// bucket places the target in one of 100 buckets, stable per flag so a rollout does not always pick
+// the same users first.
+func bucket(key string, target Target) int {
+	id := target.Key()
+	if id == "" {
+		return percentageBuckets
+	}
+
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(key + ":" + id))
+
+	return int(hash.Sum32() % percentageBuckets)
+}
+
+// Target is who a flag is evaluated for.
+type Target struct {
+	UserID   string
+	TenantID string
+	// Overrides force flag values, e.g. from the X-Feature-Flags header, and skip the provider.
+	Overrides map[string]string
+}
+
+// Key identifies the target for percentage rollouts: the user, else the tenant.
+func (t Target) Key() string {
+	if t.UserID != "" {
+		return t.UserID
+	}
+
+	return t.TenantID
+}
+
+type targetKey struct{}
+
+// ContextWithTarget sets the target flags are evaluated for, e.g. in a worker handling a tenant's job.
+func ContextWithTarget(ctx context.Context, target Target) context.Context {
+	return context.WithValue(ctx, targetKey{}, func() Target { return target })
+}
+
+func contextWithTargetFunc(ctx context.Context, target func() Target) context.Context {
+	return context.WithValue(ctx, targetKey{}, target)
+}
+
+func TargetFromContext(ctx context.Context) Target {
+	if target, ok := ctx.Value(targetKey{}).(func() Target); ok {
+		return target()
+	}
+
+	return Target{UserID: "", TenantID: "", Overrides: nil}
+}
@@ -0,0 +1,292 @@
+//nolint:exhaustruct
+package featureflag_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/andyle182810/gframework/featureflag"
+	"github.com/andyle182810/gframework/httpclient"
+	"github.com/andyle182810/gframework/middleware"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v5"
+	"github.com/open-feature/go-sdk/openfeature"
+	"github.com/open-feature/go-sdk/openfeature/memprovider"
+	"github.com/stretchr/testify/require"
+)
+
+func newClient(t *testing.T, flags map[string]featureflag.Flag) *featureflag.Client {
+	t.Helper()
+
+	client, err := featureflag.New(featureflag.NewStaticProvider(flags))
+	require.NoError(t, err)
+
+	return client
+}
+
+func TestFlag_Resolve(t *testing.T) {
+	t.Parallel()
+
+	flag := featureflag.Flag{
+		Value: "control",
+		Rules: []featureflag.Rule{
+			{Users: []string{"user-1"}, Value: "user"},
+			{Tenants: []string{"acme"}, Value: "tenant"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		target   featureflag.Target
+		expected any
+	}{
+		{name: "no target", target: featureflag.Target{}, expected: "control"},
+		{name: "listed user", target: featureflag.Target{UserID: "user-1", TenantID: "acme"}, expected: "user"},
+		{name: "listed tenant", target: featureflag.Target{UserID: "user-2", TenantID: "acme"}, expected: "tenant"},
+		{name: "unlisted", target: featureflag.Target{UserID: "user-2", TenantID: "globex"}, expected: "control"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.expected, flag.Resolve("variant", tt.target))
+		})
+	}
+}
+
+func TestFlag_ResolvePercentage(t *testing.T) {
+	t.Parallel()
+
+	flag := featureflag.Flag{Value: false, Rules: []featureflag.Rule{{Percentage: 30, Value: true}}}
+
+	enabled := 0
+
+	for i := range 1000 {
+		target := featureflag.Target{UserID: "user-" + strconv.Itoa(i)}
+		value := flag.Resolve("rollout", target)
+
+		require.Equal(t, value, flag.Resolve("rollout", target), "a target stays in its bucket")
+
+		if value == true {
+			enabled++
+		}
+	}
+
+	require.InDelta(t, 300, enabled, 60)
+	require.Equal(t, false, flag.Resolve("rollout", featureflag.Target{}), "anonymous targets are not rolled out to")
+}
+
+func TestClient_TypedEvaluation(t *testing.T) {
+	t.Parallel()
+
+	client := newClient(t, map[string]featureflag.Flag{
+		"enabled":   {Value: true},
+		"theme":     {Value: "dark"},
+		"page-size": {Value: 50},
+		"ratio":     {Value: 0.5},
+	})
+	ctx := t.Context()
+
+	require.True(t, client.Bool(ctx, "enabled", false))
+	require.Equal(t, "dark", client.String(ctx, "theme", "light"))
+	require.Equal(t, 50, client.Int(ctx, "page-size", 10))
+
+	require.False(t, client.Bool(ctx, "missing", false), "missing flags yield the default")
+	require.Equal(t, "light", client.String(ctx, "page-size", "light"), "wrong types yield the default")
+	require.Equal(t, 10, client.Int(ctx, "ratio", 10))
+}
+
+func TestClient_TargetFromContext(t *testing.T) {
+	t.Parallel()
+
+	client := newClient(t, map[string]featureflag.Flag{
+		"beta": {Value: false, Rules: []featureflag.Rule{{Tenants: []string{"acme"}, Value: true}}},
+	})
+
+	ctx := featureflag.ContextWithTarget(t.Context(), featureflag.Target{TenantID: "acme"})
+	require.True(t, client.Bool(ctx, "beta", false))
+	require.False(t, client.Bool(t.Context(), "beta", false))
+
+	ctx = featureflag.ContextWithTarget(t.Context(), featureflag.Target{Overrides: map[string]string{"beta": "true"}})
+	require.True(t, client.Bool(ctx, "beta", false), "overrides skip the provider")
+}
+
+func TestClient_NilReturnsDefaults(t *testing.T) {
+	t.Parallel()
+
+	client := featureflag.FromContext(t.Context())
+
+	require.Nil(t, client)
+	require.True(t, client.Bool(t.Context(), "enabled", true))
+	require.Equal(t, "light", client.String(t.Context(), "theme", "light"))
+	require.Equal(t, 10, client.Int(t.Context(), "page-size", 10))
+}
+
+func TestNew_RequiresProvider(t *testing.T) {
+	t.Parallel()
+
+	_, err := featureflag.New(nil)
+
+	require.ErrorIs(t, err, featureflag.ErrNilProvider)
+}
+
+func TestLoadFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "flags.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+new-checkout:
+  value: false
+  rules:
+    - tenants: [acme]
+      value: true
+page-size:
+  value: 25
+`), 0o600))
+
+	provider, err := featureflag.LoadFile(path)
+	require.NoError(t, err)
+
+	client, err := featureflag.New(provider)
+	require.NoError(t, err)
+
+	ctx := featureflag.ContextWithTarget(t.Context(), featureflag.Target{TenantID: "acme"})
+	require.True(t, client.Bool(ctx, "new-checkout", false))
+	require.Equal(t, 25, client.Int(ctx, "page-size", 10))
+
+	require.NoError(t, os.WriteFile(path, []byte(`{"page-size": {"value": 100}}`), 0o600))
+	require.Error(t, provider.Reload(filepath.Join(t.TempDir(), "flags.toml")))
+	require.NoError(t, provider.Reload(path))
+	require.Equal(t, 100, client.Int(ctx, "page-size", 10))
+	require.False(t, client.Bool(ctx, "new-checkout", false))
+}
+
+func TestParseOverrides(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, featureflag.ParseOverrides(" "))
+	require.Equal(t,
+		map[string]string{"new-checkout": "true", "theme": "dark", "beta": "true"},
+		featureflag.ParseOverrides("new-checkout=true, theme = dark,beta,,"),
+	)
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	client := newClient(t, map[string]featureflag.Flag{
+		"beta":  {Value: false, Rules: []featureflag.Rule{{Users: []string{"user-1"}, Value: true}}},
+		"theme": {Value: "light", Rules: []featureflag.Rule{{Tenants: []string{"acme"}, Value: "dark"}}},
+	})
+
+	tests := []struct {
+		name      string
+		opts      []featureflag.MiddlewareOption
+		header    string
+		expected  string
+		setTarget bool
+	}{
+		{name: "anonymous", expected: "false light"},
+		{name: "targeted", setTarget: true, expected: "true dark"},
+		{name: "header ignored by default", header: "theme=blue", expected: "false light"},
+		{
+			name:     "header overrides",
+			opts:     []featureflag.MiddlewareOption{featureflag.WithHeaderOverrides()},
+			header:   "theme=blue,beta",
+			expected: "true blue",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.Use(featureflag.Middleware(client, tt.opts...))
+
+			if tt.setTarget {
+				// Registered after the flags middleware: the target is resolved on first evaluation.
+				e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+					return func(c *echo.Context) error {
+						c.Set(middleware.ContextKeyTenantID, "acme")
+						c.Set(middleware.ContextKeyClaims, &middleware.ExtendedClaims{
+							RegisteredClaims: jwt.RegisteredClaims{Subject: "user-1"},
+						})
+
+						return next(c)
+					}
+				})
+			}
+
+			e.GET("/", func(c *echo.Context) error {
+				ctx := c.Request().Context()
+				flags := featureflag.FromContext(ctx)
+
+				return c.String(http.StatusOK, strconv.FormatBool(flags.Bool(ctx, "beta", false))+" "+flags.String(ctx, "theme", ""))
+			})
+
+			req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(httpclient.HeaderXFeatureFlags, tt.header)
+			}
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			require.Equal(t, http.StatusOK, rec.Code)
+			require.Equal(t, tt.expected, rec.Body.String())
+		})
+	}
+}
+
+func TestOpenFeatureProvider(t *testing.T) {
+	t.Parallel()
+
+	tenantEvaluator := func(flag memprovider.InMemoryFlag, flatCtx openfeature.FlattenedContext) (any, openfeature.ProviderResolutionDetail) {
+		variant := flag.DefaultVariant
+		if flatCtx["tenantId"] == "acme" {
+			variant = "on"
+		}
+
+		return flag.Variants[variant], openfeature.ProviderResolutionDetail{Variant: variant, Reason: openfeature.TargetingMatchReason}
+	}
+
+	provider := memprovider.NewInMemoryProvider(map[string]memprovider.InMemoryFlag{
+		"beta": {
+			Key:              "beta",
+			State:            memprovider.Enabled,
+			DefaultVariant:   "off",
+			Variants:         map[string]any{"on": true, "off": false},
+			ContextEvaluator: &tenantEvaluator,
+		},
+		"theme":     {Key: "theme", State: memprovider.Enabled, DefaultVariant: "dark", Variants: map[string]any{"dark": "dark"}},
+		"page-size": {Key: "page-size", State: memprovider.Enabled, DefaultVariant: "large", Variants: map[string]any{"large": int64(100)}},
+	})
+
+	domain := "featureflag-test"
+	require.NoError(t, openfeature.SetNamedProviderAndWait(domain, provider))
+
+	adapter, err := featureflag.NewOpenFeatureProvider(openfeature.NewClient(domain))
+	require.NoError(t, err)
+
+	client, err := featureflag.New(adapter)
+	require.NoError(t, err)
+
+	ctx := featureflag.ContextWithTarget(t.Context(), featureflag.Target{UserID: "user-1", TenantID: "acme"})
+	require.True(t, client.Bool(ctx, "beta", false))
+	require.False(t, client.Bool(t.Context(), "beta", true))
+	require.Equal(t, "dark", client.String(ctx, "theme", "light"))
+	require.Equal(t, 100, client.Int(ctx, "page-size", 10))
+	require.Equal(t, 10, client.Int(ctx, "missing", 10))
+
+	_, err = adapter.Evaluate(ctx, "missing", featureflag.KindBool, featureflag.Target{})
+	require.ErrorIs(t, err, featureflag.ErrFlagNotFound)
+
+	_, err = featureflag.NewOpenFeatureProvider(nil)
+	require.ErrorIs(t, err, featureflag.ErrNilProvider)
+}
@@ -0,0 +1,90 @@
+package featureflag
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/andyle182810/gframework/httpclient"
+	"github.com/andyle182810/gframework/middleware"
+	"github.com/labstack/echo/v5"
+)
+
+type middlewareConfig struct {
+	overrides bool
+}
+
+type MiddlewareOption func(*middlewareConfig)
+
+// WithHeaderOverrides lets callers force flag values with the X-Feature-Flags header, e.g.
+// "new-checkout=true,page-size=50"; a flag without a value is set to true. Only enable it where
+// callers are trusted, such as internal services or staging.
+func WithHeaderOverrides() MiddlewareOption {
+	return func(c *middlewareConfig) {
+		c.overrides = true
+	}
+}
+
+// Middleware makes FromContext(ctx.Request().Context()) return client and targets flag evaluations at
+// the tenant and JWT subject of the request. The target is resolved on first evaluation, so it does
+// not matter whether the tenant and JWT middleware run before or after it.
+func Middleware(client *Client, opts ...MiddlewareOption) echo.MiddlewareFunc {
+	cfg := middlewareConfig{overrides: false}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx *echo.Context) error {
+			req := ctx.Request()
+
+			var overrides map[string]string
+			if cfg.overrides {
+				overrides = ParseOverrides(req.Header.Get(httpclient.HeaderXFeatureFlags))
+			}
+
+			target := sync.OnceValue(func() Target {
+				resolved := Target{
+					UserID:    "",
+					TenantID:  middleware.GetTenantID(ctx),
+					Overrides: overrides,
+				}
+
+				if userID, err := middleware.CurrentUserID(ctx); err == nil {
+					resolved.UserID = userID
+				}
+
+				return resolved
+			})
+
+			reqCtx := contextWithTargetFunc(ContextWithClient(req.Context(), client), target)
+			ctx.SetRequest(req.WithContext(reqCtx))
+
+			return next(ctx)
+		}
+	}
+}
+
+// ParseOverrides parses an X-Feature-Flags header value such as "new-checkout=true,theme=dark,beta".
+func ParseOverrides(header string) map[string]string {
+	if strings.TrimSpace(header) == "" {
+		return nil
+	}
+
+	overrides := make(map[string]string)
+
+	for part := range strings.SplitSeq(header, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if key = strings.TrimSpace(key); key == "" {
+			continue
+		}
+
+		if !found {
+			value = "true"
+		}
+
+		overrides[key] = strings.TrimSpace(value)
+	}
+
+	return overrides
+}
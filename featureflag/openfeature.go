@@ -0,0 +1,82 @@
+package featureflag
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/open-feature/go-sdk/openfeature"
+)
+
+const (
+	openFeatureUserIDAttribute   = "userId"
+	openFeatureTenantIDAttribute = "tenantId"
+)
+
+// OpenFeatureProvider evaluates flags with an OpenFeature client, so any OpenFeature provider (flagd,
+// LaunchDarkly, Unleash, ...) can back a Client. The targeting key is Target.Key(); the user and tenant
+// are passed as the userId and tenantId attributes.
+type OpenFeatureProvider struct {
+	client openfeature.IClient
+}
+
+func NewOpenFeatureProvider(client openfeature.IClient) (*OpenFeatureProvider, error) {
+	if client == nil {
+		return nil, ErrNilProvider
+	}
+
+	return &OpenFeatureProvider{client: client}, nil
+}
+
+func (p *OpenFeatureProvider) Evaluate(ctx context.Context, key string, kind Kind, target Target) (any, error) {
+	evalCtx := openFeatureContext(target)
+
+	var (
+		value   any
+		details openfeature.EvaluationDetails
+		err     error
+	)
+
+	switch kind {
+	case KindBool:
+		var result openfeature.BooleanEvaluationDetails
+
+		result, err = p.client.BooleanValueDetails(ctx, key, false, evalCtx)
+		value, details = result.Value, result.EvaluationDetails
+	case KindString:
+		var result openfeature.StringEvaluationDetails
+
+		result, err = p.client.StringValueDetails(ctx, key, "", evalCtx)
+		value, details = result.Value, result.EvaluationDetails
+	case KindInt:
+		var result openfeature.IntEvaluationDetails
+
+		result, err = p.client.IntValueDetails(ctx, key, 0, evalCtx)
+		value, details = result.Value, result.EvaluationDetails
+	default:
+		return nil, fmt.Errorf("%w: unknown kind %q", ErrTypeMismatch, kind)
+	}
+
+	if err != nil {
+		if details.ErrorCode == openfeature.FlagNotFoundCode {
+			return nil, fmt.Errorf("%w: %s", ErrFlagNotFound, key)
+		}
+
+		return nil, fmt.Errorf("featureflag: openfeature evaluation of %s failed: %w", key, err)
+	}
+
+	return value, nil
+}
+
+func openFeatureContext(target Target) openfeature.EvaluationContext {
+	attributes := make(map[string]any, 2) //nolint:mnd
+
+	if target.UserID != "" {
+		attributes[openFeatureUserIDAttribute] = target.UserID
+	}
+
+	if target.TenantID != "" {
+		attributes[openFeatureTenantIDAttribute] = target.TenantID
+	}
+
+	return openfeature.NewEvaluationContext(target.Key(), attributes)
+}
@@ -0,0 +1,88 @@
+package featureflag
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"gopkg.in/yaml.v3"
+)
+
+// StaticProvider serves flags from memory, loaded from a map or a file.
+type StaticProvider struct {
+	flags atomic.Pointer[map[string]Flag]
+}
+
+func NewStaticProvider(flags map[string]Flag) *StaticProvider {
+	//nolint:exhaustruct
+	p := &StaticProvider{}
+	p.Set(flags)
+
+	return p
+}
+
+// LoadFile reads flags from a YAML or JSON file mapping flag names to definitions.
+func LoadFile(path string) (*StaticProvider, error) {
+	flags, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewStaticProvider(flags), nil
+}
+
+// Reload replaces the flags with the contents of the file, e.g. on SIGHUP. The flags are unchanged
+// when the file cannot be read.
+func (p *StaticProvider) Reload(path string) error {
+	flags, err := readFile(path)
+	if err != nil {
+		return err
+	}
+
+	p.Set(flags)
+
+	return nil
+}
+
+// Set replaces all flags.
+func (p *StaticProvider) Set(flags map[string]Flag) {
+	copied := make(map[string]Flag, len(flags))
+	for key, flag := range flags {
+		copied[key] = flag
+	}
+
+	p.flags.Store(&copied)
+}
+
+func (p *StaticProvider) Evaluate(_ context.Context, key string, _ Kind, target Target) (any, error) {
+	flag, ok := (*p.flags.Load())[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrFlagNotFound, key)
+	}
+
+	return flag.Resolve(key, target), nil
+}
+
+func readFile(path string) (map[string]Flag, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".json":
+	default:
+		return nil, fmt.Errorf("featureflag: unsupported file %s, expected .yaml, .yml or .json", path)
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return nil, fmt.Errorf("featureflag: failed to read %s: %w", path, err)
+	}
+
+	// JSON is a subset of YAML, so one decoder reads both.
+	var flags map[string]Flag
+	if err := yaml.Unmarshal(data, &flags); err != nil {
+		return nil, fmt.Errorf("featureflag: failed to parse %s: %w", path, err)
+	}
+
+	return flags, nil
+}
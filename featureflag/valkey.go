@@ -0,0 +1,265 @@
+package featureflag
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/andyle182810/gframework/valkey"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultValkeyKey             = "featureflags"
+	defaultValkeyRefreshInterval = time.Minute
+)
+
+var ErrFlagsNotLoaded = errors.New("featureflag: failed to load flags from valkey")
+
+type ValkeyOption func(*ValkeyProvider)
+
+// WithValkeyKey sets the hash holding the flags; updates are published on "<key>:updates". It defaults
+// to "featureflags".
+func WithValkeyKey(key string) ValkeyOption {
+	return func(p *ValkeyProvider) {
+		if key != "" {
+			p.key = key
+		}
+	}
+}
+
+// WithValkeyRefreshInterval sets how often all flags are reloaded, to pick up updates published while
+// the subscriber was disconnected. It defaults to one minute.
+func WithValkeyRefreshInterval(d time.Duration) ValkeyOption {
+	return func(p *ValkeyProvider) {
+		if d > 0 {
+			p.refreshInterval = d
+		}
+	}
+}
+
+func WithValkeyPubSubOptions(opts ...valkey.PubSubOption) ValkeyOption {
+	return func(p *ValkeyProvider) {
+		p.pubSubOpts = append(p.pubSubOpts, opts...)
+	}
+}
+
+// ValkeyProvider serves flags stored as JSON in a Valkey hash, one field per flag, from memory. It is
+// a runner service: Start loads the flags and keeps them current from the pub/sub messages SetFlag and
+// DeleteFlag publish, so every replica sees a change within moments.
+type ValkeyProvider struct {
+	*valkey.PubSubSubscriber
+
+	client          redis.UniversalClient
+	key             string
+	refreshInterval time.Duration
+	pubSubOpts      []valkey.PubSubOption
+	flags           atomic.Pointer[map[string]Flag]
+	mu              sync.Mutex // serializes reloads so an older snapshot never replaces a newer one
+	ready           chan struct{}
+	readyOnce       sync.Once
+}
+
+func NewValkeyProvider(client redis.UniversalClient, opts ...ValkeyOption) (*ValkeyProvider, error) {
+	if client == nil {
+		return nil, valkey.ErrValkeyPoolNil
+	}
+
+	//nolint:exhaustruct
+	provider := &ValkeyProvider{
+		client:          client,
+		key:             defaultValkeyKey,
+		refreshInterval: defaultValkeyRefreshInterval,
+		ready:           make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(provider)
+	}
+
+	provider.flags.Store(&map[string]Flag{})
+
+	pubSubOpts := append([]valkey.PubSubOption{valkey.WithPubSubName("featureflag-valkey")}, provider.pubSubOpts...)
+
+	subscriber, err := valkey.NewPubSubSubscriber(client, []string{provider.channel()},
+		func(ctx context.Context, _ string, payload []byte) error {
+			return provider.handleUpdate(ctx, string(payload))
+		}, pubSubOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	provider.PubSubSubscriber = subscriber
+
+	return provider, nil
+}
+
+// Start loads all flags and applies updates until ctx is cancelled or Stop is called.
+func (p *ValkeyProvider) Start(ctx context.Context) error {
+	if err := p.Refresh(ctx); err != nil {
+		return err
+	}
+
+	p.readyOnce.Do(func() { close(p.ready) })
+
+	refreshCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go p.refreshLoop(refreshCtx)
+
+	return p.PubSubSubscriber.Start(ctx)
+}
+
+// Ready is closed once the flags have been loaded, so runner starts core services after it.
+func (p *ValkeyProvider) Ready() <-chan struct{} {
+	return p.ready
+}
+
+// Refresh reloads all flags.
+func (p *ValkeyProvider) Refresh(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	raw, err := p.client.HGetAll(ctx, p.key).Result()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFlagsNotLoaded, err)
+	}
+
+	flags := make(map[string]Flag, len(raw))
+
+	for name, data := range raw {
+		flag, err := decodeFlag(data)
+		if err != nil {
+			log.Error().Str("source", "gframework").Err(err).Str("flag", name).Msg("Skipping invalid feature flag")
+
+			continue
+		}
+
+		flags[name] = flag
+	}
+
+	p.flags.Store(&flags)
+
+	return nil
+}
+
+func (p *ValkeyProvider) Evaluate(_ context.Context, key string, _ Kind, target Target) (any, error) {
+	flag, ok := (*p.flags.Load())[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrFlagNotFound, key)
+	}
+
+	return flag.Resolve(key, target), nil
+}
+
+// SetFlag stores the flag and notifies every provider watching the same key.
+func (p *ValkeyProvider) SetFlag(ctx context.Context, name string, flag Flag) error {
+	data, err := json.Marshal(flag)
+	if err != nil {
+		return fmt.Errorf("featureflag: failed to encode flag %s: %w", name, err)
+	}
+
+	_, err = p.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, p.key, name, data)
+		pipe.Publish(ctx, p.channel(), name)
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("featureflag: failed to set flag %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// DeleteFlag removes the flag and notifies every provider watching the same key.
+func (p *ValkeyProvider) DeleteFlag(ctx context.Context, name string) error {
+	_, err := p.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HDel(ctx, p.key, name)
+		pipe.Publish(ctx, p.channel(), name)
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("featureflag: failed to delete flag %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func (p *ValkeyProvider) channel() string {
+	return p.key + ":updates"
+}
+
+// handleUpdate reloads the flag named in the message, or all flags for an empty message.
+func (p *ValkeyProvider) handleUpdate(ctx context.Context, name string) error {
+	if name == "" {
+		return p.Refresh(ctx)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	data, err := p.client.HGet(ctx, p.key, name).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return fmt.Errorf("featureflag: failed to load flag %s: %w", name, err)
+	}
+
+	current := *p.flags.Load()
+	flags := make(map[string]Flag, len(current))
+
+	for key, flag := range current {
+		flags[key] = flag
+	}
+
+	if errors.Is(err, redis.Nil) {
+		delete(flags, name)
+	} else {
+		flag, err := decodeFlag(data)
+		if err != nil {
+			return fmt.Errorf("featureflag: invalid flag %s: %w", name, err)
+		}
+
+		flags[name] = flag
+	}
+
+	p.flags.Store(&flags)
+
+	return nil
+}
+
+func (p *ValkeyProvider) refreshLoop(ctx context.Context) {
+	ticker := time.NewTicker(p.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := p.Refresh(ctx); err != nil && ctx.Err() == nil {
+				log.Warn().Str("source", "gframework").Err(err).Str("key", p.key).Msg("Failed to refresh feature flags")
+			}
+		}
+	}
+}
+
+// decodeFlag keeps numbers as json.Number so integers are not turned into floats.
+func decodeFlag(data string) (Flag, error) {
+	var flag Flag
+
+	decoder := json.NewDecoder(bytes.NewReader([]byte(data)))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&flag); err != nil {
+		return Flag{Value: nil, Rules: nil}, fmt.Errorf("featureflag: failed to decode flag: %w", err)
+	}
+
+	return flag, nil
+}
@@ -0,0 +1,69 @@
+//nolint:exhaustruct
+package featureflag_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/featureflag"
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/andyle182810/gframework/valkey"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValkeyProvider(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	client := testutil.SetupValkey(t)
+
+	writer, err := featureflag.NewValkeyProvider(client, featureflag.WithValkeyKey("flags:test"))
+	require.NoError(t, err)
+	require.NoError(t, writer.SetFlag(ctx, "page-size", featureflag.Flag{Value: 25}))
+
+	provider, err := featureflag.NewValkeyProvider(client,
+		featureflag.WithValkeyKey("flags:test"),
+		featureflag.WithValkeyRefreshInterval(time.Hour),
+	)
+	require.NoError(t, err)
+
+	flags, err := featureflag.New(provider)
+	require.NoError(t, err)
+
+	errCh := make(chan error, 1)
+
+	go func() { errCh <- provider.Start(context.Background()) }()
+
+	select {
+	case <-provider.Ready():
+	case <-time.After(5 * time.Second):
+		t.Fatal("flags were not loaded")
+	}
+
+	require.Equal(t, 25, flags.Int(ctx, "page-size", 10))
+	require.Eventually(t, provider.IsHealthy, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, writer.SetFlag(ctx, "beta", featureflag.Flag{
+		Value: false,
+		Rules: []featureflag.Rule{{Tenants: []string{"acme"}, Value: true}},
+	}))
+
+	acme := featureflag.ContextWithTarget(ctx, featureflag.Target{TenantID: "acme"})
+	require.Eventually(t, func() bool { return flags.Bool(acme, "beta", false) }, 5*time.Second, 10*time.Millisecond)
+	require.False(t, flags.Bool(ctx, "beta", true))
+
+	require.NoError(t, writer.DeleteFlag(ctx, "page-size"))
+	require.Eventually(t, func() bool { return flags.Int(ctx, "page-size", 10) == 10 }, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, provider.Stop())
+	require.NoError(t, <-errCh)
+}
+
+func TestNewValkeyProvider_RequiresClient(t *testing.T) {
+	t.Parallel()
+
+	_, err := featureflag.NewValkeyProvider(nil)
+
+	require.ErrorIs(t, err, valkey.ErrValkeyPoolNil)
+}
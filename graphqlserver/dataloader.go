@@ -0,0 +1,295 @@
+package graphqlserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/andyle182810/gframework/cache"
+	"github.com/andyle182810/gframework/logutil"
+)
+
+const (
+	defaultLoaderWait     = 2 * time.Millisecond
+	defaultLoaderMaxBatch = 100
+)
+
+var (
+	ErrNotFound           = errors.New("graphqlserver: not found")
+	ErrLoaderNotInstalled = errors.New("graphqlserver: loader is not installed in the context")
+)
+
+// BatchFunc loads the values of keys in one call, e.g. with a WHERE id = ANY($1) query. Keys missing
+// from the map are reported as ErrNotFound; an error fails every key of the batch.
+type BatchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]*V, error)
+
+type LoaderOption func(*loaderOptions)
+
+type loaderOptions struct {
+	wait     time.Duration
+	maxBatch int
+}
+
+// WithWait sets how long a loader collects keys before it calls the batch function. It defaults to 2ms.
+func WithWait(d time.Duration) LoaderOption {
+	return func(o *loaderOptions) {
+		if d > 0 {
+			o.wait = d
+		}
+	}
+}
+
+// WithMaxBatch sets how many keys a batch holds at most; a full batch is loaded without waiting. It
+// defaults to 100.
+func WithMaxBatch(n int) LoaderOption {
+	return func(o *loaderOptions) {
+		if n > 0 {
+			o.maxBatch = n
+		}
+	}
+}
+
+// Loader collects the keys resolvers load concurrently and loads them with one BatchFunc call,
+// avoiding N+1 queries. Results, including errors, are memoized for the life of the loader, so create
+// one loader per request. Callers of the same key share the value; do not modify it.
+type Loader[K comparable, V any] struct {
+	batch   BatchFunc[K, V]
+	cache   *cache.Cache[K, V]
+	options loaderOptions
+
+	mu      sync.Mutex
+	results map[K]*loadResult[V]
+	pending *pendingBatch[K, V]
+}
+
+type loadResult[V any] struct {
+	done  chan struct{}
+	value *V
+	err   error
+}
+
+type pendingBatch[K comparable, V any] struct {
+	ctx     context.Context //nolint:containedctx
+	keys    []K
+	results []*loadResult[V]
+	timer   *time.Timer
+}
+
+func NewLoader[K comparable, V any](batch BatchFunc[K, V], opts ...LoaderOption) *Loader[K, V] {
+	return newLoader(batch, nil, opts)
+}
+
+// NewCachedLoader returns a loader that reads through c: keys found in the cache are not batch loaded,
+// and batch loaded values are stored in it. Not-found results are not cached.
+func NewCachedLoader[K comparable, V any](
+	batch BatchFunc[K, V],
+	c *cache.Cache[K, V],
+	opts ...LoaderOption,
+) *Loader[K, V] {
+	return newLoader(batch, c, opts)
+}
+
+func newLoader[K comparable, V any](batch BatchFunc[K, V], c *cache.Cache[K, V], opts []LoaderOption) *Loader[K, V] {
+	options := loaderOptions{
+		wait:     defaultLoaderWait,
+		maxBatch: defaultLoaderMaxBatch,
+	}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return &Loader[K, V]{ //nolint:exhaustruct
+		batch:   batch,
+		cache:   c,
+		options: options,
+		results: make(map[K]*loadResult[V]),
+	}
+}
+
+// Load returns the value of key, waiting for the batch it is loaded in.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (*V, error) {
+	l.mu.Lock()
+
+	result, ok := l.results[key]
+	if !ok {
+		result = &loadResult[V]{done: make(chan struct{}), value: nil, err: nil}
+		l.results[key] = result
+	}
+
+	l.mu.Unlock()
+
+	if !ok {
+		l.start(ctx, key, result)
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-result.done:
+		return result.value, result.err
+	}
+}
+
+// LoadMany returns the values of keys in order, loaded in as few batches as possible. It fails with the
+// error of the first key that failed.
+func (l *Loader[K, V]) LoadMany(ctx context.Context, keys []K) ([]*V, error) {
+	values := make([]*V, len(keys))
+	errs := make([]error, len(keys))
+
+	var wg sync.WaitGroup
+
+	for i, key := range keys {
+		wg.Go(func() {
+			values[i], errs[i] = l.Load(ctx, key)
+		})
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
+
+// Clear forgets the memoized result of key, e.g. after a mutation changed it. It does not touch the
+// cache.
+func (l *Loader[K, V]) Clear(key K) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.results, key)
+}
+
+func (l *Loader[K, V]) start(ctx context.Context, key K, result *loadResult[V]) {
+	if l.cache != nil {
+		value, err := l.cache.Get(ctx, key)
+		if err == nil {
+			result.value = value
+			close(result.done)
+
+			return
+		}
+
+		if !errors.Is(err, cache.ErrKeyNotFound) {
+			logutil.FromContext(ctx).Warn().
+				Str("source", "gframework").
+				Err(err).
+				Msg("Failed to read the loader cache")
+		}
+	}
+
+	l.enqueue(ctx, key, result)
+}
+
+func (l *Loader[K, V]) enqueue(ctx context.Context, key K, result *loadResult[V]) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.pending == nil {
+		// The batch outlives the first caller's cancellation; it serves every caller of the batch.
+		batch := &pendingBatch[K, V]{ctx: context.WithoutCancel(ctx), keys: nil, results: nil, timer: nil}
+		batch.timer = time.AfterFunc(l.options.wait, func() {
+			l.dispatch(batch)
+		})
+		l.pending = batch
+	}
+
+	batch := l.pending
+	batch.keys = append(batch.keys, key)
+	batch.results = append(batch.results, result)
+
+	if len(batch.keys) >= l.options.maxBatch {
+		l.pending = nil
+
+		if batch.timer.Stop() {
+			go l.dispatch(batch)
+		}
+	}
+}
+
+func (l *Loader[K, V]) dispatch(batch *pendingBatch[K, V]) {
+	l.mu.Lock()
+	if l.pending == batch {
+		l.pending = nil
+	}
+	l.mu.Unlock()
+
+	values, err := l.batch(batch.ctx, batch.keys)
+
+	for i, key := range batch.keys {
+		result := batch.results[i]
+
+		switch value := values[key]; {
+		case err != nil:
+			result.err = err
+		case value == nil:
+			result.err = fmt.Errorf("%w: %v", ErrNotFound, key)
+		default:
+			result.value = value
+			l.store(batch.ctx, key, value)
+		}
+
+		close(result.done)
+	}
+}
+
+func (l *Loader[K, V]) store(ctx context.Context, key K, value *V) {
+	if l.cache == nil {
+		return
+	}
+
+	if err := l.cache.Set(ctx, key, value); err != nil {
+		logutil.FromContext(ctx).Warn().
+			Str("source", "gframework").
+			Err(err).
+			Msg("Failed to write the loader cache")
+	}
+}
+
+// LoaderKey identifies a loader in the request context. Declare one per loader as a package variable,
+// install a new loader per request in Config.Loaders and load through the key in the resolvers:
+//
+//	var userLoader = graphqlserver.NewLoaderKey[string, User]("users")
+//
+//	cfg.Loaders = func(ctx context.Context) context.Context {
+//	    return userLoader.Install(ctx, graphqlserver.NewCachedLoader(repo.FindUsers, usersCache))
+//	}
+//
+//	func (r *orderResolver) Customer(ctx context.Context, order *Order) (*User, error) {
+//	    return userLoader.Load(ctx, order.CustomerID)
+//	}
+type LoaderKey[K comparable, V any] struct {
+	name string
+}
+
+func NewLoaderKey[K comparable, V any](name string) *LoaderKey[K, V] {
+	return &LoaderKey[K, V]{name: name}
+}
+
+func (k *LoaderKey[K, V]) Install(ctx context.Context, loader *Loader[K, V]) context.Context {
+	return context.WithValue(ctx, k, loader)
+}
+
+// From returns the loader installed in ctx, or nil.
+func (k *LoaderKey[K, V]) From(ctx context.Context) *Loader[K, V] {
+	loader, _ := ctx.Value(k).(*Loader[K, V])
+
+	return loader
+}
+
+// Load loads key with the loader installed in ctx.
+func (k *LoaderKey[K, V]) Load(ctx context.Context, key K) (*V, error) {
+	loader := k.From(ctx)
+	if loader == nil {
+		return nil, fmt.Errorf("%w: %s", ErrLoaderNotInstalled, k.name)
+	}
+
+	return loader.Load(ctx, key)
+}
@@ -0,0 +1,156 @@
+//nolint:exhaustruct
+package graphqlserver_test
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/cache"
+	"github.com/andyle182810/gframework/graphqlserver"
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+type user struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// userStore records the batches it is asked for and knows the users with IDs up to 100.
+type userStore struct {
+	mu      sync.Mutex
+	batches [][]int
+}
+
+func (s *userStore) findUsers(_ context.Context, ids []int) (map[int]*user, error) {
+	s.mu.Lock()
+	s.batches = append(s.batches, slices.Sorted(slices.Values(ids)))
+	s.mu.Unlock()
+
+	users := make(map[int]*user, len(ids))
+
+	for _, id := range ids {
+		if id <= 100 {
+			users[id] = &user{ID: id, Name: "user-" + strconv.Itoa(id)}
+		}
+	}
+
+	return users, nil
+}
+
+func (s *userStore) recordedBatches() [][]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.batches)
+}
+
+func TestLoader_BatchesConcurrentLoads(t *testing.T) {
+	t.Parallel()
+
+	store := &userStore{}
+	loader := graphqlserver.NewLoader(store.findUsers, graphqlserver.WithWait(20*time.Millisecond))
+
+	users, err := loader.LoadMany(t.Context(), []int{3, 1, 2, 1})
+	require.NoError(t, err)
+	require.Equal(t, []string{"user-3", "user-1", "user-2", "user-1"},
+		[]string{users[0].Name, users[1].Name, users[2].Name, users[3].Name})
+	require.Equal(t, [][]int{{1, 2, 3}}, store.recordedBatches())
+
+	again, err := loader.Load(t.Context(), 2)
+	require.NoError(t, err)
+	require.Same(t, users[2], again, "results are memoized")
+	require.Len(t, store.recordedBatches(), 1)
+
+	loader.Clear(2)
+
+	_, err = loader.Load(t.Context(), 2)
+	require.NoError(t, err)
+	require.Equal(t, [][]int{{1, 2, 3}, {2}}, store.recordedBatches())
+}
+
+func TestLoader_SplitsFullBatches(t *testing.T) {
+	t.Parallel()
+
+	store := &userStore{}
+	loader := graphqlserver.NewLoader(store.findUsers,
+		graphqlserver.WithWait(time.Second),
+		graphqlserver.WithMaxBatch(2),
+	)
+
+	started := time.Now()
+
+	_, err := loader.LoadMany(t.Context(), []int{1, 2, 3, 4})
+	require.NoError(t, err)
+	require.Less(t, time.Since(started), time.Second, "full batches are loaded without waiting")
+
+	batches := store.recordedBatches()
+	require.Len(t, batches, 2)
+	require.Len(t, batches[0], 2)
+	require.ElementsMatch(t, []int{1, 2, 3, 4}, slices.Concat(batches...))
+}
+
+func TestLoader_ReportsMissingKeysAndErrors(t *testing.T) {
+	t.Parallel()
+
+	store := &userStore{}
+	loader := graphqlserver.NewLoader(store.findUsers)
+
+	_, err := loader.Load(t.Context(), 404)
+	require.ErrorIs(t, err, graphqlserver.ErrNotFound)
+
+	_, err = loader.LoadMany(t.Context(), []int{1, 404})
+	require.ErrorIs(t, err, graphqlserver.ErrNotFound)
+
+	failing := graphqlserver.NewLoader(func(context.Context, []int) (map[int]*user, error) {
+		return nil, errDatabase
+	})
+
+	_, err = failing.Load(t.Context(), 1)
+	require.ErrorIs(t, err, errDatabase)
+}
+
+func TestLoader_ReadsThroughCache(t *testing.T) {
+	t.Parallel()
+
+	client := testutil.NewFakeValkey(t)
+	users := cache.New[int, user](client, "users", time.Minute, cache.NewIntKeyEncoder())
+	require.NoError(t, users.Set(t.Context(), 1, &user{ID: 1, Name: "cached"}))
+
+	store := &userStore{}
+	loader := graphqlserver.NewCachedLoader(store.findUsers, users)
+
+	loaded, err := loader.LoadMany(t.Context(), []int{1, 2})
+	require.NoError(t, err)
+	require.Equal(t, "cached", loaded[0].Name)
+	require.Equal(t, "user-2", loaded[1].Name)
+	require.Equal(t, [][]int{{2}}, store.recordedBatches())
+
+	stored, err := users.Get(t.Context(), 2)
+	require.NoError(t, err)
+	require.Equal(t, "user-2", stored.Name)
+}
+
+func TestLoaderKey(t *testing.T) {
+	t.Parallel()
+
+	key := graphqlserver.NewLoaderKey[int, user]("users")
+
+	_, err := key.Load(t.Context(), 1)
+	require.ErrorIs(t, err, graphqlserver.ErrLoaderNotInstalled)
+	require.Nil(t, key.From(t.Context()))
+
+	store := &userStore{}
+	ctx := key.Install(t.Context(), graphqlserver.NewLoader(store.findUsers))
+
+	loaded, err := key.Load(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, "user-1", loaded.Name)
+
+	other := graphqlserver.NewLoaderKey[int, user]("users")
+	require.Nil(t, other.From(ctx), "keys are distinct even with the same name")
+}
@@ -0,0 +1,141 @@
+package graphqlserver
+
+import (
+	"context"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	instrumentationName = "github.com/andyle182810/gframework/graphqlserver"
+	// ErrCodeDepthLimit is the extensions.code of errors of operations rejected by DepthLimit.
+	ErrCodeDepthLimit = "DEPTH_LIMIT_EXCEEDED"
+)
+
+// DepthLimit is a gqlgen extension that rejects operations nesting fields deeper than its value, e.g.
+// DepthLimit(8). Fields of fragments count at the depth they are spread at; introspection fields are not
+// counted.
+type DepthLimit int
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = DepthLimit(0)
+
+func (DepthLimit) ExtensionName() string {
+	return "DepthLimit"
+}
+
+func (DepthLimit) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (d DepthLimit) MutateOperationContext(_ context.Context, opCtx *graphql.OperationContext) *gqlerror.Error {
+	if opCtx.Operation == nil {
+		return nil
+	}
+
+	depth := selectionDepth(opCtx.Operation.SelectionSet, map[string]bool{})
+	if depth <= int(d) {
+		return nil
+	}
+
+	err := gqlerror.Errorf("operation has depth %d, which exceeds the limit of %d", depth, int(d))
+	errcode.Set(err, ErrCodeDepthLimit)
+
+	return err
+}
+
+// selectionDepth returns the number of nested field levels of set. visiting guards against fragment
+// cycles, which validation rejects but which must not hang the server before it does.
+func selectionDepth(set ast.SelectionSet, visiting map[string]bool) int {
+	deepest := 0
+
+	for _, selection := range set {
+		depth := 0
+
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(selection.Name, "__") {
+				continue
+			}
+
+			depth = 1 + selectionDepth(selection.SelectionSet, visiting)
+		case *ast.InlineFragment:
+			depth = selectionDepth(selection.SelectionSet, visiting)
+		case *ast.FragmentSpread:
+			if selection.Definition == nil || visiting[selection.Name] {
+				continue
+			}
+
+			visiting[selection.Name] = true
+			depth = selectionDepth(selection.Definition.SelectionSet, visiting)
+			delete(visiting, selection.Name)
+		}
+
+		deepest = max(deepest, depth)
+	}
+
+	return deepest
+}
+
+// Tracer is a gqlgen extension that starts a span per operation, named after the operation type and
+// name, e.g. "query GetOrder", under the span of tracing.Middleware. Operations that return errors are
+// marked as failed. Without a tracer provider, e.g. one installed by tracing.New, the spans are no-ops.
+type Tracer struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+} = Tracer{}
+
+func (Tracer) ExtensionName() string {
+	return "Tracer"
+}
+
+func (Tracer) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (Tracer) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if !graphql.HasOperationContext(ctx) {
+		return next(ctx)
+	}
+
+	opCtx := graphql.GetOperationContext(ctx)
+
+	operationType := "operation"
+	if opCtx.Operation != nil {
+		operationType = string(opCtx.Operation.Operation)
+	}
+
+	spanName := operationType
+	if opCtx.OperationName != "" {
+		spanName += " " + opCtx.OperationName
+	}
+
+	ctx, span := otel.Tracer(instrumentationName).Start(ctx, spanName,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(
+			semconv.GraphQLOperationTypeKey.String(operationType),
+			semconv.GraphQLOperationName(opCtx.OperationName),
+		),
+	)
+	defer span.End()
+
+	response := next(ctx)
+
+	if response != nil && len(response.Errors) > 0 {
+		span.SetStatus(codes.Error, response.Errors.Error())
+	}
+
+	return response
+}
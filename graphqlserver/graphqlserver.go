@@ -0,0 +1,232 @@
+// Package graphqlserver serves a gqlgen executable schema from an httpserver.Server behind the framework
+// middleware.
+//
+// Mount builds a gqlgen handler with POST, GET and multipart transports, a query cache, complexity and
+// depth limits and operation spans, and registers it on the server:
+//
+//	srv := httpserver.New(&httpserver.Config{Port: 8080})
+//	jwtConfig := middleware.DefaultJWTConfig()
+//	jwtConfig.Keyfunc = keys
+//
+//	graphqlserver.Mount(srv, generated.NewExecutableSchema(generated.Config{Resolvers: resolver}),
+//	    graphqlserver.Config{
+//	        JWT:             &jwtConfig,
+//	        ComplexityLimit: 500,
+//	        MaxDepth:        8,
+//	        Loaders:         installLoaders,
+//	    },
+//	)
+//
+// Every request goes through tracing.Middleware, middleware.RequestID, which generates missing request
+// IDs, and, when Config.JWT is set, middleware.JWTWithConfig. Resolvers reach the Echo context, e.g. for
+// the claims, with EchoContext:
+//
+//	userID, err := middleware.CurrentUserID(graphqlserver.EchoContext(ctx))
+//
+// Loader batches the lookups of a request into one call and optionally reads through a cache.Cache; see
+// NewLoader and LoaderKey for wiring loaders per request.
+package graphqlserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/andyle182810/gframework/httpserver"
+	"github.com/andyle182810/gframework/logutil"
+	"github.com/andyle182810/gframework/middleware"
+	"github.com/andyle182810/gframework/tracing"
+	"github.com/labstack/echo/v5"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const (
+	DefaultPath            = "/graphql"
+	DefaultComplexityLimit = 1000
+	DefaultMaxDepth        = 15
+	defaultQueryCacheSize  = 1000
+	defaultAPQCacheSize    = 100
+)
+
+var ErrInternal = errors.New("internal server error")
+
+type Config struct {
+	// Path is the route of the endpoint. It defaults to DefaultPath.
+	Path string
+	// ComplexityLimit rejects operations whose complexity, as computed by the generated complexity
+	// functions, exceeds it. It defaults to DefaultComplexityLimit; a negative value disables the limit.
+	ComplexityLimit int
+	// MaxDepth rejects operations that nest selections deeper than it. It defaults to DefaultMaxDepth;
+	// a negative value disables the limit.
+	MaxDepth int
+	// Introspection enables the __schema and __type queries, e.g. for GraphiQL. Keep it off in
+	// production unless the schema is public.
+	Introspection bool
+	// QueryCacheSize is the number of parsed and validated queries kept in memory.
+	QueryCacheSize int
+	// JWT authenticates the requests when set; without it the endpoint is public.
+	JWT *middleware.JWTConfig
+	// Middleware runs after the framework middleware, e.g. rate limiting or tenant resolution.
+	Middleware []echo.MiddlewareFunc
+	// Loaders returns the request context with the loaders of the request installed, see LoaderKey.
+	Loaders func(ctx context.Context) context.Context
+}
+
+func (c *Config) applyDefaults() {
+	if c.Path == "" {
+		c.Path = DefaultPath
+	}
+
+	if c.ComplexityLimit == 0 {
+		c.ComplexityLimit = DefaultComplexityLimit
+	}
+
+	if c.MaxDepth == 0 {
+		c.MaxDepth = DefaultMaxDepth
+	}
+
+	if c.QueryCacheSize <= 0 {
+		c.QueryCacheSize = defaultQueryCacheSize
+	}
+}
+
+// NewHandler returns a gqlgen handler for schema configured with the transports, caches, limits and
+// extensions of cfg. Use it to mount the schema on another router; Mount also wires the middleware.
+func NewHandler(schema graphql.ExecutableSchema, cfg Config) *handler.Server {
+	cfg.applyDefaults()
+
+	srv := handler.New(schema)
+
+	srv.AddTransport(transport.Options{})       //nolint:exhaustruct
+	srv.AddTransport(transport.GET{})           //nolint:exhaustruct
+	srv.AddTransport(transport.POST{})          //nolint:exhaustruct
+	srv.AddTransport(transport.MultipartForm{}) //nolint:exhaustruct
+
+	srv.SetQueryCache(lru.New[*ast.QueryDocument](cfg.QueryCacheSize))
+	srv.SetErrorPresenter(presentError)
+	srv.SetRecoverFunc(recoverPanic)
+
+	if cfg.Introspection {
+		srv.Use(extension.Introspection{})
+	}
+
+	srv.Use(extension.AutomaticPersistedQuery{Cache: lru.New[string](defaultAPQCacheSize)})
+
+	if cfg.ComplexityLimit > 0 {
+		srv.Use(extension.FixedComplexityLimit(cfg.ComplexityLimit))
+	}
+
+	if cfg.MaxDepth > 0 {
+		srv.Use(DepthLimit(cfg.MaxDepth))
+	}
+
+	srv.Use(Tracer{})
+
+	return srv
+}
+
+// Mount registers the schema at cfg.Path of server for GET, POST and OPTIONS requests and returns the
+// handler, e.g. to add extensions with Use.
+func Mount(server *httpserver.Server, schema graphql.ExecutableSchema, cfg Config) *handler.Server {
+	cfg.applyDefaults()
+
+	srv := NewHandler(schema, cfg)
+
+	requestIDConfig := middleware.DefaultRequestIDConfig()
+	requestIDConfig.AutoGenerate = true
+
+	middlewares := []echo.MiddlewareFunc{tracing.Middleware(), middleware.RequestIDWithConfig(requestIDConfig)}
+
+	if cfg.JWT != nil {
+		middlewares = append(middlewares, middleware.JWTWithConfig(*cfg.JWT))
+	}
+
+	middlewares = append(middlewares, cfg.Middleware...)
+	middlewares = append(middlewares, withEchoContext(cfg.Loaders))
+
+	server.Root.Match(
+		[]string{http.MethodGet, http.MethodPost, http.MethodOptions},
+		cfg.Path,
+		echo.WrapHandler(srv),
+		middlewares...,
+	)
+
+	return srv
+}
+
+type echoContextKey struct{}
+
+// withEchoContext stores the Echo context in the request context, where gqlgen passes it on to the
+// resolvers, and installs the loaders of the request.
+func withEchoContext(loaders func(ctx context.Context) context.Context) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx *echo.Context) error {
+			req := ctx.Request()
+			reqCtx := context.WithValue(req.Context(), echoContextKey{}, ctx)
+
+			if loaders != nil {
+				reqCtx = loaders(reqCtx)
+			}
+
+			ctx.SetRequest(req.WithContext(reqCtx))
+
+			return next(ctx)
+		}
+	}
+}
+
+// EchoContext returns the Echo context of the request a resolver runs for, or nil outside of Mount.
+func EchoContext(ctx context.Context) *echo.Context {
+	ectx, _ := ctx.Value(echoContextKey{}).(*echo.Context)
+
+	return ectx
+}
+
+// presentError logs resolver errors and hides the ones that are not meant for clients. Errors created
+// with gqlerror, e.g. validation errors, and errors wrapping echo.HTTPError keep their message.
+func presentError(ctx context.Context, err error) *gqlerror.Error {
+	presented := graphql.DefaultErrorPresenter(ctx, err)
+
+	var (
+		gqlErr  *gqlerror.Error
+		httpErr *echo.HTTPError
+	)
+
+	switch {
+	case errors.As(err, &httpErr):
+		presented.Message = httpErr.Message
+
+		if presented.Extensions == nil {
+			presented.Extensions = map[string]any{}
+		}
+
+		presented.Extensions["status"] = httpErr.Code
+	case errors.As(err, &gqlErr) && gqlErr.Err == nil:
+	default:
+		logutil.FromContext(ctx).Error().
+			Str("source", "gframework").
+			Err(err).
+			Str("path", presented.Path.String()).
+			Msg("The GraphQL resolver has failed")
+
+		presented.Message = ErrInternal.Error()
+	}
+
+	return presented
+}
+
+func recoverPanic(ctx context.Context, recovered any) error {
+	logutil.FromContext(ctx).Error().
+		Str("source", "gframework").
+		Str("panic", fmt.Sprint(recovered)).
+		Msg("The GraphQL resolver has panicked")
+
+	return gqlerror.Errorf("%s", ErrInternal.Error())
+}
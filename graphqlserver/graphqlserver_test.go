@@ -0,0 +1,217 @@
+//nolint:exhaustruct
+package graphqlserver_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/andyle182810/gframework/graphqlserver"
+	"github.com/andyle182810/gframework/httpserver"
+	"github.com/andyle182810/gframework/middleware"
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const testSchema = `
+	type Query {
+		me: String!
+		user(id: ID!): User
+	}
+	type User {
+		name: String!
+		friends: [User!]!
+	}
+`
+
+var errDatabase = errors.New("connection refused")
+
+type gqlResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+// newSchema returns an executable schema that answers every query with the result of exec, standing in
+// for gqlgen generated code.
+func newSchema(exec func(ctx context.Context) (any, error)) graphql.ExecutableSchema {
+	schema := gqlparser.MustLoadSchema(&ast.Source{Input: testSchema})
+
+	return &graphql.ExecutableSchemaMock{
+		ExecFunc: func(ctx context.Context) graphql.ResponseHandler {
+			data, err := exec(ctx)
+			if err != nil {
+				graphql.AddError(ctx, err)
+
+				return graphql.OneShot(&graphql.Response{Data: []byte("null"), Errors: graphql.GetErrors(ctx)})
+			}
+
+			encoded, err := json.Marshal(data)
+			if err != nil {
+				panic(err)
+			}
+
+			return graphql.OneShot(&graphql.Response{Data: encoded})
+		},
+		SchemaFunc: func() *ast.Schema {
+			return schema
+		},
+		ComplexityFunc: func(_ context.Context, _, _ string, childComplexity int, _ map[string]any) (int, bool) {
+			return childComplexity + 1, true
+		},
+	}
+}
+
+func serve(t *testing.T, server *httpserver.Server, query string) (*httptest.ResponseRecorder, gqlResponse) {
+	t.Helper()
+
+	body, err := json.Marshal(map[string]string{"query": query})
+	require.NoError(t, err)
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodPost, graphqlserver.DefaultPath,
+		strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := httptest.NewRecorder()
+	server.Echo.ServeHTTP(rec, req)
+
+	var response gqlResponse
+	if rec.Code == http.StatusOK || rec.Code == http.StatusUnprocessableEntity {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	}
+
+	return rec, response
+}
+
+func TestMount_PassesEchoContextToResolvers(t *testing.T) {
+	t.Parallel()
+
+	server := httpserver.New(&httpserver.Config{})
+	graphqlserver.Mount(server, newSchema(func(ctx context.Context) (any, error) {
+		ectx := graphqlserver.EchoContext(ctx)
+		if ectx == nil {
+			return nil, errors.New("no echo context") //nolint:err113
+		}
+
+		return map[string]string{"me": middleware.GetRequestID(ectx)}, nil
+	}), graphqlserver.Config{})
+
+	rec, response := serve(t, server, "{ me }")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Empty(t, response.Errors)
+
+	requestID := rec.Header().Get(middleware.HeaderXRequestID)
+	require.NotEmpty(t, requestID, "a missing request ID is generated")
+	require.JSONEq(t, `{"me":"`+requestID+`"}`, string(response.Data))
+}
+
+func TestMount_AppliesMiddleware(t *testing.T) {
+	t.Parallel()
+
+	server := httpserver.New(&httpserver.Config{})
+	graphqlserver.Mount(server, newSchema(func(context.Context) (any, error) {
+		return map[string]string{"me": "alice"}, nil
+	}), graphqlserver.Config{
+		Path: "/api/graphql",
+		Middleware: []echo.MiddlewareFunc{func(next echo.HandlerFunc) echo.HandlerFunc {
+			return func(ctx *echo.Context) error {
+				if ctx.Request().Header.Get("X-Tenant-Id") == "" {
+					return echo.NewHTTPError(http.StatusForbidden, "tenant required")
+				}
+
+				return next(ctx)
+			}
+		}},
+	})
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/api/graphql?query={me}", nil)
+	rec := httptest.NewRecorder()
+	server.Echo.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusForbidden, rec.Code)
+
+	req.Header.Set("X-Tenant-Id", "acme")
+	rec = httptest.NewRecorder()
+	server.Echo.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"data":{"me":"alice"}}`, rec.Body.String())
+}
+
+func TestMount_RejectsMissingTokens(t *testing.T) {
+	t.Parallel()
+
+	jwtConfig := middleware.DefaultJWTConfig()
+
+	server := httpserver.New(&httpserver.Config{})
+	graphqlserver.Mount(server, newSchema(func(context.Context) (any, error) {
+		return map[string]string{"me": "alice"}, nil
+	}), graphqlserver.Config{JWT: &jwtConfig})
+
+	rec, _ := serve(t, server, "{ me }")
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestMount_EnforcesLimits(t *testing.T) {
+	t.Parallel()
+
+	schema := newSchema(func(context.Context) (any, error) {
+		return map[string]any{"user": nil}, nil
+	})
+
+	server := httpserver.New(&httpserver.Config{})
+	graphqlserver.Mount(server, schema, graphqlserver.Config{MaxDepth: 3, ComplexityLimit: 10})
+
+	_, response := serve(t, server, `{ user(id: 1) { friends { name } } }`)
+	require.Empty(t, response.Errors)
+
+	_, response = serve(t, server, `
+		query Deep { user(id: 1) { ...friends } }
+		fragment friends on User { friends { friends { name } } }
+	`)
+	require.Len(t, response.Errors, 1)
+	require.Equal(t, "operation has depth 4, which exceeds the limit of 3", response.Errors[0].Message)
+	require.Equal(t, graphqlserver.ErrCodeDepthLimit, response.Errors[0].Extensions["code"])
+
+	_, response = serve(t, server, `{ me a: me b: me c: me d: me e: me f: me g: me h: me i: me j: me }`)
+	require.Len(t, response.Errors, 1)
+	require.Contains(t, response.Errors[0].Message, "exceeds the limit of 10")
+}
+
+func TestMount_HidesInternalErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		err     error
+		message string
+		status  any
+	}{
+		{name: "internal error", err: errDatabase, message: graphqlserver.ErrInternal.Error()},
+		{name: "http error", err: echo.NewHTTPError(http.StatusNotFound, "order not found"),
+			message: "order not found", status: float64(http.StatusNotFound)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httpserver.New(&httpserver.Config{})
+			graphqlserver.Mount(server, newSchema(func(context.Context) (any, error) {
+				return nil, tt.err
+			}), graphqlserver.Config{})
+
+			_, response := serve(t, server, "{ me }")
+			require.Len(t, response.Errors, 1)
+			require.Equal(t, tt.message, response.Errors[0].Message)
+			require.Equal(t, tt.status, response.Errors[0].Extensions["status"])
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package health
+
+import (
+	"context"
+	"errors"
+
+	"github.com/andyle182810/gframework/httpclient"
+	"github.com/andyle182810/gframework/mongo"
+	"github.com/andyle182810/gframework/postgres"
+	"github.com/andyle182810/gframework/search"
+	"github.com/andyle182810/gframework/valkey"
+)
+
+var ErrUnhealthy = errors.New("health: dependency is unhealthy")
+
+// Postgres checks that the pool can reach the database. It is critical.
+func Postgres(db *postgres.Postgres, opts ...postgres.HealthCheckOption) Checker {
+	return Checker{
+		Name: "postgres",
+		Check: func(ctx context.Context) error {
+			return db.HealthCheck(ctx, opts...)
+		},
+		Timeout:  0,
+		Critical: true,
+	}
+}
+
+// Mongo checks that the client can reach the server. It is critical.
+func Mongo(db *mongo.Mongo, opts ...mongo.HealthCheckOption) Checker {
+	return Checker{
+		Name: "mongo",
+		Check: func(ctx context.Context) error {
+			return db.HealthCheck(ctx, opts...)
+		},
+		Timeout:  0,
+		Critical: true,
+	}
+}
+
+// Valkey checks that the client can reach the server. It is critical.
+func Valkey(client *valkey.Valkey) Checker {
+	return Checker{
+		Name:     "valkey",
+		Check:    client.HealthCheck,
+		Timeout:  0,
+		Critical: true,
+	}
+}
+
+// Search checks that the cluster is reachable and not red. It is not critical, as search is usually
+// a secondary index the service can run without.
+func Search(client *search.Client) Checker {
+	return Checker{
+		Name:     "search",
+		Check:    client.HealthCheck,
+		Timeout:  0,
+		Critical: false,
+	}
+}
+
+// HTTP checks that GET path on a dependency responds with a 2xx status. It is not critical, as a
+// service usually degrades rather than stops when another service is down; set Critical otherwise.
+func HTTP(name string, client *httpclient.Client, path string) Checker {
+	return Checker{
+		Name: name,
+		Check: func(ctx context.Context) error {
+			return client.Get(ctx, path, nil)
+		},
+		Timeout:  0,
+		Critical: false,
+	}
+}
+
+// Subscriber checks a service that tracks its own health, such as the redissub, kafkasub,
+// natsjetstream, amqpsub and sqssub subscribers or a workerpool. It is critical.
+func Subscriber(name string, subscriber interface{ IsHealthy() bool }) Checker {
+	return Checker{
+		Name: name,
+		Check: func(context.Context) error {
+			if !subscriber.IsHealthy() {
+				return ErrUnhealthy
+			}
+
+			return nil
+		},
+		Timeout:  0,
+		Critical: true,
+	}
+}
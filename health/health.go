@@ -0,0 +1,281 @@
+// Package health aggregates dependency checks into liveness and readiness endpoints.
+//
+// Basic usage:
+//
+//	checks := health.New(health.WithCacheTTL(2 * time.Second))
+//	checks.Register(
+//	    health.Postgres(db),
+//	    health.Valkey(cache),
+//	    health.Mongo(documents),
+//	    health.Search(searchClient),
+//	    health.Subscriber("orders-subscriber", subscriber),
+//	    health.HTTP("billing-api", billingClient, "/health"),
+//	)
+//
+//	checks.RegisterRoutes(server.Root) // GET /health and GET /ready
+//
+// /health reports that the process is alive without checking dependencies, so an orchestrator does not
+// restart the service while a database is down. /ready runs the checks: a failing critical check
+// reports the service as down with 503, a failing non-critical check as degraded with 200.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v5"
+)
+
+const (
+	defaultTimeout  = 3 * time.Second
+	defaultCacheTTL = time.Second
+)
+
+var ErrNotReady = errors.New("health: critical dependencies are down")
+
+type Status string
+
+const (
+	StatusUp       Status = "up"
+	StatusDegraded Status = "degraded"
+	StatusDown     Status = "down"
+)
+
+// Checker checks one dependency.
+type Checker struct {
+	Name string
+	// Check returns an error when the dependency is unhealthy.
+	Check func(ctx context.Context) error
+	// Timeout bounds Check. It defaults to the timeout of the Health.
+	Timeout time.Duration
+	// Critical marks dependencies the service cannot serve requests without.
+	Critical bool
+}
+
+type Result struct {
+	Name      string    `json:"name"`
+	Status    Status    `json:"status"`
+	Critical  bool      `json:"critical"`
+	Error     string    `json:"error,omitempty"`
+	Duration  string    `json:"duration"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+type Report struct {
+	Status Status   `json:"status"`
+	Checks []Result `json:"checks"`
+}
+
+type Option func(*Health)
+
+// WithTimeout sets the timeout of checkers without one. It defaults to 3 seconds.
+func WithTimeout(timeout time.Duration) Option {
+	return func(h *Health) {
+		if timeout > 0 {
+			h.timeout = timeout
+		}
+	}
+}
+
+// WithCacheTTL sets how long a check result is reused, so frequent probes from load balancers and
+// orchestrators do not hit the dependencies on every request. It defaults to one second; zero runs
+// the checks on every request.
+func WithCacheTTL(ttl time.Duration) Option {
+	return func(h *Health) {
+		h.cacheTTL = max(ttl, 0)
+	}
+}
+
+type cachedResult struct {
+	mu      sync.Mutex
+	result  Result
+	expires time.Time
+}
+
+type registered struct {
+	checker Checker
+	cache   *cachedResult
+}
+
+// Health runs the registered checkers concurrently and caches their results.
+type Health struct {
+	timeout  time.Duration
+	cacheTTL time.Duration
+
+	mu       sync.RWMutex
+	checkers []registered
+}
+
+func New(opts ...Option) *Health {
+	h := &Health{
+		timeout:  defaultTimeout,
+		cacheTTL: defaultCacheTTL,
+		mu:       sync.RWMutex{},
+		checkers: nil,
+	}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+// Register adds checkers to the readiness report, in order.
+func (h *Health) Register(checkers ...Checker) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, checker := range checkers {
+		h.checkers = append(h.checkers, registered{checker: checker, cache: &cachedResult{}}) //nolint:exhaustruct
+	}
+}
+
+// Check runs the checkers whose cached result has expired and reports all results. Concurrent calls
+// share a running check instead of starting another one.
+func (h *Health) Check(ctx context.Context) Report {
+	h.mu.RLock()
+	checkers := append([]registered(nil), h.checkers...)
+	h.mu.RUnlock()
+
+	results := make([]Result, len(checkers))
+
+	var wg sync.WaitGroup
+
+	for i, entry := range checkers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			results[i] = h.cached(ctx, entry)
+		}()
+	}
+
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Checks: results}
+
+	for _, result := range results {
+		if result.Status != StatusDown {
+			continue
+		}
+
+		if result.Critical {
+			report.Status = StatusDown
+
+			break
+		}
+
+		report.Status = StatusDegraded
+	}
+
+	return report
+}
+
+// Ready returns an error naming the failing critical checks, e.g. to gate a readiness probe of
+// another server:
+//
+//	msrv.AddReadinessCheck("dependencies", checks.Ready)
+func (h *Health) Ready(ctx context.Context) error {
+	report := h.Check(ctx)
+	if report.Status != StatusDown {
+		return nil
+	}
+
+	var failed []string
+
+	for _, result := range report.Checks {
+		if result.Critical && result.Status == StatusDown {
+			failed = append(failed, result.Name+": "+result.Error)
+		}
+	}
+
+	return fmt.Errorf("%w: %s", ErrNotReady, strings.Join(failed, "; "))
+}
+
+// LivenessHandler responds 200 while the process can serve HTTP requests.
+func (h *Health) LivenessHandler() echo.HandlerFunc {
+	return func(c *echo.Context) error {
+		return c.JSON(http.StatusOK, Report{Status: StatusUp, Checks: []Result{}})
+	}
+}
+
+// ReadinessHandler responds with the report of Check, with 503 when a critical check fails.
+func (h *Health) ReadinessHandler() echo.HandlerFunc {
+	return func(c *echo.Context) error {
+		report := h.Check(c.Request().Context())
+
+		status := http.StatusOK
+		if report.Status == StatusDown {
+			status = http.StatusServiceUnavailable
+		}
+
+		return c.JSON(status, report)
+	}
+}
+
+// RegisterRoutes serves LivenessHandler on GET /health and ReadinessHandler on GET /ready.
+func (h *Health) RegisterRoutes(group *echo.Group) {
+	group.GET("/health", h.LivenessHandler())
+	group.GET("/ready", h.ReadinessHandler())
+}
+
+func (h *Health) cached(ctx context.Context, entry registered) Result {
+	entry.cache.mu.Lock()
+	defer entry.cache.mu.Unlock()
+
+	if time.Now().Before(entry.cache.expires) {
+		return entry.cache.result
+	}
+
+	result := h.run(ctx, entry.checker)
+
+	entry.cache.result = result
+	entry.cache.expires = result.CheckedAt.Add(h.cacheTTL)
+
+	return result
+}
+
+func (h *Health) run(ctx context.Context, checker Checker) (result Result) {
+	timeout := checker.Timeout
+	if timeout <= 0 {
+		timeout = h.timeout
+	}
+
+	// A canceled probe request must not cache a failure for the other callers.
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancel()
+
+	startedAt := time.Now()
+
+	result = Result{
+		Name:      checker.Name,
+		Status:    StatusUp,
+		Critical:  checker.Critical,
+		Error:     "",
+		Duration:  "",
+		CheckedAt: startedAt,
+	}
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			result.Status = StatusDown
+			result.Error = fmt.Sprintf("panic: %v", rec)
+		}
+
+		result.Duration = time.Since(startedAt).String()
+	}()
+
+	if err := checker.Check(ctx); err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+
+	return result
+}
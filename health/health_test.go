@@ -0,0 +1,198 @@
+//nolint:exhaustruct
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/health"
+	"github.com/andyle182810/gframework/httpclient"
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/require"
+)
+
+var errDown = errors.New("connection refused")
+
+func checker(name string, critical bool, err error) health.Checker {
+	return health.Checker{
+		Name:     name,
+		Check:    func(context.Context) error { return err },
+		Critical: critical,
+	}
+}
+
+func TestHealth_Check(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		checkers   []health.Checker
+		wantStatus health.Status
+	}{
+		{name: "no checkers", wantStatus: health.StatusUp},
+		{
+			name:       "all up",
+			checkers:   []health.Checker{checker("postgres", true, nil), checker("billing", false, nil)},
+			wantStatus: health.StatusUp,
+		},
+		{
+			name:       "non-critical down",
+			checkers:   []health.Checker{checker("postgres", true, nil), checker("billing", false, errDown)},
+			wantStatus: health.StatusDegraded,
+		},
+		{
+			name:       "critical down",
+			checkers:   []health.Checker{checker("billing", false, errDown), checker("postgres", true, errDown)},
+			wantStatus: health.StatusDown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			checks := health.New()
+			checks.Register(tt.checkers...)
+
+			report := checks.Check(t.Context())
+			require.Equal(t, tt.wantStatus, report.Status)
+			require.Len(t, report.Checks, len(tt.checkers))
+
+			for i, result := range report.Checks {
+				require.Equal(t, tt.checkers[i].Name, result.Name, "results keep the registration order")
+			}
+		})
+	}
+}
+
+func TestHealth_TimeoutsAndPanics(t *testing.T) {
+	t.Parallel()
+
+	checks := health.New(health.WithTimeout(20 * time.Millisecond))
+	checks.Register(
+		health.Checker{
+			Name:     "slow",
+			Check:    func(ctx context.Context) error { <-ctx.Done(); return ctx.Err() },
+			Critical: true,
+		},
+		health.Checker{
+			Name:  "broken",
+			Check: func(context.Context) error { panic("nil map") },
+		},
+	)
+
+	report := checks.Check(t.Context())
+	require.Equal(t, health.StatusDown, report.Status)
+	require.Equal(t, context.DeadlineExceeded.Error(), report.Checks[0].Error)
+	require.Equal(t, "panic: nil map", report.Checks[1].Error)
+}
+
+func TestHealth_CachesResults(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	checks := health.New(health.WithCacheTTL(100 * time.Millisecond))
+	checks.Register(health.Checker{
+		Name: "counted",
+		Check: func(context.Context) error {
+			calls.Add(1)
+			time.Sleep(10 * time.Millisecond)
+
+			return nil
+		},
+	})
+
+	done := make(chan struct{})
+
+	for range 5 {
+		go func() {
+			checks.Check(t.Context())
+			done <- struct{}{}
+		}()
+	}
+
+	for range 5 {
+		<-done
+	}
+
+	require.Equal(t, int32(1), calls.Load(), "concurrent checks share one run")
+
+	require.Eventually(t, func() bool {
+		checks.Check(t.Context())
+
+		return calls.Load() == 2
+	}, time.Second, 20*time.Millisecond, "the result is checked again once expired")
+}
+
+func TestHealth_Handlers(t *testing.T) {
+	t.Parallel()
+
+	checks := health.New(health.WithCacheTTL(0))
+	checks.Register(checker("postgres", true, errDown))
+
+	e := echo.New()
+	checks.RegisterRoutes(e.Group(""))
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	require.Equal(t, http.StatusOK, rec.Code, "liveness does not depend on dependencies")
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	var report health.Report
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	require.Equal(t, health.StatusDown, report.Status)
+	require.Equal(t, "connection refused", report.Checks[0].Error)
+
+	err := checks.Ready(t.Context())
+	require.ErrorIs(t, err, health.ErrNotReady)
+	require.ErrorContains(t, err, "postgres: connection refused")
+}
+
+type fakeSubscriber struct {
+	healthy atomic.Bool
+}
+
+func (s *fakeSubscriber) IsHealthy() bool { return s.healthy.Load() }
+
+func TestCheckers(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	subscriber := &fakeSubscriber{}
+
+	checks := health.New(health.WithCacheTTL(0))
+	checks.Register(
+		health.Valkey(testutil.SetupValkey(t)),
+		health.Subscriber("orders-subscriber", subscriber),
+		health.HTTP("billing", httpclient.New(server.URL), "/health"),
+		health.HTTP("ledger", httpclient.New(server.URL), "/down"),
+	)
+
+	report := checks.Check(t.Context())
+	require.Equal(t, health.StatusDown, report.Status)
+	require.Equal(t, health.StatusUp, report.Checks[0].Status)
+	require.Equal(t, health.StatusDown, report.Checks[1].Status)
+	require.Equal(t, health.StatusUp, report.Checks[2].Status)
+	require.Equal(t, health.StatusDown, report.Checks[3].Status)
+
+	subscriber.healthy.Store(true)
+
+	require.Equal(t, health.StatusDegraded, checks.Check(t.Context()).Status, "only the non-critical ledger check fails")
+}
@@ -21,6 +21,8 @@
 //
 // All methods (Get, Post, Put, Patch, Delete) accept a context, path, optional request body, and response pointer.
 // Request IDs are automatically propagated from the context if set via middleware.ContextKeyRequestID.
+// Inbound headers such as tenant ID, locale and baggage captured by middleware.PropagateHeaders are
+// forwarded when the client is created with WithHeaderPropagation.
 package httpclient
 
 import (
@@ -48,13 +50,14 @@ type TokenProvider interface {
 var _ Doer = (*http.Client)(nil)
 
 type Client struct {
-	baseURL         string
-	httpClient      Doer
-	requestIDKey    any
-	defaultHeaders  map[string]string
-	authConfig      *AuthConfig
-	tokenProvider   TokenProvider
-	maxResponseSize int64 // 0 means no limit
+	baseURL           string
+	httpClient        Doer
+	requestIDKey      any
+	defaultHeaders    map[string]string
+	authConfig        *AuthConfig
+	tokenProvider     TokenProvider
+	maxResponseSize   int64 // 0 means no limit
+	propagatedHeaders []string
 }
 
 func New(baseURL string, opts ...Option) *Client {
@@ -67,9 +70,10 @@ func New(baseURL string, opts ...Option) *Client {
 		defaultHeaders: map[string]string{
 			HeaderContentType: ContentTypeJSON,
 		},
-		authConfig:      nil,
-		tokenProvider:   nil,
-		maxResponseSize: 0,
+		authConfig:        nil,
+		tokenProvider:     nil,
+		maxResponseSize:   0,
+		propagatedHeaders: nil,
 	}
 
 	for _, opt := range opts {
@@ -241,6 +245,8 @@ func (c *Client) buildRequest(
 		req.Header.Set(k, v)
 	}
 
+	c.applyPropagatedHeaders(ctx, req)
+
 	if cfg.requestID != "" {
 		req.Header.Set(HeaderXRequestID, cfg.requestID)
 	}
@@ -33,6 +33,16 @@ func WithHTTPClient(httpClient *http.Client) Option {
 	}
 }
 
+// WithTransport sets the round tripper of the default http.Client, e.g. to trace or record outbound calls.
+// It has no effect on a client set with WithHTTPClient.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		if httpClient, ok := c.httpClient.(*http.Client); ok {
+			httpClient.Transport = transport
+		}
+	}
+}
+
 func WithRequestIDKey(key any) Option {
 	return func(c *Client) {
 		c.requestIDKey = key
@@ -0,0 +1,98 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+)
+
+const (
+	HeaderXTenantID      = "X-Tenant-ID"
+	HeaderAcceptLanguage = "Accept-Language"
+	HeaderBaggage        = "Baggage"
+	HeaderXFeatureFlags  = "X-Feature-Flags"
+)
+
+// DefaultPropagatedHeaders are forwarded when WithHeaderPropagation is used without explicit names.
+func DefaultPropagatedHeaders() []string {
+	return []string{HeaderXTenantID, HeaderAcceptLanguage, HeaderBaggage, HeaderXFeatureFlags}
+}
+
+type propagatedHeadersKey struct{}
+
+// ContextWithPropagatedHeaders stores inbound headers in ctx so clients configured with
+// WithHeaderPropagation forward them on outbound calls. Existing values in ctx are kept unless
+// overridden by headers.
+func ContextWithPropagatedHeaders(ctx context.Context, headers http.Header) context.Context {
+	existing := PropagatedHeaders(ctx)
+	merged := make(http.Header, len(existing)+len(headers))
+
+	for key, values := range existing {
+		merged[key] = values
+	}
+
+	for key, values := range headers {
+		if len(values) > 0 {
+			merged[http.CanonicalHeaderKey(key)] = values
+		}
+	}
+
+	return context.WithValue(ctx, propagatedHeadersKey{}, merged)
+}
+
+func PropagatedHeaders(ctx context.Context) http.Header {
+	headers, _ := ctx.Value(propagatedHeadersKey{}).(http.Header)
+
+	return headers
+}
+
+// CaptureHeaders copies the named headers from an inbound request into its context.
+func CaptureHeaders(req *http.Request, names ...string) *http.Request {
+	captured := make(http.Header, len(names))
+
+	for _, name := range names {
+		if values := req.Header.Values(name); len(values) > 0 {
+			captured[http.CanonicalHeaderKey(name)] = values
+		}
+	}
+
+	if len(captured) == 0 {
+		return req
+	}
+
+	return req.WithContext(ContextWithPropagatedHeaders(req.Context(), captured))
+}
+
+// WithHeaderPropagation forwards the named headers from the request context onto outbound calls.
+// Headers set explicitly on the client or request take precedence.
+func WithHeaderPropagation(names ...string) Option {
+	if len(names) == 0 {
+		names = DefaultPropagatedHeaders()
+	}
+
+	return func(c *Client) {
+		for _, name := range names {
+			c.propagatedHeaders = append(c.propagatedHeaders, http.CanonicalHeaderKey(name))
+		}
+	}
+}
+
+func (c *Client) applyPropagatedHeaders(ctx context.Context, req *http.Request) {
+	if len(c.propagatedHeaders) == 0 {
+		return
+	}
+
+	headers := PropagatedHeaders(ctx)
+	if len(headers) == 0 {
+		return
+	}
+
+	for _, name := range c.propagatedHeaders {
+		if req.Header.Get(name) != "" {
+			continue
+		}
+
+		for _, value := range headers.Values(name) {
+			req.Header.Add(name, value)
+		}
+	}
+}
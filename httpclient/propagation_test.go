@@ -0,0 +1,62 @@
+package httpclient_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andyle182810/gframework/httpclient"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithHeaderPropagation_ForwardsCapturedHeaders(t *testing.T) {
+	t.Parallel()
+
+	var received http.Header
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	inbound := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	inbound.Header.Set(httpclient.HeaderXTenantID, "tenant-1")
+	inbound.Header.Set(httpclient.HeaderAcceptLanguage, "vi-VN")
+	inbound.Header.Set("X-Internal-Secret", "do-not-forward")
+
+	inbound = httpclient.CaptureHeaders(inbound, httpclient.DefaultPropagatedHeaders()...)
+
+	client := httpclient.New(server.URL, httpclient.WithHeaderPropagation())
+
+	err := client.Get(inbound.Context(), "/downstream", nil,
+		httpclient.WithRequestHeader(httpclient.HeaderAcceptLanguage, "en-US"),
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, "tenant-1", received.Get(httpclient.HeaderXTenantID))
+	require.Equal(t, "en-US", received.Get(httpclient.HeaderAcceptLanguage))
+	require.Empty(t, received.Get("X-Internal-Secret"))
+}
+
+func TestWithHeaderPropagation_DisabledByDefault(t *testing.T) {
+	t.Parallel()
+
+	var received http.Header
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	ctx := httpclient.ContextWithPropagatedHeaders(t.Context(), http.Header{
+		httpclient.HeaderXTenantID: []string{"tenant-1"},
+	})
+
+	err := httpclient.New(server.URL).Get(ctx, "/downstream", nil)
+	require.NoError(t, err)
+	require.Empty(t, received.Get(httpclient.HeaderXTenantID))
+}
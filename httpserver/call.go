@@ -53,6 +53,7 @@ func ExecuteStandardized[REQ any, RES any](
 		RequestID:  requestID,
 		Data:       internalResponse.Data,
 		Pagination: internalResponse.Pagination,
+		Cursor:     internalResponse.Cursor,
 	}
 
 	return finalPayload, nil
@@ -0,0 +1,196 @@
+package httpserver
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andyle182810/gframework/middleware"
+	"github.com/andyle182810/gframework/pagination"
+	"github.com/labstack/echo/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+const MIMEApplicationProtobuf = "application/x-protobuf"
+
+var ErrPayloadNotEncodable = errors.New("httpserver: payload not supported by encoder")
+
+// ResponseEncoder serializes handler results for a media type other than JSON. Encode returns
+// ErrPayloadNotEncodable when the value cannot be represented, in which case Wrapper falls back to JSON.
+//
+// Encoders receive the data of the response envelopes only. Wrapper sends the rest of the envelope as
+// headers: the request ID as X-Request-ID, offset pagination as X-Page, X-Page-Size, X-Total-Count and
+// X-Total-Pages, and cursor pagination as X-Next-Cursor, X-Prev-Cursor and X-Page-Limit.
+type ResponseEncoder interface {
+	ContentType() string
+	Encode(v any) ([]byte, error)
+}
+
+const (
+	HeaderXPage       = "X-Page"
+	HeaderXPageSize   = "X-Page-Size"
+	HeaderXTotalCount = "X-Total-Count"
+	HeaderXTotalPages = "X-Total-Pages"
+	HeaderXNextCursor = "X-Next-Cursor"
+	HeaderXPrevCursor = "X-Prev-Cursor"
+	HeaderXPageLimit  = "X-Page-Limit"
+)
+
+type WrapperOption func(*wrapperConfig)
+
+type wrapperConfig struct {
+	encoders []ResponseEncoder
+}
+
+func WithResponseEncoder(encoder ResponseEncoder) WrapperOption {
+	return func(c *wrapperConfig) {
+		if encoder != nil {
+			c.encoders = append(c.encoders, encoder)
+		}
+	}
+}
+
+func WithProtobuf() WrapperOption {
+	return WithResponseEncoder(ProtobufEncoder{})
+}
+
+// payloadCarrier is implemented by the response envelopes so binary encoders can reach the data and
+// Wrapper can send the metadata as headers.
+type payloadCarrier interface {
+	payload() any
+	metadata() (requestID string, offset *Pagination, cursor *pagination.Page)
+}
+
+func (r *HandlerResponse[T]) payload() any {
+	return r.Data
+}
+
+func (r *HandlerResponse[T]) metadata() (string, *Pagination, *pagination.Page) {
+	return "", r.Pagination, r.Cursor
+}
+
+func (r *APIResponse[T]) payload() any {
+	return r.Data
+}
+
+func (r *APIResponse[T]) metadata() (string, *Pagination, *pagination.Page) {
+	return r.RequestID, r.Pagination, r.Cursor
+}
+
+// setEnvelopeHeaders sends the metadata of a response envelope, which encoders drop, as headers.
+func setEnvelopeHeaders(header http.Header, v any) {
+	carrier, ok := v.(payloadCarrier)
+	if !ok {
+		return
+	}
+
+	requestID, offset, cursor := carrier.metadata()
+
+	if requestID != "" && header.Get(middleware.HeaderXRequestID) == "" {
+		header.Set(middleware.HeaderXRequestID, requestID)
+	}
+
+	if offset != nil {
+		header.Set(HeaderXPage, strconv.Itoa(offset.Page))
+		header.Set(HeaderXPageSize, strconv.Itoa(offset.PageSize))
+		header.Set(HeaderXTotalCount, strconv.Itoa(offset.TotalCount))
+		header.Set(HeaderXTotalPages, strconv.Itoa(offset.TotalPages))
+	}
+
+	if cursor != nil {
+		if cursor.NextCursor != "" {
+			header.Set(HeaderXNextCursor, cursor.NextCursor)
+		}
+
+		if cursor.PrevCursor != "" {
+			header.Set(HeaderXPrevCursor, cursor.PrevCursor)
+		}
+
+		header.Set(HeaderXPageLimit, strconv.Itoa(cursor.Limit))
+	}
+}
+
+type ProtobufEncoder struct{}
+
+func (ProtobufEncoder) ContentType() string {
+	return MIMEApplicationProtobuf
+}
+
+func (ProtobufEncoder) Encode(v any) ([]byte, error) {
+	if carrier, ok := v.(payloadCarrier); ok {
+		v = carrier.payload()
+	}
+
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrPayloadNotEncodable, v)
+	}
+
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("httpserver: failed to marshal protobuf: %w", err)
+	}
+
+	return data, nil
+}
+
+// negotiateEncoder returns the configured encoder the Accept header prefers over JSON, or nil for
+// JSON. Encoders are only chosen when their content type is listed explicitly with a non-zero quality;
+// JSON competes with application/json and the wildcards. Ties go to the media type listed first.
+func negotiateEncoder(accept string, encoders []ResponseEncoder) ResponseEncoder {
+	if accept == "" || len(encoders) == 0 {
+		return nil
+	}
+
+	var (
+		best        ResponseEncoder
+		bestQuality float64
+	)
+
+	for part := range strings.SplitSeq(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		quality := acceptQuality(params)
+		if quality <= bestQuality {
+			continue
+		}
+
+		switch mediaType {
+		case echo.MIMEApplicationJSON, "application/*", "*/*":
+			best, bestQuality = nil, quality
+
+			continue
+		}
+
+		for _, encoder := range encoders {
+			if strings.EqualFold(mediaType, encoder.ContentType()) {
+				best, bestQuality = encoder, quality
+
+				break
+			}
+		}
+	}
+
+	return best
+}
+
+// acceptQuality returns the q parameter of an Accept element, 1 when absent and 0 when invalid.
+func acceptQuality(params map[string]string) float64 {
+	raw, ok := params["q"]
+	if !ok {
+		return 1
+	}
+
+	quality, err := strconv.ParseFloat(raw, 64)
+	if err != nil || quality < 0 || quality > 1 {
+		return 0
+	}
+
+	return quality
+}
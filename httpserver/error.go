@@ -31,7 +31,10 @@ func HTTPError(code int, err error, details ...string) *echo.HTTPError {
 	httpErr := echo.NewHTTPError(code, message)
 
 	if err != nil {
-		_ = httpErr.Wrap(err)
+		// Wrap returns a copy, keep it so the cause stays reachable with errors.As.
+		wrapped, _ := httpErr.Wrap(err).(*echo.HTTPError)
+
+		return wrapped
 	}
 
 	return httpErr
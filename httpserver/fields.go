@@ -0,0 +1,35 @@
+package httpserver
+
+import (
+	"github.com/andyle182810/gframework/middleware"
+)
+
+const envelopeDataKey = "data"
+
+// filterResponseFields applies sparse fieldsets to the data of an APIResponse envelope, leaving
+// requestId and pagination untouched. Payloads without an envelope are filtered as a whole.
+func filterResponseFields(res any, fields []string) (any, error) {
+	decoded, err := middleware.FilterFields(res, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope, ok := decoded.(map[string]any)
+	if !ok {
+		return middleware.FilterFields(decoded, fields)
+	}
+
+	data, hasData := envelope[envelopeDataKey]
+	if !hasData {
+		return middleware.FilterFields(decoded, fields)
+	}
+
+	filtered, err := middleware.FilterFields(data, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	envelope[envelopeDataKey] = filtered
+
+	return envelope, nil
+}
@@ -1,14 +1,18 @@
 package httpserver
 
+import "github.com/andyle182810/gframework/pagination"
+
 type HandlerResponse[T any] struct {
-	Data       T           `json:"data"`
-	Pagination *Pagination `json:"pagination,omitempty"`
+	Data       T                `json:"data"`
+	Pagination *Pagination      `json:"pagination,omitempty"`
+	Cursor     *pagination.Page `json:"cursor,omitempty"`
 }
 
 func NewResponse[T any](data T) *HandlerResponse[T] {
 	return &HandlerResponse[T]{
 		Data:       data,
 		Pagination: nil,
+		Cursor:     nil,
 	}
 }
 
@@ -16,6 +20,15 @@ func NewPaginatedResponse[T any](data T, pagination *Pagination) *HandlerRespons
 	return &HandlerResponse[T]{
 		Data:       data,
 		Pagination: pagination,
+		Cursor:     nil,
+	}
+}
+
+func NewCursorPaginatedResponse[T any](data T, page *pagination.Page) *HandlerResponse[T] {
+	return &HandlerResponse[T]{
+		Data:       data,
+		Pagination: nil,
+		Cursor:     page,
 	}
 }
 
@@ -27,9 +40,10 @@ type Pagination struct {
 }
 
 type APIResponse[T any] struct {
-	RequestID  string      `example:"3bf74527-8097-4217-8485-ffe05d16f82e" json:"requestId,omitempty"`
-	Data       T           `json:"data"`
-	Pagination *Pagination `json:"pagination,omitempty"`
+	RequestID  string           `example:"3bf74527-8097-4217-8485-ffe05d16f82e" json:"requestId,omitempty"`
+	Data       T                `json:"data"`
+	Pagination *Pagination      `json:"pagination,omitempty"`
+	Cursor     *pagination.Page `json:"cursor,omitempty"`
 }
 
 type ResponseError struct {
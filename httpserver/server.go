@@ -1,7 +1,8 @@
 // Package httpserver provides an opinionated HTTP server wrapper around Echo v5 with integrated middleware.
 //
 // The server automatically configures request logging, body size limiting, CORS (optional), validation,
-// and error handling. It supports graceful shutdown and is production-ready.
+// and error handling. Handlers get a logger carrying the request ID, tenant and user from
+// logutil.FromContext(ctx.Request().Context()). It supports graceful shutdown and is production-ready.
 //
 // Basic usage:
 //
@@ -67,10 +68,13 @@ type Server struct {
 func New(cfg *Config) *Server {
 	e := echo.New()
 	e.Validator = validator.DefaultRestValidator()
+	e.Binder = validator.NewEchoBinder()
 	e.HTTPErrorHandler = middleware.ErrorHandler(echo.DefaultHTTPErrorHandler(false))
 
+	e.Pre(middleware.ContextLogger())
 	e.Pre(middleware.RequestLogger(log.Logger, SafeLogFieldsExtractor))
 	e.Pre(echomiddleware.BodyLimit(parseBodyLimit(cfg.BodyLimit)))
+	e.Pre(middleware.AutoMethods(e))
 
 	if cfg.EnableCors {
 		e.Use(echomiddleware.CORS(cfg.AllowOrigins...))
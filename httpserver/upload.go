@@ -0,0 +1,162 @@
+package httpserver
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"slices"
+
+	"github.com/andyle182810/gframework/blobstore"
+	"github.com/labstack/echo/v5"
+)
+
+var (
+	ErrUploadMissing      = errors.New("upload field is missing")
+	ErrUploadTooLarge     = errors.New("upload exceeds the maximum size")
+	ErrUploadContentType  = errors.New("upload content type is not allowed")
+	errUploadLimitReached = errors.New("upload limit reached")
+)
+
+const sniffLen = 512
+
+type uploadConfig struct {
+	maxSize      int64
+	contentTypes []string
+	putOptions   []blobstore.PutOption
+}
+
+type UploadOption func(*uploadConfig)
+
+// WithMaxUploadSize rejects files larger than size bytes with 413 Request Entity Too Large.
+func WithMaxUploadSize(size int64) UploadOption {
+	return func(c *uploadConfig) {
+		c.maxSize = size
+	}
+}
+
+// WithAllowedContentTypes rejects files of other media types, such as "image/png", with
+// 415 Unsupported Media Type.
+func WithAllowedContentTypes(contentTypes ...string) UploadOption {
+	return func(c *uploadConfig) {
+		c.contentTypes = contentTypes
+	}
+}
+
+// WithUploadPutOptions passes options such as metadata or encryption to the store.
+func WithUploadPutOptions(opts ...blobstore.PutOption) UploadOption {
+	return func(c *uploadConfig) {
+		c.putOptions = append(c.putOptions, opts...)
+	}
+}
+
+// SaveUpload streams the file of a multipart form field into the store under key, without buffering
+// the request in memory or on disk. The content type is sniffed from the first bytes; the type sent by
+// the client is only used when sniffing finds generic binary or plain text data. Parts before the
+// field are skipped, so other form values must be sent after the file to be read by the handler.
+func SaveUpload(
+	c *echo.Context,
+	store blobstore.Store,
+	field, key string,
+	opts ...UploadOption,
+) (*blobstore.Object, *echo.HTTPError) {
+	config := &uploadConfig{maxSize: 0, contentTypes: nil, putOptions: nil}
+
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	reader, err := c.Request().MultipartReader()
+	if err != nil {
+		return nil, BadRequestError(err, "Invalid multipart request")
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, BadRequestError(ErrUploadMissing, fmt.Sprintf("Missing file field %q", field))
+		}
+
+		if err != nil {
+			return nil, BadRequestError(err, "Invalid multipart request")
+		}
+
+		if part.FormName() != field || part.FileName() == "" {
+			_ = part.Close()
+
+			continue
+		}
+
+		object, httpErr := saveUploadPart(c, store, key, part, config)
+		_ = part.Close()
+
+		return object, httpErr
+	}
+}
+
+func saveUploadPart(
+	c *echo.Context,
+	store blobstore.Store,
+	key string,
+	part *multipart.Part,
+	config *uploadConfig,
+) (*blobstore.Object, *echo.HTTPError) {
+	var body io.Reader = part
+	if config.maxSize > 0 {
+		body = &limitedReader{reader: part, remaining: config.maxSize}
+	}
+
+	buffered := bufio.NewReaderSize(body, sniffLen)
+	head, _ := buffered.Peek(sniffLen)
+
+	contentType := http.DetectContentType(head)
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		contentType = mediaType
+	}
+
+	if contentType == "application/octet-stream" || contentType == "text/plain" {
+		if declared, _, err := mime.ParseMediaType(part.Header.Get(echo.HeaderContentType)); err == nil {
+			contentType = declared
+		}
+	}
+
+	if len(config.contentTypes) > 0 && !slices.Contains(config.contentTypes, contentType) {
+		return nil, HTTPError(http.StatusUnsupportedMediaType, ErrUploadContentType,
+			fmt.Sprintf("Content type %s is not allowed", contentType))
+	}
+
+	putOptions := append([]blobstore.PutOption{blobstore.WithContentType(contentType)}, config.putOptions...)
+
+	object, err := store.Put(c.Request().Context(), key, buffered, putOptions...)
+	if errors.Is(err, errUploadLimitReached) {
+		return nil, HTTPError(http.StatusRequestEntityTooLarge, ErrUploadTooLarge,
+			fmt.Sprintf("File exceeds the maximum size of %d bytes", config.maxSize))
+	}
+
+	if err != nil {
+		return nil, InternalError(err, "Failed to store upload")
+	}
+
+	return object, nil
+}
+
+// limitedReader fails once more than remaining bytes are read, unlike io.LimitReader, which would
+// silently truncate the file.
+type limitedReader struct {
+	reader    io.Reader
+	remaining int64
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.remaining -= int64(n)
+
+	if r.remaining < 0 {
+		return n, errUploadLimitReached
+	}
+
+	return n, err
+}
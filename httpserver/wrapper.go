@@ -11,7 +11,18 @@ import (
 	"github.com/rs/zerolog"
 )
 
-func Wrapper[TREQ any](wrapped func(*echo.Context, *TREQ) (any, *echo.HTTPError)) echo.HandlerFunc {
+func Wrapper[TREQ any](
+	wrapped func(*echo.Context, *TREQ) (any, *echo.HTTPError),
+	opts ...WrapperOption,
+) echo.HandlerFunc {
+	config := &wrapperConfig{
+		encoders: nil,
+	}
+
+	for _, opt := range opts {
+		opt(config)
+	}
+
 	return func(c *echo.Context) error {
 		logger := getOrCreateLogger(c)
 		requestID := middleware.GetRequestID(c)
@@ -36,6 +47,32 @@ func Wrapper[TREQ any](wrapped func(*echo.Context, *TREQ) (any, *echo.HTTPError)
 			status = response.Status
 		}
 
+		if len(config.encoders) > 0 {
+			c.Response().Header().Add(echo.HeaderVary, echo.HeaderAccept)
+		}
+
+		if encoder := negotiateEncoder(c.Request().Header.Get(echo.HeaderAccept), config.encoders); encoder != nil {
+			data, encodeErr := encoder.Encode(res)
+			if encodeErr == nil {
+				setEnvelopeHeaders(c.Response().Header(), res)
+
+				return c.Blob(status, encoder.ContentType(), data)
+			}
+
+			if !errors.Is(encodeErr, ErrPayloadNotEncodable) {
+				return InternalError(encodeErr, "Failed to encode response")
+			}
+		}
+
+		if fields := middleware.GetFields(c); len(fields) > 0 {
+			filtered, filterErr := filterResponseFields(res, fields)
+			if filterErr != nil {
+				return InternalError(filterErr, "Failed to apply response field filter")
+			}
+
+			res = filtered
+		}
+
 		return c.JSON(status, res)
 	}
 }
@@ -57,25 +94,46 @@ func bindAndValidate[TREQ any](c *echo.Context) (*TREQ, *echo.HTTPError) {
 	var req TREQ
 
 	if err := c.Bind(&req); err != nil {
+		// validator.EchoBinder validates while binding.
+		if isValidationError(err) {
+			return nil, validationHTTPError(err)
+		}
+
 		httpErr := BadRequestError(err, "Invalid request body")
 		_ = httpErr.Wrap(err)
 
 		return nil, httpErr
 	}
 
-	if err := c.Validate(&req); err != nil {
-		message := "Validation failed"
+	if _, validated := c.Echo().Binder.(*validator.EchoBinder); validated {
+		return &req, nil
+	}
 
-		var validationErrs validator.ValidationErrors
+	if err := validator.ValidateEcho(c, &req); err != nil {
+		return nil, validationHTTPError(err)
+	}
 
-		if errors.As(err, &validationErrs) {
-			message = validationErrs.Error()
-		}
+	return &req, nil
+}
 
-		httpErr := BadRequestError(err, message)
+func isValidationError(err error) bool {
+	var validationErrs validator.ValidationErrors
 
-		return nil, httpErr
+	return errors.As(err, &validationErrs) || errors.Is(err, validator.ErrAsyncValidation)
+}
+
+func validationHTTPError(err error) *echo.HTTPError {
+	if errors.Is(err, validator.ErrAsyncValidation) {
+		return ServiceUnavailableError(err, "Validation could not be completed")
 	}
 
-	return &req, nil
+	message := "Validation failed"
+
+	var validationErrs validator.ValidationErrors
+
+	if errors.As(err, &validationErrs) {
+		message = validationErrs.Error()
+	}
+
+	return BadRequestError(err, message)
 }
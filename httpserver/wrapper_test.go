@@ -0,0 +1,198 @@
+package httpserver_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andyle182810/gframework/httpserver"
+	"github.com/andyle182810/gframework/pagination"
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type greetingRequest struct{}
+
+func serveWrapper(t *testing.T, accept string, handler echo.HandlerFunc) *httptest.ResponseRecorder {
+	t.Helper()
+
+	headers := map[string]string{}
+	if accept != "" {
+		headers[echo.HeaderAccept] = accept
+	}
+
+	ctx, rec, _ := testutil.SetupEchoContext(t, &testutil.Options{
+		Method:        http.MethodGet,
+		Path:          "/greetings",
+		Body:          nil,
+		Headers:       headers,
+		QueryParams:   nil,
+		PathParams:    nil,
+		ContentType:   "",
+		SkipRequestID: false,
+	})
+
+	require.NoError(t, handler(ctx))
+
+	return rec
+}
+
+func TestWrapper_ProtobufNegotiation(t *testing.T) {
+	t.Parallel()
+
+	handler := httpserver.Wrapper(func(_ *echo.Context, _ *greetingRequest) (any, *echo.HTTPError) {
+		return httpserver.NewResponse(wrapperspb.String("hello")), nil
+	}, httpserver.WithProtobuf())
+
+	tests := []struct {
+		name        string
+		accept      string
+		contentType string
+	}{
+		{name: "no accept header", accept: "", contentType: echo.MIMEApplicationJSON},
+		{name: "json", accept: "application/json", contentType: echo.MIMEApplicationJSON},
+		{name: "wildcard", accept: "*/*", contentType: echo.MIMEApplicationJSON},
+		{name: "protobuf", accept: "application/x-protobuf", contentType: httpserver.MIMEApplicationProtobuf},
+		{
+			name:        "protobuf preferred by quality",
+			accept:      "application/json;q=0.5, application/x-protobuf",
+			contentType: httpserver.MIMEApplicationProtobuf,
+		},
+		{
+			name:        "protobuf listed first",
+			accept:      "application/x-protobuf, application/json",
+			contentType: httpserver.MIMEApplicationProtobuf,
+		},
+		{
+			name:        "protobuf not acceptable",
+			accept:      "application/x-protobuf;q=0",
+			contentType: echo.MIMEApplicationJSON,
+		},
+		{
+			name:        "json preferred by quality",
+			accept:      "application/json, application/x-protobuf;q=0.1",
+			contentType: echo.MIMEApplicationJSON,
+		},
+		{
+			name:        "wildcard preferred by quality",
+			accept:      "*/*, application/x-protobuf;q=0.9",
+			contentType: echo.MIMEApplicationJSON,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := serveWrapper(t, tt.accept, handler)
+
+			require.Equal(t, http.StatusOK, rec.Code)
+			require.Contains(t, rec.Header().Get(echo.HeaderContentType), tt.contentType)
+			require.Equal(t, echo.HeaderAccept, rec.Header().Get(echo.HeaderVary))
+
+			if tt.contentType == httpserver.MIMEApplicationProtobuf {
+				var message wrapperspb.StringValue
+				require.NoError(t, proto.Unmarshal(rec.Body.Bytes(), &message))
+				require.Equal(t, "hello", message.GetValue())
+
+				return
+			}
+
+			require.JSONEq(t, `{"data":{"value":"hello"}}`, rec.Body.String())
+		})
+	}
+}
+
+func TestWrapper_ProtobufEnvelopeHeaders(t *testing.T) {
+	t.Parallel()
+
+	t.Run("cursor pagination", func(t *testing.T) {
+		t.Parallel()
+
+		handler := httpserver.Wrapper(func(_ *echo.Context, _ *greetingRequest) (any, *echo.HTTPError) {
+			page := &pagination.Page{NextCursor: "next", PrevCursor: "", Limit: 20}
+
+			return httpserver.NewCursorPaginatedResponse(wrapperspb.String("hello"), page), nil
+		}, httpserver.WithProtobuf())
+
+		rec := serveWrapper(t, httpserver.MIMEApplicationProtobuf, handler)
+
+		require.Equal(t, httpserver.MIMEApplicationProtobuf, rec.Header().Get(echo.HeaderContentType))
+		require.Equal(t, "next", rec.Header().Get(httpserver.HeaderXNextCursor))
+		require.Empty(t, rec.Header().Get(httpserver.HeaderXPrevCursor))
+		require.Equal(t, "20", rec.Header().Get(httpserver.HeaderXPageLimit))
+	})
+
+	t.Run("offset pagination", func(t *testing.T) {
+		t.Parallel()
+
+		handler := httpserver.Wrapper(func(_ *echo.Context, _ *greetingRequest) (any, *echo.HTTPError) {
+			page := &httpserver.Pagination{Page: 2, PageSize: 10, TotalCount: 42, TotalPages: 5}
+
+			return httpserver.NewPaginatedResponse(wrapperspb.String("hello"), page), nil
+		}, httpserver.WithProtobuf())
+
+		rec := serveWrapper(t, httpserver.MIMEApplicationProtobuf, handler)
+
+		require.Equal(t, "2", rec.Header().Get(httpserver.HeaderXPage))
+		require.Equal(t, "10", rec.Header().Get(httpserver.HeaderXPageSize))
+		require.Equal(t, "42", rec.Header().Get(httpserver.HeaderXTotalCount))
+		require.Equal(t, "5", rec.Header().Get(httpserver.HeaderXTotalPages))
+	})
+
+	t.Run("json keeps the metadata in the body", func(t *testing.T) {
+		t.Parallel()
+
+		handler := httpserver.Wrapper(func(_ *echo.Context, _ *greetingRequest) (any, *echo.HTTPError) {
+			page := &pagination.Page{NextCursor: "next", PrevCursor: "", Limit: 20}
+
+			return httpserver.NewCursorPaginatedResponse(wrapperspb.String("hello"), page), nil
+		}, httpserver.WithProtobuf())
+
+		rec := serveWrapper(t, echo.MIMEApplicationJSON, handler)
+
+		require.Empty(t, rec.Header().Get(httpserver.HeaderXNextCursor))
+		require.JSONEq(t, `{"data":{"value":"hello"},"cursor":{"nextCursor":"next","limit":20}}`, rec.Body.String())
+	})
+}
+
+func TestWrapper_JSONFallback(t *testing.T) {
+	t.Parallel()
+
+	type greeting struct {
+		Message string `json:"message"`
+	}
+
+	handler := httpserver.Wrapper(func(_ *echo.Context, _ *greetingRequest) (any, *echo.HTTPError) {
+		return httpserver.NewResponse(greeting{Message: "hello"}), nil
+	}, httpserver.WithProtobuf())
+
+	rec := serveWrapper(t, httpserver.MIMEApplicationProtobuf, handler)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON)
+
+	var body struct {
+		Data greeting `json:"data"`
+	}
+
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Equal(t, "hello", body.Data.Message)
+
+	t.Run("without encoders", func(t *testing.T) {
+		t.Parallel()
+
+		plain := httpserver.Wrapper(func(_ *echo.Context, _ *greetingRequest) (any, *echo.HTTPError) {
+			return httpserver.NewResponse(wrapperspb.String("hello")), nil
+		})
+
+		rec := serveWrapper(t, httpserver.MIMEApplicationProtobuf, plain)
+
+		require.Contains(t, rec.Header().Get(echo.HeaderContentType), echo.MIMEApplicationJSON)
+		require.Empty(t, rec.Header().Get(echo.HeaderVary))
+	})
+}
@@ -0,0 +1,347 @@
+// Package i18n translates messages from JSON catalogs with plural rules and locale fallback, and
+// negotiates the locale of HTTP requests.
+//
+// Catalogs are JSON files named after their locale, e.g. "locales/en.json" and "locales/vi.json".
+// Nested objects are namespaces joined with dots, and an object whose keys are CLDR plural categories
+// (zero, one, two, few, many, other) holds the forms of a plural message. Placeholders such as
+// "{name}" are replaced with parameters:
+//
+//	{
+//	    "greeting": "Hello, {name}!",
+//	    "cart": {"items": {"one": "{count} item", "other": "{count} items"}},
+//	    "validation": {"required": "{field} is required"}
+//	}
+//
+// Basic usage:
+//
+//	//go:embed locales/*.json
+//	var locales embed.FS
+//
+//	bundle := i18n.NewBundle("en", i18n.WithFallback("pt-BR", "pt"))
+//	if err := bundle.LoadFS(locales, "locales"); err != nil {
+//	    return err
+//	}
+//
+//	server.Echo.Use(i18n.Middleware(bundle))
+//
+//	msg := bundle.T(ctx, "cart.items", i18n.Params{"count": 3})
+//
+// A message missing in the requested locale is looked up in its parent locales (pt-BR, then pt), the
+// configured fallbacks and finally the default locale; a key missing everywhere is returned as is.
+package i18n
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"golang.org/x/text/language"
+)
+
+var (
+	ErrInvalidLocale  = errors.New("i18n: invalid locale")
+	ErrInvalidCatalog = errors.New("i18n: invalid catalog")
+)
+
+// Params are the values of the placeholders of a message. A "count" parameter selects the plural form.
+type Params map[string]any
+
+type localeKey struct{}
+
+// ContextWithLocale sets the locale messages are translated into, see Middleware.
+func ContextWithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// LocaleFromContext returns the locale set with ContextWithLocale, or "".
+func LocaleFromContext(ctx context.Context) string {
+	locale, _ := ctx.Value(localeKey{}).(string)
+
+	return locale
+}
+
+// message is a translation, with its plural forms when it has any.
+type message struct {
+	text   string
+	plural map[string]string
+}
+
+type Option func(*Bundle)
+
+// WithFallback looks up messages missing in locale in fallbacks, in order, before the default locale,
+// e.g. WithFallback("pt-BR", "pt-PT").
+func WithFallback(locale string, fallbacks ...string) Option {
+	return func(b *Bundle) {
+		b.fallbacks[canonical(locale)] = append(b.fallbacks[canonical(locale)], mapCanonical(fallbacks)...)
+	}
+}
+
+// Bundle holds the catalogs of all locales.
+type Bundle struct {
+	defaultLocale string
+	fallbacks     map[string][]string
+
+	mu       sync.RWMutex
+	catalogs map[string]map[string]message
+	locales  []string
+	matcher  language.Matcher
+}
+
+// NewBundle creates a bundle whose messages fall back to defaultLocale, which is also the locale of
+// requests that accept none of the loaded locales.
+func NewBundle(defaultLocale string, opts ...Option) *Bundle {
+	b := &Bundle{
+		defaultLocale: canonical(defaultLocale),
+		fallbacks:     make(map[string][]string),
+		mu:            sync.RWMutex{},
+		catalogs:      make(map[string]map[string]message),
+		locales:       nil,
+		matcher:       nil,
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	b.addLocale(b.defaultLocale)
+
+	return b
+}
+
+// DefaultLocale returns the locale messages fall back to.
+func (b *Bundle) DefaultLocale() string {
+	return b.defaultLocale
+}
+
+// Locales returns the locales with a catalog, the default locale first.
+func (b *Bundle) Locales() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return slices.Clone(b.locales)
+}
+
+// LoadFS loads the "<locale>.json" catalogs in dir of fsys, e.g. an embed.FS. Messages of a locale
+// loaded more than once are merged, later files winning.
+func (b *Bundle) LoadFS(fsys fs.FS, dir string) error {
+	files, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return fmt.Errorf("i18n: failed to list catalogs: %w", err)
+	}
+
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return fmt.Errorf("i18n: failed to read %s: %w", file, err)
+		}
+
+		var messages map[string]any
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrInvalidCatalog, file, err)
+		}
+
+		if err := b.AddMessages(strings.TrimSuffix(path.Base(file), ".json"), messages); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+	}
+
+	return nil
+}
+
+// AddMessages adds messages to the catalog of locale, in the structure of a JSON catalog.
+func (b *Bundle) AddMessages(locale string, messages map[string]any) error {
+	if _, err := language.Parse(locale); err != nil {
+		return fmt.Errorf("%w: %q", ErrInvalidLocale, locale)
+	}
+
+	flat := make(map[string]message)
+	if err := flatten("", messages, flat); err != nil {
+		return err
+	}
+
+	locale = canonical(locale)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.addLocale(locale)
+
+	for key, msg := range flat {
+		b.catalogs[locale][key] = msg
+	}
+
+	return nil
+}
+
+// Localize returns the message of key in locale with params filled in.
+func (b *Bundle) Localize(locale, key string, params Params) string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, candidate := range b.chain(locale) {
+		msg, ok := b.catalogs[candidate][key]
+		if !ok {
+			continue
+		}
+
+		text := msg.text
+
+		if msg.plural != nil {
+			text = msg.plural[pluralForm(candidate, params["count"])]
+			if text == "" {
+				text = msg.plural["other"]
+			}
+		}
+
+		return interpolate(text, params)
+	}
+
+	return key
+}
+
+// T returns the message of key in the locale of ctx with params filled in.
+func (b *Bundle) T(ctx context.Context, key string, params Params) string {
+	return b.Localize(LocaleFromContext(ctx), key, params)
+}
+
+// Match returns the loaded locale that best matches preferences, which are locales or Accept-Language
+// header values in order of preference, or the default locale.
+func (b *Bundle) Match(preferences ...string) string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	_, index := language.MatchStrings(b.matcher, preferences...)
+
+	return b.locales[index]
+}
+
+// addLocale registers locale; b.mu must be held unless called from NewBundle.
+func (b *Bundle) addLocale(locale string) {
+	if _, ok := b.catalogs[locale]; ok {
+		return
+	}
+
+	b.catalogs[locale] = make(map[string]message)
+	b.locales = append(b.locales, locale)
+
+	tags := make([]language.Tag, 0, len(b.locales))
+	for _, loaded := range b.locales {
+		tags = append(tags, language.Make(loaded))
+	}
+
+	b.matcher = language.NewMatcher(tags)
+}
+
+// chain returns the locales to look a message up in, in order.
+func (b *Bundle) chain(locale string) []string {
+	var chain []string
+
+	add := func(candidate string) {
+		if _, ok := b.catalogs[candidate]; ok && !slices.Contains(chain, candidate) {
+			chain = append(chain, candidate)
+		}
+	}
+
+	if locale != "" {
+		locale = canonical(locale)
+
+		for tag := language.Make(locale); !tag.IsRoot(); tag = tag.Parent() {
+			add(tag.String())
+		}
+
+		for _, fallback := range b.fallbacks[locale] {
+			add(fallback)
+		}
+	}
+
+	add(b.defaultLocale)
+
+	return chain
+}
+
+func flatten(prefix string, values map[string]any, out map[string]message) error {
+	for key, value := range values {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		switch typed := value.(type) {
+		case string:
+			out[key] = message{text: typed, plural: nil}
+		case map[string]any:
+			if forms, ok := pluralForms(typed); ok {
+				out[key] = message{text: "", plural: forms}
+
+				continue
+			}
+
+			if err := flatten(key, typed, out); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%w: %s must be a string or an object", ErrInvalidCatalog, key)
+		}
+	}
+
+	return nil
+}
+
+// interpolate replaces the {name} placeholders of text with params. Unknown placeholders are kept.
+func interpolate(text string, params Params) string {
+	if len(params) == 0 || !strings.Contains(text, "{") {
+		return text
+	}
+
+	var out strings.Builder
+
+	for {
+		start := strings.IndexByte(text, '{')
+		if start < 0 {
+			break
+		}
+
+		end := strings.IndexByte(text[start:], '}')
+		if end < 0 {
+			break
+		}
+
+		end += start
+		out.WriteString(text[:start])
+
+		if value, ok := params[text[start+1:end]]; ok {
+			fmt.Fprint(&out, value)
+		} else {
+			out.WriteString(text[start : end+1])
+		}
+
+		text = text[end+1:]
+	}
+
+	out.WriteString(text)
+
+	return out.String()
+}
+
+func canonical(locale string) string {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return locale
+	}
+
+	return tag.String()
+}
+
+func mapCanonical(locales []string) []string {
+	out := make([]string, 0, len(locales))
+	for _, locale := range locales {
+		out = append(out, canonical(locale))
+	}
+
+	return out
+}
@@ -0,0 +1,159 @@
+//nolint:exhaustruct
+package i18n_test
+
+import (
+	"os"
+	"testing"
+	"testing/fstest"
+
+	"github.com/andyle182810/gframework/i18n"
+	"github.com/stretchr/testify/require"
+)
+
+func newBundle(t *testing.T) *i18n.Bundle {
+	t.Helper()
+
+	bundle := i18n.NewBundle("en", i18n.WithFallback("pt-BR", "vi"))
+	require.NoError(t, bundle.LoadFS(os.DirFS("testdata"), "locales"))
+
+	return bundle
+}
+
+func TestBundle_Localize(t *testing.T) {
+	t.Parallel()
+
+	bundle := newBundle(t)
+
+	tests := []struct {
+		name   string
+		locale string
+		key    string
+		params i18n.Params
+		want   string
+	}{
+		{name: "default locale", locale: "en", key: "greeting", params: i18n.Params{"name": "An"}, want: "Hello, An!"},
+		{name: "locale", locale: "vi", key: "greeting", params: i18n.Params{"name": "An"}, want: "Xin chào, An!"},
+		{name: "no locale", locale: "", key: "greeting", params: i18n.Params{"name": "An"}, want: "Hello, An!"},
+		{
+			name: "region falls back to parent", locale: "vi-VN", key: "greeting",
+			params: i18n.Params{"name": "An"}, want: "Xin chào, An!",
+		},
+		{name: "parent before fallbacks", locale: "pt-BR", key: "greeting", params: i18n.Params{"name": "An"}, want: "Olá, An!"},
+		{
+			name: "configured fallback", locale: "pt-BR", key: "user.not_found",
+			params: i18n.Params{"id": 7}, want: "Không tìm thấy người dùng 7",
+		},
+		{name: "default locale fallback", locale: "ru", key: "greeting", params: i18n.Params{"name": "An"}, want: "Hello, An!"},
+		{name: "unknown locale", locale: "fr", key: "greeting", params: i18n.Params{"name": "An"}, want: "Hello, An!"},
+		{name: "missing key", locale: "vi", key: "missing.key", want: "missing.key"},
+		{name: "unknown placeholder kept", locale: "en", key: "greeting", params: i18n.Params{"other": 1}, want: "Hello, {name}!"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, bundle.Localize(tt.locale, tt.key, tt.params))
+		})
+	}
+}
+
+func TestBundle_Plural(t *testing.T) {
+	t.Parallel()
+
+	bundle := newBundle(t)
+
+	tests := []struct {
+		locale string
+		count  any
+		want   string
+	}{
+		{locale: "en", count: 1, want: "1 item"},
+		{locale: "en", count: 0, want: "0 items"},
+		{locale: "en", count: int64(5), want: "5 items"},
+		{locale: "en", count: 1.5, want: "1.5 items"},
+		{locale: "en", count: "many", want: "many items"},
+		{locale: "vi", count: 1, want: "1 sản phẩm"},
+		{locale: "pt-BR", count: 0, want: "0 item"},
+		{locale: "pt-BR", count: 2, want: "2 itens"},
+		{locale: "ru", count: 1, want: "1 товар"},
+		{locale: "ru", count: 3, want: "3 товара"},
+		{locale: "ru", count: 5, want: "5 товаров"},
+		{locale: "ru", count: 21, want: "21 товар"},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.want, bundle.Localize(tt.locale, "cart.items", i18n.Params{"count": tt.count}),
+			"%s %v", tt.locale, tt.count)
+	}
+}
+
+func TestBundle_T(t *testing.T) {
+	t.Parallel()
+
+	bundle := newBundle(t)
+	ctx := i18n.ContextWithLocale(t.Context(), "vi")
+
+	require.Equal(t, "vi", i18n.LocaleFromContext(ctx))
+	require.Equal(t, "Xin chào, An!", bundle.T(ctx, "greeting", i18n.Params{"name": "An"}))
+	require.Equal(t, "Hello, An!", bundle.T(t.Context(), "greeting", i18n.Params{"name": "An"}))
+}
+
+func TestBundle_Match(t *testing.T) {
+	t.Parallel()
+
+	bundle := newBundle(t)
+
+	require.Equal(t, []string{"en", "pt-BR", "pt", "ru", "vi"}, bundle.Locales())
+	require.Equal(t, "vi", bundle.Match("vi-VN,vi;q=0.9,en;q=0.8"))
+	require.Equal(t, "pt-BR", bundle.Match("pt-BR"))
+	require.Equal(t, "ru", bundle.Match("fr", "ru"))
+	require.Equal(t, "en", bundle.Match("fr-FR"))
+	require.Equal(t, "en", bundle.Match(""))
+}
+
+func TestBundle_InvalidCatalog(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		files   fstest.MapFS
+		wantErr error
+	}{
+		{
+			name:    "invalid json",
+			files:   fstest.MapFS{"en.json": {Data: []byte(`{"greeting":`)}},
+			wantErr: i18n.ErrInvalidCatalog,
+		},
+		{
+			name:    "invalid message",
+			files:   fstest.MapFS{"en.json": {Data: []byte(`{"greeting": 1}`)}},
+			wantErr: i18n.ErrInvalidCatalog,
+		},
+		{
+			name:    "invalid locale",
+			files:   fstest.MapFS{"not a locale.json": {Data: []byte(`{}`)}},
+			wantErr: i18n.ErrInvalidLocale,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := i18n.NewBundle("en").LoadFS(tt.files, ".")
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestBundle_AddMessages(t *testing.T) {
+	t.Parallel()
+
+	bundle := i18n.NewBundle("en")
+	require.NoError(t, bundle.AddMessages("en", map[string]any{"greeting": "Hi"}))
+	require.NoError(t, bundle.AddMessages("en", map[string]any{"farewell": "Bye"}))
+
+	require.Equal(t, "Hi", bundle.Localize("en", "greeting", nil))
+	require.Equal(t, "Bye", bundle.Localize("en", "farewell", nil))
+}
@@ -0,0 +1,119 @@
+package i18n
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/andyle182810/gframework/validator"
+	"github.com/labstack/echo/v5"
+)
+
+type middlewareConfig struct {
+	queryParam string
+}
+
+type MiddlewareOption func(*middlewareConfig)
+
+// WithQueryParam lets clients override Accept-Language with a query parameter, e.g. "?lang=vi".
+func WithQueryParam(name string) MiddlewareOption {
+	return func(c *middlewareConfig) {
+		c.queryParam = name
+	}
+}
+
+// Middleware negotiates the locale of each request from its Accept-Language header against the
+// locales of bundle and sets it on the request context, for Bundle.T and for validation messages. The
+// negotiated locale is returned in the Content-Language header.
+func Middleware(bundle *Bundle, opts ...MiddlewareOption) echo.MiddlewareFunc {
+	cfg := middlewareConfig{queryParam: ""}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c *echo.Context) error {
+			req := c.Request()
+
+			var preferences []string
+
+			if cfg.queryParam != "" {
+				if lang := c.QueryParam(cfg.queryParam); lang != "" {
+					preferences = append(preferences, lang)
+				}
+			}
+
+			preferences = append(preferences, req.Header.Get("Accept-Language"))
+
+			locale := bundle.Match(preferences...)
+
+			ctx := ContextWithLocale(req.Context(), locale)
+			ctx = validator.ContextWithLocale(ctx, locale)
+			c.SetRequest(req.WithContext(ctx))
+
+			header := c.Response().Header()
+			header.Set("Content-Language", locale)
+			header.Add("Vary", "Accept-Language")
+
+			return next(c)
+		}
+	}
+}
+
+// Error is an error whose message is translated in HTTP error responses, see ErrorResponse:
+//
+//	return httpserver.NotFoundError(i18n.NewError("user.not_found", i18n.Params{"id": id}))
+type Error struct {
+	Key    string
+	Params Params
+}
+
+func NewError(key string, params Params) *Error {
+	return &Error{Key: key, Params: params}
+}
+
+func (e *Error) Error() string {
+	return e.Key
+}
+
+// ErrorResponse builds the error envelope of middleware.ErrorHandler with the message translated into
+// the locale of the request. The message of an *Error is translated from its key, any other message
+// is used as a key and kept as is when no catalog has it. Use it as the CustomErrorResponse of
+// middleware.ErrorHandlerConfig:
+//
+//	server.Echo.HTTPErrorHandler = middleware.ErrorHandler(server.Echo.HTTPErrorHandler,
+//	    &middleware.ErrorHandlerConfig{CustomErrorResponse: bundle.ErrorResponse(false)})
+func (b *Bundle) ErrorResponse(includeInternal bool) func(*echo.Context, error, int) map[string]any {
+	return func(c *echo.Context, err error, code int) map[string]any {
+		locale := LocaleFromContext(c.Request().Context())
+		if locale == "" {
+			locale = b.Match(c.Request().Header.Get("Accept-Language"))
+		}
+
+		message := http.StatusText(code)
+
+		var httpErr *echo.HTTPError
+		if errors.As(err, &httpErr) {
+			message = httpErr.Message
+		}
+
+		var localized *Error
+		if errors.As(err, &localized) {
+			message = b.Localize(locale, localized.Key, localized.Params)
+		} else {
+			message = b.Localize(locale, message, nil)
+		}
+
+		response := map[string]any{
+			"message": message,
+		}
+
+		if includeInternal && httpErr != nil {
+			if internal := httpErr.Unwrap(); internal != nil {
+				response["internal"] = internal.Error()
+			}
+		}
+
+		return response
+	}
+}
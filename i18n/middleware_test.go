@@ -0,0 +1,157 @@
+//nolint:exhaustruct
+package i18n_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andyle182810/gframework/httpserver"
+	"github.com/andyle182810/gframework/i18n"
+	"github.com/andyle182810/gframework/middleware"
+	"github.com/andyle182810/gframework/validator"
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/require"
+)
+
+type createUserRequest struct {
+	Name string `json:"name" validate:"required"`
+}
+
+func newServer(t *testing.T, includeInternal bool) *echo.Echo {
+	t.Helper()
+
+	bundle := newBundle(t)
+
+	restValidator := validator.DefaultRestValidator()
+	require.NoError(t, bundle.RegisterValidationMessages(restValidator))
+
+	e := echo.New()
+	e.Validator = restValidator
+	e.HTTPErrorHandler = middleware.ErrorHandler(e.HTTPErrorHandler, &middleware.ErrorHandlerConfig{
+		CustomErrorResponse: bundle.ErrorResponse(includeInternal),
+	})
+	e.Use(i18n.Middleware(bundle, i18n.WithQueryParam("lang")))
+
+	e.GET("/greeting", func(c *echo.Context) error {
+		return c.String(http.StatusOK, bundle.T(c.Request().Context(), "greeting", i18n.Params{"name": "An"}))
+	})
+	e.GET("/users/:id", func(c *echo.Context) error {
+		return httpserver.NotFoundError(i18n.NewError("user.not_found", i18n.Params{"id": c.Param("id")}))
+	})
+	e.GET("/validate", func(c *echo.Context) error {
+		var req createUserRequest
+
+		err := restValidator.ValidateCtx(c.Request().Context(), &req)
+
+		var validationErrs validator.ValidationErrors
+		require.ErrorAs(t, err, &validationErrs)
+
+		return c.String(http.StatusBadRequest, validationErrs[0].Message)
+	})
+	e.GET("/orders/:id", func(_ *echo.Context) error {
+		return httpserver.NotFoundError(nil)
+	})
+	e.GET("/fail", func(_ *echo.Context) error {
+		return httpserver.InternalError(errors.New("database is down")) //nolint:err113
+	})
+
+	return e
+}
+
+func serve(e *echo.Echo, target, acceptLanguage string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if acceptLanguage != "" {
+		req.Header.Set("Accept-Language", acceptLanguage)
+	}
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func errorMessage(t *testing.T, rec *httptest.ResponseRecorder) map[string]any {
+	t.Helper()
+
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+
+	return body
+}
+
+func TestMiddleware_Negotiation(t *testing.T) {
+	t.Parallel()
+
+	e := newServer(t, false)
+
+	tests := []struct {
+		name           string
+		target         string
+		acceptLanguage string
+		wantLocale     string
+		wantBody       string
+	}{
+		{name: "accept language", target: "/greeting", acceptLanguage: "vi-VN,vi;q=0.9", wantLocale: "vi", wantBody: "Xin chào, An!"},
+		{name: "no header", target: "/greeting", wantLocale: "en", wantBody: "Hello, An!"},
+		{name: "unsupported", target: "/greeting", acceptLanguage: "fr", wantLocale: "en", wantBody: "Hello, An!"},
+		{name: "query parameter", target: "/greeting?lang=ru", acceptLanguage: "vi", wantLocale: "ru", wantBody: "Hello, An!"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rec := serve(e, tt.target, tt.acceptLanguage)
+
+			require.Equal(t, http.StatusOK, rec.Code)
+			require.Equal(t, tt.wantBody, rec.Body.String())
+			require.Equal(t, tt.wantLocale, rec.Header().Get("Content-Language"))
+			require.Equal(t, "Accept-Language", rec.Header().Get("Vary"))
+		})
+	}
+}
+
+func TestMiddleware_ValidationMessages(t *testing.T) {
+	t.Parallel()
+
+	e := newServer(t, false)
+
+	rec := serve(e, "/validate", "vi")
+	require.Equal(t, "Vui lòng nhập name", rec.Body.String())
+
+	rec = serve(e, "/validate", "en")
+	require.Equal(t, "name is required", rec.Body.String())
+}
+
+func TestBundle_ErrorResponse(t *testing.T) {
+	t.Parallel()
+
+	e := newServer(t, false)
+
+	rec := serve(e, "/users/42", "vi")
+	require.Equal(t, http.StatusNotFound, rec.Code)
+	require.Equal(t, map[string]any{"message": "Không tìm thấy người dùng 42"}, errorMessage(t, rec))
+
+	rec = serve(e, "/users/42", "en")
+	require.Equal(t, map[string]any{"message": "User 42 was not found"}, errorMessage(t, rec))
+
+	rec = serve(e, "/orders/1", "vi")
+	require.Equal(t, http.StatusNotFound, rec.Code)
+	require.Equal(t, map[string]any{"message": "Không tìm thấy"}, errorMessage(t, rec))
+
+	rec = serve(e, "/fail", "vi")
+	require.Equal(t, map[string]any{"message": "database is down"}, errorMessage(t, rec))
+}
+
+func TestBundle_ErrorResponse_IncludeInternal(t *testing.T) {
+	t.Parallel()
+
+	e := newServer(t, true)
+
+	rec := serve(e, "/fail", "vi")
+	require.Equal(t, http.StatusInternalServerError, rec.Code)
+	require.Equal(t, map[string]any{"message": "database is down", "internal": "database is down"}, errorMessage(t, rec))
+}
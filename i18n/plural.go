@@ -0,0 +1,89 @@
+package i18n
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+//nolint:gochecknoglobals
+var pluralCategories = map[plural.Form]string{
+	plural.Zero:  "zero",
+	plural.One:   "one",
+	plural.Two:   "two",
+	plural.Few:   "few",
+	plural.Many:  "many",
+	plural.Other: "other",
+}
+
+// pluralForms returns the forms of a plural message, an object with only plural categories as keys
+// and an "other" form.
+func pluralForms(values map[string]any) (map[string]string, bool) {
+	if _, ok := values["other"].(string); !ok {
+		return nil, false
+	}
+
+	forms := make(map[string]string, len(values))
+
+	for key, value := range values {
+		text, ok := value.(string)
+		if !ok {
+			return nil, false
+		}
+
+		switch key {
+		case "zero", "one", "two", "few", "many", "other":
+			forms[key] = text
+		default:
+			return nil, false
+		}
+	}
+
+	return forms, true
+}
+
+// pluralForm returns the CLDR plural category of count in locale, "other" when count is not a number.
+func pluralForm(locale string, count any) string {
+	var number string
+
+	switch typed := count.(type) {
+	case int:
+		number = strconv.Itoa(typed)
+	case int32:
+		number = strconv.FormatInt(int64(typed), 10)
+	case int64:
+		number = strconv.FormatInt(typed, 10)
+	case uint:
+		number = strconv.FormatUint(uint64(typed), 10)
+	case uint32:
+		number = strconv.FormatUint(uint64(typed), 10)
+	case uint64:
+		number = strconv.FormatUint(typed, 10)
+	case float32:
+		number = strconv.FormatFloat(float64(typed), 'f', -1, 32)
+	case float64:
+		number = strconv.FormatFloat(typed, 'f', -1, 64)
+	default:
+		return "other"
+	}
+
+	integer, fraction, _ := strings.Cut(strings.TrimPrefix(number, "-"), ".")
+
+	// MatchPlural takes the CLDR operands: the integer digits, the visible fraction digits with and
+	// without trailing zeros, and their values.
+	i, err := strconv.Atoi(integer)
+	if err != nil {
+		i = math.MaxInt
+	}
+
+	trimmed := strings.TrimRight(fraction, "0")
+	f, _ := strconv.Atoi("0" + fraction)
+	t, _ := strconv.Atoi("0" + trimmed)
+
+	form := plural.Cardinal.MatchPlural(language.Make(locale), i, len(fraction), len(trimmed), f, t)
+
+	return pluralCategories[form]
+}
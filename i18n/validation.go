@@ -0,0 +1,52 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/andyle182810/gframework/validator"
+	"golang.org/x/text/language"
+)
+
+const validationPrefix = "validation."
+
+// RegisterValidationMessages enables the translations of v for the loaded locales it supports and
+// overrides its messages with the "validation.<tag>" messages of the catalogs, where "{field}" is the
+// name of the field:
+//
+//	{"validation": {"required": "{field} is required", "sku": "{field} is not a valid SKU"}}
+//
+// Locales the validator cannot translate are skipped; their requests get English messages.
+func (b *Bundle) RegisterValidationMessages(v *validator.Validator) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, locale := range b.locales {
+		base, _ := language.Make(locale).Base()
+
+		err := v.EnableTranslations(base.String())
+		if errors.Is(err, validator.ErrUnsupportedLocale) {
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("i18n: failed to enable validation messages for %q: %w", locale, err)
+		}
+
+		for key, msg := range b.catalogs[locale] {
+			tag, ok := strings.CutPrefix(key, validationPrefix)
+			if !ok || msg.plural != nil {
+				continue
+			}
+
+			format := strings.ReplaceAll(strings.ReplaceAll(msg.text, "%", "%%"), "{field}", "%s")
+
+			if err := v.RegisterTranslation(base.String(), tag, format); err != nil {
+				return fmt.Errorf("i18n: failed to register validation message %q for %q: %w", tag, locale, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+// Package kafkapub provides a Kafka publisher with the same API as redispub.
+//
+// It wraps Watermill's Kafka publisher, so services can move a topic from Redis Streams to Kafka by
+// swapping the constructor; code written against the Publisher interface does not change.
+//
+// Basic usage:
+//
+//	publisher, err := kafkapub.New([]string{"localhost:9092"}, kafkapub.Options{
+//	    Timeout: 5 * time.Second,
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer publisher.Close()
+//
+//	err = publisher.PublishToTopic(ctx, "events", `{"event":"user.created"}`)
+//
+// Each message gets a random UUID, which kafkasub uses as the message ID. Messages are sent with a
+// synchronous producer, so PublishToTopic returns once the brokers have acknowledged them.
+package kafkapub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill-kafka/v3/pkg/kafka"
+	"github.com/ThreeDotsLabs/watermill/message"
+)
+
+const (
+	defaultPublishTimeout = 5 * time.Second
+)
+
+var (
+	ErrPublisherInitialization = errors.New("publisher: failed to initialize kafka publisher")
+	ErrPublishFailed           = errors.New("publisher: failed to publish messages")
+	ErrNoBrokers               = errors.New("publisher: at least one kafka broker is required")
+)
+
+type Publisher interface {
+	PublishToTopic(ctx context.Context, topic string, messageContents ...string) error
+	Close() error
+}
+
+type Options struct {
+	Timeout time.Duration
+	Logger  watermill.LoggerAdapter
+	// SaramaConfig overrides kafka.DefaultSaramaSyncPublisherConfig, e.g. to enable TLS or SASL.
+	SaramaConfig *sarama.Config
+}
+
+type KafkaPublisher struct {
+	publisher *kafka.Publisher
+	timeout   time.Duration
+}
+
+var _ Publisher = (*KafkaPublisher)(nil)
+
+func New(brokers []string, opts Options) (*KafkaPublisher, error) {
+	if len(brokers) == 0 {
+		return nil, ErrNoBrokers
+	}
+
+	publisher, err := kafka.NewPublisher(
+		kafka.PublisherConfig{ //nolint:exhaustruct
+			Brokers:               brokers,
+			Marshaler:             kafka.DefaultMarshaler{},
+			OverwriteSaramaConfig: opts.SaramaConfig,
+		},
+		opts.Logger,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrPublisherInitialization, err)
+	}
+
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = defaultPublishTimeout
+	}
+
+	return &KafkaPublisher{
+		publisher: publisher,
+		timeout:   timeout,
+	}, nil
+}
+
+func (p *KafkaPublisher) PublishToTopic(ctx context.Context, topic string, messageContents ...string) error {
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+
+	messages := make([]*message.Message, 0, len(messageContents))
+
+	for _, content := range messageContents {
+		msg := message.NewMessage(watermill.NewUUID(), []byte(content))
+		msg.SetContext(ctx)
+		messages = append(messages, msg)
+	}
+
+	if err := p.publisher.Publish(topic, messages...); err != nil {
+		return fmt.Errorf("%w to topic %s: %w", ErrPublishFailed, topic, err)
+	}
+
+	return nil
+}
+
+func (p *KafkaPublisher) Close() error {
+	return p.publisher.Close()
+}
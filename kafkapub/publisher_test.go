@@ -0,0 +1,35 @@
+package kafkapub_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/andyle182810/gframework/kafkapub"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_WithoutBrokers(t *testing.T) {
+	t.Parallel()
+
+	publisher, err := kafkapub.New(nil, kafkapub.Options{}) //nolint:exhaustruct
+
+	require.ErrorIs(t, err, kafkapub.ErrNoBrokers)
+	require.Nil(t, publisher)
+}
+
+func TestNew_WithUnreachableBroker(t *testing.T) {
+	t.Parallel()
+
+	saramaConfig := sarama.NewConfig()
+	saramaConfig.Producer.Return.Successes = true
+	saramaConfig.Metadata.Retry.Max = 0
+	saramaConfig.Net.DialTimeout = 100 * time.Millisecond
+
+	publisher, err := kafkapub.New([]string{"127.0.0.1:1"}, kafkapub.Options{ //nolint:exhaustruct
+		SaramaConfig: saramaConfig,
+	})
+
+	require.ErrorIs(t, err, kafkapub.ErrPublisherInitialization)
+	require.Nil(t, publisher)
+}
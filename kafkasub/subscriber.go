@@ -0,0 +1,508 @@
+// Package kafkasub provides a Kafka consumer-group subscriber with the same API and delivery semantics
+// as redissub.
+//
+// Handlers, retries, the dead letter topic, execution timeouts, metrics and the runner lifecycle work
+// exactly as in redissub, so a handler written for Redis Streams can consume a Kafka topic unchanged:
+//
+//	handler := func(ctx context.Context, payload message.Payload) error {
+//	    var event MyEvent
+//	    if err := json.Unmarshal(payload, &event); err != nil {
+//	        return err
+//	    }
+//	    // process event
+//	    return nil
+//	}
+//
+//	subscriber, err := kafkasub.NewSubscriber(
+//	    []string{"localhost:9092"},
+//	    "my-consumer-group",
+//	    "events-topic",
+//	    handler,
+//	    kafkasub.WithRetry(3, time.Second, "events-topic-dlq"),
+//	)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	runner.New(runner.WithCoreService(subscriber)).Run()
+//
+// Messages are acknowledged, i.e. their offset is committed, only after they have been processed or,
+// once retries are exhausted, sent to the dead letter topic. The dead letter message keeps the UUID and
+// payload and carries original_topic, consumer_group, error and failed_at in its metadata.
+package kafkasub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/ThreeDotsLabs/watermill-kafka/v3/pkg/kafka"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultShutdownTimeout = 20 * time.Second
+	defaultExecTimeout     = 30 * time.Second
+)
+
+var (
+	ErrNoBrokers                = errors.New("subscriber: at least one kafka broker is required")
+	ErrEmptyConsumerGroup       = errors.New("subscriber: consumer group name cannot be empty")
+	ErrEmptyTopicName           = errors.New("subscriber: topic name cannot be empty")
+	ErrNilMessageHandler        = errors.New("subscriber: message handler cannot be nil")
+	ErrMessageHandlerNotDefined = errors.New("subscriber: message handler is not defined")
+	ErrMaxRetriesExceeded       = errors.New("subscriber: max retries exceeded")
+	ErrExecTimeout              = errors.New("subscriber: message handler execution timed out")
+	ErrAlreadyRunning           = errors.New("subscriber: already running")
+)
+
+// MessageHandler has the same signature as redissub.MessageHandler.
+type MessageHandler func(ctx context.Context, payload message.Payload) error
+
+type Metrics interface {
+	MessageReceived(topic string)
+	MessageProcessed(topic string, duration time.Duration, err error)
+	MessageAcked(topic string)
+	MessageNacked(topic string)
+	MessageSentToDLQ(topic string)
+}
+
+type RetryConfig struct {
+	MaxRetries int           // Maximum number of retries (0 = no retries)
+	RetryDelay time.Duration // Delay between retries
+	DLQTopic   string        // Dead letter queue topic (empty = no DLQ)
+}
+
+type SubscriberConfig struct {
+	ShutdownTimeout time.Duration // Maximum time to wait for graceful shutdown before force closing
+	ExecTimeout     time.Duration // Maximum time allowed for message handler execution
+	Metrics         Metrics
+	Retry           *RetryConfig
+	// SaramaConfig overrides kafka.DefaultSaramaSubscriberConfig, e.g. to start from the oldest offset
+	// or to enable TLS or SASL. It is also used for the dead letter producer.
+	SaramaConfig *sarama.Config
+}
+
+type SubscriberOption func(*SubscriberConfig)
+
+func WithMetrics(m Metrics) SubscriberOption {
+	return func(c *SubscriberConfig) {
+		c.Metrics = m
+	}
+}
+
+func WithRetry(maxRetries int, retryDelay time.Duration, dlqTopic string) SubscriberOption {
+	return func(c *SubscriberConfig) {
+		c.Retry = &RetryConfig{
+			MaxRetries: maxRetries,
+			RetryDelay: retryDelay,
+			DLQTopic:   dlqTopic,
+		}
+	}
+}
+
+func WithShutdownTimeout(d time.Duration) SubscriberOption {
+	return func(c *SubscriberConfig) {
+		c.ShutdownTimeout = d
+	}
+}
+
+func WithExecTimeout(d time.Duration) SubscriberOption {
+	return func(c *SubscriberConfig) {
+		c.ExecTimeout = d
+	}
+}
+
+func WithSaramaConfig(cfg *sarama.Config) SubscriberOption {
+	return func(c *SubscriberConfig) {
+		c.SaramaConfig = cfg
+	}
+}
+
+type Subscriber struct {
+	*kafka.Subscriber
+	name           string
+	brokers        []string
+	topic          string
+	consumerGroup  string
+	shutdownSignal chan struct{}
+	stoppedSignal  chan struct{}
+	messageHandler MessageHandler
+	config         SubscriberConfig
+	healthy        atomic.Bool
+	running        atomic.Bool
+	dlqPublisher   *kafka.Publisher
+}
+
+func NewSubscriber(
+	brokers []string,
+	consumerGroup,
+	topic string,
+	messageHandler MessageHandler,
+	opts ...SubscriberOption,
+) (*Subscriber, error) {
+	if len(brokers) == 0 {
+		return nil, ErrNoBrokers
+	}
+
+	if consumerGroup == "" {
+		return nil, ErrEmptyConsumerGroup
+	}
+
+	if topic == "" {
+		return nil, ErrEmptyTopicName
+	}
+
+	if messageHandler == nil {
+		return nil, ErrNilMessageHandler
+	}
+
+	config := defaultSubscriberConfig()
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	//nolint:exhaustruct
+	kafkaSubscriber, err := kafka.NewSubscriber(
+		kafka.SubscriberConfig{
+			Brokers:               brokers,
+			Unmarshaler:           kafka.DefaultMarshaler{},
+			OverwriteSaramaConfig: config.SaramaConfig,
+			ConsumerGroup:         consumerGroup,
+		},
+		nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka subscriber: %w", err)
+	}
+
+	//nolint:exhaustruct
+	sub := &Subscriber{
+		name:           fmt.Sprintf("kafkasub-%s-%s", consumerGroup, topic),
+		brokers:        brokers,
+		topic:          topic,
+		consumerGroup:  consumerGroup,
+		Subscriber:     kafkaSubscriber,
+		messageHandler: messageHandler,
+		shutdownSignal: make(chan struct{}),
+		stoppedSignal:  make(chan struct{}),
+		config:         config,
+	}
+	sub.healthy.Store(false)
+
+	return sub, nil
+}
+
+func defaultSubscriberConfig() SubscriberConfig {
+	return SubscriberConfig{
+		ShutdownTimeout: defaultShutdownTimeout,
+		ExecTimeout:     defaultExecTimeout,
+		Metrics:         nil,
+		Retry:           nil,
+		SaramaConfig:    nil,
+	}
+}
+
+func (s *Subscriber) Stop() error {
+	if !s.running.CompareAndSwap(true, false) {
+		return nil
+	}
+
+	log.Info().
+		Str("source", "gframework").
+		Str("topic", s.topic).
+		Str("consumer_group", s.consumerGroup).
+		Msg("Stopping subscriber")
+
+	s.healthy.Store(false)
+
+	if s.shutdownSignal != nil {
+		close(s.shutdownSignal)
+	}
+
+	select {
+	case <-s.stoppedSignal:
+		// Good, subscriber stopped cleanly
+	case <-time.After(s.config.ShutdownTimeout):
+		log.Error().
+			Str("source", "gframework").
+			Str("topic", s.topic).
+			Str("consumer_group", s.consumerGroup).
+			Dur("timeout", s.config.ShutdownTimeout).
+			Msg("Timeout waiting for subscriber to stop")
+	}
+
+	if err := s.Subscriber.Close(); err != nil {
+		return fmt.Errorf("failed to close Kafka subscriber: %w", err)
+	}
+
+	if s.dlqPublisher != nil {
+		if err := s.dlqPublisher.Close(); err != nil {
+			return fmt.Errorf("failed to close Kafka DLQ publisher: %w", err)
+		}
+	}
+
+	log.Info().
+		Str("source", "gframework").
+		Str("topic", s.topic).
+		Str("consumer_group", s.consumerGroup).
+		Msg("Subscriber stopped")
+
+	return nil
+}
+
+func (s *Subscriber) Name() string {
+	return s.name
+}
+
+func (s *Subscriber) IsHealthy() bool {
+	return s.healthy.Load()
+}
+
+func (s *Subscriber) ConsumerGroup() string {
+	return s.consumerGroup
+}
+
+func (s *Subscriber) Topic() string {
+	return s.topic
+}
+
+func (s *Subscriber) Start(ctx context.Context) error { //nolint:cyclop
+	if !s.running.CompareAndSwap(false, true) {
+		return ErrAlreadyRunning
+	}
+
+	defer close(s.stoppedSignal)
+
+	log.Info().
+		Str("source", "gframework").
+		Str("topic", s.Topic()).
+		Msg("The subscription is being started")
+
+	if s.config.Retry != nil && s.config.Retry.DLQTopic != "" {
+		//nolint:exhaustruct
+		dlqPublisher, err := kafka.NewPublisher(kafka.PublisherConfig{
+			Brokers:               s.brokers,
+			Marshaler:             kafka.DefaultMarshaler{},
+			OverwriteSaramaConfig: s.config.SaramaConfig,
+		}, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create Kafka DLQ publisher: %w", err)
+		}
+
+		s.dlqPublisher = dlqPublisher
+	}
+
+	msgChan, err := s.Subscriber.Subscribe(ctx, s.Topic())
+	if err != nil {
+		return fmt.Errorf("subscription to topic %s failed: %w", s.Topic(), err)
+	}
+
+	s.healthy.Store(true)
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.healthy.Store(false)
+			log.Info().
+				Str("source", "gframework").
+				Str("service_name", s.Name()).
+				Str("topic", s.Topic()).
+				Msg("Subscriber stopped: context cancelled")
+
+			return ctx.Err()
+		case <-s.shutdownSignal:
+			s.healthy.Store(false)
+			log.Info().
+				Str("source", "gframework").
+				Str("service_name", s.Name()).
+				Str("topic", s.Topic()).
+				Msg("Subscriber stopped: graceful shutdown initiated")
+
+			return nil
+		case msg := <-msgChan:
+			if msg == nil || msg.UUID == "" {
+				log.Debug().
+					Str("source", "gframework").
+					Str("topic", s.Topic()).
+					Msg("An empty message has been received")
+
+				continue
+			}
+
+			if s.config.Metrics != nil {
+				s.config.Metrics.MessageReceived(s.Topic())
+			}
+
+			if err := s.handleMessage(ctx, msg); err != nil {
+				log.Error().
+					Str("source", "gframework").
+					Err(err).
+					Str("topic", s.Topic()).
+					Str("message_id", msg.UUID).
+					Msg("The message processing has failed")
+			}
+		}
+	}
+}
+
+func (s *Subscriber) handleMessage(ctx context.Context, msg *message.Message) error {
+	if s.messageHandler == nil {
+		return ErrMessageHandlerNotDefined
+	}
+
+	start := time.Now()
+	processingErr := s.processWithRetry(ctx, msg)
+	duration := time.Since(start)
+
+	if s.config.Metrics != nil {
+		s.config.Metrics.MessageProcessed(s.Topic(), duration, processingErr)
+	}
+
+	if processingErr != nil {
+		s.handleFailedMessage(msg, processingErr)
+
+		return fmt.Errorf("%w: %w", ErrMaxRetriesExceeded, processingErr)
+	}
+
+	s.acknowledgeMessage(msg)
+
+	return nil
+}
+
+func (s *Subscriber) processWithRetry(ctx context.Context, msg *message.Message) error {
+	maxAttempts := 1
+	if s.config.Retry != nil && s.config.Retry.MaxRetries > 0 {
+		maxAttempts = s.config.Retry.MaxRetries + 1
+	}
+
+	var processingErr error
+
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		processingErr = s.executeWithTimeout(ctx, msg)
+		if processingErr == nil {
+			return nil
+		}
+
+		if attempt < maxAttempts {
+			log.Warn().
+				Str("source", "gframework").
+				Err(processingErr).
+				Str("message_id", msg.UUID).
+				Int("attempt", attempt).
+				Int("max_attempts", maxAttempts).
+				Msg("Message processing failed, retrying")
+
+			if err := s.waitForRetry(ctx); err != nil {
+				return err
+			}
+		}
+	}
+
+	return processingErr
+}
+
+func (s *Subscriber) executeWithTimeout(ctx context.Context, msg *message.Message) error {
+	if s.config.ExecTimeout <= 0 {
+		return s.messageHandler(ctx, msg.Payload)
+	}
+
+	execCtx, cancel := context.WithTimeout(ctx, s.config.ExecTimeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- s.messageHandler(execCtx, msg.Payload)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-execCtx.Done():
+		if errors.Is(execCtx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("%w: exceeded %v", ErrExecTimeout, s.config.ExecTimeout)
+		}
+
+		return execCtx.Err()
+	}
+}
+
+func (s *Subscriber) waitForRetry(ctx context.Context) error {
+	if s.config.Retry == nil || s.config.Retry.RetryDelay <= 0 {
+		return nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(s.config.Retry.RetryDelay):
+		return nil
+	}
+}
+
+func (s *Subscriber) handleFailedMessage(msg *message.Message, processingErr error) {
+	if s.config.Retry != nil && s.config.Retry.DLQTopic != "" {
+		if dlqErr := s.sendToDLQ(msg, processingErr); dlqErr != nil {
+			log.Error().
+				Str("source", "gframework").
+				Err(dlqErr).
+				Str("message_id", msg.UUID).
+				Msg("Failed to send message to DLQ")
+		} else if s.config.Metrics != nil {
+			s.config.Metrics.MessageSentToDLQ(s.Topic())
+		}
+	}
+
+	// Acknowledge the message to prevent infinite redelivery loop.
+	// The message has either been sent to DLQ or logged as failed after exhausting retries.
+	if !msg.Ack() {
+		log.Warn().
+			Str("source", "gframework").
+			Str("message_id", msg.UUID).
+			Msg("Failed message was already acknowledged")
+	}
+
+	if s.config.Metrics != nil {
+		s.config.Metrics.MessageNacked(s.Topic())
+	}
+}
+
+func (s *Subscriber) acknowledgeMessage(msg *message.Message) {
+	if !msg.Ack() {
+		log.Debug().
+			Str("source", "gframework").
+			Str("message_id", msg.UUID).
+			Msg("The message has already been acknowledged")
+
+		return
+	}
+
+	log.Debug().
+		Str("source", "gframework").
+		Str("message_id", msg.UUID).
+		Msg("The message has been acknowledged successfully")
+
+	if s.config.Metrics != nil {
+		s.config.Metrics.MessageAcked(s.Topic())
+	}
+}
+
+func (s *Subscriber) sendToDLQ(msg *message.Message, processingErr error) error {
+	if s.dlqPublisher == nil {
+		return nil
+	}
+
+	dlqMsg := message.NewMessage(msg.UUID, msg.Payload)
+	dlqMsg.Metadata.Set("original_topic", s.topic)
+	dlqMsg.Metadata.Set("consumer_group", s.consumerGroup)
+	dlqMsg.Metadata.Set("error", processingErr.Error())
+	dlqMsg.Metadata.Set("failed_at", time.Now().UTC().Format(time.RFC3339))
+
+	return s.dlqPublisher.Publish(s.config.Retry.DLQTopic, dlqMsg)
+}
@@ -0,0 +1,137 @@
+package kafkasub_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/andyle182810/gframework/kafkasub"
+	"github.com/stretchr/testify/require"
+)
+
+var testBrokers = []string{"localhost:9092"} //nolint:gochecknoglobals
+
+func noopHandler(_ context.Context, _ message.Payload) error {
+	return nil
+}
+
+func TestNewSubscriber(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		brokers       []string
+		consumerGroup string
+		topic         string
+		handler       kafkasub.MessageHandler
+		expectError   error
+	}{
+		{
+			name:          "valid configuration",
+			brokers:       testBrokers,
+			consumerGroup: "test-group",
+			topic:         "test-topic",
+			handler:       noopHandler,
+			expectError:   nil,
+		},
+		{
+			name:          "no brokers",
+			brokers:       nil,
+			consumerGroup: "test-group",
+			topic:         "test-topic",
+			handler:       noopHandler,
+			expectError:   kafkasub.ErrNoBrokers,
+		},
+		{
+			name:          "empty consumer group",
+			brokers:       testBrokers,
+			consumerGroup: "",
+			topic:         "test-topic",
+			handler:       noopHandler,
+			expectError:   kafkasub.ErrEmptyConsumerGroup,
+		},
+		{
+			name:          "empty topic",
+			brokers:       testBrokers,
+			consumerGroup: "test-group",
+			topic:         "",
+			handler:       noopHandler,
+			expectError:   kafkasub.ErrEmptyTopicName,
+		},
+		{
+			name:          "nil handler",
+			brokers:       testBrokers,
+			consumerGroup: "test-group",
+			topic:         "test-topic",
+			handler:       nil,
+			expectError:   kafkasub.ErrNilMessageHandler,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sub, err := kafkasub.NewSubscriber(tt.brokers, tt.consumerGroup, tt.topic, tt.handler)
+			if tt.expectError != nil {
+				require.ErrorIs(t, err, tt.expectError)
+				require.Nil(t, sub)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, sub)
+		})
+	}
+}
+
+func TestSubscriberAccessors(t *testing.T) {
+	t.Parallel()
+
+	sub, err := kafkasub.NewSubscriber(testBrokers, "orders-group", "orders", noopHandler,
+		kafkasub.WithRetry(3, time.Second, "orders-dlq"),
+		kafkasub.WithExecTimeout(5*time.Second),
+		kafkasub.WithShutdownTimeout(time.Second),
+	)
+	require.NoError(t, err)
+
+	require.Equal(t, "kafkasub-orders-group-orders", sub.Name())
+	require.Equal(t, "orders", sub.Topic())
+	require.Equal(t, "orders-group", sub.ConsumerGroup())
+	require.False(t, sub.IsHealthy())
+}
+
+func TestSubscriberStopWhenNotRunning(t *testing.T) {
+	t.Parallel()
+
+	sub, err := kafkasub.NewSubscriber(testBrokers, "test-group", "test-topic", noopHandler)
+	require.NoError(t, err)
+
+	require.NoError(t, sub.Stop())
+}
+
+func TestMultiSubscriber(t *testing.T) {
+	t.Parallel()
+
+	multi := kafkasub.NewMultiSubscriber("events", testBrokers, "test-group", kafkasub.WithRetry(1, 0, ""))
+
+	require.Equal(t, "events", multi.Name())
+	require.ErrorIs(t, multi.Subscribe("", noopHandler), kafkasub.ErrEmptyTopic)
+	require.ErrorIs(t, multi.Subscribe("orders", nil), kafkasub.ErrNilMessageHandler)
+
+	require.NoError(t, multi.Subscribe("orders", noopHandler))
+	require.NoError(t, multi.Subscribe("payments", noopHandler))
+	require.Equal(t, 2, multi.SubscriberCount())
+	require.False(t, multi.IsHealthy())
+}
+
+func TestMultiSubscriberStartWithNoSubscribers(t *testing.T) {
+	t.Parallel()
+
+	multi := kafkasub.NewMultiSubscriber("empty", testBrokers, "test-group")
+
+	require.NoError(t, multi.Start(t.Context()))
+	require.ErrorIs(t, multi.Start(t.Context()), kafkasub.ErrMultiSubscriberAlreadyRunning)
+}
@@ -0,0 +1,114 @@
+package logutil
+
+import (
+	"context"
+	"maps"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	FieldRequestID = "request_id"
+	FieldTraceID   = "trace_id"
+	FieldSpanID    = "span_id"
+	FieldTenantID  = "tenant_id"
+	FieldUserID    = "user_id"
+)
+
+// FieldsFunc resolves log fields when a logger is built, e.g. from values set by later middleware.
+type FieldsFunc func() map[string]any
+
+type contextKey struct{}
+
+type contextLogger struct {
+	logger *zerolog.Logger
+	fields map[string]any
+	funcs  []FieldsFunc
+}
+
+func fromContext(ctx context.Context) contextLogger {
+	if current, ok := ctx.Value(contextKey{}).(contextLogger); ok {
+		return current
+	}
+
+	return contextLogger{logger: nil, fields: nil, funcs: nil}
+}
+
+// WithLogger sets the logger FromContext builds on. Defaults to the global log.Logger.
+func WithLogger(ctx context.Context, logger *zerolog.Logger) context.Context {
+	current := fromContext(ctx)
+	current.logger = logger
+
+	return context.WithValue(ctx, contextKey{}, current)
+}
+
+// WithFields adds fields to every logger returned by FromContext for ctx and its children. Fields of a
+// child context override those of its parent.
+func WithFields(ctx context.Context, fields map[string]any) context.Context {
+	current := fromContext(ctx)
+
+	merged := make(map[string]any, len(current.fields)+len(fields))
+	maps.Copy(merged, current.fields)
+	maps.Copy(merged, fields)
+	current.fields = merged
+
+	return context.WithValue(ctx, contextKey{}, current)
+}
+
+// WithFieldsFunc adds fields that are resolved every time FromContext is called.
+func WithFieldsFunc(ctx context.Context, fn FieldsFunc) context.Context {
+	current := fromContext(ctx)
+	current.funcs = append(current.funcs[:len(current.funcs):len(current.funcs)], fn)
+
+	return context.WithValue(ctx, contextKey{}, current)
+}
+
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return WithFields(ctx, map[string]any{FieldRequestID: requestID})
+}
+
+func WithTenantID(ctx context.Context, tenantID string) context.Context {
+	return WithFields(ctx, map[string]any{FieldTenantID: tenantID})
+}
+
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return WithFields(ctx, map[string]any{FieldUserID: userID})
+}
+
+// FromContext returns a logger carrying the fields added to ctx and the trace and span IDs of the
+// OpenTelemetry span in ctx, if any.
+func FromContext(ctx context.Context) *zerolog.Logger {
+	current := fromContext(ctx)
+
+	base := current.logger
+	if base == nil {
+		base = &log.Logger
+	}
+
+	fields := make(map[string]any, len(current.fields))
+
+	for _, fn := range current.funcs {
+		for key, value := range fn() {
+			if value != "" && value != nil {
+				fields[key] = value
+			}
+		}
+	}
+
+	maps.Copy(fields, current.fields)
+
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		fields[FieldTraceID] = spanContext.TraceID().String()
+		fields[FieldSpanID] = spanContext.SpanID().String()
+	}
+
+	if len(fields) == 0 {
+		return base
+	}
+
+	logger := base.With().Fields(fields).Logger()
+
+	return &logger
+}
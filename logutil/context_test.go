@@ -0,0 +1,71 @@
+package logutil_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/andyle182810/gframework/logutil"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func logLine(t *testing.T, ctx context.Context) map[string]any {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	logger := zerolog.New(&buf)
+	ctx = logutil.WithLogger(ctx, &logger)
+
+	logutil.FromContext(ctx).Info().Msg("hello")
+
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+
+	return line
+}
+
+func TestFromContext_Fields(t *testing.T) {
+	t.Parallel()
+
+	tenant := "acme"
+
+	ctx := logutil.WithRequestID(t.Context(), "req-1")
+	ctx = logutil.WithUserID(ctx, "user-1")
+	ctx = logutil.WithFieldsFunc(ctx, func() map[string]any {
+		return map[string]any{logutil.FieldTenantID: tenant, logutil.FieldUserID: "from-func"}
+	})
+	child := logutil.WithFields(ctx, map[string]any{"order_id": "o-1", logutil.FieldUserID: "user-2"})
+
+	tenant = "globex"
+
+	line := logLine(t, child)
+	require.Equal(t, "req-1", line[logutil.FieldRequestID])
+	require.Equal(t, "globex", line[logutil.FieldTenantID])
+	require.Equal(t, "user-2", line[logutil.FieldUserID])
+	require.Equal(t, "o-1", line["order_id"])
+
+	line = logLine(t, ctx)
+	require.Equal(t, "user-1", line[logutil.FieldUserID])
+	require.NotContains(t, line, "order_id")
+}
+
+func TestFromContext_TraceID(t *testing.T) {
+	t.Parallel()
+
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{ //nolint:exhaustruct
+		TraceID: trace.TraceID{1, 2, 3},
+		SpanID:  trace.SpanID{4, 5, 6},
+	})
+	ctx := trace.ContextWithSpanContext(t.Context(), spanContext)
+
+	line := logLine(t, ctx)
+	require.Equal(t, spanContext.TraceID().String(), line[logutil.FieldTraceID])
+	require.Equal(t, spanContext.SpanID().String(), line[logutil.FieldSpanID])
+
+	line = logLine(t, t.Context())
+	require.NotContains(t, line, logutil.FieldTraceID)
+}
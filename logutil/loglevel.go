@@ -1,4 +1,5 @@
-// Package logutil provides log-level string parsing for zerolog and PostgreSQL query tracing.
+// Package logutil provides log-level string parsing for zerolog and PostgreSQL query tracing, and
+// context-aware loggers.
 //
 // It offers a single, consistent parsing point for log-level configuration strings used throughout
 // the application. Unknown levels default to Info.
@@ -10,6 +11,20 @@
 //
 // Supported levels: "trace", "debug", "info", "warn", "error", "fatal", "panic" (for zerolog).
 // For PostgreSQL: "trace", "debug", "info", "warn", "error".
+//
+// FromContext returns a logger carrying the request ID, tenant, user and trace ID of the request, so
+// handlers do not pass them into every log call. httpserver wires the request fields through
+// middleware.ContextLogger:
+//
+//	logutil.FromContext(ctx).Info().Str("order_id", id).Msg("Order created")
+//
+// EnableRedaction masks passwords, tokens, card numbers, email addresses and other sensitive data in
+// every event of the global logger:
+//
+//	logutil.EnableRedaction(os.Stdout, logutil.WithRedactFields("ssn"))
+//
+// EnableOTLP additionally exports every event to an OpenTelemetry collector, with the trace context of
+// the event, so logs and traces correlate.
 package logutil
 
 import (
@@ -0,0 +1,295 @@
+package logutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultOTLPShutdownTimeout = 10 * time.Second
+	otlpInstrumentationName    = "github.com/andyle182810/gframework/logutil"
+)
+
+var ErrOTLPEndpointRequired = errors.New("logutil: OTLP endpoint is required")
+
+// OTLPConfig exports log events to an OpenTelemetry collector over OTLP/HTTP.
+type OTLPConfig struct {
+	// Endpoint is the full URL of the collector, e.g. "https://otel-collector:4318/v1/logs".
+	// Plain http URLs disable TLS.
+	Endpoint string
+	// Headers are sent with every export, e.g. an authorization token.
+	Headers map[string]string
+	// ServiceName is reported as the service.name resource attribute.
+	ServiceName string
+	// Timeout of an export. Defaults to the exporter's default of 10s.
+	Timeout time.Duration
+	// ShutdownTimeout bounds the final flush in Stop. Defaults to 10s.
+	ShutdownTimeout time.Duration
+}
+
+// OTLPWriter is a zerolog.LevelWriter that exports events as OpenTelemetry log records. The trace_id and
+// span_id fields added by FromContext or TraceHook become the trace context of the record, so logs
+// correlate with traces. Records are batched; register the writer with the runner, or call Stop, to
+// flush them on shutdown.
+type OTLPWriter struct {
+	provider        *sdklog.LoggerProvider
+	logger          otellog.Logger
+	shutdownTimeout time.Duration
+}
+
+var _ zerolog.LevelWriter = (*OTLPWriter)(nil)
+
+func NewOTLPWriter(ctx context.Context, cfg OTLPConfig) (*OTLPWriter, error) {
+	if cfg.Endpoint == "" {
+		return nil, ErrOTLPEndpointRequired
+	}
+
+	opts := []otlploghttp.Option{otlploghttp.WithEndpointURL(cfg.Endpoint)}
+
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlploghttp.WithHeaders(cfg.Headers))
+	}
+
+	if cfg.Timeout > 0 {
+		opts = append(opts, otlploghttp.WithTimeout(cfg.Timeout))
+	}
+
+	exporter, err := otlploghttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("logutil: failed to create OTLP exporter: %w", err)
+	}
+
+	res := resource.Default()
+	if cfg.ServiceName != "" {
+		res, err = resource.Merge(res, resource.NewSchemaless(attribute.String("service.name", cfg.ServiceName)))
+		if err != nil {
+			return nil, fmt.Errorf("logutil: failed to build OTLP resource: %w", err)
+		}
+	}
+
+	provider := sdklog.NewLoggerProvider(
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)),
+		sdklog.WithResource(res),
+	)
+
+	shutdownTimeout := cfg.ShutdownTimeout
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = defaultOTLPShutdownTimeout
+	}
+
+	return &OTLPWriter{
+		provider:        provider,
+		logger:          provider.Logger(otlpInstrumentationName),
+		shutdownTimeout: shutdownTimeout,
+	}, nil
+}
+
+// EnableOTLP makes the global log.Logger write every event to both w, e.g. os.Stdout, and the collector.
+// Like EnableRedaction, call it before constructing servers and clients.
+func EnableOTLP(ctx context.Context, w io.Writer, cfg OTLPConfig) (*OTLPWriter, error) {
+	writer, err := NewOTLPWriter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Logger = log.Logger.Output(zerolog.MultiLevelWriter(w, writer)).Hook(TraceHook())
+
+	return writer, nil
+}
+
+func (w *OTLPWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel never fails, so that an unreachable collector cannot break logging.
+func (w *OTLPWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	decoder := json.NewDecoder(bytes.NewReader(p))
+	decoder.UseNumber()
+
+	var fields map[string]any
+	if decoder.Decode(&fields) != nil {
+		return len(p), nil
+	}
+
+	ctx, record := eventRecord(level, fields)
+	w.logger.Emit(ctx, record)
+
+	return len(p), nil
+}
+
+// Start is a no-op; the writer exports from the moment it is created.
+func (w *OTLPWriter) Start(_ context.Context) error {
+	return nil
+}
+
+// Stop exports the buffered records and shuts the exporter down.
+func (w *OTLPWriter) Stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), w.shutdownTimeout)
+	defer cancel()
+
+	if err := w.provider.Shutdown(ctx); err != nil {
+		return fmt.Errorf("logutil: failed to shut down OTLP exporter: %w", err)
+	}
+
+	return nil
+}
+
+func (w *OTLPWriter) Name() string {
+	return "logutil-otlp"
+}
+
+type traceHook struct{}
+
+// TraceHook adds the trace and span IDs of the span in the event context, set with Event.Ctx, so events
+// logged through loggers not obtained from FromContext correlate too.
+func TraceHook() zerolog.Hook { //nolint:ireturn
+	return traceHook{}
+}
+
+func (traceHook) Run(e *zerolog.Event, _ zerolog.Level, _ string) {
+	ctx := e.GetCtx()
+	if ctx == nil {
+		return
+	}
+
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		e.Str(FieldTraceID, spanContext.TraceID().String()).Str(FieldSpanID, spanContext.SpanID().String())
+	}
+}
+
+func eventRecord(level zerolog.Level, fields map[string]any) (context.Context, otellog.Record) {
+	var record otellog.Record
+
+	if raw, ok := fields[zerolog.LevelFieldName].(string); ok && level == zerolog.NoLevel {
+		if parsed, err := zerolog.ParseLevel(raw); err == nil {
+			level = parsed
+		}
+	}
+
+	record.SetSeverity(severity(level))
+	record.SetSeverityText(level.String())
+	record.SetObservedTimestamp(time.Now())
+
+	if msg, ok := fields[zerolog.MessageFieldName].(string); ok {
+		record.SetBody(otellog.StringValue(msg))
+	}
+
+	if raw, ok := fields[zerolog.TimestampFieldName].(string); ok {
+		if parsed, err := time.Parse(zerolog.TimeFieldFormat, raw); err == nil {
+			record.SetTimestamp(parsed)
+		}
+	}
+
+	ctx := context.Background()
+
+	traceID, _ := fields[FieldTraceID].(string)
+	spanID, _ := fields[FieldSpanID].(string)
+
+	if spanContext := spanContextFromHex(traceID, spanID); spanContext.IsValid() {
+		ctx = trace.ContextWithSpanContext(ctx, spanContext)
+
+		delete(fields, FieldTraceID)
+		delete(fields, FieldSpanID)
+	}
+
+	for key, value := range fields {
+		switch key {
+		case zerolog.LevelFieldName, zerolog.MessageFieldName, zerolog.TimestampFieldName:
+			continue
+		}
+
+		record.AddAttributes(otellog.KeyValue{Key: key, Value: logValue(value)})
+	}
+
+	return ctx, record
+}
+
+func spanContextFromHex(traceID, spanID string) trace.SpanContext {
+	tid, err := trace.TraceIDFromHex(traceID)
+	if err != nil {
+		return trace.SpanContext{}
+	}
+
+	sid, err := trace.SpanIDFromHex(spanID)
+	if err != nil {
+		return trace.SpanContext{}
+	}
+
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    tid,
+		SpanID:     sid,
+		TraceFlags: trace.FlagsSampled,
+		TraceState: trace.TraceState{},
+		Remote:     false,
+	})
+}
+
+func logValue(value any) otellog.Value {
+	switch typed := value.(type) {
+	case string:
+		return otellog.StringValue(typed)
+	case bool:
+		return otellog.BoolValue(typed)
+	case json.Number:
+		if n, err := typed.Int64(); err == nil {
+			return otellog.Int64Value(n)
+		}
+
+		f, _ := typed.Float64()
+
+		return otellog.Float64Value(f)
+	case []any:
+		values := make([]otellog.Value, 0, len(typed))
+		for _, item := range typed {
+			values = append(values, logValue(item))
+		}
+
+		return otellog.SliceValue(values...)
+	case map[string]any:
+		kvs := make([]otellog.KeyValue, 0, len(typed))
+		for key, item := range typed {
+			kvs = append(kvs, otellog.KeyValue{Key: key, Value: logValue(item)})
+		}
+
+		return otellog.MapValue(kvs...)
+	default:
+		return otellog.Value{}
+	}
+}
+
+func severity(level zerolog.Level) otellog.Severity {
+	switch level {
+	case zerolog.TraceLevel:
+		return otellog.SeverityTrace
+	case zerolog.DebugLevel:
+		return otellog.SeverityDebug
+	case zerolog.InfoLevel:
+		return otellog.SeverityInfo
+	case zerolog.WarnLevel:
+		return otellog.SeverityWarn
+	case zerolog.ErrorLevel:
+		return otellog.SeverityError
+	case zerolog.FatalLevel:
+		return otellog.SeverityFatal
+	case zerolog.PanicLevel:
+		return otellog.SeverityFatal4
+	case zerolog.NoLevel, zerolog.Disabled:
+		return otellog.SeverityUndefined
+	default:
+		return otellog.SeverityUndefined
+	}
+}
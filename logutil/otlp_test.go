@@ -0,0 +1,99 @@
+package logutil_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/andyle182810/gframework/logutil"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+type otlpCollector struct {
+	mu      sync.Mutex
+	records []*logspb.LogRecord
+}
+
+func (c *otlpCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	var req collogspb.ExportLogsServiceRequest
+	if err := proto.Unmarshal(body, &req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+
+		return
+	}
+
+	c.mu.Lock()
+	for _, resourceLogs := range req.GetResourceLogs() {
+		for _, scopeLogs := range resourceLogs.GetScopeLogs() {
+			c.records = append(c.records, scopeLogs.GetLogRecords()...)
+		}
+	}
+	c.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestOTLPWriter(t *testing.T) {
+	t.Parallel()
+
+	collector := &otlpCollector{} //nolint:exhaustruct
+	server := httptest.NewServer(collector)
+	t.Cleanup(server.Close)
+
+	writer, err := logutil.NewOTLPWriter(t.Context(), logutil.OTLPConfig{
+		Endpoint:        server.URL + "/v1/logs",
+		Headers:         nil,
+		ServiceName:     "orders",
+		Timeout:         0,
+		ShutdownTimeout: 0,
+	})
+	require.NoError(t, err)
+
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{ //nolint:exhaustruct
+		TraceID: trace.TraceID{1, 2, 3},
+		SpanID:  trace.SpanID{4, 5, 6},
+	})
+	ctx := trace.ContextWithSpanContext(t.Context(), spanContext)
+
+	logger := zerolog.New(writer).Hook(logutil.TraceHook())
+	logger.Warn().Ctx(ctx).Str("order_id", "o-1").Int("attempt", 2).Msg("payment retried")
+
+	require.NoError(t, writer.Stop())
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+
+	require.Len(t, collector.records, 1)
+
+	record := collector.records[0]
+	require.Equal(t, "payment retried", record.GetBody().GetStringValue())
+	require.Equal(t, logspb.SeverityNumber_SEVERITY_NUMBER_WARN, record.GetSeverityNumber())
+	require.Equal(t, spanContext.TraceID().String(), trace.TraceID(record.GetTraceId()).String())
+	require.Equal(t, spanContext.SpanID().String(), trace.SpanID(record.GetSpanId()).String())
+
+	attributes := map[string]any{}
+	for _, kv := range record.GetAttributes() {
+		if value := kv.GetValue().GetStringValue(); value != "" {
+			attributes[kv.GetKey()] = value
+		} else {
+			attributes[kv.GetKey()] = kv.GetValue().GetIntValue()
+		}
+	}
+
+	require.Equal(t, map[string]any{"order_id": "o-1", "attempt": int64(2)}, attributes)
+}
@@ -0,0 +1,246 @@
+package logutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	DefaultRedactMask = "[REDACTED]"
+	luhnModulo        = 10
+	luhnMaxDigit      = 9
+)
+
+// DefaultRedactFields are the field name patterns redacted by DefaultRedactor.
+func DefaultRedactFields() []string {
+	return []string{
+		"password", "passwd", "secret", "token", "authorization", "api_key", "apikey", "cookie",
+		"private_key", "client_assertion",
+	}
+}
+
+var (
+	cardNumberPattern = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+	emailPattern      = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+)
+
+type valueRule struct {
+	pattern *regexp.Regexp
+	accept  func(match string) bool
+}
+
+// Redactor masks sensitive fields and values in zerolog JSON events. A field is masked entirely when its
+// name contains one of the field patterns, case-insensitively; string values anywhere in the event, the
+// message included, have the parts matching a value pattern masked.
+type Redactor struct {
+	fields []string
+	values []valueRule
+	mask   string
+}
+
+type RedactOption func(*Redactor)
+
+// WithRedactFields adds field name patterns, e.g. "ssn".
+func WithRedactFields(patterns ...string) RedactOption {
+	return func(r *Redactor) {
+		for _, pattern := range patterns {
+			r.fields = append(r.fields, strings.ToLower(pattern))
+		}
+	}
+}
+
+// WithRedactValues adds value patterns.
+func WithRedactValues(patterns ...*regexp.Regexp) RedactOption {
+	return func(r *Redactor) {
+		for _, pattern := range patterns {
+			r.values = append(r.values, valueRule{pattern: pattern, accept: nil})
+		}
+	}
+}
+
+// WithCardNumbers masks digit sequences that look like payment card numbers and pass the Luhn check.
+func WithCardNumbers() RedactOption {
+	return func(r *Redactor) {
+		r.values = append(r.values, valueRule{pattern: cardNumberPattern, accept: luhnValid})
+	}
+}
+
+// WithEmails masks email addresses.
+func WithEmails() RedactOption {
+	return WithRedactValues(emailPattern)
+}
+
+func WithRedactMask(mask string) RedactOption {
+	return func(r *Redactor) {
+		r.mask = mask
+	}
+}
+
+func NewRedactor(opts ...RedactOption) *Redactor {
+	r := &Redactor{
+		fields: nil,
+		values: nil,
+		mask:   DefaultRedactMask,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// DefaultRedactor masks DefaultRedactFields, card numbers and email addresses, plus opts.
+func DefaultRedactor(opts ...RedactOption) *Redactor {
+	defaults := []RedactOption{WithRedactFields(DefaultRedactFields()...), WithCardNumbers(), WithEmails()}
+
+	return NewRedactor(append(defaults, opts...)...)
+}
+
+// EnableRedaction makes the global log.Logger, which every framework package logs through, redact its
+// events before writing them to w. Call it before constructing servers and clients, since some of them
+// copy the global logger.
+func EnableRedaction(w io.Writer, opts ...RedactOption) {
+	log.Logger = log.Logger.Output(DefaultRedactor(opts...).Writer(w))
+}
+
+// Writer returns a zerolog.LevelWriter that redacts events before passing them to w. Wrap the final
+// output, e.g. zerolog.ConsoleWriter, since redaction needs the JSON events.
+func (r *Redactor) Writer(w io.Writer) zerolog.LevelWriter {
+	out, ok := w.(zerolog.LevelWriter)
+	if !ok {
+		out = zerolog.LevelWriterAdapter{Writer: w}
+	}
+
+	return &redactWriter{redactor: r, out: out}
+}
+
+// Redact returns event with the sensitive fields and values masked. Events that are not JSON objects
+// only have their value patterns masked.
+func (r *Redactor) Redact(event []byte) []byte {
+	decoder := json.NewDecoder(bytes.NewReader(event))
+	decoder.UseNumber()
+
+	var fields map[string]any
+	if err := decoder.Decode(&fields); err != nil {
+		return []byte(r.redactString(string(event)))
+	}
+
+	redacted, err := json.Marshal(r.RedactFields(fields))
+	if err != nil {
+		return event
+	}
+
+	if bytes.HasSuffix(event, []byte("\n")) {
+		redacted = append(redacted, '\n')
+	}
+
+	return redacted
+}
+
+// RedactFields masks fields in place and returns it.
+func (r *Redactor) RedactFields(fields map[string]any) map[string]any {
+	for key, value := range fields {
+		if r.sensitiveField(key) {
+			fields[key] = r.mask
+
+			continue
+		}
+
+		fields[key] = r.redactValue(value)
+	}
+
+	return fields
+}
+
+func (r *Redactor) redactValue(value any) any {
+	switch typed := value.(type) {
+	case string:
+		return r.redactString(typed)
+	case map[string]any:
+		return r.RedactFields(typed)
+	case []any:
+		for i, item := range typed {
+			typed[i] = r.redactValue(item)
+		}
+
+		return typed
+	default:
+		return value
+	}
+}
+
+func (r *Redactor) redactString(value string) string {
+	for _, rule := range r.values {
+		value = rule.pattern.ReplaceAllStringFunc(value, func(match string) string {
+			if rule.accept != nil && !rule.accept(match) {
+				return match
+			}
+
+			return r.mask
+		})
+	}
+
+	return value
+}
+
+func (r *Redactor) sensitiveField(name string) bool {
+	name = strings.ToLower(name)
+
+	for _, pattern := range r.fields {
+		if strings.Contains(name, pattern) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type redactWriter struct {
+	redactor *Redactor
+	out      zerolog.LevelWriter
+}
+
+func (w *redactWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel reports len(p) on success since callers compare it with the event they passed in.
+func (w *redactWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if _, err := w.out.WriteLevel(level, w.redactor.Redact(p)); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+func luhnValid(match string) bool {
+	sum := 0
+	double := false
+
+	for i := len(match) - 1; i >= 0; i-- {
+		c := match[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+
+		digit := int(c - '0')
+		if double {
+			digit *= 2
+			if digit > luhnMaxDigit {
+				digit -= luhnMaxDigit
+			}
+		}
+
+		sum += digit
+		double = !double
+	}
+
+	return sum%luhnModulo == 0
+}
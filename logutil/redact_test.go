@@ -0,0 +1,64 @@
+package logutil_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"testing"
+
+	"github.com/andyle182810/gframework/logutil"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactor_Writer(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	redactor := logutil.DefaultRedactor(
+		logutil.WithRedactFields("ssn"),
+		logutil.WithRedactValues(regexp.MustCompile(`acct-\d+`)),
+	)
+	logger := zerolog.New(redactor.Writer(&buf))
+
+	logger.Info().
+		Str("Authorization", "Bearer abc").
+		Str("user_password", "hunter2").
+		Str("ssn", "123-45-6789").
+		Str("note", "paid with 4111 1111 1111 1111 from acct-42").
+		Str("order_id", "1234567890123").
+		Int("amount", 1500).
+		Interface("body", map[string]any{
+			"email":    "alice@example.com",
+			"contacts": []any{"bob@example.com", "n/a"},
+			"token":    "t-1",
+		}).
+		Msg("charged alice@example.com")
+
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+
+	require.Equal(t, logutil.DefaultRedactMask, line["Authorization"])
+	require.Equal(t, logutil.DefaultRedactMask, line["user_password"])
+	require.Equal(t, logutil.DefaultRedactMask, line["ssn"])
+	require.Equal(t, "paid with [REDACTED] from [REDACTED]", line["note"])
+	require.Equal(t, "1234567890123", line["order_id"], "not a valid card number")
+	require.InDelta(t, 1500, line["amount"], 0)
+	require.Equal(t, "charged [REDACTED]", line[zerolog.MessageFieldName])
+	require.Equal(t, "info", line[zerolog.LevelFieldName])
+
+	body, ok := line["body"].(map[string]any)
+	require.True(t, ok)
+	require.Equal(t, logutil.DefaultRedactMask, body["email"])
+	require.Equal(t, []any{logutil.DefaultRedactMask, "n/a"}, body["contacts"])
+	require.Equal(t, logutil.DefaultRedactMask, body["token"])
+}
+
+func TestRedactor_NonJSON(t *testing.T) {
+	t.Parallel()
+
+	redactor := logutil.NewRedactor(logutil.WithEmails(), logutil.WithRedactMask("***"))
+
+	require.Equal(t, "mail ***\n", string(redactor.Redact([]byte("mail alice@example.com\n"))))
+}
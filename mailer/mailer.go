@@ -0,0 +1,201 @@
+// Package mailer sends email through SMTP, Amazon SES or SendGrid behind one Sender interface, with
+// templated HTML and text bodies, attachments, rate limiting and an optional queue that sends in the
+// background with retries.
+//
+// Basic usage:
+//
+//	sender, err := mailer.NewSMTP(mailer.SMTPConfig{Host: "smtp.example.com", Port: 587, Username: user, Password: pass})
+//	templates, err := mailer.ParseTemplates(templatesFS)
+//
+//	m, err := mailer.New(sender,
+//	    mailer.WithDefaultFrom("Orders <orders@example.com>"),
+//	    mailer.WithTemplates(templates),
+//	    mailer.WithRateLimit(10, 20),
+//	)
+//
+//	err = m.SendTemplate(ctx, &mailer.Message{To: []string{user.Email}}, "order_confirmed", order)
+//
+// Wrap a Mailer with NewQueue to enqueue messages in Valkey and send them from workers, so a slow or
+// unavailable provider never delays the request that triggered the email.
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"golang.org/x/time/rate"
+)
+
+var (
+	ErrNilSender      = errors.New("mailer: sender is nil")
+	ErrNoRecipients   = errors.New("mailer: message has no recipients")
+	ErrNoSender       = errors.New("mailer: message has no from address")
+	ErrNoBody         = errors.New("mailer: message has neither a text nor an HTML body")
+	ErrInvalidAddress = errors.New("mailer: invalid email address")
+	ErrInvalidHeader  = errors.New("mailer: invalid header name")
+	ErrNoTemplates    = errors.New("mailer: no templates configured")
+	ErrSendFailed     = errors.New("mailer: failed to send message")
+)
+
+// Message is an email. Addresses are RFC 5322 addresses such as "jane@example.com" or
+// "Jane Doe <jane@example.com>".
+type Message struct {
+	From    string            `json:"from"`
+	To      []string          `json:"to"`
+	Cc      []string          `json:"cc,omitempty"`
+	Bcc     []string          `json:"bcc,omitempty"`
+	ReplyTo string            `json:"replyTo,omitempty"`
+	Subject string            `json:"subject"`
+	Text    string            `json:"text,omitempty"`
+	HTML    string            `json:"html,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	// Attachments are sent as files, or inline for use in the HTML body when they have a ContentID.
+	Attachments []Attachment `json:"attachments,omitempty"`
+}
+
+type Attachment struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"contentType,omitempty"`
+	Data        []byte `json:"data"`
+	// ContentID makes the attachment inline, referenced from the HTML body as "cid:<ContentID>".
+	ContentID string `json:"contentId,omitempty"`
+}
+
+// Recipients returns the addresses of To, Cc and Bcc.
+func (m *Message) Recipients() []string {
+	recipients := make([]string, 0, len(m.To)+len(m.Cc)+len(m.Bcc))
+	recipients = append(recipients, m.To...)
+	recipients = append(recipients, m.Cc...)
+
+	return append(recipients, m.Bcc...)
+}
+
+// Validate checks that the message has a sender, recipients and a body, and that its addresses and
+// header names are well-formed.
+func (m *Message) Validate() error {
+	if m.From == "" {
+		return ErrNoSender
+	}
+
+	if len(m.Recipients()) == 0 {
+		return ErrNoRecipients
+	}
+
+	if m.Text == "" && m.HTML == "" {
+		return ErrNoBody
+	}
+
+	addresses := append([]string{m.From}, m.Recipients()...)
+	if m.ReplyTo != "" {
+		addresses = append(addresses, m.ReplyTo)
+	}
+
+	for _, address := range addresses {
+		if _, err := mail.ParseAddress(address); err != nil {
+			return fmt.Errorf("%w: %q", ErrInvalidAddress, address)
+		}
+	}
+
+	for key := range m.Headers {
+		if key == "" || strings.ContainsAny(key, " :\r\n") {
+			return fmt.Errorf("%w: %q", ErrInvalidHeader, key)
+		}
+	}
+
+	return nil
+}
+
+// Sender delivers a message through a provider.
+type Sender interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+type Option func(*Mailer)
+
+// WithDefaultFrom sets the from address of messages without one.
+func WithDefaultFrom(from string) Option {
+	return func(m *Mailer) {
+		m.defaultFrom = from
+	}
+}
+
+// WithRateLimit limits the messages sent per second, allowing bursts of burst messages, to stay within
+// the sending quota of the provider. Send waits for the limiter until its context is done.
+func WithRateLimit(perSecond float64, burst int) Option {
+	return func(m *Mailer) {
+		if perSecond > 0 {
+			m.limiter = rate.NewLimiter(rate.Limit(perSecond), max(burst, 1))
+		}
+	}
+}
+
+func WithTemplates(templates *Templates) Option {
+	return func(m *Mailer) {
+		m.templates = templates
+	}
+}
+
+// Mailer validates messages, fills in defaults and sends them through its Sender.
+type Mailer struct {
+	sender      Sender
+	defaultFrom string
+	limiter     *rate.Limiter
+	templates   *Templates
+}
+
+func New(sender Sender, opts ...Option) (*Mailer, error) {
+	if sender == nil {
+		return nil, ErrNilSender
+	}
+
+	m := &Mailer{sender: sender, defaultFrom: "", limiter: nil, templates: nil}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m, nil
+}
+
+func (m *Mailer) Send(ctx context.Context, msg *Message) error {
+	if msg.From == "" {
+		msg.From = m.defaultFrom
+	}
+
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+
+	if m.limiter != nil {
+		if err := m.limiter.Wait(ctx); err != nil {
+			return fmt.Errorf("mailer: rate limit wait: %w", err)
+		}
+	}
+
+	if err := m.sender.Send(ctx, msg); err != nil {
+		return fmt.Errorf("%w: %w", ErrSendFailed, err)
+	}
+
+	return nil
+}
+
+// SendTemplate renders the named template into msg, see Templates.Render, and sends it.
+func (m *Mailer) SendTemplate(ctx context.Context, msg *Message, name string, data any) error {
+	if err := m.Render(msg, name, data); err != nil {
+		return err
+	}
+
+	return m.Send(ctx, msg)
+}
+
+// Render renders the named template into msg without sending it, e.g. before enqueueing it.
+func (m *Mailer) Render(msg *Message, name string, data any) error {
+	if m.templates == nil {
+		return ErrNoTemplates
+	}
+
+	return m.templates.Render(msg, name, data)
+}
@@ -0,0 +1,276 @@
+//nolint:exhaustruct
+package mailer_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/andyle182810/gframework/mailer"
+	"github.com/stretchr/testify/require"
+)
+
+var errProviderDown = errors.New("provider down")
+
+type recordingSender struct {
+	mu   sync.Mutex
+	sent []*mailer.Message
+	err  error
+}
+
+func (s *recordingSender) Send(_ context.Context, msg *mailer.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+
+	s.sent = append(s.sent, msg)
+
+	return nil
+}
+
+func (s *recordingSender) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.sent)
+}
+
+func TestMessage_Validate(t *testing.T) {
+	t.Parallel()
+
+	valid := func() *mailer.Message {
+		return &mailer.Message{From: "a@example.com", To: []string{"b@example.com"}, Text: "hi"}
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*mailer.Message)
+		wantErr error
+	}{
+		{name: "valid", modify: func(*mailer.Message) {}},
+		{name: "no from", modify: func(m *mailer.Message) { m.From = "" }, wantErr: mailer.ErrNoSender},
+		{name: "no recipients", modify: func(m *mailer.Message) { m.To = nil }, wantErr: mailer.ErrNoRecipients},
+		{name: "bcc only", modify: func(m *mailer.Message) { m.To, m.Bcc = nil, []string{"c@example.com"} }},
+		{name: "no body", modify: func(m *mailer.Message) { m.Text = "" }, wantErr: mailer.ErrNoBody},
+		{name: "bad address", modify: func(m *mailer.Message) { m.Cc = []string{"not an address"} }, wantErr: mailer.ErrInvalidAddress},
+		{
+			name:    "header injection",
+			modify:  func(m *mailer.Message) { m.Headers = map[string]string{"X-Tag\r\nBcc": "x"} },
+			wantErr: mailer.ErrInvalidHeader,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			msg := valid()
+			tt.modify(msg)
+
+			err := msg.Validate()
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMessage_Raw(t *testing.T) {
+	t.Parallel()
+
+	msg := &mailer.Message{
+		From:    "Orders <orders@example.com>",
+		To:      []string{"Zoë <zoe@example.com>"},
+		Bcc:     []string{"audit@example.com"},
+		Subject: "Your order — #42",
+		Text:    "Thanks for your order.",
+		HTML:    `<p>Thanks!</p><img src="cid:logo">`,
+		Headers: map[string]string{"X-Campaign": "orders"},
+		Attachments: []mailer.Attachment{
+			{Filename: "invoice.pdf", ContentType: "application/pdf", Data: []byte("%PDF-1.7")},
+			{Filename: "logo.png", ContentType: "image/png", Data: []byte("png"), ContentID: "logo"},
+		},
+	}
+
+	raw, err := msg.Raw()
+	require.NoError(t, err)
+
+	parsed, err := mail.ReadMessage(bytes.NewReader(raw))
+	require.NoError(t, err)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	require.Equal(t, msg.Subject, subject)
+
+	to, err := parsed.Header.AddressList("To")
+	require.NoError(t, err)
+	require.Equal(t, "Zoë", to[0].Name)
+	require.Empty(t, parsed.Header.Get("Bcc"), "bcc recipients are not disclosed")
+	require.Equal(t, "orders", parsed.Header.Get("X-Campaign"))
+	require.Contains(t, parsed.Header.Get("Message-Id"), "@example.com>")
+
+	mixed := readParts(t, parsed.Header.Get("Content-Type"), parsed.Body, "multipart/mixed")
+	require.Len(t, mixed, 2)
+	require.Equal(t, "attachment; filename=invoice.pdf", mixed[1].header.Get("Content-Disposition"))
+
+	pdf, err := base64.StdEncoding.DecodeString(string(mixed[1].body))
+	require.NoError(t, err)
+	require.Equal(t, "%PDF-1.7", string(pdf))
+
+	related := readParts(t, mixed[0].header.Get("Content-Type"), bytes.NewReader(mixed[0].body), "multipart/related")
+	require.Len(t, related, 2)
+	require.Equal(t, "<logo>", related[1].header.Get("Content-Id"))
+
+	alternative := readParts(t, related[0].header.Get("Content-Type"), bytes.NewReader(related[0].body), "multipart/alternative")
+	require.Len(t, alternative, 2)
+	require.Equal(t, "Thanks for your order.", string(alternative[0].body))
+	require.Equal(t, `<p>Thanks!</p><img src="cid:logo">`, string(alternative[1].body))
+}
+
+type testPart struct {
+	header textproto.MIMEHeader
+	body   []byte
+}
+
+func readParts(t *testing.T, contentType string, body io.Reader, wantType string) []testPart {
+	t.Helper()
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	require.NoError(t, err)
+	require.Equal(t, wantType, mediaType)
+
+	var parts []testPart
+
+	reader := multipart.NewReader(body, params["boundary"])
+
+	for {
+		p, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return parts
+		}
+
+		require.NoError(t, err)
+
+		data, err := io.ReadAll(p)
+		require.NoError(t, err)
+
+		parts = append(parts, testPart{header: p.Header, body: bytes.TrimSpace(data)})
+	}
+}
+
+func TestMailer_Send(t *testing.T) {
+	t.Parallel()
+
+	t.Run("fills in the default from address", func(t *testing.T) {
+		t.Parallel()
+
+		sender := &recordingSender{}
+
+		m, err := mailer.New(sender, mailer.WithDefaultFrom("noreply@example.com"))
+		require.NoError(t, err)
+
+		require.NoError(t, m.Send(t.Context(), &mailer.Message{To: []string{"b@example.com"}, Text: "hi"}))
+		require.Equal(t, "noreply@example.com", sender.sent[0].From)
+	})
+
+	t.Run("does not send invalid messages", func(t *testing.T) {
+		t.Parallel()
+
+		sender := &recordingSender{}
+
+		m, err := mailer.New(sender)
+		require.NoError(t, err)
+
+		require.ErrorIs(t, m.Send(t.Context(), &mailer.Message{To: []string{"b@example.com"}, Text: "hi"}), mailer.ErrNoSender)
+		require.Zero(t, sender.count())
+	})
+
+	t.Run("wraps provider errors", func(t *testing.T) {
+		t.Parallel()
+
+		m, err := mailer.New(&recordingSender{err: errProviderDown}, mailer.WithDefaultFrom("a@example.com"))
+		require.NoError(t, err)
+
+		err = m.Send(t.Context(), &mailer.Message{To: []string{"b@example.com"}, Text: "hi"})
+		require.ErrorIs(t, err, mailer.ErrSendFailed)
+		require.ErrorIs(t, err, errProviderDown)
+	})
+
+	t.Run("rate limits sends", func(t *testing.T) {
+		t.Parallel()
+
+		sender := &recordingSender{}
+
+		m, err := mailer.New(sender, mailer.WithDefaultFrom("a@example.com"), mailer.WithRateLimit(1, 1))
+		require.NoError(t, err)
+
+		require.NoError(t, m.Send(t.Context(), &mailer.Message{To: []string{"b@example.com"}, Text: "hi"}))
+
+		ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+		defer cancel()
+
+		err = m.Send(ctx, &mailer.Message{To: []string{"b@example.com"}, Text: "hi"})
+		require.Error(t, err, "the second send waits longer than the context allows")
+		require.Equal(t, 1, sender.count())
+	})
+
+	t.Run("rejects a nil sender", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := mailer.New(nil)
+		require.ErrorIs(t, err, mailer.ErrNilSender)
+	})
+
+	t.Run("requires templates to render", func(t *testing.T) {
+		t.Parallel()
+
+		m, err := mailer.New(&recordingSender{})
+		require.NoError(t, err)
+
+		require.ErrorIs(t, m.SendTemplate(t.Context(), &mailer.Message{}, "welcome", nil), mailer.ErrNoTemplates)
+	})
+}
+
+func TestTemplates_Render(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"_footer.html": {Data: []byte(`{{define "footer"}}<small>Example Inc.</small>{{end}}`)},
+		"welcome.html": {Data: []byte(`{{define "subject"}}Welcome, {{.Name}} & co{{end}}<p>Hi {{.Name}}</p>{{template "footer"}}`)},
+		"welcome.txt":  {Data: []byte(`Hi {{.Name}}`)},
+		"reset.txt":    {Data: []byte(`{{define "subject"}}Reset your password{{end}}Code: {{.Code}}`)},
+		"README.md":    {Data: []byte(`ignored`)},
+	}
+
+	templates, err := mailer.ParseTemplates(fsys)
+	require.NoError(t, err)
+
+	msg := &mailer.Message{Subject: "fallback"}
+	require.NoError(t, templates.Render(msg, "welcome", map[string]string{"Name": "<Jane>"}))
+	require.Equal(t, "Welcome, <Jane> & co", msg.Subject)
+	require.Equal(t, "<p>Hi &lt;Jane&gt;</p><small>Example Inc.</small>", msg.HTML)
+	require.Equal(t, "Hi <Jane>", msg.Text)
+
+	msg = &mailer.Message{}
+	require.NoError(t, templates.Render(msg, "reset", map[string]string{"Code": "123456"}))
+	require.Equal(t, "Reset your password", msg.Subject)
+	require.Equal(t, "Code: 123456", msg.Text)
+	require.Empty(t, msg.HTML)
+
+	require.ErrorIs(t, templates.Render(&mailer.Message{}, "missing", nil), mailer.ErrTemplateNotFound)
+}
@@ -0,0 +1,254 @@
+package mailer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const base64LineLength = 76
+
+// mimePart is an entity of a MIME message: its headers and encoded body.
+type mimePart struct {
+	header textproto.MIMEHeader
+	body   []byte
+}
+
+// Raw encodes the message as a MIME document as sent over SMTP, with a multipart/alternative body when
+// it has both text and HTML, wrapped in multipart/related for inline attachments and in
+// multipart/mixed for other attachments. Bcc recipients are left out of the headers.
+func (m *Message) Raw() ([]byte, error) {
+	body, err := m.mimeBody()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+
+	writeHeader := func(key, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", key, value)
+	}
+
+	writeHeader("From", formatAddresses([]string{m.From}))
+
+	if len(m.To) > 0 {
+		writeHeader("To", formatAddresses(m.To))
+	}
+
+	if len(m.Cc) > 0 {
+		writeHeader("Cc", formatAddresses(m.Cc))
+	}
+
+	if m.ReplyTo != "" {
+		writeHeader("Reply-To", formatAddresses([]string{m.ReplyTo}))
+	}
+
+	writeHeader("Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	writeHeader("Date", time.Now().Format(time.RFC1123Z))
+	writeHeader("Message-ID", messageID(m.From))
+	writeHeader("MIME-Version", "1.0")
+
+	keys := make([]string, 0, len(m.Headers))
+	for key := range m.Headers {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		writeHeader(textproto.CanonicalMIMEHeaderKey(key), mime.QEncoding.Encode("utf-8", m.Headers[key]))
+	}
+
+	writePartHeader(&buf, body.header)
+	buf.WriteString("\r\n")
+	buf.Write(body.body)
+
+	return buf.Bytes(), nil
+}
+
+func (m *Message) mimeBody() (mimePart, error) {
+	var alternatives []mimePart
+
+	if m.Text != "" {
+		alternatives = append(alternatives, textPart("text/plain", m.Text))
+	}
+
+	if m.HTML != "" {
+		alternatives = append(alternatives, textPart("text/html", m.HTML))
+	}
+
+	if len(alternatives) == 0 {
+		return mimePart{}, ErrNoBody
+	}
+
+	body := alternatives[0]
+
+	if len(alternatives) > 1 {
+		var err error
+
+		if body, err = multipartOf("alternative", alternatives); err != nil {
+			return mimePart{}, err
+		}
+	}
+
+	var inline, attached []mimePart
+
+	for _, attachment := range m.Attachments {
+		if attachment.ContentID != "" {
+			inline = append(inline, attachmentPart(attachment, "inline"))
+		} else {
+			attached = append(attached, attachmentPart(attachment, "attachment"))
+		}
+	}
+
+	if len(inline) > 0 {
+		var err error
+
+		if body, err = multipartOf("related", append([]mimePart{body}, inline...)); err != nil {
+			return mimePart{}, err
+		}
+	}
+
+	if len(attached) > 0 {
+		var err error
+
+		if body, err = multipartOf("mixed", append([]mimePart{body}, attached...)); err != nil {
+			return mimePart{}, err
+		}
+	}
+
+	return body, nil
+}
+
+func textPart(contentType, text string) mimePart {
+	var buf bytes.Buffer
+
+	writer := quotedprintable.NewWriter(&buf)
+	_, _ = writer.Write([]byte(strings.ReplaceAll(text, "\r\n", "\n")))
+	_ = writer.Close()
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", contentType+"; charset=utf-8")
+	header.Set("Content-Transfer-Encoding", "quoted-printable")
+
+	return mimePart{header: header, body: buf.Bytes()}
+}
+
+func attachmentPart(attachment Attachment, disposition string) mimePart {
+	contentType := attachment.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", mime.FormatMediaType(contentType, map[string]string{"name": attachment.Filename}))
+	header.Set("Content-Transfer-Encoding", "base64")
+	header.Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": attachment.Filename}))
+
+	if attachment.ContentID != "" {
+		header.Set("Content-ID", "<"+attachment.ContentID+">")
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(attachment.Data)
+
+	var buf bytes.Buffer
+
+	for len(encoded) > base64LineLength {
+		buf.WriteString(encoded[:base64LineLength] + "\r\n")
+		encoded = encoded[base64LineLength:]
+	}
+
+	buf.WriteString(encoded)
+
+	return mimePart{header: header, body: buf.Bytes()}
+}
+
+func multipartOf(subtype string, parts []mimePart) (mimePart, error) {
+	var buf bytes.Buffer
+
+	writer := multipart.NewWriter(&buf)
+
+	for _, part := range parts {
+		w, err := writer.CreatePart(part.header)
+		if err != nil {
+			return mimePart{}, fmt.Errorf("mailer: failed to encode message: %w", err)
+		}
+
+		if _, err := w.Write(part.body); err != nil {
+			return mimePart{}, fmt.Errorf("mailer: failed to encode message: %w", err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return mimePart{}, fmt.Errorf("mailer: failed to encode message: %w", err)
+	}
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", mime.FormatMediaType("multipart/"+subtype, map[string]string{"boundary": writer.Boundary()}))
+
+	return mimePart{header: header, body: buf.Bytes()}, nil
+}
+
+func writePartHeader(buf *bytes.Buffer, header textproto.MIMEHeader) {
+	keys := make([]string, 0, len(header))
+	for key := range header {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		for _, value := range header[key] {
+			fmt.Fprintf(buf, "%s: %s\r\n", key, value)
+		}
+	}
+}
+
+// formatAddresses encodes display names, e.g. with non-ASCII characters, as RFC 2047 words.
+func formatAddresses(addresses []string) string {
+	formatted := make([]string, 0, len(addresses))
+
+	for _, address := range addresses {
+		parsed, err := mail.ParseAddress(address)
+		if err != nil {
+			formatted = append(formatted, address)
+
+			continue
+		}
+
+		formatted = append(formatted, parsed.String())
+	}
+
+	return strings.Join(formatted, ", ")
+}
+
+func messageID(from string) string {
+	domain := "localhost"
+
+	if parsed, err := mail.ParseAddress(from); err == nil {
+		if at := strings.LastIndex(parsed.Address, "@"); at >= 0 {
+			domain = parsed.Address[at+1:]
+		}
+	}
+
+	return "<" + uuid.NewString() + "@" + domain + ">"
+}
+
+// addressOnly returns the bare address of an RFC 5322 address, as SMTP envelopes require.
+func addressOnly(address string) string {
+	if parsed, err := mail.ParseAddress(address); err == nil {
+		return parsed.Address
+	}
+
+	return address
+}
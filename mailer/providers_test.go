@@ -0,0 +1,266 @@
+//nolint:exhaustruct
+package mailer_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/andyle182810/gframework/mailer"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSMTPServer accepts one message per connection without TLS or auth and records the envelope.
+type fakeSMTPServer struct {
+	listener net.Listener
+
+	mu         sync.Mutex
+	from       string
+	recipients []string
+	data       string
+}
+
+func startFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := &fakeSMTPServer{listener: listener}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go server.serve(conn)
+		}
+	}()
+
+	return server
+}
+
+func (s *fakeSMTPServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port //nolint:forcetypeassert
+}
+
+func (s *fakeSMTPServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = conn.Write([]byte(line + "\r\n")) }
+
+	reply("220 localhost ESMTP")
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		command := strings.TrimSpace(line)
+		upper := strings.ToUpper(command)
+
+		switch {
+		case strings.HasPrefix(upper, "EHLO"):
+			reply("250-localhost")
+			reply("250 8BITMIME")
+		case strings.HasPrefix(upper, "MAIL FROM:"):
+			s.mu.Lock()
+			s.from, _, _ = strings.Cut(strings.TrimPrefix(command[len("MAIL FROM:"):], "<"), ">")
+			s.mu.Unlock()
+			reply("250 OK")
+		case strings.HasPrefix(upper, "RCPT TO:"):
+			s.mu.Lock()
+			s.recipients = append(s.recipients, strings.Trim(command[len("RCPT TO:"):], "<>"))
+			s.mu.Unlock()
+			reply("250 OK")
+		case upper == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+
+			var data strings.Builder
+
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+
+				if dataLine == ".\r\n" {
+					break
+				}
+
+				data.WriteString(dataLine)
+			}
+
+			s.mu.Lock()
+			s.data = data.String()
+			s.mu.Unlock()
+			reply("250 OK")
+		case upper == "QUIT":
+			reply("221 Bye")
+
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+func TestSMTPSender_Send(t *testing.T) {
+	t.Parallel()
+
+	server := startFakeSMTPServer(t)
+
+	sender, err := mailer.NewSMTP(mailer.SMTPConfig{Host: "127.0.0.1", Port: server.port(), Security: mailer.SMTPInsecure})
+	require.NoError(t, err)
+
+	err = sender.Send(t.Context(), &mailer.Message{
+		From:    "Orders <orders@example.com>",
+		To:      []string{"Jane <jane@example.com>"},
+		Bcc:     []string{"audit@example.com"},
+		Subject: "Hello",
+		Text:    "Hi Jane",
+	})
+	require.NoError(t, err)
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	require.Equal(t, "orders@example.com", server.from)
+	require.Equal(t, []string{"jane@example.com", "audit@example.com"}, server.recipients)
+	require.Contains(t, server.data, "Subject: Hello\r\n")
+	require.NotContains(t, server.data, "audit@example.com")
+}
+
+func TestSMTPSender_RequiresStartTLS(t *testing.T) {
+	t.Parallel()
+
+	server := startFakeSMTPServer(t)
+
+	sender, err := mailer.NewSMTP(mailer.SMTPConfig{Host: "127.0.0.1", Port: server.port()})
+	require.NoError(t, err)
+
+	err = sender.Send(t.Context(), &mailer.Message{From: "a@example.com", To: []string{"b@example.com"}, Text: "hi"})
+	require.ErrorContains(t, err, "does not support STARTTLS")
+}
+
+func TestNewSMTP_RequiresHostAndPort(t *testing.T) {
+	t.Parallel()
+
+	_, err := mailer.NewSMTP(mailer.SMTPConfig{Host: "smtp.example.com"})
+	require.ErrorIs(t, err, mailer.ErrSMTPConfig)
+}
+
+type fakeSES struct {
+	input *sesv2.SendEmailInput
+	err   error
+}
+
+func (f *fakeSES) SendEmail(
+	_ context.Context,
+	input *sesv2.SendEmailInput,
+	_ ...func(*sesv2.Options),
+) (*sesv2.SendEmailOutput, error) {
+	f.input = input
+
+	return &sesv2.SendEmailOutput{}, f.err
+}
+
+func TestSESSender_Send(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeSES{}
+
+	sender, err := mailer.NewSES(client, mailer.WithSESConfigurationSet("transactional"))
+	require.NoError(t, err)
+
+	err = sender.Send(t.Context(), &mailer.Message{
+		From:    "a@example.com",
+		To:      []string{"b@example.com"},
+		Bcc:     []string{"c@example.com"},
+		Subject: "Hello",
+		HTML:    "<p>Hi</p>",
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, "transactional", *client.input.ConfigurationSetName)
+	require.Equal(t, []string{"b@example.com"}, client.input.Destination.ToAddresses)
+	require.Equal(t, []string{"c@example.com"}, client.input.Destination.BccAddresses)
+	require.Contains(t, string(client.input.Content.Raw.Data), "Subject: Hello\r\n")
+
+	client.err = errProviderDown
+	require.ErrorIs(t, sender.Send(t.Context(), &mailer.Message{From: "a@example.com", Text: "hi"}), errProviderDown)
+
+	_, err = mailer.NewSES(nil)
+	require.ErrorIs(t, err, mailer.ErrNilSESClient)
+}
+
+func TestSendGridSender_Send(t *testing.T) {
+	t.Parallel()
+
+	var (
+		authorization string
+		request       map[string]any
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v3/mail/send" {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		authorization = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&request)
+
+		if strings.Contains(r.Header.Get("Content-Type"), "json") && request["subject"] == "fail" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errors":[{"message":"bad request"}]}`))
+
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(server.Close)
+
+	sender, err := mailer.NewSendGrid("sg-key", mailer.WithSendGridBaseURL(server.URL))
+	require.NoError(t, err)
+
+	err = sender.Send(t.Context(), &mailer.Message{
+		From:        "Orders <orders@example.com>",
+		To:          []string{"jane@example.com"},
+		Subject:     "Hello",
+		Text:        "Hi",
+		HTML:        "<p>Hi</p>",
+		Attachments: []mailer.Attachment{{Filename: "a.txt", ContentType: "text/plain", Data: []byte("abc")}},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, "Bearer sg-key", authorization)
+	require.Equal(t, map[string]any{"email": "orders@example.com", "name": "Orders"}, request["from"])
+	require.Equal(t, []any{
+		map[string]any{"type": "text/plain", "value": "Hi"},
+		map[string]any{"type": "text/html", "value": "<p>Hi</p>"},
+	}, request["content"])
+	require.Equal(t, []any{map[string]any{
+		"content": "YWJj", "type": "text/plain", "filename": "a.txt", "disposition": "attachment",
+	}}, request["attachments"])
+
+	err = sender.Send(t.Context(), &mailer.Message{From: "a@example.com", To: []string{"b@example.com"}, Subject: "fail", Text: "x"})
+	require.ErrorContains(t, err, "bad request")
+
+	_, err = mailer.NewSendGrid("")
+	require.ErrorIs(t, err, mailer.ErrEmptyAPIKey)
+}
@@ -0,0 +1,151 @@
+package mailer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/andyle182810/gframework/taskqueue"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+const defaultQueueMaxAttempts = 5
+
+var ErrNilMailer = errors.New("mailer: mailer is nil")
+
+type QueueOption func(*queueOptions)
+
+type queueOptions struct {
+	maxAttempts  int
+	queueOptions []taskqueue.Option
+}
+
+// WithMaxAttempts sets how many times a message is sent before it is dropped. It defaults to 5.
+func WithMaxAttempts(attempts int) QueueOption {
+	return func(o *queueOptions) {
+		if attempts > 0 {
+			o.maxAttempts = attempts
+		}
+	}
+}
+
+// WithQueueOptions configures the underlying task queue, e.g. its worker count and exec timeout.
+func WithQueueOptions(opts ...taskqueue.Option) QueueOption {
+	return func(o *queueOptions) {
+		o.queueOptions = append(o.queueOptions, opts...)
+	}
+}
+
+type queuedMessage struct {
+	Attempt int      `json:"attempt"`
+	Message *Message `json:"message"`
+}
+
+// Queue sends messages in the background from a taskqueue.Queue. A message whose send fails is
+// enqueued again until it has been attempted the configured number of times. It is a runner service,
+// started and stopped with the queue.
+type Queue struct {
+	*taskqueue.Queue
+
+	mailer      *Mailer
+	maxAttempts int
+}
+
+var _ taskqueue.Executor = (*Queue)(nil)
+
+func NewQueue(client redis.UniversalClient, queueKey string, mailer *Mailer, opts ...QueueOption) (*Queue, error) {
+	if mailer == nil {
+		return nil, ErrNilMailer
+	}
+
+	options := &queueOptions{maxAttempts: defaultQueueMaxAttempts, queueOptions: nil}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	queue := &Queue{Queue: nil, mailer: mailer, maxAttempts: options.maxAttempts}
+
+	tasks, err := taskqueue.New(client, queueKey, queue, options.queueOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	queue.Queue = tasks
+
+	return queue, nil
+}
+
+func (q *Queue) Name() string {
+	return "mailer-queue"
+}
+
+// Enqueue validates msg, filling in the default from address, and enqueues it for sending.
+func (q *Queue) Enqueue(ctx context.Context, msg *Message) error {
+	if msg.From == "" {
+		msg.From = q.mailer.defaultFrom
+	}
+
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+
+	return q.push(ctx, queuedMessage{Attempt: 1, Message: msg})
+}
+
+// EnqueueTemplate renders the named template into msg and enqueues it.
+func (q *Queue) EnqueueTemplate(ctx context.Context, msg *Message, name string, data any) error {
+	if err := q.mailer.Render(msg, name, data); err != nil {
+		return err
+	}
+
+	return q.Enqueue(ctx, msg)
+}
+
+func (q *Queue) Execute(ctx context.Context, taskID string, payload taskqueue.Payload) error {
+	var queued queuedMessage
+	if err := json.Unmarshal(payload, &queued); err != nil || queued.Message == nil {
+		log.Error().Str("source", "gframework").Err(err).Str("task_id", taskID).Msg("Dropping malformed queued email")
+
+		return nil
+	}
+
+	err := q.mailer.Send(ctx, queued.Message)
+	if err == nil || !errors.Is(err, ErrSendFailed) {
+		// Messages that fail validation or the rate limit wait are not worth retrying.
+		return err
+	}
+
+	if queued.Attempt >= q.maxAttempts {
+		log.Error().
+			Str("source", "gframework").
+			Err(err).
+			Str("task_id", taskID).
+			Int("attempts", queued.Attempt).
+			Strs("to", queued.Message.To).
+			Msg("Dropping email after max attempts")
+
+		return err
+	}
+
+	queued.Attempt++
+
+	// The retry gets a new task ID because the queue deletes the payload of this one once it returns.
+	if pushErr := q.push(context.WithoutCancel(ctx), queued); pushErr != nil {
+		return fmt.Errorf("%w (requeue failed: %w)", err, pushErr)
+	}
+
+	return err
+}
+
+func (q *Queue) push(ctx context.Context, queued queuedMessage) error {
+	payload, err := json.Marshal(queued)
+	if err != nil {
+		return fmt.Errorf("mailer: failed to encode message: %w", err)
+	}
+
+	return q.Queue.Push(ctx, taskqueue.Task{ID: uuid.NewString(), Payload: payload})
+}
@@ -0,0 +1,95 @@
+//nolint:exhaustruct
+package mailer_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/mailer"
+	"github.com/andyle182810/gframework/taskqueue"
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// flakySender fails the first failures sends.
+type flakySender struct {
+	recordingSender
+
+	failures int32
+	calls    atomic.Int32
+}
+
+func (s *flakySender) Send(ctx context.Context, msg *mailer.Message) error {
+	if s.calls.Add(1) <= s.failures {
+		return errProviderDown
+	}
+
+	return s.recordingSender.Send(ctx, msg)
+}
+
+func startQueue(t *testing.T, sender mailer.Sender, opts ...mailer.QueueOption) *mailer.Queue {
+	t.Helper()
+
+	valkeyClient := testutil.SetupValkey(t)
+
+	m, err := mailer.New(sender, mailer.WithDefaultFrom("noreply@example.com"))
+	require.NoError(t, err)
+
+	opts = append(opts, mailer.WithQueueOptions(taskqueue.WithPollInterval(10*time.Millisecond)))
+
+	queue, err := mailer.NewQueue(valkeyClient.Client, "mail:"+t.Name(), m, opts...)
+	require.NoError(t, err)
+
+	go func() { _ = queue.Start(t.Context()) }()
+
+	t.Cleanup(func() { _ = queue.Stop() })
+
+	return queue
+}
+
+func TestQueue_RetriesFailedSends(t *testing.T) {
+	t.Parallel()
+
+	sender := &flakySender{failures: 2}
+	queue := startQueue(t, sender)
+
+	require.Equal(t, "mailer-queue", queue.Name())
+	require.NoError(t, queue.Enqueue(t.Context(), &mailer.Message{To: []string{"b@example.com"}, Text: "hi"}))
+
+	require.Eventually(t, func() bool { return sender.count() == 1 }, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, int32(3), sender.calls.Load())
+	require.Equal(t, "noreply@example.com", sender.sent[0].From)
+}
+
+func TestQueue_DropsAfterMaxAttempts(t *testing.T) {
+	t.Parallel()
+
+	sender := &flakySender{failures: 100}
+	queue := startQueue(t, sender, mailer.WithMaxAttempts(2))
+
+	require.NoError(t, queue.Enqueue(t.Context(), &mailer.Message{To: []string{"b@example.com"}, Text: "hi"}))
+
+	require.Eventually(t, func() bool { return sender.calls.Load() == 2 }, 5*time.Second, 10*time.Millisecond)
+
+	require.Eventually(t, func() bool {
+		depth, err := queue.Depth(t.Context())
+
+		return err == nil && depth == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	time.Sleep(100 * time.Millisecond)
+	require.Equal(t, int32(2), sender.calls.Load())
+}
+
+func TestQueue_ValidatesBeforeEnqueueing(t *testing.T) {
+	t.Parallel()
+
+	queue := startQueue(t, &recordingSender{})
+
+	require.ErrorIs(t, queue.Enqueue(t.Context(), &mailer.Message{Text: "hi"}), mailer.ErrNoRecipients)
+
+	_, err := mailer.NewQueue(nil, "mail", nil)
+	require.ErrorIs(t, err, mailer.ErrNilMailer)
+}
@@ -0,0 +1,176 @@
+package mailer
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+
+	"github.com/andyle182810/gframework/httpclient"
+)
+
+const defaultSendGridBaseURL = "https://api.sendgrid.com"
+
+var ErrEmptyAPIKey = errors.New("mailer: api key is empty")
+
+type SendGridOption func(*sendGridOptions)
+
+type sendGridOptions struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// WithSendGridBaseURL overrides the API URL, e.g. for the EU region or a test server.
+func WithSendGridBaseURL(baseURL string) SendGridOption {
+	return func(o *sendGridOptions) {
+		o.baseURL = baseURL
+	}
+}
+
+func WithSendGridHTTPClient(client *http.Client) SendGridOption {
+	return func(o *sendGridOptions) {
+		o.httpClient = client
+	}
+}
+
+// SendGridSender delivers messages through the SendGrid v3 mail send API.
+type SendGridSender struct {
+	client *httpclient.Client
+}
+
+var _ Sender = (*SendGridSender)(nil)
+
+func NewSendGrid(apiKey string, opts ...SendGridOption) (*SendGridSender, error) {
+	if apiKey == "" {
+		return nil, ErrEmptyAPIKey
+	}
+
+	options := &sendGridOptions{baseURL: defaultSendGridBaseURL, httpClient: nil}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	clientOpts := []httpclient.Option{
+		httpclient.WithDefaultHeaders(map[string]string{"Authorization": "Bearer " + apiKey}),
+	}
+
+	if options.httpClient != nil {
+		clientOpts = append(clientOpts, httpclient.WithHTTPClient(options.httpClient))
+	}
+
+	return &SendGridSender{client: httpclient.New(options.baseURL, clientOpts...)}, nil
+}
+
+type sendGridAddress struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+type sendGridPersonalization struct {
+	To  []sendGridAddress `json:"to,omitempty"`
+	Cc  []sendGridAddress `json:"cc,omitempty"`
+	Bcc []sendGridAddress `json:"bcc,omitempty"`
+}
+
+type sendGridContent struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type sendGridAttachment struct {
+	Content     string `json:"content"`
+	Type        string `json:"type,omitempty"`
+	Filename    string `json:"filename"`
+	Disposition string `json:"disposition"`
+	ContentID   string `json:"content_id,omitempty"`
+}
+
+type sendGridRequest struct {
+	Personalizations []sendGridPersonalization `json:"personalizations"`
+	From             sendGridAddress           `json:"from"`
+	ReplyTo          *sendGridAddress          `json:"reply_to,omitempty"`
+	Subject          string                    `json:"subject"`
+	Content          []sendGridContent         `json:"content"`
+	Attachments      []sendGridAttachment      `json:"attachments,omitempty"`
+	Headers          map[string]string         `json:"headers,omitempty"`
+}
+
+func (s *SendGridSender) Send(ctx context.Context, msg *Message) error {
+	if err := s.client.Post(ctx, "/v3/mail/send", newSendGridRequest(msg), nil); err != nil {
+		return fmt.Errorf("sendgrid: %w", err)
+	}
+
+	return nil
+}
+
+func newSendGridRequest(msg *Message) sendGridRequest {
+	request := sendGridRequest{
+		Personalizations: []sendGridPersonalization{{
+			To:  sendGridAddresses(msg.To),
+			Cc:  sendGridAddresses(msg.Cc),
+			Bcc: sendGridAddresses(msg.Bcc),
+		}},
+		From:        sendGridAddressOf(msg.From),
+		ReplyTo:     nil,
+		Subject:     msg.Subject,
+		Content:     nil,
+		Attachments: nil,
+		Headers:     msg.Headers,
+	}
+
+	if msg.ReplyTo != "" {
+		replyTo := sendGridAddressOf(msg.ReplyTo)
+		request.ReplyTo = &replyTo
+	}
+
+	// SendGrid requires text/plain to come before text/html.
+	if msg.Text != "" {
+		request.Content = append(request.Content, sendGridContent{Type: "text/plain", Value: msg.Text})
+	}
+
+	if msg.HTML != "" {
+		request.Content = append(request.Content, sendGridContent{Type: "text/html", Value: msg.HTML})
+	}
+
+	for _, attachment := range msg.Attachments {
+		disposition := "attachment"
+		if attachment.ContentID != "" {
+			disposition = "inline"
+		}
+
+		request.Attachments = append(request.Attachments, sendGridAttachment{
+			Content:     base64.StdEncoding.EncodeToString(attachment.Data),
+			Type:        attachment.ContentType,
+			Filename:    attachment.Filename,
+			Disposition: disposition,
+			ContentID:   attachment.ContentID,
+		})
+	}
+
+	return request
+}
+
+func sendGridAddresses(addresses []string) []sendGridAddress {
+	if len(addresses) == 0 {
+		return nil
+	}
+
+	converted := make([]sendGridAddress, 0, len(addresses))
+	for _, address := range addresses {
+		converted = append(converted, sendGridAddressOf(address))
+	}
+
+	return converted
+}
+
+func sendGridAddressOf(address string) sendGridAddress {
+	parsed, err := mail.ParseAddress(address)
+	if err != nil {
+		return sendGridAddress{Email: address, Name: ""}
+	}
+
+	return sendGridAddress{Email: parsed.Address, Name: parsed.Name}
+}
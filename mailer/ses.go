@@ -0,0 +1,78 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2/types"
+)
+
+var ErrNilSESClient = errors.New("mailer: ses client is nil")
+
+// SESAPI is the part of *sesv2.Client the sender uses.
+type SESAPI interface {
+	SendEmail(ctx context.Context, params *sesv2.SendEmailInput, optFns ...func(*sesv2.Options)) (*sesv2.SendEmailOutput, error)
+}
+
+type SESOption func(*SESSender)
+
+// WithSESConfigurationSet sends through a configuration set, e.g. to publish delivery and bounce events.
+func WithSESConfigurationSet(name string) SESOption {
+	return func(s *SESSender) {
+		s.configurationSet = name
+	}
+}
+
+// SESSender delivers messages through the Amazon SES v2 API as raw MIME, which carries attachments and
+// inline images.
+type SESSender struct {
+	client           SESAPI
+	configurationSet string
+}
+
+var _ Sender = (*SESSender)(nil)
+
+func NewSES(client SESAPI, opts ...SESOption) (*SESSender, error) {
+	if client == nil {
+		return nil, ErrNilSESClient
+	}
+
+	sender := &SESSender{client: client, configurationSet: ""}
+
+	for _, opt := range opts {
+		opt(sender)
+	}
+
+	return sender, nil
+}
+
+func (s *SESSender) Send(ctx context.Context, msg *Message) error {
+	raw, err := msg.Raw()
+	if err != nil {
+		return err
+	}
+
+	//nolint:exhaustruct
+	input := &sesv2.SendEmailInput{
+		FromEmailAddress: aws.String(msg.From),
+		Destination: &types.Destination{
+			ToAddresses:  msg.To,
+			CcAddresses:  msg.Cc,
+			BccAddresses: msg.Bcc,
+		},
+		Content: &types.EmailContent{Raw: &types.RawMessage{Data: raw}},
+	}
+
+	if s.configurationSet != "" {
+		input.ConfigurationSetName = aws.String(s.configurationSet)
+	}
+
+	if _, err := s.client.SendEmail(ctx, input); err != nil {
+		return fmt.Errorf("ses: %w", err)
+	}
+
+	return nil
+}
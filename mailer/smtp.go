@@ -0,0 +1,194 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+const defaultSMTPTimeout = 30 * time.Second
+
+var ErrSMTPConfig = errors.New("mailer: invalid smtp configuration")
+
+// SMTPSecurity selects how the connection to the SMTP server is secured.
+type SMTPSecurity int
+
+const (
+	// SMTPStartTLS upgrades the connection with STARTTLS and fails when the server does not offer it,
+	// as on port 587.
+	SMTPStartTLS SMTPSecurity = iota
+	// SMTPImplicitTLS connects with TLS from the start, as on port 465.
+	SMTPImplicitTLS
+	// SMTPInsecure sends in plain text, for local relays and test servers such as Mailpit.
+	SMTPInsecure
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	Security SMTPSecurity
+	// LocalName is the host name sent with EHLO. It defaults to "localhost".
+	LocalName string
+	// Timeout bounds a whole delivery when the context has no deadline. It defaults to 30 seconds.
+	Timeout   time.Duration
+	TLSConfig *tls.Config
+}
+
+// SMTPSender delivers messages to an SMTP server, opening a connection per message.
+type SMTPSender struct {
+	cfg SMTPConfig
+}
+
+var _ Sender = (*SMTPSender)(nil)
+
+func NewSMTP(cfg SMTPConfig) (*SMTPSender, error) {
+	if cfg.Host == "" || cfg.Port <= 0 {
+		return nil, fmt.Errorf("%w: host and port are required", ErrSMTPConfig)
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultSMTPTimeout
+	}
+
+	if cfg.LocalName == "" {
+		cfg.LocalName = "localhost"
+	}
+
+	return &SMTPSender{cfg: cfg}, nil
+}
+
+func (s *SMTPSender) Send(ctx context.Context, msg *Message) error {
+	raw, err := msg.Raw()
+	if err != nil {
+		return err
+	}
+
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, s.cfg.Timeout)
+		defer cancel()
+	}
+
+	client, err := s.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close() //nolint:errcheck
+
+	if err := s.deliver(client, msg, raw); err != nil {
+		return err
+	}
+
+	if err := client.Quit(); err != nil {
+		return fmt.Errorf("smtp: quit: %w", err)
+	}
+
+	return nil
+}
+
+func (s *SMTPSender) dial(ctx context.Context) (*smtp.Client, error) {
+	address := net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port))
+
+	var (
+		conn net.Conn
+		err  error
+	)
+
+	if s.cfg.Security == SMTPImplicitTLS {
+		dialer := &tls.Dialer{NetDialer: &net.Dialer{}, Config: s.tlsConfig()} //nolint:exhaustruct
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", address) //nolint:exhaustruct
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("smtp: failed to connect to %s: %w", address, err)
+	}
+
+	// The deadline of ctx bounds the whole conversation, which net/smtp does not take a context for.
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		_ = conn.Close()
+
+		return nil, fmt.Errorf("smtp: failed to start session with %s: %w", address, err)
+	}
+
+	if err := client.Hello(s.cfg.LocalName); err != nil {
+		_ = client.Close()
+
+		return nil, fmt.Errorf("smtp: hello: %w", err)
+	}
+
+	if s.cfg.Security == SMTPStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			_ = client.Close()
+
+			return nil, fmt.Errorf("smtp: %s does not support STARTTLS", address)
+		}
+
+		if err := client.StartTLS(s.tlsConfig()); err != nil {
+			_ = client.Close()
+
+			return nil, fmt.Errorf("smtp: starttls: %w", err)
+		}
+	}
+
+	if s.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)); err != nil {
+			_ = client.Close()
+
+			return nil, fmt.Errorf("smtp: auth: %w", err)
+		}
+	}
+
+	return client, nil
+}
+
+func (s *SMTPSender) deliver(client *smtp.Client, msg *Message, raw []byte) error {
+	if err := client.Mail(addressOnly(msg.From)); err != nil {
+		return fmt.Errorf("smtp: mail from: %w", err)
+	}
+
+	for _, recipient := range msg.Recipients() {
+		if err := client.Rcpt(addressOnly(recipient)); err != nil {
+			return fmt.Errorf("smtp: rcpt to %s: %w", recipient, err)
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp: data: %w", err)
+	}
+
+	if _, err := writer.Write(raw); err != nil {
+		_ = writer.Close()
+
+		return fmt.Errorf("smtp: write message: %w", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("smtp: data: %w", err)
+	}
+
+	return nil
+}
+
+func (s *SMTPSender) tlsConfig() *tls.Config {
+	if s.cfg.TLSConfig != nil {
+		return s.cfg.TLSConfig
+	}
+
+	return &tls.Config{ServerName: s.cfg.Host, MinVersion: tls.VersionTLS12} //nolint:exhaustruct
+}
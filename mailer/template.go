@@ -0,0 +1,149 @@
+package mailer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"strings"
+	texttemplate "text/template"
+)
+
+const subjectTemplate = "subject"
+
+var ErrTemplateNotFound = errors.New("mailer: template not found")
+
+// Templates holds the email templates of a directory. A template named "welcome" is read from
+// "welcome.html", rendered with html/template, and "welcome.txt", rendered with text/template; either
+// may be missing. Either file may define a "subject" template for the subject line. Files whose name
+// starts with "_" are partials shared by all templates of the same kind, e.g. "_layout.html".
+type Templates struct {
+	html map[string]*htmltemplate.Template
+	text map[string]*texttemplate.Template
+}
+
+// ParseTemplates parses the templates at the root of fsys, e.g. an embed.FS or os.DirFS.
+func ParseTemplates(fsys fs.FS) (*Templates, error) {
+	htmlPartials, err := fs.Glob(fsys, "_*.html")
+	if err != nil {
+		return nil, fmt.Errorf("mailer: failed to list templates: %w", err)
+	}
+
+	textPartials, err := fs.Glob(fsys, "_*.txt")
+	if err != nil {
+		return nil, fmt.Errorf("mailer: failed to list templates: %w", err)
+	}
+
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("mailer: failed to list templates: %w", err)
+	}
+
+	templates := &Templates{
+		html: make(map[string]*htmltemplate.Template),
+		text: make(map[string]*texttemplate.Template),
+	}
+
+	for _, entry := range entries {
+		file := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(file, "_") {
+			continue
+		}
+
+		name := strings.TrimSuffix(file, path.Ext(file))
+
+		switch path.Ext(file) {
+		case ".html":
+			tmpl, err := htmltemplate.New(file).ParseFS(fsys, append([]string{file}, htmlPartials...)...)
+			if err != nil {
+				return nil, fmt.Errorf("mailer: failed to parse template %s: %w", file, err)
+			}
+
+			templates.html[name] = tmpl
+		case ".txt":
+			tmpl, err := texttemplate.New(file).ParseFS(fsys, append([]string{file}, textPartials...)...)
+			if err != nil {
+				return nil, fmt.Errorf("mailer: failed to parse template %s: %w", file, err)
+			}
+
+			templates.text[name] = tmpl
+		}
+	}
+
+	return templates, nil
+}
+
+// Render executes the named template with data and sets the HTML and text bodies of msg, and its
+// subject when the template defines one. The subject of the text template takes precedence.
+func (t *Templates) Render(msg *Message, name string, data any) error {
+	htmlTmpl, hasHTML := t.html[name]
+	textTmpl, hasText := t.text[name]
+
+	if !hasHTML && !hasText {
+		return fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+
+	var subject string
+
+	if hasHTML {
+		body, err := executeHTML(htmlTmpl, htmlTmpl.Name(), data)
+		if err != nil {
+			return err
+		}
+
+		msg.HTML = body
+
+		if htmlTmpl.Lookup(subjectTemplate) != nil {
+			if subject, err = executeHTML(htmlTmpl, subjectTemplate, data); err != nil {
+				return err
+			}
+
+			// The subject is not HTML, so undo the escaping of html/template.
+			subject = html.UnescapeString(subject)
+		}
+	}
+
+	if hasText {
+		body, err := executeText(textTmpl, textTmpl.Name(), data)
+		if err != nil {
+			return err
+		}
+
+		msg.Text = body
+
+		if textTmpl.Lookup(subjectTemplate) != nil {
+			if subject, err = executeText(textTmpl, subjectTemplate, data); err != nil {
+				return err
+			}
+		}
+	}
+
+	if subject = strings.TrimSpace(subject); subject != "" {
+		msg.Subject = subject
+	}
+
+	return nil
+}
+
+func executeHTML(tmpl *htmltemplate.Template, name string, data any) (string, error) {
+	var buf bytes.Buffer
+
+	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", fmt.Errorf("mailer: failed to render template %s: %w", name, err)
+	}
+
+	return buf.String(), nil
+}
+
+func executeText(tmpl *texttemplate.Template, name string, data any) (string, error) {
+	var buf bytes.Buffer
+
+	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", fmt.Errorf("mailer: failed to render template %s: %w", name, err)
+	}
+
+	return buf.String(), nil
+}
@@ -0,0 +1,107 @@
+package metricserver
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+)
+
+const bearerPrefix = "Bearer "
+
+var (
+	ErrTLSCertRequired     = errors.New("metricserver: TLSCertFile and TLSKeyFile are both required for TLS")
+	ErrClientCAParseFailed = errors.New("metricserver: failed to parse client CA certificate")
+)
+
+// metricsAuth protects /metrics and /debug/profile with the configured static token, basic auth credentials or client
+// certificates. Any configured method is sufficient, and the endpoint stays open when none is.
+func metricsAuth(cfg *Config) echo.MiddlewareFunc {
+	requireCert := cfg.TLSClientCAFile != ""
+	enabled := authConfigured(cfg)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx *echo.Context) error {
+			if !enabled {
+				return next(ctx)
+			}
+
+			request := ctx.Request()
+
+			if requireCert && request.TLS != nil && len(request.TLS.VerifiedChains) > 0 {
+				return next(ctx)
+			}
+
+			if cfg.MetricsToken != "" {
+				token, ok := strings.CutPrefix(request.Header.Get(echo.HeaderAuthorization), bearerPrefix)
+				if ok && secureEqual(token, cfg.MetricsToken) {
+					return next(ctx)
+				}
+			}
+
+			if cfg.MetricsUsername != "" {
+				username, password, ok := request.BasicAuth()
+				if ok && secureEqual(username, cfg.MetricsUsername) && secureEqual(password, cfg.MetricsPassword) {
+					return next(ctx)
+				}
+
+				ctx.Response().Header().Set(echo.HeaderWWWAuthenticate, `Basic realm="metrics"`)
+			}
+
+			return echo.NewHTTPError(http.StatusUnauthorized, "unauthorized")
+		}
+	}
+}
+
+func authConfigured(cfg *Config) bool {
+	return cfg.MetricsToken != "" || cfg.MetricsUsername != "" || cfg.TLSClientCAFile != ""
+}
+
+func secureEqual(got, want string) bool {
+	return subtle.ConstantTimeCompare([]byte(got), []byte(want)) == 1
+}
+
+func (s *Server) tlsEnabled() bool {
+	return s.tlsCertFile != "" || s.tlsKeyFile != "" || s.tlsClientCAFile != ""
+}
+
+// buildTLSConfig verifies client certificates when presented but leaves requiring them to /metrics,
+// so that probes can still reach /status, /healthz and /readyz without one.
+func (s *Server) buildTLSConfig() (*tls.Config, error) {
+	if s.tlsCertFile == "" || s.tlsKeyFile == "" {
+		return nil, ErrTLSCertRequired
+	}
+
+	cert, err := tls.LoadX509KeyPair(s.tlsCertFile, s.tlsKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server certificate: %w", err)
+	}
+
+	tlsConfig := &tls.Config{ //nolint:exhaustruct
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if s.tlsClientCAFile != "" {
+		caCert, err := os.ReadFile(s.tlsClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA certificate: %w", err)
+		}
+
+		caCertPool := x509.NewCertPool()
+		if !caCertPool.AppendCertsFromPEM(caCert) {
+			return nil, ErrClientCAParseFailed
+		}
+
+		tlsConfig.ClientCAs = caCertPool
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,106 @@
+package metricserver_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/metricserver"
+	"github.com/stretchr/testify/require"
+)
+
+func getStatus(t *testing.T, url string, prepare func(req *http.Request)) int {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 2*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	require.NoError(t, err)
+
+	prepare(req)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	_ = resp.Body.Close()
+
+	return resp.StatusCode
+}
+
+func TestMetricsAuth(t *testing.T) {
+	t.Parallel()
+
+	server := metricserver.New(&metricserver.Config{
+		Host:            "127.0.0.1",
+		Port:            9094,
+		ReadTimeout:     2 * time.Second,
+		WriteTimeout:    2 * time.Second,
+		GracePeriod:     2 * time.Second,
+		Registry:        nil,
+		OTLP:            nil,
+		CheckTimeout:    0,
+		MetricsToken:    "scrape-token",
+		MetricsUsername: "prometheus",
+		MetricsPassword: "secret",
+		TLSCertFile:     "",
+		TLSKeyFile:      "",
+		TLSClientCAFile: "",
+		Profiling:       nil,
+	})
+
+	go func() {
+		_ = server.Start(t.Context())
+	}()
+
+	t.Cleanup(func() {
+		_ = server.Stop()
+	})
+
+	time.Sleep(500 * time.Millisecond)
+
+	const metricsURL = "http://127.0.0.1:9094/metrics"
+
+	require.Equal(t, http.StatusUnauthorized, getStatus(t, metricsURL, func(*http.Request) {}))
+	require.Equal(t, http.StatusUnauthorized, getStatus(t, metricsURL, func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer wrong-token")
+	}))
+	require.Equal(t, http.StatusOK, getStatus(t, metricsURL, func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer scrape-token")
+	}))
+	require.Equal(t, http.StatusOK, getStatus(t, metricsURL, func(req *http.Request) {
+		req.SetBasicAuth("prometheus", "secret")
+	}))
+	require.Equal(t, http.StatusUnauthorized, getStatus(t, metricsURL, func(req *http.Request) {
+		req.SetBasicAuth("prometheus", "wrong")
+	}))
+
+	// Probes must keep working without credentials.
+	require.Equal(t, http.StatusOK, getStatus(t, "http://127.0.0.1:9094/status", func(*http.Request) {}))
+	require.Equal(t, http.StatusOK, getStatus(t, "http://127.0.0.1:9094/readyz", func(*http.Request) {}))
+}
+
+func TestClientCAWithoutServerCertificate(t *testing.T) {
+	t.Parallel()
+
+	server := metricserver.New(&metricserver.Config{
+		Host:            "127.0.0.1",
+		Port:            0,
+		ReadTimeout:     time.Second,
+		WriteTimeout:    time.Second,
+		GracePeriod:     time.Second,
+		Registry:        nil,
+		OTLP:            nil,
+		CheckTimeout:    0,
+		MetricsToken:    "",
+		MetricsUsername: "",
+		MetricsPassword: "",
+		TLSCertFile:     "",
+		TLSKeyFile:      "",
+		TLSClientCAFile: "/etc/ssl/metrics-ca.pem",
+		Profiling:       nil,
+	})
+
+	require.ErrorIs(t, server.Start(t.Context()), metricserver.ErrTLSCertRequired)
+}
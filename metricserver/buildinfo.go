@@ -0,0 +1,101 @@
+package metricserver
+
+import (
+	"errors"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/rs/zerolog/log"
+)
+
+// Version and Commit override the values read from the module build info, e.g.
+//
+//	go build -ldflags "-X github.com/andyle182810/gframework/metricserver.Version=1.4.0 \
+//	    -X github.com/andyle182810/gframework/metricserver.Commit=$(git rev-parse HEAD)"
+//
+//nolint:gochecknoglobals
+var (
+	Version string
+	Commit  string
+)
+
+type BuildInfo struct {
+	Module    string
+	Version   string
+	Commit    string
+	GoVersion string
+}
+
+// ReadBuildInfo returns the build info of the running binary, preferring Version and Commit when set.
+func ReadBuildInfo() BuildInfo {
+	info := BuildInfo{
+		Module:    "",
+		Version:   "",
+		Commit:    "",
+		GoVersion: runtime.Version(),
+	}
+
+	if buildInfo, ok := debug.ReadBuildInfo(); ok {
+		info.Module = buildInfo.Main.Path
+
+		if version := buildInfo.Main.Version; version != "(devel)" {
+			info.Version = version
+		}
+
+		for _, setting := range buildInfo.Settings {
+			if setting.Key == "vcs.revision" {
+				info.Commit = setting.Value
+			}
+		}
+	}
+
+	if Version != "" {
+		info.Version = Version
+	}
+
+	if Commit != "" {
+		info.Commit = Commit
+	}
+
+	return info
+}
+
+func newBuildInfoCollector(info BuildInfo) prometheus.Collector {
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{ //nolint:exhaustruct
+		Namespace: Namespace,
+		Name:      "build_info",
+		Help:      "Build information of the service. Always 1.",
+		ConstLabels: prometheus.Labels{
+			"module":     info.Module,
+			"version":    info.Version,
+			"commit":     info.Commit,
+			"go_version": info.GoVersion,
+		},
+	})
+	gauge.Set(1)
+
+	return gauge
+}
+
+// registerDefaultMetrics registers build_info and, for registries other than the global one which
+// already has them, the Go runtime and process collectors.
+func registerDefaultMetrics(reg prometheus.Registerer, withRuntime bool) {
+	defaults := []prometheus.Collector{newBuildInfoCollector(ReadBuildInfo())}
+
+	if withRuntime {
+		defaults = append(defaults,
+			collectors.NewGoCollector(),
+			collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}), //nolint:exhaustruct
+		)
+	}
+
+	for _, collector := range defaults {
+		var alreadyRegistered prometheus.AlreadyRegisteredError
+
+		if err := reg.Register(collector); err != nil && !errors.As(err, &alreadyRegistered) {
+			log.Warn().Str("source", "gframework").Err(err).Msg("Failed to register default metrics")
+		}
+	}
+}
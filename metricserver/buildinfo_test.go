@@ -0,0 +1,79 @@
+//nolint:paralleltest
+package metricserver_test
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/andyle182810/gframework/metricserver"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+// These tests set the package level Version and Commit and therefore do not run in parallel.
+
+func TestReadBuildInfo(t *testing.T) {
+	info := metricserver.ReadBuildInfo()
+	require.Equal(t, runtime.Version(), info.GoVersion)
+
+	metricserver.Version, metricserver.Commit = "1.4.0", "abc123"
+
+	t.Cleanup(func() {
+		metricserver.Version, metricserver.Commit = "", ""
+	})
+
+	info = metricserver.ReadBuildInfo()
+	require.Equal(t, "1.4.0", info.Version)
+	require.Equal(t, "abc123", info.Commit)
+}
+
+func TestServer_RegistersDefaultMetrics(t *testing.T) {
+	metricserver.Version = "2.0.0"
+
+	t.Cleanup(func() {
+		metricserver.Version = ""
+	})
+
+	reg := prometheus.NewRegistry()
+
+	metricserver.New(&metricserver.Config{
+		Host:            "127.0.0.1",
+		Port:            0,
+		ReadTimeout:     0,
+		WriteTimeout:    0,
+		GracePeriod:     0,
+		Registry:        reg,
+		OTLP:            nil,
+		CheckTimeout:    0,
+		MetricsToken:    "",
+		MetricsUsername: "",
+		MetricsPassword: "",
+		TLSCertFile:     "",
+		TLSKeyFile:      "",
+		TLSClientCAFile: "",
+		Profiling:       nil,
+	})
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+
+	labels := map[string]string{}
+	names := map[string]bool{}
+
+	for _, family := range families {
+		names[family.GetName()] = true
+
+		if family.GetName() == "gframework_build_info" {
+			require.InDelta(t, 1, family.GetMetric()[0].GetGauge().GetValue(), 0)
+
+			for _, label := range family.GetMetric()[0].GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+		}
+	}
+
+	require.True(t, names["go_goroutines"])
+	require.True(t, names["process_start_time_seconds"])
+	require.Equal(t, "2.0.0", labels["version"])
+	require.Equal(t, runtime.Version(), labels["go_version"])
+}
@@ -0,0 +1,70 @@
+package metricserver
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Namespace prefixes every metric registered by the framework, e.g. gframework_cache_hits_total.
+const Namespace = "gframework"
+
+// LatencyBuckets suits fast IO such as cache lookups and valkey commands: 0.5ms to about 1s.
+func LatencyBuckets() []float64 {
+	return prometheus.ExponentialBuckets(0.0005, 2, 12) //nolint:mnd
+}
+
+// DurationBuckets suits requests and jobs: 5ms to 10s.
+func DurationBuckets() []float64 {
+	return prometheus.DefBuckets
+}
+
+// NewCounterVec returns a counter named Namespace_subsystem_name. Register it with Register.
+func NewCounterVec(subsystem, name, help string, labels ...string) *prometheus.CounterVec {
+	return prometheus.NewCounterVec(prometheus.CounterOpts{ //nolint:exhaustruct
+		Namespace: Namespace,
+		Subsystem: subsystem,
+		Name:      name,
+		Help:      help,
+	}, labels)
+}
+
+// NewGaugeVec returns a gauge named Namespace_subsystem_name. Register it with Register.
+func NewGaugeVec(subsystem, name, help string, labels ...string) *prometheus.GaugeVec {
+	return prometheus.NewGaugeVec(prometheus.GaugeOpts{ //nolint:exhaustruct
+		Namespace: Namespace,
+		Subsystem: subsystem,
+		Name:      name,
+		Help:      help,
+	}, labels)
+}
+
+// NewHistogramVec returns a histogram named Namespace_subsystem_name. Buckets default to
+// DurationBuckets. Register it with Register.
+func NewHistogramVec(subsystem, name, help string, buckets []float64, labels ...string) *prometheus.HistogramVec {
+	if len(buckets) == 0 {
+		buckets = DurationBuckets()
+	}
+
+	return prometheus.NewHistogramVec(prometheus.HistogramOpts{ //nolint:exhaustruct
+		Namespace: Namespace,
+		Subsystem: subsystem,
+		Name:      name,
+		Help:      help,
+		Buckets:   buckets,
+	}, labels)
+}
+
+// Register registers all collectors with reg, or none of them: when one fails, the ones registered
+// before it are unregistered again so the caller can retry or fall back.
+func Register(reg prometheus.Registerer, collectors ...prometheus.Collector) error {
+	for i, collector := range collectors {
+		if err := reg.Register(collector); err != nil {
+			for _, registered := range collectors[:i] {
+				reg.Unregister(registered)
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package metricserver_test
+
+import (
+	"testing"
+
+	"github.com/andyle182810/gframework/metricserver"
+	"github.com/prometheus/client_golang/prometheus"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectors_FollowNamingConvention(t *testing.T) {
+	t.Parallel()
+
+	reg := prometheus.NewRegistry()
+
+	counter := metricserver.NewCounterVec("orders", "created_total", "Number of orders created.", "channel")
+	gauge := metricserver.NewGaugeVec("orders", "pending", "Number of pending orders.")
+	histogram := metricserver.NewHistogramVec("orders", "checkout_duration_seconds", "Duration of checkouts.", nil)
+
+	require.NoError(t, metricserver.Register(reg, counter, gauge, histogram))
+
+	counter.WithLabelValues("web").Inc()
+	gauge.WithLabelValues().Set(3)
+	histogram.WithLabelValues().Observe(0.2)
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(families))
+	for _, family := range families {
+		names = append(names, family.GetName())
+	}
+
+	require.ElementsMatch(t, []string{
+		"gframework_orders_created_total",
+		"gframework_orders_pending",
+		"gframework_orders_checkout_duration_seconds",
+	}, names)
+
+	for _, family := range families {
+		if family.GetName() == "gframework_orders_checkout_duration_seconds" {
+			require.Len(t, family.GetMetric()[0].GetHistogram().GetBucket(), len(metricserver.DurationBuckets()))
+		}
+	}
+}
+
+func TestRegister_RollsBackOnFailure(t *testing.T) {
+	t.Parallel()
+
+	reg := prometheus.NewRegistry()
+
+	existing := metricserver.NewCounterVec("orders", "created_total", "Number of orders created.")
+	require.NoError(t, metricserver.Register(reg, existing))
+
+	fresh := metricserver.NewGaugeVec("orders", "pending", "Number of pending orders.")
+	duplicate := metricserver.NewCounterVec("orders", "created_total", "Number of orders created.")
+
+	err := metricserver.Register(reg, fresh, duplicate)
+
+	var alreadyRegistered prometheus.AlreadyRegisteredError
+	require.ErrorAs(t, err, &alreadyRegistered)
+
+	fresh.WithLabelValues().Set(1)
+	require.Equal(t, 0, promtestutil.CollectAndCount(reg, "gframework_orders_pending"))
+
+	require.NoError(t, metricserver.Register(reg, fresh))
+}
+
+func TestServer_CustomRegistry(t *testing.T) {
+	t.Parallel()
+
+	reg := prometheus.NewRegistry()
+
+	server := metricserver.New(&metricserver.Config{
+		Host:            "127.0.0.1",
+		Port:            0,
+		ReadTimeout:     0,
+		WriteTimeout:    0,
+		GracePeriod:     0,
+		Registry:        reg,
+		OTLP:            nil,
+		CheckTimeout:    0,
+		MetricsToken:    "",
+		MetricsUsername: "",
+		MetricsPassword: "",
+		TLSCertFile:     "",
+		TLSKeyFile:      "",
+		TLSClientCAFile: "",
+		Profiling:       nil,
+	})
+
+	require.Same(t, reg, server.Registerer())
+	require.Same(t, reg, server.Gatherer())
+}
@@ -0,0 +1,192 @@
+package metricserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v5"
+)
+
+const (
+	healthzPath = "/healthz"
+	readyzPath  = "/readyz"
+
+	defaultCheckTimeout     = 3 * time.Second
+	healthStatusOK          = "ok"
+	healthStatusUnavailable = "unavailable"
+)
+
+var (
+	ErrCheckUnsupported = errors.New("metricserver: service does not report its health")
+	ErrUnhealthy        = errors.New("metricserver: service is unhealthy")
+)
+
+// HealthChecker returns an error when the checked dependency is unhealthy.
+type HealthChecker func(ctx context.Context) error
+
+type healthReporter interface {
+	IsHealthy() bool
+}
+
+type contextHealthReporter interface {
+	IsHealthy(ctx context.Context) bool
+}
+
+type errorHealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+type namedCheck struct {
+	name  string
+	check HealthChecker
+}
+
+type CheckResult struct {
+	Name     string `json:"name"`
+	Healthy  bool   `json:"healthy"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+type CheckReport struct {
+	Status string        `json:"status"`
+	Checks []CheckResult `json:"checks"`
+}
+
+// CheckService adapts services that report their health like the runner expects: HealthCheck(ctx)
+// error (valkey), IsHealthy(ctx) bool (postgres) or IsHealthy() bool (subscribers).
+func CheckService(svc any) HealthChecker {
+	return func(ctx context.Context) error {
+		switch checked := svc.(type) {
+		case errorHealthChecker:
+			return checked.HealthCheck(ctx)
+		case contextHealthReporter:
+			if !checked.IsHealthy(ctx) {
+				return ErrUnhealthy
+			}
+		case healthReporter:
+			if !checked.IsHealthy() {
+				return ErrUnhealthy
+			}
+		default:
+			return fmt.Errorf("%w: %T", ErrCheckUnsupported, svc)
+		}
+
+		return nil
+	}
+}
+
+// AddHealthCheck adds a liveness check served on /healthz. Only add checks whose failure means the
+// process must be restarted; dependencies belong in AddReadinessCheck.
+func (s *Server) AddHealthCheck(name string, check HealthChecker) {
+	s.checksMu.Lock()
+	defer s.checksMu.Unlock()
+
+	s.healthChecks = append(s.healthChecks, namedCheck{name: name, check: check})
+}
+
+// AddReadinessCheck adds a check served on /readyz, e.g.
+//
+//	msrv.AddReadinessCheck("postgres", metricserver.CheckService(db))
+func (s *Server) AddReadinessCheck(name string, check HealthChecker) {
+	s.checksMu.Lock()
+	defer s.checksMu.Unlock()
+
+	s.readinessChecks = append(s.readinessChecks, namedCheck{name: name, check: check})
+}
+
+// Health runs the liveness checks concurrently, each bounded by Config.CheckTimeout.
+func (s *Server) Health(ctx context.Context) CheckReport {
+	s.checksMu.RLock()
+	checks := append([]namedCheck(nil), s.healthChecks...)
+	s.checksMu.RUnlock()
+
+	return runChecks(ctx, checks, s.checkTimeout)
+}
+
+// Readiness runs the readiness checks concurrently, each bounded by Config.CheckTimeout.
+func (s *Server) Readiness(ctx context.Context) CheckReport {
+	s.checksMu.RLock()
+	checks := append([]namedCheck(nil), s.readinessChecks...)
+	s.checksMu.RUnlock()
+
+	return runChecks(ctx, checks, s.checkTimeout)
+}
+
+func runChecks(ctx context.Context, checks []namedCheck, timeout time.Duration) CheckReport {
+	results := make([]CheckResult, len(checks))
+
+	var wg sync.WaitGroup
+
+	for i, check := range checks {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			results[i] = runCheck(ctx, check, timeout)
+		}()
+	}
+
+	wg.Wait()
+
+	report := CheckReport{
+		Status: healthStatusOK,
+		Checks: results,
+	}
+
+	for _, result := range results {
+		if !result.Healthy {
+			report.Status = healthStatusUnavailable
+		}
+	}
+
+	return report
+}
+
+func runCheck(ctx context.Context, check namedCheck, timeout time.Duration) (result CheckResult) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	startedAt := time.Now()
+
+	result = CheckResult{
+		Name:     check.name,
+		Healthy:  true,
+		Error:    "",
+		Duration: "",
+	}
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			result.Healthy = false
+			result.Error = fmt.Sprintf("panic: %v", rec)
+		}
+
+		result.Duration = time.Since(startedAt).String()
+	}()
+
+	if err := check.check(ctx); err != nil {
+		result.Healthy = false
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+func checkHandler(run func(ctx context.Context) CheckReport) echo.HandlerFunc {
+	return func(c *echo.Context) error {
+		report := run(c.Request().Context())
+
+		status := http.StatusOK
+		if report.Status != healthStatusOK {
+			status = http.StatusServiceUnavailable
+		}
+
+		return c.JSON(status, report)
+	}
+}
@@ -0,0 +1,127 @@
+package metricserver_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/metricserver"
+	"github.com/stretchr/testify/require"
+)
+
+var errPingFailed = errors.New("ping failed")
+
+type fakeSubscriber struct {
+	healthy bool
+}
+
+func (s *fakeSubscriber) IsHealthy() bool {
+	return s.healthy
+}
+
+func newCheckServer(t *testing.T, port int, timeout time.Duration) *metricserver.Server {
+	t.Helper()
+
+	return metricserver.New(&metricserver.Config{
+		Host:            "127.0.0.1",
+		Port:            port,
+		ReadTimeout:     2 * time.Second,
+		WriteTimeout:    2 * time.Second,
+		GracePeriod:     2 * time.Second,
+		Registry:        nil,
+		OTLP:            nil,
+		CheckTimeout:    timeout,
+		MetricsToken:    "",
+		MetricsUsername: "",
+		MetricsPassword: "",
+		TLSCertFile:     "",
+		TLSKeyFile:      "",
+		TLSClientCAFile: "",
+		Profiling:       nil,
+	})
+}
+
+func TestReadinessReportsEveryCheck(t *testing.T) {
+	t.Parallel()
+
+	server := newCheckServer(t, 0, 50*time.Millisecond)
+	server.AddReadinessCheck("postgres", func(context.Context) error { return nil })
+	server.AddReadinessCheck("valkey", func(context.Context) error { return errPingFailed })
+	server.AddReadinessCheck("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+
+		return ctx.Err()
+	})
+
+	report := server.Readiness(t.Context())
+
+	require.Equal(t, "unavailable", report.Status)
+	require.Len(t, report.Checks, 3)
+	require.True(t, report.Checks[0].Healthy)
+	require.Equal(t, "valkey", report.Checks[1].Name)
+	require.Equal(t, "ping failed", report.Checks[1].Error)
+	require.False(t, report.Checks[2].Healthy)
+	require.Equal(t, context.DeadlineExceeded.Error(), report.Checks[2].Error)
+	require.NotEmpty(t, report.Checks[2].Duration)
+}
+
+func TestHealthWithoutChecksIsOK(t *testing.T) {
+	t.Parallel()
+
+	server := newCheckServer(t, 0, 0)
+
+	report := server.Health(t.Context())
+
+	require.Equal(t, "ok", report.Status)
+	require.Empty(t, report.Checks)
+}
+
+func TestCheckService(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, metricserver.CheckService(&fakeSubscriber{healthy: true})(t.Context()))
+	require.ErrorIs(t, metricserver.CheckService(&fakeSubscriber{healthy: false})(t.Context()), metricserver.ErrUnhealthy)
+	require.ErrorIs(t, metricserver.CheckService(struct{}{})(t.Context()), metricserver.ErrCheckUnsupported)
+}
+
+func TestReadyzEndpoint(t *testing.T) {
+	t.Parallel()
+
+	server := newCheckServer(t, 9093, 0)
+	server.AddHealthCheck("process", func(context.Context) error { return nil })
+	server.AddReadinessCheck("subscriber", metricserver.CheckService(&fakeSubscriber{healthy: false}))
+
+	go func() {
+		_ = server.Start(t.Context())
+	}()
+
+	t.Cleanup(func() {
+		_ = server.Stop()
+	})
+
+	time.Sleep(500 * time.Millisecond)
+
+	testEndpoint(t, "http://127.0.0.1:9093/healthz", http.StatusOK, `"name":"process"`)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 2*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://127.0.0.1:9093/readyz", nil)
+	require.NoError(t, err)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	var report metricserver.CheckReport
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&report))
+	require.Equal(t, "unavailable", report.Status)
+	require.Equal(t, "subscriber", report.Checks[0].Name)
+	require.Equal(t, metricserver.ErrUnhealthy.Error(), report.Checks[0].Error)
+}
@@ -0,0 +1,85 @@
+package metricserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	promBridge "go.opentelemetry.io/contrib/bridges/prometheus"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+const defaultOTLPInterval = 30 * time.Second
+
+var ErrOTLPEndpointRequired = errors.New("metricserver: OTLP endpoint is required")
+
+// OTLPConfig pushes the metrics of the served registry to an OpenTelemetry collector over OTLP/HTTP
+// in addition to the /metrics scrape endpoint.
+type OTLPConfig struct {
+	// Endpoint is the full URL of the collector, e.g. "https://otel-collector:4318/v1/metrics".
+	// Plain http URLs disable TLS.
+	Endpoint string
+	// Headers are sent with every export, e.g. an authorization token.
+	Headers map[string]string
+	// Interval between exports. Defaults to 30s.
+	Interval time.Duration
+	// Timeout of an export. Defaults to the exporter's default of 10s.
+	Timeout time.Duration
+}
+
+func (s *Server) startOTLP(ctx context.Context) error {
+	cfg := s.otlp
+	if cfg == nil {
+		return nil
+	}
+
+	if cfg.Endpoint == "" {
+		return ErrOTLPEndpointRequired
+	}
+
+	opts := []otlpmetrichttp.Option{otlpmetrichttp.WithEndpointURL(cfg.Endpoint)}
+
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlpmetrichttp.WithHeaders(cfg.Headers))
+	}
+
+	if cfg.Timeout > 0 {
+		opts = append(opts, otlpmetrichttp.WithTimeout(cfg.Timeout))
+	}
+
+	exporter, err := otlpmetrichttp.New(ctx, opts...)
+	if err != nil {
+		return fmt.Errorf("metricserver: failed to create OTLP exporter: %w", err)
+	}
+
+	interval := cfg.Interval
+	if interval <= 0 {
+		interval = defaultOTLPInterval
+	}
+
+	reader := sdkmetric.NewPeriodicReader(exporter,
+		sdkmetric.WithInterval(interval),
+		sdkmetric.WithProducer(promBridge.NewMetricProducer(promBridge.WithGatherer(s.gatherer))),
+	)
+
+	s.meterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	return nil
+}
+
+// stopOTLP exports the final metrics and shuts the exporter down.
+func (s *Server) stopOTLP(ctx context.Context) error {
+	if s.meterProvider == nil {
+		return nil
+	}
+
+	if err := s.meterProvider.Shutdown(ctx); err != nil {
+		return fmt.Errorf("metricserver: failed to shut down OTLP exporter: %w", err)
+	}
+
+	s.meterProvider = nil
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package metricserver_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/metricserver"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_PushesOTLP(t *testing.T) {
+	t.Parallel()
+
+	type export struct {
+		path          string
+		authorization string
+		size          int64
+	}
+
+	exports := make(chan export, 10)
+
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		exports <- export{path: r.URL.Path, authorization: r.Header.Get("Authorization"), size: r.ContentLength}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(collector.Close)
+
+	reg := prometheus.NewRegistry()
+
+	counter := metricserver.NewCounterVec("orders", "created_total", "Number of orders created.")
+	require.NoError(t, metricserver.Register(reg, counter))
+	counter.WithLabelValues().Inc()
+
+	server := metricserver.New(&metricserver.Config{
+		Host:         "127.0.0.1",
+		Port:         0,
+		ReadTimeout:  time.Second,
+		WriteTimeout: time.Second,
+		GracePeriod:  2 * time.Second,
+		Registry:     reg,
+		OTLP: &metricserver.OTLPConfig{
+			Endpoint: collector.URL + "/v1/metrics",
+			Headers:  map[string]string{"Authorization": "Bearer token"},
+			Interval: 100 * time.Millisecond,
+			Timeout:  time.Second,
+		},
+		CheckTimeout:    0,
+		MetricsToken:    "",
+		MetricsUsername: "",
+		MetricsPassword: "",
+		TLSCertFile:     "",
+		TLSKeyFile:      "",
+		TLSClientCAFile: "",
+		Profiling:       nil,
+	})
+
+	require.NoError(t, server.Start(t.Context()))
+
+	select {
+	case received := <-exports:
+		require.Equal(t, "/v1/metrics", received.path)
+		require.Equal(t, "Bearer token", received.authorization)
+		require.Positive(t, received.size)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no metrics were exported")
+	}
+
+	require.NoError(t, server.Stop())
+}
+
+func TestServer_OTLPRequiresEndpoint(t *testing.T) {
+	t.Parallel()
+
+	server := metricserver.New(&metricserver.Config{
+		Host:            "127.0.0.1",
+		Port:            0,
+		ReadTimeout:     0,
+		WriteTimeout:    0,
+		GracePeriod:     0,
+		Registry:        prometheus.NewRegistry(),
+		OTLP:            &metricserver.OTLPConfig{Endpoint: "", Headers: nil, Interval: 0, Timeout: 0},
+		CheckTimeout:    0,
+		MetricsToken:    "",
+		MetricsUsername: "",
+		MetricsPassword: "",
+		TLSCertFile:     "",
+		TLSKeyFile:      "",
+		TLSClientCAFile: "",
+		Profiling:       nil,
+	})
+
+	require.ErrorIs(t, server.Start(t.Context()), metricserver.ErrOTLPEndpointRequired)
+}
@@ -0,0 +1,143 @@
+package metricserver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime/pprof"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	profilePath = "/debug/profile"
+
+	profileCPU               = "cpu"
+	defaultProfileSeconds    = 30
+	defaultProfileMaxSeconds = 60
+	profileWriteGrace        = 10 * time.Second
+)
+
+var ErrProfilingRequiresAuth = errors.New("metricserver: profiling requires MetricsToken, basic auth or TLSClientCAFile")
+
+// BlobStore receives captured profiles when set on ProfilingConfig.
+type BlobStore interface {
+	Put(ctx context.Context, key string, body io.Reader, contentType string) error
+}
+
+// ProfilingConfig enables GET /debug/profile?profile=cpu&seconds=30, protected by the same
+// credentials as /metrics. profile is "cpu" or any runtime/pprof profile, e.g. "heap" or "goroutine";
+// seconds only applies to cpu.
+type ProfilingConfig struct {
+	// MaxDuration caps the seconds of a CPU capture. Defaults to 60s.
+	MaxDuration time.Duration
+	// Store uploads the profile and responds with its key instead of returning the profile.
+	Store BlobStore
+	// KeyPrefix is prepended to the uploaded keys, e.g. "profiles/orders-api/".
+	KeyPrefix string
+}
+
+type profileUpload struct {
+	Key string `json:"key"`
+}
+
+func (s *Server) registerProfiling(cfg *Config) {
+	if cfg.Profiling == nil {
+		return
+	}
+
+	s.profiling = cfg.Profiling
+	s.echo.GET(profilePath, s.captureProfile, metricsAuth(cfg))
+}
+
+func (s *Server) captureProfile(c *echo.Context) error {
+	name := c.QueryParamOr("profile", profileCPU)
+
+	var (
+		buf bytes.Buffer
+		err error
+	)
+
+	if name == profileCPU {
+		err = s.captureCPUProfile(c, &buf)
+	} else {
+		err = writeProfile(name, &buf)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	filename := fmt.Sprintf("%s-%s.pb.gz", name, time.Now().UTC().Format("20060102T150405Z"))
+
+	if s.profiling.Store == nil {
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+
+		return c.Blob(http.StatusOK, echo.MIMEOctetStream, buf.Bytes())
+	}
+
+	key := s.profiling.KeyPrefix + filename
+
+	if err := s.profiling.Store.Put(c.Request().Context(), key, &buf, echo.MIMEOctetStream); err != nil {
+		log.Error().Str("source", "gframework").Err(err).Str("key", key).Msg("Failed to upload profile")
+
+		return echo.NewHTTPError(http.StatusBadGateway, "failed to upload profile")
+	}
+
+	return c.JSON(http.StatusOK, profileUpload{Key: key})
+}
+
+func (s *Server) captureCPUProfile(c *echo.Context, buf *bytes.Buffer) error {
+	seconds := defaultProfileSeconds
+
+	if raw := c.QueryParam("seconds"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "seconds must be a positive integer")
+		}
+
+		seconds = parsed
+	}
+
+	maxDuration := s.profiling.MaxDuration
+	if maxDuration <= 0 {
+		maxDuration = defaultProfileMaxSeconds * time.Second
+	}
+
+	duration := min(time.Duration(seconds)*time.Second, maxDuration)
+
+	// The capture outlives the server's WriteTimeout for longer durations.
+	_ = http.NewResponseController(c.Response()).SetWriteDeadline(time.Now().Add(duration + profileWriteGrace))
+
+	if err := pprof.StartCPUProfile(buf); err != nil {
+		return echo.NewHTTPError(http.StatusConflict, "a CPU profile is already being captured")
+	}
+
+	select {
+	case <-time.After(duration):
+	case <-c.Request().Context().Done():
+	}
+
+	pprof.StopCPUProfile()
+
+	return nil
+}
+
+func writeProfile(name string, buf *bytes.Buffer) error {
+	profile := pprof.Lookup(name)
+	if profile == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("unknown profile %q", name))
+	}
+
+	if err := profile.WriteTo(buf, 0); err != nil {
+		return fmt.Errorf("failed to write %s profile: %w", name, err)
+	}
+
+	return nil
+}
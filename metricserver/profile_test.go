@@ -0,0 +1,148 @@
+package metricserver_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/metricserver"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryBlobStore struct {
+	mu    sync.Mutex
+	blobs map[string][]byte
+}
+
+func (s *memoryBlobStore) Put(_ context.Context, key string, body io.Reader, _ string) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.blobs[key] = data
+
+	return nil
+}
+
+func newProfilingServer(t *testing.T, port int, token string, profiling *metricserver.ProfilingConfig) *metricserver.Server {
+	t.Helper()
+
+	return metricserver.New(&metricserver.Config{
+		Host:            "127.0.0.1",
+		Port:            port,
+		ReadTimeout:     2 * time.Second,
+		WriteTimeout:    2 * time.Second,
+		GracePeriod:     2 * time.Second,
+		Registry:        nil,
+		OTLP:            nil,
+		CheckTimeout:    0,
+		MetricsToken:    token,
+		MetricsUsername: "",
+		MetricsPassword: "",
+		TLSCertFile:     "",
+		TLSKeyFile:      "",
+		TLSClientCAFile: "",
+		Profiling:       profiling,
+	})
+}
+
+func fetchProfile(t *testing.T, url string) (int, http.Header, []byte) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer profile-token")
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	return resp.StatusCode, resp.Header, body
+}
+
+func TestProfilingRequiresAuth(t *testing.T) {
+	t.Parallel()
+
+	server := newProfilingServer(t, 0, "", &metricserver.ProfilingConfig{MaxDuration: 0, Store: nil, KeyPrefix: ""})
+
+	require.ErrorIs(t, server.Start(t.Context()), metricserver.ErrProfilingRequiresAuth)
+}
+
+func TestCaptureProfile(t *testing.T) {
+	t.Parallel()
+
+	store := &memoryBlobStore{blobs: map[string][]byte{}} //nolint:exhaustruct
+	server := newProfilingServer(t, 9095, "profile-token", &metricserver.ProfilingConfig{
+		MaxDuration: time.Second,
+		Store:       store,
+		KeyPrefix:   "profiles/",
+	})
+
+	go func() {
+		_ = server.Start(t.Context())
+	}()
+
+	t.Cleanup(func() {
+		_ = server.Stop()
+	})
+
+	time.Sleep(500 * time.Millisecond)
+
+	status, _, body := fetchProfile(t, "http://127.0.0.1:9095/debug/profile?profile=cpu&seconds=30")
+	require.Equal(t, http.StatusOK, status)
+
+	var upload struct {
+		Key string `json:"key"`
+	}
+
+	require.NoError(t, json.Unmarshal(body, &upload))
+	require.Regexp(t, `^profiles/cpu-\d{8}T\d{6}Z\.pb\.gz$`, upload.Key)
+
+	store.mu.Lock()
+	require.NotEmpty(t, store.blobs[upload.Key])
+	store.mu.Unlock()
+
+	status, _, _ = fetchProfile(t, "http://127.0.0.1:9095/debug/profile?profile=unknown")
+	require.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestCaptureProfileDownload(t *testing.T) {
+	t.Parallel()
+
+	server := newProfilingServer(t, 9096, "profile-token", &metricserver.ProfilingConfig{
+		MaxDuration: 0,
+		Store:       nil,
+		KeyPrefix:   "",
+	})
+
+	go func() {
+		_ = server.Start(t.Context())
+	}()
+
+	t.Cleanup(func() {
+		_ = server.Stop()
+	})
+
+	time.Sleep(500 * time.Millisecond)
+
+	status, header, body := fetchProfile(t, "http://127.0.0.1:9096/debug/profile?profile=goroutine")
+	require.Equal(t, http.StatusOK, status)
+	require.Contains(t, header.Get("Content-Disposition"), "goroutine-")
+	require.True(t, bytes.HasPrefix(body, []byte{0x1f, 0x8b}), "profile must be gzipped")
+}
@@ -1,5 +1,6 @@
-// Package metricserver provides a dedicated HTTP server for operational endpoints:
-// a /status health-check and a /metrics Prometheus scrape endpoint.
+// Package metricserver provides a dedicated HTTP server for operational endpoints: a /status
+// health-check, /healthz and /readyz aggregating registered checks, and a /metrics Prometheus scrape
+// endpoint.
 //
 // The server implements the runner.Service interface (Start, Stop, Name) and is designed to run
 // independently from the main application server. This separation allows metrics and health to be
@@ -16,23 +17,45 @@
 //
 //	// Endpoints:
 //	// GET /status     returns {"status":"ok"}
+//	// GET /healthz    runs the checks added with AddHealthCheck
+//	// GET /readyz     runs the checks added with AddReadinessCheck, e.g.
+//	//                 msrv.AddReadinessCheck("postgres", metricserver.CheckService(db))
 //	// GET /metrics    returns Prometheus-formatted metrics
 //
-// The metrics endpoint integrates with Prometheus client via echoprometheus middleware.
+// The metrics endpoint integrates with Prometheus client via echoprometheus middleware and serves the
+// global registry unless Config.Registry is set. Packages register their collectors with
+// NewCounterVec, NewGaugeVec, NewHistogramVec and Register so every metric follows the same
+// gframework_<subsystem>_<name> convention and bucket layouts:
+//
+//	m, err := cache.NewPrometheusMetrics(msrv.Registerer())
+//
+// Every registry exposes the Go runtime and process metrics and a gframework_build_info gauge labeled
+// with the module, version, commit and Go version, see ReadBuildInfo.
+//
+// Setting Config.OTLP additionally pushes the same metrics to an OpenTelemetry collector.
+//
+// When the metrics port is reachable from a shared network, protect /metrics with
+// Config.MetricsToken, basic auth or client certificates (TLSClientCAFile). The status and health
+// endpoints stay open so that probes keep working. The same credentials protect the on-demand
+// profile captures enabled with Config.Profiling.
 package metricserver
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"net"
 	"net/http"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/labstack/echo-contrib/echoprometheus"
 	"github.com/labstack/echo/v5"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rs/zerolog/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 )
 
 const (
@@ -46,15 +69,49 @@ type Config struct {
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
 	GracePeriod  time.Duration
+	// Registry is served on /metrics. Defaults to the global prometheus registry.
+	Registry *prometheus.Registry
+	// OTLP additionally pushes the metrics to an OpenTelemetry collector when set.
+	OTLP *OTLPConfig
+	// CheckTimeout bounds every check of /healthz and /readyz. Defaults to 3s.
+	CheckTimeout time.Duration
+	// MetricsToken requires "Authorization: Bearer <token>" on /metrics.
+	MetricsToken string
+	// MetricsUsername and MetricsPassword require basic auth on /metrics.
+	MetricsUsername string
+	MetricsPassword string
+	// TLSCertFile and TLSKeyFile serve HTTPS. With TLSClientCAFile set, /metrics also accepts clients
+	// presenting a certificate signed by that CA, and requires one unless a token or basic auth matches.
+	TLSCertFile     string
+	TLSKeyFile      string
+	TLSClientCAFile string
+	// Profiling enables on-demand profile captures on /debug/profile. It requires one of the
+	// authentication methods above.
+	Profiling *ProfilingConfig
 }
 
 type Server struct {
-	gracePeriod  time.Duration
-	readTimeout  time.Duration
-	writeTimeout time.Duration
-	address      string
-	echo         *echo.Echo
-	httpServer   *http.Server
+	gracePeriod   time.Duration
+	readTimeout   time.Duration
+	writeTimeout  time.Duration
+	address       string
+	registerer    prometheus.Registerer
+	gatherer      prometheus.Gatherer
+	otlp          *OTLPConfig
+	meterProvider *sdkmetric.MeterProvider
+	echo          *echo.Echo
+	httpServer    *http.Server
+
+	tlsCertFile     string
+	tlsKeyFile      string
+	tlsClientCAFile string
+	authEnabled     bool
+	profiling       *ProfilingConfig
+
+	checksMu        sync.RWMutex
+	checkTimeout    time.Duration
+	healthChecks    []namedCheck
+	readinessChecks []namedCheck
 }
 
 func New(cfg *Config) *Server {
@@ -64,25 +121,91 @@ func New(cfg *Config) *Server {
 		return ctx.JSON(http.StatusOK, map[string]any{"status": "ok"})
 	})
 
-	ech.GET(metricsPath, echoprometheus.NewHandler())
+	var (
+		registerer = prometheus.DefaultRegisterer
+		gatherer   = prometheus.DefaultGatherer
+	)
+
+	if cfg.Registry != nil {
+		registerer, gatherer = cfg.Registry, cfg.Registry
+	}
+
+	registerDefaultMetrics(registerer, cfg.Registry != nil)
+
+	ech.GET(
+		metricsPath,
+		echoprometheus.NewHandlerWithConfig(echoprometheus.HandlerConfig{Gatherer: gatherer}),
+		metricsAuth(cfg),
+	)
 
 	address := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
 
-	return &Server{ //nolint:exhaustruct
+	checkTimeout := cfg.CheckTimeout
+	if checkTimeout <= 0 {
+		checkTimeout = defaultCheckTimeout
+	}
+
+	server := &Server{ //nolint:exhaustruct
 		gracePeriod:  cfg.GracePeriod,
 		readTimeout:  cfg.ReadTimeout,
 		writeTimeout: cfg.WriteTimeout,
 		address:      address,
+		registerer:   registerer,
+		gatherer:     gatherer,
+		otlp:         cfg.OTLP,
 		echo:         ech,
+		checkTimeout: checkTimeout,
+
+		tlsCertFile:     cfg.TLSCertFile,
+		tlsKeyFile:      cfg.TLSKeyFile,
+		tlsClientCAFile: cfg.TLSClientCAFile,
+		authEnabled:     authConfigured(cfg),
 	}
+
+	server.registerProfiling(cfg)
+
+	ech.GET(healthzPath, checkHandler(server.Health))
+	ech.GET(readyzPath, checkHandler(server.Readiness))
+
+	return server
+}
+
+// Registerer returns the registry served on /metrics, to pass to the NewPrometheusMetrics
+// constructors of the other packages.
+func (s *Server) Registerer() prometheus.Registerer {
+	return s.registerer
+}
+
+func (s *Server) Gatherer() prometheus.Gatherer {
+	return s.gatherer
 }
 
-func (s *Server) Start(_ context.Context) error {
+func (s *Server) Start(ctx context.Context) error {
+	if s.profiling != nil && !s.authEnabled {
+		return ErrProfilingRequiresAuth
+	}
+
+	var tlsConfig *tls.Config
+
+	if s.tlsEnabled() {
+		var err error
+
+		tlsConfig, err = s.buildTLSConfig()
+		if err != nil {
+			return fmt.Errorf("failed to build TLS config: %w", err)
+		}
+	}
+
+	if err := s.startOTLP(ctx); err != nil {
+		return err
+	}
+
 	s.httpServer = &http.Server{ //nolint:exhaustruct
 		Addr:         s.address,
 		Handler:      s.echo,
 		ReadTimeout:  s.readTimeout,
 		WriteTimeout: s.writeTimeout,
+		TLSConfig:    tlsConfig,
 	}
 
 	log.Info().
@@ -91,7 +214,7 @@ func (s *Server) Start(_ context.Context) error {
 		Msg("The metrics server is being started")
 
 	go func() {
-		if err := s.httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		if err := s.listenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			log.Error().Str("source", "gframework").Err(err).Msg("Metrics server failed to start")
 		}
 	}()
@@ -99,6 +222,14 @@ func (s *Server) Start(_ context.Context) error {
 	return nil
 }
 
+func (s *Server) listenAndServe() error {
+	if s.httpServer.TLSConfig != nil {
+		return s.httpServer.ListenAndServeTLS("", "")
+	}
+
+	return s.httpServer.ListenAndServe()
+}
+
 func (s *Server) Stop() error {
 	log.Info().
 		Str("source", "gframework").
@@ -117,6 +248,12 @@ func (s *Server) Stop() error {
 		return fmt.Errorf("failed to stop metrics server: %w", err)
 	}
 
+	if err := s.stopOTLP(ctx); err != nil {
+		log.Error().Str("source", "gframework").Err(err).Msg("Failed to flush OTLP metrics")
+
+		return err
+	}
+
 	log.Info().
 		Str("source", "gframework").
 		Msg("The metrics server shutdown has been completed successfully")
@@ -127,3 +264,9 @@ func (s *Server) Stop() error {
 func (s *Server) Name() string {
 	return "metric"
 }
+
+// Handle registers an additional GET endpoint, e.g. the runner's aggregated health handler.
+// It must be called before Start.
+func (s *Server) Handle(path string, handler http.Handler) {
+	s.echo.GET(path, echo.WrapHandler(handler))
+}
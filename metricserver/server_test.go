@@ -15,11 +15,21 @@ func startServer(t *testing.T) *metricserver.Server {
 	t.Helper()
 
 	opts := &metricserver.Config{
-		Host:         "127.0.0.1",
-		Port:         9090,
-		ReadTimeout:  2 * time.Second,
-		WriteTimeout: 2 * time.Second,
-		GracePeriod:  2 * time.Second,
+		Host:            "127.0.0.1",
+		Port:            9090,
+		ReadTimeout:     2 * time.Second,
+		WriteTimeout:    2 * time.Second,
+		GracePeriod:     2 * time.Second,
+		Registry:        nil,
+		OTLP:            nil,
+		CheckTimeout:    0,
+		MetricsToken:    "",
+		MetricsUsername: "",
+		MetricsPassword: "",
+		TLSCertFile:     "",
+		TLSKeyFile:      "",
+		TLSClientCAFile: "",
+		Profiling:       nil,
 	}
 
 	server := metricserver.New(opts)
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"github.com/andyle182810/gframework/logutil"
+	"github.com/labstack/echo/v5"
+)
+
+// ContextLogger makes logutil.FromContext(ctx.Request().Context()) carry the request ID, tenant and user
+// of the request. The fields are resolved when the logger is built, so it does not matter whether
+// RequestID or JWT run before or after it.
+func ContextLogger() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx *echo.Context) error {
+			req := ctx.Request()
+
+			reqCtx := logutil.WithFieldsFunc(req.Context(), func() map[string]any {
+				return contextLogFields(ctx)
+			})
+			ctx.SetRequest(req.WithContext(reqCtx))
+
+			return next(ctx)
+		}
+	}
+}
+
+func contextLogFields(ctx *echo.Context) map[string]any {
+	fields := map[string]any{}
+
+	if id, ok := ctx.Get(ContextKeyRequestID).(string); ok && id != "" {
+		fields[logutil.FieldRequestID] = id
+	}
+
+	if tenantID := GetTenantID(ctx); tenantID != "" {
+		fields[logutil.FieldTenantID] = tenantID
+	}
+
+	if userID, err := CurrentUserID(ctx); err == nil {
+		fields[logutil.FieldUserID] = userID
+	}
+
+	return fields
+}
@@ -0,0 +1,57 @@
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/andyle182810/gframework/logutil"
+	"github.com/andyle182810/gframework/middleware"
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v5"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContextLogger(t *testing.T) {
+	t.Parallel()
+
+	ctx, _, _ := testutil.SetupEchoContext(t, &testutil.Options{
+		Method:        http.MethodGet,
+		Path:          "/test",
+		Body:          nil,
+		Headers:       nil,
+		QueryParams:   nil,
+		PathParams:    nil,
+		ContentType:   "",
+		SkipRequestID: true,
+	})
+
+	var buf bytes.Buffer
+
+	logger := zerolog.New(&buf)
+
+	handler := middleware.ContextLogger()(func(c *echo.Context) error {
+		// Set after ContextLogger ran, like RequestID and JWT registered later.
+		c.Set(middleware.ContextKeyRequestID, "req-1")
+		c.Set(middleware.ContextKeyTenantID, "acme")
+		c.Set(middleware.ContextKeyClaims, &middleware.ExtendedClaims{ //nolint:exhaustruct
+			RegisteredClaims: jwt.RegisteredClaims{Subject: "user-1"}, //nolint:exhaustruct
+		})
+
+		reqCtx := logutil.WithLogger(c.Request().Context(), &logger)
+		logutil.FromContext(reqCtx).Info().Msg("handled")
+
+		return nil
+	})
+
+	require.NoError(t, handler(ctx))
+
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	require.Equal(t, "req-1", line[logutil.FieldRequestID])
+	require.Equal(t, "acme", line[logutil.FieldTenantID])
+	require.Equal(t, "user-1", line[logutil.FieldUserID])
+}
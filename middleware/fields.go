@@ -0,0 +1,199 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+	"github.com/labstack/echo/v5/middleware"
+)
+
+const (
+	QueryParamFields     = "fields"
+	defaultMaxFieldPaths = 50
+	defaultMaxFieldDepth = 5
+)
+
+var (
+	ErrTooManyFieldPaths = errors.New("fields: too many field paths requested")
+	ErrFieldPathTooDeep  = errors.New("fields: field path is nested too deeply")
+	ErrInvalidFieldPath  = errors.New("fields: invalid field path")
+)
+
+type SparseFieldsConfig struct {
+	Skipper    middleware.Skipper
+	QueryParam string
+	MaxPaths   int
+	MaxDepth   int
+}
+
+func DefaultSparseFieldsConfig() SparseFieldsConfig {
+	return SparseFieldsConfig{
+		Skipper:    middleware.DefaultSkipper,
+		QueryParam: QueryParamFields,
+		MaxPaths:   defaultMaxFieldPaths,
+		MaxDepth:   defaultMaxFieldDepth,
+	}
+}
+
+// SparseFields parses the fields query parameter (e.g. ?fields=id,name,address.city) and stores the
+// requested paths in the context. httpserver.Wrapper prunes the response data to those paths.
+func SparseFields() echo.MiddlewareFunc {
+	return SparseFieldsWithConfig(DefaultSparseFieldsConfig())
+}
+
+func SparseFieldsWithConfig(config SparseFieldsConfig) echo.MiddlewareFunc {
+	if config.Skipper == nil {
+		config.Skipper = middleware.DefaultSkipper
+	}
+
+	if config.QueryParam == "" {
+		config.QueryParam = QueryParamFields
+	}
+
+	if config.MaxPaths <= 0 {
+		config.MaxPaths = defaultMaxFieldPaths
+	}
+
+	if config.MaxDepth <= 0 {
+		config.MaxDepth = defaultMaxFieldDepth
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx *echo.Context) error {
+			if config.Skipper(ctx) {
+				return next(ctx)
+			}
+
+			raw := ctx.QueryParam(config.QueryParam)
+			if raw == "" {
+				return next(ctx)
+			}
+
+			fields, err := ParseFieldPaths(raw, config.MaxPaths, config.MaxDepth)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+
+			ctx.Set(ContextKeyFields, fields)
+
+			return next(ctx)
+		}
+	}
+}
+
+func GetFields(c *echo.Context) []string {
+	if fields, ok := c.Get(ContextKeyFields).([]string); ok {
+		return fields
+	}
+
+	return nil
+}
+
+func ParseFieldPaths(raw string, maxPaths, maxDepth int) ([]string, error) {
+	parts := strings.Split(raw, ",")
+	fields := make([]string, 0, len(parts))
+
+	for _, part := range parts {
+		path := strings.TrimSpace(part)
+		if path == "" {
+			continue
+		}
+
+		segments := strings.Split(path, ".")
+		if len(segments) > maxDepth {
+			return nil, fmt.Errorf("%w: %s", ErrFieldPathTooDeep, path)
+		}
+
+		for _, segment := range segments {
+			if segment == "" {
+				return nil, fmt.Errorf("%w: %s", ErrInvalidFieldPath, path)
+			}
+		}
+
+		fields = append(fields, path)
+	}
+
+	if len(fields) > maxPaths {
+		return nil, fmt.Errorf("%w: %d > %d", ErrTooManyFieldPaths, len(fields), maxPaths)
+	}
+
+	return fields, nil
+}
+
+type fieldTree map[string]fieldTree
+
+func buildFieldTree(paths []string) fieldTree {
+	root := fieldTree{}
+
+	for _, path := range paths {
+		node := root
+
+		for _, segment := range strings.Split(path, ".") {
+			child, ok := node[segment]
+			if !ok {
+				child = fieldTree{}
+				node[segment] = child
+			}
+
+			node = child
+		}
+	}
+
+	return root
+}
+
+// FilterFields returns the JSON representation of value reduced to the given dot-separated paths.
+// Paths apply to every element when they traverse an array; unknown paths are ignored.
+func FilterFields(value any, paths []string) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("fields: failed to marshal value: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var decoded any
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, fmt.Errorf("fields: failed to decode value: %w", err)
+	}
+
+	if len(paths) == 0 {
+		return decoded, nil
+	}
+
+	return pruneFields(decoded, buildFieldTree(paths)), nil
+}
+
+func pruneFields(value any, tree fieldTree) any {
+	if len(tree) == 0 {
+		return value
+	}
+
+	switch typed := value.(type) {
+	case map[string]any:
+		pruned := make(map[string]any, len(tree))
+
+		for key, subtree := range tree {
+			if child, ok := typed[key]; ok {
+				pruned[key] = pruneFields(child, subtree)
+			}
+		}
+
+		return pruned
+	case []any:
+		pruned := make([]any, len(typed))
+		for i, item := range typed {
+			pruned[i] = pruneFields(item, tree)
+		}
+
+		return pruned
+	default:
+		return value
+	}
+}
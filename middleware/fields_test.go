@@ -0,0 +1,144 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/andyle182810/gframework/middleware"
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/require"
+)
+
+type fieldsAddress struct {
+	City    string `json:"city"`
+	Country string `json:"country"`
+}
+
+type fieldsUser struct {
+	ID      int           `json:"id"`
+	Name    string        `json:"name"`
+	Email   string        `json:"email"`
+	Address fieldsAddress `json:"address"`
+}
+
+func TestParseFieldPaths(t *testing.T) {
+	t.Parallel()
+
+	fields, err := middleware.ParseFieldPaths(" id, name ,,address.city", 10, 3)
+	require.NoError(t, err)
+	require.Equal(t, []string{"id", "name", "address.city"}, fields)
+
+	_, err = middleware.ParseFieldPaths("a.b.c.d", 10, 3)
+	require.ErrorIs(t, err, middleware.ErrFieldPathTooDeep)
+
+	_, err = middleware.ParseFieldPaths("address..city", 10, 3)
+	require.ErrorIs(t, err, middleware.ErrInvalidFieldPath)
+
+	_, err = middleware.ParseFieldPaths("a,b,c", 2, 3)
+	require.ErrorIs(t, err, middleware.ErrTooManyFieldPaths)
+}
+
+func TestFilterFields_NestedAndArrays(t *testing.T) {
+	t.Parallel()
+
+	users := []fieldsUser{
+		{ID: 1, Name: "Alice", Email: "alice@example.com", Address: fieldsAddress{City: "Hanoi", Country: "VN"}},
+		{ID: 2, Name: "Bob", Email: "bob@example.com", Address: fieldsAddress{City: "Tokyo", Country: "JP"}},
+	}
+
+	filtered, err := middleware.FilterFields(users, []string{"id", "address.city", "unknown"})
+	require.NoError(t, err)
+
+	items, ok := filtered.([]any)
+	require.True(t, ok)
+	require.Len(t, items, 2)
+
+	first, ok := items[0].(map[string]any)
+	require.True(t, ok)
+	require.Len(t, first, 2)
+	require.Equal(t, json.Number("1"), first["id"])
+	require.Equal(t, map[string]any{"city": "Hanoi"}, first["address"])
+}
+
+func TestFilterFields_NoPathsReturnsFullValue(t *testing.T) {
+	t.Parallel()
+
+	user := fieldsUser{ID: 1, Name: "Alice", Email: "alice@example.com", Address: fieldsAddress{City: "", Country: ""}}
+
+	filtered, err := middleware.FilterFields(user, nil)
+	require.NoError(t, err)
+
+	decoded, ok := filtered.(map[string]any)
+	require.True(t, ok)
+	require.Len(t, decoded, 4)
+}
+
+func TestSparseFieldsMiddleware(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		query          map[string]string
+		expectedStatus int
+		expectedFields []string
+	}{
+		{
+			name:           "no fields param",
+			query:          nil,
+			expectedStatus: http.StatusOK,
+			expectedFields: nil,
+		},
+		{
+			name:           "fields param parsed",
+			query:          map[string]string{"fields": "id,address.city"},
+			expectedStatus: http.StatusOK,
+			expectedFields: []string{"id", "address.city"},
+		},
+		{
+			name:           "invalid fields param rejected",
+			query:          map[string]string{"fields": "a.b.c.d.e.f"},
+			expectedStatus: http.StatusBadRequest,
+			expectedFields: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, rec, _ := testutil.SetupEchoContext(t, &testutil.Options{
+				Method:        http.MethodGet,
+				Path:          "/users",
+				Body:          nil,
+				Headers:       nil,
+				QueryParams:   tt.query,
+				PathParams:    nil,
+				ContentType:   "",
+				SkipRequestID: false,
+			})
+
+			var captured []string
+
+			handler := middleware.SparseFields()(func(c *echo.Context) error {
+				captured = middleware.GetFields(c)
+
+				return c.NoContent(http.StatusOK)
+			})
+
+			err := handler(ctx)
+			if tt.expectedStatus != http.StatusOK {
+				var httpErr *echo.HTTPError
+				require.ErrorAs(t, err, &httpErr)
+				require.Equal(t, tt.expectedStatus, httpErr.Code)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, rec.Code)
+			require.Equal(t, tt.expectedFields, captured)
+		})
+	}
+}
@@ -0,0 +1,434 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+	"github.com/labstack/echo/v5/middleware"
+)
+
+const (
+	defaultJSONMaxDepth        = 32
+	defaultJSONMaxArrayLength  = 10000
+	defaultJSONMaxStringLength = 1 << 16
+	defaultJSONMaxBodySize     = 1 << 20
+)
+
+var (
+	ErrJSONTooDeep       = errors.New("json guard: document is nested too deeply")
+	ErrJSONArrayTooLong  = errors.New("json guard: array has too many elements")
+	ErrJSONStringTooLong = errors.New("json guard: string is too long")
+	ErrJSONBodyTooLarge  = errors.New("json guard: body exceeds maximum size")
+	ErrJSONMalformed     = errors.New("json guard: malformed document")
+)
+
+type JSONGuardConfig struct {
+	Skipper        middleware.Skipper
+	MaxDepth       int
+	MaxArrayLength int
+	// MaxStringLength bounds keys and string values by their length in the body, escapes included.
+	MaxStringLength int
+	MaxBodySize     int64
+}
+
+func DefaultJSONGuardConfig() JSONGuardConfig {
+	return JSONGuardConfig{
+		Skipper:         middleware.DefaultSkipper,
+		MaxDepth:        defaultJSONMaxDepth,
+		MaxArrayLength:  defaultJSONMaxArrayLength,
+		MaxStringLength: defaultJSONMaxStringLength,
+		MaxBodySize:     defaultJSONMaxBodySize,
+	}
+}
+
+// JSONGuard rejects JSON bodies that exceed size, depth, array length or string length limits, are
+// malformed or hold more than one top-level value. The body is scanned byte by byte as it is read, so
+// a hostile document is rejected as soon as a limit is crossed and at most MaxBodySize bytes are kept
+// for the handler.
+func JSONGuard() echo.MiddlewareFunc {
+	return JSONGuardWithConfig(DefaultJSONGuardConfig())
+}
+
+func JSONGuardWithConfig(config JSONGuardConfig) echo.MiddlewareFunc {
+	if config.Skipper == nil {
+		config.Skipper = middleware.DefaultSkipper
+	}
+
+	if config.MaxDepth <= 0 {
+		config.MaxDepth = defaultJSONMaxDepth
+	}
+
+	if config.MaxArrayLength <= 0 {
+		config.MaxArrayLength = defaultJSONMaxArrayLength
+	}
+
+	if config.MaxStringLength <= 0 {
+		config.MaxStringLength = defaultJSONMaxStringLength
+	}
+
+	if config.MaxBodySize <= 0 {
+		config.MaxBodySize = defaultJSONMaxBodySize
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx *echo.Context) error {
+			request := ctx.Request()
+			if config.Skipper(ctx) || request.Body == nil || !isJSONContentType(request.Header.Get(echo.HeaderContentType)) {
+				return next(ctx)
+			}
+
+			var buffer bytes.Buffer
+
+			err := inspectJSON(io.TeeReader(request.Body, &buffer), &config)
+			if err != nil {
+				status := http.StatusRequestEntityTooLarge
+				if errors.Is(err, ErrJSONMalformed) {
+					status = http.StatusBadRequest
+				}
+
+				return echo.NewHTTPError(status, err.Error())
+			}
+
+			request.Body = io.NopCloser(&buffer)
+
+			return next(ctx)
+		}
+	}
+}
+
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return mediaType == echo.MIMEApplicationJSON || strings.HasSuffix(mediaType, "+json")
+}
+
+type jsonFrame struct {
+	array bool
+	count int
+}
+
+// jsonExpect is what the scanner accepts next, apart from whitespace.
+type jsonExpect int
+
+const (
+	expectValue jsonExpect = iota
+	// expectValueOrEnd follows '['.
+	expectValueOrEnd
+	expectKey
+	// expectKeyOrEnd follows '{'.
+	expectKeyOrEnd
+	expectColon
+	expectCommaOrEnd
+	// expectEOF follows the top-level value.
+	expectEOF
+)
+
+type jsonScanner struct {
+	reader *bufio.Reader
+	config *JSONGuardConfig
+	read   int64
+	stack  []*jsonFrame
+	expect jsonExpect
+}
+
+func inspectJSON(reader io.Reader, config *JSONGuardConfig) error {
+	scanner := &jsonScanner{
+		reader: bufio.NewReader(io.LimitReader(reader, config.MaxBodySize+1)),
+		config: config,
+		read:   0,
+		stack:  make([]*jsonFrame, 0, config.MaxDepth),
+		expect: expectValue,
+	}
+
+	return scanner.scan()
+}
+
+func (s *jsonScanner) scan() error {
+	for {
+		b, err := s.readByte()
+		if errors.Is(err, io.EOF) {
+			if s.expect != expectEOF {
+				return fmt.Errorf("%w: unexpected end of input", ErrJSONMalformed)
+			}
+
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		switch b {
+		case ' ', '\t', '\n', '\r':
+			continue
+		}
+
+		if err := s.token(b); err != nil {
+			return err
+		}
+	}
+}
+
+// readByte returns the next byte of the body, or io.EOF at its end.
+func (s *jsonScanner) readByte() (byte, error) {
+	b, err := s.reader.ReadByte()
+	if errors.Is(err, io.EOF) {
+		return 0, io.EOF
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrJSONMalformed, err)
+	}
+
+	s.read++
+	if s.read > s.config.MaxBodySize {
+		return 0, fmt.Errorf("%w: more than %d bytes", ErrJSONBodyTooLarge, s.config.MaxBodySize)
+	}
+
+	return b, nil
+}
+
+// next returns the next byte inside a token, where the end of the body is malformed.
+func (s *jsonScanner) next() (byte, error) {
+	b, err := s.readByte()
+	if errors.Is(err, io.EOF) {
+		return 0, fmt.Errorf("%w: unexpected end of input", ErrJSONMalformed)
+	}
+
+	return b, err
+}
+
+func (s *jsonScanner) token(b byte) error {
+	switch b {
+	case '{', '[':
+		if err := s.beginValue(b); err != nil {
+			return err
+		}
+
+		if len(s.stack) >= s.config.MaxDepth {
+			return fmt.Errorf("%w: more than %d levels", ErrJSONTooDeep, s.config.MaxDepth)
+		}
+
+		s.stack = append(s.stack, &jsonFrame{array: b == '[', count: 0})
+
+		s.expect = expectKeyOrEnd
+		if b == '[' {
+			s.expect = expectValueOrEnd
+		}
+
+		return nil
+	case '}', ']':
+		return s.endContainer(b)
+	case ':':
+		if s.expect != expectColon {
+			return unexpectedJSONByte(b)
+		}
+
+		s.expect = expectValue
+
+		return nil
+	case ',':
+		if s.expect != expectCommaOrEnd {
+			return unexpectedJSONByte(b)
+		}
+
+		s.expect = expectKey
+		if s.stack[len(s.stack)-1].array {
+			s.expect = expectValue
+		}
+
+		return nil
+	case '"':
+		if s.expect == expectKey || s.expect == expectKeyOrEnd {
+			s.expect = expectColon
+
+			return s.scanString("key")
+		}
+
+		if err := s.beginValue(b); err != nil {
+			return err
+		}
+
+		if err := s.scanString("value"); err != nil {
+			return err
+		}
+
+		s.endValue()
+
+		return nil
+	default:
+		if err := s.beginValue(b); err != nil {
+			return err
+		}
+
+		if err := s.scanLiteral(b); err != nil {
+			return err
+		}
+
+		s.endValue()
+
+		return nil
+	}
+}
+
+// beginValue checks that a value may start here and counts it against the length of its array.
+func (s *jsonScanner) beginValue(b byte) error {
+	switch s.expect {
+	case expectValue, expectValueOrEnd:
+	case expectEOF:
+		return fmt.Errorf("%w: data after the top-level value", ErrJSONMalformed)
+	case expectKey, expectKeyOrEnd, expectColon, expectCommaOrEnd:
+		return unexpectedJSONByte(b)
+	}
+
+	if len(s.stack) == 0 {
+		return nil
+	}
+
+	if top := s.stack[len(s.stack)-1]; top.array {
+		top.count++
+		if top.count > s.config.MaxArrayLength {
+			return fmt.Errorf("%w: more than %d", ErrJSONArrayTooLong, s.config.MaxArrayLength)
+		}
+	}
+
+	return nil
+}
+
+func (s *jsonScanner) endValue() {
+	s.expect = expectCommaOrEnd
+	if len(s.stack) == 0 {
+		s.expect = expectEOF
+	}
+}
+
+func (s *jsonScanner) endContainer(b byte) error {
+	if len(s.stack) == 0 || s.stack[len(s.stack)-1].array != (b == ']') {
+		return unexpectedJSONByte(b)
+	}
+
+	if s.expect != expectCommaOrEnd &&
+		!(b == ']' && s.expect == expectValueOrEnd) && !(b == '}' && s.expect == expectKeyOrEnd) {
+		return unexpectedJSONByte(b)
+	}
+
+	s.stack = s.stack[:len(s.stack)-1]
+	s.endValue()
+
+	return nil
+}
+
+// scanString reads a string up to its closing quote and stops as soon as it crosses MaxStringLength.
+func (s *jsonScanner) scanString(kind string) error {
+	for length := 0; ; length++ {
+		if length > s.config.MaxStringLength {
+			return fmt.Errorf("%w: %s of more than %d bytes", ErrJSONStringTooLong, kind, s.config.MaxStringLength)
+		}
+
+		b, err := s.next()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case b == '"':
+			return nil
+		case b == '\\':
+			escaped, err := s.scanEscape()
+			if err != nil {
+				return err
+			}
+
+			length += escaped
+		case b < ' ':
+			return fmt.Errorf("%w: control character in string", ErrJSONMalformed)
+		}
+	}
+}
+
+// scanEscape reads the escape sequence following a backslash and returns the number of bytes it read.
+func (s *jsonScanner) scanEscape() (int, error) {
+	b, err := s.next()
+	if err != nil {
+		return 0, err
+	}
+
+	switch b {
+	case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+		return 1, nil
+	case 'u':
+		for range 4 {
+			digit, err := s.next()
+			if err != nil {
+				return 0, err
+			}
+
+			if !isHexDigit(digit) {
+				return 0, fmt.Errorf("%w: invalid unicode escape", ErrJSONMalformed)
+			}
+		}
+
+		return 5, nil
+	default:
+		return 0, fmt.Errorf("%w: invalid escape %q", ErrJSONMalformed, b)
+	}
+}
+
+// scanLiteral reads a number, true, false or null starting with first. Literals are bounded by
+// MaxStringLength as well.
+func (s *jsonScanner) scanLiteral(first byte) error {
+	literal := []byte{first}
+
+	for {
+		b, err := s.readByte()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if !isJSONLiteralByte(b) {
+			_ = s.reader.UnreadByte()
+			s.read--
+
+			break
+		}
+
+		if len(literal) >= s.config.MaxStringLength {
+			return fmt.Errorf("%w: literal of more than %d bytes", ErrJSONStringTooLong, s.config.MaxStringLength)
+		}
+
+		literal = append(literal, b)
+	}
+
+	if !json.Valid(literal) {
+		return fmt.Errorf("%w: invalid literal %q", ErrJSONMalformed, literal)
+	}
+
+	return nil
+}
+
+func unexpectedJSONByte(b byte) error {
+	return fmt.Errorf("%w: unexpected %q", ErrJSONMalformed, b)
+}
+
+func isJSONLiteralByte(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9' || b == '+' || b == '-' || b == '.'
+}
+
+func isHexDigit(b byte) bool {
+	return b >= '0' && b <= '9' || b >= 'a' && b <= 'f' || b >= 'A' && b <= 'F'
+}
@@ -0,0 +1,173 @@
+package middleware_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/andyle182810/gframework/middleware"
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/labstack/echo/v5"
+	echomiddleware "github.com/labstack/echo/v5/middleware"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONGuardMiddleware(t *testing.T) {
+	t.Parallel()
+
+	config := middleware.JSONGuardConfig{
+		Skipper:         echomiddleware.DefaultSkipper,
+		MaxDepth:        3,
+		MaxArrayLength:  3,
+		MaxStringLength: 8,
+		MaxBodySize:     64,
+	}
+
+	tests := []struct {
+		name           string
+		body           string
+		contentType    string
+		expectedStatus int
+	}{
+		{
+			name:           "valid document",
+			body:           `{"name":"alice","tags":["a","b"],"meta":{"age":30}}`,
+			contentType:    "",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "too deep",
+			body:           `{"a":{"b":{"c":{"d":1}}}}`,
+			contentType:    "",
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "array too long",
+			body:           `{"items":[1,2,3,4]}`,
+			contentType:    "",
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "string value too long",
+			body:           `{"name":"` + strings.Repeat("x", 9) + `"}`,
+			contentType:    "",
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "key too long",
+			body:           `{"` + strings.Repeat("k", 9) + `":1}`,
+			contentType:    "",
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "malformed document",
+			body:           `{"name":`,
+			contentType:    "",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "escaped string too long",
+			body:           `{"name":"\u0041\u0042"}`,
+			contentType:    "",
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "body too large",
+			body:           `{"a":1}` + strings.Repeat(" ", 64),
+			contentType:    "",
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "trailing top-level value",
+			body:           `{"a":1} {"b":2}`,
+			contentType:    "",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "missing comma",
+			body:           `{"a":1 "b":2}`,
+			contentType:    "",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "mismatched brackets",
+			body:           `{"a":[1}`,
+			contentType:    "",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid literal",
+			body:           `{"a":nul}`,
+			contentType:    "",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "top-level scalar",
+			body:           ` -1.5e3 `,
+			contentType:    "",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "content type is matched case-insensitively",
+			body:           `{"a":{"b":{"c":{"d":1}}}}`,
+			contentType:    "Application/JSON; charset=UTF-8",
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "structured syntax suffix",
+			body:           `{"a":{"b":{"c":{"d":1}}}}`,
+			contentType:    "application/problem+json",
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "non json content type is ignored",
+			body:           `{"a":{"b":{"c":{"d":1}}}}`,
+			contentType:    "text/plain",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, rec, _ := testutil.SetupEchoContext(t, &testutil.Options{
+				Method:        http.MethodPost,
+				Path:          "/users",
+				Body:          []byte(tt.body),
+				Headers:       nil,
+				QueryParams:   nil,
+				PathParams:    nil,
+				ContentType:   tt.contentType,
+				SkipRequestID: false,
+			})
+
+			var received string
+
+			handler := middleware.JSONGuardWithConfig(config)(func(c *echo.Context) error {
+				data, err := io.ReadAll(c.Request().Body)
+				if err != nil {
+					return err
+				}
+
+				received = string(data)
+
+				return c.NoContent(http.StatusOK)
+			})
+
+			err := handler(ctx)
+			if tt.expectedStatus != http.StatusOK {
+				var httpErr *echo.HTTPError
+				require.ErrorAs(t, err, &httpErr)
+				require.Equal(t, tt.expectedStatus, httpErr.Code)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, rec.Code)
+			require.Equal(t, tt.body, received)
+		})
+	}
+}
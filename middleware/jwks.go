@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/MicahParks/keyfunc/v3"
+	"github.com/andyle182810/gframework/jwks"
+)
+
+// NewJWKSKeyfunc builds the Keyfunc for JWT from a single JWKS endpoint, e.g. the Keycloak realm certs URL.
+// Keys are refreshed every refreshInterval until ctx is done, and a token with an unknown kid triggers a
+// rate-limited re-fetch so rotated keys are picked up without waiting for the next refresh. A failed
+// refresh keeps the cached keys, and an endpoint that is down at startup does not fail construction.
+// opts tune the rate limit, timeout and HTTP client as in jwks.New.
+//
+//nolint:ireturn
+func NewJWKSKeyfunc(
+	ctx context.Context,
+	url string,
+	refreshInterval time.Duration,
+	opts ...jwks.Option,
+) (keyfunc.Keyfunc, error) {
+	opts = append([]jwks.Option{jwks.WithRefreshInterval(refreshInterval)}, opts...)
+
+	return jwks.New(ctx, []string{url}, opts...)
+}
@@ -0,0 +1,110 @@
+package middleware_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MicahParks/jwkset"
+	"github.com/andyle182810/gframework/jwks"
+	"github.com/andyle182810/gframework/middleware"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+type jwksStub struct {
+	mu      sync.Mutex
+	storage *jwkset.MemoryJWKSet
+	down    bool
+}
+
+func (s *jwksStub) addKey(t *testing.T, kid string) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	jwk, err := jwkset.NewJWKFromKey(key.Public(), jwkset.JWKOptions{ //nolint:exhaustruct
+		Metadata: jwkset.JWKMetadataOptions{KID: kid, ALG: jwkset.AlgRS256, USE: jwkset.UseSig}, //nolint:exhaustruct
+	})
+	require.NoError(t, err)
+	require.NoError(t, s.storage.KeyWrite(t.Context(), jwk))
+
+	return key
+}
+
+func (s *jwksStub) setDown(down bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.down = down
+}
+
+func (s *jwksStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.down {
+		w.WriteHeader(http.StatusServiceUnavailable)
+
+		return
+	}
+
+	body, err := s.storage.JSONPublic(r.Context())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(body)
+}
+
+func signWithKID(t *testing.T, key *rsa.PrivateKey, kid string) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.RegisteredClaims{ //nolint:exhaustruct
+		Subject:   "user-1",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	})
+	token.Header["kid"] = kid
+
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	return signed
+}
+
+func TestNewJWKSKeyfunc_RefetchesUnknownKIDAndKeepsCachedKeys(t *testing.T) {
+	t.Parallel()
+
+	stub := &jwksStub{storage: jwkset.NewMemoryStorage()} //nolint:exhaustruct
+	firstKey := stub.addKey(t, "first")
+
+	server := httptest.NewServer(stub)
+	t.Cleanup(server.Close)
+
+	kf, err := middleware.NewJWKSKeyfunc(t.Context(), server.URL, time.Hour, jwks.WithRateLimitWaitMax(time.Second))
+	require.NoError(t, err)
+
+	_, err = jwt.Parse(signWithKID(t, firstKey, "first"), kf.Keyfunc)
+	require.NoError(t, err)
+
+	rotatedKey := stub.addKey(t, "rotated")
+
+	_, err = jwt.Parse(signWithKID(t, rotatedKey, "rotated"), kf.Keyfunc)
+	require.NoError(t, err)
+
+	stub.setDown(true)
+
+	_, err = jwt.Parse(signWithKID(t, firstKey, "first"), kf.Keyfunc)
+	require.NoError(t, err)
+
+	_, err = jwt.Parse(signWithKID(t, firstKey, "unknown"), kf.Keyfunc)
+	require.Error(t, err)
+}
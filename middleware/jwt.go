@@ -1,8 +1,12 @@
 package middleware
 
 import (
+	"errors"
+	"fmt"
+	"maps"
 	"net/http"
 	"slices"
+	"time"
 
 	"github.com/MicahParks/keyfunc/v3"
 	"github.com/golang-jwt/jwt/v5"
@@ -17,6 +21,9 @@ var (
 	ErrTokenRequired   = echo.NewHTTPError(http.StatusUnauthorized, "Authorization header is required")
 	ErrJWKSFetchFailed = echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch JWKS")
 	ErrInvalidToken    = echo.NewHTTPError(http.StatusUnauthorized, "Invalid token")
+
+	ErrIssuerNotAllowed = errors.New("jwt: issuer not allowed")
+	ErrNoKeyfunc        = errors.New("jwt: no keyfunc configured")
 )
 
 type RoleAccess struct {
@@ -79,23 +86,37 @@ func (c *ExtendedClaims) GetResourceRoles(resource string) []string {
 	return access.Roles
 }
 
+// JWTConfig configures JWTWithConfig.
+//
+// Issuers and Audiences restrict the iss and aud claims; a token must carry one of the issuers and at least
+// one of the audiences. IssuerKeyfuncs verifies tokens of each issuer with its own keys, e.g. the internal
+// IdP and a partner IdP, and implicitly allows those issuers. Tokens of other issuers are verified with
+// Keyfunc. ClockSkew is the leeway applied to exp, nbf and iat.
 type JWTConfig struct {
-	Skipper       middleware.Skipper
-	Logger        *zerolog.Logger
-	Keyfunc       keyfunc.Keyfunc
-	NewClaimsFunc func(*echo.Context) jwt.Claims
-	ContextKey    string
-	TokenLookup   string
+	Skipper        middleware.Skipper
+	Logger         *zerolog.Logger
+	Keyfunc        keyfunc.Keyfunc
+	IssuerKeyfuncs map[string]keyfunc.Keyfunc
+	Issuers        []string
+	Audiences      []string
+	ClockSkew      time.Duration
+	NewClaimsFunc  func(*echo.Context) jwt.Claims
+	ContextKey     string
+	TokenLookup    string
 }
 
 func DefaultJWTConfig() JWTConfig {
 	return JWTConfig{
-		Skipper:       middleware.DefaultSkipper,
-		Logger:        &log.Logger,
-		Keyfunc:       nil,
-		NewClaimsFunc: defaultNewClaimsFunc,
-		ContextKey:    "user",
-		TokenLookup:   "",
+		Skipper:        middleware.DefaultSkipper,
+		Logger:         &log.Logger,
+		Keyfunc:        nil,
+		IssuerKeyfuncs: nil,
+		Issuers:        nil,
+		Audiences:      nil,
+		ClockSkew:      0,
+		NewClaimsFunc:  defaultNewClaimsFunc,
+		ContextKey:     "user",
+		TokenLookup:    "",
 	}
 }
 
@@ -141,18 +162,16 @@ func JWTWithConfig(config JWTConfig) echo.MiddlewareFunc {
 
 func buildJWTConfig(config JWTConfig) echojwt.Config {
 	return echojwt.Config{
-		Skipper:          nil,
-		BeforeFunc:       nil,
-		ContextKey:       config.ContextKey,
-		SigningKey:       nil,
-		SigningKeys:      nil,
-		SigningMethod:    "",
-		TokenLookup:      config.TokenLookup,
-		TokenLookupFuncs: nil,
-		ParseTokenFunc:   nil,
-		KeyFunc: func(token *jwt.Token) (any, error) {
-			return config.Keyfunc.Keyfunc(token)
-		},
+		Skipper:                nil,
+		BeforeFunc:             nil,
+		ContextKey:             config.ContextKey,
+		SigningKey:             nil,
+		SigningKeys:            nil,
+		SigningMethod:          "",
+		TokenLookup:            config.TokenLookup,
+		TokenLookupFuncs:       nil,
+		ParseTokenFunc:         newParseTokenFunc(config),
+		KeyFunc:                nil,
 		NewClaimsFunc:          config.NewClaimsFunc,
 		SuccessHandler:         createSuccessHandler(config.Logger),
 		ErrorHandler:           createErrorHandler(config.Logger),
@@ -160,6 +179,47 @@ func buildJWTConfig(config JWTConfig) echojwt.Config {
 	}
 }
 
+func newParseTokenFunc(config JWTConfig) func(*echo.Context, string) (any, error) {
+	parserOptions := []jwt.ParserOption{jwt.WithLeeway(config.ClockSkew)}
+	if len(config.Audiences) > 0 {
+		parserOptions = append(parserOptions, jwt.WithAudience(config.Audiences...))
+	}
+
+	parser := jwt.NewParser(parserOptions...)
+
+	issuers := slices.Concat(config.Issuers, slices.Collect(maps.Keys(config.IssuerKeyfuncs)))
+
+	keyFunc := func(token *jwt.Token) (any, error) {
+		issuer, err := token.Claims.GetIssuer()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(issuers) > 0 && !slices.Contains(issuers, issuer) {
+			return nil, fmt.Errorf("%w: %q", ErrIssuerNotAllowed, issuer)
+		}
+
+		if kf, ok := config.IssuerKeyfuncs[issuer]; ok {
+			return kf.Keyfunc(token)
+		}
+
+		if config.Keyfunc == nil {
+			return nil, fmt.Errorf("%w: issuer %q", ErrNoKeyfunc, issuer)
+		}
+
+		return config.Keyfunc.Keyfunc(token)
+	}
+
+	return func(ctx *echo.Context, auth string) (any, error) {
+		token, err := parser.ParseWithClaims(auth, config.NewClaimsFunc(ctx), keyFunc)
+		if err != nil {
+			return nil, &echojwt.TokenError{Token: token, Err: err}
+		}
+
+		return token, nil
+	}
+}
+
 func createSuccessHandler(logger *zerolog.Logger) func(*echo.Context) error {
 	return func(echoCtx *echo.Context) error {
 		token, ok := echoCtx.Get("user").(*jwt.Token)
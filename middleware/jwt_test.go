@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/MicahParks/jwkset"
+	"github.com/MicahParks/keyfunc/v3"
 	"github.com/andyle182810/gframework/middleware"
 	"github.com/andyle182810/gframework/testutil"
 	"github.com/golang-jwt/jwt/v5"
@@ -221,11 +222,15 @@ func TestJWTWithConfig_Skipper(t *testing.T) {
 		Skipper: func(ctx *echo.Context) bool {
 			return ctx.Request().URL.Path == "/health"
 		},
-		Logger:        nil,
-		Keyfunc:       mock,
-		NewClaimsFunc: nil,
-		ContextKey:    "",
-		TokenLookup:   "",
+		Logger:         nil,
+		Keyfunc:        mock,
+		IssuerKeyfuncs: nil,
+		Issuers:        nil,
+		Audiences:      nil,
+		ClockSkew:      0,
+		NewClaimsFunc:  nil,
+		ContextKey:     "",
+		TokenLookup:    "",
 	}
 
 	mw := middleware.JWTWithConfig(config)
@@ -265,12 +270,16 @@ func TestJWTWithConfig_CustomLogger(t *testing.T) {
 	}, "Bearer "+token)
 
 	config := middleware.JWTConfig{
-		Skipper:       nil,
-		Logger:        &logger,
-		Keyfunc:       mock,
-		NewClaimsFunc: nil,
-		ContextKey:    "",
-		TokenLookup:   "",
+		Skipper:        nil,
+		Logger:         &logger,
+		Keyfunc:        mock,
+		IssuerKeyfuncs: nil,
+		Issuers:        nil,
+		Audiences:      nil,
+		ClockSkew:      0,
+		NewClaimsFunc:  nil,
+		ContextKey:     "",
+		TokenLookup:    "",
 	}
 
 	mw := middleware.JWTWithConfig(config)
@@ -308,12 +317,16 @@ func TestJWTWithConfig_CustomContextKey(t *testing.T) {
 	}, "Bearer "+token)
 
 	config := middleware.JWTConfig{
-		Skipper:       nil,
-		Logger:        nil,
-		Keyfunc:       mock,
-		NewClaimsFunc: nil,
-		ContextKey:    "jwt-token",
-		TokenLookup:   "",
+		Skipper:        nil,
+		Logger:         nil,
+		Keyfunc:        mock,
+		IssuerKeyfuncs: nil,
+		Issuers:        nil,
+		Audiences:      nil,
+		ClockSkew:      0,
+		NewClaimsFunc:  nil,
+		ContextKey:     "jwt-token",
+		TokenLookup:    "",
 	}
 
 	mw := middleware.JWTWithConfig(config)
@@ -384,12 +397,16 @@ func TestJWTWithConfig_NilDefaults(t *testing.T) {
 	}, "Bearer "+token)
 
 	config := middleware.JWTConfig{
-		Skipper:       nil,
-		Logger:        nil,
-		Keyfunc:       mock,
-		NewClaimsFunc: nil,
-		ContextKey:    "",
-		TokenLookup:   "",
+		Skipper:        nil,
+		Logger:         nil,
+		Keyfunc:        mock,
+		IssuerKeyfuncs: nil,
+		Issuers:        nil,
+		Audiences:      nil,
+		ClockSkew:      0,
+		NewClaimsFunc:  nil,
+		ContextKey:     "",
+		TokenLookup:    "",
 	}
 
 	mw := middleware.JWTWithConfig(config)
@@ -418,12 +435,16 @@ func TestJWTWithConfig_DefaultSkipper(t *testing.T) {
 	})
 
 	config := middleware.JWTConfig{
-		Skipper:       echomiddleware.DefaultSkipper,
-		Logger:        nil,
-		Keyfunc:       mock,
-		NewClaimsFunc: nil,
-		ContextKey:    "",
-		TokenLookup:   "",
+		Skipper:        echomiddleware.DefaultSkipper,
+		Logger:         nil,
+		Keyfunc:        mock,
+		IssuerKeyfuncs: nil,
+		Issuers:        nil,
+		Audiences:      nil,
+		ClockSkew:      0,
+		NewClaimsFunc:  nil,
+		ContextKey:     "",
+		TokenLookup:    "",
 	}
 
 	mw := middleware.JWTWithConfig(config)
@@ -436,3 +457,90 @@ func TestJWTWithConfig_DefaultSkipper(t *testing.T) {
 	require.ErrorAs(t, err, &httpErr)
 	require.Equal(t, http.StatusUnauthorized, httpErr.Code)
 }
+
+func runJWTWithConfig(t *testing.T, config middleware.JWTConfig, token string) error {
+	t.Helper()
+
+	ctx, _, _ := testutil.SetupEchoContextWithAuth(t, &testutil.Options{
+		Method:        http.MethodGet,
+		Path:          "/test",
+		Body:          nil,
+		Headers:       nil,
+		QueryParams:   nil,
+		PathParams:    nil,
+		ContentType:   "",
+		SkipRequestID: true,
+	}, "Bearer "+token)
+
+	return middleware.JWTWithConfig(config)(echoSuccessHandler)(ctx)
+}
+
+func TestJWTWithConfig_MultipleIssuers(t *testing.T) {
+	t.Parallel()
+
+	internal := newMockKeyfunc(t)
+	partner := newMockKeyfunc(t)
+
+	config := middleware.DefaultJWTConfig()
+	config.IssuerKeyfuncs = map[string]keyfunc.Keyfunc{
+		"https://idp.internal": internal,
+		"https://idp.partner":  partner,
+	}
+	config.Audiences = []string{"orders-api"}
+
+	newClaims := func(issuer string, audience ...string) *middleware.ExtendedClaims {
+		return &middleware.ExtendedClaims{ //nolint:exhaustruct
+			//nolint:exhaustruct
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    issuer,
+				Audience:  audience,
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			},
+		}
+	}
+
+	require.NoError(t, runJWTWithConfig(t, config,
+		createTestToken(t, internal.key, newClaims("https://idp.internal", "orders-api"))))
+	require.NoError(t, runJWTWithConfig(t, config,
+		createTestToken(t, partner.key, newClaims("https://idp.partner", "account", "orders-api"))))
+
+	// Signed by the internal IdP key but claiming the partner issuer.
+	require.ErrorIs(t, runJWTWithConfig(t, config,
+		createTestToken(t, internal.key, newClaims("https://idp.partner", "orders-api"))), middleware.ErrInvalidToken)
+	require.ErrorIs(t, runJWTWithConfig(t, config,
+		createTestToken(t, internal.key, newClaims("https://idp.unknown", "orders-api"))), middleware.ErrInvalidToken)
+	require.ErrorIs(t, runJWTWithConfig(t, config,
+		createTestToken(t, internal.key, newClaims("https://idp.internal", "billing-api"))), middleware.ErrInvalidToken)
+}
+
+func TestJWTWithConfig_IssuersAndClockSkew(t *testing.T) {
+	t.Parallel()
+
+	mock := newMockKeyfunc(t)
+
+	config := middleware.DefaultJWTConfig()
+	config.Keyfunc = mock
+	config.Issuers = []string{"https://idp.internal"}
+	config.ClockSkew = time.Minute
+
+	token := createTestToken(t, mock.key, &middleware.ExtendedClaims{ //nolint:exhaustruct
+		//nolint:exhaustruct
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "https://idp.internal",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-30 * time.Second)),
+		},
+	})
+	require.NoError(t, runJWTWithConfig(t, config, token))
+
+	config.ClockSkew = 0
+	require.ErrorIs(t, runJWTWithConfig(t, config, token), middleware.ErrInvalidToken)
+
+	token = createTestToken(t, mock.key, &middleware.ExtendedClaims{ //nolint:exhaustruct
+		//nolint:exhaustruct
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "https://idp.other",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	})
+	require.ErrorIs(t, runJWTWithConfig(t, config, token), middleware.ErrInvalidToken)
+}
@@ -0,0 +1,196 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/labstack/echo/v5/middleware"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	defaultMaintenanceMessage    = "Service is under maintenance, please retry later"
+	defaultMaintenanceRetryAfter = 60 * time.Second
+	defaultMaintenanceCacheTTL   = time.Second
+)
+
+var ErrMaintenanceFlagSourceNil = errors.New("maintenance: flag source is nil")
+
+type MaintenanceFlagSource interface {
+	InMaintenance(ctx context.Context) (bool, error)
+}
+
+type MaintenanceFlagFunc func(ctx context.Context) (bool, error)
+
+func (f MaintenanceFlagFunc) InMaintenance(ctx context.Context) (bool, error) {
+	return f(ctx)
+}
+
+type MaintenanceResponse struct {
+	Message    string `json:"message"`
+	RetryAfter int    `json:"retryAfter"`
+}
+
+type MaintenanceConfig struct {
+	Skipper    middleware.Skipper
+	FlagSource MaintenanceFlagSource
+	// ExemptPaths keep serving during maintenance (health, metrics, admin), with the paths below them:
+	// "/admin" exempts "/admin" and "/admin/drain" but not "/administrator".
+	ExemptPaths []string
+	Message     string
+	RetryAfter  time.Duration
+}
+
+func DefaultMaintenanceConfig() MaintenanceConfig {
+	return MaintenanceConfig{
+		Skipper:     middleware.DefaultSkipper,
+		FlagSource:  nil,
+		ExemptPaths: []string{"/health", "/ready", "/metrics"},
+		Message:     defaultMaintenanceMessage,
+		RetryAfter:  defaultMaintenanceRetryAfter,
+	}
+}
+
+// Maintenance returns 503 with a MaintenanceResponse body while flagSource reports maintenance.
+// Flag lookup errors fail open so a flag store outage does not take the service down.
+func Maintenance(flagSource MaintenanceFlagSource, exemptPaths ...string) echo.MiddlewareFunc {
+	config := DefaultMaintenanceConfig()
+	config.FlagSource = flagSource
+	config.ExemptPaths = append(config.ExemptPaths, exemptPaths...)
+
+	return MaintenanceWithConfig(config)
+}
+
+func MaintenanceWithConfig(config MaintenanceConfig) echo.MiddlewareFunc {
+	if config.FlagSource == nil {
+		panic(ErrMaintenanceFlagSourceNil)
+	}
+
+	if config.Skipper == nil {
+		config.Skipper = middleware.DefaultSkipper
+	}
+
+	if config.Message == "" {
+		config.Message = defaultMaintenanceMessage
+	}
+
+	if config.RetryAfter <= 0 {
+		config.RetryAfter = defaultMaintenanceRetryAfter
+	}
+
+	retryAfter := int(config.RetryAfter.Seconds())
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx *echo.Context) error {
+			if config.Skipper(ctx) || isMaintenanceExempt(ctx.Request().URL.Path, config.ExemptPaths) {
+				return next(ctx)
+			}
+
+			enabled, err := config.FlagSource.InMaintenance(ctx.Request().Context())
+			if err != nil {
+				log.Warn().Str("source", "gframework").Err(err).Msg("Failed to read maintenance flag")
+
+				return next(ctx)
+			}
+
+			if !enabled {
+				return next(ctx)
+			}
+
+			ctx.Response().Header().Set(echo.HeaderRetryAfter, strconv.Itoa(retryAfter))
+
+			return ctx.JSON(http.StatusServiceUnavailable, MaintenanceResponse{
+				Message:    config.Message,
+				RetryAfter: retryAfter,
+			})
+		}
+	}
+}
+
+func isMaintenanceExempt(path string, exemptPaths []string) bool {
+	for _, exempt := range exemptPaths {
+		if exempt == "" {
+			continue
+		}
+
+		exempt = strings.TrimSuffix(exempt, "/")
+		if path == exempt || strings.HasPrefix(path, exempt+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+type valkeyMaintenanceFlag struct {
+	client   redis.UniversalClient
+	key      string
+	cacheTTL time.Duration
+	group    singleflight.Group
+	snapshot atomic.Pointer[maintenanceSnapshot]
+}
+
+// maintenanceSnapshot is the result of a flag lookup, failed lookups included.
+type maintenanceSnapshot struct {
+	enabled   bool
+	err       error
+	checkedAt time.Time
+}
+
+// ValkeyMaintenanceFlag reports maintenance while key exists. Results, failed lookups included, are
+// cached for cacheTTL (one second when zero) so the flag is not read on every request, and concurrent
+// requests share one lookup, so an unreachable Valkey costs one dial timeout per cacheTTL.
+func ValkeyMaintenanceFlag(client redis.UniversalClient, key string, cacheTTL time.Duration) MaintenanceFlagSource {
+	if cacheTTL <= 0 {
+		cacheTTL = defaultMaintenanceCacheTTL
+	}
+
+	return &valkeyMaintenanceFlag{
+		client:   client,
+		key:      key,
+		cacheTTL: cacheTTL,
+		group:    singleflight.Group{},
+		snapshot: atomic.Pointer[maintenanceSnapshot]{},
+	}
+}
+
+func (f *valkeyMaintenanceFlag) InMaintenance(ctx context.Context) (bool, error) {
+	if snapshot := f.fresh(); snapshot != nil {
+		return snapshot.enabled, snapshot.err
+	}
+
+	result, _, _ := f.group.Do(f.key, func() (any, error) {
+		if snapshot := f.fresh(); snapshot != nil {
+			return snapshot, nil
+		}
+
+		// The lookup is shared, so a request that goes away must not fail it for the others.
+		count, err := f.client.Exists(context.WithoutCancel(ctx), f.key).Result()
+
+		snapshot := &maintenanceSnapshot{enabled: err == nil && count > 0, err: err, checkedAt: time.Now()}
+		f.snapshot.Store(snapshot)
+
+		return snapshot, nil
+	})
+
+	snapshot, _ := result.(*maintenanceSnapshot)
+
+	return snapshot.enabled, snapshot.err
+}
+
+func (f *valkeyMaintenanceFlag) fresh() *maintenanceSnapshot {
+	snapshot := f.snapshot.Load()
+	if snapshot == nil || time.Since(snapshot.checkedAt) >= f.cacheTTL {
+		return nil
+	}
+
+	return snapshot
+}
@@ -0,0 +1,161 @@
+package middleware_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/middleware"
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+var errFlagStoreDown = errors.New("flag store down")
+
+func TestMaintenanceMiddleware(t *testing.T) {
+	t.Parallel()
+
+	enabled := middleware.MaintenanceFlagFunc(func(_ context.Context) (bool, error) {
+		return true, nil
+	})
+	disabled := middleware.MaintenanceFlagFunc(func(_ context.Context) (bool, error) {
+		return false, nil
+	})
+	failing := middleware.MaintenanceFlagFunc(func(_ context.Context) (bool, error) {
+		return false, errFlagStoreDown
+	})
+
+	tests := []struct {
+		name           string
+		path           string
+		flag           middleware.MaintenanceFlagSource
+		expectedStatus int
+	}{
+		{name: "maintenance off", path: "/v1/users", flag: disabled, expectedStatus: http.StatusOK},
+		{name: "maintenance on", path: "/v1/users", flag: enabled, expectedStatus: http.StatusServiceUnavailable},
+		{name: "health exempt", path: "/health", flag: enabled, expectedStatus: http.StatusOK},
+		{name: "below exempt path", path: "/admin/drain/now", flag: enabled, expectedStatus: http.StatusOK},
+		{
+			name:           "exempt prefix of a segment",
+			path:           "/healthcare",
+			flag:           enabled,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{name: "admin allowlisted", path: "/admin/drain", flag: enabled, expectedStatus: http.StatusOK},
+		{name: "flag error fails open", path: "/v1/users", flag: failing, expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, rec, _ := testutil.SetupEchoContext(t, &testutil.Options{
+				Method:        http.MethodGet,
+				Path:          tt.path,
+				Body:          nil,
+				Headers:       nil,
+				QueryParams:   nil,
+				PathParams:    nil,
+				ContentType:   "",
+				SkipRequestID: false,
+			})
+
+			err := middleware.Maintenance(tt.flag, "/admin")(echoSuccessHandler)(ctx)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedStatus, rec.Code)
+
+			if tt.expectedStatus == http.StatusServiceUnavailable {
+				var body middleware.MaintenanceResponse
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+				require.NotEmpty(t, body.Message)
+				require.Equal(t, 60, body.RetryAfter)
+				require.Equal(t, "60", rec.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
+
+func TestValkeyMaintenanceFlag(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reports the key and caches the result", func(t *testing.T) {
+		t.Parallel()
+
+		client, server := testutil.NewFakeValkeyWithServer(t)
+		flag := middleware.ValkeyMaintenanceFlag(client, "maintenance", time.Hour)
+
+		enabled, err := flag.InMaintenance(t.Context())
+		require.NoError(t, err)
+		require.False(t, enabled)
+
+		require.NoError(t, server.Set("maintenance", "1"))
+
+		enabled, err = flag.InMaintenance(t.Context())
+		require.NoError(t, err)
+		require.False(t, enabled, "the cached result is served until cacheTTL passes")
+
+		flag = middleware.ValkeyMaintenanceFlag(client, "maintenance", time.Hour)
+
+		enabled, err = flag.InMaintenance(t.Context())
+		require.NoError(t, err)
+		require.True(t, enabled)
+	})
+
+	t.Run("caches failed lookups", func(t *testing.T) {
+		t.Parallel()
+
+		client, server := testutil.NewFakeValkeyWithServer(t)
+		flag := middleware.ValkeyMaintenanceFlag(client, "maintenance", time.Hour)
+
+		server.SetError("down")
+
+		var wg sync.WaitGroup
+
+		errs := make(chan error, 20)
+
+		for range cap(errs) {
+			wg.Go(func() {
+				_, err := flag.InMaintenance(t.Context())
+				errs <- err
+			})
+		}
+
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			require.Error(t, err)
+		}
+
+		commands := server.CommandCount()
+
+		server.SetError("")
+
+		_, err := flag.InMaintenance(t.Context())
+		require.Error(t, err, "the failure is served until cacheTTL passes")
+		require.Equal(t, commands, server.CommandCount())
+	})
+
+	t.Run("reads the key again after cacheTTL", func(t *testing.T) {
+		t.Parallel()
+
+		client, server := testutil.NewFakeValkeyWithServer(t)
+		flag := middleware.ValkeyMaintenanceFlag(client, "maintenance", 10*time.Millisecond)
+
+		enabled, err := flag.InMaintenance(t.Context())
+		require.NoError(t, err)
+		require.False(t, enabled)
+
+		require.NoError(t, server.Set("maintenance", "1"))
+
+		require.Eventually(t, func() bool {
+			enabled, err := flag.InMaintenance(t.Context())
+
+			return err == nil && enabled
+		}, time.Second, 5*time.Millisecond)
+	})
+}
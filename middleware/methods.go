@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+)
+
+// AutoMethods answers HEAD for routes that only register GET by running the GET handler and discarding
+// the body, and advertises HEAD in the Allow header of OPTIONS and 405 responses for those routes.
+// Register it with e.Pre so it runs before routing.
+func AutoMethods(e *echo.Echo) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx *echo.Context) error {
+			if response, err := echo.UnwrapResponse(ctx.Response()); err == nil {
+				response.Before(func() {
+					header := response.Header()
+					if allow := header.Get(echo.HeaderAllow); allow != "" {
+						header.Set(echo.HeaderAllow, allowWithHead(allow))
+					}
+				})
+			}
+
+			request := ctx.Request()
+			if request.Method != http.MethodHead || !routeAllowsGetOnly(e, request) {
+				return next(ctx)
+			}
+
+			request.Method = http.MethodGet
+			ctx.SetResponse(&headResponseWriter{ResponseWriter: ctx.Response()})
+
+			defer func() {
+				request.Method = http.MethodHead
+			}()
+
+			return next(ctx)
+		}
+	}
+}
+
+// routeAllowsGetOnly reports whether the request path has no HEAD route but does have a GET route.
+// The router only sets the allow value on the context when the method itself did not match.
+func routeAllowsGetOnly(e *echo.Echo, request *http.Request) bool {
+	probe := e.NewContext(request, nil)
+	e.Router().Route(probe)
+
+	allow, ok := probe.Get(echo.ContextKeyHeaderAllow).(string)
+	if !ok || allow == "" {
+		return false
+	}
+
+	return hasMethod(allow, http.MethodGet)
+}
+
+func allowWithHead(allow string) string {
+	if !hasMethod(allow, http.MethodGet) || hasMethod(allow, http.MethodHead) {
+		return allow
+	}
+
+	return allow + ", " + http.MethodHead
+}
+
+func hasMethod(allow, method string) bool {
+	for part := range strings.SplitSeq(allow, ",") {
+		if strings.TrimSpace(part) == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+type headResponseWriter struct {
+	http.ResponseWriter
+}
+
+func (w *headResponseWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (w *headResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
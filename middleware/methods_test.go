@@ -0,0 +1,104 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andyle182810/gframework/middleware"
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutoMethods(t *testing.T) {
+	t.Parallel()
+
+	e := echo.New()
+	e.Pre(middleware.AutoMethods(e))
+
+	e.GET("/users", func(c *echo.Context) error {
+		return c.String(http.StatusOK, "users")
+	})
+	e.POST("/users", echoSuccessHandler)
+	e.HEAD("/custom", func(c *echo.Context) error {
+		return c.NoContent(http.StatusAccepted)
+	})
+	e.GET("/custom", echoSuccessHandler)
+	e.POST("/orders", echoSuccessHandler)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+		expectedAllow  string
+		expectedBody   string
+	}{
+		{
+			name:           "head served by get handler",
+			method:         http.MethodHead,
+			path:           "/users",
+			expectedStatus: http.StatusOK,
+			expectedAllow:  "",
+			expectedBody:   "",
+		},
+		{
+			name:           "explicit head route wins",
+			method:         http.MethodHead,
+			path:           "/custom",
+			expectedStatus: http.StatusAccepted,
+			expectedAllow:  "",
+			expectedBody:   "",
+		},
+		{
+			name:           "options lists head for get routes",
+			method:         http.MethodOptions,
+			path:           "/users",
+			expectedStatus: http.StatusNoContent,
+			expectedAllow:  "OPTIONS, GET, POST, HEAD",
+			expectedBody:   "",
+		},
+		{
+			name:           "method not allowed lists head",
+			method:         http.MethodDelete,
+			path:           "/users",
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedAllow:  "OPTIONS, GET, POST, HEAD",
+			expectedBody:   "",
+		},
+		{
+			name:           "head without get route is not allowed",
+			method:         http.MethodHead,
+			path:           "/orders",
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedAllow:  "OPTIONS, POST",
+			expectedBody:   "",
+		},
+		{
+			name:           "get unaffected",
+			method:         http.MethodGet,
+			path:           "/users",
+			expectedStatus: http.StatusOK,
+			expectedAllow:  "",
+			expectedBody:   "users",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			rec := httptest.NewRecorder()
+
+			e.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.expectedStatus, rec.Code)
+			require.Equal(t, tt.expectedAllow, rec.Header().Get(echo.HeaderAllow))
+
+			if tt.expectedStatus < http.StatusBadRequest {
+				require.Equal(t, tt.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
@@ -6,10 +6,12 @@
 //
 // Key components:
 //
-//   - JWT validation via middleware.JWT() with support for Keycloak-style extended claims
+//   - JWT validation via middleware.JWT() with support for Keycloak-style extended claims, with keys from
+//     middleware.NewJWKSKeyfunc()
 //   - Request ID tracking via middleware.RequestID()
 //   - Request logging via middleware.RequestLogger()
 //   - Centralized error handling via middleware.ErrorHandler()
+//   - Webhook HMAC signature verification via middleware.VerifyWebhook()
 //   - Context getter functions: GetRequestID(), GetToken(), GetExtendedClaimsFromContext(), etc.
 //
 // Example:
@@ -40,6 +42,7 @@ const (
 	ContextKeyToken         string = "token"
 	ContextKeyClaims        string = "claims"
 	ContextKeyHandler       string = "handler"
+	ContextKeyFields        string = "fields"
 )
 
 const (
@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"github.com/andyle182810/gframework/httpclient"
+	"github.com/labstack/echo/v5"
+)
+
+// PropagateHeaders stores the named inbound headers in the request context, from where httpclient
+// clients created with WithHeaderPropagation forward them. Without names, the httpclient defaults
+// (tenant ID, locale, baggage and feature-flag overrides) are captured.
+func PropagateHeaders(headers ...string) echo.MiddlewareFunc {
+	if len(headers) == 0 {
+		headers = httpclient.DefaultPropagatedHeaders()
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx *echo.Context) error {
+			ctx.SetRequest(httpclient.CaptureHeaders(ctx.Request(), headers...))
+
+			return next(ctx)
+		}
+	}
+}
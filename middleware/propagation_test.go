@@ -0,0 +1,39 @@
+package middleware_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/andyle182810/gframework/httpclient"
+	"github.com/andyle182810/gframework/middleware"
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPropagateHeadersMiddleware(t *testing.T) {
+	t.Parallel()
+
+	ctx, _, _ := testutil.SetupEchoContext(t, &testutil.Options{
+		Method:        http.MethodGet,
+		Path:          "/orders",
+		Body:          nil,
+		Headers:       map[string]string{httpclient.HeaderXTenantID: "tenant-1", "X-Other": "ignored"},
+		QueryParams:   nil,
+		PathParams:    nil,
+		ContentType:   "",
+		SkipRequestID: false,
+	})
+
+	var captured http.Header
+
+	handler := middleware.PropagateHeaders()(func(c *echo.Context) error {
+		captured = httpclient.PropagatedHeaders(c.Request().Context())
+
+		return c.NoContent(http.StatusOK)
+	})
+
+	require.NoError(t, handler(ctx))
+	require.Equal(t, "tenant-1", captured.Get(httpclient.HeaderXTenantID))
+	require.Empty(t, captured.Get("X-Other"))
+}
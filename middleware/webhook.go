@@ -0,0 +1,354 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1" //nolint:gosec
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v5"
+	"github.com/labstack/echo/v5/middleware"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+type WebhookAlgorithm string
+
+const (
+	WebhookAlgorithmSHA1   WebhookAlgorithm = "sha1"
+	WebhookAlgorithmSHA256 WebhookAlgorithm = "sha256"
+	WebhookAlgorithmSHA512 WebhookAlgorithm = "sha512"
+)
+
+const (
+	defaultWebhookTolerance   = 5 * time.Minute
+	defaultWebhookMaxBodySize = 1 << 20
+	defaultWebhookReplayKey   = "webhook:replay:"
+	webhookTimestampElement   = "t"
+	webhookStripeSignature    = "v1"
+)
+
+var (
+	ErrWebhookSecretProviderNil = errors.New("webhook: secret provider is nil")
+	ErrWebhookAlgorithm         = errors.New("webhook: unsupported algorithm")
+	ErrWebhookSignatureMissing  = errors.New("webhook: signature header is missing")
+	ErrWebhookSignatureInvalid  = errors.New("webhook: signature is invalid")
+	ErrWebhookTimestampMissing  = errors.New("webhook: timestamp is missing")
+	ErrWebhookTimestampInvalid  = errors.New("webhook: timestamp is invalid")
+	ErrWebhookTimestampExpired  = errors.New("webhook: timestamp is outside the tolerance window")
+	ErrWebhookReplayed          = errors.New("webhook: signature has already been used")
+	ErrWebhookBodyTooLarge      = errors.New("webhook: body exceeds maximum size")
+)
+
+// WebhookSecretProvider returns the signing secrets accepted for the current request. Returning
+// more than one secret allows rotation without rejecting in-flight deliveries.
+type WebhookSecretProvider func(c *echo.Context) ([][]byte, error)
+
+type WebhookConfig struct {
+	Skipper          middleware.Skipper
+	SecretProvider   WebhookSecretProvider
+	Header           string
+	Algorithm        WebhookAlgorithm
+	TimestampHeader  string
+	RequireTimestamp bool
+	Tolerance        time.Duration
+	MaxBodySize      int64
+	// ReplayStore accepts each signature once within the tolerance window. Setting it requires a
+	// timestamp on every delivery, as RequireTimestamp does.
+	ReplayStore     redis.UniversalClient
+	ReplayKeyPrefix string
+	Now             func() time.Time
+}
+
+func DefaultWebhookConfig() WebhookConfig {
+	return WebhookConfig{
+		Skipper:          middleware.DefaultSkipper,
+		SecretProvider:   nil,
+		Header:           HeaderXSignature,
+		Algorithm:        WebhookAlgorithmSHA256,
+		TimestampHeader:  HeaderXTimestamp,
+		RequireTimestamp: false,
+		Tolerance:        defaultWebhookTolerance,
+		MaxBodySize:      defaultWebhookMaxBodySize,
+		ReplayStore:      nil,
+		ReplayKeyPrefix:  defaultWebhookReplayKey,
+		Now:              time.Now,
+	}
+}
+
+// StaticWebhookSecret returns a provider that always yields the given secrets.
+func StaticWebhookSecret(secrets ...string) WebhookSecretProvider {
+	keys := make([][]byte, 0, len(secrets))
+	for _, secret := range secrets {
+		keys = append(keys, []byte(secret))
+	}
+
+	return func(_ *echo.Context) ([][]byte, error) {
+		return keys, nil
+	}
+}
+
+// VerifyWebhook validates HMAC signatures on inbound webhooks. The header may carry a GitHub style
+// value ("sha256=<hex>"), a Stripe style value ("t=<unix>,v1=<hex>") or a bare hex digest. When a
+// timestamp is present, the signed payload is "<timestamp>.<body>" and must fall within the tolerance.
+// With a replayStore, deliveries must carry a timestamp and each signature is accepted once within
+// the tolerance window; a nil replayStore skips the replay check.
+func VerifyWebhook(
+	secretProvider WebhookSecretProvider,
+	header string,
+	algorithm WebhookAlgorithm,
+	replayStore redis.UniversalClient,
+) echo.MiddlewareFunc {
+	config := DefaultWebhookConfig()
+	config.SecretProvider = secretProvider
+	config.Header = header
+	config.Algorithm = algorithm
+	config.ReplayStore = replayStore
+
+	return VerifyWebhookWithConfig(config)
+}
+
+func VerifyWebhookWithConfig(config WebhookConfig) echo.MiddlewareFunc {
+	if config.SecretProvider == nil {
+		panic(ErrWebhookSecretProviderNil)
+	}
+
+	newHash, err := webhookHash(config.Algorithm)
+	if err != nil {
+		panic(err)
+	}
+
+	applyWebhookDefaults(&config)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx *echo.Context) error {
+			if config.Skipper(ctx) {
+				return next(ctx)
+			}
+
+			if err := verifyWebhookRequest(ctx, &config, newHash); err != nil {
+				return err
+			}
+
+			return next(ctx)
+		}
+	}
+}
+
+func applyWebhookDefaults(config *WebhookConfig) {
+	if config.Skipper == nil {
+		config.Skipper = middleware.DefaultSkipper
+	}
+
+	if config.Header == "" {
+		config.Header = HeaderXSignature
+	}
+
+	if config.Tolerance <= 0 {
+		config.Tolerance = defaultWebhookTolerance
+	}
+
+	if config.MaxBodySize <= 0 {
+		config.MaxBodySize = defaultWebhookMaxBodySize
+	}
+
+	if config.ReplayKeyPrefix == "" {
+		config.ReplayKeyPrefix = defaultWebhookReplayKey
+	}
+
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+}
+
+func verifyWebhookRequest(ctx *echo.Context, config *WebhookConfig, newHash func() hash.Hash) error {
+	signatures, timestamp := parseWebhookSignature(ctx.Request().Header.Get(config.Header), config.Algorithm)
+	if len(signatures) == 0 {
+		return echo.NewHTTPError(http.StatusUnauthorized, ErrWebhookSignatureMissing.Error())
+	}
+
+	if timestamp == "" && config.TimestampHeader != "" {
+		timestamp = ctx.Request().Header.Get(config.TimestampHeader)
+	}
+
+	// Without a timestamp the replay window is unbounded, so a replay store requires one.
+	if timestamp == "" && (config.RequireTimestamp || config.ReplayStore != nil) {
+		return echo.NewHTTPError(http.StatusUnauthorized, ErrWebhookTimestampMissing.Error())
+	}
+
+	if timestamp != "" {
+		if err := checkWebhookTimestamp(timestamp, config.Now(), config.Tolerance); err != nil {
+			return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
+		}
+	}
+
+	body, err := readWebhookBody(ctx, config.MaxBodySize)
+	if errors.Is(err, ErrWebhookBodyTooLarge) {
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, err.Error())
+	}
+
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "webhook: failed to read body")
+	}
+
+	secrets, err := config.SecretProvider(ctx)
+	if err != nil {
+		log.Error().Str("source", "gframework").Err(err).Msg("Failed to look up webhook secrets")
+
+		return echo.NewHTTPError(http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+	}
+
+	payload := body
+	if timestamp != "" {
+		payload = append([]byte(timestamp+"."), body...)
+	}
+
+	matched, ok := matchWebhookSignature(payload, signatures, secrets, newHash)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, ErrWebhookSignatureInvalid.Error())
+	}
+
+	if config.ReplayStore != nil && timestamp != "" {
+		key := config.ReplayKeyPrefix + matched
+
+		stored, err := config.ReplayStore.SetNX(ctx.Request().Context(), key, timestamp, 2*config.Tolerance).Result()
+		if err != nil {
+			log.Error().Str("source", "gframework").Err(err).Msg("Failed to check webhook replay")
+
+			return echo.NewHTTPError(http.StatusServiceUnavailable, http.StatusText(http.StatusServiceUnavailable))
+		}
+
+		if !stored {
+			return echo.NewHTTPError(http.StatusConflict, ErrWebhookReplayed.Error())
+		}
+	}
+
+	return nil
+}
+
+func webhookHash(algorithm WebhookAlgorithm) (func() hash.Hash, error) {
+	switch algorithm {
+	case WebhookAlgorithmSHA1:
+		return sha1.New, nil
+	case WebhookAlgorithmSHA256:
+		return sha256.New, nil
+	case WebhookAlgorithmSHA512:
+		return sha512.New, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrWebhookAlgorithm, algorithm)
+	}
+}
+
+func parseWebhookSignature(value string, algorithm WebhookAlgorithm) ([]string, string) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, ""
+	}
+
+	var (
+		signatures []string
+		timestamp  string
+	)
+
+	for element := range strings.SplitSeq(value, ",") {
+		key, val, found := strings.Cut(strings.TrimSpace(element), "=")
+		if !found {
+			signatures = append(signatures, key)
+
+			continue
+		}
+
+		switch key {
+		case webhookTimestampElement:
+			timestamp = val
+		case webhookStripeSignature, string(algorithm):
+			signatures = append(signatures, val)
+		}
+	}
+
+	return signatures, timestamp
+}
+
+func checkWebhookTimestamp(timestamp string, now time.Time, tolerance time.Duration) error {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrWebhookTimestampInvalid, timestamp)
+	}
+
+	age := now.Sub(time.Unix(seconds, 0))
+	if age > tolerance || age < -tolerance {
+		return ErrWebhookTimestampExpired
+	}
+
+	return nil
+}
+
+func readWebhookBody(ctx *echo.Context, maxSize int64) ([]byte, error) {
+	request := ctx.Request()
+	if request.Body == nil {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(request.Body, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("webhook: failed to read body: %w", err)
+	}
+
+	if int64(len(body)) > maxSize {
+		return nil, ErrWebhookBodyTooLarge
+	}
+
+	request.Body = io.NopCloser(bytes.NewReader(body))
+
+	return body, nil
+}
+
+func matchWebhookSignature(payload []byte, signatures []string, secrets [][]byte, newHash func() hash.Hash) (string, bool) {
+	for _, secret := range secrets {
+		mac := hmac.New(newHash, secret)
+		mac.Write(payload)
+		expected := mac.Sum(nil)
+
+		for _, signature := range signatures {
+			decoded, err := hex.DecodeString(signature)
+			if err != nil {
+				continue
+			}
+
+			if hmac.Equal(decoded, expected) {
+				return signature, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// SignWebhook computes the hex HMAC of payload, prefixed with the timestamp when one is given, in the
+// form expected by VerifyWebhook. It is primarily useful for tests and outbound webhook senders.
+func SignWebhook(secret []byte, algorithm WebhookAlgorithm, timestamp string, payload []byte) (string, error) {
+	newHash, err := webhookHash(algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	mac := hmac.New(newHash, secret)
+
+	if timestamp != "" {
+		mac.Write([]byte(timestamp + "."))
+	}
+
+	mac.Write(payload)
+
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
@@ -0,0 +1,256 @@
+package middleware_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/andyle182810/gframework/middleware"
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/require"
+)
+
+const webhookSecret = "whsec_test"
+
+var errWebhookInternal = errors.New("vault: permission denied for secret/webhooks")
+
+func signWebhook(t *testing.T, timestamp string, body []byte) string {
+	t.Helper()
+
+	signature, err := middleware.SignWebhook([]byte(webhookSecret), middleware.WebhookAlgorithmSHA256, timestamp, body)
+	require.NoError(t, err)
+
+	return signature
+}
+
+func TestVerifyWebhook(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{"event":"payment.succeeded"}`)
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	stale := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+
+	tests := []struct {
+		name           string
+		signature      string
+		expectedStatus int
+	}{
+		{
+			name:           "github style signature",
+			signature:      "sha256=" + signWebhook(t, "", body),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "stripe style signature",
+			signature:      "t=" + now + ",v1=" + signWebhook(t, now, body),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "bare hex signature",
+			signature:      signWebhook(t, "", body),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing signature",
+			signature:      "",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "wrong signature",
+			signature:      "sha256=" + signWebhook(t, "", []byte("tampered")),
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "expired timestamp",
+			signature:      "t=" + stale + ",v1=" + signWebhook(t, stale, body),
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, rec, _ := testutil.SetupEchoContext(t, &testutil.Options{
+				Method:        http.MethodPost,
+				Path:          "/webhooks",
+				Body:          body,
+				Headers:       map[string]string{"Stripe-Signature": tt.signature},
+				QueryParams:   nil,
+				PathParams:    nil,
+				ContentType:   "",
+				SkipRequestID: false,
+			})
+
+			var received []byte
+
+			handler := middleware.VerifyWebhook(
+				middleware.StaticWebhookSecret("old_secret", webhookSecret),
+				"Stripe-Signature",
+				middleware.WebhookAlgorithmSHA256,
+				nil,
+			)(func(c *echo.Context) error {
+				data, err := io.ReadAll(c.Request().Body)
+				if err != nil {
+					return err
+				}
+
+				received = data
+
+				return c.NoContent(http.StatusOK)
+			})
+
+			err := handler(ctx)
+			if tt.expectedStatus != http.StatusOK {
+				var httpErr *echo.HTTPError
+				require.ErrorAs(t, err, &httpErr)
+				require.Equal(t, tt.expectedStatus, httpErr.Code)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, http.StatusOK, rec.Code)
+			require.Equal(t, body, received)
+		})
+	}
+}
+
+func TestVerifyWebhook_RequireTimestamp(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{}`)
+
+	ctx, _, _ := testutil.SetupEchoContext(t, &testutil.Options{
+		Method:        http.MethodPost,
+		Path:          "/webhooks",
+		Body:          body,
+		Headers:       map[string]string{middleware.HeaderXSignature: signWebhook(t, "", body)},
+		QueryParams:   nil,
+		PathParams:    nil,
+		ContentType:   "",
+		SkipRequestID: false,
+	})
+
+	config := middleware.DefaultWebhookConfig()
+	config.SecretProvider = middleware.StaticWebhookSecret(webhookSecret)
+	config.RequireTimestamp = true
+
+	err := middleware.VerifyWebhookWithConfig(config)(echoSuccessHandler)(ctx)
+
+	var httpErr *echo.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusUnauthorized, httpErr.Code)
+}
+
+func TestVerifyWebhook_ReplayStore(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{"event":"payment.succeeded"}`)
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+
+	client, server := testutil.NewFakeValkeyWithServer(t)
+	verify := middleware.VerifyWebhook(
+		middleware.StaticWebhookSecret(webhookSecret),
+		middleware.HeaderXSignature,
+		middleware.WebhookAlgorithmSHA256,
+		client,
+	)
+
+	serve := func(signature string) error {
+		ctx, _, _ := testutil.SetupEchoContext(t, &testutil.Options{
+			Method:        http.MethodPost,
+			Path:          "/webhooks",
+			Body:          body,
+			Headers:       map[string]string{middleware.HeaderXSignature: signature},
+			QueryParams:   nil,
+			PathParams:    nil,
+			ContentType:   "",
+			SkipRequestID: false,
+		})
+
+		return verify(echoSuccessHandler)(ctx)
+	}
+
+	requireStatus := func(err error, status int) {
+		var httpErr *echo.HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		require.Equal(t, status, httpErr.Code)
+	}
+
+	signature := "t=" + now + ",v1=" + signWebhook(t, now, body)
+
+	require.NoError(t, serve(signature))
+	requireStatus(serve(signature), http.StatusConflict)
+
+	keys := server.Keys()
+
+	requireStatus(serve("sha256="+signWebhook(t, "", body)), http.StatusUnauthorized)
+	require.Equal(t, keys, server.Keys(), "no replay key is written without a timestamp")
+}
+
+func TestVerifyWebhook_HidesInternalErrors(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{"event":"payment.succeeded"}`)
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	signature := "t=" + now + ",v1=" + signWebhook(t, now, body)
+
+	client, server := testutil.NewFakeValkeyWithServer(t)
+
+	serve := func(secrets middleware.WebhookSecretProvider, requestBody io.Reader) *echo.HTTPError {
+		ctx, _, _ := testutil.SetupEchoContext(t, &testutil.Options{
+			Method:        http.MethodPost,
+			Path:          "/webhooks",
+			Body:          body,
+			Headers:       map[string]string{middleware.HeaderXSignature: signature},
+			QueryParams:   nil,
+			PathParams:    nil,
+			ContentType:   "",
+			SkipRequestID: false,
+		})
+
+		if requestBody != nil {
+			ctx.Request().Body = io.NopCloser(requestBody)
+		}
+
+		err := middleware.VerifyWebhook(secrets, middleware.HeaderXSignature, middleware.WebhookAlgorithmSHA256,
+			client)(echoSuccessHandler)(ctx)
+
+		var httpErr *echo.HTTPError
+		require.ErrorAs(t, err, &httpErr)
+
+		return httpErr
+	}
+
+	httpErr := serve(middleware.StaticWebhookSecret(webhookSecret), iotest.ErrReader(errWebhookInternal))
+	require.Equal(t, http.StatusBadRequest, httpErr.Code)
+	require.NotContains(t, httpErr.Message, errWebhookInternal.Error())
+
+	httpErr = serve(func(*echo.Context) ([][]byte, error) { return nil, errWebhookInternal }, nil)
+	require.Equal(t, http.StatusInternalServerError, httpErr.Code)
+	require.NotContains(t, httpErr.Message, errWebhookInternal.Error())
+
+	server.Close()
+
+	httpErr = serve(middleware.StaticWebhookSecret(webhookSecret), nil)
+	require.Equal(t, http.StatusServiceUnavailable, httpErr.Code)
+	require.Equal(t, http.StatusText(http.StatusServiceUnavailable), httpErr.Message)
+}
+
+func TestVerifyWebhook_InvalidConfigPanics(t *testing.T) {
+	t.Parallel()
+
+	require.Panics(t, func() {
+		middleware.VerifyWebhook(nil, middleware.HeaderXSignature, middleware.WebhookAlgorithmSHA256, nil)
+	})
+
+	require.Panics(t, func() {
+		middleware.VerifyWebhook(middleware.StaticWebhookSecret(webhookSecret), middleware.HeaderXSignature, "md5", nil)
+	})
+}
@@ -0,0 +1,83 @@
+//nolint:exhaustruct
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
+)
+
+const (
+	defaultHealthCheckTimeout = 5 * time.Second
+)
+
+var ErrNoActiveConnections = errors.New("mongo: no active connections in pool")
+
+type HealthCheckOptions struct {
+	Timeout            time.Duration
+	RequirePrimary     bool
+	RequireActiveConns bool
+}
+
+type HealthCheckOption func(*HealthCheckOptions)
+
+func WithHealthCheckTimeout(timeout time.Duration) HealthCheckOption {
+	return func(opts *HealthCheckOptions) {
+		opts.Timeout = timeout
+	}
+}
+
+// WithRequirePrimary pings the primary instead of any member allowed by the read preference of the
+// client, for services that cannot serve requests without writes.
+func WithRequirePrimary() HealthCheckOption {
+	return func(opts *HealthCheckOptions) {
+		opts.RequirePrimary = true
+	}
+}
+
+func WithRequireActiveConns() HealthCheckOption {
+	return func(opts *HealthCheckOptions) {
+		opts.RequireActiveConns = true
+	}
+}
+
+func (m *Mongo) HealthCheck(ctx context.Context, opts ...HealthCheckOption) error {
+	if m.Client == nil {
+		return ErrClientNil
+	}
+
+	options := &HealthCheckOptions{
+		Timeout:            defaultHealthCheckTimeout,
+		RequirePrimary:     false,
+		RequireActiveConns: false,
+	}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	healthCtx, cancel := context.WithTimeout(ctx, options.Timeout)
+	defer cancel()
+
+	var readPref *readpref.ReadPref
+	if options.RequirePrimary {
+		readPref = readpref.Primary()
+	}
+
+	if err := m.Client.Ping(healthCtx, readPref); err != nil {
+		return fmt.Errorf("mongo health check ping failed: %w", err)
+	}
+
+	if options.RequireActiveConns && m.PoolStats().TotalConns == 0 {
+		return ErrNoActiveConnections
+	}
+
+	return nil
+}
+
+func (m *Mongo) IsHealthy(ctx context.Context) bool {
+	return m.HealthCheck(ctx) == nil
+}
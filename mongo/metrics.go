@@ -0,0 +1,158 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/andyle182810/gframework/metricserver"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.mongodb.org/mongo-driver/v2/event"
+)
+
+// Metrics receives the outcome of every command sent to the server, e.g. "find" or "insert".
+type Metrics interface {
+	CommandFinished(command string, duration time.Duration, err error)
+}
+
+func commandMonitor(metrics Metrics) *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started: nil,
+		Succeeded: func(_ context.Context, evt *event.CommandSucceededEvent) {
+			metrics.CommandFinished(evt.CommandName, evt.Duration, nil)
+		},
+		Failed: func(_ context.Context, evt *event.CommandFailedEvent) {
+			metrics.CommandFinished(evt.CommandName, evt.Duration, evt.Failure)
+		},
+	}
+}
+
+// PrometheusMetrics implements Metrics with Prometheus collectors.
+type PrometheusMetrics struct {
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+}
+
+// NewPrometheusMetrics registers the command metrics with reg, e.g. the registerer of metricserver.
+// Set it as Config.Metrics.
+func NewPrometheusMetrics(reg prometheus.Registerer) (*PrometheusMetrics, error) {
+	m := &PrometheusMetrics{
+		duration: metricserver.NewHistogramVec("mongo", "command_duration_seconds",
+			"Duration of commands sent to the server.", metricserver.LatencyBuckets(), "command"),
+		errors: metricserver.NewCounterVec("mongo", "command_errors_total",
+			"Number of commands that failed.", "command"),
+	}
+
+	if err := metricserver.Register(reg, m.duration, m.errors); err != nil {
+		return nil, fmt.Errorf("mongo: failed to register metrics: %w", err)
+	}
+
+	return m, nil
+}
+
+func (m *PrometheusMetrics) CommandFinished(command string, duration time.Duration, err error) {
+	m.duration.WithLabelValues(command).Observe(duration.Seconds())
+
+	if err != nil {
+		m.errors.WithLabelValues(command).Inc()
+	}
+}
+
+type PoolStats struct {
+	TotalConns       int64
+	InUseConns       int64
+	CheckOutFailures int64
+	PoolClears       int64
+}
+
+// poolStats counts connection pool events, as the driver does not expose pool stats.
+type poolStats struct {
+	totalConns       atomic.Int64
+	inUseConns       atomic.Int64
+	checkOutFailures atomic.Int64
+	poolClears       atomic.Int64
+}
+
+func (s *poolStats) monitor() *event.PoolMonitor {
+	return &event.PoolMonitor{
+		Event: func(evt *event.PoolEvent) {
+			switch evt.Type {
+			case event.ConnectionCreated:
+				s.totalConns.Add(1)
+			case event.ConnectionClosed:
+				s.totalConns.Add(-1)
+			case event.ConnectionCheckedOut:
+				s.inUseConns.Add(1)
+			case event.ConnectionCheckedIn:
+				s.inUseConns.Add(-1)
+			case event.ConnectionCheckOutFailed:
+				s.checkOutFailures.Add(1)
+			case event.ConnectionPoolCleared:
+				s.poolClears.Add(1)
+			}
+		},
+	}
+}
+
+// PoolStats returns the connection pool stats of all servers.
+func (m *Mongo) PoolStats() PoolStats {
+	return PoolStats{
+		TotalConns:       m.pool.totalConns.Load(),
+		InUseConns:       m.pool.inUseConns.Load(),
+		CheckOutFailures: m.pool.checkOutFailures.Load(),
+		PoolClears:       m.pool.poolClears.Load(),
+	}
+}
+
+// RegisterPoolMetrics registers collectors reading m's connection pool stats at scrape time, e.g. to
+// spot pool exhaustion from rising check-out failures.
+func RegisterPoolMetrics(reg prometheus.Registerer, m *Mongo) error {
+	if m == nil || m.Client == nil {
+		return ErrClientNil
+	}
+
+	if err := reg.Register(newPoolCollector(m)); err != nil {
+		return fmt.Errorf("mongo: failed to register pool metrics: %w", err)
+	}
+
+	return nil
+}
+
+type poolCollector struct {
+	mongo            *Mongo
+	totalConns       *prometheus.Desc
+	inUseConns       *prometheus.Desc
+	checkOutFailures *prometheus.Desc
+	poolClears       *prometheus.Desc
+}
+
+func newPoolCollector(m *Mongo) *poolCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName("gframework", "mongo", name), help, nil, nil)
+	}
+
+	return &poolCollector{
+		mongo:            m,
+		totalConns:       desc("pool_connections", "Number of connections in the pool."),
+		inUseConns:       desc("pool_in_use_connections", "Number of connections checked out of the pool."),
+		checkOutFailures: desc("pool_checkout_failures_total", "Number of times a connection could not be checked out."),
+		poolClears:       desc("pool_clears_total", "Number of times the pool was cleared after a server error."),
+	}
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.totalConns
+	ch <- c.inUseConns
+	ch <- c.checkOutFailures
+	ch <- c.poolClears
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.mongo.PoolStats()
+
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stats.TotalConns))
+	ch <- prometheus.MustNewConstMetric(c.inUseConns, prometheus.GaugeValue, float64(stats.InUseConns))
+	ch <- prometheus.MustNewConstMetric(c.checkOutFailures, prometheus.CounterValue, float64(stats.CheckOutFailures))
+	ch <- prometheus.MustNewConstMetric(c.poolClears, prometheus.CounterValue, float64(stats.PoolClears))
+}
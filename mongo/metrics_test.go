@@ -0,0 +1,59 @@
+//nolint:exhaustruct
+package mongo_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/mongo"
+	"github.com/prometheus/client_golang/prometheus"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestPrometheusMetrics_RecordsCommandsAndPoolStats(t *testing.T) {
+	t.Parallel()
+
+	reg := prometheus.NewRegistry()
+
+	metrics, err := mongo.NewPrometheusMetrics(reg)
+	require.NoError(t, err)
+
+	db := setupMongo(t, &mongo.Config{Metrics: metrics})
+	require.NoError(t, mongo.RegisterPoolMetrics(reg, db))
+
+	ctx := t.Context()
+
+	_, err = db.Collection("users").InsertOne(ctx, bson.D{{Key: "_id", Value: 1}})
+	require.NoError(t, err)
+
+	_, err = db.Collection("users").InsertOne(ctx, bson.D{{Key: "_id", Value: 1}})
+	require.Error(t, err)
+
+	require.Equal(t, 1, promtestutil.CollectAndCount(reg, "gframework_mongo_command_errors_total"))
+	require.Equal(t, 4, promtestutil.CollectAndCount(reg,
+		"gframework_mongo_pool_connections", "gframework_mongo_pool_in_use_connections",
+		"gframework_mongo_pool_checkout_failures_total", "gframework_mongo_pool_clears_total"))
+}
+
+func TestPrometheusMetrics_CommandFinished(t *testing.T) {
+	t.Parallel()
+
+	reg := prometheus.NewRegistry()
+
+	metrics, err := mongo.NewPrometheusMetrics(reg)
+	require.NoError(t, err)
+
+	metrics.CommandFinished("find", 10*time.Millisecond, nil)
+	metrics.CommandFinished("insert", 10*time.Millisecond, errAbort)
+
+	require.Equal(t, 2, promtestutil.CollectAndCount(reg, "gframework_mongo_command_duration_seconds"))
+	require.Equal(t, 1, promtestutil.CollectAndCount(reg, "gframework_mongo_command_errors_total"))
+}
+
+func TestRegisterPoolMetrics_NilClient(t *testing.T) {
+	t.Parallel()
+
+	require.ErrorIs(t, mongo.RegisterPoolMetrics(prometheus.NewRegistry(), nil), mongo.ErrClientNil)
+}
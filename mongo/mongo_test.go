@@ -0,0 +1,107 @@
+//nolint:exhaustruct
+package mongo_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/mongo"
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func setupMongo(t *testing.T, cfg *mongo.Config) *mongo.Mongo {
+	t.Helper()
+
+	container := testutil.SetupMongoContainer(t)
+	cfg.URI = container.URI()
+
+	db, err := mongo.New(cfg)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = db.Stop() })
+
+	require.NoError(t, db.Start(t.Context()))
+
+	return db
+}
+
+func TestNew_InvalidConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		cfg     *mongo.Config
+		wantErr error
+	}{
+		{name: "nil config", cfg: nil, wantErr: mongo.ErrConfigNil},
+		{name: "missing URI", cfg: &mongo.Config{Database: "app"}, wantErr: mongo.ErrInvalidURI},
+		{name: "missing database", cfg: &mongo.Config{URI: "mongodb://localhost:27017"}, wantErr: mongo.ErrInvalidDatabase},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := mongo.New(tt.cfg)
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestNew_DatabaseFromURI(t *testing.T) {
+	t.Parallel()
+
+	db, err := mongo.New(&mongo.Config{URI: "mongodb://localhost:27017/orders"})
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = db.Stop() })
+
+	require.Equal(t, "orders", db.Database.Name())
+	require.Equal(t, "mongo", db.Name())
+
+	db, err = mongo.New(&mongo.Config{URI: "mongodb://localhost:27017/orders", Database: "billing"})
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = db.Stop() })
+
+	require.Equal(t, "billing", db.Collection("invoices").Database().Name())
+}
+
+func TestMongo_UnreachableServer(t *testing.T) {
+	t.Parallel()
+
+	db, err := mongo.New(&mongo.Config{
+		URI:                    "mongodb://127.0.0.1:1/app",
+		ServerSelectionTimeout: 100 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = db.Stop() })
+
+	require.Error(t, db.Start(t.Context()))
+	require.Error(t, db.HealthCheck(t.Context(), mongo.WithHealthCheckTimeout(time.Second)))
+	require.False(t, db.IsHealthy(t.Context()))
+}
+
+func TestMongo_Connection(t *testing.T) {
+	t.Parallel()
+
+	db := setupMongo(t, &mongo.Config{MaxPoolSize: 5, Timeout: 5 * time.Second})
+	ctx := t.Context()
+
+	_, err := db.Collection("users").InsertOne(ctx, bson.D{{Key: "name", Value: "An"}})
+	require.NoError(t, err)
+
+	var user struct {
+		Name string `bson:"name"`
+	}
+
+	require.NoError(t, db.Collection("users").FindOne(ctx, bson.D{}).Decode(&user))
+	require.Equal(t, "An", user.Name)
+
+	require.NoError(t, db.HealthCheck(ctx, mongo.WithRequirePrimary(), mongo.WithRequireActiveConns()))
+	require.True(t, db.IsHealthy(ctx))
+	require.Positive(t, db.PoolStats().TotalConns)
+}
@@ -0,0 +1,71 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/v2/mongo/writeconcern"
+)
+
+var (
+	ErrStartSessionFailed = errors.New("mongo: failed to start session")
+	ErrTxRolledBack       = errors.New("mongo: transaction rolled back")
+	ErrTxCommitFailed     = errors.New("mongo: failed to commit transaction")
+)
+
+// TxFunc runs in a transaction. Operations must use ctx, which carries the session.
+type TxFunc func(ctx context.Context) error
+
+// WithTransaction runs fn in a transaction, committing when it returns nil and aborting otherwise.
+// Transactions require a replica set or a sharded cluster.
+//
+// fn is retried, together with the commit, on transient transaction errors such as write conflicts, so
+// it must not have side effects outside the database.
+func (m *Mongo) WithTransaction(ctx context.Context, fn TxFunc) error {
+	return m.WithTransactionOptions(ctx, options.Transaction(), fn)
+}
+
+func (m *Mongo) WithTransactionOptions(
+	ctx context.Context,
+	txOptions *options.TransactionOptionsBuilder,
+	fn TxFunc,
+) error {
+	if m.Client == nil {
+		return ErrClientNil
+	}
+
+	session, err := m.Client.StartSession()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrStartSessionFailed, err)
+	}
+
+	defer session.EndSession(context.WithoutCancel(ctx))
+
+	var fnErr error
+
+	_, err = session.WithTransaction(ctx, func(ctx context.Context) (any, error) {
+		fnErr = fn(ctx)
+
+		return nil, fnErr
+	}, txOptions)
+	if err == nil {
+		return nil
+	}
+
+	if fnErr != nil {
+		return fmt.Errorf("%w: %w", ErrTxRolledBack, fnErr)
+	}
+
+	return fmt.Errorf("%w: %w", ErrTxCommitFailed, err)
+}
+
+// WithSnapshotTransaction runs fn in a transaction reading from a majority-committed snapshot and
+// committing with a majority write concern.
+func (m *Mongo) WithSnapshotTransaction(ctx context.Context, fn TxFunc) error {
+	return m.WithTransactionOptions(ctx, options.Transaction().
+		SetReadConcern(readconcern.Snapshot()).
+		SetWriteConcern(writeconcern.Majority()), fn)
+}
@@ -0,0 +1,61 @@
+//nolint:exhaustruct
+package mongo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/andyle182810/gframework/mongo"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+var errAbort = errors.New("abort")
+
+func TestWithTransaction(t *testing.T) {
+	t.Parallel()
+
+	db := setupMongo(t, &mongo.Config{})
+	ctx := t.Context()
+	accounts := db.Collection("accounts")
+
+	err := db.WithTransaction(ctx, func(ctx context.Context) error {
+		_, err := accounts.InsertOne(ctx, bson.D{{Key: "_id", Value: "committed"}})
+
+		return err
+	})
+	require.NoError(t, err)
+
+	err = db.WithTransaction(ctx, func(ctx context.Context) error {
+		if _, err := accounts.InsertOne(ctx, bson.D{{Key: "_id", Value: "rolled-back"}}); err != nil {
+			return err
+		}
+
+		return errAbort
+	})
+	require.ErrorIs(t, err, mongo.ErrTxRolledBack)
+	require.ErrorIs(t, err, errAbort)
+
+	count, err := accounts.CountDocuments(ctx, bson.D{})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+}
+
+func TestWithSnapshotTransaction(t *testing.T) {
+	t.Parallel()
+
+	db := setupMongo(t, &mongo.Config{})
+	ctx := t.Context()
+
+	_, err := db.Collection("orders").InsertOne(ctx, bson.D{{Key: "total", Value: 10}})
+	require.NoError(t, err)
+
+	err = db.WithSnapshotTransaction(ctx, func(ctx context.Context) error {
+		count, err := db.Collection("orders").CountDocuments(ctx, bson.D{})
+		require.Equal(t, int64(1), count)
+
+		return err
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,85 @@
+package natsjetstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+const (
+	defaultPublishTimeout = 5 * time.Second
+)
+
+var (
+	ErrNilConnection           = errors.New("natsjetstream: nats connection cannot be nil")
+	ErrPublisherInitialization = errors.New("natsjetstream: failed to initialize jetstream publisher")
+	ErrPublishFailed           = errors.New("natsjetstream: failed to publish messages")
+)
+
+// Publisher has the same method set as redispub.Publisher and kafkapub.Publisher. Topics are NATS
+// subjects, which must be bound to a stream.
+type Publisher interface {
+	PublishToTopic(ctx context.Context, topic string, messageContents ...string) error
+	Close() error
+}
+
+type Options struct {
+	Timeout time.Duration
+}
+
+type JetStreamPublisher struct {
+	js      jetstream.JetStream
+	timeout time.Duration
+}
+
+var _ Publisher = (*JetStreamPublisher)(nil)
+
+func NewPublisher(conn *nats.Conn, opts Options) (*JetStreamPublisher, error) {
+	if conn == nil {
+		return nil, ErrNilConnection
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrPublisherInitialization, err)
+	}
+
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = defaultPublishTimeout
+	}
+
+	return &JetStreamPublisher{
+		js:      js,
+		timeout: timeout,
+	}, nil
+}
+
+// PublishToTopic publishes each message to subject and waits for the stream to acknowledge it. Every
+// message gets a random UUID as its Nats-Msg-Id, so the stream drops duplicates of a retried publish
+// within its duplicate window.
+func (p *JetStreamPublisher) PublishToTopic(ctx context.Context, subject string, messageContents ...string) error {
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+
+	for _, content := range messageContents {
+		if _, err := p.js.Publish(ctx, subject, []byte(content), jetstream.WithMsgID(watermill.NewUUID())); err != nil {
+			return fmt.Errorf("%w to subject %s: %w", ErrPublishFailed, subject, err)
+		}
+	}
+
+	return nil
+}
+
+// Close is a no-op; the NATS connection is owned by the caller.
+func (p *JetStreamPublisher) Close() error {
+	return nil
+}
@@ -0,0 +1,564 @@
+// Package natsjetstream provides a NATS JetStream publisher and durable-consumer subscriber with the
+// same API shape as redispub and redissub.
+//
+// The stream must exist before the subscriber starts; the subscriber creates or updates its durable
+// consumer on it:
+//
+//	js, _ := jetstream.New(nc)
+//	_, err := js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+//	    Name:     "ORDERS",
+//	    Subjects: []string{"orders.>", "orders-dlq.>"},
+//	})
+//
+//	subscriber, err := natsjetstream.NewSubscriber(nc, "ORDERS", "shipping", "orders.created", handler,
+//	    natsjetstream.WithRetry(3, time.Second, "orders-dlq.created"),
+//	)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	runner.New(runner.WithCoreService(subscriber)).Run()
+//
+// Retries are redeliveries by the server: a failed message is negatively acknowledged with the retry
+// delay, and the consumer's MaxDeliver is set to MaxRetries+1. When a message reaches MaxDeliver the
+// server emits a max-deliveries advisory; the subscriber listens for it and copies the message from the
+// stream to the dead letter subject, with original_topic, consumer_group, stream_sequence, deliveries,
+// failed_at and, when known, error headers. The advisory is consumed through a queue group named after
+// the consumer, so only one instance handles it, and the dead letter message is published with a
+// deterministic Nats-Msg-Id so the stream drops duplicates.
+//
+// Without WithRetry a failed message is terminated and not redelivered, as in redissub.
+package natsjetstream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultShutdownTimeout = 20 * time.Second
+	defaultExecTimeout     = 30 * time.Second
+	defaultAckWait         = 60 * time.Second
+
+	maxDeliveriesAdvisoryPrefix = "$JS.EVENT.ADVISORY.CONSUMER.MAX_DELIVERIES"
+)
+
+var (
+	ErrEmptyStreamName          = errors.New("natsjetstream: stream name cannot be empty")
+	ErrEmptyConsumerName        = errors.New("natsjetstream: consumer name cannot be empty")
+	ErrEmptySubject             = errors.New("natsjetstream: subject cannot be empty")
+	ErrNilMessageHandler        = errors.New("natsjetstream: message handler cannot be nil")
+	ErrMessageHandlerNotDefined = errors.New("natsjetstream: message handler is not defined")
+	ErrExecTimeout              = errors.New("natsjetstream: message handler execution timed out")
+	ErrAlreadyRunning           = errors.New("natsjetstream: subscriber already running")
+)
+
+// MessageHandler has the same signature as redissub.MessageHandler.
+type MessageHandler func(ctx context.Context, payload message.Payload) error
+
+type Metrics interface {
+	MessageReceived(topic string)
+	MessageProcessed(topic string, duration time.Duration, err error)
+	MessageAcked(topic string)
+	MessageNacked(topic string)
+	MessageSentToDLQ(topic string)
+}
+
+type RetryConfig struct {
+	MaxRetries int           // Maximum number of redeliveries (0 = no retries)
+	RetryDelay time.Duration // Redelivery delay after a failure
+	DLQTopic   string        // Dead letter subject (empty = no DLQ)
+}
+
+type SubscriberConfig struct {
+	ShutdownTimeout time.Duration // Maximum time to wait for graceful shutdown before force closing
+	ExecTimeout     time.Duration // Maximum time allowed for message handler execution
+	Metrics         Metrics
+	Retry           *RetryConfig
+	AckPolicy       jetstream.AckPolicy // Consumer ack policy (default AckExplicitPolicy)
+	AckWait         time.Duration       // Time the server waits for an ack before redelivering
+}
+
+type SubscriberOption func(*SubscriberConfig)
+
+func WithMetrics(m Metrics) SubscriberOption {
+	return func(c *SubscriberConfig) {
+		c.Metrics = m
+	}
+}
+
+func WithRetry(maxRetries int, retryDelay time.Duration, dlqTopic string) SubscriberOption {
+	return func(c *SubscriberConfig) {
+		c.Retry = &RetryConfig{
+			MaxRetries: maxRetries,
+			RetryDelay: retryDelay,
+			DLQTopic:   dlqTopic,
+		}
+	}
+}
+
+func WithShutdownTimeout(d time.Duration) SubscriberOption {
+	return func(c *SubscriberConfig) {
+		c.ShutdownTimeout = d
+	}
+}
+
+func WithExecTimeout(d time.Duration) SubscriberOption {
+	return func(c *SubscriberConfig) {
+		c.ExecTimeout = d
+	}
+}
+
+// WithAckPolicy sets the consumer's ack policy. With jetstream.AckNonePolicy messages are not
+// acknowledged, so retries and the dead letter subject have no effect.
+func WithAckPolicy(policy jetstream.AckPolicy) SubscriberOption {
+	return func(c *SubscriberConfig) {
+		c.AckPolicy = policy
+	}
+}
+
+// WithAckWait sets how long the server waits for an ack before redelivering. It should be longer than
+// the exec timeout.
+func WithAckWait(d time.Duration) SubscriberOption {
+	return func(c *SubscriberConfig) {
+		c.AckWait = d
+	}
+}
+
+type Subscriber struct {
+	conn           *nats.Conn
+	js             jetstream.JetStream
+	name           string
+	stream         string
+	consumer       string
+	subject        string
+	shutdownSignal chan struct{}
+	stoppedSignal  chan struct{}
+	messageHandler MessageHandler
+	config         SubscriberConfig
+	healthy        atomic.Bool
+	running        atomic.Bool
+	// lastErrors holds the handler error of messages whose last delivery failed, by stream sequence,
+	// until the max-deliveries advisory sends them to the DLQ.
+	lastErrors sync.Map
+}
+
+type maxDeliveriesAdvisory struct {
+	Stream     string `json:"stream"`
+	Consumer   string `json:"consumer"`
+	StreamSeq  uint64 `json:"stream_seq"`
+	Deliveries uint64 `json:"deliveries"`
+}
+
+func NewSubscriber(
+	conn *nats.Conn,
+	stream,
+	consumer,
+	subject string,
+	messageHandler MessageHandler,
+	opts ...SubscriberOption,
+) (*Subscriber, error) {
+	if conn == nil {
+		return nil, ErrNilConnection
+	}
+
+	if stream == "" {
+		return nil, ErrEmptyStreamName
+	}
+
+	if consumer == "" {
+		return nil, ErrEmptyConsumerName
+	}
+
+	if subject == "" {
+		return nil, ErrEmptySubject
+	}
+
+	if messageHandler == nil {
+		return nil, ErrNilMessageHandler
+	}
+
+	config := defaultSubscriberConfig()
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	js, err := jetstream.New(conn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JetStream context: %w", err)
+	}
+
+	//nolint:exhaustruct
+	sub := &Subscriber{
+		conn:           conn,
+		js:             js,
+		name:           fmt.Sprintf("natsjetstream-%s-%s", stream, consumer),
+		stream:         stream,
+		consumer:       consumer,
+		subject:        subject,
+		shutdownSignal: make(chan struct{}),
+		stoppedSignal:  make(chan struct{}),
+		messageHandler: messageHandler,
+		config:         config,
+	}
+	sub.healthy.Store(false)
+
+	return sub, nil
+}
+
+func defaultSubscriberConfig() SubscriberConfig {
+	return SubscriberConfig{
+		ShutdownTimeout: defaultShutdownTimeout,
+		ExecTimeout:     defaultExecTimeout,
+		Metrics:         nil,
+		Retry:           nil,
+		AckPolicy:       jetstream.AckExplicitPolicy,
+		AckWait:         defaultAckWait,
+	}
+}
+
+func (s *Subscriber) Name() string {
+	return s.name
+}
+
+func (s *Subscriber) IsHealthy() bool {
+	return s.healthy.Load()
+}
+
+// ConsumerGroup returns the durable consumer name, which plays the role of a redissub consumer group.
+func (s *Subscriber) ConsumerGroup() string {
+	return s.consumer
+}
+
+func (s *Subscriber) Topic() string {
+	return s.subject
+}
+
+func (s *Subscriber) Stream() string {
+	return s.stream
+}
+
+func (s *Subscriber) Start(ctx context.Context) error {
+	if !s.running.CompareAndSwap(false, true) {
+		return ErrAlreadyRunning
+	}
+
+	defer close(s.stoppedSignal)
+
+	log.Info().
+		Str("source", "gframework").
+		Str("stream", s.stream).
+		Str("subject", s.subject).
+		Msg("The subscription is being started")
+
+	stream, err := s.js.Stream(ctx, s.stream)
+	if err != nil {
+		return fmt.Errorf("failed to look up stream %s: %w", s.stream, err)
+	}
+
+	consumer, err := stream.CreateOrUpdateConsumer(ctx, s.consumerConfig())
+	if err != nil {
+		return fmt.Errorf("failed to create consumer %s: %w", s.consumer, err)
+	}
+
+	if s.config.Retry != nil && s.config.Retry.DLQTopic != "" {
+		advisorySubject := fmt.Sprintf("%s.%s.%s", maxDeliveriesAdvisoryPrefix, s.stream, s.consumer)
+
+		advisorySub, err := s.conn.QueueSubscribe(advisorySubject, s.consumer, func(msg *nats.Msg) {
+			s.handleMaxDeliveries(ctx, stream, msg)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to subscribe to max deliveries advisories: %w", err)
+		}
+
+		defer func() {
+			if err := advisorySub.Unsubscribe(); err != nil {
+				log.Warn().
+					Str("source", "gframework").
+					Err(err).
+					Str("subject", advisorySubject).
+					Msg("Failed to unsubscribe from max deliveries advisories")
+			}
+		}()
+	}
+
+	consumeCtx, err := consumer.Consume(func(msg jetstream.Msg) {
+		s.handleMessage(ctx, msg)
+	})
+	if err != nil {
+		return fmt.Errorf("subscription to subject %s failed: %w", s.subject, err)
+	}
+
+	s.healthy.Store(true)
+
+	select {
+	case <-ctx.Done():
+		s.healthy.Store(false)
+		consumeCtx.Stop()
+		log.Info().
+			Str("source", "gframework").
+			Str("service_name", s.Name()).
+			Str("subject", s.subject).
+			Msg("Subscriber stopped: context cancelled")
+
+		return ctx.Err()
+	case <-s.shutdownSignal:
+		s.healthy.Store(false)
+		consumeCtx.Drain()
+		<-consumeCtx.Closed()
+		log.Info().
+			Str("source", "gframework").
+			Str("service_name", s.Name()).
+			Str("subject", s.subject).
+			Msg("Subscriber stopped: graceful shutdown initiated")
+
+		return nil
+	}
+}
+
+func (s *Subscriber) Stop() error {
+	if !s.running.CompareAndSwap(true, false) {
+		return nil
+	}
+
+	log.Info().
+		Str("source", "gframework").
+		Str("stream", s.stream).
+		Str("consumer", s.consumer).
+		Msg("Stopping subscriber")
+
+	s.healthy.Store(false)
+	close(s.shutdownSignal)
+
+	select {
+	case <-s.stoppedSignal:
+		// Good, subscriber stopped cleanly
+	case <-time.After(s.config.ShutdownTimeout):
+		log.Error().
+			Str("source", "gframework").
+			Str("stream", s.stream).
+			Str("consumer", s.consumer).
+			Dur("timeout", s.config.ShutdownTimeout).
+			Msg("Timeout waiting for subscriber to stop")
+	}
+
+	log.Info().
+		Str("source", "gframework").
+		Str("stream", s.stream).
+		Str("consumer", s.consumer).
+		Msg("Subscriber stopped")
+
+	return nil
+}
+
+func (s *Subscriber) consumerConfig() jetstream.ConsumerConfig {
+	maxDeliver := -1
+	if s.config.Retry != nil {
+		maxDeliver = max(s.config.Retry.MaxRetries, 0) + 1
+	}
+
+	//nolint:exhaustruct
+	return jetstream.ConsumerConfig{
+		Durable:       s.consumer,
+		FilterSubject: s.subject,
+		AckPolicy:     s.config.AckPolicy,
+		AckWait:       s.config.AckWait,
+		MaxDeliver:    maxDeliver,
+	}
+}
+
+func (s *Subscriber) handleMessage(ctx context.Context, msg jetstream.Msg) {
+	if s.config.Metrics != nil {
+		s.config.Metrics.MessageReceived(s.subject)
+	}
+
+	start := time.Now()
+	processingErr := s.executeWithTimeout(ctx, msg)
+
+	if s.config.Metrics != nil {
+		s.config.Metrics.MessageProcessed(s.subject, time.Since(start), processingErr)
+	}
+
+	if s.config.AckPolicy == jetstream.AckNonePolicy {
+		if processingErr != nil {
+			s.logFailure(msg, processingErr)
+		}
+
+		return
+	}
+
+	if processingErr != nil {
+		s.handleFailedMessage(msg, processingErr)
+
+		return
+	}
+
+	if err := msg.Ack(); err != nil {
+		log.Warn().
+			Str("source", "gframework").
+			Err(err).
+			Str("subject", msg.Subject()).
+			Msg("Failed to acknowledge the message")
+
+		return
+	}
+
+	if s.config.Metrics != nil {
+		s.config.Metrics.MessageAcked(s.subject)
+	}
+}
+
+func (s *Subscriber) executeWithTimeout(ctx context.Context, msg jetstream.Msg) error {
+	if s.messageHandler == nil {
+		return ErrMessageHandlerNotDefined
+	}
+
+	if s.config.ExecTimeout <= 0 {
+		return s.messageHandler(ctx, msg.Data())
+	}
+
+	execCtx, cancel := context.WithTimeout(ctx, s.config.ExecTimeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- s.messageHandler(execCtx, msg.Data())
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-execCtx.Done():
+		if errors.Is(execCtx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("%w: exceeded %v", ErrExecTimeout, s.config.ExecTimeout)
+		}
+
+		return execCtx.Err()
+	}
+}
+
+func (s *Subscriber) handleFailedMessage(msg jetstream.Msg, processingErr error) {
+	s.logFailure(msg, processingErr)
+
+	var err error
+
+	if s.config.Retry == nil {
+		// Mirror redissub: without retries a failed message is dropped instead of redelivered.
+		err = msg.Term()
+	} else {
+		if meta, metaErr := msg.Metadata(); metaErr == nil &&
+			s.config.Retry.DLQTopic != "" && meta.NumDelivered > uint64(max(s.config.Retry.MaxRetries, 0)) {
+			s.lastErrors.Store(meta.Sequence.Stream, processingErr.Error())
+		}
+
+		// The server redelivers until MaxDeliver and then emits the max deliveries advisory.
+		err = msg.NakWithDelay(s.config.Retry.RetryDelay)
+	}
+
+	if err != nil {
+		log.Warn().
+			Str("source", "gframework").
+			Err(err).
+			Str("subject", msg.Subject()).
+			Msg("Failed to negatively acknowledge the message")
+	}
+
+	if s.config.Metrics != nil {
+		s.config.Metrics.MessageNacked(s.subject)
+	}
+}
+
+func (s *Subscriber) logFailure(msg jetstream.Msg, processingErr error) {
+	event := log.Error().
+		Str("source", "gframework").
+		Err(processingErr).
+		Str("subject", msg.Subject())
+
+	if meta, err := msg.Metadata(); err == nil {
+		event = event.
+			Uint64("stream_sequence", meta.Sequence.Stream).
+			Uint64("deliveries", meta.NumDelivered)
+	}
+
+	event.Msg("The message processing has failed")
+}
+
+func (s *Subscriber) handleMaxDeliveries(ctx context.Context, stream jetstream.Stream, advisoryMsg *nats.Msg) {
+	var advisory maxDeliveriesAdvisory
+
+	if err := json.Unmarshal(advisoryMsg.Data, &advisory); err != nil {
+		log.Error().
+			Str("source", "gframework").
+			Err(err).
+			Str("subject", advisoryMsg.Subject).
+			Msg("Failed to decode max deliveries advisory")
+
+		return
+	}
+
+	if err := s.sendToDLQ(ctx, stream, advisory); err != nil {
+		log.Error().
+			Str("source", "gframework").
+			Err(err).
+			Uint64("stream_sequence", advisory.StreamSeq).
+			Msg("Failed to send message to DLQ")
+
+		return
+	}
+
+	if s.config.Metrics != nil {
+		s.config.Metrics.MessageSentToDLQ(s.subject)
+	}
+}
+
+func (s *Subscriber) sendToDLQ(ctx context.Context, stream jetstream.Stream, advisory maxDeliveriesAdvisory) error {
+	original, err := stream.GetMsg(ctx, advisory.StreamSeq)
+	if err != nil {
+		return fmt.Errorf("failed to load message %d from stream %s: %w", advisory.StreamSeq, s.stream, err)
+	}
+
+	header := nats.Header{}
+
+	for key, values := range original.Header {
+		if key != jetstream.MsgIDHeader {
+			header[key] = values
+		}
+	}
+
+	header.Set("original_topic", original.Subject)
+	header.Set("consumer_group", s.consumer)
+	header.Set("stream_sequence", strconv.FormatUint(advisory.StreamSeq, 10))
+	header.Set("deliveries", strconv.FormatUint(advisory.Deliveries, 10))
+	header.Set("failed_at", time.Now().UTC().Format(time.RFC3339))
+
+	if lastErr, ok := s.lastErrors.LoadAndDelete(advisory.StreamSeq); ok {
+		header.Set("error", lastErr.(string)) //nolint:forcetypeassert
+	}
+
+	//nolint:exhaustruct
+	dlqMsg := &nats.Msg{
+		Subject: s.config.Retry.DLQTopic,
+		Header:  header,
+		Data:    original.Data,
+	}
+	msgID := fmt.Sprintf("%s-%s-%d", s.stream, s.consumer, advisory.StreamSeq)
+
+	if _, err := s.js.PublishMsg(ctx, dlqMsg, jetstream.WithMsgID(msgID)); err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", s.config.Retry.DLQTopic, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,204 @@
+//nolint:exhaustruct
+package natsjetstream_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/andyle182810/gframework/natsjetstream"
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testStream  = "ORDERS"
+	testTimeout = 10 * time.Second
+)
+
+var errHandlerFailed = errors.New("handler failed")
+
+func startJetStream(t *testing.T) (*nats.Conn, jetstream.JetStream) {
+	t.Helper()
+
+	conn := testutil.SetupNATSContainer(t).Connect(t)
+
+	js, err := jetstream.New(conn)
+	require.NoError(t, err)
+
+	_, err = js.CreateOrUpdateStream(t.Context(), jetstream.StreamConfig{
+		Name:     testStream,
+		Subjects: []string{"orders.>", "orders-dlq.>"},
+	})
+	require.NoError(t, err)
+
+	return conn, js
+}
+
+func startSubscriber(t *testing.T, sub *natsjetstream.Subscriber) {
+	t.Helper()
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- sub.Start(context.Background())
+	}()
+
+	require.Eventually(t, sub.IsHealthy, testTimeout, 10*time.Millisecond)
+
+	t.Cleanup(func() {
+		require.NoError(t, sub.Stop())
+		require.NoError(t, <-errCh)
+	})
+}
+
+func TestNewSubscriber(t *testing.T) {
+	t.Parallel()
+
+	conn, _ := startJetStream(t)
+	handler := func(context.Context, message.Payload) error { return nil }
+
+	tests := []struct {
+		name        string
+		conn        *nats.Conn
+		stream      string
+		consumer    string
+		subject     string
+		handler     natsjetstream.MessageHandler
+		expectError error
+	}{
+		{"valid configuration", conn, testStream, "shipping", "orders.created", handler, nil},
+		{"nil connection", nil, testStream, "shipping", "orders.created", handler, natsjetstream.ErrNilConnection},
+		{"empty stream", conn, "", "shipping", "orders.created", handler, natsjetstream.ErrEmptyStreamName},
+		{"empty consumer", conn, testStream, "", "orders.created", handler, natsjetstream.ErrEmptyConsumerName},
+		{"empty subject", conn, testStream, "shipping", "", handler, natsjetstream.ErrEmptySubject},
+		{"nil handler", conn, testStream, "shipping", "orders.created", nil, natsjetstream.ErrNilMessageHandler},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sub, err := natsjetstream.NewSubscriber(tt.conn, tt.stream, tt.consumer, tt.subject, tt.handler)
+			if tt.expectError != nil {
+				require.ErrorIs(t, err, tt.expectError)
+				require.Nil(t, sub)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, "natsjetstream-ORDERS-shipping", sub.Name())
+			require.Equal(t, "shipping", sub.ConsumerGroup())
+			require.Equal(t, "orders.created", sub.Topic())
+			require.Equal(t, testStream, sub.Stream())
+			require.False(t, sub.IsHealthy())
+		})
+	}
+}
+
+func TestSubscriber_DeliversPublishedMessages(t *testing.T) {
+	t.Parallel()
+
+	conn, _ := startJetStream(t)
+
+	received := make(chan string, 2)
+	sub, err := natsjetstream.NewSubscriber(conn, testStream, "shipping", "orders.created",
+		func(_ context.Context, payload message.Payload) error {
+			received <- string(payload)
+
+			return nil
+		})
+	require.NoError(t, err)
+	startSubscriber(t, sub)
+
+	publisher, err := natsjetstream.NewPublisher(conn, natsjetstream.Options{})
+	require.NoError(t, err)
+	require.NoError(t, publisher.PublishToTopic(t.Context(), "orders.created", "first", "second"))
+
+	for _, want := range []string{"first", "second"} {
+		select {
+		case got := <-received:
+			require.Equal(t, want, got)
+		case <-time.After(testTimeout):
+			t.Fatalf("message %q was not delivered", want)
+		}
+	}
+}
+
+func TestSubscriber_SendsToDLQAfterMaxDeliveries(t *testing.T) {
+	t.Parallel()
+
+	conn, js := startJetStream(t)
+
+	var attempts atomic.Int32
+
+	sub, err := natsjetstream.NewSubscriber(conn, testStream, "shipping", "orders.created",
+		func(context.Context, message.Payload) error {
+			attempts.Add(1)
+
+			return errHandlerFailed
+		},
+		natsjetstream.WithRetry(2, 0, "orders-dlq.created"),
+	)
+	require.NoError(t, err)
+	startSubscriber(t, sub)
+
+	publisher, err := natsjetstream.NewPublisher(conn, natsjetstream.Options{})
+	require.NoError(t, err)
+	require.NoError(t, publisher.PublishToTopic(t.Context(), "orders.created", `{"id":1}`))
+
+	dlqConsumer, err := js.CreateOrUpdateConsumer(t.Context(), testStream, jetstream.ConsumerConfig{
+		FilterSubject: "orders-dlq.created",
+	})
+	require.NoError(t, err)
+
+	dlqMsg, err := dlqConsumer.Next(jetstream.FetchMaxWait(testTimeout))
+	require.NoError(t, err)
+
+	require.Equal(t, `{"id":1}`, string(dlqMsg.Data()))
+	require.Equal(t, "orders.created", dlqMsg.Headers().Get("original_topic"))
+	require.Equal(t, "shipping", dlqMsg.Headers().Get("consumer_group"))
+	require.Equal(t, "3", dlqMsg.Headers().Get("deliveries"))
+	require.Equal(t, errHandlerFailed.Error(), dlqMsg.Headers().Get("error"))
+	require.EqualValues(t, 3, attempts.Load())
+}
+
+func TestSubscriber_StartTwice(t *testing.T) {
+	t.Parallel()
+
+	conn, _ := startJetStream(t)
+
+	sub, err := natsjetstream.NewSubscriber(conn, testStream, "shipping", "orders.created",
+		func(context.Context, message.Payload) error { return nil })
+	require.NoError(t, err)
+	startSubscriber(t, sub)
+
+	require.ErrorIs(t, sub.Start(t.Context()), natsjetstream.ErrAlreadyRunning)
+}
+
+func TestSubscriber_StopWhenNotRunning(t *testing.T) {
+	t.Parallel()
+
+	conn, _ := startJetStream(t)
+
+	sub, err := natsjetstream.NewSubscriber(conn, testStream, "shipping", "orders.created",
+		func(context.Context, message.Payload) error { return nil })
+	require.NoError(t, err)
+
+	require.NoError(t, sub.Stop())
+}
+
+func TestNewPublisher_NilConnection(t *testing.T) {
+	t.Parallel()
+
+	publisher, err := natsjetstream.NewPublisher(nil, natsjetstream.Options{})
+
+	require.ErrorIs(t, err, natsjetstream.ErrNilConnection)
+	require.Nil(t, publisher)
+}
@@ -0,0 +1,199 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	apnsProductionURL = "https://api.push.apple.com"
+	apnsSandboxURL    = "https://api.sandbox.push.apple.com"
+	// APNs rejects tokens older than an hour and refreshes more often than every 20 minutes.
+	apnsTokenLifetime = 50 * time.Minute
+)
+
+var ErrAPNsConfig = errors.New("notify: invalid apns configuration")
+
+//nolint:gochecknoglobals
+var apnsInvalidRecipientReasons = []string{"BadDeviceToken", "Unregistered", "DeviceTokenNotForTopic"}
+
+type APNsConfig struct {
+	// KeyID and TeamID identify the token signing key of the Apple developer account.
+	KeyID  string
+	TeamID string
+	// PrivateKey is the PEM encoded .p8 signing key.
+	PrivateKey []byte
+	// Topic is the bundle ID of the app.
+	Topic string
+	// Production sends to the production environment instead of the sandbox.
+	Production bool
+	// BaseURL overrides the environment URL, e.g. for a test server.
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// APNsSender sends push notifications to Apple devices through the APNs HTTP/2 provider API with
+// token-based authentication.
+type APNsSender struct {
+	cfg APNsConfig
+	key *ecdsa.PrivateKey
+
+	mu       sync.Mutex
+	token    string
+	issuedAt time.Time
+}
+
+var _ PushSender = (*APNsSender)(nil)
+
+func NewAPNs(cfg APNsConfig) (*APNsSender, error) {
+	if cfg.KeyID == "" || cfg.TeamID == "" || cfg.Topic == "" {
+		return nil, fmt.Errorf("%w: key id, team id and topic are required", ErrAPNsConfig)
+	}
+
+	key, err := jwt.ParseECPrivateKeyFromPEM(cfg.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid private key: %w", ErrAPNsConfig, err)
+	}
+
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = apnsSandboxURL
+		if cfg.Production {
+			cfg.BaseURL = apnsProductionURL
+		}
+	}
+
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: defaultHTTPTimeout} //nolint:exhaustruct
+	}
+
+	cfg.BaseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+
+	return &APNsSender{cfg: cfg, key: key, mu: sync.Mutex{}, token: "", issuedAt: time.Time{}}, nil
+}
+
+type apnsResponse struct {
+	Reason string `json:"reason"`
+}
+
+func (s *APNsSender) SendPush(ctx context.Context, msg *Push) (string, error) {
+	payload, err := json.Marshal(newAPNsPayload(msg))
+	if err != nil {
+		return "", fmt.Errorf("notify: failed to encode apns payload: %w", err)
+	}
+
+	token, err := s.authToken()
+	if err != nil {
+		return "", err
+	}
+
+	endpoint := s.cfg.BaseURL + "/3/device/" + url.PathEscape(msg.Token)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("notify: failed to create apns request: %w", err)
+	}
+
+	req.Header.Set("Authorization", "bearer "+token)
+	req.Header.Set("Apns-Topic", s.cfg.Topic)
+
+	if msg.Title == "" && msg.Body == "" {
+		req.Header.Set("Apns-Push-Type", "background")
+		req.Header.Set("Apns-Priority", "5")
+	} else {
+		req.Header.Set("Apns-Push-Type", "alert")
+		req.Header.Set("Apns-Priority", "10")
+	}
+
+	resp, body, err := do(s.cfg.HTTPClient, req)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		var decoded apnsResponse
+		_ = json.Unmarshal(body, &decoded)
+
+		if decoded.Reason == "ExpiredProviderToken" {
+			s.resetToken()
+		}
+
+		return "", &ProviderError{
+			Provider:         "apns",
+			StatusCode:       resp.StatusCode,
+			Code:             decoded.Reason,
+			Message:          string(body),
+			InvalidRecipient: slices.Contains(apnsInvalidRecipientReasons, decoded.Reason),
+		}
+	}
+
+	return resp.Header.Get("Apns-Id"), nil
+}
+
+func (s *APNsSender) authToken() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && time.Since(s.issuedAt) < apnsTokenLifetime {
+		return s.token, nil
+	}
+
+	now := time.Now()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{"iss": s.cfg.TeamID, "iat": now.Unix()})
+	token.Header["kid"] = s.cfg.KeyID
+
+	signed, err := token.SignedString(s.key)
+	if err != nil {
+		return "", fmt.Errorf("notify: failed to sign apns token: %w", err)
+	}
+
+	s.token, s.issuedAt = signed, now
+
+	return signed, nil
+}
+
+func (s *APNsSender) resetToken() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.token = ""
+}
+
+func newAPNsPayload(msg *Push) map[string]any {
+	aps := map[string]any{}
+
+	if msg.Title != "" || msg.Body != "" {
+		aps["alert"] = map[string]string{"title": msg.Title, "body": msg.Body}
+	} else {
+		aps["content-available"] = 1
+	}
+
+	if msg.Sound != "" {
+		aps["sound"] = msg.Sound
+	}
+
+	if msg.Badge != nil {
+		aps["badge"] = *msg.Badge
+	}
+
+	payload := make(map[string]any, len(msg.Data)+1)
+	for key, value := range msg.Data {
+		payload[key] = value
+	}
+
+	payload["aps"] = aps
+
+	return payload
+}
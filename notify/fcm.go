@@ -0,0 +1,166 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/andyle182810/gframework/httpclient"
+)
+
+const defaultFCMBaseURL = "https://fcm.googleapis.com"
+
+var ErrFCMConfig = errors.New("notify: invalid fcm configuration")
+
+type FCMConfig struct {
+	ProjectID string
+	// Tokens provides OAuth 2.0 access tokens of a service account with the
+	// "https://www.googleapis.com/auth/firebase.messaging" scope. A token is invalidated when FCM
+	// rejects it.
+	Tokens     httpclient.TokenProvider
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// FCMSender sends push notifications to Android, iOS and web apps through the Firebase Cloud
+// Messaging HTTP v1 API.
+type FCMSender struct {
+	cfg FCMConfig
+}
+
+var _ PushSender = (*FCMSender)(nil)
+
+func NewFCM(cfg FCMConfig) (*FCMSender, error) {
+	if cfg.ProjectID == "" || cfg.Tokens == nil {
+		return nil, fmt.Errorf("%w: project id and token provider are required", ErrFCMConfig)
+	}
+
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = defaultFCMBaseURL
+	}
+
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: defaultHTTPTimeout} //nolint:exhaustruct
+	}
+
+	cfg.BaseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+
+	return &FCMSender{cfg: cfg}, nil
+}
+
+type fcmNotification struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body,omitempty"`
+}
+
+type fcmMessage struct {
+	Token        string            `json:"token"`
+	Notification *fcmNotification  `json:"notification,omitempty"`
+	Data         map[string]string `json:"data,omitempty"`
+	Android      map[string]any    `json:"android,omitempty"`
+	APNs         map[string]any    `json:"apns,omitempty"`
+}
+
+type fcmResponse struct {
+	Name  string `json:"name"`
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"`
+		Details []struct {
+			ErrorCode string `json:"errorCode"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+func (s *FCMSender) SendPush(ctx context.Context, msg *Push) (string, error) {
+	payload, err := json.Marshal(map[string]fcmMessage{"message": newFCMMessage(msg)})
+	if err != nil {
+		return "", fmt.Errorf("notify: failed to encode fcm message: %w", err)
+	}
+
+	token, err := s.cfg.Tokens.GetToken(ctx)
+	if err != nil {
+		return "", fmt.Errorf("notify: failed to get fcm access token: %w", err)
+	}
+
+	endpoint := s.cfg.BaseURL + "/v1/projects/" + url.PathEscape(s.cfg.ProjectID) + "/messages:send"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return "", fmt.Errorf("notify: failed to create fcm request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, body, err := do(s.cfg.HTTPClient, req)
+	if err != nil {
+		return "", err
+	}
+
+	var decoded fcmResponse
+	_ = json.Unmarshal(body, &decoded)
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		if resp.StatusCode == http.StatusUnauthorized {
+			s.cfg.Tokens.InvalidateToken()
+		}
+
+		code := decoded.Error.Status
+		for _, detail := range decoded.Error.Details {
+			if detail.ErrorCode != "" {
+				code = detail.ErrorCode
+			}
+		}
+
+		providerErr := &ProviderError{
+			Provider:         "fcm",
+			StatusCode:       resp.StatusCode,
+			Code:             code,
+			Message:          decoded.Error.Message,
+			InvalidRecipient: code == "UNREGISTERED" || code == "SENDER_ID_MISMATCH",
+		}
+
+		if providerErr.Message == "" {
+			providerErr.Message = string(body)
+		}
+
+		return "", providerErr
+	}
+
+	return decoded.Name, nil
+}
+
+func newFCMMessage(msg *Push) fcmMessage {
+	message := fcmMessage{Token: msg.Token, Notification: nil, Data: msg.Data, Android: nil, APNs: nil}
+
+	if msg.Title != "" || msg.Body != "" {
+		message.Notification = &fcmNotification{Title: msg.Title, Body: msg.Body}
+	}
+
+	if msg.Sound != "" {
+		message.Android = map[string]any{"notification": map[string]any{"sound": msg.Sound}}
+	}
+
+	aps := map[string]any{}
+
+	if msg.Sound != "" {
+		aps["sound"] = msg.Sound
+	}
+
+	if msg.Badge != nil {
+		aps["badge"] = *msg.Badge
+	}
+
+	if len(aps) > 0 {
+		message.APNs = map[string]any{"payload": map[string]any{"aps": aps}}
+	}
+
+	return message
+}
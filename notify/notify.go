@@ -0,0 +1,229 @@
+// Package notify sends SMS through Twilio-compatible APIs and mobile push notifications through
+// Firebase Cloud Messaging and Apple Push Notification service, with text templates, a retrying queue
+// with a dead-letter list, and an echo handler for delivery status callbacks.
+//
+// Basic usage:
+//
+//	sms, err := notify.NewTwilio(notify.TwilioConfig{AccountSID: sid, AuthToken: token, From: "+15550100"})
+//	push, err := notify.NewFCM(notify.FCMConfig{ProjectID: "my-app", Tokens: googleTokens})
+//	templates, err := notify.ParseTemplates(templatesFS)
+//
+//	n, err := notify.New(notify.WithSMS(sms), notify.WithPush(push), notify.WithTemplates(templates))
+//
+//	id, err := n.SendSMSTemplate(ctx, &notify.SMS{To: user.Phone}, "login_code", code)
+//
+// Wrap a Notifier with NewQueue to send from workers with retries; messages that keep failing, or
+// whose recipient the provider rejects, are moved to a dead-letter list for inspection and replay.
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNoSender         = errors.New("notify: no sender configured")
+	ErrNoSMSSender      = errors.New("notify: no sms sender configured")
+	ErrNoPushSender     = errors.New("notify: no push sender configured")
+	ErrNoRecipient      = errors.New("notify: message has no recipient")
+	ErrNoBody           = errors.New("notify: message has no content")
+	ErrNoTemplates      = errors.New("notify: no templates configured")
+	ErrTemplateNotFound = errors.New("notify: template not found")
+	ErrSendFailed       = errors.New("notify: failed to send message")
+	// ErrInvalidRecipient is wrapped by provider errors for phone numbers and device tokens that are
+	// invalid, unsubscribed or no longer registered. Retrying such messages cannot succeed.
+	ErrInvalidRecipient = errors.New("notify: recipient is invalid or unregistered")
+)
+
+// SMS is a text message. To and From are E.164 phone numbers such as "+14155550100"; an empty From
+// uses the sender configured on the provider.
+type SMS struct {
+	To   string `json:"to"`
+	From string `json:"from,omitempty"`
+	Body string `json:"body"`
+}
+
+func (s *SMS) Validate() error {
+	if s.To == "" {
+		return ErrNoRecipient
+	}
+
+	if s.Body == "" {
+		return ErrNoBody
+	}
+
+	return nil
+}
+
+// Push is a notification for one device. A push without a title and body is delivered silently to
+// the app with its data.
+type Push struct {
+	Token string            `json:"token"`
+	Title string            `json:"title,omitempty"`
+	Body  string            `json:"body,omitempty"`
+	Data  map[string]string `json:"data,omitempty"`
+	Sound string            `json:"sound,omitempty"`
+	// Badge sets the app icon badge on iOS; zero clears it and nil leaves it unchanged.
+	Badge *int `json:"badge,omitempty"`
+}
+
+func (p *Push) Validate() error {
+	if p.Token == "" {
+		return ErrNoRecipient
+	}
+
+	if p.Title == "" && p.Body == "" && len(p.Data) == 0 {
+		return ErrNoBody
+	}
+
+	return nil
+}
+
+// SMSSender delivers a text message and returns the ID the provider assigned to it, which delivery
+// status callbacks refer to.
+type SMSSender interface {
+	SendSMS(ctx context.Context, msg *SMS) (string, error)
+}
+
+// PushSender delivers a push notification and returns the ID the provider assigned to it.
+type PushSender interface {
+	SendPush(ctx context.Context, msg *Push) (string, error)
+}
+
+// ProviderError is an error response of a provider.
+type ProviderError struct {
+	Provider   string
+	StatusCode int
+	Code       string
+	Message    string
+	// InvalidRecipient reports that the provider rejected the phone number or device token.
+	InvalidRecipient bool
+}
+
+func (e *ProviderError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("notify: %s returned %d (%s): %s", e.Provider, e.StatusCode, e.Code, e.Message)
+	}
+
+	return fmt.Sprintf("notify: %s returned %d: %s", e.Provider, e.StatusCode, e.Message)
+}
+
+func (e *ProviderError) Unwrap() error {
+	if e.InvalidRecipient {
+		return ErrInvalidRecipient
+	}
+
+	return nil
+}
+
+type Option func(*Notifier)
+
+func WithSMS(sender SMSSender) Option {
+	return func(n *Notifier) {
+		n.sms = sender
+	}
+}
+
+func WithPush(sender PushSender) Option {
+	return func(n *Notifier) {
+		n.push = sender
+	}
+}
+
+func WithTemplates(templates *Templates) Option {
+	return func(n *Notifier) {
+		n.templates = templates
+	}
+}
+
+// Notifier validates messages and sends them through the configured SMS and push senders.
+type Notifier struct {
+	sms       SMSSender
+	push      PushSender
+	templates *Templates
+}
+
+func New(opts ...Option) (*Notifier, error) {
+	n := &Notifier{sms: nil, push: nil, templates: nil}
+
+	for _, opt := range opts {
+		opt(n)
+	}
+
+	if n.sms == nil && n.push == nil {
+		return nil, ErrNoSender
+	}
+
+	return n, nil
+}
+
+// SendSMS sends msg and returns the message ID of the provider.
+func (n *Notifier) SendSMS(ctx context.Context, msg *SMS) (string, error) {
+	if n.sms == nil {
+		return "", ErrNoSMSSender
+	}
+
+	if err := msg.Validate(); err != nil {
+		return "", err
+	}
+
+	id, err := n.sms.SendSMS(ctx, msg)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrSendFailed, err)
+	}
+
+	return id, nil
+}
+
+// SendPush sends msg and returns the message ID of the provider.
+func (n *Notifier) SendPush(ctx context.Context, msg *Push) (string, error) {
+	if n.push == nil {
+		return "", ErrNoPushSender
+	}
+
+	if err := msg.Validate(); err != nil {
+		return "", err
+	}
+
+	id, err := n.push.SendPush(ctx, msg)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrSendFailed, err)
+	}
+
+	return id, nil
+}
+
+// SendSMSTemplate renders the named template into the body of msg and sends it.
+func (n *Notifier) SendSMSTemplate(ctx context.Context, msg *SMS, name string, data any) (string, error) {
+	if err := n.RenderSMS(msg, name, data); err != nil {
+		return "", err
+	}
+
+	return n.SendSMS(ctx, msg)
+}
+
+// SendPushTemplate renders the named template into the title and body of msg and sends it.
+func (n *Notifier) SendPushTemplate(ctx context.Context, msg *Push, name string, data any) (string, error) {
+	if err := n.RenderPush(msg, name, data); err != nil {
+		return "", err
+	}
+
+	return n.SendPush(ctx, msg)
+}
+
+func (n *Notifier) RenderSMS(msg *SMS, name string, data any) error {
+	if n.templates == nil {
+		return ErrNoTemplates
+	}
+
+	return n.templates.RenderSMS(msg, name, data)
+}
+
+func (n *Notifier) RenderPush(msg *Push, name string, data any) error {
+	if n.templates == nil {
+		return ErrNoTemplates
+	}
+
+	return n.templates.RenderPush(msg, name, data)
+}
@@ -0,0 +1,147 @@
+//nolint:exhaustruct
+package notify_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/andyle182810/gframework/notify"
+	"github.com/stretchr/testify/require"
+)
+
+var errProviderDown = errors.New("provider down")
+
+type recordingSender struct {
+	mu     sync.Mutex
+	sms    []*notify.SMS
+	pushes []*notify.Push
+	err    error
+}
+
+func (s *recordingSender) SendSMS(_ context.Context, msg *notify.SMS) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return "", s.err
+	}
+
+	s.sms = append(s.sms, msg)
+
+	return "SM1", nil
+}
+
+func (s *recordingSender) SendPush(_ context.Context, msg *notify.Push) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return "", s.err
+	}
+
+	s.pushes = append(s.pushes, msg)
+
+	return "push-1", nil
+}
+
+func (s *recordingSender) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.sms) + len(s.pushes)
+}
+
+func TestNotifier(t *testing.T) {
+	t.Parallel()
+
+	t.Run("requires a sender", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := notify.New()
+		require.ErrorIs(t, err, notify.ErrNoSender)
+	})
+
+	t.Run("sends through the configured senders", func(t *testing.T) {
+		t.Parallel()
+
+		sender := &recordingSender{}
+
+		n, err := notify.New(notify.WithSMS(sender))
+		require.NoError(t, err)
+
+		id, err := n.SendSMS(t.Context(), &notify.SMS{To: "+14155550100", Body: "hi"})
+		require.NoError(t, err)
+		require.Equal(t, "SM1", id)
+
+		_, err = n.SendPush(t.Context(), &notify.Push{Token: "device", Body: "hi"})
+		require.ErrorIs(t, err, notify.ErrNoPushSender)
+	})
+
+	t.Run("validates messages", func(t *testing.T) {
+		t.Parallel()
+
+		sender := &recordingSender{}
+
+		n, err := notify.New(notify.WithSMS(sender), notify.WithPush(sender))
+		require.NoError(t, err)
+
+		_, err = n.SendSMS(t.Context(), &notify.SMS{Body: "hi"})
+		require.ErrorIs(t, err, notify.ErrNoRecipient)
+
+		_, err = n.SendPush(t.Context(), &notify.Push{Token: "device"})
+		require.ErrorIs(t, err, notify.ErrNoBody)
+
+		_, err = n.SendPush(t.Context(), &notify.Push{Token: "device", Data: map[string]string{"sync": "1"}})
+		require.NoError(t, err, "data-only pushes are silent notifications")
+		require.Equal(t, 1, sender.count())
+	})
+
+	t.Run("wraps provider errors", func(t *testing.T) {
+		t.Parallel()
+
+		n, err := notify.New(notify.WithSMS(&recordingSender{err: errProviderDown}))
+		require.NoError(t, err)
+
+		_, err = n.SendSMS(t.Context(), &notify.SMS{To: "+14155550100", Body: "hi"})
+		require.ErrorIs(t, err, notify.ErrSendFailed)
+		require.ErrorIs(t, err, errProviderDown)
+	})
+}
+
+func TestTemplates(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{
+		"_brand.tmpl":     {Data: []byte(`{{define "brand"}}Acme{{end}}`)},
+		"login_code.tmpl": {Data: []byte("{{template \"brand\"}}: your code is {{.}}\n")},
+		"order_shipped.tmpl": {Data: []byte(
+			`{{define "title"}}Order {{.ID}} shipped{{end}}Arriving {{.ETA}}`,
+		)},
+	}
+
+	templates, err := notify.ParseTemplates(fsys)
+	require.NoError(t, err)
+
+	sender := &recordingSender{}
+
+	n, err := notify.New(notify.WithSMS(sender), notify.WithPush(sender), notify.WithTemplates(templates))
+	require.NoError(t, err)
+
+	_, err = n.SendSMSTemplate(t.Context(), &notify.SMS{To: "+14155550100"}, "login_code", "123456")
+	require.NoError(t, err)
+	require.Equal(t, "Acme: your code is 123456", sender.sms[0].Body)
+
+	push := &notify.Push{Token: "device"}
+	require.NoError(t, n.RenderPush(push, "order_shipped", map[string]string{"ID": "42", "ETA": "tomorrow"}))
+	require.Equal(t, "Order 42 shipped", push.Title)
+	require.Equal(t, "Arriving tomorrow", push.Body)
+
+	require.ErrorIs(t, n.RenderSMS(&notify.SMS{}, "missing", nil), notify.ErrTemplateNotFound)
+
+	withoutTemplates, err := notify.New(notify.WithSMS(sender))
+	require.NoError(t, err)
+	require.ErrorIs(t, withoutTemplates.RenderSMS(&notify.SMS{}, "login_code", nil), notify.ErrNoTemplates)
+}
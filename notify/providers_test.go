@@ -0,0 +1,208 @@
+//nolint:exhaustruct
+package notify_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/andyle182810/gframework/notify"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTwilioSender(t *testing.T) {
+	t.Parallel()
+
+	var form url.Values
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, _ := r.BasicAuth()
+		if r.URL.Path != "/2010-04-01/Accounts/AC123/Messages.json" || user != "AC123" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		_ = r.ParseForm()
+		form = r.PostForm
+
+		if r.PostForm.Get("To") == "+1000" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":21211,"message":"The 'To' number is not a valid phone number.","status":400}`))
+
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"sid":"SM1","status":"queued"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	sender, err := notify.NewTwilio(notify.TwilioConfig{
+		AccountSID:        "AC123",
+		AuthToken:         "secret",
+		From:              "+15550100",
+		StatusCallbackURL: "https://example.com/callbacks/twilio",
+		BaseURL:           server.URL,
+	})
+	require.NoError(t, err)
+
+	id, err := sender.SendSMS(t.Context(), &notify.SMS{To: "+14155550100", Body: "hi"})
+	require.NoError(t, err)
+	require.Equal(t, "SM1", id)
+	require.Equal(t, "+15550100", form.Get("From"))
+	require.Equal(t, "hi", form.Get("Body"))
+	require.Equal(t, "https://example.com/callbacks/twilio", form.Get("StatusCallback"))
+
+	_, err = sender.SendSMS(t.Context(), &notify.SMS{To: "+1000", Body: "hi"})
+	require.ErrorIs(t, err, notify.ErrInvalidRecipient)
+
+	var providerErr *notify.ProviderError
+	require.ErrorAs(t, err, &providerErr)
+	require.Equal(t, "21211", providerErr.Code)
+
+	_, err = notify.NewTwilio(notify.TwilioConfig{AccountSID: "AC123", AuthToken: "secret"})
+	require.ErrorIs(t, err, notify.ErrTwilioConfig)
+}
+
+type staticTokens struct {
+	invalidated atomic.Int32
+}
+
+func (s *staticTokens) GetToken(_ context.Context) (string, error) { return "access-token", nil }
+
+func (s *staticTokens) InvalidateToken() { s.invalidated.Add(1) }
+
+func TestFCMSender(t *testing.T) {
+	t.Parallel()
+
+	var request map[string]map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/projects/my-app/messages:send" || r.Header.Get("Authorization") != "Bearer access-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		_ = json.NewDecoder(r.Body).Decode(&request)
+
+		if request["message"]["token"] == "stale" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"code":404,"message":"Requested entity was not found.","status":"NOT_FOUND",` +
+				`"details":[{"@type":"type.googleapis.com/google.firebase.fcm.v1.FcmError","errorCode":"UNREGISTERED"}]}}`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"name":"projects/my-app/messages/1"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	tokens := &staticTokens{}
+
+	sender, err := notify.NewFCM(notify.FCMConfig{ProjectID: "my-app", Tokens: tokens, BaseURL: server.URL})
+	require.NoError(t, err)
+
+	badge := 3
+
+	id, err := sender.SendPush(t.Context(), &notify.Push{
+		Token: "device", Title: "Hello", Body: "World", Data: map[string]string{"orderId": "42"}, Badge: &badge,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "projects/my-app/messages/1", id)
+	require.Equal(t, map[string]any{"title": "Hello", "body": "World"}, request["message"]["notification"])
+	require.Equal(t, map[string]any{"orderId": "42"}, request["message"]["data"])
+	require.Equal(t,
+		map[string]any{"payload": map[string]any{"aps": map[string]any{"badge": float64(3)}}},
+		request["message"]["apns"],
+	)
+
+	_, err = sender.SendPush(t.Context(), &notify.Push{Token: "stale", Body: "hi"})
+	require.ErrorIs(t, err, notify.ErrInvalidRecipient)
+
+	_, err = notify.NewFCM(notify.FCMConfig{ProjectID: "my-app"})
+	require.ErrorIs(t, err, notify.ErrFCMConfig)
+}
+
+func TestAPNsSender(t *testing.T) {
+	t.Parallel()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	var (
+		headers http.Header
+		payload map[string]any
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+
+		token, err := jwt.Parse(strings.TrimPrefix(r.Header.Get("Authorization"), "bearer "), func(*jwt.Token) (any, error) {
+			return &key.PublicKey, nil
+		}, jwt.WithValidMethods([]string{"ES256"}))
+		if err != nil || token.Header["kid"] != "KEY123" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"reason":"InvalidProviderToken"}`))
+
+			return
+		}
+
+		if r.URL.Path == "/3/device/gone" {
+			w.WriteHeader(http.StatusGone)
+			_, _ = w.Write([]byte(`{"reason":"Unregistered"}`))
+
+			return
+		}
+
+		w.Header().Set("Apns-Id", "apns-1")
+	}))
+	t.Cleanup(server.Close)
+
+	sender, err := notify.NewAPNs(notify.APNsConfig{
+		KeyID:      "KEY123",
+		TeamID:     "TEAM123",
+		PrivateKey: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}),
+		Topic:      "com.example.app",
+		BaseURL:    server.URL,
+	})
+	require.NoError(t, err)
+
+	id, err := sender.SendPush(t.Context(), &notify.Push{
+		Token: "device", Title: "Hello", Body: "World", Data: map[string]string{"orderId": "42"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "apns-1", id)
+	require.Equal(t, "com.example.app", headers.Get("Apns-Topic"))
+	require.Equal(t, "alert", headers.Get("Apns-Push-Type"))
+	require.Equal(t, "42", payload["orderId"])
+	require.Equal(t, map[string]any{"alert": map[string]any{"title": "Hello", "body": "World"}}, payload["aps"])
+
+	_, err = sender.SendPush(t.Context(), &notify.Push{Token: "device", Data: map[string]string{"sync": "1"}})
+	require.NoError(t, err)
+	require.Equal(t, "background", headers.Get("Apns-Push-Type"))
+
+	_, err = sender.SendPush(t.Context(), &notify.Push{Token: "gone", Body: "hi"})
+	require.ErrorIs(t, err, notify.ErrInvalidRecipient)
+
+	_, err = notify.NewAPNs(notify.APNsConfig{
+		KeyID: "KEY123", TeamID: "TEAM123", Topic: "com.example.app", PrivateKey: []byte("bad"),
+	})
+	require.ErrorIs(t, err, notify.ErrAPNsConfig)
+}
@@ -0,0 +1,246 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/andyle182810/gframework/taskqueue"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+const defaultQueueMaxAttempts = 5
+
+var ErrNilNotifier = errors.New("notify: notifier is nil")
+
+type QueueOption func(*queueOptions)
+
+type queueOptions struct {
+	maxAttempts  int
+	queueOptions []taskqueue.Option
+}
+
+// WithMaxAttempts sets how many times a message is sent before it is moved to the dead-letter list.
+// It defaults to 5.
+func WithMaxAttempts(attempts int) QueueOption {
+	return func(o *queueOptions) {
+		if attempts > 0 {
+			o.maxAttempts = attempts
+		}
+	}
+}
+
+// WithQueueOptions configures the underlying task queue, e.g. its worker count and exec timeout.
+func WithQueueOptions(opts ...taskqueue.Option) QueueOption {
+	return func(o *queueOptions) {
+		o.queueOptions = append(o.queueOptions, opts...)
+	}
+}
+
+// Job is a message in the queue. Exactly one of SMS and Push is set.
+type Job struct {
+	Attempt int   `json:"attempt"`
+	SMS     *SMS  `json:"sms,omitempty"`
+	Push    *Push `json:"push,omitempty"`
+}
+
+// DeadLetter is a job that was given up on, with the error of its last attempt.
+type DeadLetter struct {
+	Job
+
+	Error    string    `json:"error"`
+	FailedAt time.Time `json:"failedAt"`
+}
+
+// Queue sends messages in the background from a taskqueue.Queue. A message whose send fails is
+// enqueued again until it has been attempted the configured number of times, then it is pushed to the
+// dead-letter list "<queueKey>:dead". Messages rejected for an invalid recipient are dead-lettered
+// without further attempts. It is a runner service, started and stopped with the queue.
+type Queue struct {
+	*taskqueue.Queue
+
+	client      redis.UniversalClient
+	deadKey     string
+	notifier    *Notifier
+	maxAttempts int
+}
+
+var _ taskqueue.Executor = (*Queue)(nil)
+
+func NewQueue(client redis.UniversalClient, queueKey string, notifier *Notifier, opts ...QueueOption) (*Queue, error) {
+	if notifier == nil {
+		return nil, ErrNilNotifier
+	}
+
+	options := &queueOptions{maxAttempts: defaultQueueMaxAttempts, queueOptions: nil}
+
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	queue := &Queue{
+		Queue:       nil,
+		client:      client,
+		deadKey:     queueKey + ":dead",
+		notifier:    notifier,
+		maxAttempts: options.maxAttempts,
+	}
+
+	tasks, err := taskqueue.New(client, queueKey, queue, options.queueOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	queue.Queue = tasks
+
+	return queue, nil
+}
+
+func (q *Queue) Name() string {
+	return "notify-queue"
+}
+
+// EnqueueSMS validates msg and enqueues it for sending.
+func (q *Queue) EnqueueSMS(ctx context.Context, msg *SMS) error {
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+
+	return q.push(ctx, Job{Attempt: 1, SMS: msg, Push: nil})
+}
+
+// EnqueuePush validates msg and enqueues it for sending.
+func (q *Queue) EnqueuePush(ctx context.Context, msg *Push) error {
+	if err := msg.Validate(); err != nil {
+		return err
+	}
+
+	return q.push(ctx, Job{Attempt: 1, SMS: nil, Push: msg})
+}
+
+func (q *Queue) Execute(ctx context.Context, taskID string, payload taskqueue.Payload) error {
+	var job Job
+	if err := json.Unmarshal(payload, &job); err != nil || (job.SMS == nil) == (job.Push == nil) {
+		log.Error().Str("source", "gframework").Err(err).Str("task_id", taskID).Msg("Dropping malformed notification job")
+
+		return nil
+	}
+
+	var err error
+
+	if job.SMS != nil {
+		_, err = q.notifier.SendSMS(ctx, job.SMS)
+	} else {
+		_, err = q.notifier.SendPush(ctx, job.Push)
+	}
+
+	if err == nil {
+		return nil
+	}
+
+	// Enqueueing and requeueing must not be skipped because the exec timeout has passed.
+	ctx = context.WithoutCancel(ctx)
+
+	if job.Attempt >= q.maxAttempts || !errors.Is(err, ErrSendFailed) || errors.Is(err, ErrInvalidRecipient) {
+		if deadErr := q.deadLetter(ctx, job, err); deadErr != nil {
+			return fmt.Errorf("%w (dead-letter failed: %w)", err, deadErr)
+		}
+
+		return err
+	}
+
+	job.Attempt++
+
+	// The retry gets a new task ID because the queue deletes the payload of this one once it returns.
+	if pushErr := q.push(ctx, job); pushErr != nil {
+		return fmt.Errorf("%w (requeue failed: %w)", err, pushErr)
+	}
+
+	return err
+}
+
+// DeadLetters returns up to limit dead letters, newest first.
+func (q *Queue) DeadLetters(ctx context.Context, limit int64) ([]DeadLetter, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	entries, err := q.client.LRange(ctx, q.deadKey, 0, limit-1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("notify: failed to read dead letters: %w", err)
+	}
+
+	letters := make([]DeadLetter, 0, len(entries))
+
+	for _, entry := range entries {
+		var letter DeadLetter
+		if err := json.Unmarshal([]byte(entry), &letter); err != nil {
+			return nil, fmt.Errorf("notify: failed to decode dead letter: %w", err)
+		}
+
+		letters = append(letters, letter)
+	}
+
+	return letters, nil
+}
+
+// RetryDeadLetters moves the dead letters back to the queue with their attempts reset, oldest first,
+// e.g. after a provider outage has been resolved. It returns the number of messages requeued.
+func (q *Queue) RetryDeadLetters(ctx context.Context) (int, error) {
+	retried := 0
+
+	for {
+		entry, err := q.client.RPop(ctx, q.deadKey).Result()
+		if errors.Is(err, redis.Nil) {
+			return retried, nil
+		}
+
+		if err != nil {
+			return retried, fmt.Errorf("notify: failed to pop dead letter: %w", err)
+		}
+
+		var letter DeadLetter
+		if err := json.Unmarshal([]byte(entry), &letter); err != nil {
+			return retried, fmt.Errorf("notify: failed to decode dead letter: %w", err)
+		}
+
+		letter.Attempt = 1
+
+		if err := q.push(ctx, letter.Job); err != nil {
+			_ = q.client.RPush(ctx, q.deadKey, entry).Err()
+
+			return retried, err
+		}
+
+		retried++
+	}
+}
+
+func (q *Queue) deadLetter(ctx context.Context, job Job, cause error) error {
+	log.Error().
+		Str("source", "gframework").
+		Err(cause).
+		Int("attempts", job.Attempt).
+		Str("dead_letter_key", q.deadKey).
+		Msg("Moving notification to dead-letter list")
+
+	entry, err := json.Marshal(DeadLetter{Job: job, Error: cause.Error(), FailedAt: time.Now().UTC()})
+	if err != nil {
+		return fmt.Errorf("notify: failed to encode dead letter: %w", err)
+	}
+
+	return q.client.LPush(ctx, q.deadKey, entry).Err()
+}
+
+func (q *Queue) push(ctx context.Context, job Job) error {
+	payload, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("notify: failed to encode job: %w", err)
+	}
+
+	return q.Queue.Push(ctx, taskqueue.Task{ID: uuid.NewString(), Payload: payload})
+}
@@ -0,0 +1,125 @@
+//nolint:exhaustruct
+package notify_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/notify"
+	"github.com/andyle182810/gframework/taskqueue"
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+// flakySender fails the first failures sends with err.
+type flakySender struct {
+	recordingSender
+
+	failures int32
+	failWith error
+	calls    atomic.Int32
+}
+
+func (s *flakySender) SendSMS(ctx context.Context, msg *notify.SMS) (string, error) {
+	if s.calls.Add(1) <= s.failures {
+		return "", s.failWith
+	}
+
+	return s.recordingSender.SendSMS(ctx, msg)
+}
+
+func startQueue(t *testing.T, sender notify.SMSSender, opts ...notify.QueueOption) *notify.Queue {
+	t.Helper()
+
+	valkeyClient := testutil.SetupValkey(t)
+
+	n, err := notify.New(notify.WithSMS(sender))
+	require.NoError(t, err)
+
+	opts = append(opts, notify.WithQueueOptions(taskqueue.WithPollInterval(10*time.Millisecond)))
+
+	queue, err := notify.NewQueue(valkeyClient.Client, "notify:"+t.Name(), n, opts...)
+	require.NoError(t, err)
+
+	go func() { _ = queue.Start(t.Context()) }()
+
+	t.Cleanup(func() { _ = queue.Stop() })
+
+	return queue
+}
+
+func TestQueue_RetriesFailedSends(t *testing.T) {
+	t.Parallel()
+
+	sender := &flakySender{failures: 2, failWith: errProviderDown}
+	queue := startQueue(t, sender)
+
+	require.Equal(t, "notify-queue", queue.Name())
+	require.NoError(t, queue.EnqueueSMS(t.Context(), &notify.SMS{To: "+14155550100", Body: "hi"}))
+
+	require.Eventually(t, func() bool { return sender.count() == 1 }, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, int32(3), sender.calls.Load())
+}
+
+func TestQueue_DeadLettersAndRetries(t *testing.T) {
+	t.Parallel()
+
+	sender := &flakySender{failures: 2, failWith: errProviderDown}
+	queue := startQueue(t, sender, notify.WithMaxAttempts(2))
+
+	require.NoError(t, queue.EnqueueSMS(t.Context(), &notify.SMS{To: "+14155550100", Body: "hi"}))
+
+	var letters []notify.DeadLetter
+
+	require.Eventually(t, func() bool {
+		var err error
+		letters, err = queue.DeadLetters(t.Context(), 10)
+
+		return err == nil && len(letters) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.Equal(t, 2, letters[0].Attempt)
+	require.Equal(t, "+14155550100", letters[0].SMS.To)
+	require.Contains(t, letters[0].Error, "provider down")
+
+	retried, err := queue.RetryDeadLetters(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, 1, retried)
+
+	require.Eventually(t, func() bool { return sender.count() == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	letters, err = queue.DeadLetters(t.Context(), 10)
+	require.NoError(t, err)
+	require.Empty(t, letters)
+}
+
+func TestQueue_DeadLettersInvalidRecipientsImmediately(t *testing.T) {
+	t.Parallel()
+
+	sender := &flakySender{failures: 100, failWith: &notify.ProviderError{Provider: "twilio", InvalidRecipient: true}}
+	queue := startQueue(t, sender)
+
+	require.NoError(t, queue.EnqueueSMS(t.Context(), &notify.SMS{To: "+1000", Body: "hi"}))
+
+	require.Eventually(t, func() bool {
+		letters, err := queue.DeadLetters(t.Context(), 10)
+
+		return err == nil && len(letters) == 1 && letters[0].Attempt == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.Equal(t, int32(1), sender.calls.Load())
+}
+
+func TestQueue_Validation(t *testing.T) {
+	t.Parallel()
+
+	queue := startQueue(t, &recordingSender{})
+
+	require.ErrorIs(t, queue.EnqueueSMS(t.Context(), &notify.SMS{Body: "hi"}), notify.ErrNoRecipient)
+	require.ErrorIs(t, queue.EnqueuePush(t.Context(), &notify.Push{Token: "device"}), notify.ErrNoBody)
+
+	_, err := notify.NewQueue(nil, "notify", nil)
+	require.ErrorIs(t, err, notify.ErrNilNotifier)
+}
@@ -0,0 +1,136 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1" //nolint:gosec
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/andyle182810/gframework/httpserver"
+	"github.com/labstack/echo/v5"
+)
+
+const headerTwilioSignature = "X-Twilio-Signature"
+
+var ErrStatusSignatureInvalid = errors.New("notify: status callback signature is invalid")
+
+type Status string
+
+const (
+	StatusQueued      Status = "queued"
+	StatusSent        Status = "sent"
+	StatusDelivered   Status = "delivered"
+	StatusUndelivered Status = "undelivered"
+	StatusFailed      Status = "failed"
+	StatusRead        Status = "read"
+)
+
+// DeliveryStatus is a delivery status update of a message sent earlier, identified by the message ID
+// its sender returned.
+type DeliveryStatus struct {
+	Provider  string
+	MessageID string
+	Recipient string
+	Status    Status
+	// ErrorCode is the provider error code of undelivered and failed messages.
+	ErrorCode string
+}
+
+// StatusFunc handles a delivery status update. Returning an error responds with 500 so the provider
+// retries the callback.
+type StatusFunc func(ctx context.Context, status DeliveryStatus) error
+
+type statusConfig struct {
+	callbackURL string
+}
+
+type StatusOption func(*statusConfig)
+
+// WithCallbackURL sets the public URL the provider calls, which the signature covers. Set it when the
+// service runs behind a proxy that changes the scheme, host or path; by default the URL is rebuilt
+// from the request.
+func WithCallbackURL(callbackURL string) StatusOption {
+	return func(c *statusConfig) {
+		c.callbackURL = callbackURL
+	}
+}
+
+// TwilioStatusHandler handles the status callbacks Twilio posts to TwilioConfig.StatusCallbackURL.
+// Requests without a valid X-Twilio-Signature for authToken are rejected with 403.
+//
+//	server.Root.POST("/callbacks/twilio", notify.TwilioStatusHandler(authToken, handleStatus))
+func TwilioStatusHandler(authToken string, handle StatusFunc, opts ...StatusOption) echo.HandlerFunc {
+	cfg := statusConfig{callbackURL: ""}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return func(c *echo.Context) error {
+		if err := c.Request().ParseForm(); err != nil {
+			return httpserver.BadRequestError(err)
+		}
+
+		form := c.Request().PostForm
+
+		callbackURL := cfg.callbackURL
+		if callbackURL == "" {
+			callbackURL = c.Scheme() + "://" + c.Request().Host + c.Request().URL.RequestURI()
+		}
+
+		if !validTwilioSignature(authToken, callbackURL, form, c.Request().Header.Get(headerTwilioSignature)) {
+			return httpserver.HTTPError(http.StatusForbidden, ErrStatusSignatureInvalid)
+		}
+
+		status := DeliveryStatus{
+			Provider:  "twilio",
+			MessageID: form.Get("MessageSid"),
+			Recipient: form.Get("To"),
+			Status:    Status(form.Get("MessageStatus")),
+			ErrorCode: form.Get("ErrorCode"),
+		}
+
+		if err := handle(c.Request().Context(), status); err != nil {
+			return httpserver.InternalError(err, "Failed to handle delivery status")
+		}
+
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// validTwilioSignature checks signature against the HMAC-SHA1 of the URL followed by the sorted form
+// parameters and their values.
+func validTwilioSignature(authToken, callbackURL string, form url.Values, signature string) bool {
+	if signature == "" {
+		return false
+	}
+
+	keys := make([]string, 0, len(form))
+	for key := range form {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	var data strings.Builder
+
+	data.WriteString(callbackURL)
+
+	for _, key := range keys {
+		for _, value := range form[key] {
+			data.WriteString(key + value)
+		}
+	}
+
+	mac := hmac.New(sha1.New, []byte(authToken))
+	mac.Write([]byte(data.String()))
+
+	expected := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
@@ -0,0 +1,107 @@
+//nolint:exhaustruct
+package notify_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1" //nolint:gosec
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/andyle182810/gframework/notify"
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func twilioSignature(authToken, callbackURL string, form url.Values) string {
+	mac := hmac.New(sha1.New, []byte(authToken))
+	mac.Write([]byte(callbackURL + "ErrorCode" + form.Get("ErrorCode") + "MessageSid" + form.Get("MessageSid") +
+		"MessageStatus" + form.Get("MessageStatus") + "To" + form.Get("To")))
+
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestTwilioStatusHandler(t *testing.T) {
+	t.Parallel()
+
+	form := url.Values{
+		"MessageSid":    {"SM1"},
+		"MessageStatus": {"undelivered"},
+		"To":            {"+14155550100"},
+		"ErrorCode":     {"30003"},
+	}
+
+	tests := []struct {
+		name        string
+		callbackURL string
+		signature   string
+		wantCode    int
+	}{
+		{
+			name:      "valid signature",
+			signature: twilioSignature("secret", "http://example.com/callbacks/twilio?tenant=1", form),
+			wantCode:  http.StatusNoContent,
+		},
+		{
+			name:        "valid signature behind a proxy",
+			callbackURL: "https://api.example.com/sms/status",
+			signature:   twilioSignature("secret", "https://api.example.com/sms/status", form),
+			wantCode:    http.StatusNoContent,
+		},
+		{name: "missing signature", wantCode: http.StatusForbidden},
+		{
+			name:      "wrong token",
+			signature: twilioSignature("other", "http://example.com/callbacks/twilio?tenant=1", form),
+			wantCode:  http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var received []notify.DeliveryStatus
+
+			var opts []notify.StatusOption
+			if tt.callbackURL != "" {
+				opts = append(opts, notify.WithCallbackURL(tt.callbackURL))
+			}
+
+			handler := notify.TwilioStatusHandler("secret", func(_ context.Context, status notify.DeliveryStatus) error {
+				received = append(received, status)
+
+				return nil
+			}, opts...)
+
+			e := echo.New()
+			e.POST("/callbacks/twilio", handler)
+
+			req := httptest.NewRequest(http.MethodPost, "http://example.com/callbacks/twilio?tenant=1", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.Header.Set("X-Twilio-Signature", tt.signature)
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantCode, rec.Code)
+
+			if tt.wantCode != http.StatusNoContent {
+				require.Empty(t, received)
+
+				return
+			}
+
+			require.Equal(t, []notify.DeliveryStatus{{
+				Provider:  "twilio",
+				MessageID: "SM1",
+				Recipient: "+14155550100",
+				Status:    notify.StatusUndelivered,
+				ErrorCode: "30003",
+			}}, received)
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"strings"
+	"text/template"
+)
+
+const (
+	templateExt   = ".tmpl"
+	titleTemplate = "title"
+)
+
+// Templates holds the message templates of a directory, parsed with text/template. A template named
+// "login_code" is read from "login_code.tmpl" and renders the message body; for push notifications it
+// may define a "title" template for the title. Files whose name starts with "_" are partials shared by
+// all templates.
+type Templates struct {
+	templates map[string]*template.Template
+}
+
+// ParseTemplates parses the templates at the root of fsys, e.g. an embed.FS or os.DirFS.
+func ParseTemplates(fsys fs.FS) (*Templates, error) {
+	partials, err := fs.Glob(fsys, "_*"+templateExt)
+	if err != nil {
+		return nil, fmt.Errorf("notify: failed to list templates: %w", err)
+	}
+
+	files, err := fs.Glob(fsys, "*"+templateExt)
+	if err != nil {
+		return nil, fmt.Errorf("notify: failed to list templates: %w", err)
+	}
+
+	templates := &Templates{templates: make(map[string]*template.Template)}
+
+	for _, file := range files {
+		if strings.HasPrefix(file, "_") {
+			continue
+		}
+
+		tmpl, err := template.New(file).ParseFS(fsys, append([]string{file}, partials...)...)
+		if err != nil {
+			return nil, fmt.Errorf("notify: failed to parse template %s: %w", file, err)
+		}
+
+		templates.templates[strings.TrimSuffix(file, templateExt)] = tmpl
+	}
+
+	return templates, nil
+}
+
+// RenderSMS executes the named template with data and sets the body of msg.
+func (t *Templates) RenderSMS(msg *SMS, name string, data any) error {
+	tmpl, err := t.lookup(name)
+	if err != nil {
+		return err
+	}
+
+	body, err := execute(tmpl, tmpl.Name(), data)
+	if err != nil {
+		return err
+	}
+
+	msg.Body = body
+
+	return nil
+}
+
+// RenderPush executes the named template with data and sets the body of msg, and its title when the
+// template defines one.
+func (t *Templates) RenderPush(msg *Push, name string, data any) error {
+	tmpl, err := t.lookup(name)
+	if err != nil {
+		return err
+	}
+
+	body, err := execute(tmpl, tmpl.Name(), data)
+	if err != nil {
+		return err
+	}
+
+	msg.Body = body
+
+	if tmpl.Lookup(titleTemplate) != nil {
+		title, err := execute(tmpl, titleTemplate, data)
+		if err != nil {
+			return err
+		}
+
+		msg.Title = title
+	}
+
+	return nil
+}
+
+func (t *Templates) lookup(name string) (*template.Template, error) {
+	tmpl, ok := t.templates[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+
+	return tmpl, nil
+}
+
+func execute(tmpl *template.Template, name string, data any) (string, error) {
+	var buf bytes.Buffer
+
+	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", fmt.Errorf("notify: failed to render template %s: %w", name, err)
+	}
+
+	return strings.TrimSpace(buf.String()), nil
+}
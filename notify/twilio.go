@@ -0,0 +1,151 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTwilioBaseURL = "https://api.twilio.com"
+	defaultHTTPTimeout   = 30 * time.Second
+	maxResponseSize      = 1 << 20
+)
+
+var ErrTwilioConfig = errors.New("notify: invalid twilio configuration")
+
+// Twilio error codes for numbers that cannot receive messages.
+//
+//nolint:gochecknoglobals
+var twilioInvalidRecipientCodes = []int{21211, 21408, 21610, 21612, 21614}
+
+type TwilioConfig struct {
+	AccountSID string
+	AuthToken  string
+	// From is the sender phone number. Either From or MessagingServiceSID is required.
+	From                string
+	MessagingServiceSID string
+	// StatusCallbackURL is where the provider posts delivery status updates, see TwilioStatusHandler.
+	StatusCallbackURL string
+	// BaseURL targets a Twilio-compatible API instead of Twilio, e.g. a test server.
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+// TwilioSender sends SMS through the Twilio Messages API or an API compatible with it.
+type TwilioSender struct {
+	cfg TwilioConfig
+}
+
+var _ SMSSender = (*TwilioSender)(nil)
+
+func NewTwilio(cfg TwilioConfig) (*TwilioSender, error) {
+	if cfg.AccountSID == "" || cfg.AuthToken == "" {
+		return nil, fmt.Errorf("%w: account sid and auth token are required", ErrTwilioConfig)
+	}
+
+	if cfg.From == "" && cfg.MessagingServiceSID == "" {
+		return nil, fmt.Errorf("%w: from or messaging service sid is required", ErrTwilioConfig)
+	}
+
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = defaultTwilioBaseURL
+	}
+
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: defaultHTTPTimeout} //nolint:exhaustruct
+	}
+
+	cfg.BaseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+
+	return &TwilioSender{cfg: cfg}, nil
+}
+
+type twilioResponse struct {
+	SID     string `json:"sid"`
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (s *TwilioSender) SendSMS(ctx context.Context, msg *SMS) (string, error) {
+	form := url.Values{}
+	form.Set("To", msg.To)
+	form.Set("Body", msg.Body)
+
+	switch {
+	case msg.From != "":
+		form.Set("From", msg.From)
+	case s.cfg.MessagingServiceSID != "":
+		form.Set("MessagingServiceSid", s.cfg.MessagingServiceSID)
+	default:
+		form.Set("From", s.cfg.From)
+	}
+
+	if s.cfg.StatusCallbackURL != "" {
+		form.Set("StatusCallback", s.cfg.StatusCallbackURL)
+	}
+
+	endpoint := s.cfg.BaseURL + "/2010-04-01/Accounts/" + url.PathEscape(s.cfg.AccountSID) + "/Messages.json"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("notify: failed to create twilio request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(s.cfg.AccountSID, s.cfg.AuthToken)
+
+	resp, body, err := do(s.cfg.HTTPClient, req)
+	if err != nil {
+		return "", err
+	}
+
+	var decoded twilioResponse
+	_ = json.Unmarshal(body, &decoded)
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		providerErr := &ProviderError{
+			Provider:         "twilio",
+			StatusCode:       resp.StatusCode,
+			Code:             "",
+			Message:          decoded.Message,
+			InvalidRecipient: slices.Contains(twilioInvalidRecipientCodes, decoded.Code),
+		}
+
+		if decoded.Code != 0 {
+			providerErr.Code = strconv.Itoa(decoded.Code)
+		}
+
+		if providerErr.Message == "" {
+			providerErr.Message = string(body)
+		}
+
+		return "", providerErr
+	}
+
+	return decoded.SID, nil
+}
+
+// do sends req and reads the response body, up to maxResponseSize bytes.
+func do(client *http.Client, req *http.Request) (*http.Response, []byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("notify: request to %s failed: %w", req.URL.Host, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, nil, fmt.Errorf("notify: failed to read response from %s: %w", req.URL.Host, err)
+	}
+
+	return resp, body, nil
+}
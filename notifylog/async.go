@@ -0,0 +1,312 @@
+package notifylog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/andyle182810/gframework/httpclient"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultQueueSize       = 1000
+	defaultBatchSize       = 20
+	defaultFlushInterval   = time.Second
+	defaultMaxAttempts     = 3
+	defaultInitialBackoff  = time.Second
+	defaultMaxBackoff      = 30 * time.Second
+	defaultShutdownTimeout = 5 * time.Second
+)
+
+var (
+	ErrQueueFull      = errors.New("notifylog: queue is full")
+	ErrAlreadyRunning = errors.New("notifylog: async notifier is already running")
+)
+
+type DropPolicy int
+
+const (
+	// DropNewest rejects new messages while the queue is full.
+	DropNewest DropPolicy = iota
+	// DropOldest discards the oldest queued message to make room for the new one.
+	DropOldest
+)
+
+type AsyncOption func(*Async)
+
+// WithQueueSize bounds the number of messages waiting to be sent.
+func WithQueueSize(size int) AsyncOption {
+	return func(a *Async) {
+		if size > 0 {
+			a.queueSize = size
+		}
+	}
+}
+
+// WithBatch flushes the queue once size messages are pending or every interval, whichever comes first.
+func WithBatch(size int, interval time.Duration) AsyncOption {
+	return func(a *Async) {
+		if size > 0 {
+			a.batchSize = size
+		}
+
+		if interval > 0 {
+			a.flushInterval = interval
+		}
+	}
+}
+
+// WithRetry retries failed sends with exponential backoff. Client errors other than 429 are not retried.
+func WithRetry(maxAttempts int, initialBackoff, maxBackoff time.Duration) AsyncOption {
+	return func(a *Async) {
+		if maxAttempts > 0 {
+			a.maxAttempts = maxAttempts
+		}
+
+		if initialBackoff > 0 {
+			a.initialBackoff = initialBackoff
+		}
+
+		if maxBackoff >= a.initialBackoff {
+			a.maxBackoff = maxBackoff
+		}
+	}
+}
+
+func WithDropPolicy(policy DropPolicy) AsyncOption {
+	return func(a *Async) {
+		a.dropPolicy = policy
+	}
+}
+
+// WithShutdownTimeout bounds how long Stop keeps sending the queued messages.
+func WithShutdownTimeout(timeout time.Duration) AsyncOption {
+	return func(a *Async) {
+		if timeout > 0 {
+			a.shutdownTimeout = timeout
+		}
+	}
+}
+
+// Async queues messages and sends them from a background worker, so that a slow or unavailable
+// platform never blocks the caller. It implements runner.Service; queued messages are sent once it
+// is started, and Stop sends the remaining ones within the shutdown timeout.
+type Async struct {
+	notifier        Notifier
+	queueSize       int
+	batchSize       int
+	flushInterval   time.Duration
+	maxAttempts     int
+	initialBackoff  time.Duration
+	maxBackoff      time.Duration
+	dropPolicy      DropPolicy
+	shutdownTimeout time.Duration
+
+	queue   chan Message
+	running atomic.Bool
+	mu      sync.Mutex
+	stop    chan struct{}
+	done    chan struct{}
+	dropped atomic.Uint64
+	failed  atomic.Uint64
+}
+
+var _ Notifier = (*Async)(nil)
+
+func NewAsync(notifier Notifier, opts ...AsyncOption) *Async {
+	async := &Async{
+		notifier:        notifier,
+		queueSize:       defaultQueueSize,
+		batchSize:       defaultBatchSize,
+		flushInterval:   defaultFlushInterval,
+		maxAttempts:     defaultMaxAttempts,
+		initialBackoff:  defaultInitialBackoff,
+		maxBackoff:      defaultMaxBackoff,
+		dropPolicy:      DropNewest,
+		shutdownTimeout: defaultShutdownTimeout,
+		queue:           nil,
+		running:         atomic.Bool{},
+		mu:              sync.Mutex{},
+		stop:            nil,
+		done:            nil,
+		dropped:         atomic.Uint64{},
+		failed:          atomic.Uint64{},
+	}
+
+	for _, opt := range opts {
+		opt(async)
+	}
+
+	async.queue = make(chan Message, async.queueSize)
+
+	return async
+}
+
+// Notify queues msg without blocking. It returns ErrQueueFull when msg is dropped under DropNewest.
+func (a *Async) Notify(_ context.Context, msg Message) error {
+	if msg.Time.IsZero() {
+		msg.Time = time.Now()
+	}
+
+	for {
+		select {
+		case a.queue <- msg:
+			return nil
+		default:
+		}
+
+		if a.dropPolicy != DropOldest {
+			a.dropped.Add(1)
+
+			return ErrQueueFull
+		}
+
+		select {
+		case <-a.queue:
+			a.dropped.Add(1)
+		default:
+		}
+	}
+}
+
+// Dropped returns the number of messages discarded because the queue was full.
+func (a *Async) Dropped() uint64 {
+	return a.dropped.Load()
+}
+
+// Failed returns the number of messages that could not be sent after all attempts.
+func (a *Async) Failed() uint64 {
+	return a.failed.Load()
+}
+
+func (a *Async) Start(ctx context.Context) error {
+	if !a.running.CompareAndSwap(false, true) {
+		return ErrAlreadyRunning
+	}
+
+	a.mu.Lock()
+	a.stop = make(chan struct{})
+	a.done = make(chan struct{})
+	a.mu.Unlock()
+
+	go a.run(ctx, a.stop, a.done)
+
+	return nil
+}
+
+func (a *Async) Stop() error {
+	if !a.running.CompareAndSwap(true, false) {
+		return nil
+	}
+
+	a.mu.Lock()
+	close(a.stop)
+	done := a.done
+	a.mu.Unlock()
+
+	<-done
+
+	if dropped := a.Dropped(); dropped > 0 {
+		log.Warn().Str("source", "gframework").Uint64("dropped", dropped).Msg("Notifications were dropped")
+	}
+
+	return nil
+}
+
+func (a *Async) Name() string {
+	return "notifylog-async"
+}
+
+func (a *Async) run(ctx context.Context, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(a.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Message, 0, a.batchSize)
+
+	for {
+		select {
+		case msg := <-a.queue:
+			batch = append(batch, msg)
+			if len(batch) >= a.batchSize {
+				batch = a.flush(ctx, batch)
+			}
+		case <-ticker.C:
+			batch = a.flush(ctx, batch)
+		case <-ctx.Done():
+			a.shutdown(ctx, batch)
+
+			return
+		case <-stop:
+			a.shutdown(ctx, batch)
+
+			return
+		}
+	}
+}
+
+func (a *Async) shutdown(ctx context.Context, batch []Message) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), a.shutdownTimeout)
+	defer cancel()
+
+	for {
+		select {
+		case msg := <-a.queue:
+			batch = append(batch, msg)
+		default:
+			a.flush(ctx, batch)
+
+			return
+		}
+	}
+}
+
+func (a *Async) flush(ctx context.Context, batch []Message) []Message {
+	for _, msg := range batch {
+		if err := a.send(ctx, msg); err != nil {
+			a.failed.Add(1)
+
+			log.Error().
+				Str("source", "gframework").
+				Err(err).
+				Str("title", msg.Title).
+				Msg("Failed to send notification")
+		}
+	}
+
+	return batch[:0]
+}
+
+func (a *Async) send(ctx context.Context, msg Message) error {
+	backoff := a.initialBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := a.notifier.Notify(ctx, msg)
+		if err == nil || attempt >= a.maxAttempts || !retryable(err) {
+			return err
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", err, ctx.Err())
+		}
+
+		backoff = min(backoff*2, a.maxBackoff)
+	}
+}
+
+func retryable(err error) bool {
+	var serviceErr *httpclient.ServiceError
+	if errors.As(err, &serviceErr) {
+		return serviceErr.StatusCode == http.StatusTooManyRequests || serviceErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	return true
+}
@@ -0,0 +1,161 @@
+package notifylog_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/httpclient"
+	"github.com/andyle182810/gframework/notifylog"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+var errPlatformDown = errors.New("platform down")
+
+type recordingNotifier struct {
+	mu       sync.Mutex
+	failures int
+	err      error
+	calls    int
+	sent     []string
+	repeated []int
+	last     notifylog.Message
+}
+
+func newRecordingNotifier(failures int, err error) *recordingNotifier {
+	return &recordingNotifier{
+		mu:       sync.Mutex{},
+		failures: failures,
+		err:      err,
+		calls:    0,
+		sent:     nil,
+		repeated: nil,
+		last:     titledMessage(""),
+	}
+}
+
+// titledMessage returns a message with only a title, at the zero level.
+func titledMessage(title string) notifylog.Message {
+	return notifylog.Message{
+		Level:       zerolog.DebugLevel,
+		Title:       title,
+		Text:        "",
+		Fields:      nil,
+		Time:        time.Time{},
+		Repeated:    0,
+		Attachments: nil,
+	}
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, msg notifylog.Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.calls++
+
+	if n.failures > 0 {
+		n.failures--
+
+		return n.err
+	}
+
+	n.sent = append(n.sent, msg.Title)
+	n.repeated = append(n.repeated, msg.Repeated)
+	n.last = msg
+
+	return nil
+}
+
+func (n *recordingNotifier) snapshot() (int, []string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.calls, append([]string(nil), n.sent...)
+}
+
+func TestAsyncRetriesAndFlushesOnStop(t *testing.T) {
+	t.Parallel()
+
+	notifier := newRecordingNotifier(2, errPlatformDown)
+	async := notifylog.NewAsync(notifier,
+		notifylog.WithBatch(10, time.Hour),
+		notifylog.WithRetry(3, time.Millisecond, time.Millisecond),
+	)
+
+	require.NoError(t, async.Start(t.Context()))
+	require.NoError(t, async.Notify(t.Context(), titledMessage("first")))
+	require.NoError(t, async.Notify(t.Context(), titledMessage("second")))
+	require.NoError(t, async.Stop())
+
+	calls, sent := notifier.snapshot()
+	require.Equal(t, 4, calls)
+	require.Equal(t, []string{"first", "second"}, sent)
+	require.Zero(t, async.Failed())
+}
+
+func TestAsyncDoesNotRetryClientErrors(t *testing.T) {
+	t.Parallel()
+
+	notifier := newRecordingNotifier(1, httpclient.NewServiceError(http.StatusBadRequest, "chat not found", "", ""))
+	async := notifylog.NewAsync(notifier,
+		notifylog.WithBatch(1, time.Hour),
+		notifylog.WithRetry(3, time.Millisecond, time.Millisecond),
+	)
+
+	require.NoError(t, async.Start(t.Context()))
+	require.NoError(t, async.Notify(t.Context(), titledMessage("lost")))
+	require.NoError(t, async.Stop())
+
+	calls, sent := notifier.snapshot()
+	require.Equal(t, 1, calls)
+	require.Empty(t, sent)
+	require.Equal(t, uint64(1), async.Failed())
+}
+
+func TestAsyncDropPolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		policy   notifylog.DropPolicy
+		expected []string
+	}{
+		{name: "drop newest", policy: notifylog.DropNewest, expected: []string{"1", "2"}},
+		{name: "drop oldest", policy: notifylog.DropOldest, expected: []string{"2", "3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			notifier := newRecordingNotifier(0, nil)
+			async := notifylog.NewAsync(notifier, notifylog.WithQueueSize(2), notifylog.WithDropPolicy(tt.policy))
+
+			// Not started yet, so the queue fills up.
+			for _, title := range []string{"1", "2", "3"} {
+				_ = async.Notify(t.Context(), titledMessage(title))
+			}
+
+			require.Equal(t, uint64(1), async.Dropped())
+
+			require.NoError(t, async.Start(t.Context()))
+			require.NoError(t, async.Stop())
+
+			_, sent := notifier.snapshot()
+			require.Equal(t, tt.expected, sent)
+		})
+	}
+}
+
+func TestAsyncQueueFull(t *testing.T) {
+	t.Parallel()
+
+	async := notifylog.NewAsync(newRecordingNotifier(0, nil), notifylog.WithQueueSize(1))
+
+	require.NoError(t, async.Notify(t.Context(), titledMessage("kept")))
+	require.ErrorIs(t, async.Notify(t.Context(), titledMessage("dropped")), notifylog.ErrQueueFull)
+}
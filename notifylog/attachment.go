@@ -0,0 +1,91 @@
+package notifylog
+
+import (
+	"bytes"
+	"context"
+	"path"
+	"time"
+
+	"github.com/andyle182810/gframework/blobstore"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultAttachmentTTL    = 7 * 24 * time.Hour
+	attachmentUploadTimeout = 30 * time.Second
+)
+
+// Attachment is a file linked from a notification, e.g. a request dump or a failed export. Chat
+// webhooks cannot carry files, so attachments are uploaded to the store of WithAttachmentStore and
+// linked with a presigned URL.
+type Attachment struct {
+	Name        string
+	ContentType string
+	// Data is uploaded when set.
+	Data []byte
+	// Key links an object that is already in the store instead of uploading Data.
+	Key string
+	// URL is linked as is, without the store.
+	URL string
+}
+
+// WithAttachmentStore uploads Message.Attachments to the store under "notifylog/<date>/<id>/<name>"
+// and links them with URLs valid for ttl, seven days by default. Attachments that fail to upload are
+// listed by name only, so the notification is still sent.
+func WithAttachmentStore(store blobstore.Store, ttl time.Duration) Option {
+	return func(o *options) {
+		o.attachmentStore = store
+
+		if ttl > 0 {
+			o.attachmentTTL = ttl
+		}
+	}
+}
+
+// withAttachmentURLs returns the message with the URLs of its attachments resolved. Errors are not
+// logged, as the notifier may itself be the destination of the logs, see Writer.
+func (o *options) withAttachmentURLs(ctx context.Context, msg Message) Message {
+	if len(msg.Attachments) == 0 || o.attachmentStore == nil {
+		return msg
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, attachmentUploadTimeout)
+	defer cancel()
+
+	attachments := make([]Attachment, len(msg.Attachments))
+	prefix := path.Join("notifylog", messageTime(msg).UTC().Format(time.DateOnly), uuid.NewString())
+
+	for i, attachment := range msg.Attachments {
+		attachments[i] = attachment
+
+		if attachment.URL != "" {
+			continue
+		}
+
+		key := attachment.Key
+		if key == "" && attachment.Data != nil {
+			key = path.Join(prefix, path.Base("/"+attachment.Name))
+
+			var putOpts []blobstore.PutOption
+			if attachment.ContentType != "" {
+				putOpts = append(putOpts, blobstore.WithContentType(attachment.ContentType))
+			}
+
+			if _, err := o.attachmentStore.Put(ctx, key, bytes.NewReader(attachment.Data), putOpts...); err != nil {
+				continue
+			}
+		}
+
+		if key == "" {
+			continue
+		}
+
+		if url, err := o.attachmentStore.PresignGet(ctx, key, o.attachmentTTL); err == nil {
+			attachments[i].URL = url
+		}
+	}
+
+	msg.Attachments = attachments
+
+	return msg
+}
@@ -0,0 +1,81 @@
+package notifylog_test
+
+import (
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/blobstore"
+	"github.com/andyle182810/gframework/notifylog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttachmentsAreUploadedAndLinked(t *testing.T) {
+	t.Parallel()
+
+	store, err := blobstore.NewFS(t.TempDir(), blobstore.WithBaseURL("https://files.example.com"))
+	require.NoError(t, err)
+
+	_, err = store.Put(t.Context(), "exports/orders.csv", strings.NewReader("id\n7"))
+	require.NoError(t, err)
+
+	server, requests := newPlatformStub(t, http.StatusNoContent)
+
+	msg := testMessage()
+	msg.Fields = nil
+	msg.Attachments = []notifylog.Attachment{
+		{Name: "request.json", ContentType: "application/json", Data: []byte(`{"id":7}`), Key: "", URL: ""},
+		{Name: "orders.csv", ContentType: "", Data: nil, Key: "exports/orders.csv", URL: ""},
+		{Name: "runbook", ContentType: "", Data: nil, Key: "", URL: "https://wiki.example.com/runbook"},
+		{Name: "missing", ContentType: "", Data: nil, Key: "", URL: ""},
+	}
+
+	notifier := notifylog.NewDiscord(server.URL, notifylog.WithAttachmentStore(store, time.Hour))
+	require.NoError(t, notifier.Notify(t.Context(), msg))
+
+	received := <-requests
+
+	embeds, ok := received.body["embeds"].([]any)
+	require.True(t, ok)
+
+	embed, ok := embeds[0].(map[string]any)
+	require.True(t, ok)
+
+	description, ok := embed["description"].(string)
+	require.True(t, ok)
+	require.Regexp(t, regexp.MustCompile(`^connection refused\n`+
+		`\[request\.json\]\(https://files\.example\.com/notifylog/2025-01-02/[0-9a-f-]{36}/request\.json\?expires=\d+\)\n`+
+		`\[orders\.csv\]\(https://files\.example\.com/exports/orders\.csv\?expires=\d+\)\n`+
+		`\[runbook\]\(https://wiki\.example\.com/runbook\)\n`+
+		`missing$`), description)
+
+	key := regexp.MustCompile(`notifylog/[^?]+request\.json`).FindString(description)
+
+	body, obj, err := store.Get(t.Context(), key)
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	require.NoError(t, body.Close())
+	require.JSONEq(t, `{"id":7}`, string(data))
+	require.Equal(t, "application/json", obj.ContentType)
+}
+
+func TestAttachmentsWithoutStoreAreListedByName(t *testing.T) {
+	t.Parallel()
+
+	server, requests := newPlatformStub(t, http.StatusOK)
+
+	msg := testMessage()
+	msg.Fields = nil
+	msg.Attachments = []notifylog.Attachment{{Name: "dump.txt", ContentType: "", Data: []byte("x"), Key: "", URL: ""}}
+
+	notifier := notifylog.NewTelegram("token", "chat", notifylog.WithTelegramAPIURL(server.URL))
+	require.NoError(t, notifier.Notify(t.Context(), msg))
+
+	received := <-requests
+	require.Equal(t, "ERROR Payment reconciliation failed\n\nconnection refused\ndump.txt", received.body["text"])
+}
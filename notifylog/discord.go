@@ -0,0 +1,70 @@
+package notifylog
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/andyle182810/gframework/httpclient"
+)
+
+const (
+	// discordTitleMaxLength and discordMaxLength are the limits of an embed title and description.
+	discordTitleMaxLength = 256
+	discordMaxLength      = 4096
+)
+
+type discordWebhook struct {
+	Embeds []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string        `json:"title"`
+	Description string        `json:"description,omitempty"`
+	Color       int           `json:"color"`
+	Timestamp   string        `json:"timestamp"`
+	Footer      discordFooter `json:"footer"`
+}
+
+type discordFooter struct {
+	Text string `json:"text"`
+}
+
+// Discord posts messages as embeds to a channel webhook.
+type Discord struct {
+	client *httpclient.Client
+	opts   *options
+}
+
+var _ Notifier = (*Discord)(nil)
+
+// NewDiscord creates a notifier for the webhook URL of a channel, e.g.
+// "https://discord.com/api/webhooks/<id>/<token>".
+func NewDiscord(webhookURL string, opts ...Option) *Discord {
+	o := newOptions(opts)
+
+	return &Discord{
+		client: o.client(webhookURL),
+		opts:   o,
+	}
+}
+
+func (d *Discord) Notify(ctx context.Context, msg Message) error {
+	msg = d.opts.withAttachmentURLs(ctx, msg)
+
+	body := discordWebhook{
+		Embeds: []discordEmbed{{
+			Title:       truncate(d.opts.title(msg), discordTitleMaxLength),
+			Description: d.opts.text(msg, discordMaxLength, markdownMarkup),
+			Color:       d.opts.color(msg.Level),
+			Timestamp:   messageTime(msg).UTC().Format(time.RFC3339),
+			Footer:      discordFooter{Text: levelLabel(msg.Level)},
+		}},
+	}
+
+	if err := d.client.Post(ctx, "", body, nil); err != nil {
+		return fmt.Errorf("%w: discord: %w", ErrNotifyFailed, err)
+	}
+
+	return nil
+}
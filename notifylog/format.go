@@ -0,0 +1,252 @@
+package notifylog
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/rs/zerolog"
+)
+
+// Default RGB colors of the levels on platforms that highlight messages, see WithLevelColors.
+const (
+	colorError = 0xD32F2F
+	colorWarn  = 0xF9A825
+	colorInfo  = 0x1976D2
+	colorDebug = 0x757575
+)
+
+type link struct {
+	label string
+	url   *template.Template
+}
+
+// markup renders code blocks and links for a platform.
+type markup struct {
+	codeBlock func(code string) string
+	link      func(label, url string) string
+}
+
+var (
+	markdownMarkup = markup{
+		codeBlock: func(code string) string { return "```json\n" + code + "\n```" },
+		link:      func(label, url string) string { return fmt.Sprintf("[%s](%s)", label, url) },
+	}
+	plainMarkup = markup{
+		codeBlock: func(code string) string { return code },
+		link:      func(label, url string) string { return label + ": " + url },
+	}
+	// slackMarkup is Slack's mrkdwn, which has no syntax highlighting and puts the URL of a link first.
+	slackMarkup = markup{
+		codeBlock: func(code string) string { return "```\n" + code + "\n```" },
+		link:      func(label, url string) string { return fmt.Sprintf("<%s|%s>", url, label) },
+	}
+)
+
+// WithTitleTemplate renders the title from the Message, e.g.
+// template.Must(template.New("title").Parse(`{{.Title}} on {{.Fields.host}}`)). WithTitlePrefix still
+// applies.
+func WithTitleTemplate(tmpl *template.Template) Option {
+	return func(o *options) {
+		o.titleTemplate = tmpl
+	}
+}
+
+// WithLevelColors overrides the RGB color of the given levels, e.g. {zerolog.WarnLevel: 0xFF9800}.
+func WithLevelColors(colors map[zerolog.Level]int) Option {
+	return func(o *options) {
+		o.colors = colors
+	}
+}
+
+// WithFields only includes the given fields.
+func WithFields(keys ...string) Option {
+	return func(o *options) {
+		o.onlyFields = append(o.onlyFields, keys...)
+	}
+}
+
+// WithoutFields leaves the given fields out of the notification, or all of Message.Fields when called
+// without keys.
+func WithoutFields(keys ...string) Option {
+	return func(o *options) {
+		if len(keys) == 0 {
+			o.includeFields = false
+
+			return
+		}
+
+		o.excludedFields = append(o.excludedFields, keys...)
+	}
+}
+
+// WithCodeBlocks renders the given fields, e.g. request payloads, as indented JSON in code blocks after
+// the other fields.
+func WithCodeBlocks(keys ...string) Option {
+	return func(o *options) {
+		o.codeFields = append(o.codeFields, keys...)
+	}
+}
+
+// WithLink appends a link rendered from the Message, e.g. to the logs of the request:
+//
+//	notifylog.WithLink("Logs", template.Must(template.New("logs").Parse(
+//	    `https://grafana.example.com/explore?request_id={{.Fields.request_id}}`)))
+//
+// The template is set to fail on missing keys and the link is left out when it fails, e.g. for
+// messages without a request ID.
+func WithLink(label string, url *template.Template) Option {
+	return func(o *options) {
+		o.links = append(o.links, link{label: label, url: url.Option("missingkey=error")})
+	}
+}
+
+func (o *options) title(msg Message) string {
+	title := msg.Title
+	if o.titleTemplate != nil {
+		if rendered, err := execute(o.titleTemplate, msg); err == nil {
+			title = rendered
+		}
+	}
+
+	if o.titlePrefix != "" {
+		title = fmt.Sprintf("[%s] %s", o.titlePrefix, title)
+	}
+
+	if msg.Repeated > 0 {
+		title = fmt.Sprintf("%s (repeated %d times)", title, msg.Repeated)
+	}
+
+	return title
+}
+
+// text renders the message text followed by its fields sorted by key, the links, the attachments and
+// the code blocks, capped to limit characters.
+func (o *options) text(msg Message, limit int, style markup) string {
+	var (
+		builder    strings.Builder
+		codeBlocks []string
+	)
+
+	builder.WriteString(msg.Text)
+
+	newLine := func() {
+		if builder.Len() > 0 {
+			builder.WriteByte('\n')
+		}
+	}
+
+	for _, key := range o.fieldKeys(msg) {
+		if slices.Contains(o.codeFields, key) {
+			codeBlocks = append(codeBlocks, key+":\n"+style.codeBlock(indentedJSON(msg.Fields[key])))
+
+			continue
+		}
+
+		newLine()
+		fmt.Fprintf(&builder, "%s: %s", key, formatValue(msg.Fields[key]))
+	}
+
+	for _, link := range o.links {
+		if url, err := execute(link.url, msg); err == nil && url != "" {
+			newLine()
+			builder.WriteString(style.link(link.label, url))
+		}
+	}
+
+	for _, attachment := range msg.Attachments {
+		newLine()
+
+		if attachment.URL == "" {
+			builder.WriteString(attachment.Name)
+
+			continue
+		}
+
+		builder.WriteString(style.link(attachment.Name, attachment.URL))
+	}
+
+	for _, block := range codeBlocks {
+		newLine()
+		builder.WriteString(block)
+	}
+
+	if o.maxLength > 0 && o.maxLength < limit {
+		limit = o.maxLength
+	}
+
+	return truncate(builder.String(), limit)
+}
+
+func (o *options) fieldKeys(msg Message) []string {
+	if !o.includeFields {
+		return nil
+	}
+
+	return slices.DeleteFunc(sortedKeys(msg.Fields), func(key string) bool {
+		if len(o.onlyFields) > 0 && !slices.Contains(o.onlyFields, key) {
+			return true
+		}
+
+		return slices.Contains(o.excludedFields, key)
+	})
+}
+
+func (o *options) color(level zerolog.Level) int {
+	if color, ok := o.colors[level]; ok {
+		return color
+	}
+
+	switch {
+	case level >= zerolog.ErrorLevel && level <= zerolog.PanicLevel:
+		return colorError
+	case level == zerolog.WarnLevel:
+		return colorWarn
+	case level == zerolog.InfoLevel || level == zerolog.NoLevel:
+		return colorInfo
+	default:
+		return colorDebug
+	}
+}
+
+// formatValue renders maps and slices, e.g. from Writer, as JSON instead of Go syntax.
+func formatValue(value any) string {
+	switch value.(type) {
+	case map[string]any, []any:
+		if encoded, err := json.Marshal(value); err == nil {
+			return string(encoded)
+		}
+	}
+
+	return fmt.Sprint(value)
+}
+
+func indentedJSON(value any) string {
+	if text, ok := value.(string); ok {
+		var decoded any
+		if json.Unmarshal([]byte(text), &decoded) != nil {
+			return text
+		}
+
+		value = decoded
+	}
+
+	encoded, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(encoded)
+}
+
+func execute(tmpl *template.Template, msg Message) (string, error) {
+	var builder strings.Builder
+
+	if err := tmpl.Execute(&builder, msg); err != nil {
+		return "", fmt.Errorf("failed to execute %s template: %w", tmpl.Name(), err)
+	}
+
+	return builder.String(), nil
+}
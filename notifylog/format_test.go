@@ -0,0 +1,86 @@
+package notifylog_test
+
+import (
+	"net/http"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/andyle182810/gframework/notifylog"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func formattedMessage() notifylog.Message {
+	return notifylog.Message{
+		Level: zerolog.WarnLevel,
+		Title: "Webhook rejected",
+		Text:  "upstream returned 422",
+		Fields: map[string]any{
+			"request_id": "req-1",
+			"host":       "api-7",
+			"secret":     "do-not-send",
+			"labels":     map[string]any{"tier": "gold"},
+			"payload":    `{"order":{"id":7}}`,
+		},
+		Time:        time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Repeated:    0,
+		Attachments: nil,
+	}
+}
+
+func formatOptions() []notifylog.Option {
+	return []notifylog.Option{
+		notifylog.WithTitleTemplate(template.Must(template.New("title").Parse(`{{.Title}} on {{.Fields.host}}`))),
+		notifylog.WithLevelColors(map[zerolog.Level]int{zerolog.WarnLevel: 0xFF9800}),
+		notifylog.WithoutFields("secret", "host"),
+		notifylog.WithCodeBlocks("payload"),
+		notifylog.WithLink("Logs", template.Must(template.New("logs").Parse(
+			`https://logs.example.com/?request_id={{.Fields.request_id}}`))),
+		notifylog.WithLink("Trace", template.Must(template.New("trace").Parse(
+			`https://traces.example.com/{{.Fields.trace_id}}`))),
+	}
+}
+
+func TestDiscordFormatting(t *testing.T) {
+	t.Parallel()
+
+	server, requests := newPlatformStub(t, http.StatusNoContent)
+
+	require.NoError(t, notifylog.NewDiscord(server.URL, formatOptions()...).Notify(t.Context(), formattedMessage()))
+
+	received := <-requests
+
+	embeds, ok := received.body["embeds"].([]any)
+	require.True(t, ok)
+
+	embed, ok := embeds[0].(map[string]any)
+	require.True(t, ok)
+	require.Equal(t, "Webhook rejected on api-7", embed["title"])
+	require.InDelta(t, 0xFF9800, embed["color"], 0)
+	require.Equal(t, "upstream returned 422\n"+
+		`labels: {"tier":"gold"}`+"\n"+
+		"request_id: req-1\n"+
+		"[Logs](https://logs.example.com/?request_id=req-1)\n"+
+		"payload:\n```json\n{\n  \"order\": {\n    \"id\": 7\n  }\n}\n```",
+		embed["description"],
+	)
+}
+
+func TestTelegramFormatting(t *testing.T) {
+	t.Parallel()
+
+	server, requests := newPlatformStub(t, http.StatusOK)
+
+	opts := append(formatOptions(), notifylog.WithTelegramAPIURL(server.URL), notifylog.WithFields("request_id"))
+
+	require.NoError(t, notifylog.NewTelegram("token", "chat", opts...).Notify(t.Context(), formattedMessage()))
+
+	received := <-requests
+	require.Equal(t, "WARN Webhook rejected on api-7\n\n"+
+		"upstream returned 422\n"+
+		"request_id: req-1\n"+
+		"Logs: https://logs.example.com/?request_id=req-1",
+		received.body["text"],
+	)
+}
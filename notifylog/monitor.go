@@ -0,0 +1,213 @@
+package notifylog
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/andyle182810/gframework/metricserver"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics receives the outcome of every notification sent through a Monitor, labeled by its name.
+type Metrics interface {
+	NotifyFinished(notifier string, duration time.Duration, err error)
+}
+
+type MonitorOption func(*Monitor)
+
+// WithMetrics reports every notification, e.g. to Prometheus via NewPrometheusMetrics.
+func WithMetrics(m Metrics) MonitorOption {
+	return func(mon *Monitor) {
+		mon.metrics = m
+	}
+}
+
+// Status is the delivery record of a monitored notifier.
+type Status struct {
+	Name   string `json:"name"`
+	Sent   uint64 `json:"sent"`
+	Failed uint64 `json:"failed"`
+	// LastLatency and AverageLatency cover failed notifications as well.
+	LastLatency    time.Duration `json:"lastLatency"`
+	AverageLatency time.Duration `json:"averageLatency"`
+	LastSentAt     *time.Time    `json:"lastSentAt,omitempty"`
+	LastFailedAt   *time.Time    `json:"lastFailedAt,omitempty"`
+	// LastError is the error of the last failed notification, kept after later ones succeed.
+	LastError string `json:"lastError,omitempty"`
+}
+
+// Healthy reports whether the last notification was sent, or none was attempted yet.
+func (s Status) Healthy() bool {
+	if s.LastFailedAt == nil {
+		return true
+	}
+
+	return s.LastSentAt != nil && s.LastSentAt.After(*s.LastFailedAt)
+}
+
+// Monitor records the deliveries of a notifier, so that a broken token or webhook is noticed before
+// the next incident. Put it directly in front of the platform, behind Async, so every attempt is
+// recorded and a slow platform is timed without the queue:
+//
+//	discord := notifylog.NewMonitor("discord", notifylog.NewDiscord(webhookURL),
+//	    notifylog.WithMetrics(metrics),
+//	)
+//	notifier := notifylog.NewAsync(discord)
+//
+// Status returns the record, e.g. for an admin endpoint or a readiness check.
+type Monitor struct {
+	name     string
+	notifier Notifier
+	metrics  Metrics
+
+	mu           sync.Mutex
+	sent         uint64
+	failed       uint64
+	total        time.Duration
+	lastLatency  time.Duration
+	lastSentAt   time.Time
+	lastFailedAt time.Time
+	lastError    string
+}
+
+var _ Notifier = (*Monitor)(nil)
+
+func NewMonitor(name string, notifier Notifier, opts ...MonitorOption) *Monitor {
+	monitor := &Monitor{
+		name:         name,
+		notifier:     notifier,
+		metrics:      nil,
+		mu:           sync.Mutex{},
+		sent:         0,
+		failed:       0,
+		total:        0,
+		lastLatency:  0,
+		lastSentAt:   time.Time{},
+		lastFailedAt: time.Time{},
+		lastError:    "",
+	}
+
+	for _, opt := range opts {
+		opt(monitor)
+	}
+
+	return monitor
+}
+
+func (m *Monitor) Name() string {
+	return m.name
+}
+
+func (m *Monitor) Notify(ctx context.Context, msg Message) error {
+	startedAt := time.Now()
+
+	err := m.notifier.Notify(ctx, msg)
+	latency := time.Since(startedAt)
+
+	m.record(startedAt, latency, err)
+
+	if m.metrics != nil {
+		m.metrics.NotifyFinished(m.name, latency, err)
+	}
+
+	return err
+}
+
+func (m *Monitor) record(at time.Time, latency time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.total += latency
+	m.lastLatency = latency
+
+	if err != nil {
+		m.failed++
+		m.lastFailedAt = at
+		m.lastError = err.Error()
+
+		return
+	}
+
+	m.sent++
+	m.lastSentAt = at
+}
+
+// Status returns the delivery record since the monitor was created.
+func (m *Monitor) Status() Status {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	status := Status{
+		Name:           m.name,
+		Sent:           m.sent,
+		Failed:         m.failed,
+		LastLatency:    m.lastLatency,
+		AverageLatency: 0,
+		LastSentAt:     nil,
+		LastFailedAt:   nil,
+		LastError:      m.lastError,
+	}
+
+	if attempts := m.sent + m.failed; attempts > 0 {
+		status.AverageLatency = m.total / time.Duration(attempts) //nolint:gosec
+	}
+
+	if !m.lastSentAt.IsZero() {
+		sentAt := m.lastSentAt
+		status.LastSentAt = &sentAt
+	}
+
+	if !m.lastFailedAt.IsZero() {
+		failedAt := m.lastFailedAt
+		status.LastFailedAt = &failedAt
+	}
+
+	return status
+}
+
+// PrometheusMetrics implements Metrics with Prometheus collectors. One instance can be shared by
+// several monitors.
+type PrometheusMetrics struct {
+	sent       *prometheus.CounterVec
+	failed     *prometheus.CounterVec
+	duration   *prometheus.HistogramVec
+	lastSentAt *prometheus.GaugeVec
+}
+
+// NewPrometheusMetrics registers the notification metrics with reg, e.g. the registerer of
+// metricserver. Alert on a rising failed count, or on a last sent timestamp that falls behind.
+func NewPrometheusMetrics(reg prometheus.Registerer) (*PrometheusMetrics, error) {
+	const subsystem = "notifylog"
+
+	m := &PrometheusMetrics{
+		sent: metricserver.NewCounterVec(subsystem, "sent_total",
+			"Number of notifications sent.", "notifier"),
+		failed: metricserver.NewCounterVec(subsystem, "failed_total",
+			"Number of notifications that failed to send.", "notifier"),
+		duration: metricserver.NewHistogramVec(subsystem, "duration_seconds",
+			"Duration of notification sends.", nil, "notifier"),
+		lastSentAt: metricserver.NewGaugeVec(subsystem, "last_sent_timestamp_seconds",
+			"Unix time of the last notification sent.", "notifier"),
+	}
+
+	if err := metricserver.Register(reg, m.sent, m.failed, m.duration, m.lastSentAt); err != nil {
+		return nil, fmt.Errorf("notifylog: failed to register metrics: %w", err)
+	}
+
+	return m, nil
+}
+
+func (m *PrometheusMetrics) NotifyFinished(notifier string, duration time.Duration, err error) {
+	m.duration.WithLabelValues(notifier).Observe(duration.Seconds())
+
+	if err != nil {
+		m.failed.WithLabelValues(notifier).Inc()
+
+		return
+	}
+
+	m.sent.WithLabelValues(notifier).Inc()
+	m.lastSentAt.WithLabelValues(notifier).SetToCurrentTime()
+}
@@ -0,0 +1,104 @@
+package notifylog_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/notifylog"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMonitorStatus(t *testing.T) {
+	t.Parallel()
+
+	notifier := newRecordingNotifier(2, errPlatformDown)
+	monitor := notifylog.NewMonitor("discord", notifier)
+
+	status := monitor.Status()
+	require.Equal(t, "discord", status.Name)
+	require.True(t, status.Healthy())
+	require.Nil(t, status.LastSentAt)
+
+	msg := titledMessage("token expired")
+
+	require.ErrorIs(t, monitor.Notify(t.Context(), msg), errPlatformDown)
+	require.ErrorIs(t, monitor.Notify(t.Context(), msg), errPlatformDown)
+
+	status = monitor.Status()
+	require.Equal(t, uint64(0), status.Sent)
+	require.Equal(t, uint64(2), status.Failed)
+	require.Equal(t, errPlatformDown.Error(), status.LastError)
+	require.NotNil(t, status.LastFailedAt)
+	require.False(t, status.Healthy())
+
+	require.NoError(t, monitor.Notify(t.Context(), msg))
+
+	status = monitor.Status()
+	require.Equal(t, uint64(1), status.Sent)
+	require.Equal(t, uint64(2), status.Failed)
+	require.NotNil(t, status.LastSentAt)
+	require.Equal(t, errPlatformDown.Error(), status.LastError, "the last error is kept for diagnosis")
+	require.True(t, status.Healthy())
+	require.Positive(t, status.LastLatency)
+	require.Positive(t, status.AverageLatency)
+}
+
+func TestMonitorBehindAsync(t *testing.T) {
+	t.Parallel()
+
+	notifier := newRecordingNotifier(1, errPlatformDown)
+	monitor := notifylog.NewMonitor("telegram", notifier)
+	async := notifylog.NewAsync(monitor,
+		notifylog.WithBatch(1, time.Hour),
+		notifylog.WithRetry(3, time.Millisecond, time.Millisecond),
+	)
+
+	require.NoError(t, async.Start(t.Context()))
+	require.NoError(t, async.Notify(t.Context(), titledMessage("disk full")))
+	require.NoError(t, async.Stop())
+
+	status := monitor.Status()
+	require.Equal(t, uint64(1), status.Sent)
+	require.Equal(t, uint64(1), status.Failed, "every attempt is recorded")
+}
+
+func TestPrometheusMetrics_RecordsNotifications(t *testing.T) {
+	t.Parallel()
+
+	reg := prometheus.NewRegistry()
+
+	metrics, err := notifylog.NewPrometheusMetrics(reg)
+	require.NoError(t, err)
+
+	notifier := newRecordingNotifier(1, errPlatformDown)
+	monitor := notifylog.NewMonitor("slack", notifier, notifylog.WithMetrics(metrics))
+
+	msg := titledMessage("deploy failed")
+
+	require.Error(t, monitor.Notify(t.Context(), msg))
+	require.NoError(t, monitor.Notify(t.Context(), msg))
+	require.NoError(t, monitor.Notify(t.Context(), msg))
+
+	expected := `
+# HELP gframework_notifylog_sent_total Number of notifications sent.
+# TYPE gframework_notifylog_sent_total counter
+gframework_notifylog_sent_total{notifier="slack"} 2
+# HELP gframework_notifylog_failed_total Number of notifications that failed to send.
+# TYPE gframework_notifylog_failed_total counter
+gframework_notifylog_failed_total{notifier="slack"} 1
+`
+
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"gframework_notifylog_sent_total",
+		"gframework_notifylog_failed_total",
+	))
+
+	require.Equal(t, 1, testutil.CollectAndCount(reg, "gframework_notifylog_duration_seconds"))
+	require.Equal(t, 1, testutil.CollectAndCount(reg, "gframework_notifylog_last_sent_timestamp_seconds"))
+
+	_, err = notifylog.NewPrometheusMetrics(reg)
+	require.Error(t, err, "the metrics are registered once per registry")
+}
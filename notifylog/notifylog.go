@@ -0,0 +1,181 @@
+// Package notifylog sends operational notifications, e.g. errors and incidents, to the chat
+// platforms teams run their incident channels on: Slack incoming webhooks, Telegram bots, Discord
+// webhooks and Microsoft Teams incoming webhooks. Every platform implements Notifier and shares the same
+// formatting options.
+//
+// Basic usage:
+//
+//	notifier := notifylog.NewDiscord(webhookURL, notifylog.WithTitlePrefix("orders-api"))
+//
+//	err := notifier.Notify(ctx, notifylog.Message{
+//	    Level:  zerolog.ErrorLevel,
+//	    Title:  "Payment reconciliation failed",
+//	    Text:   err.Error(),
+//	    Fields: map[string]any{"batch_id": batchID},
+//	})
+//
+// Wrap a notifier with NewAsync to send from a background worker with a bounded queue, batched flushes
+// and retries, so that a slow platform never blocks the code that logs the incident, and with
+// NewThrottle to rate limit and collapse duplicate messages. A Router sends messages to different
+// notifiers per level and fields, and Writer turns zerolog events into messages. NewMonitor records the
+// successes, failures and latency of a notifier, see Monitor.Status.
+package notifylog
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/andyle182810/gframework/blobstore"
+	"github.com/andyle182810/gframework/httpclient"
+	"github.com/rs/zerolog"
+)
+
+const defaultTimeout = 10 * time.Second
+
+var ErrNotifyFailed = errors.New("notifylog: failed to send notification")
+
+type Message struct {
+	Level  zerolog.Level
+	Title  string
+	Text   string
+	Fields map[string]any
+	// Time defaults to the time the message is sent.
+	Time time.Time
+	// Repeated is the number of identical messages suppressed since this one was last sent, see
+	// WithDedup.
+	Repeated int
+	// Attachments are linked after the fields, see WithAttachmentStore.
+	Attachments []Attachment
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, msg Message) error
+}
+
+type Option func(*options)
+
+type options struct {
+	httpClient      *http.Client
+	timeout         time.Duration
+	titlePrefix     string
+	titleTemplate   *template.Template
+	colors          map[zerolog.Level]int
+	includeFields   bool
+	onlyFields      []string
+	excludedFields  []string
+	codeFields      []string
+	links           []link
+	maxLength       int
+	telegramAPIURL  string
+	attachmentStore blobstore.Store
+	attachmentTTL   time.Duration
+}
+
+// WithHTTPClient sets the client used to call the platform, e.g. one routed through a proxy.
+func WithHTTPClient(client *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = client
+	}
+}
+
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		if timeout > 0 {
+			o.timeout = timeout
+		}
+	}
+}
+
+// WithTitlePrefix prefixes every title, e.g. with the service and environment name.
+func WithTitlePrefix(prefix string) Option {
+	return func(o *options) {
+		o.titlePrefix = prefix
+	}
+}
+
+// WithMaxLength truncates the text, including the fields, to n characters. Every platform is
+// already capped to its own limit.
+func WithMaxLength(n int) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.maxLength = n
+		}
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		httpClient:      nil,
+		timeout:         defaultTimeout,
+		titlePrefix:     "",
+		titleTemplate:   nil,
+		colors:          nil,
+		includeFields:   true,
+		onlyFields:      nil,
+		excludedFields:  nil,
+		codeFields:      nil,
+		links:           nil,
+		maxLength:       0,
+		telegramAPIURL:  defaultTelegramAPIURL,
+		attachmentStore: nil,
+		attachmentTTL:   defaultAttachmentTTL,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+func (o *options) client(baseURL string) *httpclient.Client {
+	clientOpts := []httpclient.Option{httpclient.WithTimeout(o.timeout)}
+	if o.httpClient != nil {
+		clientOpts = append(clientOpts, httpclient.WithHTTPClient(o.httpClient))
+	}
+
+	return httpclient.New(baseURL, clientOpts...)
+}
+
+func messageTime(msg Message) time.Time {
+	if msg.Time.IsZero() {
+		return time.Now()
+	}
+
+	return msg.Time
+}
+
+func levelLabel(level zerolog.Level) string {
+	if level == zerolog.NoLevel {
+		return "INFO"
+	}
+
+	return strings.ToUpper(level.String())
+}
+
+func sortedKeys(fields map[string]any) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
+
+func truncate(text string, limit int) string {
+	const ellipsis = "…"
+
+	runes := []rune(text)
+	if limit <= 0 || len(runes) <= limit {
+		return text
+	}
+
+	return string(runes[:limit-1]) + ellipsis
+}
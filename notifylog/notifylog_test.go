@@ -0,0 +1,158 @@
+package notifylog_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/notifylog"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+type capturedRequest struct {
+	path string
+	body map[string]any
+}
+
+func newPlatformStub(t *testing.T, status int) (*httptest.Server, <-chan capturedRequest) {
+	t.Helper()
+
+	requests := make(chan capturedRequest, 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+
+		_ = json.NewDecoder(r.Body).Decode(&body)
+
+		requests <- capturedRequest{path: r.URL.Path, body: body}
+
+		w.WriteHeader(status)
+	}))
+
+	t.Cleanup(server.Close)
+
+	return server, requests
+}
+
+func testMessage() notifylog.Message {
+	return notifylog.Message{
+		Level:       zerolog.ErrorLevel,
+		Title:       "Payment reconciliation failed",
+		Text:        "connection refused",
+		Fields:      map[string]any{"batch_id": 42, "attempt": 3},
+		Time:        time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Repeated:    0,
+		Attachments: nil,
+	}
+}
+
+func TestSlack(t *testing.T) {
+	t.Parallel()
+
+	server, requests := newPlatformStub(t, http.StatusOK)
+
+	notifier := notifylog.NewSlack(server.URL+"/services/T0/B0/token", notifylog.WithCodeBlocks("batch_id"))
+
+	require.NoError(t, notifier.Notify(t.Context(), testMessage()))
+
+	received := <-requests
+	require.Equal(t, "/services/T0/B0/token", received.path)
+	require.Equal(t, "ERROR Payment reconciliation failed", received.body["text"])
+
+	attachments, ok := received.body["attachments"].([]any)
+	require.True(t, ok)
+	require.Len(t, attachments, 1)
+
+	attachment, ok := attachments[0].(map[string]any)
+	require.True(t, ok)
+	require.Equal(t, "#D32F2F", attachment["color"])
+	require.Equal(t, "Payment reconciliation failed", attachment["title"])
+	require.Equal(t, "connection refused\nattempt: 3\nbatch_id:\n```\n42\n```", attachment["text"])
+	require.InDelta(t, 1735787045, attachment["ts"], 0)
+}
+
+func TestTelegram(t *testing.T) {
+	t.Parallel()
+
+	server, requests := newPlatformStub(t, http.StatusOK)
+
+	notifier := notifylog.NewTelegram("123:abc", "@incidents",
+		notifylog.WithTelegramAPIURL(server.URL),
+		notifylog.WithTitlePrefix("orders-api"),
+	)
+
+	require.NoError(t, notifier.Notify(t.Context(), testMessage()))
+
+	received := <-requests
+	require.Equal(t, "/bot123:abc/sendMessage", received.path)
+	require.Equal(t, "@incidents", received.body["chat_id"])
+	require.Equal(t,
+		"ERROR [orders-api] Payment reconciliation failed\n\nconnection refused\nattempt: 3\nbatch_id: 42",
+		received.body["text"],
+	)
+}
+
+func TestDiscord(t *testing.T) {
+	t.Parallel()
+
+	server, requests := newPlatformStub(t, http.StatusNoContent)
+
+	notifier := notifylog.NewDiscord(server.URL+"/api/webhooks/1/token", notifylog.WithoutFields())
+
+	require.NoError(t, notifier.Notify(t.Context(), testMessage()))
+
+	received := <-requests
+	require.Equal(t, "/api/webhooks/1/token", received.path)
+
+	embeds, ok := received.body["embeds"].([]any)
+	require.True(t, ok)
+	require.Len(t, embeds, 1)
+
+	embed, ok := embeds[0].(map[string]any)
+	require.True(t, ok)
+	require.Equal(t, "Payment reconciliation failed", embed["title"])
+	require.Equal(t, "connection refused", embed["description"])
+	require.InDelta(t, 0xD32F2F, embed["color"], 0)
+	require.Equal(t, "2025-01-02T03:04:05Z", embed["timestamp"])
+}
+
+func TestTeams(t *testing.T) {
+	t.Parallel()
+
+	server, requests := newPlatformStub(t, http.StatusOK)
+
+	notifier := notifylog.NewTeams(server.URL, notifylog.WithMaxLength(25))
+
+	msg := testMessage()
+	msg.Level = zerolog.WarnLevel
+
+	require.NoError(t, notifier.Notify(t.Context(), msg))
+
+	received := <-requests
+	require.Equal(t, "MessageCard", received.body["@type"])
+	require.Equal(t, "F9A825", received.body["themeColor"])
+
+	sections, ok := received.body["sections"].([]any)
+	require.True(t, ok)
+
+	section, ok := sections[0].(map[string]any)
+	require.True(t, ok)
+
+	text, ok := section["text"].(string)
+	require.True(t, ok)
+	require.Equal(t, "connection refused  \nattem…", text)
+	require.Contains(t, section["activitySubtitle"], "WARN · 2025-01-02T03:04:05Z")
+}
+
+func TestNotifyFailure(t *testing.T) {
+	t.Parallel()
+
+	server, _ := newPlatformStub(t, http.StatusBadRequest)
+
+	notifier := notifylog.NewDiscord(server.URL)
+
+	require.ErrorIs(t, notifier.Notify(t.Context(), testMessage()), notifylog.ErrNotifyFailed)
+}
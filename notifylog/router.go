@@ -0,0 +1,97 @@
+package notifylog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/rs/zerolog"
+)
+
+// Matcher reports whether a Rule applies to msg.
+type Matcher func(msg Message) bool
+
+func MinLevel(level zerolog.Level) Matcher {
+	return func(msg Message) bool {
+		return msg.Level >= level
+	}
+}
+
+func MaxLevel(level zerolog.Level) Matcher {
+	return func(msg Message) bool {
+		return msg.Level <= level
+	}
+}
+
+// FieldEquals matches messages whose field key formats to the same value, e.g. FieldEquals("team", "payments").
+func FieldEquals(key string, value any) Matcher {
+	expected := fmt.Sprint(value)
+
+	return func(msg Message) bool {
+		actual, ok := msg.Fields[key]
+
+		return ok && fmt.Sprint(actual) == expected
+	}
+}
+
+func HasField(key string) Matcher {
+	return func(msg Message) bool {
+		_, ok := msg.Fields[key]
+
+		return ok
+	}
+}
+
+// Rule sends the messages matching every matcher to all of its notifiers.
+type Rule struct {
+	Match     []Matcher
+	Notifiers []Notifier
+	// Final skips the remaining rules when this one matches.
+	Final bool
+}
+
+// Router routes each message to the notifiers of every matching rule, in order. Messages matching no
+// rule are dropped. For example, errors page on-call only while warnings go to the alerts channel:
+//
+//	router := notifylog.NewRouter(
+//	    notifylog.Rule{
+//	        Match:     []notifylog.Matcher{notifylog.MinLevel(zerolog.ErrorLevel)},
+//	        Notifiers: []notifylog.Notifier{pager},
+//	        Final:     true,
+//	    },
+//	    notifylog.Rule{
+//	        Match:     []notifylog.Matcher{notifylog.MinLevel(zerolog.WarnLevel)},
+//	        Notifiers: []notifylog.Notifier{alerts},
+//	    },
+//	)
+type Router struct {
+	rules []Rule
+}
+
+var _ Notifier = (*Router)(nil)
+
+func NewRouter(rules ...Rule) *Router {
+	return &Router{rules: rules}
+}
+
+// Notify returns the errors of all notifiers that failed.
+func (r *Router) Notify(ctx context.Context, msg Message) error {
+	var errs []error
+
+	for _, rule := range r.rules {
+		if !slices.ContainsFunc(rule.Match, func(match Matcher) bool { return !match(msg) }) {
+			for _, notifier := range rule.Notifiers {
+				if err := notifier.Notify(ctx, msg); err != nil {
+					errs = append(errs, err)
+				}
+			}
+
+			if rule.Final {
+				break
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,100 @@
+package notifylog_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/andyle182810/gframework/notifylog"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func leveledMessage(level zerolog.Level, title string, fields map[string]any) notifylog.Message {
+	msg := titledMessage(title)
+	msg.Level = level
+	msg.Fields = fields
+
+	return msg
+}
+
+func TestRouter(t *testing.T) {
+	t.Parallel()
+
+	pager := newRecordingNotifier(0, nil)
+	alerts := newRecordingNotifier(0, nil)
+	payments := newRecordingNotifier(0, nil)
+
+	router := notifylog.NewRouter(
+		notifylog.Rule{
+			Match:     []notifylog.Matcher{notifylog.FieldEquals("team", "payments")},
+			Notifiers: []notifylog.Notifier{payments},
+			Final:     false,
+		},
+		notifylog.Rule{
+			Match:     []notifylog.Matcher{notifylog.MinLevel(zerolog.ErrorLevel)},
+			Notifiers: []notifylog.Notifier{pager},
+			Final:     true,
+		},
+		notifylog.Rule{
+			Match:     []notifylog.Matcher{notifylog.MinLevel(zerolog.WarnLevel)},
+			Notifiers: []notifylog.Notifier{alerts},
+			Final:     false,
+		},
+	)
+
+	messages := []notifylog.Message{
+		leveledMessage(zerolog.ErrorLevel, "database down", nil),
+		leveledMessage(zerolog.WarnLevel, "slow query", map[string]any{"team": "payments"}),
+		leveledMessage(zerolog.InfoLevel, "deployed", nil),
+	}
+
+	for _, msg := range messages {
+		require.NoError(t, router.Notify(t.Context(), msg))
+	}
+
+	_, paged := pager.snapshot()
+	_, alerted := alerts.snapshot()
+	_, routed := payments.snapshot()
+
+	require.Equal(t, []string{"database down"}, paged)
+	require.Equal(t, []string{"slow query"}, alerted)
+	require.Equal(t, []string{"slow query"}, routed)
+}
+
+func TestRouterJoinsErrors(t *testing.T) {
+	t.Parallel()
+
+	failing := newRecordingNotifier(1, errPlatformDown)
+	healthy := newRecordingNotifier(0, nil)
+
+	router := notifylog.NewRouter(notifylog.Rule{
+		Match:     nil,
+		Notifiers: []notifylog.Notifier{failing, healthy},
+		Final:     false,
+	})
+
+	require.ErrorIs(t, router.Notify(t.Context(), titledMessage("x")), errPlatformDown)
+
+	_, sent := healthy.snapshot()
+	require.Equal(t, []string{"x"}, sent)
+}
+
+func TestWriter(t *testing.T) {
+	t.Parallel()
+
+	notifier := newRecordingNotifier(0, nil)
+
+	var output bytes.Buffer
+
+	logger := zerolog.New(zerolog.MultiLevelWriter(&output, notifylog.NewWriter(notifier, zerolog.WarnLevel)))
+
+	logger.Info().Msg("started")
+	logger.Error().Err(errPlatformDown).Str("order_id", "o-1").Msg("payment failed")
+
+	_, sent := notifier.snapshot()
+	require.Equal(t, []string{"payment failed"}, sent)
+	require.Equal(t, zerolog.ErrorLevel, notifier.last.Level)
+	require.Equal(t, "platform down", notifier.last.Text)
+	require.Equal(t, map[string]any{"order_id": "o-1"}, notifier.last.Fields)
+	require.Contains(t, output.String(), "started")
+}
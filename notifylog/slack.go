@@ -0,0 +1,70 @@
+package notifylog
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/andyle182810/gframework/httpclient"
+)
+
+// slackMaxLength bounds the attachment text, well below the 40,000 characters after which Slack
+// truncates a message.
+const slackMaxLength = 4000
+
+type slackWebhook struct {
+	// Text is the fallback shown in notifications, where attachments are not rendered.
+	Text        string            `json:"text"`
+	Attachments []slackAttachment `json:"attachments"`
+}
+
+type slackAttachment struct {
+	Color    string   `json:"color"`
+	Title    string   `json:"title"`
+	Text     string   `json:"text,omitempty"`
+	Footer   string   `json:"footer"`
+	Ts       int64    `json:"ts"`
+	MrkdwnIn []string `json:"mrkdwn_in"`
+}
+
+// Slack posts messages as attachments to a Slack incoming webhook.
+type Slack struct {
+	client *httpclient.Client
+	opts   *options
+}
+
+var _ Notifier = (*Slack)(nil)
+
+// NewSlack creates a notifier for the incoming webhook URL of a channel, e.g.
+// "https://hooks.slack.com/services/<team>/<bot>/<token>".
+func NewSlack(webhookURL string, opts ...Option) *Slack {
+	o := newOptions(opts)
+
+	return &Slack{
+		client: o.client(webhookURL),
+		opts:   o,
+	}
+}
+
+func (s *Slack) Notify(ctx context.Context, msg Message) error {
+	msg = s.opts.withAttachmentURLs(ctx, msg)
+
+	title := s.opts.title(msg)
+
+	body := slackWebhook{
+		Text: fmt.Sprintf("%s %s", levelLabel(msg.Level), title),
+		Attachments: []slackAttachment{{
+			Color:    fmt.Sprintf("#%06X", s.opts.color(msg.Level)),
+			Title:    title,
+			Text:     s.opts.text(msg, slackMaxLength, slackMarkup),
+			Footer:   levelLabel(msg.Level),
+			Ts:       messageTime(msg).Unix(),
+			MrkdwnIn: []string{"text"},
+		}},
+	}
+
+	if err := s.client.Post(ctx, "", body, nil); err != nil {
+		return fmt.Errorf("%w: slack: %w", ErrNotifyFailed, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package notifylog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/andyle182810/gframework/httpclient"
+)
+
+// teamsMaxLength keeps the card well below the 28 KB payload limit of incoming webhooks.
+const teamsMaxLength = 20000
+
+type teamsCard struct {
+	Type       string         `json:"@type"`
+	Context    string         `json:"@context"`
+	ThemeColor string         `json:"themeColor"`
+	Summary    string         `json:"summary"`
+	Title      string         `json:"title"`
+	Sections   []teamsSection `json:"sections"`
+}
+
+type teamsSection struct {
+	ActivitySubtitle string `json:"activitySubtitle"`
+	Text             string `json:"text,omitempty"`
+}
+
+// Teams posts messages as cards to a Microsoft Teams incoming webhook.
+type Teams struct {
+	client *httpclient.Client
+	opts   *options
+}
+
+var _ Notifier = (*Teams)(nil)
+
+// NewTeams creates a notifier for the incoming webhook URL of a channel.
+func NewTeams(webhookURL string, opts ...Option) *Teams {
+	o := newOptions(opts)
+
+	return &Teams{
+		client: o.client(webhookURL),
+		opts:   o,
+	}
+}
+
+func (t *Teams) Notify(ctx context.Context, msg Message) error {
+	msg = t.opts.withAttachmentURLs(ctx, msg)
+
+	title := t.opts.title(msg)
+
+	body := teamsCard{
+		Type:       "MessageCard",
+		Context:    "https://schema.org/extensions",
+		ThemeColor: fmt.Sprintf("%06X", t.opts.color(msg.Level)),
+		Summary:    title,
+		Title:      title,
+		Sections: []teamsSection{{
+			ActivitySubtitle: fmt.Sprintf("%s · %s", levelLabel(msg.Level), messageTime(msg).UTC().Format(time.RFC3339)),
+			// Teams renders the text as markdown, where lines need two trailing spaces to break.
+			Text: markdownLineBreaks(t.opts.text(msg, teamsMaxLength, markdownMarkup)),
+		}},
+	}
+
+	if err := t.client.Post(ctx, "", body, nil); err != nil {
+		return fmt.Errorf("%w: teams: %w", ErrNotifyFailed, err)
+	}
+
+	return nil
+}
+
+func markdownLineBreaks(text string) string {
+	return strings.ReplaceAll(text, "\n", "  \n")
+}
@@ -0,0 +1,67 @@
+package notifylog
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/andyle182810/gframework/httpclient"
+)
+
+const (
+	defaultTelegramAPIURL = "https://api.telegram.org"
+	// telegramMaxLength is the maximum length of a Telegram message.
+	telegramMaxLength = 4096
+)
+
+// WithTelegramAPIURL overrides the Bot API URL, e.g. for a self-hosted Bot API server.
+func WithTelegramAPIURL(url string) Option {
+	return func(o *options) {
+		o.telegramAPIURL = url
+	}
+}
+
+type telegramMessage struct {
+	ChatID                string `json:"chat_id"`
+	Text                  string `json:"text"`
+	DisableWebPagePreview bool   `json:"disable_web_page_preview"`
+}
+
+// Telegram sends messages through a bot to a chat, group or channel.
+type Telegram struct {
+	client *httpclient.Client
+	opts   *options
+	chatID string
+}
+
+var _ Notifier = (*Telegram)(nil)
+
+// NewTelegram creates a notifier for the bot with botToken posting to chatID, e.g. "-1001234567890"
+// or "@incidents".
+func NewTelegram(botToken, chatID string, opts ...Option) *Telegram {
+	o := newOptions(opts)
+
+	return &Telegram{
+		client: o.client(fmt.Sprintf("%s/bot%s", o.telegramAPIURL, botToken)),
+		opts:   o,
+		chatID: chatID,
+	}
+}
+
+func (t *Telegram) Notify(ctx context.Context, msg Message) error {
+	msg = t.opts.withAttachmentURLs(ctx, msg)
+
+	header := fmt.Sprintf("%s %s", levelLabel(msg.Level), t.opts.title(msg))
+	text := header + "\n\n" + t.opts.text(msg, telegramMaxLength-len([]rune(header))-2, plainMarkup)
+
+	body := telegramMessage{
+		ChatID:                t.chatID,
+		Text:                  text,
+		DisableWebPagePreview: true,
+	}
+
+	if err := t.client.Post(ctx, "/sendMessage", body, nil); err != nil {
+		return fmt.Errorf("%w: telegram: %w", ErrNotifyFailed, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,178 @@
+package notifylog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var ErrRateLimited = errors.New("notifylog: notification rate limit exceeded")
+
+type ThrottleOption func(*Throttle)
+
+// WithRateLimit allows at most limit notifications per window.
+func WithRateLimit(limit int, window time.Duration) ThrottleOption {
+	return func(t *Throttle) {
+		if limit > 0 && window > 0 {
+			t.limit = limit
+			t.window = window
+		}
+	}
+}
+
+// WithDedup suppresses messages with the same level, title, text and fields for ttl after one was
+// sent. The next occurrence after ttl is sent with Message.Repeated set to the number suppressed.
+func WithDedup(ttl time.Duration) ThrottleOption {
+	return func(t *Throttle) {
+		if ttl > 0 {
+			t.dedupTTL = ttl
+		}
+	}
+}
+
+type dedupEntry struct {
+	expiresAt  time.Time
+	suppressed int
+}
+
+// Throttle protects a channel from floods, e.g. of a crash loop. Wrap the Async notifier with it so
+// that suppressed messages never take a place in the queue:
+//
+//	notifier := notifylog.NewThrottle(notifylog.NewAsync(discord),
+//	    notifylog.WithRateLimit(20, time.Minute),
+//	    notifylog.WithDedup(10*time.Minute),
+//	)
+type Throttle struct {
+	notifier Notifier
+	limit    int
+	window   time.Duration
+	dedupTTL time.Duration
+
+	mu          sync.Mutex
+	windowStart time.Time
+	windowCount int
+	seen        map[uint64]*dedupEntry
+	lastPrune   time.Time
+	suppressed  atomic.Uint64
+}
+
+var _ Notifier = (*Throttle)(nil)
+
+func NewThrottle(notifier Notifier, opts ...ThrottleOption) *Throttle {
+	throttle := &Throttle{
+		notifier:    notifier,
+		limit:       0,
+		window:      0,
+		dedupTTL:    0,
+		mu:          sync.Mutex{},
+		windowStart: time.Time{},
+		windowCount: 0,
+		seen:        make(map[uint64]*dedupEntry),
+		lastPrune:   time.Time{},
+		suppressed:  atomic.Uint64{},
+	}
+
+	for _, opt := range opts {
+		opt(throttle)
+	}
+
+	return throttle
+}
+
+// Notify returns nil for duplicates and ErrRateLimited once the rate limit is exceeded.
+func (t *Throttle) Notify(ctx context.Context, msg Message) error {
+	send, err := t.admit(&msg, time.Now())
+	if !send {
+		return err
+	}
+
+	return t.notifier.Notify(ctx, msg)
+}
+
+// Suppressed returns the number of messages dropped as duplicates or by the rate limit.
+func (t *Throttle) Suppressed() uint64 {
+	return t.suppressed.Load()
+}
+
+func (t *Throttle) admit(msg *Message, now time.Time) (bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var entry *dedupEntry
+
+	if t.dedupTTL > 0 {
+		t.prune(now)
+
+		key := fingerprint(*msg)
+
+		entry = t.seen[key]
+		if entry != nil && now.Before(entry.expiresAt) {
+			entry.suppressed++
+			t.suppressed.Add(1)
+
+			return false, nil
+		}
+
+		if entry == nil {
+			entry = &dedupEntry{
+				expiresAt:  time.Time{},
+				suppressed: 0,
+			}
+			t.seen[key] = entry
+		}
+	}
+
+	if t.limit > 0 {
+		if now.Sub(t.windowStart) >= t.window {
+			t.windowStart = now
+			t.windowCount = 0
+		}
+
+		if t.windowCount >= t.limit {
+			t.suppressed.Add(1)
+
+			return false, ErrRateLimited
+		}
+
+		t.windowCount++
+	}
+
+	if entry != nil {
+		msg.Repeated = entry.suppressed
+		entry.suppressed = 0
+		entry.expiresAt = now.Add(t.dedupTTL)
+	}
+
+	return true, nil
+}
+
+// prune forgets the fingerprints without suppressed messages whose ttl elapsed, at most once per ttl.
+func (t *Throttle) prune(now time.Time) {
+	if now.Sub(t.lastPrune) < t.dedupTTL {
+		return
+	}
+
+	t.lastPrune = now
+
+	for key, entry := range t.seen {
+		if entry.suppressed == 0 && now.After(entry.expiresAt) {
+			delete(t.seen, key)
+		}
+	}
+}
+
+func fingerprint(msg Message) uint64 {
+	hash := fnv.New64a()
+
+	_, _ = fmt.Fprintf(hash, "%d\x00%s\x00%s", msg.Level, msg.Title, msg.Text)
+
+	for _, key := range sortedKeys(msg.Fields) {
+		_, _ = fmt.Fprintf(hash, "\x00%s=%v", key, msg.Fields[key])
+	}
+
+	return hash.Sum64()
+}
@@ -0,0 +1,59 @@
+package notifylog_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/notifylog"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestThrottleDedup(t *testing.T) {
+	t.Parallel()
+
+	notifier := newRecordingNotifier(0, nil)
+	throttle := notifylog.NewThrottle(notifier, notifylog.WithDedup(50*time.Millisecond))
+
+	crash := leveledMessage(zerolog.ErrorLevel, "worker crashed", map[string]any{"worker": "billing"})
+
+	for range 5 {
+		require.NoError(t, throttle.Notify(t.Context(), crash))
+	}
+
+	other := crash
+	other.Fields = map[string]any{"worker": "shipping"}
+	require.NoError(t, throttle.Notify(t.Context(), other))
+
+	_, sent := notifier.snapshot()
+	require.Equal(t, []string{"worker crashed", "worker crashed"}, sent)
+	require.Equal(t, uint64(4), throttle.Suppressed())
+
+	time.Sleep(60 * time.Millisecond)
+
+	require.NoError(t, throttle.Notify(t.Context(), crash))
+
+	notifier.mu.Lock()
+	defer notifier.mu.Unlock()
+
+	require.Equal(t, []int{0, 0, 4}, notifier.repeated)
+}
+
+func TestThrottleRateLimit(t *testing.T) {
+	t.Parallel()
+
+	notifier := newRecordingNotifier(0, nil)
+	throttle := notifylog.NewThrottle(notifier, notifylog.WithRateLimit(2, 50*time.Millisecond))
+
+	require.NoError(t, throttle.Notify(t.Context(), titledMessage("1")))
+	require.NoError(t, throttle.Notify(t.Context(), titledMessage("2")))
+	require.ErrorIs(t, throttle.Notify(t.Context(), titledMessage("3")), notifylog.ErrRateLimited)
+
+	time.Sleep(60 * time.Millisecond)
+
+	require.NoError(t, throttle.Notify(t.Context(), titledMessage("4")))
+
+	_, sent := notifier.snapshot()
+	require.Equal(t, []string{"1", "2", "4"}, sent)
+	require.Equal(t, uint64(1), throttle.Suppressed())
+}
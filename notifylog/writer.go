@@ -0,0 +1,103 @@
+package notifylog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Writer is a zerolog.LevelWriter that turns log events into notifications. The message becomes the
+// title, the error the text and the remaining fields the message fields. Attach it next to the
+// regular output and put an Async notifier behind it so that logging never waits for a platform:
+//
+//	writer := notifylog.NewWriter(notifylog.NewAsync(router), zerolog.WarnLevel)
+//	log.Logger = zerolog.New(zerolog.MultiLevelWriter(os.Stdout, writer)).With().Timestamp().Logger()
+type Writer struct {
+	notifier Notifier
+	minLevel zerolog.Level
+}
+
+var _ zerolog.LevelWriter = (*Writer)(nil)
+
+func NewWriter(notifier Notifier, minLevel zerolog.Level) *Writer {
+	return &Writer{
+		notifier: notifier,
+		minLevel: minLevel,
+	}
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel never fails, so that a notification problem cannot break logging.
+func (w *Writer) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if level != zerolog.NoLevel && level < w.minLevel {
+		return len(p), nil
+	}
+
+	var fields map[string]any
+	if json.Unmarshal(p, &fields) != nil {
+		return len(p), nil
+	}
+
+	msg := eventMessage(level, fields)
+	if msg.Level < w.minLevel {
+		return len(p), nil
+	}
+
+	_ = w.notifier.Notify(context.Background(), msg)
+
+	return len(p), nil
+}
+
+func eventMessage(level zerolog.Level, fields map[string]any) Message {
+	if raw, ok := fields[zerolog.LevelFieldName].(string); ok && level == zerolog.NoLevel {
+		if parsed, err := zerolog.ParseLevel(raw); err == nil {
+			level = parsed
+		}
+	}
+
+	msg := Message{
+		Level:       level,
+		Title:       stringField(fields, zerolog.MessageFieldName),
+		Text:        stringField(fields, zerolog.ErrorFieldName),
+		Fields:      fields,
+		Time:        time.Time{},
+		Repeated:    0,
+		Attachments: nil,
+	}
+
+	if raw, ok := fields[zerolog.TimestampFieldName].(string); ok {
+		if parsed, err := time.Parse(zerolog.TimeFieldFormat, raw); err == nil {
+			msg.Time = parsed
+		}
+	}
+
+	for _, key := range []string{
+		zerolog.LevelFieldName,
+		zerolog.MessageFieldName,
+		zerolog.ErrorFieldName,
+		zerolog.TimestampFieldName,
+	} {
+		delete(fields, key)
+	}
+
+	return msg
+}
+
+func stringField(fields map[string]any, key string) string {
+	value, ok := fields[key]
+	if !ok {
+		return ""
+	}
+
+	if text, ok := value.(string); ok {
+		return text
+	}
+
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,125 @@
+// Package pagination provides cursor-based (keyset) pagination alongside the page and pageSize API of
+// httpserver.
+//
+// A cursor is an opaque base64 token holding the sort key of the row a page starts after, so pages
+// stay stable while rows are inserted and deep pages cost the same as the first one:
+//
+//	type ListOrdersRequest struct {
+//	    pagination.Request
+//	}
+//
+//	cursor, limit, err := req.Normalize()
+//	if err != nil {
+//	    return nil, httpserver.BadRequestError(err, "Invalid cursor")
+//	}
+//
+//	keyset := postgres.Keyset{Columns: []string{"created_at", "id"}, Descending: true}
+//	query, args, err := keyset.Query("SELECT id, created_at, total FROM orders WHERE store_id = $1",
+//	    []any{storeID}, cursor, limit)
+//	...
+//	orders, page := pagination.Paginate(rows, cursor, limit, func(o Order) []any {
+//	    return []any{o.CreatedAt, o.ID}
+//	})
+//	return httpserver.NewCursorPaginatedResponse(orders, page), nil
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+const (
+	DefaultLimit = 50
+	MaxLimit     = 100
+)
+
+var ErrInvalidCursor = errors.New("pagination: invalid cursor")
+
+// Cursor holds the sort key of the row a page starts after, or before when Backward is set. Values
+// are kept in their text form, which PostgreSQL parses into the column types.
+type Cursor struct {
+	Values   []string `json:"v"`
+	Backward bool     `json:"b,omitempty"`
+}
+
+// NewCursor returns a forward cursor for the sort key values. Times are formatted as RFC 3339 with
+// nanoseconds, other values with fmt.
+func NewCursor(values ...any) Cursor {
+	formatted := make([]string, 0, len(values))
+
+	for _, value := range values {
+		switch v := value.(type) {
+		case time.Time:
+			formatted = append(formatted, v.Format(time.RFC3339Nano))
+		case string:
+			formatted = append(formatted, v)
+		default:
+			formatted = append(formatted, fmt.Sprint(v))
+		}
+	}
+
+	return Cursor{
+		Values:   formatted,
+		Backward: false,
+	}
+}
+
+// DecodeCursor parses a token produced by Encode. An empty token is the zero cursor of the first page.
+func DecodeCursor(token string) (Cursor, error) {
+	var cursor Cursor
+
+	if token == "" {
+		return cursor, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return Cursor{}, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	if len(cursor.Values) == 0 {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	return cursor, nil
+}
+
+// Encode returns the opaque token of the cursor, or "" for the zero cursor.
+func (c Cursor) Encode() string {
+	if c.IsZero() {
+		return ""
+	}
+
+	data, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func (c Cursor) IsZero() bool {
+	return len(c.Values) == 0
+}
+
+// Args returns the values as query arguments.
+func (c Cursor) Args() []any {
+	args := make([]any, 0, len(c.Values))
+	for _, value := range c.Values {
+		args = append(args, value)
+	}
+
+	return args
+}
+
+func (c Cursor) reversed() Cursor {
+	return Cursor{
+		Values:   slices.Clone(c.Values),
+		Backward: !c.Backward,
+	}
+}
@@ -0,0 +1,104 @@
+package pagination_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/pagination"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	createdAt := time.Date(2025, 3, 1, 10, 30, 0, 123456000, time.UTC)
+
+	cursor := pagination.NewCursor(createdAt, int64(42), "abc")
+	require.Equal(t, []string{"2025-03-01T10:30:00.123456Z", "42", "abc"}, cursor.Values)
+
+	token := cursor.Encode()
+	require.NotEmpty(t, token)
+
+	decoded, err := pagination.DecodeCursor(token)
+	require.NoError(t, err)
+	require.Equal(t, cursor, decoded)
+	require.Equal(t, []any{"2025-03-01T10:30:00.123456Z", "42", "abc"}, decoded.Args())
+}
+
+func TestDecodeCursor(t *testing.T) {
+	t.Parallel()
+
+	cursor, err := pagination.DecodeCursor("")
+	require.NoError(t, err)
+	require.True(t, cursor.IsZero())
+	require.Empty(t, cursor.Encode())
+
+	for _, token := range []string{"not base64!", "bm90IGpzb24", "e30"} {
+		_, err := pagination.DecodeCursor(token)
+		require.ErrorIs(t, err, pagination.ErrInvalidCursor, token)
+	}
+}
+
+func TestRequest_Normalize(t *testing.T) {
+	t.Parallel()
+
+	token := pagination.NewCursor(7).Encode()
+
+	cursor, limit, err := pagination.Request{Cursor: token, Limit: 0}.Normalize()
+	require.NoError(t, err)
+	require.Equal(t, []string{"7"}, cursor.Values)
+	require.Equal(t, pagination.DefaultLimit, limit)
+
+	_, limit, err = pagination.Request{Cursor: "", Limit: 1000}.Normalize()
+	require.NoError(t, err)
+	require.Equal(t, pagination.MaxLimit, limit)
+
+	_, _, err = pagination.Request{Cursor: "!", Limit: 10}.Normalize()
+	require.ErrorIs(t, err, pagination.ErrInvalidCursor)
+}
+
+func idKey(id int) []any {
+	return []any{id}
+}
+
+func decode(t *testing.T, token string) pagination.Cursor {
+	t.Helper()
+
+	cursor, err := pagination.DecodeCursor(token)
+	require.NoError(t, err)
+
+	return cursor
+}
+
+func TestPaginate(t *testing.T) {
+	t.Parallel()
+
+	// First page: 3 rows of a limit of 2 means there is a next page but no previous one.
+	rows, page := pagination.Paginate([]int{1, 2, 3}, pagination.Cursor{}, 2, idKey)
+	require.Equal(t, []int{1, 2}, rows)
+	require.Empty(t, page.PrevCursor)
+	require.Equal(t, 2, page.Limit)
+
+	next := decode(t, page.NextCursor)
+	require.Equal(t, pagination.Cursor{Values: []string{"2"}, Backward: false}, next)
+
+	// Last page reached going forward.
+	rows, page = pagination.Paginate([]int{3, 4}, next, 2, idKey)
+	require.Equal(t, []int{3, 4}, rows)
+	require.Empty(t, page.NextCursor)
+
+	prev := decode(t, page.PrevCursor)
+	require.Equal(t, pagination.Cursor{Values: []string{"3"}, Backward: true}, prev)
+
+	// Going back returns rows in descending order, which are put back into natural order.
+	rows, page = pagination.Paginate([]int{2, 1}, prev, 2, idKey)
+	require.Equal(t, []int{1, 2}, rows)
+	require.Empty(t, page.PrevCursor)
+	require.Equal(t, pagination.Cursor{Values: []string{"2"}, Backward: false}, decode(t, page.NextCursor))
+
+	// Empty pages have no cursors.
+	rows, page = pagination.Paginate([]int{}, next, 2, idKey)
+	require.Empty(t, rows)
+	require.Empty(t, page.NextCursor)
+	require.Empty(t, page.PrevCursor)
+}
@@ -0,0 +1,86 @@
+package pagination
+
+import (
+	"slices"
+)
+
+// Request is embedded in request structs to bind the cursor and limit query params.
+type Request struct {
+	Cursor string `json:"cursor" query:"cursor"`
+	Limit  int    `json:"limit"  query:"limit"`
+}
+
+// Normalize decodes the cursor and clamps the limit to 1..MaxLimit, using DefaultLimit when unset.
+func (r Request) Normalize() (Cursor, int, error) {
+	cursor, err := DecodeCursor(r.Cursor)
+	if err != nil {
+		return Cursor{}, 0, err
+	}
+
+	return cursor, NormalizeLimit(r.Limit), nil
+}
+
+func NormalizeLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultLimit
+	}
+
+	return min(limit, MaxLimit)
+}
+
+// Page is the response envelope of a cursor-paginated list. A cursor is empty when there are no rows
+// in its direction.
+type Page struct {
+	NextCursor string `example:"eyJ2IjpbIjQyIl19" json:"nextCursor,omitempty"`
+	PrevCursor string `example:"eyJ2IjpbIjEzIl0sImIiOnRydWV9" json:"prevCursor,omitempty"`
+	Limit      int    `example:"50" json:"limit"`
+}
+
+// Paginate builds the page from rows fetched with up to limit+1 rows in the direction of cursor, as
+// returned by postgres.Keyset.Query. The extra row only signals that more rows follow and is dropped.
+// Rows of a backward page are put back into the natural order. key returns the sort key of a row in
+// the order of the keyset columns.
+func Paginate[T any](rows []T, cursor Cursor, limit int, key func(T) []any) ([]T, *Page) {
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+
+	if cursor.Backward {
+		rows = slices.Clone(rows)
+		slices.Reverse(rows)
+	}
+
+	page := &Page{
+		NextCursor: "",
+		PrevCursor: "",
+		Limit:      limit,
+	}
+
+	if len(rows) == 0 {
+		return rows, page
+	}
+
+	first := NewCursor(key(rows[0])...).reversed()
+	last := NewCursor(key(rows[len(rows)-1])...)
+
+	// Forward pages have rows before them unless they are the first page; backward pages always have
+	// the rows they were paged back from after them.
+	if cursor.Backward {
+		page.NextCursor = last.Encode()
+
+		if hasMore {
+			page.PrevCursor = first.Encode()
+		}
+	} else {
+		if hasMore {
+			page.NextCursor = last.Encode()
+		}
+
+		if !cursor.IsZero() {
+			page.PrevCursor = first.Encode()
+		}
+	}
+
+	return rows, page
+}
@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/andyle182810/gframework/pagination"
+	"github.com/jackc/pgx/v5"
+)
+
+// Keyset orders rows by Columns, all ascending or all descending, for cursor pagination. The columns
+// must be unique together, e.g. created_at and id, and selected by the paginated query.
+type Keyset struct {
+	Columns    []string
+	Descending bool
+}
+
+// Query wraps query so it returns up to limit+1 rows after cursor (before it for backward cursors) in
+// keyset order, ready for pagination.Paginate. args are the arguments of query; the cursor values and
+// limit are appended to them.
+func (k Keyset) Query(query string, args []any, cursor pagination.Cursor, limit int) (string, []any, error) {
+	if !cursor.IsZero() && len(cursor.Values) != len(k.Columns) {
+		return "", nil, fmt.Errorf("%w: expected %d values, got %d",
+			pagination.ErrInvalidCursor, len(k.Columns), len(cursor.Values))
+	}
+
+	descending := k.Descending != cursor.Backward
+
+	columns := make([]string, 0, len(k.Columns))
+	for _, column := range k.Columns {
+		columns = append(columns, pgx.Identifier{column}.Sanitize())
+	}
+
+	args = append(args[:len(args):len(args)], cursor.Args()...)
+
+	var builder strings.Builder
+
+	builder.WriteString("SELECT * FROM (")
+	builder.WriteString(query)
+	builder.WriteString(") AS keyset_page")
+
+	if !cursor.IsZero() {
+		placeholders := make([]string, 0, len(cursor.Values))
+		for i := range cursor.Values {
+			placeholders = append(placeholders, "$"+strconv.Itoa(len(args)-len(cursor.Values)+i+1))
+		}
+
+		operator := ">"
+		if descending {
+			operator = "<"
+		}
+
+		fmt.Fprintf(&builder, " WHERE (%s) %s (%s)",
+			strings.Join(columns, ", "), operator, strings.Join(placeholders, ", "))
+	}
+
+	direction := " ASC"
+	if descending {
+		direction = " DESC"
+	}
+
+	builder.WriteString(" ORDER BY ")
+	builder.WriteString(strings.Join(columns, direction+", ") + direction)
+
+	args = append(args, limit+1)
+	builder.WriteString(" LIMIT $" + strconv.Itoa(len(args)))
+
+	return builder.String(), args, nil
+}
@@ -0,0 +1,69 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/andyle182810/gframework/pagination"
+	"github.com/andyle182810/gframework/postgres"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyset_Query(t *testing.T) {
+	t.Parallel()
+
+	const base = "SELECT id, created_at FROM orders WHERE store_id = $1"
+
+	keyset := postgres.Keyset{Columns: []string{"created_at", "id"}, Descending: true}
+
+	tests := []struct {
+		name         string
+		cursor       pagination.Cursor
+		expectedSQL  string
+		expectedArgs []any
+	}{
+		{
+			name:   "first page",
+			cursor: pagination.Cursor{},
+			expectedSQL: "SELECT * FROM (" + base + `) AS keyset_page ORDER BY "created_at" DESC, "id" DESC` +
+				" LIMIT $2",
+			expectedArgs: []any{"store-1", 21},
+		},
+		{
+			name:   "forward",
+			cursor: pagination.Cursor{Values: []string{"2025-03-01T10:30:00Z", "42"}, Backward: false},
+			expectedSQL: "SELECT * FROM (" + base + `) AS keyset_page WHERE ("created_at", "id") < ($2, $3)` +
+				` ORDER BY "created_at" DESC, "id" DESC LIMIT $4`,
+			expectedArgs: []any{"store-1", "2025-03-01T10:30:00Z", "42", 21},
+		},
+		{
+			name:   "backward",
+			cursor: pagination.Cursor{Values: []string{"2025-03-01T10:30:00Z", "42"}, Backward: true},
+			expectedSQL: "SELECT * FROM (" + base + `) AS keyset_page WHERE ("created_at", "id") > ($2, $3)` +
+				` ORDER BY "created_at" ASC, "id" ASC LIMIT $4`,
+			expectedArgs: []any{"store-1", "2025-03-01T10:30:00Z", "42", 21},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			args := []any{"store-1"}
+
+			query, queryArgs, err := keyset.Query(base, args, tt.cursor, 20)
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedSQL, query)
+			require.Equal(t, tt.expectedArgs, queryArgs)
+			require.Equal(t, []any{"store-1"}, args)
+		})
+	}
+}
+
+func TestKeyset_QueryRejectsMismatchedCursor(t *testing.T) {
+	t.Parallel()
+
+	keyset := postgres.Keyset{Columns: []string{"created_at", "id"}, Descending: false}
+
+	_, _, err := keyset.Query("SELECT 1", nil, pagination.NewCursor(1), 10)
+	require.ErrorIs(t, err, pagination.ErrInvalidCursor)
+}
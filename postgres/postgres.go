@@ -21,6 +21,9 @@
 //
 // Session-level timeouts (statement_timeout, lock_timeout, idle_in_transaction_session_timeout) can be
 // configured to prevent long-running queries from blocking other connections.
+//
+// Config.CredentialsProvider supplies the username and password of every new connection instead of the
+// URL, e.g. rotating database credentials from secrets.Watcher, so that rotations need no restart.
 package postgres
 
 import (
@@ -33,6 +36,7 @@ import (
 	pgxdecimal "github.com/jackc/pgx-shopspring-decimal"
 	pgxzerolog "github.com/jackc/pgx-zerolog"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/multitracer"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/jackc/pgx/v5/tracelog"
 	"github.com/rs/zerolog/log"
@@ -59,6 +63,23 @@ type Config struct {
 	StatementTimeout         time.Duration
 	LockTimeout              time.Duration
 	IdleInTransactionTimeout time.Duration
+	// Tracer, when set, runs alongside the zerolog query logger, e.g. tracing.PgxTracer().
+	Tracer pgx.QueryTracer
+	// CredentialsProvider, when set, supplies the username and password for every new connection
+	// instead of those in URL, e.g. for rotating credentials.
+	CredentialsProvider CredentialsProvider
+}
+
+// CredentialsProvider supplies the username and password used to authenticate new connections.
+type CredentialsProvider interface {
+	Credentials(ctx context.Context) (username, password string, err error)
+}
+
+// CredentialsFunc adapts a function to CredentialsProvider.
+type CredentialsFunc func(ctx context.Context) (username, password string, err error)
+
+func (f CredentialsFunc) Credentials(ctx context.Context) (string, string, error) {
+	return f(ctx)
 }
 
 type Postgres struct {
@@ -78,12 +99,16 @@ func New(cfg *Config) (*Postgres, error) {
 	tracerLogger := log.Logger.With().Str("component", "pgx_tracer").Logger()
 	logger := pgxzerolog.NewLogger(tracerLogger, pgxzerolog.WithoutPGXModule())
 
-	tracer := &tracelog.TraceLog{
+	var tracer pgx.QueryTracer = &tracelog.TraceLog{
 		Logger:   logger,
 		LogLevel: cfg.LogLevel,
 		Config:   nil,
 	}
 
+	if cfg.Tracer != nil {
+		tracer = multitracer.New(tracer, cfg.Tracer)
+	}
+
 	pgConfig.MaxConns = cfg.MaxConnection
 	pgConfig.MinConns = cfg.MinConnection
 	pgConfig.MaxConnIdleTime = cfg.MaxConnectionIdleTime
@@ -105,6 +130,20 @@ func New(cfg *Config) (*Postgres, error) {
 		pgConfig.ConnConfig.RuntimeParams["idle_in_transaction_session_timeout"] = strconv.FormatInt(cfg.IdleInTransactionTimeout.Milliseconds(), 10)
 	}
 
+	if cfg.CredentialsProvider != nil {
+		pgConfig.BeforeConnect = func(ctx context.Context, connConfig *pgx.ConnConfig) error {
+			username, password, err := cfg.CredentialsProvider.Credentials(ctx)
+			if err != nil {
+				return fmt.Errorf("postgres: failed to fetch credentials: %w", err)
+			}
+
+			connConfig.User = username
+			connConfig.Password = password
+
+			return nil
+		}
+	}
+
 	pgConfig.AfterConnect = func(_ context.Context, conn *pgx.Conn) error {
 		pgxdecimal.Register(conn.TypeMap())
 
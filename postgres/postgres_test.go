@@ -3,6 +3,7 @@ package postgres_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"testing"
@@ -52,3 +53,23 @@ func TestPostgresConnection(t *testing.T) {
 	// Close the DB pool
 	postgres.Close()
 }
+
+func TestPostgres_CredentialsProviderError(t *testing.T) {
+	t.Parallel()
+
+	errNoCredentials := errors.New("no credentials")
+
+	db, err := postgres.New(&postgres.Config{
+		URL:            "postgres://localhost:1/app?sslmode=disable",
+		MaxConnection:  1,
+		ConnectTimeout: time.Second,
+		CredentialsProvider: postgres.CredentialsFunc(func(context.Context) (string, string, error) {
+			return "", "", errNoCredentials
+		}),
+	})
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	require.ErrorIs(t, db.Start(t.Context()), errNoCredentials)
+}
@@ -8,6 +8,7 @@ import (
 	"slices"
 	"time"
 
+	"github.com/andyle182810/gframework/clock"
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
@@ -24,6 +25,8 @@ type RetryConfig struct {
 	MaxDelay      time.Duration
 	Multiplier    float64
 	RetryableErrs []string
+	// Clock times the waits between attempts; nil means clock.Real.
+	Clock clock.Clock
 }
 
 func DefaultRetryConfig() RetryConfig {
@@ -39,6 +42,7 @@ func DefaultRetryConfig() RetryConfig {
 			"08006", // connection_failure
 			"08003", // connection_does_not_exist
 		},
+		Clock: nil,
 	}
 }
 
@@ -56,13 +60,14 @@ type RetryableFunc func(ctx context.Context) error
 func WithRetry(ctx context.Context, config RetryConfig, fn RetryableFunc) error {
 	var lastErr error
 	delay := config.InitialDelay
+	clk := clock.OrReal(config.Clock)
 
 	for attempt := 0; attempt <= config.MaxRetries; attempt++ {
 		if attempt > 0 {
 			select {
 			case <-ctx.Done():
 				return fmt.Errorf("retry cancelled: %w", ctx.Err())
-			case <-time.After(delay):
+			case <-clk.After(delay):
 				// Calculate next delay with exponential backoff
 				delay = min(time.Duration(float64(delay)*config.Multiplier), config.MaxDelay)
 			}
@@ -143,6 +143,7 @@ func TestWithRetry_SuccessOnFirstAttempt(t *testing.T) {
 		MaxDelay:      100 * time.Millisecond,
 		Multiplier:    2,
 		RetryableErrs: []string{"40001"},
+		Clock:         nil,
 	}
 
 	var attempts int32
@@ -167,6 +168,7 @@ func TestWithRetry_SuccessAfterRetries(t *testing.T) {
 		MaxDelay:      100 * time.Millisecond,
 		Multiplier:    2,
 		RetryableErrs: []string{"40001"},
+		Clock:         nil,
 	}
 
 	var attempts int32
@@ -194,6 +196,7 @@ func TestWithRetry_MaxRetriesExceeded(t *testing.T) {
 		MaxDelay:      100 * time.Millisecond,
 		Multiplier:    2,
 		RetryableErrs: []string{"40001"},
+		Clock:         nil,
 	}
 
 	var attempts int32
@@ -219,6 +222,7 @@ func TestWithRetry_NonRetryableError(t *testing.T) {
 		MaxDelay:      100 * time.Millisecond,
 		Multiplier:    2,
 		RetryableErrs: []string{"40001"},
+		Clock:         nil,
 	}
 
 	var attempts int32
@@ -244,6 +248,7 @@ func TestWithRetry_ContextCancelled(t *testing.T) {
 		MaxDelay:      1 * time.Second,
 		Multiplier:    2,
 		RetryableErrs: []string{"40001"},
+		Clock:         nil,
 	}
 
 	var attempts int32
@@ -274,6 +279,7 @@ func TestWithRetry_ExponentialBackoff(t *testing.T) {
 		MaxDelay:      500 * time.Millisecond,
 		Multiplier:    2,
 		RetryableErrs: []string{"40001"},
+		Clock:         nil,
 	}
 
 	var timestamps []time.Time
@@ -310,6 +316,7 @@ func TestWithRetry_MaxDelayRespected(t *testing.T) {
 		MaxDelay:      150 * time.Millisecond, // Cap at 150ms
 		Multiplier:    2,
 		RetryableErrs: []string{"40001"},
+		Clock:         nil,
 	}
 
 	var timestamps []time.Time
@@ -354,6 +361,7 @@ func TestWithRetryTx_Success(t *testing.T) {
 		MaxDelay:      100 * time.Millisecond,
 		Multiplier:    2,
 		RetryableErrs: []string{"40001"},
+		Clock:         nil,
 	}
 
 	err = pg.WithRetryTx(ctx, config, func(ctx context.Context, tx pgx.Tx) error {
@@ -392,6 +400,7 @@ func TestWithRetryTx_RollbackOnNonRetryableError(t *testing.T) {
 		MaxDelay:      100 * time.Millisecond,
 		Multiplier:    2,
 		RetryableErrs: []string{"40001"}, // unique_violation (23505) is NOT in this list
+		Clock:         nil,
 	}
 
 	var attempts int32
@@ -476,6 +485,7 @@ func TestWithRetry_RegularErrorNotRetryable(t *testing.T) {
 		MaxDelay:      100 * time.Millisecond,
 		Multiplier:    2,
 		RetryableErrs: []string{"40001"},
+		Clock:         nil,
 	}
 
 	var attempts int32
@@ -504,6 +514,7 @@ func TestWithRetry_ZeroMaxRetries(t *testing.T) {
 		MaxDelay:      100 * time.Millisecond,
 		Multiplier:    2,
 		RetryableErrs: []string{"40001"},
+		Clock:         nil,
 	}
 
 	var attempts int32
@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrInvalidTableName = errors.New("postgres: invalid table name")
+
+var tableNamePattern = regexp.MustCompile(`^([a-zA-Z_][a-zA-Z0-9_]*\.)?[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// Table is a validated, optionally schema-qualified table name, for packages that build their queries
+// around a configurable table such as an outbox.
+type Table struct {
+	name       string
+	identifier string
+}
+
+// NewTable validates name, e.g. "outbox" or "events.outbox", and returns ErrInvalidTableName when it
+// is not a plain or schema-qualified identifier.
+func NewTable(name string) (Table, error) {
+	if !tableNamePattern.MatchString(name) {
+		return Table{}, fmt.Errorf("%w: %q", ErrInvalidTableName, name) //nolint:exhaustruct
+	}
+
+	return Table{name: name, identifier: pgx.Identifier(strings.Split(name, ".")).Sanitize()}, nil
+}
+
+// Name returns the table name as given, e.g. for logs and metric labels.
+func (t Table) Name() string {
+	return t.name
+}
+
+// Identifier returns the quoted table name for use in queries.
+func (t Table) Identifier() string {
+	return t.identifier
+}
+
+// Index returns the quoted name of an index of the table, which Postgres creates in the schema of the table.
+func (t Table) Index(suffix string) string {
+	parts := strings.Split(t.name, ".")
+
+	return pgx.Identifier{parts[len(parts)-1] + "_" + suffix}.Sanitize()
+}
@@ -0,0 +1,23 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/andyle182810/gframework/postgres"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTable(t *testing.T) {
+	t.Parallel()
+
+	table, err := postgres.NewTable("events.outbox")
+	require.NoError(t, err)
+	require.Equal(t, "events.outbox", table.Name())
+	require.Equal(t, `"events"."outbox"`, table.Identifier())
+	require.Equal(t, `"outbox_pending_idx"`, table.Index("pending_idx"))
+
+	for _, name := range []string{"", "1outbox", "outbox; DROP TABLE orders", "a.b.c", `"outbox"`} {
+		_, err := postgres.NewTable(name)
+		require.ErrorIs(t, err, postgres.ErrInvalidTableName, name)
+	}
+}
@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Conn is the query surface shared by DBPool and pgx.Tx, so repositories can run against either.
+type Conn interface {
+	pgxscan.Querier
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+}
+
+type txContextKey struct{}
+
+func ContextWithTx(ctx context.Context, tx pgx.Tx) context.Context {
+	return context.WithValue(ctx, txContextKey{}, tx)
+}
+
+func TxFromCtx(ctx context.Context) (pgx.Tx, bool) {
+	tx, ok := ctx.Value(txContextKey{}).(pgx.Tx)
+
+	return tx, ok && tx != nil
+}
+
+// FromCtx returns the ambient transaction started by BeginCtx, or fallback when there is none.
+func FromCtx(ctx context.Context, fallback Conn) Conn {
+	if tx, ok := TxFromCtx(ctx); ok {
+		return tx
+	}
+
+	return fallback
+}
+
+// BeginCtx runs fn with a transaction stashed in its context. Repositories that resolve their
+// connection through FromCtx join it transparently. When the context already carries a transaction,
+// fn runs inside a savepoint so a nested failure only undoes its own work.
+func BeginCtx(ctx context.Context, runner TxRunner, fn func(ctx context.Context) error) error {
+	return BeginCtxOptions(ctx, runner, pgx.TxOptions{
+		IsoLevel:       "",
+		AccessMode:     "",
+		DeferrableMode: "",
+		BeginQuery:     "",
+		CommitQuery:    "",
+	}, fn)
+}
+
+func BeginCtxOptions(
+	ctx context.Context,
+	runner TxRunner,
+	txOptions pgx.TxOptions,
+	fn func(ctx context.Context) error,
+) error {
+	if outer, ok := TxFromCtx(ctx); ok {
+		return withSavepoint(ctx, outer, fn)
+	}
+
+	if runner == nil {
+		return ErrConnectionPoolNil
+	}
+
+	return runner.WithTransactionOptions(ctx, txOptions, func(ctx context.Context, tx pgx.Tx) error {
+		return fn(ContextWithTx(ctx, tx))
+	})
+}
+
+func withSavepoint(ctx context.Context, outer pgx.Tx, fn func(ctx context.Context) error) error {
+	savepoint, err := outer.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrBeginTxFailed, err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = savepoint.Rollback(ctx)
+
+			panic(p)
+		}
+	}()
+
+	if err := fn(ContextWithTx(ctx, savepoint)); err != nil {
+		if rbErr := savepoint.Rollback(ctx); rbErr != nil {
+			return fmt.Errorf("%w: %w, %w: %w", ErrTxRolledBack, err, ErrTxRollbackFailed, rbErr)
+		}
+
+		return fmt.Errorf("%w: %w", ErrTxRolledBack, err)
+	}
+
+	if err := savepoint.Commit(ctx); err != nil {
+		return fmt.Errorf("%w: %w", ErrTxCommitFailed, err)
+	}
+
+	return nil
+}
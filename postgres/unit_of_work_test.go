@@ -0,0 +1,86 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/andyle182810/gframework/postgres"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromCtx_FallbackWithoutTx(t *testing.T) {
+	t.Parallel()
+
+	pg := &postgres.Postgres{DBPool: nil}
+
+	_, ok := postgres.TxFromCtx(t.Context())
+	require.False(t, ok)
+	require.Equal(t, postgres.Conn(pg), postgres.FromCtx(t.Context(), pg))
+}
+
+func TestBeginCtx_NilRunner(t *testing.T) {
+	t.Parallel()
+
+	err := postgres.BeginCtx(t.Context(), nil, func(_ context.Context) error {
+		return nil
+	})
+	require.ErrorIs(t, err, postgres.ErrConnectionPoolNil)
+}
+
+func TestBeginCtx_CommitAndNestedRollback(t *testing.T) {
+	t.Parallel()
+
+	pg, ctx := setupTransactionTestPostgres(t)
+
+	_, err := pg.Exec(ctx, `
+		CREATE TABLE IF NOT EXISTS uow_test (
+			id SERIAL PRIMARY KEY,
+			value TEXT NOT NULL
+		)
+	`)
+	require.NoError(t, err)
+
+	insert := func(ctx context.Context, value string) error {
+		_, execErr := postgres.FromCtx(ctx, pg).Exec(ctx, "INSERT INTO uow_test (value) VALUES ($1)", value)
+
+		return execErr
+	}
+
+	err = postgres.BeginCtx(ctx, pg, func(ctx context.Context) error {
+		_, ok := postgres.TxFromCtx(ctx)
+		require.True(t, ok)
+
+		if err := insert(ctx, "outer"); err != nil {
+			return err
+		}
+
+		nestedErr := postgres.BeginCtx(ctx, pg, func(ctx context.Context) error {
+			if err := insert(ctx, "nested"); err != nil {
+				return err
+			}
+
+			return errIntentional
+		})
+		require.ErrorIs(t, nestedErr, errIntentional)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	var values []string
+
+	rows, err := pg.Query(ctx, "SELECT value FROM uow_test ORDER BY id")
+	require.NoError(t, err)
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var value string
+		require.NoError(t, rows.Scan(&value))
+
+		values = append(values, value)
+	}
+
+	require.NoError(t, rows.Err())
+	require.Equal(t, []string{"outer"}, values)
+}
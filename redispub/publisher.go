@@ -34,6 +34,8 @@ import (
 	"github.com/ThreeDotsLabs/watermill-redisstream/pkg/redisstream"
 	"github.com/ThreeDotsLabs/watermill/message"
 	goredis "github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
 )
 
 const (
@@ -110,6 +112,8 @@ func (p *RedisPublisher) PublishToTopic(ctx context.Context, topic string, messa
 	for _, content := range messageContents {
 		msg := message.NewMessage(watermill.NewUUID(), []byte(content))
 		msg.SetContext(ctx)
+		// Carry the trace context to the subscriber; a no-op unless a propagator is installed, e.g. by tracing.New.
+		otel.GetTextMapPropagator().Inject(ctx, propagation.MapCarrier(msg.Metadata))
 		messages = append(messages, msg)
 	}
 
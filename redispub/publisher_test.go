@@ -36,7 +36,7 @@ func setupTestClient(t *testing.T) *valkey.Valkey {
 		Host:            container.Host,
 		Port:            port,
 		Password:        "",
-		DB:              0,
+		DB:              container.DB,
 		DialTimeout:     5 * time.Second,
 		MaxIdleConns:    5,
 		MinIdleConns:    1,
@@ -0,0 +1,464 @@
+package redissub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultAdvisorInterval      = 30 * time.Second
+	defaultTargetDrainTime      = time.Minute
+	defaultMinConcurrency       = 1
+	defaultMaxConcurrency       = 16
+	defaultScaleDownLagFraction = 0.1
+)
+
+var (
+	ErrAdvisorAlreadyRunning = errors.New("advisor: already running")
+	ErrAdvisorDuplicateWatch = errors.New("advisor: topic is already watched for consumer group")
+	ErrInvalidConcurrency    = errors.New("advisor: min concurrency must be positive and not exceed max concurrency")
+	ErrConsumerGroupNotFound = errors.New("advisor: consumer group not found")
+)
+
+type ScalingAction string
+
+const (
+	ScalingActionNone      ScalingAction = "none"
+	ScalingActionScaleUp   ScalingAction = "scale_up"
+	ScalingActionScaleDown ScalingAction = "scale_down"
+)
+
+type Scaler interface {
+	Concurrency() int
+	SetConcurrency(n int) error
+}
+
+type AdvisorMetrics interface {
+	ConsumerLag(topic, consumerGroup string, lag int64)
+	ConsumerPending(topic, consumerGroup string, pending int64)
+	ProcessingRate(topic, consumerGroup string, perSecond float64)
+	RecommendedConcurrency(topic, consumerGroup string, concurrency int)
+}
+
+type GroupStats struct {
+	Topic          string
+	ConsumerGroup  string
+	Lag            int64
+	Pending        int64
+	Consumers      int64
+	ProcessingRate float64 // Entries read per second since the previous evaluation
+}
+
+type Recommendation struct {
+	GroupStats
+
+	CurrentConcurrency     int
+	RecommendedConcurrency int
+	Action                 ScalingAction
+	EvaluatedAt            time.Time
+}
+
+type ScalingPolicy struct {
+	MinConcurrency  int           // Lower bound for recommendations
+	MaxConcurrency  int           // Upper bound for recommendations
+	TargetDrainTime time.Duration // Lag should be drained within this duration at the observed rate
+	// ScaleDownLagFraction is the share of the drain budget below which the group is considered over-provisioned.
+	ScaleDownLagFraction float64
+}
+
+func DefaultScalingPolicy() ScalingPolicy {
+	return ScalingPolicy{
+		MinConcurrency:       defaultMinConcurrency,
+		MaxConcurrency:       defaultMaxConcurrency,
+		TargetDrainTime:      defaultTargetDrainTime,
+		ScaleDownLagFraction: defaultScaleDownLagFraction,
+	}
+}
+
+// Recommend returns the concurrency needed to drain the observed lag within TargetDrainTime,
+// assuming the processing rate scales linearly with concurrency.
+func (p ScalingPolicy) Recommend(stats GroupStats, current int) int {
+	current = p.clamp(current)
+
+	if stats.Lag <= 0 {
+		if stats.Pending == 0 {
+			return p.clamp(current - 1)
+		}
+
+		return current
+	}
+
+	if stats.ProcessingRate <= 0 {
+		// Lag is building up but nothing is being consumed; add capacity one step at a time.
+		return p.clamp(current + 1)
+	}
+
+	drainBudget := p.TargetDrainTime.Seconds()
+	perWorkerRate := stats.ProcessingRate / float64(current)
+	needed := int(math.Ceil(float64(stats.Lag) / (perWorkerRate * drainBudget)))
+
+	if needed > current {
+		return p.clamp(needed)
+	}
+
+	if float64(stats.Lag) < stats.ProcessingRate*drainBudget*p.ScaleDownLagFraction {
+		return p.clamp(current - 1)
+	}
+
+	return current
+}
+
+func (p ScalingPolicy) clamp(n int) int {
+	return max(p.MinConcurrency, min(p.MaxConcurrency, n))
+}
+
+func (p ScalingPolicy) validate() error {
+	if p.MinConcurrency <= 0 || p.MinConcurrency > p.MaxConcurrency {
+		return fmt.Errorf("%w: min=%d max=%d", ErrInvalidConcurrency, p.MinConcurrency, p.MaxConcurrency)
+	}
+
+	return nil
+}
+
+type AdvisorOption func(*Advisor)
+
+func WithAdvisorInterval(d time.Duration) AdvisorOption {
+	return func(a *Advisor) {
+		if d > 0 {
+			a.interval = d
+		}
+	}
+}
+
+func WithScalingPolicy(policy ScalingPolicy) AdvisorOption {
+	return func(a *Advisor) {
+		a.policy = policy
+	}
+}
+
+func WithAdvisorMetrics(m AdvisorMetrics) AdvisorOption {
+	return func(a *Advisor) {
+		a.metrics = m
+	}
+}
+
+func WithRecommendationHandler(fn func(Recommendation)) AdvisorOption {
+	return func(a *Advisor) {
+		a.onRecommendation = fn
+	}
+}
+
+type watchTarget struct {
+	topic           string
+	consumerGroup   string
+	scaler          Scaler
+	lastEntriesRead int64
+	lastEvaluatedAt time.Time
+	recommended     int
+}
+
+// Advisor periodically inspects consumer group lag and throughput and recommends handler concurrency.
+// When a Scaler is attached to a watched topic the recommendation is applied automatically, e.g. to the
+// concurrency of the Subscriber consuming it:
+//
+//	advisor.WatchSubscriber(sub, sub)
+type Advisor struct {
+	name             string
+	redisClient      goredis.UniversalClient
+	interval         time.Duration
+	policy           ScalingPolicy
+	metrics          AdvisorMetrics
+	onRecommendation func(Recommendation)
+	targets          []*watchTarget
+	targetsMux       sync.Mutex
+	healthy          atomic.Bool
+	running          atomic.Bool
+	shutdownSignal   chan struct{}
+	stoppedSignal    chan struct{}
+}
+
+func NewAdvisor(redisClient goredis.UniversalClient, opts ...AdvisorOption) (*Advisor, error) {
+	if redisClient == nil {
+		return nil, ErrNilRedisClient
+	}
+
+	//nolint:exhaustruct
+	advisor := &Advisor{
+		name:           "redissub-advisor",
+		redisClient:    redisClient,
+		interval:       defaultAdvisorInterval,
+		policy:         DefaultScalingPolicy(),
+		targets:        make([]*watchTarget, 0),
+		shutdownSignal: make(chan struct{}),
+		stoppedSignal:  make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(advisor)
+	}
+
+	if err := advisor.policy.validate(); err != nil {
+		return nil, err
+	}
+
+	return advisor, nil
+}
+
+// Watch registers a consumer group/topic pair for evaluation. The scaler is optional; without it the
+// advisor only emits recommendations and metrics.
+func (a *Advisor) Watch(consumerGroup, topic string, scaler Scaler) error {
+	if consumerGroup == "" {
+		return ErrEmptyConsumerGroup
+	}
+
+	if topic == "" {
+		return ErrEmptyTopicName
+	}
+
+	a.targetsMux.Lock()
+	defer a.targetsMux.Unlock()
+
+	for _, target := range a.targets {
+		if target.topic == topic && target.consumerGroup == consumerGroup {
+			return fmt.Errorf("%w: %s/%s", ErrAdvisorDuplicateWatch, consumerGroup, topic)
+		}
+	}
+
+	//nolint:exhaustruct
+	a.targets = append(a.targets, &watchTarget{
+		topic:         topic,
+		consumerGroup: consumerGroup,
+		scaler:        scaler,
+		recommended:   a.policy.MinConcurrency,
+	})
+
+	return nil
+}
+
+func (a *Advisor) WatchSubscriber(sub *Subscriber, scaler Scaler) error {
+	return a.Watch(sub.ConsumerGroup(), sub.Topic(), scaler)
+}
+
+func (a *Advisor) Start(ctx context.Context) error {
+	if !a.running.CompareAndSwap(false, true) {
+		return ErrAdvisorAlreadyRunning
+	}
+
+	defer close(a.stoppedSignal)
+
+	log.Info().
+		Str("source", "gframework").
+		Str("service_name", a.Name()).
+		Dur("interval", a.interval).
+		Msg("Consumer group advisor is starting")
+
+	a.healthy.Store(true)
+
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			a.healthy.Store(false)
+
+			return ctx.Err()
+		case <-a.shutdownSignal:
+			a.healthy.Store(false)
+
+			return nil
+		case <-ticker.C:
+			a.Evaluate(ctx)
+		}
+	}
+}
+
+func (a *Advisor) Stop() error {
+	if !a.running.CompareAndSwap(true, false) {
+		return nil
+	}
+
+	a.healthy.Store(false)
+	close(a.shutdownSignal)
+
+	select {
+	case <-a.stoppedSignal:
+	case <-time.After(defaultShutdownTimeout):
+		log.Error().
+			Str("source", "gframework").
+			Str("service_name", a.Name()).
+			Dur("timeout", defaultShutdownTimeout).
+			Msg("Timeout waiting for consumer group advisor to stop")
+	}
+
+	log.Info().Str("source", "gframework").Str("service_name", a.Name()).Msg("Consumer group advisor stopped")
+
+	return nil
+}
+
+func (a *Advisor) Name() string {
+	return a.name
+}
+
+func (a *Advisor) IsHealthy() bool {
+	return a.healthy.Load()
+}
+
+// Evaluate runs a single evaluation pass over all watched targets and returns the recommendations produced.
+func (a *Advisor) Evaluate(ctx context.Context) []Recommendation {
+	a.targetsMux.Lock()
+	targets := make([]*watchTarget, len(a.targets))
+	copy(targets, a.targets)
+	a.targetsMux.Unlock()
+
+	recommendations := make([]Recommendation, 0, len(targets))
+
+	for _, target := range targets {
+		rec, err := a.evaluateTarget(ctx, target)
+		if err != nil {
+			log.Warn().
+				Str("source", "gframework").
+				Err(err).
+				Str("topic", target.topic).
+				Str("consumer_group", target.consumerGroup).
+				Msg("Consumer group evaluation failed")
+
+			continue
+		}
+
+		recommendations = append(recommendations, rec)
+	}
+
+	return recommendations
+}
+
+// evaluateTarget computes the recommendation for target. The scaler, metrics and recommendation handler
+// are called without holding targetsMux, so they may call Watch or Evaluate.
+func (a *Advisor) evaluateTarget(ctx context.Context, target *watchTarget) (Recommendation, error) {
+	stats, entriesRead, err := a.groupStats(ctx, target)
+	if err != nil {
+		return Recommendation{}, err
+	}
+
+	now := time.Now()
+
+	a.targetsMux.Lock()
+
+	if !target.lastEvaluatedAt.IsZero() && entriesRead >= target.lastEntriesRead {
+		elapsed := now.Sub(target.lastEvaluatedAt).Seconds()
+		if elapsed > 0 {
+			stats.ProcessingRate = float64(entriesRead-target.lastEntriesRead) / elapsed
+		}
+	}
+
+	target.lastEntriesRead = entriesRead
+	target.lastEvaluatedAt = now
+	current, scaler := target.recommended, target.scaler
+
+	a.targetsMux.Unlock()
+
+	if scaler != nil {
+		current = scaler.Concurrency()
+	}
+
+	recommended := a.policy.Recommend(stats, current)
+
+	a.targetsMux.Lock()
+	target.recommended = recommended
+	a.targetsMux.Unlock()
+
+	rec := Recommendation{
+		GroupStats:             stats,
+		CurrentConcurrency:     current,
+		RecommendedConcurrency: recommended,
+		Action:                 scalingAction(current, recommended),
+		EvaluatedAt:            now,
+	}
+
+	a.publish(rec, scaler)
+
+	return rec, nil
+}
+
+// groupStats reads the lag of the consumer group of target and the number of entries it has read.
+func (a *Advisor) groupStats(ctx context.Context, target *watchTarget) (GroupStats, int64, error) {
+	groups, err := a.redisClient.XInfoGroups(ctx, target.topic).Result()
+	if err != nil {
+		return GroupStats{}, 0, fmt.Errorf("failed to inspect consumer groups: %w", err)
+	}
+
+	for _, group := range groups {
+		if group.Name != target.consumerGroup {
+			continue
+		}
+
+		stats := GroupStats{
+			Topic:          target.topic,
+			ConsumerGroup:  target.consumerGroup,
+			Lag:            max(group.Lag, 0),
+			Pending:        group.Pending,
+			Consumers:      group.Consumers,
+			ProcessingRate: 0,
+		}
+
+		return stats, group.EntriesRead, nil
+	}
+
+	return GroupStats{}, 0, fmt.Errorf("%w: %s/%s", ErrConsumerGroupNotFound, target.consumerGroup, target.topic)
+}
+
+func (a *Advisor) publish(rec Recommendation, scaler Scaler) {
+	if a.metrics != nil {
+		a.metrics.ConsumerLag(rec.Topic, rec.ConsumerGroup, rec.Lag)
+		a.metrics.ConsumerPending(rec.Topic, rec.ConsumerGroup, rec.Pending)
+		a.metrics.ProcessingRate(rec.Topic, rec.ConsumerGroup, rec.ProcessingRate)
+		a.metrics.RecommendedConcurrency(rec.Topic, rec.ConsumerGroup, rec.RecommendedConcurrency)
+	}
+
+	if rec.Action != ScalingActionNone {
+		log.Info().
+			Str("source", "gframework").
+			Str("topic", rec.Topic).
+			Str("consumer_group", rec.ConsumerGroup).
+			Int64("lag", rec.Lag).
+			Float64("processing_rate", rec.ProcessingRate).
+			Int("current_concurrency", rec.CurrentConcurrency).
+			Int("recommended_concurrency", rec.RecommendedConcurrency).
+			Str("action", string(rec.Action)).
+			Msg("Consumer group scaling recommended")
+
+		if scaler != nil {
+			if err := scaler.SetConcurrency(rec.RecommendedConcurrency); err != nil {
+				log.Error().
+					Str("source", "gframework").
+					Err(err).
+					Str("topic", rec.Topic).
+					Str("consumer_group", rec.ConsumerGroup).
+					Msg("Failed to apply recommended concurrency")
+			}
+		}
+	}
+
+	if a.onRecommendation != nil {
+		a.onRecommendation(rec)
+	}
+}
+
+func scalingAction(current, recommended int) ScalingAction {
+	switch {
+	case recommended > current:
+		return ScalingActionScaleUp
+	case recommended < current:
+		return ScalingActionScaleDown
+	default:
+		return ScalingActionNone
+	}
+}
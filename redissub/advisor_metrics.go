@@ -0,0 +1,58 @@
+package redissub
+
+import (
+	"fmt"
+
+	"github.com/andyle182810/gframework/metricserver"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PrometheusAdvisorMetrics implements AdvisorMetrics with Prometheus gauges labeled by topic and
+// consumer group. The recommended concurrency gauge is meant as an external metric for a Kubernetes
+// HorizontalPodAutoscaler, e.g. through prometheus-adapter.
+type PrometheusAdvisorMetrics struct {
+	lag            *prometheus.GaugeVec
+	pending        *prometheus.GaugeVec
+	processingRate *prometheus.GaugeVec
+	recommended    *prometheus.GaugeVec
+}
+
+var _ AdvisorMetrics = (*PrometheusAdvisorMetrics)(nil)
+
+// NewPrometheusAdvisorMetrics registers the advisor metrics with reg, e.g. the registerer of metricserver.
+func NewPrometheusAdvisorMetrics(reg prometheus.Registerer) (*PrometheusAdvisorMetrics, error) {
+	const subsystem = "redissub"
+
+	m := &PrometheusAdvisorMetrics{
+		lag: metricserver.NewGaugeVec(subsystem, "consumer_lag",
+			"Number of stream entries not yet delivered to the consumer group.", "topic", "consumer_group"),
+		pending: metricserver.NewGaugeVec(subsystem, "consumer_pending",
+			"Number of entries delivered to the consumer group but not acknowledged.", "topic", "consumer_group"),
+		processingRate: metricserver.NewGaugeVec(subsystem, "processing_rate",
+			"Entries read by the consumer group per second.", "topic", "consumer_group"),
+		recommended: metricserver.NewGaugeVec(subsystem, "recommended_concurrency",
+			"Handler concurrency recommended by the advisor.", "topic", "consumer_group"),
+	}
+
+	if err := metricserver.Register(reg, m.lag, m.pending, m.processingRate, m.recommended); err != nil {
+		return nil, fmt.Errorf("redissub: failed to register advisor metrics: %w", err)
+	}
+
+	return m, nil
+}
+
+func (m *PrometheusAdvisorMetrics) ConsumerLag(topic, consumerGroup string, lag int64) {
+	m.lag.WithLabelValues(topic, consumerGroup).Set(float64(lag))
+}
+
+func (m *PrometheusAdvisorMetrics) ConsumerPending(topic, consumerGroup string, pending int64) {
+	m.pending.WithLabelValues(topic, consumerGroup).Set(float64(pending))
+}
+
+func (m *PrometheusAdvisorMetrics) ProcessingRate(topic, consumerGroup string, perSecond float64) {
+	m.processingRate.WithLabelValues(topic, consumerGroup).Set(perSecond)
+}
+
+func (m *PrometheusAdvisorMetrics) RecommendedConcurrency(topic, consumerGroup string, concurrency int) {
+	m.recommended.WithLabelValues(topic, consumerGroup).Set(float64(concurrency))
+}
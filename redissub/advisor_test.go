@@ -0,0 +1,248 @@
+package redissub_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/redissub"
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/prometheus/client_golang/prometheus"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+type stubScaler struct {
+	mu          sync.Mutex
+	concurrency int
+}
+
+func (s *stubScaler) Concurrency() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.concurrency
+}
+
+func (s *stubScaler) SetConcurrency(n int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.concurrency = n
+
+	return nil
+}
+
+// addBacklog adds count entries to topic and creates consumerGroup before them, so that all of them are
+// lag of the group.
+func addBacklog(t *testing.T, client goredis.UniversalClient, topic, consumerGroup string, count int) {
+	t.Helper()
+
+	ctx := t.Context()
+
+	require.NoError(t, client.XGroupCreateMkStream(ctx, topic, consumerGroup, "0").Err())
+
+	for i := range count {
+		require.NoError(t, client.XAdd(ctx, &goredis.XAddArgs{
+			Stream:     topic,
+			NoMkStream: false,
+			MaxLen:     0,
+			MinID:      "",
+			Approx:     false,
+			Limit:      0,
+			Mode:       "",
+			ID:         "",
+			Values:     map[string]any{"n": i},
+		}).Err())
+	}
+}
+
+func newScalingPolicy() redissub.ScalingPolicy {
+	return redissub.ScalingPolicy{
+		MinConcurrency:       1,
+		MaxConcurrency:       10,
+		TargetDrainTime:      10 * time.Second,
+		ScaleDownLagFraction: 0.1,
+	}
+}
+
+func newGroupStats(lag, pending int64, rate float64) redissub.GroupStats {
+	return redissub.GroupStats{
+		Topic:          "orders",
+		ConsumerGroup:  "workers",
+		Lag:            lag,
+		Pending:        pending,
+		Consumers:      1,
+		ProcessingRate: rate,
+	}
+}
+
+func TestScalingPolicy_Recommend(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		stats    redissub.GroupStats
+		current  int
+		expected int
+	}{
+		{
+			name:     "scales up to drain lag within target",
+			stats:    newGroupStats(400, 5, 10),
+			current:  2,
+			expected: 8,
+		},
+		{
+			name:     "caps at max concurrency",
+			stats:    newGroupStats(100000, 5, 10),
+			current:  2,
+			expected: 10,
+		},
+		{
+			name:     "adds one step when nothing is consumed",
+			stats:    newGroupStats(50, 0, 0),
+			current:  3,
+			expected: 4,
+		},
+		{
+			name:     "scales down when idle",
+			stats:    newGroupStats(0, 0, 0),
+			current:  3,
+			expected: 2,
+		},
+		{
+			name:     "keeps concurrency while pending messages remain",
+			stats:    newGroupStats(0, 4, 5),
+			current:  3,
+			expected: 3,
+		},
+		{
+			name:     "scales down when lag is far below budget",
+			stats:    newGroupStats(5, 1, 100),
+			current:  4,
+			expected: 3,
+		},
+		{
+			name:     "never goes below min concurrency",
+			stats:    newGroupStats(0, 0, 0),
+			current:  1,
+			expected: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			policy := newScalingPolicy()
+			require.Equal(t, tt.expected, policy.Recommend(tt.stats, tt.current))
+		})
+	}
+}
+
+func TestNewAdvisor_Validation(t *testing.T) {
+	t.Parallel()
+
+	_, err := redissub.NewAdvisor(nil)
+	require.ErrorIs(t, err, redissub.ErrNilRedisClient)
+
+	client := goredis.NewClient(&goredis.Options{Addr: "127.0.0.1:0"}) //nolint:exhaustruct
+
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	invalid := newScalingPolicy()
+	invalid.MinConcurrency = 0
+
+	_, err = redissub.NewAdvisor(client, redissub.WithScalingPolicy(invalid))
+	require.ErrorIs(t, err, redissub.ErrInvalidConcurrency)
+
+	advisor, err := redissub.NewAdvisor(client)
+	require.NoError(t, err)
+	require.Equal(t, "redissub-advisor", advisor.Name())
+	require.False(t, advisor.IsHealthy())
+
+	require.NoError(t, advisor.Watch("workers", "orders", nil))
+	require.ErrorIs(t, advisor.Watch("workers", "orders", nil), redissub.ErrAdvisorDuplicateWatch)
+	require.ErrorIs(t, advisor.Watch("", "orders", nil), redissub.ErrEmptyConsumerGroup)
+	require.ErrorIs(t, advisor.Watch("workers", "", nil), redissub.ErrEmptyTopicName)
+}
+
+func TestAdvisor_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	client := testutil.NewFakeValkey(t).Client
+	addBacklog(t, client, "orders", "workers", 5)
+
+	reg := prometheus.NewRegistry()
+	metrics, err := redissub.NewPrometheusAdvisorMetrics(reg)
+	require.NoError(t, err)
+
+	scaler := &stubScaler{mu: sync.Mutex{}, concurrency: 1}
+
+	var (
+		advisor  *redissub.Advisor
+		handled  []redissub.Recommendation
+		watchErr error
+	)
+
+	advisor, err = redissub.NewAdvisor(client,
+		redissub.WithScalingPolicy(newScalingPolicy()),
+		redissub.WithAdvisorMetrics(metrics),
+		redissub.WithRecommendationHandler(func(rec redissub.Recommendation) {
+			handled = append(handled, rec)
+			// The handler runs without the advisor's lock held, so it may watch more topics.
+			watchErr = advisor.Watch("workers", "payments", nil)
+		}),
+	)
+	require.NoError(t, err)
+	require.NoError(t, advisor.Watch("workers", "orders", scaler))
+
+	recommendations := advisor.Evaluate(t.Context())
+	require.Len(t, recommendations, 1)
+
+	rec := recommendations[0]
+	require.Equal(t, "orders", rec.Topic)
+	require.Equal(t, "workers", rec.ConsumerGroup)
+	require.Equal(t, int64(5), rec.Lag)
+	require.Equal(t, 1, rec.CurrentConcurrency)
+	require.Equal(t, 2, rec.RecommendedConcurrency, "lag without throughput adds one step")
+	require.Equal(t, redissub.ScalingActionScaleUp, rec.Action)
+	require.Equal(t, 2, scaler.Concurrency())
+	require.Equal(t, []redissub.Recommendation{rec}, handled)
+	require.NoError(t, watchErr)
+
+	expected := `
+# HELP gframework_redissub_consumer_lag Number of stream entries not yet delivered to the consumer group.
+# TYPE gframework_redissub_consumer_lag gauge
+gframework_redissub_consumer_lag{consumer_group="workers",topic="orders"} 5
+# HELP gframework_redissub_recommended_concurrency Handler concurrency recommended by the advisor.
+# TYPE gframework_redissub_recommended_concurrency gauge
+gframework_redissub_recommended_concurrency{consumer_group="workers",topic="orders"} 2
+`
+
+	require.NoError(t, promtestutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"gframework_redissub_consumer_lag", "gframework_redissub_recommended_concurrency"))
+
+	// payments has no stream, so its evaluation fails and is left out.
+	require.Len(t, advisor.Evaluate(t.Context()), 1)
+}
+
+func TestAdvisor_EvaluateMissingConsumerGroup(t *testing.T) {
+	t.Parallel()
+
+	client := testutil.NewFakeValkey(t).Client
+	addBacklog(t, client, "orders", "workers", 5)
+
+	scaler := &stubScaler{mu: sync.Mutex{}, concurrency: 3}
+
+	advisor, err := redissub.NewAdvisor(client, redissub.WithScalingPolicy(newScalingPolicy()))
+	require.NoError(t, err)
+	require.NoError(t, advisor.Watch("billing", "orders", scaler))
+
+	require.Empty(t, advisor.Evaluate(t.Context()), "a missing group must not be recommended a scale-down")
+	require.Equal(t, 3, scaler.Concurrency())
+}
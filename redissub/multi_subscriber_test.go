@@ -27,6 +27,7 @@ func setupMultiTestClient(t *testing.T) *valkey.Valkey {
 	valkeyClient, err := valkey.New(&valkey.Config{
 		Host: container.Host,
 		Port: port,
+		DB:   container.DB,
 	})
 	require.NoError(t, err)
 
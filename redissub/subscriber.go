@@ -38,6 +38,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -45,11 +46,13 @@ import (
 	"github.com/ThreeDotsLabs/watermill/message"
 	goredis "github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/codes"
 )
 
 const (
 	defaultShutdownTimeout = 20 * time.Second
 	defaultExecTimeout     = 30 * time.Second
+	defaultConcurrency     = 1
 )
 
 var (
@@ -61,6 +64,7 @@ var (
 	ErrMaxRetriesExceeded       = errors.New("subscriber: max retries exceeded")
 	ErrExecTimeout              = errors.New("subscriber: message handler execution timed out")
 	ErrAlreadyRunning           = errors.New("subscriber: already running")
+	ErrNonPositiveConcurrency   = errors.New("subscriber: concurrency must be positive")
 )
 
 type MessageHandler func(ctx context.Context, payload message.Payload) error
@@ -85,6 +89,7 @@ type SubscriberConfig struct {
 	MaxIdleTime     time.Duration // Max idle time before message can be claimed
 	ShutdownTimeout time.Duration // Maximum time to wait for graceful shutdown before force closing
 	ExecTimeout     time.Duration // Maximum time allowed for message handler execution
+	Concurrency     int           // Number of messages handled at once, see Subscriber.SetConcurrency
 	Metrics         Metrics
 	Retry           *RetryConfig
 }
@@ -137,6 +142,15 @@ func WithExecTimeout(d time.Duration) SubscriberOption {
 	}
 }
 
+// WithConcurrency sets the number of messages handled at once; values below 1 are ignored.
+func WithConcurrency(n int) SubscriberOption {
+	return func(c *SubscriberConfig) {
+		if n > 0 {
+			c.Concurrency = n
+		}
+	}
+}
+
 type Subscriber struct {
 	*redisstream.Subscriber
 	name           string
@@ -149,8 +163,18 @@ type Subscriber struct {
 	healthy        atomic.Bool
 	running        atomic.Bool
 	redisClient    goredis.UniversalClient
+
+	// workersMux guards the workers of a running subscriber, each consuming its own stream
+	// subscription, so that SetConcurrency can add and remove them.
+	workersMux  sync.Mutex
+	concurrency int
+	runCtx      context.Context //nolint:containedctx
+	workers     []chan struct{}
+	workersWG   sync.WaitGroup
 }
 
+var _ Scaler = (*Subscriber)(nil)
+
 func NewSubscriber(
 	redisClient goredis.UniversalClient,
 	consumerGroup,
@@ -207,6 +231,7 @@ func NewSubscriber(
 		stoppedSignal:  make(chan struct{}),
 		config:         config,
 		redisClient:    redisClient,
+		concurrency:    config.Concurrency,
 	}
 	sub.healthy.Store(false)
 
@@ -220,6 +245,7 @@ func defaultSubscriberConfig() SubscriberConfig {
 		MaxIdleTime:     0,
 		ShutdownTimeout: defaultShutdownTimeout,
 		ExecTimeout:     defaultExecTimeout,
+		Concurrency:     defaultConcurrency,
 		Metrics:         nil,
 		Retry:           nil,
 	}
@@ -279,7 +305,7 @@ func (s *Subscriber) Topic() string {
 	return s.topic
 }
 
-func (s *Subscriber) Start(ctx context.Context) error { //nolint:cyclop
+func (s *Subscriber) Start(ctx context.Context) error {
 	if !s.running.CompareAndSwap(false, true) {
 		return ErrAlreadyRunning
 	}
@@ -289,36 +315,138 @@ func (s *Subscriber) Start(ctx context.Context) error { //nolint:cyclop
 	log.Info().
 		Str("source", "gframework").
 		Str("topic", s.Topic()).
+		Int("concurrency", s.Concurrency()).
 		Msg("The subscription is being started")
 
-	msgChan, err := s.Subscriber.Subscribe(ctx, s.Topic())
+	s.workersMux.Lock()
+	s.runCtx = ctx
+	err := s.scaleWorkers()
+	s.workersMux.Unlock()
+
+	defer s.stopWorkers()
+
 	if err != nil {
-		return fmt.Errorf("subscription to topic %s failed: %w", s.Topic(), err)
+		return err
 	}
 
 	s.healthy.Store(true)
 
+	select {
+	case <-ctx.Done():
+		s.healthy.Store(false)
+		log.Info().
+			Str("source", "gframework").
+			Str("service_name", s.Name()).
+			Str("topic", s.Topic()).
+			Msg("Subscriber stopped: context cancelled")
+
+		return ctx.Err()
+	case <-s.shutdownSignal:
+		s.healthy.Store(false)
+		log.Info().
+			Str("source", "gframework").
+			Str("service_name", s.Name()).
+			Str("topic", s.Topic()).
+			Msg("Subscriber stopped: graceful shutdown initiated")
+
+		return nil
+	}
+}
+
+// Concurrency returns the number of messages handled at once.
+func (s *Subscriber) Concurrency() int {
+	s.workersMux.Lock()
+	defer s.workersMux.Unlock()
+
+	return s.concurrency
+}
+
+// SetConcurrency changes the number of messages handled at once, also while the subscriber runs, e.g.
+// driven by an Advisor. Removed workers finish the message they are handling first; a message a removed
+// worker had already read from the stream stays pending until it is claimed after MaxIdleTime.
+func (s *Subscriber) SetConcurrency(n int) error {
+	if n <= 0 {
+		return fmt.Errorf("%w: %d", ErrNonPositiveConcurrency, n)
+	}
+
+	s.workersMux.Lock()
+	defer s.workersMux.Unlock()
+
+	s.concurrency = n
+
+	if s.runCtx == nil {
+		return nil
+	}
+
+	return s.scaleWorkers()
+}
+
+// scaleWorkers starts or stops workers until their number matches the concurrency. It must be called
+// with workersMux held.
+func (s *Subscriber) scaleWorkers() error {
+	for len(s.workers) < s.concurrency {
+		subscriptionCtx, cancel := context.WithCancel(s.runCtx)
+
+		msgChan, err := s.Subscriber.Subscribe(subscriptionCtx, s.Topic())
+		if err != nil {
+			cancel()
+
+			return fmt.Errorf("subscription to topic %s failed: %w", s.Topic(), err)
+		}
+
+		stop := make(chan struct{})
+		s.workers = append(s.workers, stop)
+		s.workersWG.Add(1)
+
+		go s.consume(s.runCtx, msgChan, stop, cancel)
+	}
+
+	for len(s.workers) > s.concurrency {
+		last := len(s.workers) - 1
+		close(s.workers[last])
+		s.workers = s.workers[:last]
+	}
+
+	return nil
+}
+
+// stopWorkers stops all workers and waits for them to finish their messages.
+func (s *Subscriber) stopWorkers() {
+	s.workersMux.Lock()
+
+	for _, stop := range s.workers {
+		close(stop)
+	}
+
+	s.workers = nil
+	s.runCtx = nil
+	s.workersMux.Unlock()
+
+	s.workersWG.Wait()
+}
+
+// consume handles the messages of one stream subscription until stop is closed or ctx is done. The
+// subscription is cancelled only after the message in hand has been handled and acknowledged.
+func (s *Subscriber) consume(
+	ctx context.Context,
+	msgChan <-chan *message.Message,
+	stop <-chan struct{},
+	cancel context.CancelFunc,
+) {
+	defer s.workersWG.Done()
+	defer cancel()
+
 	for {
 		select {
 		case <-ctx.Done():
-			s.healthy.Store(false)
-			log.Info().
-				Str("source", "gframework").
-				Str("service_name", s.Name()).
-				Str("topic", s.Topic()).
-				Msg("Subscriber stopped: context cancelled")
-
-			return ctx.Err()
-		case <-s.shutdownSignal:
-			s.healthy.Store(false)
-			log.Info().
-				Str("source", "gframework").
-				Str("service_name", s.Name()).
-				Str("topic", s.Topic()).
-				Msg("Subscriber stopped: graceful shutdown initiated")
+			return
+		case <-stop:
+			return
+		case msg, ok := <-msgChan:
+			if !ok {
+				return
+			}
 
-			return nil
-		case msg := <-msgChan:
 			if msg == nil || msg.UUID == "" {
 				log.Debug().
 					Str("source", "gframework").
@@ -349,6 +477,9 @@ func (s *Subscriber) handleMessage(ctx context.Context, msg *message.Message) er
 		return ErrMessageHandlerNotDefined
 	}
 
+	ctx, span := startProcessSpan(ctx, s.Topic(), msg)
+	defer span.End()
+
 	start := time.Now()
 	processingErr := s.processWithRetry(ctx, msg)
 	duration := time.Since(start)
@@ -358,6 +489,8 @@ func (s *Subscriber) handleMessage(ctx context.Context, msg *message.Message) er
 	}
 
 	if processingErr != nil {
+		span.RecordError(processingErr)
+		span.SetStatus(codes.Error, processingErr.Error())
 		s.handleFailedMessage(ctx, msg, processingErr)
 
 		return fmt.Errorf("%w: %w", ErrMaxRetriesExceeded, processingErr)
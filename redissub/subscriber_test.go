@@ -34,6 +34,7 @@ func setupTestClient(t *testing.T) *valkey.Valkey {
 	valkeyClient, err := valkey.New(&valkey.Config{
 		Host: container.Host,
 		Port: port,
+		DB:   container.DB,
 	})
 	require.NoError(t, err)
 
@@ -1028,3 +1029,79 @@ func TestSubscriberMetricsAfterRetryExhaustion(t *testing.T) {
 	require.Equal(t, int32(1), metrics.nackedCount.Load(), "should record nacked exactly once (for failed processing)")
 	require.Equal(t, int32(0), metrics.ackedCount.Load(), "should not record acked (message failed)")
 }
+
+func TestSubscriberSetConcurrency(t *testing.T) {
+	t.Parallel()
+
+	valkeyClient := setupTestClient(t)
+	publisher := setupTestPublisher(t, valkeyClient)
+
+	const messages = 9
+
+	var active, maxActive, handled atomic.Int32
+
+	release := make(chan struct{})
+
+	handler := func(ctx context.Context, _ message.Payload) error {
+		current := active.Add(1)
+		for {
+			seen := maxActive.Load()
+			if current <= seen || maxActive.CompareAndSwap(seen, current) {
+				break
+			}
+		}
+
+		select {
+		case <-release:
+		case <-ctx.Done():
+		}
+
+		active.Add(-1)
+		handled.Add(1)
+
+		return nil
+	}
+
+	subscriber, err := redissub.NewSubscriber(
+		valkeyClient.Client,
+		"test-group",
+		"test-topic-concurrency",
+		handler,
+		redissub.WithConcurrency(1),
+		redissub.WithExecTimeout(0),
+	)
+	require.NoError(t, err)
+	require.Equal(t, 1, subscriber.Concurrency())
+	require.ErrorIs(t, subscriber.SetConcurrency(0), redissub.ErrNonPositiveConcurrency)
+
+	stopped := make(chan error, 1)
+
+	go func() {
+		stopped <- subscriber.Start(t.Context())
+	}()
+
+	require.Eventually(t, subscriber.IsHealthy, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, subscriber.SetConcurrency(3))
+	require.Equal(t, 3, subscriber.Concurrency())
+
+	// Every stream subscription reads ahead up to two messages besides the one it handles, so enough
+	// messages are published for all three workers to get one.
+	for i := range messages {
+		publishTestMessage(t, publisher, "test-topic-concurrency", "message-"+strconv.Itoa(i))
+	}
+
+	require.Eventually(t, func() bool {
+		return active.Load() == 3
+	}, 5*time.Second, 10*time.Millisecond, "three messages should be handled at once")
+
+	close(release)
+
+	require.Eventually(t, func() bool {
+		return handled.Load() == messages
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, int32(3), maxActive.Load())
+
+	require.NoError(t, subscriber.SetConcurrency(1))
+	require.NoError(t, subscriber.Stop())
+	require.NoError(t, <-stopped)
+}
@@ -0,0 +1,29 @@
+package redissub
+
+import (
+	"context"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/andyle182810/gframework/redissub"
+
+// startProcessSpan starts a consumer span continuing the trace that redispub injected into the message
+// metadata. Without a tracer provider, e.g. one installed by tracing.New, the span is a no-op.
+func startProcessSpan(ctx context.Context, topic string, msg *message.Message) (context.Context, trace.Span) {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(msg.Metadata))
+
+	return otel.Tracer(instrumentationName).Start(ctx, "process "+topic, //nolint:spancheck
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKey.String("redis_streams"),
+			semconv.MessagingOperationTypeProcess,
+			semconv.MessagingDestinationName(topic),
+			semconv.MessagingMessageID(msg.UUID),
+		),
+	)
+}
@@ -0,0 +1,141 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultHealthCheckTimeout = 3 * time.Second
+	healthStatusOK            = "ok"
+	healthStatusUnavailable   = "unavailable"
+)
+
+// HealthReporter is implemented by services that track their own health, e.g. subscribers.
+type HealthReporter interface {
+	IsHealthy() bool
+}
+
+// ContextHealthReporter is implemented by services whose health needs IO, e.g. postgres.
+type ContextHealthReporter interface {
+	IsHealthy(ctx context.Context) bool
+}
+
+// HealthChecker is implemented by services that report the reason they are unhealthy, e.g. valkey.
+type HealthChecker interface {
+	HealthCheck(ctx context.Context) error
+}
+
+type ServiceHealth struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Healthy bool   `json:"healthy"`
+	Error   string `json:"error,omitempty"`
+}
+
+type HealthReport struct {
+	Status   string          `json:"status"`
+	Services []ServiceHealth `json:"services"`
+}
+
+// Health checks every registered service that implements HealthReporter, ContextHealthReporter or
+// HealthChecker concurrently. Services implementing none of them are not included.
+func (r *Runner) Health(ctx context.Context) HealthReport {
+	type entry struct {
+		service     Service
+		serviceType string
+	}
+
+	entries := make([]entry, 0, len(r.infrastructureServices)+len(r.coreServices))
+	for _, svc := range r.infrastructureServices {
+		entries = append(entries, entry{service: svc, serviceType: string(TierInfrastructure)})
+	}
+
+	for _, svc := range r.coreServices {
+		entries = append(entries, entry{service: svc, serviceType: string(TierCore)})
+	}
+
+	results := make([]*ServiceHealth, len(entries))
+
+	var wg sync.WaitGroup
+
+	for i, e := range entries {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			results[i] = checkServiceHealth(ctx, e.service, e.serviceType)
+		}()
+	}
+
+	wg.Wait()
+
+	report := HealthReport{
+		Status:   healthStatusOK,
+		Services: make([]ServiceHealth, 0, len(results)),
+	}
+
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+
+		if !result.Healthy {
+			report.Status = healthStatusUnavailable
+		}
+
+		report.Services = append(report.Services, *result)
+	}
+
+	return report
+}
+
+// HealthHandler serves Health as JSON with 200 when every service is healthy and 503 otherwise.
+// Mount it on metricserver or httpserver, e.g. server.Root.GET("/ready", echo.WrapHandler(r.HealthHandler())).
+func (r *Runner) HealthHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx, cancel := context.WithTimeout(req.Context(), defaultHealthCheckTimeout)
+		defer cancel()
+
+		report := r.Health(ctx)
+
+		status := http.StatusOK
+		if report.Status != healthStatusOK {
+			status = http.StatusServiceUnavailable
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+
+		_ = json.NewEncoder(w).Encode(report)
+	})
+}
+
+func checkServiceHealth(ctx context.Context, svc Service, serviceType string) *ServiceHealth {
+	result := &ServiceHealth{
+		Name:    svc.Name(),
+		Type:    serviceType,
+		Healthy: true,
+		Error:   "",
+	}
+
+	switch checked := unwrapService(svc).(type) {
+	case HealthChecker:
+		if err := checked.HealthCheck(ctx); err != nil {
+			result.Healthy = false
+			result.Error = err.Error()
+		}
+	case ContextHealthReporter:
+		result.Healthy = checked.IsHealthy(ctx)
+	case HealthReporter:
+		result.Healthy = checked.IsHealthy()
+	default:
+		return nil
+	}
+
+	return result
+}
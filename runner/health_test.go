@@ -0,0 +1,90 @@
+package runner_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andyle182810/gframework/runner"
+	"github.com/stretchr/testify/require"
+)
+
+type healthyService struct {
+	*mockService
+
+	healthy bool
+}
+
+func (h *healthyService) IsHealthy() bool {
+	return h.healthy
+}
+
+type checkedService struct {
+	*mockService
+
+	err error
+}
+
+func (c *checkedService) HealthCheck(_ context.Context) error {
+	return c.err
+}
+
+func TestRunner_HealthHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		services       []runner.Option
+		expectedStatus int
+		expectedCount  int
+	}{
+		{
+			name: "all healthy",
+			services: []runner.Option{
+				runner.WithInfrastructureService(&checkedService{mockService: newMockService("valkey"), err: nil}),
+				runner.WithCoreService(&healthyService{mockService: newMockService("subscriber"), healthy: true}),
+				runner.WithCoreService(newMockService("no-health")),
+			},
+			expectedStatus: http.StatusOK,
+			expectedCount:  2,
+		},
+		{
+			name: "failing health check",
+			services: []runner.Option{
+				runner.WithInfrastructureService(&checkedService{mockService: newMockService("valkey"), err: errStart}),
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedCount:  1,
+		},
+		{
+			name: "supervised service is unwrapped",
+			services: []runner.Option{
+				runner.WithCoreService(runner.WithRestartPolicy(
+					&healthyService{mockService: newMockService("subscriber"), healthy: false},
+					runner.DefaultRestartPolicy(),
+				)),
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedCount:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := runner.New(tt.services...)
+
+			rec := httptest.NewRecorder()
+			r.HealthHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+
+			require.Equal(t, tt.expectedStatus, rec.Code)
+
+			var report runner.HealthReport
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+			require.Len(t, report.Services, tt.expectedCount)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const eventBufferSize = 32
+
+var ErrHookFailed = errors.New("runner: lifecycle hook failed")
+
+// Tier identifies a group of services that start and stop together.
+type Tier string
+
+const (
+	TierInfrastructure Tier = "infrastructure"
+	TierCore           Tier = "core"
+)
+
+// LifecyclePhase is a point in the runner lifecycle at which hooks run and events are emitted. Each
+// phase occurs once per tier: infrastructure starts before core, core stops before infrastructure.
+type LifecyclePhase int
+
+const (
+	OnBeforeStart LifecyclePhase = iota
+	OnAfterStart
+	OnBeforeStop
+	OnAfterStop
+)
+
+func (p LifecyclePhase) String() string {
+	switch p {
+	case OnBeforeStart:
+		return "before_start"
+	case OnAfterStart:
+		return "after_start"
+	case OnBeforeStop:
+		return "before_stop"
+	case OnAfterStop:
+		return "after_stop"
+	default:
+		return fmt.Sprintf("phase(%d)", int(p))
+	}
+}
+
+// HookFunc runs at a lifecycle phase of a tier. An error from a start hook aborts startup like a
+// failing service; errors from stop hooks are logged and shutdown continues.
+type HookFunc func(ctx context.Context, tier Tier) error
+
+// Event describes a lifecycle phase that has completed. Err is set when a start phase failed, and
+// carries the shutdown reason for stop phases.
+type Event struct {
+	Phase LifecyclePhase
+	Tier  Tier
+	Err   error
+	Time  time.Time
+}
+
+// WithHook registers fn to run at phase, e.g. WithHook(OnAfterStart, warmCaches) to warm caches once
+// infrastructure is up. Hooks of the same phase run in registration order.
+func WithHook(phase LifecyclePhase, fn HookFunc) Option {
+	return func(r *Runner) {
+		if fn == nil {
+			return
+		}
+
+		r.hooks[phase] = append(r.hooks[phase], fn)
+	}
+}
+
+// Events returns the lifecycle event stream. Events are dropped when the buffer is full, so a slow
+// consumer never blocks startup or shutdown.
+func (r *Runner) Events() <-chan Event {
+	return r.events
+}
+
+func (r *Runner) runHooks(ctx context.Context, phase LifecyclePhase, tier Tier) error {
+	for _, hook := range r.hooks[phase] {
+		if err := hook(ctx, tier); err != nil {
+			return fmt.Errorf("%w: %s %s: %w", ErrHookFailed, phase, tier, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *Runner) emit(phase LifecyclePhase, tier Tier, err error) {
+	event := Event{
+		Phase: phase,
+		Tier:  tier,
+		Err:   err,
+		Time:  time.Now(),
+	}
+
+	select {
+	case r.events <- event:
+	default:
+		log.Warn().
+			Str("source", "gframework").
+			Stringer("phase", phase).
+			Str("tier", string(tier)).
+			Msg("Lifecycle event dropped, consumer is too slow")
+	}
+}
@@ -0,0 +1,108 @@
+package runner_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/runner"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithHook_RunsInLifecycleOrder(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu    sync.Mutex
+		calls []string
+	)
+
+	record := func(phase runner.LifecyclePhase) runner.HookFunc {
+		return func(_ context.Context, tier runner.Tier) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			calls = append(calls, phase.String()+":"+string(tier))
+
+			return nil
+		}
+	}
+
+	r := runner.New(
+		runner.WithInfrastructureService(newMockService("db")),
+		runner.WithCoreService(newMockService("api")),
+		runner.WithHook(runner.OnBeforeStart, record(runner.OnBeforeStart)),
+		runner.WithHook(runner.OnAfterStart, record(runner.OnAfterStart)),
+		runner.WithHook(runner.OnBeforeStop, record(runner.OnBeforeStop)),
+		runner.WithHook(runner.OnAfterStop, record(runner.OnAfterStop)),
+		runner.WithShutdownTimeout(time.Second),
+	)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, r.RunContext(ctx), context.DeadlineExceeded)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	require.Equal(t, []string{
+		"before_start:infrastructure",
+		"after_start:infrastructure",
+		"before_start:core",
+		"after_start:core",
+		"before_stop:core",
+		"after_stop:core",
+		"before_stop:infrastructure",
+		"after_stop:infrastructure",
+	}, calls)
+}
+
+func TestWithHook_StartHookFailureAbortsStartup(t *testing.T) {
+	t.Parallel()
+
+	core := newMockService("api")
+
+	r := runner.New(
+		runner.WithInfrastructureService(newMockService("db")),
+		runner.WithCoreService(core),
+		runner.WithHook(runner.OnAfterStart, func(_ context.Context, tier runner.Tier) error {
+			if tier == runner.TierInfrastructure {
+				return errStart
+			}
+
+			return nil
+		}),
+		runner.WithShutdownTimeout(time.Second),
+	)
+
+	err := r.RunContext(t.Context())
+	require.ErrorIs(t, err, runner.ErrHookFailed)
+	require.ErrorIs(t, err, errStart)
+	require.False(t, core.wasStarted())
+}
+
+func TestRunner_EventsPublishesPhases(t *testing.T) {
+	t.Parallel()
+
+	r := runner.New(
+		runner.WithCoreService(newMockService("api")),
+		runner.WithShutdownTimeout(time.Second),
+	)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	require.ErrorIs(t, r.RunContext(ctx), context.Canceled)
+
+	var phases []runner.LifecyclePhase
+
+	for len(r.Events()) > 0 {
+		event := <-r.Events()
+		phases = append(phases, event.Phase)
+	}
+
+	require.Contains(t, phases, runner.OnBeforeStart)
+	require.Contains(t, phases, runner.OnAfterStop)
+}
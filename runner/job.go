@@ -0,0 +1,68 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+var ErrJobFailed = errors.New("runner: job failed")
+
+// JobFunc is a run-to-completion task such as a migration or a backfill.
+type JobFunc func(ctx context.Context) error
+
+type job struct {
+	name string
+	fn   JobFunc
+}
+
+// WithJob registers a job that runs after the infrastructure services have started and before the
+// core services start. Jobs run sequentially in registration order; a failing job aborts startup. A
+// runner with jobs but no core services shuts down once all jobs have completed, which allows
+// `app migrate` style entrypoints that reuse the application's wiring.
+func WithJob(name string, fn JobFunc) Option {
+	return func(r *Runner) {
+		if fn == nil {
+			return
+		}
+
+		r.jobs = append(r.jobs, job{name: name, fn: fn})
+		log.Info().
+			Str("source", "gframework").
+			Str("job_name", name).
+			Msg("Job registered")
+	}
+}
+
+func (r *Runner) runJobs(ctx context.Context) error {
+	for _, j := range r.jobs {
+		log.Info().Str("source", "gframework").Str("job_name", j.name).Msg("Running job")
+
+		startedAt := time.Now()
+
+		if err := j.run(ctx); err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrJobFailed, j.name, err)
+		}
+
+		log.Info().
+			Str("source", "gframework").
+			Str("job_name", j.name).
+			Dur("duration", time.Since(startedAt)).
+			Msg("Job completed")
+	}
+
+	return nil
+}
+
+func (j job) run(ctx context.Context) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("%w: %v", ErrServicePanic, rec)
+		}
+	}()
+
+	return j.fn(ctx)
+}
@@ -0,0 +1,77 @@
+package runner_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/runner"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithJob_RunsBetweenTiers(t *testing.T) {
+	t.Parallel()
+
+	infra := newReadyService("db", 0)
+	core := newMockService("api")
+
+	var infraStartedForJob, coreStartedForJob bool
+
+	r := runner.New(
+		runner.WithInfrastructureService(infra),
+		runner.WithCoreService(core),
+		runner.WithJob("migrate", func(_ context.Context) error {
+			infraStartedForJob = infra.wasStarted()
+			coreStartedForJob = core.wasStarted()
+
+			return nil
+		}),
+		runner.WithShutdownTimeout(time.Second),
+	)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, r.RunContext(ctx), context.DeadlineExceeded)
+	require.True(t, infraStartedForJob)
+	require.False(t, coreStartedForJob)
+	require.True(t, core.wasStarted())
+}
+
+func TestWithJob_FailureAbortsStartup(t *testing.T) {
+	t.Parallel()
+
+	infra := newMockService("db")
+	core := newMockService("api")
+
+	r := runner.New(
+		runner.WithInfrastructureService(infra),
+		runner.WithCoreService(core),
+		runner.WithJob("backfill", func(_ context.Context) error { return errStart }),
+		runner.WithShutdownTimeout(time.Second),
+	)
+
+	err := r.RunContext(t.Context())
+	require.ErrorIs(t, err, runner.ErrJobFailed)
+	require.ErrorIs(t, err, errStart)
+	require.False(t, core.wasStarted())
+	require.True(t, infra.wasStopped())
+}
+
+func TestWithJob_ExitsAfterJobsWithoutCoreServices(t *testing.T) {
+	t.Parallel()
+
+	infra := newMockService("db")
+	runs := 0
+
+	r := runner.New(
+		runner.WithInfrastructureService(infra),
+		runner.WithJob("first", func(_ context.Context) error { runs++; return nil }),
+		runner.WithJob("second", func(_ context.Context) error { runs++; return nil }),
+		runner.WithShutdownTimeout(time.Second),
+	)
+
+	require.NoError(t, r.RunContext(t.Context()))
+	require.Equal(t, 2, runs)
+	require.True(t, infra.wasStopped())
+}
@@ -0,0 +1,234 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultLeaderRetryInterval   = 5 * time.Second
+	defaultLeaderRefreshInterval = 5 * time.Second
+	leaderReleaseTimeout         = 3 * time.Second
+)
+
+// LeaderLock is a distributed lock held by at most one replica at a time, e.g. distlock.LeaderLock. The lock
+// must expire on its own when the holder stops refreshing it so that another replica can take over.
+type LeaderLock interface {
+	// TryAcquire takes the lock if it is free and reports whether this replica now holds it.
+	TryAcquire(ctx context.Context) (bool, error)
+	// Refresh extends the lock and reports whether this replica still holds it.
+	Refresh(ctx context.Context) (bool, error)
+	Release(ctx context.Context) error
+}
+
+type LeaderOption func(*leaderService)
+
+// WithLeaderRetryInterval sets how often a follower tries to acquire the lock.
+func WithLeaderRetryInterval(d time.Duration) LeaderOption {
+	return func(s *leaderService) {
+		if d > 0 {
+			s.retryInterval = d
+		}
+	}
+}
+
+// WithLeaderRefreshInterval sets how often the leader refreshes the lock. It must be well below the
+// lock's expiry.
+func WithLeaderRefreshInterval(d time.Duration) LeaderOption {
+	return func(s *leaderService) {
+		if d > 0 {
+			s.refreshInterval = d
+		}
+	}
+}
+
+type leaderService struct {
+	Service
+
+	lock            LeaderLock
+	retryInterval   time.Duration
+	refreshInterval time.Duration
+	mu              sync.Mutex
+	leading         bool
+	ready           chan struct{}
+	readyOnce       sync.Once
+}
+
+// WithLeaderElectedService wraps svc so that it only runs on the replica holding lock, e.g. schedulers
+// and recovery pools. Followers keep trying to acquire the lock, so when the leader dies its lock
+// expires and another replica starts the service. A leader that fails to refresh the lock stops the
+// service and becomes a follower again.
+func WithLeaderElectedService(svc Service, lock LeaderLock, opts ...LeaderOption) Service {
+	s := &leaderService{ //nolint:exhaustruct
+		Service:         svc,
+		lock:            lock,
+		retryInterval:   defaultLeaderRetryInterval,
+		refreshInterval: defaultLeaderRefreshInterval,
+		ready:           make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Start campaigns for the lock until ctx is done or the service's Start returns while leading.
+func (s *leaderService) Start(ctx context.Context) error {
+	for {
+		acquired, err := s.lock.TryAcquire(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Warn().
+				Str("source", "gframework").
+				Err(err).
+				Str("service_name", s.Name()).
+				Msg("Failed to acquire leader lock")
+		}
+
+		if !acquired {
+			s.markReady()
+		}
+
+		if acquired {
+			if finished, err := s.lead(ctx); finished || err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(s.retryInterval):
+		}
+	}
+}
+
+// lead runs the service while the lock is held. It reports finished when the service's Start returned on
+// its own, in which case the lock is resigned and the error of the service, if any, is returned. It
+// returns nil when leadership is lost. It only returns once the service's Start has returned, so that a
+// re-acquired lock never runs the service twice at once.
+func (s *leaderService) lead(ctx context.Context) (bool, error) {
+	log.Info().Str("source", "gframework").Str("service_name", s.Name()).Msg("Acquired leadership, starting service")
+
+	leaderCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	s.mu.Lock()
+	s.leading = true
+	s.mu.Unlock()
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- s.Service.Start(leaderCtx)
+	}()
+
+	go s.awaitLeaderReady(leaderCtx)
+
+	ticker := time.NewTicker(s.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			cancel()
+			<-errCh
+
+			return true, ctx.Err()
+		case err := <-errCh:
+			s.resign()
+
+			if err != nil && !errors.Is(err, context.Canceled) {
+				return true, err
+			}
+
+			log.Info().Str("source", "gframework").Str("service_name", s.Name()).Msg("Service finished, resigned leadership")
+
+			return true, nil
+		case <-ticker.C:
+			held, err := s.lock.Refresh(ctx)
+			if err == nil && held {
+				continue
+			}
+
+			log.Warn().
+				Str("source", "gframework").
+				Err(err).
+				Str("service_name", s.Name()).
+				Msg("Lost leadership, stopping service")
+
+			cancel()
+			s.resign()
+			<-errCh
+
+			return false, nil
+		}
+	}
+}
+
+// Stop stops the service and releases the lock if this replica is the leader.
+func (s *leaderService) Stop() error {
+	return s.resign()
+}
+
+func (s *leaderService) resign() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.leading {
+		return nil
+	}
+
+	s.leading = false
+
+	err := s.Service.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), leaderReleaseTimeout)
+	defer cancel()
+
+	if releaseErr := s.lock.Release(ctx); releaseErr != nil {
+		log.Warn().
+			Str("source", "gframework").
+			Err(releaseErr).
+			Str("service_name", s.Name()).
+			Msg("Failed to release leader lock")
+	}
+
+	return err
+}
+
+// Ready is closed once this replica follows, or once the service is ready while this replica leads,
+// so that a follower does not hold up the tiers started after it.
+func (s *leaderService) Ready() <-chan struct{} {
+	return s.ready
+}
+
+func (s *leaderService) markReady() {
+	s.readyOnce.Do(func() { close(s.ready) })
+}
+
+// awaitLeaderReady marks the service ready once the led service is, or right away when it does not
+// implement ReadinessNotifier.
+func (s *leaderService) awaitLeaderReady(ctx context.Context) {
+	notifier, ok := readinessNotifier(s.Service)
+	if !ok {
+		s.markReady()
+
+		return
+	}
+
+	select {
+	case <-notifier.Ready():
+		s.markReady()
+	case <-ctx.Done():
+	}
+}
+
+func (s *leaderService) Unwrap() Service {
+	return s.Service
+}
@@ -0,0 +1,227 @@
+package runner_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/runner"
+	"github.com/stretchr/testify/require"
+)
+
+type lockState struct {
+	mu     sync.Mutex
+	holder string
+}
+
+type memoryLock struct {
+	state *lockState
+	owner string
+}
+
+func (l *memoryLock) TryAcquire(_ context.Context) (bool, error) {
+	l.state.mu.Lock()
+	defer l.state.mu.Unlock()
+
+	if l.state.holder == "" {
+		l.state.holder = l.owner
+	}
+
+	return l.state.holder == l.owner, nil
+}
+
+func (l *memoryLock) Refresh(_ context.Context) (bool, error) {
+	l.state.mu.Lock()
+	defer l.state.mu.Unlock()
+
+	return l.state.holder == l.owner, nil
+}
+
+func (l *memoryLock) Release(_ context.Context) error {
+	l.state.mu.Lock()
+	defer l.state.mu.Unlock()
+
+	if l.state.holder == l.owner {
+		l.state.holder = ""
+	}
+
+	return nil
+}
+
+func (l *lockState) expire() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.holder = ""
+}
+
+func newLeaderTestService(svc runner.Service, lock runner.LeaderLock) runner.Service {
+	return runner.WithLeaderElectedService(svc, lock,
+		runner.WithLeaderRetryInterval(5*time.Millisecond),
+		runner.WithLeaderRefreshInterval(5*time.Millisecond),
+	)
+}
+
+func TestWithLeaderElectedService_RunsOnOneReplica(t *testing.T) {
+	t.Parallel()
+
+	state := &lockState{}
+	first := &blockingService{name: "scheduler"}
+	second := &blockingService{name: "scheduler"}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	leaderA := newLeaderTestService(first, &memoryLock{state: state, owner: "a"})
+	leaderB := newLeaderTestService(second, &memoryLock{state: state, owner: "b"})
+
+	ctxA, cancelA := context.WithCancel(ctx)
+
+	go func() { _ = leaderA.Start(ctxA) }()
+
+	require.Eventually(t, func() bool { return first.starts.Load() == 1 }, time.Second, time.Millisecond)
+
+	go func() { _ = leaderB.Start(ctx) }()
+
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, int32(0), second.starts.Load())
+
+	cancelA()
+	require.NoError(t, leaderA.Stop())
+	require.Equal(t, int32(1), first.stops.Load())
+
+	require.Eventually(t, func() bool { return second.starts.Load() == 1 }, time.Second, time.Millisecond)
+}
+
+func TestWithLeaderElectedService_StopsWhenLeadershipIsLost(t *testing.T) {
+	t.Parallel()
+
+	state := &lockState{}
+	svc := &blockingService{name: "recovery"}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	leader := newLeaderTestService(svc, &memoryLock{state: state, owner: "a"})
+
+	go func() { _ = leader.Start(ctx) }()
+
+	require.Eventually(t, func() bool { return svc.starts.Load() == 1 }, time.Second, time.Millisecond)
+
+	state.mu.Lock()
+	state.holder = "b"
+	state.mu.Unlock()
+
+	require.Eventually(t, func() bool { return svc.stops.Load() == 1 }, time.Second, time.Millisecond)
+
+	state.expire()
+
+	require.Eventually(t, func() bool { return svc.starts.Load() == 2 }, time.Second, time.Millisecond)
+}
+
+func TestWithLeaderElectedService_ResignsWhenServiceFinishes(t *testing.T) {
+	t.Parallel()
+
+	state := &lockState{}
+	svc := newMockService("migrator")
+
+	leader := newLeaderTestService(svc, &memoryLock{state: state, owner: "a"})
+
+	done := make(chan error, 1)
+
+	go func() { done <- leader.Start(t.Context()) }()
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("leader kept campaigning after the service finished")
+	}
+
+	require.True(t, svc.wasStarted())
+	require.True(t, svc.wasStopped())
+	require.Empty(t, state.holder, "the lock should be released")
+}
+
+// slowStoppingService returns from Start a while after Stop and records how many Starts overlap.
+type slowStoppingService struct {
+	mu         sync.Mutex
+	stop       chan struct{}
+	running    int
+	maxRunning int
+	starts     int
+}
+
+func (s *slowStoppingService) Start(_ context.Context) error {
+	s.mu.Lock()
+	s.starts++
+	s.running++
+	s.maxRunning = max(s.maxRunning, s.running)
+	stop := make(chan struct{})
+	s.stop = stop
+	s.mu.Unlock()
+
+	<-stop
+	time.Sleep(50 * time.Millisecond)
+
+	s.mu.Lock()
+	s.running--
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *slowStoppingService) Stop() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+	}
+
+	return nil
+}
+
+func (s *slowStoppingService) Name() string {
+	return "slow"
+}
+
+func (s *slowStoppingService) stats() (int, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.starts, s.maxRunning
+}
+
+func TestWithLeaderElectedService_WaitsForServiceBeforeLeadingAgain(t *testing.T) {
+	t.Parallel()
+
+	state := &lockState{}
+	svc := &slowStoppingService{}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	leader := newLeaderTestService(svc, &memoryLock{state: state, owner: "a"})
+
+	go func() { _ = leader.Start(ctx) }()
+
+	require.Eventually(t, func() bool {
+		starts, _ := svc.stats()
+
+		return starts == 1
+	}, time.Second, time.Millisecond)
+
+	state.expire()
+
+	require.Eventually(t, func() bool {
+		starts, _ := svc.stats()
+
+		return starts == 2
+	}, time.Second, time.Millisecond)
+
+	_, maxRunning := svc.stats()
+	require.Equal(t, 1, maxRunning)
+}
@@ -0,0 +1,77 @@
+package runner
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/andyle182810/gframework/metricserver"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// PrometheusMetrics implements ShutdownMetrics with Prometheus collectors.
+type PrometheusMetrics struct {
+	serviceStopDuration *prometheus.GaugeVec
+	serviceStopErrors   *prometheus.CounterVec
+	serviceStopTimeouts *prometheus.CounterVec
+	shutdownDuration    prometheus.Gauge
+	shutdownTimeouts    prometheus.Counter
+}
+
+// NewPrometheusMetrics registers the runner's shutdown metrics with reg, e.g. the registerer of
+// metricserver.
+func NewPrometheusMetrics(reg prometheus.Registerer) (*PrometheusMetrics, error) {
+	const subsystem = "runner"
+
+	labels := []string{"service", "tier"}
+
+	m := &PrometheusMetrics{
+		serviceStopDuration: metricserver.NewGaugeVec(subsystem, "service_stop_duration_seconds",
+			"Time the service took to stop during the last shutdown.", labels...),
+		serviceStopErrors: metricserver.NewCounterVec(subsystem, "service_stop_errors_total",
+			"Number of times the service returned an error from Stop.", labels...),
+		serviceStopTimeouts: metricserver.NewCounterVec(subsystem, "service_stop_timeouts_total",
+			"Number of times the service did not stop within the shutdown timeout.", labels...),
+		shutdownDuration: prometheus.NewGauge(prometheus.GaugeOpts{ //nolint:exhaustruct
+			Namespace: metricserver.Namespace,
+			Subsystem: subsystem,
+			Name:      "shutdown_duration_seconds",
+			Help:      "Duration of the last shutdown.",
+		}),
+		shutdownTimeouts: prometheus.NewCounter(prometheus.CounterOpts{ //nolint:exhaustruct
+			Namespace: metricserver.Namespace,
+			Subsystem: subsystem,
+			Name:      "shutdown_timeouts_total",
+			Help:      "Number of shutdowns that exceeded the shutdown timeout.",
+		}),
+	}
+
+	err := metricserver.Register(reg,
+		m.serviceStopDuration, m.serviceStopErrors, m.serviceStopTimeouts, m.shutdownDuration, m.shutdownTimeouts)
+	if err != nil {
+		return nil, fmt.Errorf("runner: failed to register metrics: %w", err)
+	}
+
+	return m, nil
+}
+
+func (m *PrometheusMetrics) ServiceStopped(report ServiceStopReport) {
+	labels := prometheus.Labels{"service": report.Name, "tier": string(report.Tier)}
+
+	m.serviceStopDuration.With(labels).Set(report.Duration.Seconds())
+
+	if report.Err != nil {
+		m.serviceStopErrors.With(labels).Inc()
+	}
+
+	if report.TimedOut {
+		m.serviceStopTimeouts.With(labels).Inc()
+	}
+}
+
+func (m *PrometheusMetrics) ShutdownCompleted(duration time.Duration, timedOut bool) {
+	m.shutdownDuration.Set(duration.Seconds())
+
+	if timedOut {
+		m.shutdownTimeouts.Inc()
+	}
+}
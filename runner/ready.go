@@ -0,0 +1,126 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+var ErrStartTimeout = errors.New("runner: service did not become ready in time")
+
+// ReadinessNotifier is implemented by services that need time after Start before dependents may use
+// them, e.g. until a consumer group is created or migrations are applied. The channel is closed once
+// the service is ready.
+type ReadinessNotifier interface {
+	Ready() <-chan struct{}
+}
+
+type timedService struct {
+	Service
+
+	timeout time.Duration
+}
+
+// WithStartTimeout wraps svc so that the runner fails startup with ErrStartTimeout when svc does not
+// become ready within d. It only has an effect on services implementing ReadinessNotifier; without a
+// timeout the runner waits for readiness until the run context is done.
+func WithStartTimeout(svc Service, d time.Duration) Service {
+	return &timedService{
+		Service: svc,
+		timeout: d,
+	}
+}
+
+func (s *timedService) Unwrap() Service {
+	return s.Service
+}
+
+// awaitReady blocks until every service of a tier implementing ReadinessNotifier is ready, so that
+// the next tier only starts once its dependencies can serve.
+func (r *Runner) awaitReady(ctx context.Context, services []Service, startedAt time.Time) error {
+	for _, svc := range services {
+		notifier, ok := readinessNotifier(svc)
+		if !ok {
+			continue
+		}
+
+		if err := r.waitReady(ctx, svc, notifier, startedAt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Runner) waitReady(ctx context.Context, svc Service, notifier ReadinessNotifier, startedAt time.Time) error {
+	var timeout <-chan time.Time
+
+	if d := startTimeout(svc); d > 0 {
+		timer := time.NewTimer(time.Until(startedAt.Add(d)))
+		defer timer.Stop()
+
+		timeout = timer.C
+	}
+
+	select {
+	case <-notifier.Ready():
+		log.Info().Str("source", "gframework").Str("service_name", svc.Name()).Msg("Service is ready")
+
+		return nil
+	case err := <-r.failures:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timeout:
+		return fmt.Errorf("%w: %s after %s", ErrStartTimeout, svc.Name(), startTimeout(svc))
+	}
+}
+
+// unwrapService returns the service at the bottom of a chain of runner wrappers such as
+// WithRestartPolicy and WithStartTimeout.
+func unwrapService(svc Service) Service {
+	for {
+		wrapper, ok := svc.(interface{ Unwrap() Service })
+		if !ok {
+			return svc
+		}
+
+		svc = wrapper.Unwrap()
+	}
+}
+
+// readinessNotifier returns the outermost service of a chain of runner wrappers that implements
+// ReadinessNotifier. A leader-elected service reports its own readiness, which does not wait for the
+// wrapped service while this replica follows.
+func readinessNotifier(svc Service) (ReadinessNotifier, bool) {
+	for {
+		if notifier, ok := svc.(ReadinessNotifier); ok {
+			return notifier, true
+		}
+
+		wrapper, ok := svc.(interface{ Unwrap() Service })
+		if !ok {
+			return nil, false
+		}
+
+		svc = wrapper.Unwrap()
+	}
+}
+
+func startTimeout(svc Service) time.Duration {
+	for {
+		if timed, ok := svc.(*timedService); ok {
+			return timed.timeout
+		}
+
+		wrapper, ok := svc.(interface{ Unwrap() Service })
+		if !ok {
+			return 0
+		}
+
+		svc = wrapper.Unwrap()
+	}
+}
@@ -0,0 +1,109 @@
+package runner_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/runner"
+	"github.com/stretchr/testify/require"
+)
+
+type readyService struct {
+	*mockService
+
+	readyAfter time.Duration
+	ready      chan struct{}
+}
+
+func newReadyService(name string, readyAfter time.Duration) *readyService {
+	return &readyService{
+		mockService: newMockService(name),
+		readyAfter:  readyAfter,
+		ready:       make(chan struct{}),
+	}
+}
+
+func (s *readyService) Start(ctx context.Context) error {
+	if err := s.mockService.Start(ctx); err != nil {
+		return err
+	}
+
+	time.AfterFunc(s.readyAfter, func() { close(s.ready) })
+
+	return nil
+}
+
+func (s *readyService) Ready() <-chan struct{} {
+	return s.ready
+}
+
+func TestRunner_WaitsForReadinessBeforeNextTier(t *testing.T) {
+	t.Parallel()
+
+	infra := newReadyService("db", 50*time.Millisecond)
+
+	var infraReadyAtCoreStart bool
+
+	core := newMockService("api")
+
+	r := runner.New(
+		runner.WithInfrastructureService(infra),
+		runner.WithCoreService(core),
+		runner.WithHook(runner.OnBeforeStart, func(_ context.Context, tier runner.Tier) error {
+			if tier == runner.TierCore {
+				select {
+				case <-infra.Ready():
+					infraReadyAtCoreStart = true
+				default:
+				}
+			}
+
+			return nil
+		}),
+		runner.WithShutdownTimeout(time.Second),
+	)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 200*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, r.RunContext(ctx), context.DeadlineExceeded)
+	require.True(t, infraReadyAtCoreStart)
+	require.True(t, core.wasStarted())
+}
+
+func TestWithStartTimeout_FailsStartupWhenNotReady(t *testing.T) {
+	t.Parallel()
+
+	core := newMockService("api")
+
+	r := runner.New(
+		runner.WithInfrastructureService(runner.WithStartTimeout(newReadyService("db", time.Hour), 20*time.Millisecond)),
+		runner.WithCoreService(core),
+		runner.WithShutdownTimeout(time.Second),
+	)
+
+	err := r.RunContext(t.Context())
+	require.ErrorIs(t, err, runner.ErrStartTimeout)
+	require.False(t, core.wasStarted())
+}
+
+func TestRunner_FollowerDoesNotHoldUpNextTier(t *testing.T) {
+	t.Parallel()
+
+	state := &lockState{holder: "b"}
+	follower := newLeaderTestService(newReadyService("scheduler", time.Hour), &memoryLock{state: state, owner: "a"})
+	core := newMockService("api")
+
+	r := runner.New(
+		runner.WithInfrastructureService(runner.WithStartTimeout(follower, time.Second)),
+		runner.WithCoreService(core),
+		runner.WithShutdownTimeout(time.Second),
+	)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 200*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, r.RunContext(ctx), context.DeadlineExceeded)
+	require.True(t, core.wasStarted())
+}
@@ -0,0 +1,174 @@
+package runner
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const restartServiceParam = "service"
+
+var (
+	ErrServiceNotFound   = errors.New("runner: service not found")
+	ErrServiceNotRunning = errors.New("runner: service is not running")
+)
+
+// runningService tracks a started service so that it can be restarted on its own.
+type runningService struct {
+	parent context.Context //nolint:containedctx
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+type restartResponse struct {
+	Service string `json:"service"`
+	Status  string `json:"status,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// launch starts service in the background with its own cancellable context derived from ctx and
+// reports failures to the runner.
+func (r *Runner) launch(ctx context.Context, service Service) {
+	serviceCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	r.mu.Lock()
+	r.running[service.Name()] = &runningService{
+		parent: ctx,
+		cancel: cancel,
+		done:   done,
+	}
+	r.mu.Unlock()
+
+	go func() {
+		defer close(done)
+
+		defer func() {
+			if rec := recover(); rec != nil {
+				r.reportFailure(fmt.Errorf("%w: %s: %v", ErrServicePanic, service.Name(), rec))
+			}
+		}()
+
+		log.Info().Str("source", "gframework").Str("service_name", service.Name()).Msg("Starting service")
+
+		if err := service.Start(serviceCtx); err != nil && !errors.Is(err, context.Canceled) {
+			r.reportFailure(fmt.Errorf("%w: %s: %w", ErrServiceFailed, service.Name(), err))
+		}
+	}()
+}
+
+// reportFailure hands err to the runner without blocking. Restarts can launch more services than
+// failures has room for and nothing reads it once shutdown has begun, so a failure that does not fit
+// is logged instead of leaving the service goroutine blocked forever.
+func (r *Runner) reportFailure(err error) {
+	select {
+	case r.failures <- err:
+	default:
+		log.Error().Str("source", "gframework").Err(err).Msg("Service failed, failure not reported to the runner")
+	}
+}
+
+// Restart stops the named service and starts it again without touching the other services, e.g. to
+// pick up a configuration change. The service's context is cancelled and Stop is called; Restart then
+// waits up to the shutdown timeout for its Start to return before starting it again. Restarts of the
+// same runner are serialized.
+func (r *Runner) Restart(name string) error {
+	service := r.service(name)
+	if service == nil {
+		return fmt.Errorf("%w: %s", ErrServiceNotFound, name)
+	}
+
+	r.restartMu.Lock()
+	defer r.restartMu.Unlock()
+
+	r.mu.Lock()
+	running, ok := r.running[name]
+	r.mu.Unlock()
+
+	if !ok || running.parent.Err() != nil {
+		return fmt.Errorf("%w: %s", ErrServiceNotRunning, name)
+	}
+
+	log.Info().Str("source", "gframework").Str("service_name", name).Msg("Restarting service")
+
+	running.cancel()
+
+	if err := service.Stop(); err != nil {
+		log.Warn().
+			Str("source", "gframework").
+			Err(err).
+			Str("service_name", name).
+			Msg("Service stop before restart failed")
+	}
+
+	select {
+	case <-running.done:
+	case <-time.After(r.shutdownTimeout):
+		return fmt.Errorf("%w: %s", ErrShutdownTimout, name)
+	}
+
+	r.launch(running.parent, service)
+
+	return nil
+}
+
+// RestartHandler serves Restart for operational control: POST ?service=<name> restarts the service
+// and responds 200, 404 for unknown services and 409 when the runner is not running it. Mount it on an
+// admin group that is protected or not publicly routed, e.g.
+// server.Root.Group("/admin").POST("/restart", echo.WrapHandler(r.RestartHandler())).
+func (r *Runner) RestartHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		name := req.URL.Query().Get(restartServiceParam)
+		if name == "" {
+			http.Error(w, "missing service parameter", http.StatusBadRequest)
+
+			return
+		}
+
+		status := http.StatusOK
+		response := restartResponse{Service: name, Status: "restarted", Error: ""}
+
+		if err := r.Restart(name); err != nil {
+			switch {
+			case errors.Is(err, ErrServiceNotFound):
+				status = http.StatusNotFound
+			case errors.Is(err, ErrServiceNotRunning):
+				status = http.StatusConflict
+			default:
+				status = http.StatusInternalServerError
+			}
+
+			response.Status = ""
+			response.Error = err.Error()
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+
+		_ = json.NewEncoder(w).Encode(response)
+	})
+}
+
+func (r *Runner) service(name string) Service {
+	for _, tier := range [][]Service{r.infrastructureServices, r.coreServices} {
+		for _, svc := range tier {
+			if svc.Name() == name {
+				return svc
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,174 @@
+package runner_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/runner"
+	"github.com/stretchr/testify/require"
+)
+
+type blockingService struct {
+	name   string
+	starts atomic.Int32
+	stops  atomic.Int32
+}
+
+func (b *blockingService) Start(ctx context.Context) error {
+	b.starts.Add(1)
+	<-ctx.Done()
+
+	return ctx.Err()
+}
+
+func (b *blockingService) Stop() error {
+	b.stops.Add(1)
+
+	return nil
+}
+
+func (b *blockingService) Name() string {
+	return b.name
+}
+
+func startRunner(t *testing.T, r *runner.Runner) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan error, 1)
+
+	go func() {
+		done <- r.RunContext(ctx)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+func TestRunner_RestartRestartsOnlyTheNamedService(t *testing.T) {
+	t.Parallel()
+
+	target := &blockingService{name: "subscriber"}
+	other := &blockingService{name: "api"}
+
+	r := runner.New(
+		runner.WithCoreService(target),
+		runner.WithCoreService(other),
+		runner.WithShutdownTimeout(time.Second),
+	)
+
+	startRunner(t, r)
+
+	require.Eventually(t, func() bool {
+		return target.starts.Load() == 1 && other.starts.Load() == 1
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, r.Restart("subscriber"))
+
+	require.Eventually(t, func() bool {
+		return target.starts.Load() == 2
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, int32(1), target.stops.Load())
+	require.Equal(t, int32(1), other.starts.Load())
+	require.Equal(t, int32(0), other.stops.Load())
+}
+
+func TestRunner_RestartErrors(t *testing.T) {
+	t.Parallel()
+
+	r := runner.New(runner.WithCoreService(&blockingService{name: "subscriber"}))
+
+	require.ErrorIs(t, r.Restart("missing"), runner.ErrServiceNotFound)
+	require.ErrorIs(t, r.Restart("subscriber"), runner.ErrServiceNotRunning)
+}
+
+// failingOnStopService fails every time it is stopped, as servers that report their shutdown as an
+// error do.
+type failingOnStopService struct {
+	blockingService
+}
+
+func (f *failingOnStopService) Start(ctx context.Context) error {
+	_ = f.blockingService.Start(ctx)
+
+	return errStart
+}
+
+func TestRunner_RestartDoesNotBlockOnUnreadFailures(t *testing.T) {
+	t.Parallel()
+
+	svc := &failingOnStopService{blockingService: blockingService{name: "db"}}
+	restarted := make(chan error, 1)
+
+	var r *runner.Runner
+
+	r = runner.New(
+		runner.WithInfrastructureService(svc),
+		runner.WithJob("migrate", func(_ context.Context) error {
+			// Nothing reads the failures of the infrastructure tier while a job runs, so the
+			// second failure does not fit.
+			for range 3 {
+				if err := r.Restart("db"); err != nil {
+					restarted <- err
+
+					return nil
+				}
+			}
+
+			restarted <- nil
+
+			return nil
+		}),
+		runner.WithShutdownTimeout(time.Second),
+	)
+
+	_ = r.RunContext(t.Context())
+
+	require.NoError(t, <-restarted)
+	require.Equal(t, int32(4), svc.starts.Load())
+}
+
+func TestRunner_RestartHandler(t *testing.T) {
+	t.Parallel()
+
+	svc := &blockingService{name: "subscriber"}
+	r := runner.New(runner.WithCoreService(svc), runner.WithShutdownTimeout(time.Second))
+
+	startRunner(t, r)
+
+	require.Eventually(t, func() bool { return svc.starts.Load() == 1 }, time.Second, 10*time.Millisecond)
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		status int
+	}{
+		{name: "restarts service", method: http.MethodPost, target: "/admin/restart?service=subscriber", status: http.StatusOK},
+		{name: "unknown service", method: http.MethodPost, target: "/admin/restart?service=missing", status: http.StatusNotFound},
+		{name: "missing parameter", method: http.MethodPost, target: "/admin/restart", status: http.StatusBadRequest},
+		{
+			name:   "wrong method",
+			method: http.MethodGet,
+			target: "/admin/restart?service=subscriber",
+			status: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequestWithContext(t.Context(), tt.method, tt.target, nil)
+		rec := httptest.NewRecorder()
+
+		r.RestartHandler().ServeHTTP(rec, req)
+
+		require.Equal(t, tt.status, rec.Code, tt.name)
+	}
+
+	require.Eventually(t, func() bool { return svc.starts.Load() == 2 }, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,101 @@
+package runner
+
+import (
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ServiceStopReport describes how a single service stopped. Duration is the time until the service
+// stopped, or until the shutdown timeout for services that did not stop in time.
+type ServiceStopReport struct {
+	Name     string
+	Tier     Tier
+	Duration time.Duration
+	Err      error
+	TimedOut bool
+}
+
+// ShutdownReport summarizes a shutdown so that slow or failing services can be identified.
+type ShutdownReport struct {
+	Reason   error
+	Duration time.Duration
+	TimedOut bool
+	Services []ServiceStopReport
+}
+
+// Slowest returns the service that took the longest to stop.
+func (r ShutdownReport) Slowest() (ServiceStopReport, bool) {
+	var slowest ServiceStopReport
+
+	for _, svc := range r.Services {
+		if svc.Duration >= slowest.Duration {
+			slowest = svc
+		}
+	}
+
+	return slowest, len(r.Services) > 0
+}
+
+// ShutdownMetrics records shutdown reports, e.g. as Prometheus metrics via NewPrometheusMetrics.
+type ShutdownMetrics interface {
+	ServiceStopped(report ServiceStopReport)
+	ShutdownCompleted(duration time.Duration, timedOut bool)
+}
+
+// WithShutdownReporter registers fn to receive the report after every shutdown.
+func WithShutdownReporter(fn func(ShutdownReport)) Option {
+	return func(r *Runner) {
+		if fn != nil {
+			r.shutdownReporters = append(r.shutdownReporters, fn)
+		}
+	}
+}
+
+func WithShutdownMetrics(m ShutdownMetrics) Option {
+	return func(r *Runner) {
+		r.shutdownMetrics = m
+	}
+}
+
+func (r *Runner) publishShutdownReport(report ShutdownReport) {
+	event := log.Info()
+	if report.TimedOut {
+		event = log.Warn()
+	}
+
+	event = event.
+		Str("source", "gframework").
+		Dur("duration", report.Duration).
+		Dur("timeout", r.shutdownTimeout).
+		Bool("timed_out", report.TimedOut).
+		Int("services", len(report.Services))
+
+	if slowest, ok := report.Slowest(); ok {
+		event = event.
+			Str("slowest_service", slowest.Name).
+			Dur("slowest_duration", slowest.Duration)
+	}
+
+	failed := make([]string, 0)
+
+	for _, svc := range report.Services {
+		if svc.Err != nil || svc.TimedOut {
+			failed = append(failed, svc.Name)
+		}
+	}
+
+	event.Strs("failed_services", failed).Msg("Shutdown report")
+
+	if r.shutdownMetrics != nil {
+		for _, svc := range report.Services {
+			r.shutdownMetrics.ServiceStopped(svc)
+		}
+
+		r.shutdownMetrics.ShutdownCompleted(report.Duration, report.TimedOut)
+	}
+
+	for _, reporter := range r.shutdownReporters {
+		reporter(report)
+	}
+}
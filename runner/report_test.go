@@ -0,0 +1,95 @@
+package runner_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/runner"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithShutdownReporter_ReportsSlowAndFailingServices(t *testing.T) {
+	t.Parallel()
+
+	slow := newMockService("slow")
+	slow.stopDelay = 300 * time.Millisecond
+
+	failing := newMockService("failing")
+	failing.stopErr = errStop
+
+	fast := newMockService("fast")
+
+	reports := make(chan runner.ShutdownReport, 1)
+
+	r := runner.New(
+		runner.WithInfrastructureService(fast),
+		runner.WithCoreService(slow),
+		runner.WithCoreService(failing),
+		runner.WithShutdownTimeout(50*time.Millisecond),
+		runner.WithShutdownReporter(func(report runner.ShutdownReport) { reports <- report }),
+	)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+
+	err := r.RunContext(ctx)
+	require.ErrorIs(t, err, runner.ErrShutdownTimout)
+
+	report := <-reports
+	require.True(t, report.TimedOut)
+	require.ErrorIs(t, report.Reason, context.DeadlineExceeded)
+	require.Len(t, report.Services, 3)
+
+	byName := make(map[string]runner.ServiceStopReport)
+	for _, svc := range report.Services {
+		byName[svc.Name] = svc
+	}
+
+	require.True(t, byName["slow"].TimedOut)
+	require.Equal(t, runner.TierCore, byName["slow"].Tier)
+	require.ErrorIs(t, byName["failing"].Err, errStop)
+	require.False(t, byName["failing"].TimedOut)
+	require.False(t, byName["fast"].TimedOut)
+	require.Equal(t, runner.TierInfrastructure, byName["fast"].Tier)
+
+	slowest, ok := report.Slowest()
+	require.True(t, ok)
+	require.Equal(t, "slow", slowest.Name)
+}
+
+func TestPrometheusMetrics(t *testing.T) {
+	t.Parallel()
+
+	reg := prometheus.NewRegistry()
+
+	metrics, err := runner.NewPrometheusMetrics(reg)
+	require.NoError(t, err)
+
+	metrics.ServiceStopped(runner.ServiceStopReport{
+		Name:     "api",
+		Tier:     runner.TierCore,
+		Duration: 2 * time.Second,
+		Err:      errStop,
+		TimedOut: true,
+	})
+	metrics.ShutdownCompleted(3*time.Second, true)
+
+	expected := `
+# HELP gframework_runner_service_stop_timeouts_total Number of times the service did not stop within the shutdown timeout.
+# TYPE gframework_runner_service_stop_timeouts_total counter
+gframework_runner_service_stop_timeouts_total{service="api",tier="core"} 1
+# HELP gframework_runner_shutdown_duration_seconds Duration of the last shutdown.
+# TYPE gframework_runner_shutdown_duration_seconds gauge
+gframework_runner_shutdown_duration_seconds 3
+`
+
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"gframework_runner_service_stop_timeouts_total", "gframework_runner_shutdown_duration_seconds"))
+
+	_, err = runner.NewPrometheusMetrics(reg)
+	require.Error(t, err)
+}
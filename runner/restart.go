@@ -0,0 +1,157 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/andyle182810/gframework/clock"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultRestartMaxAttempts    = 5
+	defaultRestartInitialBackoff = time.Second
+	defaultRestartMaxBackoff     = 30 * time.Second
+)
+
+var (
+	ErrRestartsExhausted = errors.New("runner: service restart attempts exhausted")
+	errServiceExited     = errors.New("service exited")
+)
+
+type RestartMode int
+
+const (
+	// RestartOnFailure restarts a service whose Start returns an error or panics.
+	RestartOnFailure RestartMode = iota
+	// RestartAlways also restarts a service whose Start returns nil. Only use it for services whose
+	// Start blocks until the context is cancelled.
+	RestartAlways
+)
+
+// RestartPolicy controls how a supervised service is restarted. MaxAttempts <= 0 means unlimited.
+// The attempt counter resets once the service has stayed up for at least MaxBackoff. Clock times the
+// backoffs; nil means clock.Real.
+type RestartPolicy struct {
+	Mode           RestartMode
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Clock          clock.Clock
+}
+
+func DefaultRestartPolicy() RestartPolicy {
+	return RestartPolicy{
+		Mode:           RestartOnFailure,
+		MaxAttempts:    defaultRestartMaxAttempts,
+		InitialBackoff: defaultRestartInitialBackoff,
+		MaxBackoff:     defaultRestartMaxBackoff,
+		Clock:          nil,
+	}
+}
+
+type supervisedService struct {
+	Service
+
+	policy RestartPolicy
+}
+
+// WithRestartPolicy wraps svc so that failures restart it with exponential backoff instead of being
+// fatal. When the attempts are exhausted, Start returns ErrRestartsExhausted and the runner shuts the
+// whole application down.
+func WithRestartPolicy(svc Service, policy RestartPolicy) Service {
+	if policy.InitialBackoff <= 0 {
+		policy.InitialBackoff = defaultRestartInitialBackoff
+	}
+
+	if policy.MaxBackoff < policy.InitialBackoff {
+		policy.MaxBackoff = max(defaultRestartMaxBackoff, policy.InitialBackoff)
+	}
+
+	policy.Clock = clock.OrReal(policy.Clock)
+
+	return &supervisedService{
+		Service: svc,
+		policy:  policy,
+	}
+}
+
+func (s *supervisedService) Start(ctx context.Context) error {
+	attempt := 0
+
+	for {
+		startedAt := s.policy.Clock.Now()
+
+		err := s.startOnce(ctx)
+		if ctx.Err() != nil {
+			return err
+		}
+
+		if err == nil {
+			if s.policy.Mode != RestartAlways {
+				return nil
+			}
+
+			err = errServiceExited
+		}
+
+		if s.policy.Clock.Since(startedAt) >= s.policy.MaxBackoff {
+			attempt = 0
+		}
+
+		attempt++
+
+		if s.policy.MaxAttempts > 0 && attempt > s.policy.MaxAttempts {
+			return fmt.Errorf("%w: %s after %d attempts: %w", ErrRestartsExhausted, s.Name(), s.policy.MaxAttempts, err)
+		}
+
+		backoff := s.backoff(attempt)
+
+		log.Warn().
+			Str("source", "gframework").
+			Err(err).
+			Str("service_name", s.Name()).
+			Int("attempt", attempt).
+			Dur("backoff", backoff).
+			Msg("Service failed, restarting")
+
+		if stopErr := s.Service.Stop(); stopErr != nil {
+			log.Debug().
+				Str("source", "gframework").
+				Err(stopErr).
+				Str("service_name", s.Name()).
+				Msg("Service stop before restart failed")
+		}
+
+		select {
+		case <-s.policy.Clock.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *supervisedService) startOnce(ctx context.Context) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("%w: %s: %v", ErrServicePanic, s.Name(), rec)
+		}
+	}()
+
+	return s.Service.Start(ctx)
+}
+
+func (s *supervisedService) backoff(attempt int) time.Duration {
+	backoff := s.policy.InitialBackoff
+	for i := 1; i < attempt && backoff < s.policy.MaxBackoff; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, s.policy.MaxBackoff)
+}
+
+func (s *supervisedService) Unwrap() Service {
+	return s.Service
+}
@@ -0,0 +1,131 @@
+package runner_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/runner"
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+type flakyService struct {
+	name     string
+	failures int32
+	starts   atomic.Int32
+	stops    atomic.Int32
+}
+
+func (f *flakyService) Start(_ context.Context) error {
+	if f.starts.Add(1) <= f.failures {
+		return errStart
+	}
+
+	return nil
+}
+
+func (f *flakyService) Stop() error {
+	f.stops.Add(1)
+
+	return nil
+}
+
+func (f *flakyService) Name() string {
+	return f.name
+}
+
+func newTestRestartPolicy(maxAttempts int) runner.RestartPolicy {
+	return runner.RestartPolicy{
+		Mode:           runner.RestartOnFailure,
+		MaxAttempts:    maxAttempts,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+		Clock:          nil,
+	}
+}
+
+func TestWithRestartPolicy_RecoversFromTransientFailures(t *testing.T) {
+	t.Parallel()
+
+	svc := &flakyService{name: "flaky", failures: 2}
+	supervised := runner.WithRestartPolicy(svc, newTestRestartPolicy(3))
+
+	require.NoError(t, supervised.Start(t.Context()))
+	require.Equal(t, int32(3), svc.starts.Load())
+	require.Equal(t, int32(2), svc.stops.Load())
+	require.Equal(t, "flaky", supervised.Name())
+}
+
+func TestWithRestartPolicy_ExhaustsAttempts(t *testing.T) {
+	t.Parallel()
+
+	svc := &flakyService{name: "broken", failures: 100}
+	supervised := runner.WithRestartPolicy(svc, newTestRestartPolicy(2))
+
+	err := supervised.Start(t.Context())
+	require.ErrorIs(t, err, runner.ErrRestartsExhausted)
+	require.ErrorIs(t, err, errStart)
+	require.Equal(t, int32(3), svc.starts.Load())
+}
+
+func TestWithRestartPolicy_RecoversFromPanic(t *testing.T) {
+	t.Parallel()
+
+	svc := newMockService("panicky")
+	svc.panicOnStart = true
+
+	supervised := runner.WithRestartPolicy(svc, newTestRestartPolicy(1))
+
+	err := supervised.Start(t.Context())
+	require.ErrorIs(t, err, runner.ErrRestartsExhausted)
+	require.ErrorIs(t, err, runner.ErrServicePanic)
+}
+
+func TestWithRestartPolicy_StopsOnContextCancel(t *testing.T) {
+	t.Parallel()
+
+	svc := &flakyService{name: "cancelled", failures: 100}
+
+	policy := newTestRestartPolicy(0)
+	policy.InitialBackoff = time.Hour
+	policy.MaxBackoff = time.Hour
+
+	supervised := runner.WithRestartPolicy(svc, policy)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, supervised.Start(ctx), context.DeadlineExceeded)
+	require.Equal(t, int32(1), svc.starts.Load())
+}
+
+func TestWithRestartPolicy_BackoffFollowsClock(t *testing.T) {
+	t.Parallel()
+
+	clk := testutil.NewFakeClock(t)
+	svc := &flakyService{name: "flaky", failures: 2}
+	supervised := runner.WithRestartPolicy(svc, runner.RestartPolicy{
+		Mode:           runner.RestartOnFailure,
+		MaxAttempts:    3,
+		InitialBackoff: time.Hour,
+		MaxBackoff:     4 * time.Hour,
+		Clock:          clk,
+	})
+
+	done := make(chan error, 1)
+
+	go func() { done <- supervised.Start(t.Context()) }()
+
+	clk.BlockUntil(1)
+	require.Equal(t, int32(1), svc.starts.Load())
+
+	clk.Advance(time.Hour)
+	clk.BlockUntil(1)
+	require.Equal(t, int32(2), svc.starts.Load())
+
+	clk.Advance(2 * time.Hour)
+	require.NoError(t, <-done)
+	require.Equal(t, int32(3), svc.starts.Load())
+}
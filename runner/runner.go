@@ -15,8 +15,27 @@
 //	)
 //	r.Run() // Blocks until SIGINT/SIGTERM; calls os.Exit(1) on error
 //
+// RunContext is the non-exiting variant: it stops when the given context is cancelled as well and
+// returns the reason for shutdown, so main can choose an exit code and tests can drive the runner.
+//
+// WithLeaderElectedService runs a service on a single replica only, guarded by a distributed LeaderLock
+// such as distlock.LeaderLock, and fails over when the leader's lock expires.
+//
+// WithJob registers run-to-completion tasks (migrations, backfills) that run between the two tiers; a failing
+// job makes Run exit with status 1.
+//
+// Services implementing ReadinessNotifier are waited for before the next tier starts; WithStartTimeout
+// bounds that wait.
+//
+// WithHook runs functions around each tier's startup and shutdown, e.g. warming caches once infrastructure
+// is up or flushing buffers before core services stop. The same phases are published on Events.
+//
+// Every shutdown produces a ShutdownReport with per-service stop durations and errors; it is logged and
+// passed to WithShutdownReporter callbacks and WithShutdownMetrics.
+//
 // Each service must implement the Service interface (Start, Stop, Name).
-// Startup failures in any tier abort the application immediately.
+// Startup failures in any tier abort the application immediately. Wrap a service with WithRestartPolicy
+// to restart it on failure; exhausting its restart attempts shuts the whole application down.
 package runner
 
 import (
@@ -38,6 +57,7 @@ var (
 	ErrServicePanic   = errors.New("runner: service panicked")
 	ErrServiceFailed  = errors.New("runner: service failed to start")
 	ErrShutdownTimout = errors.New("runner: shutdown timeout exceeded")
+	ErrSignalReceived = errors.New("runner: shutdown signal received")
 )
 
 type Service interface {
@@ -50,6 +70,15 @@ type Runner struct {
 	coreServices           []Service
 	infrastructureServices []Service
 	shutdownTimeout        time.Duration
+	failures               chan error
+	mu                     sync.Mutex
+	restartMu              sync.Mutex
+	running                map[string]*runningService
+	hooks                  map[LifecyclePhase][]HookFunc
+	events                 chan Event
+	jobs                   []job
+	shutdownReporters      []func(ShutdownReport)
+	shutdownMetrics        ShutdownMetrics
 }
 
 type Option func(*Runner)
@@ -59,6 +88,15 @@ func New(opts ...Option) *Runner {
 		coreServices:           make([]Service, 0),
 		infrastructureServices: make([]Service, 0),
 		shutdownTimeout:        defaultShutdownTimeout,
+		failures:               nil,
+		mu:                     sync.Mutex{},
+		restartMu:              sync.Mutex{},
+		running:                make(map[string]*runningService),
+		hooks:                  make(map[LifecyclePhase][]HookFunc),
+		events:                 make(chan Event, eventBufferSize),
+		jobs:                   nil,
+		shutdownReporters:      nil,
+		shutdownMetrics:        nil,
 	}
 
 	for _, opt := range opts {
@@ -96,25 +134,74 @@ func WithShutdownTimeout(d time.Duration) Option {
 	}
 }
 
+// Run starts all services and blocks until SIGINT/SIGTERM or a fatal service failure, then shuts down.
+// It exits the process with status 1 when the shutdown was caused by a failure; a shutdown timeout alone
+// does not change the exit status. Use RunContext to act on ErrShutdownTimout.
 func (r *Runner) Run() {
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
-	defer stop()
+	_, reason := r.run(context.Background())
+	if reason != nil && !errors.Is(reason, ErrSignalReceived) {
+		os.Exit(1)
+	}
+}
+
+// RunContext starts all services and blocks until ctx is done, SIGINT/SIGTERM is received or a
+// service fails fatally. After shutting down it returns the reason: the service error, an error
+// wrapping ErrSignalReceived, or the cause of ctx. ErrShutdownTimout is joined in when services did
+// not stop within the shutdown timeout.
+func (r *Runner) RunContext(parent context.Context) error {
+	timedOut, reason := r.run(parent)
+	if timedOut {
+		return errors.Join(reason, ErrShutdownTimout)
+	}
+
+	return reason
+}
+
+// run is RunContext with the shutdown timeout reported apart from the reason for shutdown.
+func (r *Runner) run(parent context.Context) (bool, error) {
+	ctx, cancel := context.WithCancelCause(parent)
+	defer cancel(nil)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	defer signal.Stop(signals)
+
+	go func() {
+		select {
+		case sig := <-signals:
+			cancel(fmt.Errorf("%w: %s", ErrSignalReceived, sig))
+		case <-ctx.Done():
+		}
+	}()
 
-	log.Info().Str("source", "gframework").Msg("Starting infrastructure services")
+	r.failures = make(chan error, len(r.infrastructureServices)+len(r.coreServices))
 
-	if err := r.startServices(ctx, r.infrastructureServices); err != nil {
+	if err := r.startTier(ctx, TierInfrastructure); err != nil {
 		log.Error().Str("source", "gframework").Err(err).Msg("Infrastructure services failed to start")
-		r.shutdownWithTimeout(r.infrastructureServices)
-		os.Exit(1)
+		cancel(err)
+
+		return r.shutdown(ctx, context.Cause(ctx), TierInfrastructure)
+	}
+
+	if err := r.runJobs(ctx); err != nil {
+		log.Error().Str("source", "gframework").Err(err).Msg("Job failed")
+		cancel(err)
+
+		return r.shutdown(ctx, context.Cause(ctx), TierInfrastructure)
 	}
 
-	log.Info().Str("source", "gframework").Msg("Starting core services")
+	if len(r.jobs) > 0 && len(r.coreServices) == 0 {
+		log.Info().Str("source", "gframework").Int("jobs", len(r.jobs)).Msg("All jobs completed, shutting down")
 
-	if err := r.startServices(ctx, r.coreServices); err != nil {
+		return r.shutdown(ctx, nil, TierInfrastructure)
+	}
+
+	if err := r.startTier(ctx, TierCore); err != nil {
 		log.Error().Str("source", "gframework").Err(err).Msg("Core services failed to start")
-		r.shutdownWithTimeout(r.coreServices)
-		r.shutdownWithTimeout(r.infrastructureServices)
-		os.Exit(1)
+		cancel(err)
+
+		return r.shutdown(ctx, context.Cause(ctx), TierCore, TierInfrastructure)
 	}
 
 	log.Info().
@@ -124,13 +211,108 @@ func (r *Runner) Run() {
 		Int("infra_services", len(r.infrastructureServices)).
 		Msg("All services started, waiting for shutdown signal")
 
-	<-ctx.Done()
-	log.Warn().Str("source", "gframework").Msg("Shutdown signal received")
+	if err := r.waitForShutdown(ctx); err != nil {
+		log.Error().Str("source", "gframework").Err(err).Msg("Service failed permanently, shutting down")
+		cancel(err)
+	} else {
+		log.Warn().Str("source", "gframework").Err(context.Cause(ctx)).Msg("Shutdown signal received")
+	}
+
+	timedOut, reason := r.shutdown(ctx, context.Cause(ctx), TierCore, TierInfrastructure)
+	if !timedOut {
+		log.Info().Str("source", "gframework").Msg("Graceful shutdown completed")
+	}
+
+	return timedOut, reason
+}
+
+// startTier runs the start hooks of tier around starting its services.
+func (r *Runner) startTier(ctx context.Context, tier Tier) error {
+	log.Info().Str("source", "gframework").Str("tier", string(tier)).Msg("Starting services")
+
+	err := r.runHooks(ctx, OnBeforeStart, tier)
+	r.emit(OnBeforeStart, tier, err)
+
+	if err != nil {
+		return err
+	}
+
+	if err := r.startServices(ctx, r.tierServices(tier)); err != nil {
+		return err
+	}
+
+	err = r.runHooks(ctx, OnAfterStart, tier)
+	r.emit(OnAfterStart, tier, err)
+
+	return err
+}
+
+// shutdown stops each tier in order, running its stop hooks, publishes a ShutdownReport and returns
+// whether a tier did not stop in time along with reason. Stop hooks get a context that is not cancelled
+// with ctx but is bounded by the shutdown timeout.
+func (r *Runner) shutdown(ctx context.Context, reason error, tiers ...Tier) (bool, error) {
+	hookCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), r.shutdownTimeout)
+	defer cancel()
+
+	report := ShutdownReport{
+		Reason:   reason,
+		Duration: 0,
+		TimedOut: false,
+		Services: make([]ServiceStopReport, 0, len(r.coreServices)+len(r.infrastructureServices)),
+	}
+
+	startedAt := time.Now()
 
-	r.shutdownWithTimeout(r.coreServices)
-	r.shutdownWithTimeout(r.infrastructureServices)
+	for _, tier := range tiers {
+		r.runStopHooks(hookCtx, OnBeforeStop, tier)
+		r.emit(OnBeforeStop, tier, reason)
 
-	log.Info().Str("source", "gframework").Msg("Graceful shutdown completed")
+		services, stopped := r.shutdownWithTimeout(tier)
+		report.Services = append(report.Services, services...)
+
+		if !stopped {
+			report.TimedOut = true
+		}
+
+		r.runStopHooks(hookCtx, OnAfterStop, tier)
+		r.emit(OnAfterStop, tier, reason)
+	}
+
+	report.Duration = time.Since(startedAt)
+	r.publishShutdownReport(report)
+
+	return report.TimedOut, reason
+}
+
+func (r *Runner) runStopHooks(ctx context.Context, phase LifecyclePhase, tier Tier) {
+	if err := r.runHooks(ctx, phase, tier); err != nil {
+		log.Error().Str("source", "gframework").Err(err).Msg("Lifecycle hook failed during shutdown")
+	}
+}
+
+func (r *Runner) tierServices(tier Tier) []Service {
+	if tier == TierInfrastructure {
+		return r.infrastructureServices
+	}
+
+	return r.coreServices
+}
+
+// waitForShutdown blocks until ctx is done or a supervised service exhausts its restarts.
+// Other late failures are logged but do not stop the application.
+func (r *Runner) waitForShutdown(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-r.failures:
+			if errors.Is(err, ErrRestartsExhausted) {
+				return err
+			}
+
+			log.Error().Str("source", "gframework").Err(err).Msg("Service failed after startup")
+		}
+	}
 }
 
 func (r *Runner) startServices(ctx context.Context, services []Service) error {
@@ -138,28 +320,20 @@ func (r *Runner) startServices(ctx context.Context, services []Service) error {
 		return nil
 	}
 
-	errCh := make(chan error, len(services))
+	startedAt := time.Now()
 
 	for _, svc := range services {
-		go func(service Service) {
-			defer func() {
-				if rec := recover(); rec != nil {
-					errCh <- fmt.Errorf("%w: %s: %v", ErrServicePanic, service.Name(), rec)
-				}
-			}()
-
-			log.Info().Str("source", "gframework").Str("service_name", service.Name()).Msg("Starting service")
+		r.launch(ctx, svc)
+	}
 
-			if err := service.Start(ctx); err != nil && !errors.Is(err, context.Canceled) {
-				errCh <- fmt.Errorf("%w: %s: %w", ErrServiceFailed, service.Name(), err)
-			}
-		}(svc)
+	if err := r.awaitReady(ctx, services, startedAt); err != nil {
+		return err
 	}
 
 	// Check for immediate startup failures.
 	// Note: This only catches services that fail synchronously.
 	select {
-	case err := <-errCh:
+	case err := <-r.failures:
 		return err
 	case <-ctx.Done():
 		return ctx.Err()
@@ -168,54 +342,108 @@ func (r *Runner) startServices(ctx context.Context, services []Service) error {
 	}
 }
 
-func (r *Runner) shutdownWithTimeout(services []Service) {
-	if len(services) == 0 {
-		return
-	}
-
-	done := make(chan struct{})
+type stopResult struct {
+	index    int
+	duration time.Duration
+	err      error
+}
 
-	go func() {
-		r.concurrentStop(services)
-		close(done)
-	}()
+// shutdownWithTimeout stops the services of tier concurrently and reports how long each took, from
+// calling Stop until its Start returned. Services that have not stopped when the shutdown timeout is hit
+// are reported as timed out.
+func (r *Runner) shutdownWithTimeout(tier Tier) ([]ServiceStopReport, bool) {
+	services := r.tierServices(tier)
+	reports := make([]ServiceStopReport, len(services))
+
+	for i, svc := range services {
+		reports[i] = ServiceStopReport{
+			Name:     svc.Name(),
+			Tier:     tier,
+			Duration: 0,
+			Err:      nil,
+			TimedOut: true,
+		}
+	}
 
-	select {
-	case <-done:
-		return
-	case <-time.After(r.shutdownTimeout):
-		log.Error().
-			Str("source", "gframework").
-			Dur("timeout", r.shutdownTimeout).
-			Msg("Shutdown timeout exceeded, some services may not have stopped cleanly")
+	if len(services) == 0 {
+		return reports, true
 	}
-}
 
-func (r *Runner) concurrentStop(services []Service) {
-	var wg sync.WaitGroup
+	startedAt := time.Now()
+	results := make(chan stopResult, len(services))
+
+	r.concurrentStop(services, results)
+
+	timer := time.NewTimer(r.shutdownTimeout)
+	defer timer.Stop()
+
+	for range services {
+		select {
+		case result := <-results:
+			reports[result.index].Duration = result.duration
+			reports[result.index].Err = result.err
+			reports[result.index].TimedOut = false
+		case <-timer.C:
+			for i := range reports {
+				if reports[i].TimedOut {
+					reports[i].Duration = time.Since(startedAt)
+
+					log.Error().
+						Str("source", "gframework").
+						Str("service_name", reports[i].Name).
+						Dur("timeout", r.shutdownTimeout).
+						Msg("Service did not stop within the shutdown timeout")
+				}
+			}
 
-	for _, svc := range services {
-		wg.Add(1)
+			return reports, false
+		}
+	}
 
-		go func(service Service) {
-			defer wg.Done()
+	return reports, true
+}
 
+func (r *Runner) concurrentStop(services []Service, results chan<- stopResult) {
+	for i, svc := range services {
+		go func(index int, service Service) {
 			log.Info().Str("source", "gframework").Str("service_name", service.Name()).Msg("Stopping service")
 
-			if err := service.Stop(); err != nil {
+			startedAt := time.Now()
+			err := service.Stop()
+			r.awaitStart(service.Name())
+			duration := time.Since(startedAt)
+
+			if err != nil {
 				log.Error().
 					Str("source", "gframework").
 					Err(err).
 					Str("service_name", service.Name()).
+					Dur("duration", duration).
 					Msg("Service failed to stop")
 			} else {
 				log.Info().
 					Str("source", "gframework").
 					Str("service_name", service.Name()).
+					Dur("duration", duration).
 					Msg("Service stopped")
 			}
-		}(svc)
+
+			results <- stopResult{index: index, duration: duration, err: err}
+		}(i, svc)
+	}
+}
+
+// awaitStart cancels the context of the named service, as last launched, after it was stopped and
+// blocks until its Start has returned.
+func (r *Runner) awaitStart(name string) {
+	r.mu.Lock()
+	running, ok := r.running[name]
+	r.mu.Unlock()
+
+	if !ok {
+		return
 	}
 
-	wg.Wait()
+	running.cancel()
+	<-running.done
 }
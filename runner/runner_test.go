@@ -369,3 +369,80 @@ func TestMockService_ConcurrentStopIsSafe(t *testing.T) {
 		<-done
 	}
 }
+
+func TestRunContext_ReturnsCauseWhenContextCancelled(t *testing.T) {
+	t.Parallel()
+
+	core := newMockService("core")
+	infra := newMockService("infra")
+	r := runner.New(
+		runner.WithCoreService(core),
+		runner.WithInfrastructureService(infra),
+		runner.WithShutdownTimeout(time.Second),
+	)
+
+	ctx, cancel := context.WithCancel(t.Context())
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- r.RunContext(ctx)
+	}()
+
+	require.Eventually(t, core.wasStarted, time.Second, 10*time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		require.ErrorIs(t, err, context.Canceled)
+	case <-time.After(2 * time.Second):
+		t.Fatal("RunContext did not return after cancellation")
+	}
+
+	require.True(t, core.wasStopped())
+	require.True(t, infra.wasStopped())
+}
+
+func TestRunContext_JoinsShutdownTimeout(t *testing.T) {
+	t.Parallel()
+
+	core := newMockService("slow")
+	core.stopDelay = 500 * time.Millisecond
+
+	r := runner.New(
+		runner.WithCoreService(core),
+		runner.WithShutdownTimeout(50*time.Millisecond),
+	)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+
+	err := r.RunContext(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.ErrorIs(t, err, runner.ErrShutdownTimout)
+}
+
+func TestRunContext_ReturnsExhaustedRestarts(t *testing.T) {
+	t.Parallel()
+
+	failing := newMockService("failing")
+	failing.startErr = errStart
+
+	r := runner.New(
+		runner.WithCoreService(runner.WithRestartPolicy(failing, runner.RestartPolicy{
+			Mode:           runner.RestartOnFailure,
+			MaxAttempts:    1,
+			InitialBackoff: 10 * time.Millisecond,
+			MaxBackoff:     10 * time.Millisecond,
+			Clock:          nil,
+		})),
+		runner.WithShutdownTimeout(time.Second),
+	)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 2*time.Second)
+	defer cancel()
+
+	err := r.RunContext(ctx)
+	require.ErrorIs(t, err, runner.ErrRestartsExhausted)
+	require.ErrorIs(t, err, errStart)
+}
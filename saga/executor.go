@@ -0,0 +1,283 @@
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/andyle182810/gframework/logutil"
+	"github.com/andyle182810/gframework/taskqueue"
+	"github.com/jackc/pgx/v5"
+)
+
+var errStale = errors.New("saga: task is stale")
+
+// claimed is a saga instance claimed for one attempt of a step.
+type claimed struct {
+	id       string
+	status   Status
+	step     int
+	data     json.RawMessage
+	attempts int
+	version  int64
+	cause    string
+	expired  bool
+}
+
+// Execute runs the step a task of the queue was dispatched for. Tasks of a version the saga has moved
+// past, e.g. pushed twice or dispatched again after a lost push, are dropped.
+func (o *Orchestrator) Execute(ctx context.Context, _ string, payload taskqueue.Payload) error {
+	var ref taskRef
+
+	if err := json.Unmarshal(payload, &ref); err != nil {
+		return fmt.Errorf("saga: invalid task: %w", err)
+	}
+
+	def, instance, err := o.claim(ctx, ref)
+	if errors.Is(err, errStale) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if instance.status == StatusRunning && instance.expired {
+		return o.compensate(ctx, def, instance, ErrTimeout.Error())
+	}
+
+	step := def.Steps[instance.step]
+
+	run := step.Action
+	if instance.status == StatusCompensating {
+		run = step.Compensate
+	}
+
+	if run == nil {
+		return o.advance(ctx, def, instance, instance.data)
+	}
+
+	exec := &Execution{
+		ID:           instance.id,
+		Saga:         def.Name,
+		Step:         step.Name,
+		Attempt:      instance.attempts,
+		Compensating: instance.status == StatusCompensating,
+		Cause:        instance.cause,
+		data:         instance.data,
+	}
+
+	err = o.run(ctx, o.stepTimeoutOf(step), run, exec)
+
+	switch {
+	case err == nil:
+		return o.advance(ctx, def, instance, exec.data)
+	case errors.Is(ctx.Err(), context.Canceled):
+		return o.release(ctx, instance)
+	case instance.status == StatusRunning && errors.Is(err, ErrAbort):
+		return o.compensate(ctx, def, instance, err.Error())
+	case instance.attempts < o.maxAttemptsOf(step):
+		return o.retry(ctx, instance, err)
+	case instance.status == StatusRunning:
+		return o.compensate(ctx, def, instance, err.Error())
+	default:
+		return o.fail(ctx, instance, err)
+	}
+}
+
+// claim fences the saga of ref for one attempt: it bumps the version, so other tasks of the saga become
+// stale, and leases the step for its timeout.
+func (o *Orchestrator) claim(ctx context.Context, ref taskRef) (*Definition, *claimed, error) {
+	query := fmt.Sprintf(`SELECT name, step FROM %s WHERE id = $1 AND version = $2 AND status IN ($3, $4)`,
+		o.table.Identifier())
+
+	var (
+		name string
+		step int
+	)
+
+	err := o.db.QueryRow(ctx, query, ref.ID, ref.Version, StatusRunning, StatusCompensating).Scan(&name, &step)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, errStale
+	}
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("saga: failed to load %s: %w", ref.ID, err)
+	}
+
+	// An unknown definition is left to an orchestrator that registered it, e.g. during a rollout.
+	def, err := o.definition(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if step < 0 || step >= len(def.Steps) {
+		return nil, nil, fmt.Errorf("%w: %s has no step %d", ErrInvalidSaga, name, step)
+	}
+
+	query = fmt.Sprintf(`UPDATE %s SET version = version + 1, attempts = attempts + 1,
+			wake_at = now() + make_interval(secs => $3), updated_at = now()
+		WHERE id = $1 AND version = $2 AND status IN ($4, $5)
+		RETURNING status, step, data, attempts, version, COALESCE(last_error, ''),
+			deadline IS NOT NULL AND deadline <= now()`, o.table.Identifier())
+
+	instance := &claimed{id: ref.ID} //nolint:exhaustruct
+
+	err = o.db.QueryRow(ctx, query, ref.ID, ref.Version, o.lease(o.stepTimeoutOf(def.Steps[step])).Seconds(),
+		StatusRunning, StatusCompensating).Scan(&instance.status, &instance.step, &instance.data,
+		&instance.attempts, &instance.version, &instance.cause, &instance.expired)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, errStale
+	}
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("saga: failed to claim %s: %w", ref.ID, err)
+	}
+
+	return def, instance, nil
+}
+
+// run runs fn with the timeout of the step. The queue cancelling ctx on shutdown cancels the step, but
+// the execution timeout of the queue does not cut a step with a longer timeout short.
+func (o *Orchestrator) run(ctx context.Context, timeout time.Duration, fn StepFunc, exec *Execution) error {
+	stepCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancel()
+
+	stopCancel := context.AfterFunc(ctx, func() {
+		if errors.Is(ctx.Err(), context.Canceled) {
+			cancel()
+		}
+	})
+	defer stopCancel()
+
+	return fn(stepCtx, exec)
+}
+
+// advance records a successful attempt and dispatches the next step or compensation.
+func (o *Orchestrator) advance(ctx context.Context, def *Definition, instance *claimed, data json.RawMessage) error {
+	status, next, clearError := instance.status, instance.step+1, instance.status == StatusRunning
+
+	switch {
+	case instance.status == StatusCompensating:
+		next = def.previousCompensable(instance.step)
+		if next < 0 {
+			status, next = StatusCompensated, instance.step
+		}
+	case next == len(def.Steps):
+		status, next = StatusCompleted, instance.step
+	}
+
+	query := fmt.Sprintf(`UPDATE %s SET status = $3, step = $4, data = $5, attempts = 0, wake_at = now(),
+			updated_at = now(), last_error = CASE WHEN $6 THEN NULL ELSE last_error END
+		WHERE id = $1 AND version = $2`, o.table.Identifier())
+
+	return o.update(ctx, instance, status.active(), query, status, next, data, clearError)
+}
+
+// compensate makes the saga undo its completed steps, or completes the compensation right away when
+// none of them has a compensation.
+func (o *Orchestrator) compensate(ctx context.Context, def *Definition, instance *claimed, cause string) error {
+	status, next := StatusCompensating, def.previousCompensable(instance.step)
+	if next < 0 {
+		status, next = StatusCompensated, instance.step
+	}
+
+	query := fmt.Sprintf(`UPDATE %s SET status = $3, step = $4, attempts = 0, last_error = $5, wake_at = now(),
+			updated_at = now()
+		WHERE id = $1 AND version = $2`, o.table.Identifier())
+
+	return o.update(ctx, instance, status.active(), query, status, next, truncate(cause))
+}
+
+// retry records a failed attempt, which the poll dispatches again after the retry delay.
+func (o *Orchestrator) retry(ctx context.Context, instance *claimed, cause error) error {
+	query := fmt.Sprintf(`UPDATE %s SET last_error = $3, wake_at = now() + make_interval(secs => $4),
+			updated_at = now()
+		WHERE id = $1 AND version = $2`, o.table.Identifier())
+
+	err := o.update(ctx, instance, false, query, truncate(cause.Error()), o.retryDelay.Seconds())
+	if err != nil {
+		return err
+	}
+
+	return fmt.Errorf("saga: attempt %d of %s failed: %w", instance.attempts, instance.id, cause)
+}
+
+// release gives back an attempt interrupted by the shutdown of the queue, without counting it.
+func (o *Orchestrator) release(ctx context.Context, instance *claimed) error {
+	query := fmt.Sprintf(`UPDATE %s SET attempts = attempts - 1, wake_at = now(), updated_at = now()
+		WHERE id = $1 AND version = $2`, o.table.Identifier())
+
+	return o.update(context.WithoutCancel(ctx), instance, false, query)
+}
+
+// fail stops a saga whose compensation failed for good until it is resumed.
+func (o *Orchestrator) fail(ctx context.Context, instance *claimed, cause error) error {
+	query := fmt.Sprintf(`UPDATE %s SET status = $3, last_error = $4, updated_at = now()
+		WHERE id = $1 AND version = $2`, o.table.Identifier())
+
+	err := o.update(ctx, instance, false, query, StatusFailed, truncate(cause.Error()))
+	if err != nil {
+		return err
+	}
+
+	logutil.FromContext(ctx).Error().
+		Str("source", "gframework").
+		Err(cause).
+		Str("saga_id", instance.id).
+		Int("step", instance.step).
+		Msg("The saga compensation has failed, resume it once the cause is fixed")
+
+	return nil
+}
+
+// update applies a transition to the claimed version of the saga and dispatches the saga right away
+// when dispatch is set. A transition of a saga claimed by another attempt since is dropped.
+func (o *Orchestrator) update(ctx context.Context, instance *claimed, dispatch bool, query string, args ...any) error {
+	tag, err := o.db.Exec(ctx, query, append([]any{instance.id, instance.version}, args...)...)
+	if err != nil {
+		return fmt.Errorf("saga: failed to update %s: %w", instance.id, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		logutil.FromContext(ctx).Warn().
+			Str("source", "gframework").
+			Str("saga_id", instance.id).
+			Int("step", instance.step).
+			Msg("The saga step outlived its lease, its outcome is dropped")
+
+		return nil
+	}
+
+	if dispatch {
+		o.dispatch(ctx, instance.id, instance.version)
+	}
+
+	return nil
+}
+
+func (o *Orchestrator) stepTimeoutOf(step Step) time.Duration {
+	if step.Timeout > 0 {
+		return step.Timeout
+	}
+
+	return o.stepTimeout
+}
+
+func (o *Orchestrator) maxAttemptsOf(step Step) int {
+	if step.MaxAttempts > 0 {
+		return step.MaxAttempts
+	}
+
+	return o.maxAttempts
+}
+
+func truncate(message string) string {
+	if len(message) > maxErrorLength {
+		return message[:maxErrorLength]
+	}
+
+	return message
+}
@@ -0,0 +1,430 @@
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/andyle182810/gframework/logutil"
+	"github.com/andyle182810/gframework/postgres"
+	"github.com/andyle182810/gframework/taskqueue"
+	"github.com/jackc/pgx/v5"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultTable        = "saga_instances"
+	defaultStepTimeout  = 30 * time.Second
+	defaultMaxAttempts  = 3
+	defaultRetryDelay   = 5 * time.Second
+	defaultPollInterval = time.Second
+	defaultBatchSize    = 100
+	leaseMargin         = 10 * time.Second
+	maxErrorLength      = 1024
+)
+
+type Option func(*Orchestrator)
+
+// WithTable sets the table of the saga instances, optionally schema-qualified. It defaults to
+// "saga_instances".
+func WithTable(name string) Option {
+	return func(o *Orchestrator) {
+		o.tableName = name
+	}
+}
+
+// WithStepTimeout sets the timeout of the steps that do not set their own. It defaults to 30 seconds.
+func WithStepTimeout(d time.Duration) Option {
+	return func(o *Orchestrator) {
+		if d > 0 {
+			o.stepTimeout = d
+		}
+	}
+}
+
+// WithMaxAttempts sets the attempts of the steps that do not set their own. It defaults to 3.
+func WithMaxAttempts(n int) Option {
+	return func(o *Orchestrator) {
+		if n > 0 {
+			o.maxAttempts = n
+		}
+	}
+}
+
+// WithRetryDelay sets the delay before a failed step is attempted again. It defaults to five seconds.
+func WithRetryDelay(d time.Duration) Option {
+	return func(o *Orchestrator) {
+		if d > 0 {
+			o.retryDelay = d
+		}
+	}
+}
+
+// WithPollInterval sets how often the table is polled for due sagas, i.e. retries, sagas started in a
+// transaction and steps whose task was lost. It defaults to one second.
+func WithPollInterval(d time.Duration) Option {
+	return func(o *Orchestrator) {
+		if d > 0 {
+			o.pollInterval = d
+		}
+	}
+}
+
+// WithBatchSize sets the maximum number of due sagas dispatched per poll. It defaults to 100.
+func WithBatchSize(n int) Option {
+	return func(o *Orchestrator) {
+		if n > 0 {
+			o.batchSize = n
+		}
+	}
+}
+
+// WithQueueOptions configures the task queue executing the steps, e.g. taskqueue.WithWorkerCount.
+func WithQueueOptions(opts ...taskqueue.Option) Option {
+	return func(o *Orchestrator) {
+		o.queueOptions = append(o.queueOptions, opts...)
+	}
+}
+
+// Orchestrator runs the sagas of its registered definitions. It is a runner service: Start executes
+// steps with the task queue workers and dispatches due sagas until Stop is called. Several
+// orchestrators can share the table and the queue; each step attempt is claimed by one of them.
+type Orchestrator struct {
+	db           postgres.Conn
+	queue        *taskqueue.Queue
+	tableName    string
+	table        postgres.Table
+	stepTimeout  time.Duration
+	maxAttempts  int
+	retryDelay   time.Duration
+	pollInterval time.Duration
+	batchSize    int
+	queueOptions []taskqueue.Option
+	definitions  sync.Map
+	running      atomic.Bool
+	mu           sync.Mutex
+	stop         chan struct{}
+	stopped      chan struct{}
+}
+
+// New returns an orchestrator storing sagas in db and executing their steps with a task queue at
+// queueKey of client.
+func New(db postgres.Conn, client redis.UniversalClient, queueKey string, opts ...Option) (*Orchestrator, error) {
+	if db == nil {
+		return nil, ErrNilDB
+	}
+
+	if client == nil {
+		return nil, ErrNilClient
+	}
+
+	//nolint:exhaustruct
+	orchestrator := &Orchestrator{
+		db:           db,
+		tableName:    defaultTable,
+		stepTimeout:  defaultStepTimeout,
+		maxAttempts:  defaultMaxAttempts,
+		retryDelay:   defaultRetryDelay,
+		pollInterval: defaultPollInterval,
+		batchSize:    defaultBatchSize,
+	}
+
+	for _, opt := range opts {
+		opt(orchestrator)
+	}
+
+	t, err := postgres.NewTable(orchestrator.tableName)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTableName, err)
+	}
+
+	orchestrator.table = t
+
+	queue, err := taskqueue.New(client, queueKey, orchestrator, orchestrator.queueOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("saga: failed to create task queue: %w", err)
+	}
+
+	orchestrator.queue = queue
+
+	return orchestrator, nil
+}
+
+// Schema returns the DDL of the saga table and its index, to include in the service's migrations.
+func (o *Orchestrator) Schema() string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %[1]s (
+	id          TEXT PRIMARY KEY,
+	name        TEXT NOT NULL,
+	status      TEXT NOT NULL,
+	step        INTEGER NOT NULL DEFAULT 0,
+	data        JSONB NOT NULL,
+	attempts    INTEGER NOT NULL DEFAULT 0,
+	version     BIGINT NOT NULL DEFAULT 0,
+	last_error  TEXT,
+	deadline    TIMESTAMPTZ,
+	wake_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+	created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+	updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS %[2]s ON %[1]s (wake_at) WHERE status IN ('running', 'compensating');
+`, o.table.Identifier(), o.table.Index("due_idx"))
+}
+
+// EnsureSchema creates the saga table when it does not exist, for services without migrations and
+// for tests.
+func (o *Orchestrator) EnsureSchema(ctx context.Context) error {
+	if _, err := o.db.Exec(ctx, o.Schema()); err != nil {
+		return fmt.Errorf("saga: failed to create table %s: %w", o.table.Name(), err)
+	}
+
+	return nil
+}
+
+// Register adds a definition, so sagas can be started with its name. Every orchestrator sharing the
+// table must register the same definitions.
+func (o *Orchestrator) Register(def Definition) error {
+	if err := def.validate(); err != nil {
+		return err
+	}
+
+	def.Steps = append([]Step(nil), def.Steps...)
+
+	if _, loaded := o.definitions.LoadOrStore(def.Name, &def); loaded {
+		return fmt.Errorf("%w: %s", ErrDuplicateSaga, def.Name)
+	}
+
+	return nil
+}
+
+func (o *Orchestrator) definition(name string) (*Definition, error) {
+	def, ok := o.definitions.Load(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSaga, name)
+	}
+
+	return def.(*Definition), nil //nolint:forcetypeassert
+}
+
+// Begin stores a saga of the named definition with the JSON encoding of data and runs its first step.
+// id identifies the saga, e.g. the ID of the order it fulfills; starting it again fails with
+// ErrSagaExists. In the transaction of ctx, started with postgres.BeginCtx, the saga is stored only if
+// the transaction commits and the first step runs at the next poll after the commit.
+func (o *Orchestrator) Begin(ctx context.Context, name, id string, data any) error {
+	def, err := o.definition(name)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("saga: failed to encode data: %w", err)
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s (id, name, status, step, data, deadline)
+		VALUES ($1, $2, $3, 0, $4, CASE WHEN $5::float8 > 0 THEN now() + make_interval(secs => $5) END)
+		ON CONFLICT (id) DO NOTHING`, o.table.Identifier())
+
+	conn := postgres.FromCtx(ctx, o.db)
+
+	tag, err := conn.Exec(ctx, query, id, name, StatusRunning, payload, def.Timeout.Seconds())
+	if err != nil {
+		return fmt.Errorf("saga: failed to start %s %s: %w", name, id, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: %s", ErrSagaExists, id)
+	}
+
+	if _, inTx := postgres.TxFromCtx(ctx); !inTx {
+		o.dispatch(ctx, id, 0)
+	}
+
+	return nil
+}
+
+// Resume compensates a failed saga again, e.g. after fixing the cause of the failure.
+func (o *Orchestrator) Resume(ctx context.Context, id string) error {
+	query := fmt.Sprintf(`UPDATE %s SET status = $2, attempts = 0, wake_at = now(), updated_at = now()
+		WHERE id = $1 AND status = $3 RETURNING version`, o.table.Identifier())
+
+	var version int64
+
+	err := o.db.QueryRow(ctx, query, id, StatusCompensating, StatusFailed).Scan(&version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("%w: %s", ErrNotResumable, id)
+	}
+
+	if err != nil {
+		return fmt.Errorf("saga: failed to resume %s: %w", id, err)
+	}
+
+	o.dispatch(ctx, id, version)
+
+	return nil
+}
+
+// Get returns the state of the saga id.
+func (o *Orchestrator) Get(ctx context.Context, id string) (*Instance, error) {
+	query := fmt.Sprintf(`SELECT id, name, status, step, data, attempts, COALESCE(last_error, ''), deadline,
+		created_at, updated_at FROM %s WHERE id = $1`, o.table.Identifier())
+
+	var instance Instance
+
+	err := o.db.QueryRow(ctx, query, id).Scan(&instance.ID, &instance.Saga, &instance.Status, &instance.Step,
+		&instance.Data, &instance.Attempts, &instance.LastError, &instance.Deadline, &instance.CreatedAt,
+		&instance.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s", ErrSagaNotFound, id)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("saga: failed to get %s: %w", id, err)
+	}
+
+	return &instance, nil
+}
+
+func (o *Orchestrator) Name() string {
+	return "saga-orchestrator"
+}
+
+// Start runs the task queue workers and dispatches due sagas until ctx is cancelled or Stop is called.
+func (o *Orchestrator) Start(ctx context.Context) error {
+	if !o.running.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	o.mu.Lock()
+	o.stop = make(chan struct{})
+	o.stopped = make(chan struct{})
+	stop, stopped := o.stop, o.stopped
+	o.mu.Unlock()
+
+	defer close(stopped)
+
+	if err := o.queue.Start(ctx); err != nil {
+		return fmt.Errorf("saga: failed to start task queue: %w", err)
+	}
+
+	log.Info().Str("source", "gframework").Str("table", o.table.Name()).Msg("Saga orchestrator is starting")
+
+	for {
+		dispatched, err := o.DispatchDue(ctx)
+		if err != nil {
+			log.Error().Str("source", "gframework").Err(err).Str("table", o.table.Name()).Msg("Failed to dispatch sagas")
+		}
+
+		delay := o.pollInterval
+		if err == nil && dispatched == o.batchSize {
+			delay = 0
+		}
+
+		select {
+		case <-stop:
+			return nil
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (o *Orchestrator) Stop() error {
+	if !o.running.CompareAndSwap(true, false) {
+		return nil
+	}
+
+	o.mu.Lock()
+	stop, stopped := o.stop, o.stopped
+	o.mu.Unlock()
+
+	close(stop)
+	<-stopped
+
+	if err := o.queue.Stop(); err != nil {
+		return fmt.Errorf("saga: failed to stop task queue: %w", err)
+	}
+
+	log.Info().Str("source", "gframework").Str("table", o.table.Name()).Msg("Saga orchestrator stopped")
+
+	return nil
+}
+
+// DispatchDue pushes the tasks of up to the batch size of due sagas: retries whose delay passed,
+// sagas started in a transaction and sagas whose step outlived its lease, e.g. because the worker
+// died. It returns the number of sagas dispatched.
+func (o *Orchestrator) DispatchDue(ctx context.Context) (int, error) {
+	query := fmt.Sprintf(`UPDATE %[1]s SET wake_at = now() + make_interval(secs => $1)
+		WHERE id IN (
+			SELECT id FROM %[1]s WHERE status IN ($2, $3) AND wake_at <= now()
+			ORDER BY wake_at LIMIT $4 FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, version`, o.table.Identifier())
+
+	rows, err := o.db.Query(ctx, query, o.lease(o.stepTimeout).Seconds(), StatusRunning, StatusCompensating,
+		o.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("saga: failed to claim due sagas: %w", err)
+	}
+
+	tasks, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (taskqueue.Task, error) {
+		var ref taskRef
+
+		if err := row.Scan(&ref.ID, &ref.Version); err != nil {
+			return taskqueue.Task{}, err //nolint:exhaustruct
+		}
+
+		return ref.task()
+	})
+	if err != nil {
+		return 0, fmt.Errorf("saga: failed to read due sagas: %w", err)
+	}
+
+	// A lost push is dispatched again once the lease set above expires.
+	if err := o.queue.Push(ctx, tasks...); err != nil {
+		return 0, fmt.Errorf("saga: failed to push due sagas: %w", err)
+	}
+
+	return len(tasks), nil
+}
+
+func (o *Orchestrator) lease(timeout time.Duration) time.Duration {
+	return timeout + leaseMargin
+}
+
+// taskRef is the payload of a step task: the saga and the version the task is valid for.
+type taskRef struct {
+	ID      string `json:"id"`
+	Version int64  `json:"version"`
+}
+
+func (r taskRef) task() (taskqueue.Task, error) {
+	payload, err := json.Marshal(r)
+	if err != nil {
+		return taskqueue.Task{}, fmt.Errorf("saga: failed to encode task: %w", err) //nolint:exhaustruct
+	}
+
+	return taskqueue.Task{ID: fmt.Sprintf("%s:%d", r.ID, r.Version), Payload: payload}, nil
+}
+
+// dispatch pushes the next task of a saga. A failed push is logged; the saga is dispatched again at a
+// later poll.
+func (o *Orchestrator) dispatch(ctx context.Context, id string, version int64) {
+	task, err := taskRef{ID: id, Version: version}.task()
+	if err == nil {
+		err = o.queue.Push(ctx, task)
+	}
+
+	if err != nil {
+		logutil.FromContext(ctx).Warn().
+			Str("source", "gframework").
+			Err(err).
+			Str("saga_id", id).
+			Msg("Failed to dispatch the saga, it is retried at the next poll")
+	}
+}
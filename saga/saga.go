@@ -0,0 +1,221 @@
+// Package saga orchestrates multi-step distributed transactions: a saga runs its steps in order and,
+// when a step fails for good or the saga times out, runs the compensations of the completed steps in
+// reverse order to undo their effects.
+//
+// The state of every saga instance is persisted in a Postgres table and its steps are executed by the
+// workers of a taskqueue.Queue, so a saga survives restarts and resumes on any replica:
+//
+//	orchestrator, err := saga.New(pg, redisClient, "sagas:orders")
+//
+//	err = orchestrator.Register(saga.Definition{
+//	    Name:    "order-fulfillment",
+//	    Timeout: 30 * time.Minute,
+//	    Steps: []saga.Step{
+//	        {Name: "reserve-stock", Action: reserveStock, Compensate: releaseStock},
+//	        {Name: "charge-payment", Action: charge, Compensate: refund},
+//	        {Name: "ship", Action: ship, Timeout: 2 * time.Minute},
+//	    },
+//	})
+//
+//	run.Add(orchestrator)
+//
+//	err = postgres.BeginCtx(ctx, pg, func(ctx context.Context) error {
+//	    if err := orders.Create(ctx, order); err != nil {
+//	        return err
+//	    }
+//
+//	    return orchestrator.Begin(ctx, "order-fulfillment", order.ID, order)
+//	})
+//
+// Steps read the saga data with Execution.Decode and persist changes with Execution.Update, e.g. the ID
+// of a payment the compensation needs to refund:
+//
+//	func charge(ctx context.Context, exec *saga.Execution) error {
+//	    var order Order
+//	    if err := exec.Decode(&order); err != nil {
+//	        return err
+//	    }
+//
+//	    paymentID, err := payments.Charge(ctx, order.CustomerID, order.Total)
+//	    if errors.Is(err, payments.ErrDeclined) {
+//	        return fmt.Errorf("%w: %w", saga.ErrAbort, err)
+//	    }
+//	    ...
+//	    order.PaymentID = paymentID
+//
+//	    return exec.Update(order)
+//	}
+//
+// A failed step is retried after a delay up to its attempts; errors wrapping ErrAbort compensate
+// right away. Delivery is at least once: a step whose worker dies, or that outlives its lease, runs
+// again, so actions and compensations must be idempotent, e.g. keyed by Execution.ID. A saga whose
+// compensation fails for good stops in StatusFailed until Resume retries it.
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type Status string
+
+const (
+	// StatusRunning is a saga running its steps.
+	StatusRunning Status = "running"
+	// StatusCompensating is a saga undoing its completed steps after a failure or a timeout.
+	StatusCompensating Status = "compensating"
+	// StatusCompleted is a saga whose steps all succeeded.
+	StatusCompleted Status = "completed"
+	// StatusCompensated is a saga whose completed steps were all compensated.
+	StatusCompensated Status = "compensated"
+	// StatusFailed is a saga whose compensation failed for good; Resume retries it.
+	StatusFailed Status = "failed"
+)
+
+// active reports whether a saga with the status has steps or compensations left to run.
+func (s Status) active() bool {
+	return s == StatusRunning || s == StatusCompensating
+}
+
+var (
+	ErrNilDB            = errors.New("saga: database is nil")
+	ErrNilClient        = errors.New("saga: redis client is nil")
+	ErrInvalidTableName = errors.New("saga: invalid table name")
+	ErrInvalidSaga      = errors.New("saga: invalid definition")
+	ErrDuplicateSaga    = errors.New("saga: definition is already registered")
+	ErrUnknownSaga      = errors.New("saga: definition is not registered")
+	ErrSagaExists       = errors.New("saga: instance already exists")
+	ErrSagaNotFound     = errors.New("saga: instance not found")
+	ErrNotResumable     = errors.New("saga: instance is not failed")
+	// ErrAbort, wrapped by the error of an action, compensates the saga without retrying the step, e.g.
+	// for a declined payment.
+	ErrAbort = errors.New("saga: aborted")
+	// ErrTimeout is the cause recorded for a saga that exceeded the timeout of its definition.
+	ErrTimeout = errors.New("saga: timed out")
+)
+
+// StepFunc runs the action or the compensation of a step.
+type StepFunc func(ctx context.Context, exec *Execution) error
+
+type Step struct {
+	// Name identifies the step in the persisted state, so keep it stable across deployments.
+	Name string
+	// Action performs the step.
+	Action StepFunc
+	// Compensate undoes the action after a later step failed. Steps without one are skipped when the
+	// saga compensates.
+	Compensate StepFunc
+	// Timeout bounds one attempt of the action or the compensation. It defaults to the step timeout of
+	// the orchestrator.
+	Timeout time.Duration
+	// MaxAttempts is how often the action, and then the compensation, is attempted before it fails for
+	// good. It defaults to the max attempts of the orchestrator.
+	MaxAttempts int
+}
+
+type Definition struct {
+	// Name identifies the definition in the persisted state, so keep it stable across deployments.
+	Name string
+	// Steps run in order.
+	Steps []Step
+	// Timeout compensates a saga that has not completed this long after it started; a running step is
+	// not interrupted, the saga compensates before its next step. Zero disables the timeout.
+	Timeout time.Duration
+}
+
+func (d *Definition) validate() error {
+	if d.Name == "" {
+		return fmt.Errorf("%w: name is empty", ErrInvalidSaga)
+	}
+
+	if len(d.Steps) == 0 {
+		return fmt.Errorf("%w: %s has no steps", ErrInvalidSaga, d.Name)
+	}
+
+	names := make(map[string]bool, len(d.Steps))
+
+	for i, step := range d.Steps {
+		switch {
+		case step.Name == "":
+			return fmt.Errorf("%w: step %d of %s has no name", ErrInvalidSaga, i, d.Name)
+		case names[step.Name]:
+			return fmt.Errorf("%w: step %s of %s is not unique", ErrInvalidSaga, step.Name, d.Name)
+		case step.Action == nil:
+			return fmt.Errorf("%w: step %s of %s has no action", ErrInvalidSaga, step.Name, d.Name)
+		}
+
+		names[step.Name] = true
+	}
+
+	return nil
+}
+
+// previousCompensable returns the index of the last step before step with a compensation, or -1.
+func (d *Definition) previousCompensable(step int) int {
+	for i := min(step, len(d.Steps)) - 1; i >= 0; i-- {
+		if d.Steps[i].Compensate != nil {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Execution is the saga instance a step runs for.
+type Execution struct {
+	// ID is the ID the saga was started with.
+	ID string
+	// Saga is the name of the definition.
+	Saga string
+	// Step is the name of the running step.
+	Step string
+	// Attempt counts the attempts of the action or the compensation, starting at 1.
+	Attempt int
+	// Compensating is true while the compensation of the step runs.
+	Compensating bool
+	// Cause is the last error recorded for the saga: the error of the previous attempt, or the error
+	// that made the saga compensate.
+	Cause string
+
+	data json.RawMessage
+}
+
+// Decode unmarshals the saga data into v.
+func (e *Execution) Decode(v any) error {
+	if err := json.Unmarshal(e.data, v); err != nil {
+		return fmt.Errorf("saga: failed to decode data: %w", err)
+	}
+
+	return nil
+}
+
+// Update replaces the saga data with v. The data is persisted once the step succeeds.
+func (e *Execution) Update(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("saga: failed to encode data: %w", err)
+	}
+
+	e.data = data
+
+	return nil
+}
+
+// Instance is the persisted state of a saga.
+type Instance struct {
+	ID     string
+	Saga   string
+	Status Status
+	// Step is the index of the step running or being compensated.
+	Step      int
+	Data      json.RawMessage
+	Attempts  int
+	LastError string
+	// Deadline is when the saga times out, or nil without a timeout.
+	Deadline  *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
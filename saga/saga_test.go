@@ -0,0 +1,396 @@
+//nolint:exhaustruct
+package saga_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/postgres"
+	"github.com/andyle182810/gframework/saga"
+	"github.com/andyle182810/gframework/taskqueue"
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	errOutOfStock = errors.New("out of stock")
+	errFlaky      = errors.New("flaky")
+)
+
+type order struct {
+	ID        string `json:"id"`
+	Reserved  bool   `json:"reserved"`
+	PaymentID string `json:"paymentId"`
+}
+
+// recorder records the steps a saga ran, in order.
+type recorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *recorder) step(name string, fn func(exec *saga.Execution) error) saga.StepFunc {
+	return func(_ context.Context, exec *saga.Execution) error {
+		r.mu.Lock()
+		r.calls = append(r.calls, name)
+		r.mu.Unlock()
+
+		if fn == nil {
+			return nil
+		}
+
+		return fn(exec)
+	}
+}
+
+func (r *recorder) Calls() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]string(nil), r.calls...)
+}
+
+func noop(_ context.Context, _ *saga.Execution) error { return nil }
+
+func TestNew_Validates(t *testing.T) {
+	t.Parallel()
+
+	client := testutil.NewFakeValkey(t)
+
+	_, err := saga.New(nil, client, "sagas")
+	require.ErrorIs(t, err, saga.ErrNilDB)
+
+	_, err = saga.New(&postgres.Postgres{}, nil, "sagas")
+	require.ErrorIs(t, err, saga.ErrNilClient)
+
+	_, err = saga.New(&postgres.Postgres{}, client, "")
+	require.ErrorIs(t, err, taskqueue.ErrEmptyQueueKey)
+
+	_, err = saga.New(&postgres.Postgres{}, client, "sagas", saga.WithTable("sagas; DROP TABLE orders"))
+	require.ErrorIs(t, err, saga.ErrInvalidTableName)
+
+	orchestrator, err := saga.New(&postgres.Postgres{}, client, "sagas", saga.WithTable("orders.sagas"))
+	require.NoError(t, err)
+	require.Contains(t, orchestrator.Schema(), `CREATE TABLE IF NOT EXISTS "orders"."sagas"`)
+	require.Contains(t, orchestrator.Schema(), `"sagas_due_idx"`)
+}
+
+func TestRegister(t *testing.T) {
+	t.Parallel()
+
+	orchestrator, err := saga.New(&postgres.Postgres{}, testutil.NewFakeValkey(t), "sagas")
+	require.NoError(t, err)
+
+	invalid := []saga.Definition{
+		{Steps: []saga.Step{{Name: "a", Action: noop}}},
+		{Name: "empty"},
+		{Name: "unnamed", Steps: []saga.Step{{Action: noop}}},
+		{Name: "no-action", Steps: []saga.Step{{Name: "a"}}},
+		{Name: "duplicate-step", Steps: []saga.Step{{Name: "a", Action: noop}, {Name: "a", Action: noop}}},
+	}
+
+	for _, def := range invalid {
+		require.ErrorIs(t, orchestrator.Register(def), saga.ErrInvalidSaga, def.Name)
+	}
+
+	def := saga.Definition{Name: "fulfillment", Steps: []saga.Step{{Name: "a", Action: noop}}}
+
+	require.NoError(t, orchestrator.Register(def))
+	require.ErrorIs(t, orchestrator.Register(def), saga.ErrDuplicateSaga)
+	require.ErrorIs(t, orchestrator.Begin(t.Context(), "refund", "order-1", nil), saga.ErrUnknownSaga)
+}
+
+func setupPostgres(t *testing.T) *postgres.Postgres {
+	t.Helper()
+
+	container := testutil.SetupPostgresContainer(t)
+
+	pg, err := postgres.New(&postgres.Config{
+		URL:                   container.ConnectionString(),
+		MaxConnection:         5,
+		MinConnection:         1,
+		MaxConnectionIdleTime: time.Minute,
+		HealthCheckPeriod:     10 * time.Second,
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(pg.Close)
+
+	return pg
+}
+
+// startOrchestrator runs an orchestrator with its own table, so the subtests do not claim each
+// other's sagas.
+func startOrchestrator(t *testing.T, pg *postgres.Postgres, table string, defs ...saga.Definition) *saga.Orchestrator {
+	t.Helper()
+
+	orchestrator, err := saga.New(pg, testutil.NewFakeValkey(t), "sagas:"+table,
+		saga.WithTable(table),
+		saga.WithPollInterval(20*time.Millisecond),
+		saga.WithRetryDelay(10*time.Millisecond),
+		saga.WithQueueOptions(taskqueue.WithPollInterval(10*time.Millisecond)),
+	)
+	require.NoError(t, err)
+	require.NoError(t, orchestrator.EnsureSchema(t.Context()))
+
+	for _, def := range defs {
+		require.NoError(t, orchestrator.Register(def))
+	}
+
+	go func() {
+		_ = orchestrator.Start(context.WithoutCancel(t.Context()))
+	}()
+
+	t.Cleanup(func() {
+		require.NoError(t, orchestrator.Stop())
+	})
+
+	return orchestrator
+}
+
+func waitForStatus(t *testing.T, orchestrator *saga.Orchestrator, id string, status saga.Status) *saga.Instance {
+	t.Helper()
+
+	var instance *saga.Instance
+
+	require.Eventually(t, func() bool {
+		current, err := orchestrator.Get(t.Context(), id)
+		if err != nil {
+			return false
+		}
+
+		instance = current
+
+		return current.Status == status
+	}, 10*time.Second, 20*time.Millisecond)
+
+	return instance
+}
+
+func TestOrchestrator(t *testing.T) {
+	t.Parallel()
+
+	pg := setupPostgres(t)
+
+	t.Run("completes the steps in order", func(t *testing.T) {
+		t.Parallel()
+
+		rec := &recorder{}
+		orchestrator := startOrchestrator(t, pg, "sagas_completed", saga.Definition{
+			Name: "fulfillment",
+			Steps: []saga.Step{
+				{Name: "reserve", Action: rec.step("reserve", func(exec *saga.Execution) error {
+					var o order
+					if err := exec.Decode(&o); err != nil {
+						return err
+					}
+
+					o.Reserved = true
+
+					return exec.Update(o)
+				})},
+				{Name: "charge", Action: rec.step("charge", func(exec *saga.Execution) error {
+					var o order
+					if err := exec.Decode(&o); err != nil {
+						return err
+					}
+
+					if !o.Reserved {
+						return errOutOfStock
+					}
+
+					o.PaymentID = "pay-" + exec.ID
+
+					return exec.Update(o)
+				})},
+				{Name: "ship", Action: rec.step("ship", nil)},
+			},
+		})
+
+		require.NoError(t, orchestrator.Begin(t.Context(), "fulfillment", "order-1", order{ID: "order-1"}))
+		require.ErrorIs(t, orchestrator.Begin(t.Context(), "fulfillment", "order-1", nil), saga.ErrSagaExists)
+
+		instance := waitForStatus(t, orchestrator, "order-1", saga.StatusCompleted)
+		require.JSONEq(t, `{"id":"order-1","reserved":true,"paymentId":"pay-order-1"}`, string(instance.Data))
+		require.Empty(t, instance.LastError)
+		require.Nil(t, instance.Deadline)
+		require.Equal(t, []string{"reserve", "charge", "ship"}, rec.Calls())
+
+		_, err := orchestrator.Get(t.Context(), "order-2")
+		require.ErrorIs(t, err, saga.ErrSagaNotFound)
+	})
+
+	t.Run("compensates the completed steps in reverse order on abort", func(t *testing.T) {
+		t.Parallel()
+
+		rec := &recorder{}
+		orchestrator := startOrchestrator(t, pg, "sagas_aborted", saga.Definition{
+			Name: "fulfillment",
+			Steps: []saga.Step{
+				{Name: "reserve", Action: rec.step("reserve", nil), Compensate: rec.step("release", nil)},
+				{Name: "notify", Action: rec.step("notify", nil)},
+				{Name: "charge", Action: rec.step("charge", nil), Compensate: rec.step("refund", nil)},
+				{Name: "ship", Action: rec.step("ship", func(_ *saga.Execution) error {
+					return errors.Join(saga.ErrAbort, errOutOfStock)
+				}), Compensate: rec.step("recall", nil)},
+			},
+		})
+
+		require.NoError(t, orchestrator.Begin(t.Context(), "fulfillment", "order-1", order{ID: "order-1"}))
+
+		instance := waitForStatus(t, orchestrator, "order-1", saga.StatusCompensated)
+		require.Contains(t, instance.LastError, errOutOfStock.Error())
+		require.Equal(t, []string{"reserve", "notify", "charge", "ship", "refund", "release"}, rec.Calls())
+	})
+
+	t.Run("retries a failed step", func(t *testing.T) {
+		t.Parallel()
+
+		var attempts atomic.Int32
+
+		rec := &recorder{}
+		orchestrator := startOrchestrator(t, pg, "sagas_retried", saga.Definition{
+			Name: "fulfillment",
+			Steps: []saga.Step{
+				{Name: "charge", MaxAttempts: 3, Action: rec.step("charge", func(exec *saga.Execution) error {
+					attempts.Add(1)
+
+					if exec.Attempt < 3 {
+						return errFlaky
+					}
+
+					return nil
+				})},
+			},
+		})
+
+		require.NoError(t, orchestrator.Begin(t.Context(), "fulfillment", "order-1", nil))
+
+		waitForStatus(t, orchestrator, "order-1", saga.StatusCompleted)
+		require.Equal(t, int32(3), attempts.Load())
+	})
+
+	t.Run("compensates when a step exhausts its attempts", func(t *testing.T) {
+		t.Parallel()
+
+		rec := &recorder{}
+		orchestrator := startOrchestrator(t, pg, "sagas_exhausted", saga.Definition{
+			Name: "fulfillment",
+			Steps: []saga.Step{
+				{Name: "reserve", Action: rec.step("reserve", nil), Compensate: rec.step("release", nil)},
+				{Name: "charge", MaxAttempts: 2, Action: rec.step("charge", func(_ *saga.Execution) error {
+					return errFlaky
+				})},
+			},
+		})
+
+		require.NoError(t, orchestrator.Begin(t.Context(), "fulfillment", "order-1", nil))
+
+		instance := waitForStatus(t, orchestrator, "order-1", saga.StatusCompensated)
+		require.Contains(t, instance.LastError, errFlaky.Error())
+		require.Equal(t, []string{"reserve", "charge", "charge", "release"}, rec.Calls())
+	})
+
+	t.Run("compensates a saga that timed out", func(t *testing.T) {
+		t.Parallel()
+
+		rec := &recorder{}
+		orchestrator := startOrchestrator(t, pg, "sagas_timed_out", saga.Definition{
+			Name:    "fulfillment",
+			Timeout: 200 * time.Millisecond,
+			Steps: []saga.Step{
+				{Name: "reserve", Action: rec.step("reserve", func(_ *saga.Execution) error {
+					time.Sleep(300 * time.Millisecond)
+
+					return nil
+				}), Compensate: rec.step("release", func(exec *saga.Execution) error {
+					if exec.Cause != saga.ErrTimeout.Error() {
+						return errFlaky
+					}
+
+					return nil
+				})},
+				{Name: "ship", Action: rec.step("ship", nil)},
+			},
+		})
+
+		require.NoError(t, orchestrator.Begin(t.Context(), "fulfillment", "order-1", nil))
+
+		instance := waitForStatus(t, orchestrator, "order-1", saga.StatusCompensated)
+		require.Equal(t, saga.ErrTimeout.Error(), instance.LastError)
+		require.NotNil(t, instance.Deadline)
+		require.Equal(t, []string{"reserve", "release"}, rec.Calls())
+	})
+
+	t.Run("resumes a saga whose compensation failed", func(t *testing.T) {
+		t.Parallel()
+
+		var broken atomic.Bool
+
+		broken.Store(true)
+
+		rec := &recorder{}
+		orchestrator := startOrchestrator(t, pg, "sagas_resumed", saga.Definition{
+			Name: "fulfillment",
+			Steps: []saga.Step{
+				{Name: "reserve", MaxAttempts: 1, Action: rec.step("reserve", nil), Compensate: rec.step("release",
+					func(_ *saga.Execution) error {
+						if broken.Load() {
+							return errFlaky
+						}
+
+						return nil
+					})},
+				{Name: "charge", Action: rec.step("charge", func(_ *saga.Execution) error {
+					return saga.ErrAbort
+				})},
+			},
+		})
+
+		require.NoError(t, orchestrator.Begin(t.Context(), "fulfillment", "order-1", nil))
+
+		instance := waitForStatus(t, orchestrator, "order-1", saga.StatusFailed)
+		require.Contains(t, instance.LastError, errFlaky.Error())
+		require.ErrorIs(t, orchestrator.Resume(t.Context(), "order-2"), saga.ErrNotResumable)
+
+		broken.Store(false)
+		require.NoError(t, orchestrator.Resume(t.Context(), "order-1"))
+
+		waitForStatus(t, orchestrator, "order-1", saga.StatusCompensated)
+		require.Equal(t, []string{"reserve", "charge", "release", "release"}, rec.Calls())
+	})
+
+	t.Run("starts a saga begun in a transaction once it commits", func(t *testing.T) {
+		t.Parallel()
+
+		rec := &recorder{}
+		orchestrator := startOrchestrator(t, pg, "sagas_transaction", saga.Definition{
+			Name:  "fulfillment",
+			Steps: []saga.Step{{Name: "reserve", Action: rec.step("reserve", nil)}},
+		})
+
+		errRollback := errors.New("rollback")
+
+		err := postgres.BeginCtx(t.Context(), pg, func(ctx context.Context) error {
+			require.NoError(t, orchestrator.Begin(ctx, "fulfillment", "order-1", nil))
+
+			return errRollback
+		})
+		require.ErrorIs(t, err, errRollback)
+
+		_, err = orchestrator.Get(t.Context(), "order-1")
+		require.ErrorIs(t, err, saga.ErrSagaNotFound)
+
+		err = postgres.BeginCtx(t.Context(), pg, func(ctx context.Context) error {
+			return orchestrator.Begin(ctx, "fulfillment", "order-1", nil)
+		})
+		require.NoError(t, err)
+
+		waitForStatus(t, orchestrator, "order-1", saga.StatusCompleted)
+		require.Equal(t, []string{"reserve"}, rec.Calls())
+	})
+}
@@ -0,0 +1,52 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+)
+
+var ErrInvalidPayload = errors.New("search: payload is not a valid document")
+
+// IndexHandler returns a message handler that decodes each JSON payload into T and indexes it into
+// index under the id returned by id. When deleted is set and reports true, the document is deleted
+// instead, so tombstone messages keep the index in sync; deleting a missing document succeeds.
+//
+// Its type is assignable to redissub.MessageHandler, kafkasub.MessageHandler,
+// natsjetstream.MessageHandler and amqpsub.MessageHandler. Payloads that cannot be decoded and failed
+// requests return an error, so the subscriber's retry and DLQ settings apply. Indexing under a stable
+// id makes redeliveries harmless.
+func IndexHandler[T any](
+	c *Client,
+	index string,
+	id func(doc T) string,
+	deleted func(doc T) bool,
+	opts ...WriteOption,
+) func(ctx context.Context, payload message.Payload) error {
+	return func(ctx context.Context, payload message.Payload) error {
+		var doc T
+		if err := json.Unmarshal(payload, &doc); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidPayload, err)
+		}
+
+		docID := id(doc)
+		if docID == "" {
+			return fmt.Errorf("%w: empty id", ErrInvalidPayload)
+		}
+
+		if deleted != nil && deleted(doc) {
+			if err := c.Delete(ctx, index, docID, opts...); err != nil && !errors.Is(err, ErrNotFound) {
+				return err
+			}
+
+			return nil
+		}
+
+		_, err := c.Index(ctx, index, docID, doc, opts...)
+
+		return err
+	}
+}
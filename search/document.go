@@ -0,0 +1,267 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var ErrBulkFailed = errors.New("search: bulk request has failed items")
+
+// Refresh controls when the changes of a write become visible to search.
+type Refresh string
+
+const (
+	// RefreshWaitFor waits until the next periodic refresh makes the changes visible.
+	RefreshWaitFor Refresh = "wait_for"
+	// RefreshImmediate refreshes the affected shards right away. It is costly; prefer it in tests.
+	RefreshImmediate Refresh = "true"
+)
+
+type writeOptions struct {
+	refresh Refresh
+	routing string
+}
+
+type WriteOption func(*writeOptions)
+
+// WithRefresh makes the write visible to search before the call returns. Writes are otherwise visible
+// after the next refresh, one second by default.
+func WithRefresh(refresh Refresh) WriteOption {
+	return func(o *writeOptions) {
+		o.refresh = refresh
+	}
+}
+
+// WithRouting routes the document to the shard of routing instead of the one of its id.
+func WithRouting(routing string) WriteOption {
+	return func(o *writeOptions) {
+		o.routing = routing
+	}
+}
+
+func newWriteQuery(opts []WriteOption) url.Values {
+	options := writeOptions{refresh: "", routing: ""}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	query := url.Values{}
+
+	if options.refresh != "" {
+		query.Set("refresh", string(options.refresh))
+	}
+
+	if options.routing != "" {
+		query.Set("routing", options.routing)
+	}
+
+	return query
+}
+
+type writeResponse struct {
+	ID      string `json:"_id"`
+	Version int64  `json:"_version"`
+	Result  string `json:"result"`
+}
+
+// Index creates or replaces the document with id in index and returns its id. An empty id lets the
+// cluster generate one.
+func (c *Client) Index(ctx context.Context, index, id string, doc any, opts ...WriteOption) (string, error) {
+	if index == "" {
+		return "", ErrInvalidIndex
+	}
+
+	method, path := http.MethodPut, indexPath(index, "_doc", id)
+	if id == "" {
+		method, path = http.MethodPost, indexPath(index, "_doc")
+	}
+
+	var resp writeResponse
+	if err := c.do(ctx, method, path, newWriteQuery(opts), doc, &resp); err != nil {
+		return "", fmt.Errorf("search: failed to index document into %s: %w", index, err)
+	}
+
+	return resp.ID, nil
+}
+
+// Delete deletes the document with id from index. It returns an error matching ErrNotFound when the
+// document does not exist.
+func (c *Client) Delete(ctx context.Context, index, id string, opts ...WriteOption) error {
+	if index == "" {
+		return ErrInvalidIndex
+	}
+
+	if err := c.do(ctx, http.MethodDelete, indexPath(index, "_doc", id), newWriteQuery(opts), nil, nil); err != nil {
+		return fmt.Errorf("search: failed to delete document %s from %s: %w", id, index, err)
+	}
+
+	return nil
+}
+
+type getResponse[T any] struct {
+	Source T `json:"_source"`
+}
+
+// Get returns the document with id from index, decoded into T. It returns an error matching
+// ErrNotFound when the document does not exist.
+func Get[T any](ctx context.Context, c *Client, index, id string) (T, error) {
+	var resp getResponse[T]
+
+	if index == "" {
+		return resp.Source, ErrInvalidIndex
+	}
+
+	if err := c.do(ctx, http.MethodGet, indexPath(index, "_doc", id), nil, nil, &resp); err != nil {
+		return resp.Source, fmt.Errorf("search: failed to get document %s from %s: %w", id, index, err)
+	}
+
+	return resp.Source, nil
+}
+
+// BulkAction is the operation of a bulk item.
+type BulkAction string
+
+const (
+	BulkIndex  BulkAction = "index"
+	BulkCreate BulkAction = "create"
+	BulkDelete BulkAction = "delete"
+)
+
+// BulkOperation is one item of a bulk request. Document is ignored for BulkDelete.
+type BulkOperation struct {
+	Action   BulkAction
+	Index    string
+	ID       string
+	Document any
+}
+
+type BulkItemResult struct {
+	Action BulkAction
+	Index  string
+	ID     string
+	Status int
+	Error  *ErrorCause
+}
+
+type BulkResult struct {
+	Took  time.Duration
+	Items []BulkItemResult
+}
+
+// Failed returns the items the cluster rejected, in request order.
+func (r *BulkResult) Failed() []BulkItemResult {
+	var failed []BulkItemResult
+
+	for _, item := range r.Items {
+		if item.Error != nil {
+			failed = append(failed, item)
+		}
+	}
+
+	return failed
+}
+
+type bulkMeta struct {
+	Index string `json:"_index,omitempty"`
+	ID    string `json:"_id,omitempty"`
+}
+
+type bulkResponseItem struct {
+	Index  string      `json:"_index"`
+	ID     string      `json:"_id"`
+	Status int         `json:"status"`
+	Error  *ErrorCause `json:"error,omitempty"`
+}
+
+type bulkResponse struct {
+	Took   int64                             `json:"took"`
+	Errors bool                              `json:"errors"`
+	Items  []map[BulkAction]bulkResponseItem `json:"items"`
+}
+
+// ndjson is a newline-delimited JSON request body, as expected by the bulk API.
+type ndjson struct {
+	buf bytes.Buffer
+}
+
+func (n *ndjson) add(v any) error {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	n.buf.Write(encoded)
+	n.buf.WriteByte('\n')
+
+	return nil
+}
+
+// Bulk sends operations in one request. The request succeeds even when some items are rejected; it
+// then returns the result together with an error wrapping ErrBulkFailed, and the rejected items are
+// listed by BulkResult.Failed.
+func (c *Client) Bulk(ctx context.Context, operations []BulkOperation, opts ...WriteOption) (*BulkResult, error) {
+	if len(operations) == 0 {
+		return &BulkResult{Took: 0, Items: nil}, nil
+	}
+
+	body := &ndjson{buf: bytes.Buffer{}}
+
+	for i, op := range operations {
+		if op.Index == "" {
+			return nil, fmt.Errorf("%w: operation %d", ErrInvalidIndex, i)
+		}
+
+		if err := body.add(map[BulkAction]bulkMeta{op.Action: {Index: op.Index, ID: op.ID}}); err != nil {
+			return nil, fmt.Errorf("search: failed to encode bulk operation %d: %w", i, err)
+		}
+
+		if op.Action == BulkDelete {
+			continue
+		}
+
+		if err := body.add(op.Document); err != nil {
+			return nil, fmt.Errorf("search: failed to encode bulk document %d: %w", i, err)
+		}
+	}
+
+	var resp bulkResponse
+	if err := c.do(ctx, http.MethodPost, "/_bulk", newWriteQuery(opts), body, &resp); err != nil {
+		return nil, fmt.Errorf("search: bulk request failed: %w", err)
+	}
+
+	result := &BulkResult{Took: time.Duration(resp.Took) * time.Millisecond, Items: make([]BulkItemResult, 0, len(resp.Items))}
+
+	for _, item := range resp.Items {
+		for action, res := range item {
+			result.Items = append(result.Items, BulkItemResult{
+				Action: action,
+				Index:  res.Index,
+				ID:     res.ID,
+				Status: res.Status,
+				Error:  res.Error,
+			})
+		}
+	}
+
+	if resp.Errors {
+		failed := result.Failed()
+		reasons := make([]string, 0, min(len(failed), 3))
+
+		for _, item := range failed[:min(len(failed), 3)] {
+			reasons = append(reasons, item.Error.Type+": "+item.Error.Reason)
+		}
+
+		return result, fmt.Errorf("%w: %d of %d items, e.g. %s",
+			ErrBulkFailed, len(failed), len(result.Items), strings.Join(reasons, "; "))
+	}
+
+	return result, nil
+}
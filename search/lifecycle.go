@@ -0,0 +1,263 @@
+package search
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	initialIndexSuffix    = "-000001"
+	indexTemplatePriority = 100
+	errTypeAlreadyExists  = "resource_already_exists_exception"
+	policyNameSuffix      = "-policy"
+	indexPatternSuffix    = "-*"
+)
+
+var (
+	ErrInvalidAlias     = errors.New("search: alias is required")
+	ErrInvalidLifecycle = errors.New("search: lifecycle policy needs at least one rollover condition")
+)
+
+// LifecyclePolicy rolls the write index over to a new one once any of the rollover conditions is met,
+// and deletes the old indices after DeleteAfter.
+type LifecyclePolicy struct {
+	RolloverMaxAge time.Duration
+	// RolloverMaxSize is the size of the largest primary shard, e.g. "50gb".
+	RolloverMaxSize string
+	RolloverMaxDocs int64
+	// DeleteAfter deletes an index that long after its rollover on Elasticsearch, and after its creation
+	// on OpenSearch. Indices are kept forever when it is zero.
+	DeleteAfter time.Duration
+}
+
+// IndexSpec describes an index written through the alias Alias.
+type IndexSpec struct {
+	Alias    string
+	Settings map[string]any
+	Mappings map[string]any
+	// Lifecycle, when set, is attached to the indices as an ILM policy on Elasticsearch and an ISM
+	// policy on OpenSearch, named "<alias>-policy".
+	Lifecycle *LifecyclePolicy
+}
+
+// Bootstrap makes sure the indices of spec exist: it creates or updates the lifecycle policy and an
+// index template matching "<alias>-*", then creates the first index "<alias>-000001" as the write
+// index of the alias unless the alias already exists.
+//
+// It is idempotent, so services can run it on every start; changes to the settings and mappings only
+// apply to indices created afterwards, e.g. by the next rollover.
+func (c *Client) Bootstrap(ctx context.Context, spec IndexSpec) error {
+	if spec.Alias == "" {
+		return ErrInvalidAlias
+	}
+
+	settings := make(map[string]any, len(spec.Settings)+2)
+	maps.Copy(settings, spec.Settings)
+
+	if spec.Lifecycle != nil {
+		if err := c.putLifecyclePolicy(ctx, spec.Alias, spec.Lifecycle); err != nil {
+			return err
+		}
+
+		if c.flavor == FlavorOpenSearch {
+			settings["plugins.index_state_management.rollover_alias"] = spec.Alias
+		} else {
+			settings["index.lifecycle.name"] = spec.Alias + policyNameSuffix
+			settings["index.lifecycle.rollover_alias"] = spec.Alias
+		}
+	}
+
+	template := map[string]any{"settings": settings}
+	if spec.Mappings != nil {
+		template["mappings"] = spec.Mappings
+	}
+
+	err := c.do(ctx, http.MethodPut, "/_index_template/"+url.PathEscape(spec.Alias), nil, map[string]any{
+		"index_patterns": []string{spec.Alias + indexPatternSuffix},
+		"priority":       indexTemplatePriority,
+		"template":       template,
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("search: failed to put index template %s: %w", spec.Alias, err)
+	}
+
+	return c.createWriteIndex(ctx, spec.Alias)
+}
+
+func (c *Client) createWriteIndex(ctx context.Context, alias string) error {
+	exists, err := c.exists(ctx, "/_alias/"+url.PathEscape(alias))
+	if err != nil {
+		return fmt.Errorf("search: failed to check alias %s: %w", alias, err)
+	}
+
+	if exists {
+		return nil
+	}
+
+	err = c.do(ctx, http.MethodPut, indexPath(alias+initialIndexSuffix), nil, map[string]any{
+		"aliases": map[string]any{alias: map[string]any{"is_write_index": true}},
+	}, nil)
+
+	// Another instance bootstrapping concurrently may have created it first.
+	var searchErr *Error
+	if errors.As(err, &searchErr) && searchErr.Type == errTypeAlreadyExists {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("search: failed to create index %s: %w", alias+initialIndexSuffix, err)
+	}
+
+	return nil
+}
+
+func (c *Client) putLifecyclePolicy(ctx context.Context, alias string, policy *LifecyclePolicy) error {
+	if policy.RolloverMaxAge <= 0 && policy.RolloverMaxSize == "" && policy.RolloverMaxDocs <= 0 {
+		return ErrInvalidLifecycle
+	}
+
+	name := alias + policyNameSuffix
+
+	var err error
+	if c.flavor == FlavorOpenSearch {
+		err = c.putISMPolicy(ctx, name, alias, policy)
+	} else {
+		err = c.do(ctx, http.MethodPut, "/_ilm/policy/"+url.PathEscape(name), nil, ilmPolicy(policy), nil)
+	}
+
+	if err != nil {
+		return fmt.Errorf("search: failed to put lifecycle policy %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func ilmPolicy(policy *LifecyclePolicy) map[string]any {
+	rollover := map[string]any{}
+
+	if policy.RolloverMaxAge > 0 {
+		rollover["max_age"] = timeUnits(policy.RolloverMaxAge)
+	}
+
+	if policy.RolloverMaxSize != "" {
+		rollover["max_primary_shard_size"] = policy.RolloverMaxSize
+	}
+
+	if policy.RolloverMaxDocs > 0 {
+		rollover["max_docs"] = policy.RolloverMaxDocs
+	}
+
+	phases := map[string]any{
+		"hot": map[string]any{"min_age": "0ms", "actions": map[string]any{"rollover": rollover}},
+	}
+
+	if policy.DeleteAfter > 0 {
+		phases["delete"] = map[string]any{
+			"min_age": timeUnits(policy.DeleteAfter),
+			"actions": map[string]any{"delete": map[string]any{}},
+		}
+	}
+
+	return map[string]any{"policy": map[string]any{"phases": phases}}
+}
+
+type ismPolicyResponse struct {
+	SeqNo       int64 `json:"_seq_no"`
+	PrimaryTerm int64 `json:"_primary_term"`
+}
+
+// putISMPolicy creates the policy, or updates it with optimistic concurrency control when it exists,
+// as OpenSearch rejects a plain overwrite.
+func (c *Client) putISMPolicy(ctx context.Context, name, alias string, policy *LifecyclePolicy) error {
+	path := "/_plugins/_ism/policies/" + url.PathEscape(name)
+
+	var (
+		current ismPolicyResponse
+		query   url.Values
+	)
+
+	err := c.do(ctx, http.MethodGet, path, nil, nil, &current)
+
+	switch {
+	case err == nil:
+		query = url.Values{}
+		query.Set("if_seq_no", strconv.FormatInt(current.SeqNo, 10))
+		query.Set("if_primary_term", strconv.FormatInt(current.PrimaryTerm, 10))
+	case !errors.Is(err, ErrNotFound):
+		return err
+	}
+
+	return c.do(ctx, http.MethodPut, path, query, ismPolicy(alias, policy), nil)
+}
+
+func ismPolicy(alias string, policy *LifecyclePolicy) map[string]any {
+	rollover := map[string]any{}
+
+	if policy.RolloverMaxAge > 0 {
+		rollover["min_index_age"] = timeUnits(policy.RolloverMaxAge)
+	}
+
+	if policy.RolloverMaxSize != "" {
+		rollover["min_primary_shard_size"] = policy.RolloverMaxSize
+	}
+
+	if policy.RolloverMaxDocs > 0 {
+		rollover["min_doc_count"] = policy.RolloverMaxDocs
+	}
+
+	hot := map[string]any{
+		"name":        "hot",
+		"actions":     []any{map[string]any{"rollover": rollover}},
+		"transitions": []any{},
+	}
+	states := []any{hot}
+
+	if policy.DeleteAfter > 0 {
+		hot["transitions"] = []any{map[string]any{
+			"state_name": "delete",
+			"conditions": map[string]any{"min_index_age": timeUnits(policy.DeleteAfter)},
+		}}
+		states = append(states, map[string]any{
+			"name":        "delete",
+			"actions":     []any{map[string]any{"delete": map[string]any{}}},
+			"transitions": []any{},
+		})
+	}
+
+	return map[string]any{"policy": map[string]any{
+		"description":   "Rollover and retention of " + alias,
+		"default_state": "hot",
+		"states":        states,
+		"ism_template": []any{map[string]any{
+			"index_patterns": []string{alias + indexPatternSuffix},
+			"priority":       indexTemplatePriority,
+		}},
+	}}
+}
+
+// timeUnits formats d in the largest whole time unit understood by the cluster, e.g. "30d".
+func timeUnits(d time.Duration) string {
+	units := []struct {
+		unit   time.Duration
+		suffix string
+	}{
+		{24 * time.Hour, "d"},
+		{time.Hour, "h"},
+		{time.Minute, "m"},
+		{time.Second, "s"},
+	}
+
+	for _, u := range units {
+		if d%u.unit == 0 {
+			return strconv.FormatInt(int64(d/u.unit), 10) + u.suffix
+		}
+	}
+
+	return strconv.FormatInt(d.Milliseconds(), 10) + "ms"
+}
@@ -0,0 +1,120 @@
+//nolint:exhaustruct
+package search_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/search"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Bootstrap_Elasticsearch(t *testing.T) {
+	t.Parallel()
+
+	cluster, client := newFakeCluster(t, search.FlavorElasticsearch)
+	cluster.respond("HEAD /_alias/orders", http.StatusNotFound, "")
+
+	err := client.Bootstrap(t.Context(), search.IndexSpec{
+		Alias:    "orders",
+		Settings: map[string]any{"number_of_shards": 2},
+		Mappings: map[string]any{"properties": map[string]any{"status": map[string]any{"type": "keyword"}}},
+		Lifecycle: &search.LifecyclePolicy{
+			RolloverMaxAge:  7 * 24 * time.Hour,
+			RolloverMaxSize: "50gb",
+			DeleteAfter:     90 * 24 * time.Hour,
+		},
+	})
+	require.NoError(t, err)
+
+	requests := cluster.recorded()
+	require.Len(t, requests, 4)
+
+	require.Equal(t, "PUT /_ilm/policy/orders-policy", requests[0].Method+" "+requests[0].Path)
+	require.JSONEq(t, `{"policy":{"phases":{
+		"hot":{"min_age":"0ms","actions":{"rollover":{"max_age":"7d","max_primary_shard_size":"50gb"}}},
+		"delete":{"min_age":"90d","actions":{"delete":{}}}
+	}}}`, requests[0].Body)
+
+	require.Equal(t, "PUT /_index_template/orders", requests[1].Method+" "+requests[1].Path)
+	require.JSONEq(t, `{
+		"index_patterns":["orders-*"],
+		"priority":100,
+		"template":{
+			"settings":{
+				"number_of_shards":2,
+				"index.lifecycle.name":"orders-policy",
+				"index.lifecycle.rollover_alias":"orders"
+			},
+			"mappings":{"properties":{"status":{"type":"keyword"}}}
+		}
+	}`, requests[1].Body)
+
+	require.Equal(t, "HEAD /_alias/orders", requests[2].Method+" "+requests[2].Path)
+	require.Equal(t, "PUT /orders-000001", requests[3].Method+" "+requests[3].Path)
+	require.JSONEq(t, `{"aliases":{"orders":{"is_write_index":true}}}`, requests[3].Body)
+}
+
+func TestClient_Bootstrap_OpenSearch(t *testing.T) {
+	t.Parallel()
+
+	cluster, client := newFakeCluster(t, search.FlavorOpenSearch)
+	cluster.respond("GET /_plugins/_ism/policies/orders-policy", http.StatusOK,
+		`{"_id":"orders-policy","_seq_no":7,"_primary_term":2,"policy":{}}`)
+
+	err := client.Bootstrap(t.Context(), search.IndexSpec{
+		Alias:     "orders",
+		Lifecycle: &search.LifecyclePolicy{RolloverMaxDocs: 1000000, DeleteAfter: 36 * time.Hour},
+	})
+	require.NoError(t, err)
+
+	requests := cluster.recorded()
+	require.Len(t, requests, 4, "the alias exists, so no index is created")
+
+	require.Equal(t, "PUT /_plugins/_ism/policies/orders-policy", requests[1].Method+" "+requests[1].Path)
+	require.Equal(t, "if_primary_term=2&if_seq_no=7", requests[1].Query)
+	require.JSONEq(t, `{"policy":{
+		"description":"Rollover and retention of orders",
+		"default_state":"hot",
+		"states":[
+			{"name":"hot","actions":[{"rollover":{"min_doc_count":1000000}}],
+				"transitions":[{"state_name":"delete","conditions":{"min_index_age":"36h"}}]},
+			{"name":"delete","actions":[{"delete":{}}],"transitions":[]}
+		],
+		"ism_template":[{"index_patterns":["orders-*"],"priority":100}]
+	}}`, requests[1].Body)
+
+	require.JSONEq(t, `{
+		"index_patterns":["orders-*"],
+		"priority":100,
+		"template":{"settings":{"plugins.index_state_management.rollover_alias":"orders"}}
+	}`, requests[2].Body)
+	require.Equal(t, "HEAD /_alias/orders", requests[3].Method+" "+requests[3].Path)
+}
+
+func TestClient_Bootstrap_Validation(t *testing.T) {
+	t.Parallel()
+
+	cluster, client := newFakeCluster(t, search.FlavorElasticsearch)
+
+	require.ErrorIs(t, client.Bootstrap(t.Context(), search.IndexSpec{}), search.ErrInvalidAlias)
+
+	err := client.Bootstrap(t.Context(), search.IndexSpec{
+		Alias:     "orders",
+		Lifecycle: &search.LifecyclePolicy{DeleteAfter: time.Hour},
+	})
+	require.ErrorIs(t, err, search.ErrInvalidLifecycle)
+	require.Empty(t, cluster.recorded())
+}
+
+func TestClient_Bootstrap_ConcurrentIndexCreation(t *testing.T) {
+	t.Parallel()
+
+	cluster, client := newFakeCluster(t, search.FlavorElasticsearch)
+	cluster.respond("HEAD /_alias/orders", http.StatusNotFound, "")
+	cluster.respond("PUT /orders-000001", http.StatusBadRequest,
+		`{"error":{"type":"resource_already_exists_exception","reason":"index [orders-000001] already exists"}}`)
+
+	require.NoError(t, client.Bootstrap(t.Context(), search.IndexSpec{Alias: "orders"}))
+}
@@ -0,0 +1,197 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Query is a query DSL clause. The helpers below build the common ones; any other clause can be
+// written as a Query literal, e.g. Query{"exists": map[string]any{"field": "deleted_at"}}.
+type Query map[string]any
+
+func MatchAll() Query {
+	return Query{"match_all": map[string]any{}}
+}
+
+// Match runs a full-text query against field.
+func Match(field string, text any) Query {
+	return Query{"match": map[string]any{field: text}}
+}
+
+// Term matches documents whose field equals value exactly, e.g. on a keyword field.
+func Term(field string, value any) Query {
+	return Query{"term": map[string]any{field: value}}
+}
+
+// Terms matches documents whose field equals any of values.
+func Terms(field string, values ...any) Query {
+	return Query{"terms": map[string]any{field: values}}
+}
+
+// RangeBounds holds the bounds of a Range query; nil bounds are left open.
+type RangeBounds struct {
+	Gt  any `json:"gt,omitempty"`
+	Gte any `json:"gte,omitempty"`
+	Lt  any `json:"lt,omitempty"`
+	Lte any `json:"lte,omitempty"`
+}
+
+func Range(field string, bounds RangeBounds) Query {
+	return Query{"range": map[string]any{field: bounds}}
+}
+
+// BoolQuery combines clauses. Filter clauses do not contribute to the score and are cached, so prefer
+// them over Must for exact matches.
+type BoolQuery struct {
+	Must    []Query `json:"must,omitempty"`
+	Filter  []Query `json:"filter,omitempty"`
+	Should  []Query `json:"should,omitempty"`
+	MustNot []Query `json:"must_not,omitempty"`
+}
+
+func Bool(query BoolQuery) Query {
+	return Query{"bool": query}
+}
+
+// SortField sorts hits by Field, ascending unless Desc is set.
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+func (s SortField) MarshalJSON() ([]byte, error) {
+	order := "asc"
+	if s.Desc {
+		order = "desc"
+	}
+
+	return json.Marshal(map[string]any{s.Field: map[string]string{"order": order}})
+}
+
+type SearchRequest struct {
+	Query Query       `json:"query,omitempty"`
+	From  int         `json:"from,omitempty"`
+	Size  int         `json:"size,omitempty"`
+	Sort  []SortField `json:"sort,omitempty"`
+	// SearchAfter continues after the sort values of the last hit of the previous page, see
+	// Hit.Sort. It scales better than From for deep pagination.
+	SearchAfter []any `json:"search_after,omitempty"`
+	// Aggregations are returned raw by SearchResult.Aggregations, as their shape depends on the request.
+	Aggregations map[string]any `json:"aggs,omitempty"`
+	// TrackTotalHits counts all matches instead of stopping at 10000.
+	TrackTotalHits bool `json:"track_total_hits,omitempty"`
+}
+
+type Hit[T any] struct {
+	Index  string
+	ID     string
+	Score  float64
+	Source T
+	Sort   []any
+}
+
+type SearchResult[T any] struct {
+	Took time.Duration
+	// Total is the number of matches, a lower bound when TotalIsLowerBound is set.
+	Total             int64
+	TotalIsLowerBound bool
+	Hits              []Hit[T]
+	Aggregations      map[string]json.RawMessage
+}
+
+// Sources returns the documents of the hits.
+func (r *SearchResult[T]) Sources() []T {
+	sources := make([]T, 0, len(r.Hits))
+
+	for _, hit := range r.Hits {
+		sources = append(sources, hit.Source)
+	}
+
+	return sources
+}
+
+type searchResponse[T any] struct {
+	Took int64 `json:"took"`
+	Hits struct {
+		Total struct {
+			Value    int64  `json:"value"`
+			Relation string `json:"relation"`
+		} `json:"total"`
+		Hits []struct {
+			Index  string   `json:"_index"`
+			ID     string   `json:"_id"`
+			Score  *float64 `json:"_score"`
+			Source T        `json:"_source"`
+			Sort   []any    `json:"sort"`
+		} `json:"hits"`
+	} `json:"hits"`
+	Aggregations map[string]json.RawMessage `json:"aggregations"`
+}
+
+// Search runs req against index, which may also be an alias or a comma-separated list, and decodes
+// the documents of the hits into T.
+func Search[T any](ctx context.Context, c *Client, index string, req *SearchRequest) (*SearchResult[T], error) {
+	if index == "" {
+		return nil, ErrInvalidIndex
+	}
+
+	if req == nil {
+		req = &SearchRequest{} //nolint:exhaustruct
+	}
+
+	var resp searchResponse[T]
+	if err := c.do(ctx, http.MethodPost, indexPath(index, "_search"), nil, req, &resp); err != nil {
+		return nil, fmt.Errorf("search: failed to search %s: %w", index, err)
+	}
+
+	result := &SearchResult[T]{
+		Took:              time.Duration(resp.Took) * time.Millisecond,
+		Total:             resp.Hits.Total.Value,
+		TotalIsLowerBound: resp.Hits.Total.Relation == "gte",
+		Hits:              make([]Hit[T], 0, len(resp.Hits.Hits)),
+		Aggregations:      resp.Aggregations,
+	}
+
+	for _, hit := range resp.Hits.Hits {
+		var score float64
+		if hit.Score != nil {
+			score = *hit.Score
+		}
+
+		result.Hits = append(result.Hits, Hit[T]{
+			Index:  hit.Index,
+			ID:     hit.ID,
+			Score:  score,
+			Source: hit.Source,
+			Sort:   hit.Sort,
+		})
+	}
+
+	return result, nil
+}
+
+type countResponse struct {
+	Count int64 `json:"count"`
+}
+
+// Count returns the number of documents of index matching query, or all of them when it is nil.
+func (c *Client) Count(ctx context.Context, index string, query Query) (int64, error) {
+	if index == "" {
+		return 0, ErrInvalidIndex
+	}
+
+	var body any
+	if query != nil {
+		body = map[string]any{"query": query}
+	}
+
+	var resp countResponse
+	if err := c.do(ctx, http.MethodPost, indexPath(index, "_count"), nil, body, &resp); err != nil {
+		return 0, fmt.Errorf("search: failed to count %s: %w", index, err)
+	}
+
+	return resp.Count, nil
+}
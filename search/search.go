@@ -0,0 +1,320 @@
+// Package search provides an Elasticsearch and OpenSearch client wrapper for side-indexing: typed
+// document, bulk and query helpers, index lifecycle bootstrap, health checks and a consumer adapter
+// that indexes documents published on a topic.
+//
+// Basic usage:
+//
+//	client, err := search.New(&search.Config{
+//	    URL:      "http://localhost:9200",
+//	    Username: "elastic",
+//	    Password: "secret",
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//
+//	err = client.Bootstrap(ctx, search.IndexSpec{
+//	    Alias:     "orders",
+//	    Mappings:  map[string]any{"properties": map[string]any{"status": map[string]any{"type": "keyword"}}},
+//	    Lifecycle: &search.LifecyclePolicy{RolloverMaxSize: "50gb", DeleteAfter: 90 * 24 * time.Hour},
+//	})
+//
+//	handler := search.IndexHandler(client, "orders", func(o Order) string { return o.ID }, nil)
+//	subscriber, err := redissub.NewSubscriber(redisClient, "search-indexer", "orders", handler)
+//
+//	result, err := search.Search[Order](ctx, client, "orders", &search.SearchRequest{
+//	    Query: search.Bool(search.BoolQuery{Filter: []search.Query{search.Term("status", "paid")}}),
+//	    Size:  20,
+//	})
+//
+// The client talks to the REST API directly, so the same code serves both engines; Config.Flavor only
+// selects the lifecycle policy API, which differs between Elasticsearch ILM and OpenSearch ISM.
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultHTTPTimeout        = 30 * time.Second
+	defaultHealthCheckTimeout = 5 * time.Second
+	initialPingTimeout        = 5 * time.Second
+	maxResponseSize           = 32 << 20
+)
+
+var (
+	ErrConfigNil     = errors.New("search: configuration must not be nil")
+	ErrInvalidURL    = errors.New("search: URL is required")
+	ErrInvalidIndex  = errors.New("search: index is required")
+	ErrNotFound      = errors.New("search: not found")
+	ErrClusterRed    = errors.New("search: cluster health is red")
+	ErrInvalidFlavor = errors.New("search: unknown flavor")
+)
+
+// Flavor is the search engine behind the URL.
+type Flavor string
+
+const (
+	FlavorElasticsearch Flavor = "elasticsearch"
+	FlavorOpenSearch    Flavor = "opensearch"
+)
+
+type Config struct {
+	// URL is the base URL of the cluster, e.g. "https://search.internal:9200".
+	URL string
+	// Flavor defaults to FlavorElasticsearch.
+	Flavor   Flavor
+	Username string
+	Password string
+	// APIKey authenticates with an Elasticsearch API key instead of basic auth.
+	APIKey     string
+	HTTPClient *http.Client
+}
+
+// Client sends requests to an Elasticsearch or OpenSearch cluster. It implements runner.Service.
+type Client struct {
+	baseURL    string
+	flavor     Flavor
+	username   string
+	password   string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// Error is returned when the cluster responds with a non-2xx status.
+type Error struct {
+	StatusCode int
+	Type       string
+	Reason     string
+}
+
+func (e *Error) Error() string {
+	if e.Type == "" {
+		return fmt.Sprintf("search: request failed with status %d: %s", e.StatusCode, e.Reason)
+	}
+
+	return fmt.Sprintf("search: request failed with status %d: %s: %s", e.StatusCode, e.Type, e.Reason)
+}
+
+// Is reports a 404 response as ErrNotFound.
+func (e *Error) Is(target error) bool {
+	return target == ErrNotFound && e.StatusCode == http.StatusNotFound
+}
+
+func New(cfg *Config) (*Client, error) {
+	if cfg == nil {
+		return nil, ErrConfigNil
+	}
+
+	if cfg.URL == "" {
+		return nil, ErrInvalidURL
+	}
+
+	flavor := cfg.Flavor
+	if flavor == "" {
+		flavor = FlavorElasticsearch
+	}
+
+	if flavor != FlavorElasticsearch && flavor != FlavorOpenSearch {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidFlavor, flavor)
+	}
+
+	httpClient := cfg.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: defaultHTTPTimeout} //nolint:exhaustruct
+	}
+
+	return &Client{
+		baseURL:    strings.TrimSuffix(cfg.URL, "/"),
+		flavor:     flavor,
+		username:   cfg.Username,
+		password:   cfg.Password,
+		apiKey:     cfg.APIKey,
+		httpClient: httpClient,
+	}, nil
+}
+
+func (c *Client) Flavor() Flavor {
+	return c.flavor
+}
+
+type clusterHealth struct {
+	Status string `json:"status"`
+}
+
+// HealthCheck fails when the cluster cannot be reached or its health is red. A yellow cluster, e.g.
+// a single node with unassigned replicas, still serves reads and writes.
+func (c *Client) HealthCheck(ctx context.Context) error {
+	healthCtx, cancel := context.WithTimeout(ctx, defaultHealthCheckTimeout)
+	defer cancel()
+
+	var health clusterHealth
+	if err := c.do(healthCtx, http.MethodGet, "/_cluster/health", nil, nil, &health); err != nil {
+		return fmt.Errorf("search health check failed: %w", err)
+	}
+
+	if health.Status == "red" {
+		return ErrClusterRed
+	}
+
+	return nil
+}
+
+func (c *Client) IsHealthy(ctx context.Context) bool {
+	return c.HealthCheck(ctx) == nil
+}
+
+func (c *Client) Start(ctx context.Context) error {
+	pingCtx, cancel := context.WithTimeout(ctx, initialPingTimeout)
+	defer cancel()
+
+	if err := c.do(pingCtx, http.MethodGet, "/", nil, nil, nil); err != nil {
+		return fmt.Errorf("search ping failed: %w", err)
+	}
+
+	return nil
+}
+
+// Stop releases idle connections; in-flight requests are left to finish.
+func (c *Client) Stop() error {
+	c.httpClient.CloseIdleConnections()
+
+	return nil
+}
+
+func (c *Client) Name() string {
+	return "search"
+}
+
+// do sends a request with body encoded as JSON, unless it is already NDJSON, and decodes the response
+// into out when it is not nil.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	var (
+		reader      io.Reader
+		contentType = "application/json"
+	)
+
+	switch b := body.(type) {
+	case nil:
+	case *ndjson:
+		reader = &b.buf
+		contentType = "application/x-ndjson"
+	default:
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("search: failed to encode request body: %w", err)
+		}
+
+		reader = bytes.NewReader(encoded)
+	}
+
+	endpoint := c.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reader)
+	if err != nil {
+		return fmt.Errorf("search: failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	if reader != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	switch {
+	case c.apiKey != "":
+		req.Header.Set("Authorization", "ApiKey "+c.apiKey)
+	case c.username != "":
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("search: %s %s failed: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return fmt.Errorf("search: failed to read response of %s %s: %w", method, path, err)
+	}
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return newError(resp.StatusCode, respBody)
+	}
+
+	if out == nil || len(respBody) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("search: failed to decode response of %s %s: %w", method, path, err)
+	}
+
+	return nil
+}
+
+// exists sends a HEAD request and reports whether the resource exists.
+func (c *Client) exists(ctx context.Context, path string) (bool, error) {
+	err := c.do(ctx, http.MethodHead, path, nil, nil, nil)
+
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, ErrNotFound):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+type errorResponse struct {
+	Error json.RawMessage `json:"error"`
+}
+
+// ErrorCause is the error reported by the cluster for a request or a bulk item.
+type ErrorCause struct {
+	Type   string `json:"type"`
+	Reason string `json:"reason"`
+}
+
+func newError(statusCode int, body []byte) *Error {
+	searchErr := &Error{StatusCode: statusCode, Type: "", Reason: string(body)}
+
+	var resp errorResponse
+	if err := json.Unmarshal(body, &resp); err != nil || len(resp.Error) == 0 {
+		return searchErr
+	}
+
+	// The error is an object on both engines, except for a few endpoints returning a plain string.
+	var cause ErrorCause
+	if err := json.Unmarshal(resp.Error, &cause); err == nil {
+		searchErr.Type, searchErr.Reason = cause.Type, cause.Reason
+	} else {
+		_ = json.Unmarshal(resp.Error, &searchErr.Reason)
+	}
+
+	return searchErr
+}
+
+func indexPath(index string, segments ...string) string {
+	path := "/" + url.PathEscape(index)
+
+	for _, segment := range segments {
+		path += "/" + url.PathEscape(segment)
+	}
+
+	return path
+}
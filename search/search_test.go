@@ -0,0 +1,294 @@
+//nolint:exhaustruct
+package search_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/andyle182810/gframework/search"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedRequest struct {
+	Method      string
+	Path        string
+	Query       string
+	ContentType string
+	Body        string
+}
+
+// fakeCluster answers requests with the responses registered by "METHOD /path" and records them.
+type fakeCluster struct {
+	mu        sync.Mutex
+	requests  []recordedRequest
+	responses map[string]func(w http.ResponseWriter)
+}
+
+func newFakeCluster(t *testing.T, flavor search.Flavor) (*fakeCluster, *search.Client) {
+	t.Helper()
+
+	cluster := &fakeCluster{responses: map[string]func(w http.ResponseWriter){}}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		cluster.mu.Lock()
+		cluster.requests = append(cluster.requests, recordedRequest{
+			Method:      r.Method,
+			Path:        r.URL.Path,
+			Query:       r.URL.RawQuery,
+			ContentType: r.Header.Get("Content-Type"),
+			Body:        string(body),
+		})
+		respond, ok := cluster.responses[r.Method+" "+r.URL.Path]
+		cluster.mu.Unlock()
+
+		if !ok {
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{}`))
+
+			return
+		}
+
+		respond(w)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := search.New(&search.Config{URL: server.URL, Flavor: flavor, Username: "elastic", Password: "secret"})
+	require.NoError(t, err)
+
+	return cluster, client
+}
+
+func (c *fakeCluster) respond(route string, status int, body string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.responses[route] = func(w http.ResponseWriter) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+func (c *fakeCluster) recorded() []recordedRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]recordedRequest(nil), c.requests...)
+}
+
+type order struct {
+	ID      string `json:"id"`
+	Status  string `json:"status"`
+	Deleted bool   `json:"deleted,omitempty"`
+}
+
+func TestNew_ValidatesConfig(t *testing.T) {
+	t.Parallel()
+
+	_, err := search.New(nil)
+	require.ErrorIs(t, err, search.ErrConfigNil)
+
+	_, err = search.New(&search.Config{})
+	require.ErrorIs(t, err, search.ErrInvalidURL)
+
+	_, err = search.New(&search.Config{URL: "http://localhost:9200", Flavor: "solr"})
+	require.ErrorIs(t, err, search.ErrInvalidFlavor)
+
+	client, err := search.New(&search.Config{URL: "http://localhost:9200/"})
+	require.NoError(t, err)
+	require.Equal(t, search.FlavorElasticsearch, client.Flavor())
+	require.Equal(t, "search", client.Name())
+}
+
+func TestClient_Documents(t *testing.T) {
+	t.Parallel()
+
+	cluster, client := newFakeCluster(t, search.FlavorElasticsearch)
+	cluster.respond("PUT /orders/_doc/o-1", http.StatusCreated, `{"_id":"o-1","result":"created"}`)
+	cluster.respond("POST /orders/_doc", http.StatusCreated, `{"_id":"generated","result":"created"}`)
+	cluster.respond("GET /orders/_doc/o-1", http.StatusOK, `{"_id":"o-1","found":true,"_source":{"id":"o-1","status":"paid"}}`)
+	cluster.respond("GET /orders/_doc/missing", http.StatusNotFound, `{"_id":"missing","found":false}`)
+
+	id, err := client.Index(t.Context(), "orders", "o-1", order{ID: "o-1", Status: "paid"},
+		search.WithRefresh(search.RefreshWaitFor))
+	require.NoError(t, err)
+	require.Equal(t, "o-1", id)
+
+	id, err = client.Index(t.Context(), "orders", "", order{Status: "new"})
+	require.NoError(t, err)
+	require.Equal(t, "generated", id)
+
+	doc, err := search.Get[order](t.Context(), client, "orders", "o-1")
+	require.NoError(t, err)
+	require.Equal(t, order{ID: "o-1", Status: "paid"}, doc)
+
+	_, err = search.Get[order](t.Context(), client, "orders", "missing")
+	require.ErrorIs(t, err, search.ErrNotFound)
+
+	require.NoError(t, client.Delete(t.Context(), "orders", "o-1", search.WithRouting("tenant-1")))
+
+	_, err = client.Index(t.Context(), "", "o-1", order{})
+	require.ErrorIs(t, err, search.ErrInvalidIndex)
+
+	requests := cluster.recorded()
+	require.Len(t, requests, 5)
+	require.Equal(t, "refresh=wait_for", requests[0].Query)
+	require.JSONEq(t, `{"id":"o-1","status":"paid"}`, requests[0].Body)
+	require.Equal(t, "application/json", requests[0].ContentType)
+	require.Equal(t, http.MethodDelete, requests[4].Method)
+	require.Equal(t, "routing=tenant-1", requests[4].Query)
+}
+
+func TestClient_ErrorResponse(t *testing.T) {
+	t.Parallel()
+
+	cluster, client := newFakeCluster(t, search.FlavorElasticsearch)
+	cluster.respond("PUT /orders/_doc/o-1", http.StatusBadRequest,
+		`{"error":{"type":"mapper_parsing_exception","reason":"failed to parse field [total]"},"status":400}`)
+
+	_, err := client.Index(t.Context(), "orders", "o-1", order{ID: "o-1"})
+
+	var searchErr *search.Error
+	require.ErrorAs(t, err, &searchErr)
+	require.Equal(t, http.StatusBadRequest, searchErr.StatusCode)
+	require.Equal(t, "mapper_parsing_exception", searchErr.Type)
+	require.Equal(t, "failed to parse field [total]", searchErr.Reason)
+	require.NotErrorIs(t, err, search.ErrNotFound)
+}
+
+func TestClient_Bulk(t *testing.T) {
+	t.Parallel()
+
+	cluster, client := newFakeCluster(t, search.FlavorElasticsearch)
+	cluster.respond("POST /_bulk", http.StatusOK, `{"took":7,"errors":true,"items":[
+		{"index":{"_index":"orders","_id":"o-1","status":201}},
+		{"create":{"_index":"orders","_id":"o-2","status":409,
+			"error":{"type":"version_conflict_engine_exception","reason":"document already exists"}}},
+		{"delete":{"_index":"orders","_id":"o-3","status":404}}
+	]}`)
+
+	result, err := client.Bulk(t.Context(), []search.BulkOperation{
+		{Action: search.BulkIndex, Index: "orders", ID: "o-1", Document: order{ID: "o-1"}},
+		{Action: search.BulkCreate, Index: "orders", ID: "o-2", Document: order{ID: "o-2"}},
+		{Action: search.BulkDelete, Index: "orders", ID: "o-3"},
+	})
+	require.ErrorIs(t, err, search.ErrBulkFailed)
+	require.Len(t, result.Items, 3)
+	require.Equal(t, int64(7), result.Took.Milliseconds())
+
+	failed := result.Failed()
+	require.Len(t, failed, 1)
+	require.Equal(t, "o-2", failed[0].ID)
+	require.Equal(t, search.BulkCreate, failed[0].Action)
+	require.Equal(t, "version_conflict_engine_exception", failed[0].Error.Type)
+
+	request := cluster.recorded()[0]
+	require.Equal(t, "application/x-ndjson", request.ContentType)
+
+	lines := strings.Split(strings.TrimSuffix(request.Body, "\n"), "\n")
+	require.Len(t, lines, 5, "index and create carry a document, delete does not")
+	require.JSONEq(t, `{"index":{"_index":"orders","_id":"o-1"}}`, lines[0])
+	require.JSONEq(t, `{"id":"o-1","status":""}`, lines[1])
+	require.JSONEq(t, `{"delete":{"_index":"orders","_id":"o-3"}}`, lines[4])
+
+	result, err = client.Bulk(t.Context(), nil)
+	require.NoError(t, err)
+	require.Empty(t, result.Items)
+}
+
+func TestSearch(t *testing.T) {
+	t.Parallel()
+
+	cluster, client := newFakeCluster(t, search.FlavorElasticsearch)
+	cluster.respond("POST /orders/_search", http.StatusOK, `{"took":3,"hits":{
+		"total":{"value":10000,"relation":"gte"},
+		"hits":[
+			{"_index":"orders-000001","_id":"o-1","_score":null,"_source":{"id":"o-1","status":"paid"},"sort":[1700000000000,"o-1"]}
+		]},
+		"aggregations":{"by_status":{"buckets":[{"key":"paid","doc_count":10000}]}}}`)
+	cluster.respond("POST /orders/_count", http.StatusOK, `{"count":42}`)
+
+	result, err := search.Search[order](t.Context(), client, "orders", &search.SearchRequest{
+		Query: search.Bool(search.BoolQuery{
+			Must:   []search.Query{search.Match("note", "gift")},
+			Filter: []search.Query{search.Term("status", "paid"), search.Range("total", search.RangeBounds{Gte: 100})},
+		}),
+		Size:         20,
+		Sort:         []search.SortField{{Field: "created_at", Desc: true}, {Field: "id"}},
+		Aggregations: map[string]any{"by_status": map[string]any{"terms": map[string]any{"field": "status"}}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(10000), result.Total)
+	require.True(t, result.TotalIsLowerBound)
+	require.Len(t, result.Hits, 1)
+	require.Equal(t, "orders-000001", result.Hits[0].Index)
+	require.Equal(t, []order{{ID: "o-1", Status: "paid"}}, result.Sources())
+	require.Len(t, result.Hits[0].Sort, 2)
+	require.Contains(t, result.Aggregations, "by_status")
+
+	require.JSONEq(t, `{
+		"query":{"bool":{
+			"must":[{"match":{"note":"gift"}}],
+			"filter":[{"term":{"status":"paid"}},{"range":{"total":{"gte":100}}}]
+		}},
+		"size":20,
+		"sort":[{"created_at":{"order":"desc"}},{"id":{"order":"asc"}}],
+		"aggs":{"by_status":{"terms":{"field":"status"}}}
+	}`, cluster.recorded()[0].Body)
+
+	count, err := client.Count(t.Context(), "orders", search.Terms("status", "paid", "shipped"))
+	require.NoError(t, err)
+	require.Equal(t, int64(42), count)
+	require.JSONEq(t, `{"query":{"terms":{"status":["paid","shipped"]}}}`, cluster.recorded()[1].Body)
+}
+
+func TestClient_HealthCheck(t *testing.T) {
+	t.Parallel()
+
+	cluster, client := newFakeCluster(t, search.FlavorElasticsearch)
+
+	cluster.respond("GET /_cluster/health", http.StatusOK, `{"status":"yellow"}`)
+	require.NoError(t, client.HealthCheck(t.Context()))
+	require.NoError(t, client.Start(t.Context()))
+
+	cluster.respond("GET /_cluster/health", http.StatusOK, `{"status":"red"}`)
+	require.ErrorIs(t, client.HealthCheck(t.Context()), search.ErrClusterRed)
+	require.False(t, client.IsHealthy(t.Context()))
+
+	require.NoError(t, client.Stop())
+}
+
+func TestIndexHandler(t *testing.T) {
+	t.Parallel()
+
+	cluster, client := newFakeCluster(t, search.FlavorElasticsearch)
+	cluster.respond("DELETE /orders/_doc/o-2", http.StatusNotFound, `{"result":"not_found"}`)
+
+	handler := search.IndexHandler(client, "orders",
+		func(o order) string { return o.ID },
+		func(o order) bool { return o.Deleted })
+
+	encode := func(o order) []byte {
+		payload, err := json.Marshal(o)
+		require.NoError(t, err)
+
+		return payload
+	}
+
+	require.NoError(t, handler(t.Context(), encode(order{ID: "o-1", Status: "paid"})))
+	require.NoError(t, handler(t.Context(), encode(order{ID: "o-2", Deleted: true})), "deleting a missing document succeeds")
+	require.ErrorIs(t, handler(t.Context(), []byte("not json")), search.ErrInvalidPayload)
+	require.ErrorIs(t, handler(t.Context(), encode(order{Status: "paid"})), search.ErrInvalidPayload)
+
+	requests := cluster.recorded()
+	require.Len(t, requests, 2)
+	require.Equal(t, http.MethodPut, requests[0].Method)
+	require.Equal(t, "/orders/_doc/o-1", requests[0].Path)
+	require.Equal(t, http.MethodDelete, requests[1].Method)
+}
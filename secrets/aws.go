@@ -0,0 +1,80 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+)
+
+var ErrNilSecretsManagerClient = errors.New("secrets: secrets manager client cannot be nil")
+
+// SecretsManagerAPI is the part of *secretsmanager.Client the provider uses.
+type SecretsManagerAPI interface {
+	GetSecretValue(
+		ctx context.Context,
+		params *secretsmanager.GetSecretValueInput,
+		optFns ...func(*secretsmanager.Options),
+	) (*secretsmanager.GetSecretValueOutput, error)
+}
+
+// AWSSecretsManagerProvider reads secrets from AWS Secrets Manager by name or ARN. The version ID is
+// the Version, so a Watcher notices rotations performed by Secrets Manager; JSON secrets, such as RDS
+// credentials, are parsed into Data.
+type AWSSecretsManagerProvider struct {
+	client       SecretsManagerAPI
+	versionStage string
+}
+
+type AWSOption func(*AWSSecretsManagerProvider)
+
+// WithVersionStage reads another staging label than AWSCURRENT, e.g. AWSPENDING during rotation.
+func WithVersionStage(stage string) AWSOption {
+	return func(p *AWSSecretsManagerProvider) {
+		if stage != "" {
+			p.versionStage = stage
+		}
+	}
+}
+
+func NewAWSSecretsManagerProvider(client SecretsManagerAPI, opts ...AWSOption) (*AWSSecretsManagerProvider, error) {
+	if client == nil {
+		return nil, ErrNilSecretsManagerClient
+	}
+
+	provider := &AWSSecretsManagerProvider{
+		client:       client,
+		versionStage: "AWSCURRENT",
+	}
+
+	for _, opt := range opts {
+		opt(provider)
+	}
+
+	return provider, nil
+}
+
+func (p *AWSSecretsManagerProvider) GetSecret(ctx context.Context, name string) (*Secret, error) {
+	out, err := p.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{ //nolint:exhaustruct
+		SecretId:     aws.String(name),
+		VersionStage: aws.String(p.versionStage),
+	})
+	if err != nil {
+		var notFound *types.ResourceNotFoundException
+		if errors.As(err, &notFound) {
+			return nil, fmt.Errorf("%w: %s", ErrSecretNotFound, name)
+		}
+
+		return nil, fmt.Errorf("secrets: failed to read %s from secrets manager: %w", name, err)
+	}
+
+	value := aws.ToString(out.SecretString)
+	if out.SecretString == nil {
+		value = string(out.SecretBinary)
+	}
+
+	return newSecret(name, value, aws.ToString(out.VersionId)), nil
+}
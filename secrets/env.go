@@ -0,0 +1,86 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type EnvProvider struct {
+	prefix string
+	lookup func(string) (string, bool)
+}
+
+type EnvOption func(*EnvProvider)
+
+// WithEnvPrefix prepends prefix to secret names, e.g. "ORDERS_".
+func WithEnvPrefix(prefix string) EnvOption {
+	return func(p *EnvProvider) {
+		p.prefix = prefix
+	}
+}
+
+// WithEnvLookup replaces os.LookupEnv, e.g. to read from a map in tests.
+func WithEnvLookup(lookup func(string) (string, bool)) EnvOption {
+	return func(p *EnvProvider) {
+		if lookup != nil {
+			p.lookup = lookup
+		}
+	}
+}
+
+// NewEnvProvider reads secrets from environment variables named after the secret.
+func NewEnvProvider(opts ...EnvOption) *EnvProvider {
+	provider := &EnvProvider{
+		prefix: "",
+		lookup: os.LookupEnv,
+	}
+
+	for _, opt := range opts {
+		opt(provider)
+	}
+
+	return provider
+}
+
+func (p *EnvProvider) GetSecret(_ context.Context, name string) (*Secret, error) {
+	value, ok := p.lookup(p.prefix + name)
+	if !ok {
+		return nil, fmt.Errorf("%w: environment variable %s is not set", ErrSecretNotFound, p.prefix+name)
+	}
+
+	return newSecret(name, value, ""), nil
+}
+
+type FileProvider struct {
+	dir string
+}
+
+// NewFileProvider reads secrets from files in dir, e.g. "/run/secrets" for Docker or a Kubernetes
+// secret volume. Names are paths relative to dir; absolute names are read as is. The trailing newline
+// is removed.
+func NewFileProvider(dir string) *FileProvider {
+	return &FileProvider{dir: dir}
+}
+
+func (p *FileProvider) GetSecret(_ context.Context, name string) (*Secret, error) {
+	path := name
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(p.dir, name)
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrSecretNotFound, path)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("secrets: failed to read %s: %w", path, err)
+	}
+
+	return newSecret(name, strings.TrimRight(string(data), "\r\n"), ""), nil
+}
@@ -0,0 +1,129 @@
+// Package secrets reads secrets from environment variables, files, HashiCorp Vault or AWS Secrets
+// Manager behind one Provider interface, and keeps them current with a Watcher.
+//
+// A Watcher renews leased secrets, such as Vault dynamic database credentials, before they expire,
+// fetches a new secret once a lease cannot be renewed any more, and polls secrets without a lease to
+// pick up rotations. Callbacks registered with Watch run whenever a secret changes:
+//
+//	provider, err := secrets.NewVaultProvider(secrets.VaultConfig{Address: "https://vault:8200", Token: token})
+//	watcher, err := secrets.NewWatcher(provider)
+//
+//	watcher.Watch("database/creds/orders", nil)
+//	watcher.Watch("secret/data/keycloak", func(_ context.Context, s *secrets.Secret) error {
+//	    tokenClient.SetClientSecret(s.Data["client_secret"])
+//
+//	    return nil
+//	})
+//
+//	pg, err := postgres.New(&postgres.Config{
+//	    URL:                 "postgres://db:5432/orders",
+//	    CredentialsProvider: watcher.Credentials("database/creds/orders"),
+//	})
+//
+// Credentials satisfies both postgres.CredentialsProvider and valkey.CredentialsProvider, so new
+// connections use the current username and password without a restart. Register the watcher as an
+// infrastructure service before the clients using it.
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	DefaultUsernameKey = "username"
+	DefaultPasswordKey = "password"
+)
+
+var (
+	ErrSecretNotFound = errors.New("secrets: secret not found")
+	ErrNilProvider    = errors.New("secrets: provider cannot be nil")
+	ErrNotWatched     = errors.New("secrets: secret is not watched")
+	ErrNotLoaded      = errors.New("secrets: secret has not been loaded yet")
+)
+
+// Secret is a secret value. Data holds its fields when the value is a JSON object or the backend
+// stores key/value pairs, e.g. {"username": "app", "password": "..."}.
+type Secret struct {
+	Name    string
+	Value   string
+	Data    map[string]string
+	Version string
+	// LeaseID, LeaseDuration and Renewable describe the lease of dynamic secrets; LeaseDuration is
+	// zero for secrets that do not expire.
+	LeaseID       string
+	LeaseDuration time.Duration
+	Renewable     bool
+	FetchedAt     time.Time
+}
+
+// Provider fetches secrets by name. It returns ErrSecretNotFound for unknown secrets.
+type Provider interface {
+	GetSecret(ctx context.Context, name string) (*Secret, error)
+}
+
+// Renewer is implemented by providers whose secrets carry renewable leases. RenewSecret extends the
+// lease and returns the secret with its new lease duration.
+type Renewer interface {
+	RenewSecret(ctx context.Context, secret *Secret) (*Secret, error)
+}
+
+// newSecret builds a Secret from a raw value, filling Data when the value is a JSON object.
+func newSecret(name, value, version string) *Secret {
+	return &Secret{
+		Name:          name,
+		Value:         value,
+		Data:          parseData(value),
+		Version:       version,
+		LeaseID:       "",
+		LeaseDuration: 0,
+		Renewable:     false,
+		FetchedAt:     time.Now(),
+	}
+}
+
+func parseData(value string) map[string]string {
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(value), &fields); err != nil {
+		return nil
+	}
+
+	return stringifyData(fields)
+}
+
+func stringifyData(fields map[string]any) map[string]string {
+	data := make(map[string]string, len(fields))
+
+	for key, field := range fields {
+		switch v := field.(type) {
+		case string:
+			data[key] = v
+		case nil:
+			data[key] = ""
+		default:
+			encoded, err := json.Marshal(v)
+			if err != nil {
+				continue
+			}
+
+			data[key] = string(encoded)
+		}
+	}
+
+	return data
+}
+
+// changed reports whether next differs from previous in a way callbacks care about.
+func changed(previous, next *Secret) bool {
+	if previous == nil {
+		return true
+	}
+
+	if previous.Version != "" || next.Version != "" {
+		return previous.Version != next.Version
+	}
+
+	return previous.Value != next.Value || previous.LeaseID != next.LeaseID
+}
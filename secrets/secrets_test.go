@@ -0,0 +1,123 @@
+//nolint:exhaustruct
+package secrets_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/andyle182810/gframework/secrets"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvProvider(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{
+		"ORDERS_API_KEY": "key-1",
+		"ORDERS_DB":      `{"username": "app", "password": "s3cret", "port": 5432}`,
+	}
+
+	provider := secrets.NewEnvProvider(
+		secrets.WithEnvPrefix("ORDERS_"),
+		secrets.WithEnvLookup(func(key string) (string, bool) {
+			value, ok := env[key]
+
+			return value, ok
+		}),
+	)
+
+	secret, err := provider.GetSecret(t.Context(), "API_KEY")
+	require.NoError(t, err)
+	require.Equal(t, "key-1", secret.Value)
+	require.Nil(t, secret.Data)
+
+	secret, err = provider.GetSecret(t.Context(), "DB")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"username": "app", "password": "s3cret", "port": "5432"}, secret.Data)
+
+	_, err = provider.GetSecret(t.Context(), "MISSING")
+	require.ErrorIs(t, err, secrets.ErrSecretNotFound)
+}
+
+func TestFileProvider(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "db_password"), []byte("s3cret\n"), 0o600))
+
+	provider := secrets.NewFileProvider(dir)
+
+	secret, err := provider.GetSecret(t.Context(), "db_password")
+	require.NoError(t, err)
+	require.Equal(t, "s3cret", secret.Value)
+
+	secret, err = provider.GetSecret(t.Context(), filepath.Join(dir, "db_password"))
+	require.NoError(t, err)
+	require.Equal(t, "s3cret", secret.Value)
+
+	_, err = provider.GetSecret(t.Context(), "missing")
+	require.ErrorIs(t, err, secrets.ErrSecretNotFound)
+}
+
+type fakeSecretsManager struct {
+	values map[string]*secretsmanager.GetSecretValueOutput
+}
+
+func (f *fakeSecretsManager) GetSecretValue(
+	_ context.Context,
+	params *secretsmanager.GetSecretValueInput,
+	_ ...func(*secretsmanager.Options),
+) (*secretsmanager.GetSecretValueOutput, error) {
+	out, ok := f.values[aws.ToString(params.SecretId)+"@"+aws.ToString(params.VersionStage)]
+	if !ok {
+		return nil, &types.ResourceNotFoundException{Message: aws.String("not found")}
+	}
+
+	return out, nil
+}
+
+func TestAWSSecretsManagerProvider(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeSecretsManager{values: map[string]*secretsmanager.GetSecretValueOutput{
+		"rds/orders@AWSCURRENT": {
+			SecretString: aws.String(`{"username": "app", "password": "current"}`),
+			VersionId:    aws.String("v2"),
+		},
+		"rds/orders@AWSPENDING": {
+			SecretString: aws.String(`{"username": "app", "password": "pending"}`),
+			VersionId:    aws.String("v3"),
+		},
+		"signing-key@AWSCURRENT": {SecretBinary: []byte("binary-key"), VersionId: aws.String("v1")},
+	}}
+
+	provider, err := secrets.NewAWSSecretsManagerProvider(client)
+	require.NoError(t, err)
+
+	secret, err := provider.GetSecret(t.Context(), "rds/orders")
+	require.NoError(t, err)
+	require.Equal(t, "v2", secret.Version)
+	require.Equal(t, "current", secret.Data["password"])
+
+	secret, err = provider.GetSecret(t.Context(), "signing-key")
+	require.NoError(t, err)
+	require.Equal(t, "binary-key", secret.Value)
+
+	_, err = provider.GetSecret(t.Context(), "missing")
+	require.ErrorIs(t, err, secrets.ErrSecretNotFound)
+
+	pending, err := secrets.NewAWSSecretsManagerProvider(client, secrets.WithVersionStage("AWSPENDING"))
+	require.NoError(t, err)
+
+	secret, err = pending.GetSecret(t.Context(), "rds/orders")
+	require.NoError(t, err)
+	require.Equal(t, "pending", secret.Data["password"])
+
+	_, err = secrets.NewAWSSecretsManagerProvider(nil)
+	require.ErrorIs(t, err, secrets.ErrNilSecretsManagerClient)
+}
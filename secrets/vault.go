@@ -0,0 +1,138 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andyle182810/gframework/httpclient"
+)
+
+const (
+	headerVaultToken     = "X-Vault-Token" //nolint:gosec
+	headerVaultNamespace = "X-Vault-Namespace"
+)
+
+var ErrVaultConfig = errors.New("secrets: vault address and token are required")
+
+type VaultConfig struct {
+	Address string // e.g. "https://vault.internal:8200"
+	Token   string
+	// Namespace selects a Vault Enterprise namespace.
+	Namespace  string
+	Timeout    time.Duration
+	HTTPClient *http.Client
+}
+
+// VaultProvider reads secrets from HashiCorp Vault over its HTTP API. Names are API paths without the
+// "v1/" prefix, e.g. "secret/data/orders" for a KV v2 secret or "database/creds/orders" for dynamic
+// database credentials. Fields of the secret are in Data, and Value is Data encoded as JSON; the KV v2
+// version is the Version. Leases of dynamic secrets are renewed by RenewSecret.
+type VaultProvider struct {
+	client *httpclient.Client
+}
+
+type vaultResponse struct {
+	LeaseID       string         `json:"lease_id"`
+	LeaseDuration int            `json:"lease_duration"`
+	Renewable     bool           `json:"renewable"`
+	Data          map[string]any `json:"data"`
+}
+
+type vaultRenewRequest struct {
+	LeaseID   string `json:"lease_id"`
+	Increment int    `json:"increment"`
+}
+
+func NewVaultProvider(cfg VaultConfig) (*VaultProvider, error) {
+	if cfg.Address == "" || cfg.Token == "" {
+		return nil, ErrVaultConfig
+	}
+
+	headers := map[string]string{headerVaultToken: cfg.Token}
+	if cfg.Namespace != "" {
+		headers[headerVaultNamespace] = cfg.Namespace
+	}
+
+	opts := []httpclient.Option{httpclient.WithDefaultHeaders(headers)}
+
+	if cfg.HTTPClient != nil {
+		opts = append(opts, httpclient.WithHTTPClient(cfg.HTTPClient))
+	} else if cfg.Timeout > 0 {
+		opts = append(opts, httpclient.WithTimeout(cfg.Timeout))
+	}
+
+	return &VaultProvider{
+		client: httpclient.New(strings.TrimSuffix(cfg.Address, "/")+"/v1", opts...),
+	}, nil
+}
+
+func (p *VaultProvider) GetSecret(ctx context.Context, name string) (*Secret, error) {
+	var resp vaultResponse
+
+	if err := p.client.Get(ctx, "/"+strings.TrimPrefix(name, "/"), &resp); err != nil {
+		if svcErr, ok := httpclient.IsServiceError(err); ok && svcErr.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("%w: %s", ErrSecretNotFound, name)
+		}
+
+		return nil, fmt.Errorf("secrets: failed to read %s from vault: %w", name, err)
+	}
+
+	fields, version := unwrapKVv2(resp.Data)
+
+	value, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("secrets: failed to encode %s: %w", name, err)
+	}
+
+	secret := newSecret(name, string(value), version)
+	secret.Data = stringifyData(fields)
+	secret.LeaseID = resp.LeaseID
+	secret.LeaseDuration = time.Duration(resp.LeaseDuration) * time.Second
+	secret.Renewable = resp.Renewable
+
+	return secret, nil
+}
+
+func (p *VaultProvider) RenewSecret(ctx context.Context, secret *Secret) (*Secret, error) {
+	if secret.LeaseID == "" {
+		return nil, fmt.Errorf("secrets: %s has no lease to renew", secret.Name)
+	}
+
+	var resp vaultResponse
+
+	req := vaultRenewRequest{LeaseID: secret.LeaseID, Increment: int(secret.LeaseDuration.Seconds())}
+	if err := p.client.Put(ctx, "/sys/leases/renew", req, &resp); err != nil {
+		return nil, fmt.Errorf("secrets: failed to renew lease of %s: %w", secret.Name, err)
+	}
+
+	renewed := *secret
+	renewed.LeaseDuration = time.Duration(resp.LeaseDuration) * time.Second
+	renewed.Renewable = resp.Renewable
+	renewed.FetchedAt = time.Now()
+
+	return &renewed, nil
+}
+
+// unwrapKVv2 returns the fields and version of a KV v2 read, which nests them under data and
+// metadata, or data unchanged for other engines.
+func unwrapKVv2(data map[string]any) (map[string]any, string) {
+	fields, hasData := data["data"].(map[string]any)
+	metadata, hasMetadata := data["metadata"].(map[string]any)
+
+	if !hasData || !hasMetadata {
+		return data, ""
+	}
+
+	version := ""
+	if number, ok := metadata["version"].(float64); ok {
+		version = strconv.FormatInt(int64(number), 10)
+	}
+
+	return fields, version
+}
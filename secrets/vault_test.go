@@ -0,0 +1,93 @@
+//nolint:exhaustruct
+package secrets_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/secrets"
+	"github.com/stretchr/testify/require"
+)
+
+func newVaultServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/secret/data/keycloak", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "vault-token", r.Header.Get("X-Vault-Token"))
+		require.Equal(t, "team-a", r.Header.Get("X-Vault-Namespace"))
+
+		_, _ = w.Write([]byte(`{"data": {"data": {"client_secret": "kc-secret"}, "metadata": {"version": 4}}}`))
+	})
+	mux.HandleFunc("GET /v1/database/creds/orders", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{
+			"lease_id": "database/creds/orders/abc",
+			"lease_duration": 3600,
+			"renewable": true,
+			"data": {"username": "v-orders-abc", "password": "generated"}
+		}`))
+	})
+	mux.HandleFunc("PUT /v1/sys/leases/renew", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, "database/creds/orders/abc", body["lease_id"])
+		require.InDelta(t, 3600, body["increment"], 0)
+
+		_, _ = w.Write([]byte(`{"lease_id": "database/creds/orders/abc", "lease_duration": 1800, "renewable": true}`))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errors": []}`))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestVaultProvider(t *testing.T) {
+	t.Parallel()
+
+	server := newVaultServer(t)
+
+	provider, err := secrets.NewVaultProvider(secrets.VaultConfig{
+		Address:   server.URL + "/",
+		Token:     "vault-token",
+		Namespace: "team-a",
+	})
+	require.NoError(t, err)
+
+	secret, err := provider.GetSecret(t.Context(), "secret/data/keycloak")
+	require.NoError(t, err)
+	require.Equal(t, "4", secret.Version)
+	require.Equal(t, map[string]string{"client_secret": "kc-secret"}, secret.Data)
+	require.JSONEq(t, `{"client_secret": "kc-secret"}`, secret.Value)
+	require.Zero(t, secret.LeaseDuration)
+
+	secret, err = provider.GetSecret(t.Context(), "database/creds/orders")
+	require.NoError(t, err)
+	require.Equal(t, "v-orders-abc", secret.Data["username"])
+	require.Equal(t, "database/creds/orders/abc", secret.LeaseID)
+	require.Equal(t, time.Hour, secret.LeaseDuration)
+	require.True(t, secret.Renewable)
+
+	renewed, err := provider.RenewSecret(t.Context(), secret)
+	require.NoError(t, err)
+	require.Equal(t, 30*time.Minute, renewed.LeaseDuration)
+	require.Equal(t, secret.Data, renewed.Data)
+
+	_, err = provider.GetSecret(t.Context(), "secret/data/missing")
+	require.ErrorIs(t, err, secrets.ErrSecretNotFound)
+}
+
+func TestNewVaultProvider_RequiresAddressAndToken(t *testing.T) {
+	t.Parallel()
+
+	_, err := secrets.NewVaultProvider(secrets.VaultConfig{Address: "http://vault:8200"})
+
+	require.ErrorIs(t, err, secrets.ErrVaultConfig)
+}
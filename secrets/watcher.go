@@ -0,0 +1,343 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultPollInterval = 5 * time.Minute
+	defaultRetryDelay   = 10 * time.Second
+)
+
+// ChangeFunc is called with the new secret whenever a watched secret changes, including when it is
+// first loaded. Errors are logged; the new secret is kept.
+type ChangeFunc func(ctx context.Context, secret *Secret) error
+
+type WatcherOption func(*Watcher)
+
+// WithPollInterval sets how often secrets without a lease are fetched again to detect rotations. It
+// defaults to five minutes.
+func WithPollInterval(d time.Duration) WatcherOption {
+	return func(w *Watcher) {
+		if d > 0 {
+			w.pollInterval = d
+		}
+	}
+}
+
+// WithRetryDelay sets the delay before retrying a failed renewal or fetch. It defaults to ten seconds.
+func WithRetryDelay(d time.Duration) WatcherOption {
+	return func(w *Watcher) {
+		if d > 0 {
+			w.retryDelay = d
+		}
+	}
+}
+
+// Watcher keeps secrets current and notifies callbacks when they change. Leased secrets are renewed
+// after two thirds of their lease; when a lease cannot be renewed, or has reached its maximum TTL, a
+// new secret is fetched instead. Secrets without a lease are fetched every poll interval.
+type Watcher struct {
+	provider     Provider
+	pollInterval time.Duration
+	retryDelay   time.Duration
+	mu           sync.RWMutex
+	entries      map[string]*watchedSecret
+	running      atomic.Bool
+	ready        chan struct{}
+	readyOnce    sync.Once
+	stop         chan struct{}
+	stopped      chan struct{}
+}
+
+type watchedSecret struct {
+	name      string
+	callbacks []ChangeFunc
+	secret    *Secret
+	failed    bool
+	// rotate is set once the lease cannot be extended further, so the next action fetches a new secret.
+	rotate bool
+}
+
+func NewWatcher(provider Provider, opts ...WatcherOption) (*Watcher, error) {
+	if provider == nil {
+		return nil, ErrNilProvider
+	}
+
+	//nolint:exhaustruct
+	watcher := &Watcher{
+		provider:     provider,
+		pollInterval: defaultPollInterval,
+		retryDelay:   defaultRetryDelay,
+		entries:      make(map[string]*watchedSecret),
+		ready:        make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(watcher)
+	}
+
+	return watcher, nil
+}
+
+// Watch keeps the named secret current and calls onChange, when not nil, whenever it changes. Call it
+// before Start.
+func (w *Watcher) Watch(name string, onChange ChangeFunc) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	entry, ok := w.entries[name]
+	if !ok {
+		//nolint:exhaustruct
+		entry = &watchedSecret{name: name}
+		w.entries[name] = entry
+	}
+
+	if onChange != nil {
+		entry.callbacks = append(entry.callbacks, onChange)
+	}
+}
+
+// Current returns the latest version of a watched secret.
+func (w *Watcher) Current(name string) (*Secret, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	entry, ok := w.entries[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotWatched, name)
+	}
+
+	if entry.secret == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotLoaded, name)
+	}
+
+	return entry.secret, nil
+}
+
+func (w *Watcher) Name() string {
+	return "secrets-watcher"
+}
+
+// Ready is closed once every watched secret has been loaded.
+func (w *Watcher) Ready() <-chan struct{} {
+	return w.ready
+}
+
+// Start loads every watched secret, failing when one cannot be loaded, and keeps them current until
+// ctx is cancelled or Stop is called.
+func (w *Watcher) Start(ctx context.Context) error {
+	if !w.running.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	w.mu.Lock()
+	w.stop = make(chan struct{})
+	w.stopped = make(chan struct{})
+	stop, stopped := w.stop, w.stopped
+
+	entries := make([]*watchedSecret, 0, len(w.entries))
+	for _, entry := range w.entries {
+		entries = append(entries, entry)
+	}
+	w.mu.Unlock()
+
+	defer close(stopped)
+
+	for _, entry := range entries {
+		if err := w.fetch(ctx, entry); err != nil {
+			w.running.Store(false)
+
+			return err
+		}
+	}
+
+	w.readyOnce.Do(func() { close(w.ready) })
+
+	log.Info().Str("source", "gframework").Int("secrets", len(entries)).Msg("Secrets watcher is starting")
+
+	var wg sync.WaitGroup
+
+	for _, entry := range entries {
+		wg.Go(func() {
+			w.watch(ctx, stop, entry)
+		})
+	}
+
+	wg.Wait()
+
+	return nil
+}
+
+func (w *Watcher) Stop() error {
+	if !w.running.CompareAndSwap(true, false) {
+		return nil
+	}
+
+	w.mu.RLock()
+	stop, stopped := w.stop, w.stopped
+	w.mu.RUnlock()
+
+	close(stop)
+	<-stopped
+
+	log.Info().Str("source", "gframework").Msg("Secrets watcher stopped")
+
+	return nil
+}
+
+func (w *Watcher) watch(ctx context.Context, stop <-chan struct{}, entry *watchedSecret) {
+	for {
+		timer := time.NewTimer(w.nextDelay(entry))
+
+		select {
+		case <-stop:
+			timer.Stop()
+
+			return
+		case <-ctx.Done():
+			timer.Stop()
+
+			return
+		case <-timer.C:
+		}
+
+		if w.renew(ctx, entry) {
+			continue
+		}
+
+		if err := w.fetch(ctx, entry); err != nil {
+			log.Error().Str("source", "gframework").Err(err).Str("secret", entry.name).Msg("Failed to fetch secret")
+		}
+	}
+}
+
+func (w *Watcher) nextDelay(entry *watchedSecret) time.Duration {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if entry.failed {
+		return w.retryDelay
+	}
+
+	secret := entry.secret
+	if secret.LeaseDuration <= 0 {
+		return w.pollInterval
+	}
+
+	//nolint:mnd
+	return max(secret.LeaseDuration*2/3-time.Since(secret.FetchedAt), 0)
+}
+
+// renew extends the lease of the secret and reports whether it did, so no new secret is needed.
+func (w *Watcher) renew(ctx context.Context, entry *watchedSecret) bool {
+	renewer, ok := w.provider.(Renewer)
+	if !ok {
+		return false
+	}
+
+	w.mu.RLock()
+	current, rotate := entry.secret, entry.rotate
+	w.mu.RUnlock()
+
+	if rotate || current.LeaseID == "" || !current.Renewable {
+		return false
+	}
+
+	renewed, err := renewer.RenewSecret(ctx, current)
+	if err != nil {
+		log.Warn().Str("source", "gframework").Err(err).Str("secret", entry.name).Msg("Failed to renew secret, fetching a new one")
+
+		return false
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	entry.secret = renewed
+	entry.failed = false
+	// A shorter lease than requested means the maximum TTL is near: rotate before it expires.
+	entry.rotate = renewed.LeaseDuration < current.LeaseDuration || !renewed.Renewable
+
+	log.Debug().
+		Str("source", "gframework").
+		Str("secret", entry.name).
+		Dur("lease_duration", renewed.LeaseDuration).
+		Msg("Secret lease renewed")
+
+	return true
+}
+
+func (w *Watcher) fetch(ctx context.Context, entry *watchedSecret) error {
+	next, err := w.provider.GetSecret(ctx, entry.name)
+
+	w.mu.Lock()
+
+	if err != nil {
+		entry.failed = true
+		w.mu.Unlock()
+
+		return fmt.Errorf("secrets: failed to load %s: %w", entry.name, err)
+	}
+
+	previous := entry.secret
+	entry.secret = next
+	entry.failed = false
+	entry.rotate = false
+	callbacks := entry.callbacks
+
+	w.mu.Unlock()
+
+	if !changed(previous, next) {
+		return nil
+	}
+
+	if previous != nil {
+		log.Info().Str("source", "gframework").Str("secret", entry.name).Msg("Secret has been rotated")
+	}
+
+	for _, callback := range callbacks {
+		if err := callback(ctx, next); err != nil {
+			log.Error().Str("source", "gframework").Err(err).Str("secret", entry.name).Msg("Secret change callback failed")
+		}
+	}
+
+	return nil
+}
+
+// Credentials returns the username and password fields of a watched secret, e.g. for
+// postgres.Config.CredentialsProvider or valkey.Config.CredentialsProvider. The secret must be watched;
+// Credentials registers it if it is not.
+func (w *Watcher) Credentials(name string) *Credentials {
+	return w.CredentialsWithKeys(name, DefaultUsernameKey, DefaultPasswordKey)
+}
+
+// CredentialsWithKeys is Credentials for secrets storing the username and password under other keys.
+func (w *Watcher) CredentialsWithKeys(name, usernameKey, passwordKey string) *Credentials {
+	w.Watch(name, nil)
+
+	return &Credentials{watcher: w, name: name, usernameKey: usernameKey, passwordKey: passwordKey}
+}
+
+// Credentials reads the current username and password of a watched secret on every call.
+type Credentials struct {
+	watcher     *Watcher
+	name        string
+	usernameKey string
+	passwordKey string
+}
+
+func (c *Credentials) Credentials(context.Context) (string, string, error) {
+	secret, err := c.watcher.Current(c.name)
+	if err != nil {
+		return "", "", err
+	}
+
+	return secret.Data[c.usernameKey], secret.Data[c.passwordKey], nil
+}
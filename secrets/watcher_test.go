@@ -0,0 +1,207 @@
+//nolint:exhaustruct
+package secrets_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/secrets"
+	"github.com/stretchr/testify/require"
+)
+
+const waitTimeout = 5 * time.Second
+
+// leaseProvider issues credentials with a lease that can be renewed a limited number of times.
+type leaseProvider struct {
+	mu            sync.Mutex
+	leaseDuration time.Duration
+	renewals      int
+	maxRenewals   int
+	issued        int
+}
+
+func (p *leaseProvider) GetSecret(_ context.Context, name string) (*secrets.Secret, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.issued++
+	p.renewals = 0
+
+	return &secrets.Secret{
+		Name:          name,
+		Data:          map[string]string{"username": "user-" + strconv.Itoa(p.issued), "password": "pw"},
+		LeaseID:       "lease-" + strconv.Itoa(p.issued),
+		LeaseDuration: p.leaseDuration,
+		Renewable:     true,
+		FetchedAt:     time.Now(),
+	}, nil
+}
+
+func (p *leaseProvider) RenewSecret(_ context.Context, secret *secrets.Secret) (*secrets.Secret, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.renewals++
+
+	renewed := *secret
+	renewed.FetchedAt = time.Now()
+
+	if p.renewals >= p.maxRenewals {
+		// The maximum TTL caps the lease.
+		renewed.LeaseDuration = secret.LeaseDuration / 2
+	}
+
+	return &renewed, nil
+}
+
+func (p *leaseProvider) counts() (int, int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.issued, p.renewals
+}
+
+func startWatcher(t *testing.T, watcher *secrets.Watcher) {
+	t.Helper()
+
+	errCh := make(chan error, 1)
+
+	go func() { errCh <- watcher.Start(context.Background()) }()
+
+	select {
+	case <-watcher.Ready():
+	case err := <-errCh:
+		t.Fatalf("watcher failed to start: %v", err)
+	case <-time.After(waitTimeout):
+		t.Fatal("watcher did not become ready")
+	}
+
+	t.Cleanup(func() {
+		require.NoError(t, watcher.Stop())
+		require.NoError(t, <-errCh)
+	})
+}
+
+func TestWatcher_RenewsLeaseThenRotates(t *testing.T) {
+	t.Parallel()
+
+	provider := &leaseProvider{leaseDuration: 60 * time.Millisecond, maxRenewals: 2}
+
+	watcher, err := secrets.NewWatcher(provider)
+	require.NoError(t, err)
+
+	usernames := make(chan string, 10)
+
+	watcher.Watch("database/creds/orders", func(_ context.Context, secret *secrets.Secret) error {
+		usernames <- secret.Data["username"]
+
+		return nil
+	})
+
+	credentials := watcher.Credentials("database/creds/orders")
+
+	startWatcher(t, watcher)
+	require.Equal(t, "user-1", <-usernames)
+
+	username, password, err := credentials.Credentials(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, "user-1", username)
+	require.Equal(t, "pw", password)
+
+	select {
+	case username := <-usernames:
+		require.Equal(t, "user-2", username, "a new secret is fetched once the lease reaches its maximum TTL")
+	case <-time.After(waitTimeout):
+		t.Fatal("secret was not rotated")
+	}
+
+	issued, _ := provider.counts()
+	require.GreaterOrEqual(t, issued, 2)
+
+	require.Eventually(t, func() bool {
+		username, _, err := credentials.Credentials(t.Context())
+
+		return err == nil && username != "user-1"
+	}, waitTimeout, 10*time.Millisecond)
+}
+
+func TestWatcher_PollsSecretsWithoutLease(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu    sync.Mutex
+		value = "v1"
+	)
+
+	env := secrets.NewEnvProvider(secrets.WithEnvLookup(func(string) (string, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return value, true
+	}))
+
+	watcher, err := secrets.NewWatcher(env, secrets.WithPollInterval(20*time.Millisecond))
+	require.NoError(t, err)
+
+	changes := make(chan string, 10)
+
+	watcher.Watch("API_KEY", func(_ context.Context, secret *secrets.Secret) error {
+		changes <- secret.Value
+
+		return nil
+	})
+
+	startWatcher(t, watcher)
+	require.Equal(t, "v1", <-changes)
+
+	mu.Lock()
+	value = "v2"
+	mu.Unlock()
+
+	select {
+	case got := <-changes:
+		require.Equal(t, "v2", got)
+	case <-time.After(waitTimeout):
+		t.Fatal("rotation was not detected")
+	}
+
+	require.Empty(t, changes, "unchanged secrets do not trigger callbacks")
+
+	current, err := watcher.Current("API_KEY")
+	require.NoError(t, err)
+	require.Equal(t, "v2", current.Value)
+}
+
+func TestWatcher_StartFailsForMissingSecret(t *testing.T) {
+	t.Parallel()
+
+	watcher, err := secrets.NewWatcher(secrets.NewEnvProvider(secrets.WithEnvLookup(func(string) (string, bool) {
+		return "", false
+	})))
+	require.NoError(t, err)
+
+	watcher.Watch("MISSING", nil)
+
+	require.ErrorIs(t, watcher.Start(t.Context()), secrets.ErrSecretNotFound)
+}
+
+func TestWatcher_Current(t *testing.T) {
+	t.Parallel()
+
+	watcher, err := secrets.NewWatcher(secrets.NewEnvProvider())
+	require.NoError(t, err)
+
+	_, err = watcher.Current("UNKNOWN")
+	require.ErrorIs(t, err, secrets.ErrNotWatched)
+
+	watcher.Watch("API_KEY", nil)
+
+	_, _, err = watcher.Credentials("API_KEY").Credentials(t.Context())
+	require.ErrorIs(t, err, secrets.ErrNotLoaded)
+
+	_, err = secrets.NewWatcher(nil)
+	require.ErrorIs(t, err, secrets.ErrNilProvider)
+}
@@ -0,0 +1,190 @@
+// Package snspub provides an Amazon SNS publisher with the same API as redispub.
+//
+// Topics are SNS topic names, resolved against Options.TopicARNPrefix, or full topic ARNs:
+//
+//	cfg, err := config.LoadDefaultConfig(ctx)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	publisher, err := snspub.New(sns.NewFromConfig(cfg), snspub.Options{
+//	    TopicARNPrefix: "arn:aws:sns:eu-west-1:123456789012:",
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	err = publisher.PublishToTopic(ctx, "orders", `{"event":"order.created"}`)
+//
+// Messages are sent with PublishBatch in batches of ten. Each message gets a random UUID, sent as the
+// message_id attribute and, on FIFO topics, as the deduplication ID. Subscribe SQS queues with raw
+// message delivery, or use sqssub.WithSNSEnvelope, to consume them with sqssub.
+package snspub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sns/types"
+)
+
+const (
+	defaultPublishTimeout = 5 * time.Second
+	defaultMessageGroupID = "default"
+	maxBatchSize          = 10
+	fifoSuffix            = ".fifo"
+	arnPrefix             = "arn:"
+	// MessageIDAttribute carries the UUID of the message.
+	MessageIDAttribute = "message_id"
+)
+
+var (
+	ErrNilSNSClient   = errors.New("snspub: sns client cannot be nil")
+	ErrPublishFailed  = errors.New("snspub: failed to publish messages")
+	ErrUnresolvedARN  = errors.New("snspub: topic is not an ARN and no topic ARN prefix is set")
+	ErrPartialFailure = errors.New("snspub: some messages were rejected")
+)
+
+// SNSAPI is the part of *sns.Client the publisher uses.
+type SNSAPI interface {
+	PublishBatch(ctx context.Context, params *sns.PublishBatchInput, optFns ...func(*sns.Options)) (*sns.PublishBatchOutput, error)
+}
+
+// Publisher has the same method set as redispub.Publisher and kafkapub.Publisher.
+type Publisher interface {
+	PublishToTopic(ctx context.Context, topic string, messageContents ...string) error
+	Close() error
+}
+
+type Options struct {
+	Timeout time.Duration
+	// TopicARNPrefix turns topic names into ARNs, e.g. "arn:aws:sns:eu-west-1:123456789012:".
+	TopicARNPrefix string
+	// MessageGroupID is the message group of messages sent to FIFO topics. Messages of one group are
+	// delivered in order. It defaults to "default".
+	MessageGroupID string
+}
+
+type SNSPublisher struct {
+	client         SNSAPI
+	timeout        time.Duration
+	topicARNPrefix string
+	messageGroupID string
+}
+
+var _ Publisher = (*SNSPublisher)(nil)
+
+func New(client SNSAPI, opts Options) (*SNSPublisher, error) {
+	if client == nil {
+		return nil, ErrNilSNSClient
+	}
+
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = defaultPublishTimeout
+	}
+
+	messageGroupID := opts.MessageGroupID
+	if messageGroupID == "" {
+		messageGroupID = defaultMessageGroupID
+	}
+
+	return &SNSPublisher{
+		client:         client,
+		timeout:        timeout,
+		topicARNPrefix: opts.TopicARNPrefix,
+		messageGroupID: messageGroupID,
+	}, nil
+}
+
+// TopicARN returns the ARN messages of topic are published to.
+func (p *SNSPublisher) TopicARN(topic string) (string, error) {
+	if strings.HasPrefix(topic, arnPrefix) {
+		return topic, nil
+	}
+
+	if p.topicARNPrefix == "" {
+		return "", fmt.Errorf("%w: %s", ErrUnresolvedARN, topic)
+	}
+
+	return p.topicARNPrefix + topic, nil
+}
+
+// PublishToTopic publishes the messages in order. On a partial failure the rejected messages are
+// reported in the error; the accepted ones are not rolled back.
+func (p *SNSPublisher) PublishToTopic(ctx context.Context, topic string, messageContents ...string) error {
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+
+	topicARN, err := p.TopicARN(topic)
+	if err != nil {
+		return fmt.Errorf("%w to topic %s: %w", ErrPublishFailed, topic, err)
+	}
+
+	fifo := strings.HasSuffix(topicARN, fifoSuffix)
+
+	for start := 0; start < len(messageContents); start += maxBatchSize {
+		batch := messageContents[start:min(start+maxBatchSize, len(messageContents))]
+
+		if err := p.publishBatch(ctx, topicARN, fifo, batch); err != nil {
+			return fmt.Errorf("%w to topic %s: %w", ErrPublishFailed, topic, err)
+		}
+	}
+
+	return nil
+}
+
+func (p *SNSPublisher) publishBatch(ctx context.Context, topicARN string, fifo bool, contents []string) error {
+	entries := make([]types.PublishBatchRequestEntry, 0, len(contents))
+
+	for _, content := range contents {
+		id := watermill.NewUUID()
+
+		//nolint:exhaustruct
+		entry := types.PublishBatchRequestEntry{
+			Id:      aws.String(id),
+			Message: aws.String(content),
+			MessageAttributes: map[string]types.MessageAttributeValue{
+				MessageIDAttribute: {DataType: aws.String("String"), StringValue: aws.String(id)},
+			},
+		}
+
+		if fifo {
+			entry.MessageGroupId = aws.String(p.messageGroupID)
+			entry.MessageDeduplicationId = aws.String(id)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	output, err := p.client.PublishBatch(ctx, &sns.PublishBatchInput{
+		TopicArn:                   aws.String(topicARN),
+		PublishBatchRequestEntries: entries,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(output.Failed) > 0 {
+		failed := output.Failed[0]
+
+		return fmt.Errorf("%w: %d of %d, e.g. %s: %s", ErrPartialFailure, len(output.Failed), len(entries),
+			aws.ToString(failed.Code), aws.ToString(failed.Message))
+	}
+
+	return nil
+}
+
+// Close is a no-op; the SNS client is owned by the caller.
+func (p *SNSPublisher) Close() error {
+	return nil
+}
@@ -0,0 +1,169 @@
+//nolint:exhaustruct
+package snspub_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/andyle182810/gframework/snspub"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/stretchr/testify/require"
+)
+
+var errThrottled = errors.New("throttled")
+
+type fakeSNS struct {
+	mu      sync.Mutex
+	inputs  []*sns.PublishBatchInput
+	failIDs int
+	err     error
+}
+
+func (f *fakeSNS) PublishBatch(
+	_ context.Context,
+	params *sns.PublishBatchInput,
+	_ ...func(*sns.Options),
+) (*sns.PublishBatchOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	f.inputs = append(f.inputs, params)
+
+	output := &sns.PublishBatchOutput{}
+
+	for i, entry := range params.PublishBatchRequestEntries {
+		if i < f.failIDs {
+			output.Failed = append(output.Failed, types.BatchResultErrorEntry{
+				Id:      entry.Id,
+				Code:    aws.String("InvalidParameter"),
+				Message: aws.String("message too large"),
+			})
+		}
+	}
+
+	return output, nil
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	publisher, err := snspub.New(nil, snspub.Options{})
+	require.ErrorIs(t, err, snspub.ErrNilSNSClient)
+	require.Nil(t, publisher)
+
+	publisher, err = snspub.New(&fakeSNS{}, snspub.Options{})
+	require.NoError(t, err)
+	require.NoError(t, publisher.Close())
+}
+
+func TestSNSPublisher_TopicARN(t *testing.T) {
+	t.Parallel()
+
+	publisher, err := snspub.New(&fakeSNS{}, snspub.Options{TopicARNPrefix: "arn:aws:sns:eu-west-1:123456789012:"})
+	require.NoError(t, err)
+
+	arn, err := publisher.TopicARN("orders")
+	require.NoError(t, err)
+	require.Equal(t, "arn:aws:sns:eu-west-1:123456789012:orders", arn)
+
+	arn, err = publisher.TopicARN("arn:aws:sns:us-east-1:123456789012:payments")
+	require.NoError(t, err)
+	require.Equal(t, "arn:aws:sns:us-east-1:123456789012:payments", arn)
+
+	withoutPrefix, err := snspub.New(&fakeSNS{}, snspub.Options{})
+	require.NoError(t, err)
+
+	_, err = withoutPrefix.TopicARN("orders")
+	require.ErrorIs(t, err, snspub.ErrUnresolvedARN)
+
+	err = withoutPrefix.PublishToTopic(t.Context(), "orders", "message")
+	require.ErrorIs(t, err, snspub.ErrPublishFailed)
+	require.ErrorIs(t, err, snspub.ErrUnresolvedARN)
+}
+
+func TestSNSPublisher_PublishToTopicBatches(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeSNS{}
+	publisher, err := snspub.New(client, snspub.Options{TopicARNPrefix: "arn:aws:sns:eu-west-1:123456789012:"})
+	require.NoError(t, err)
+
+	messages := make([]string, 23)
+	for i := range messages {
+		messages[i] = string(rune('a' + i))
+	}
+
+	require.NoError(t, publisher.PublishToTopic(t.Context(), "orders", messages...))
+
+	require.Len(t, client.inputs, 3)
+	require.Len(t, client.inputs[0].PublishBatchRequestEntries, 10)
+	require.Len(t, client.inputs[1].PublishBatchRequestEntries, 10)
+	require.Len(t, client.inputs[2].PublishBatchRequestEntries, 3)
+
+	var sent []string
+
+	for _, input := range client.inputs {
+		require.Equal(t, "arn:aws:sns:eu-west-1:123456789012:orders", aws.ToString(input.TopicArn))
+
+		for _, entry := range input.PublishBatchRequestEntries {
+			sent = append(sent, aws.ToString(entry.Message))
+
+			require.NotEmpty(t, aws.ToString(entry.Id))
+			require.Equal(t, aws.ToString(entry.Id),
+				aws.ToString(entry.MessageAttributes[snspub.MessageIDAttribute].StringValue))
+			require.Nil(t, entry.MessageGroupId, "standard topics have no message groups")
+			require.Nil(t, entry.MessageDeduplicationId)
+		}
+	}
+
+	require.Equal(t, messages, sent)
+}
+
+func TestSNSPublisher_PublishToFIFOTopic(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeSNS{}
+	publisher, err := snspub.New(client, snspub.Options{
+		TopicARNPrefix: "arn:aws:sns:eu-west-1:123456789012:",
+		MessageGroupID: "tenant-1",
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, publisher.PublishToTopic(t.Context(), "orders.fifo", "first", "second"))
+
+	require.Len(t, client.inputs, 1)
+
+	for _, entry := range client.inputs[0].PublishBatchRequestEntries {
+		require.Equal(t, "tenant-1", aws.ToString(entry.MessageGroupId))
+		require.Equal(t, aws.ToString(entry.Id), aws.ToString(entry.MessageDeduplicationId))
+	}
+}
+
+func TestSNSPublisher_PublishToTopicFailures(t *testing.T) {
+	t.Parallel()
+
+	opts := snspub.Options{TopicARNPrefix: "arn:aws:sns:eu-west-1:123456789012:"}
+
+	publisher, err := snspub.New(&fakeSNS{err: errThrottled}, opts)
+	require.NoError(t, err)
+
+	err = publisher.PublishToTopic(t.Context(), "orders", "message")
+	require.ErrorIs(t, err, snspub.ErrPublishFailed)
+	require.ErrorIs(t, err, errThrottled)
+
+	publisher, err = snspub.New(&fakeSNS{failIDs: 1}, opts)
+	require.NoError(t, err)
+
+	err = publisher.PublishToTopic(t.Context(), "orders", "first", "second")
+	require.ErrorIs(t, err, snspub.ErrPublishFailed)
+	require.ErrorIs(t, err, snspub.ErrPartialFailure)
+	require.ErrorContains(t, err, "message too large")
+}
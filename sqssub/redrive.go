@@ -0,0 +1,143 @@
+package sqssub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+var (
+	ErrInvalidMaxReceiveCount = errors.New("sqssub: max receive count must be between 1 and 1000")
+	ErrQueueARNNotFound       = errors.New("sqssub: queue has no ARN attribute")
+)
+
+const maxReceiveCountLimit = 1000
+
+// RedriveTask is a message move task started by Redrive.
+type RedriveTask struct {
+	Handle                 string
+	Status                 string
+	SourceARN              string
+	DestinationARN         string
+	MessagesMoved          int64
+	MessagesToMove         int64
+	FailureReason          string
+	StartedTimestampMillis int64
+}
+
+// QueueARN returns the ARN of the queue at queueURL.
+func QueueARN(ctx context.Context, client SQSAPI, queueURL string) (string, error) {
+	output, err := client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(queueURL),
+		AttributeNames: []types.QueueAttributeName{types.QueueAttributeNameQueueArn},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get attributes of %s: %w", queueURL, err)
+	}
+
+	arn, ok := output.Attributes[string(types.QueueAttributeNameQueueArn)]
+	if !ok || arn == "" {
+		return "", fmt.Errorf("%w: %s", ErrQueueARNNotFound, queueURL)
+	}
+
+	return arn, nil
+}
+
+// ConfigureDeadLetterQueue sets the redrive policy of the queue at queueURL, so SQS moves a message to
+// the queue with ARN dlqARN once it has been received maxReceiveCount times.
+func ConfigureDeadLetterQueue(
+	ctx context.Context,
+	client SQSAPI,
+	queueURL string,
+	dlqARN string,
+	maxReceiveCount int,
+) error {
+	if maxReceiveCount < 1 || maxReceiveCount > maxReceiveCountLimit {
+		return ErrInvalidMaxReceiveCount
+	}
+
+	policy, err := json.Marshal(map[string]string{
+		"deadLetterTargetArn": dlqARN,
+		"maxReceiveCount":     strconv.Itoa(maxReceiveCount),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal redrive policy: %w", err)
+	}
+
+	_, err = client.SetQueueAttributes(ctx, &sqs.SetQueueAttributesInput{
+		QueueUrl: aws.String(queueURL),
+		Attributes: map[string]string{
+			string(types.QueueAttributeNameRedrivePolicy): string(policy),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set redrive policy of %s: %w", queueURL, err)
+	}
+
+	return nil
+}
+
+// Redrive starts moving the messages of the dead letter queue dlqARN to destinationARN, or back to
+// their source queues when destinationARN is empty. maxPerSecond limits the move rate; zero lets SQS
+// pick it. The move runs asynchronously; use RedriveTasks to follow it.
+func Redrive(
+	ctx context.Context,
+	client SQSAPI,
+	dlqARN string,
+	destinationARN string,
+	maxPerSecond int,
+) (string, error) {
+	//nolint:exhaustruct
+	input := &sqs.StartMessageMoveTaskInput{
+		SourceArn: aws.String(dlqARN),
+	}
+
+	if destinationARN != "" {
+		input.DestinationArn = aws.String(destinationARN)
+	}
+
+	if maxPerSecond > 0 {
+		input.MaxNumberOfMessagesPerSecond = aws.Int32(int32(maxPerSecond)) //nolint:gosec
+	}
+
+	output, err := client.StartMessageMoveTask(ctx, input)
+	if err != nil {
+		return "", fmt.Errorf("failed to start redrive of %s: %w", dlqARN, err)
+	}
+
+	return aws.ToString(output.TaskHandle), nil
+}
+
+// RedriveTasks returns the most recent message move tasks of the dead letter queue dlqARN, newest first.
+func RedriveTasks(ctx context.Context, client SQSAPI, dlqARN string) ([]RedriveTask, error) {
+	//nolint:exhaustruct
+	output, err := client.ListMessageMoveTasks(ctx, &sqs.ListMessageMoveTasksInput{
+		SourceArn: aws.String(dlqARN),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list redrive tasks of %s: %w", dlqARN, err)
+	}
+
+	tasks := make([]RedriveTask, 0, len(output.Results))
+
+	for _, result := range output.Results {
+		tasks = append(tasks, RedriveTask{
+			Handle:                 aws.ToString(result.TaskHandle),
+			Status:                 aws.ToString(result.Status),
+			SourceARN:              aws.ToString(result.SourceArn),
+			DestinationARN:         aws.ToString(result.DestinationArn),
+			MessagesMoved:          result.ApproximateNumberOfMessagesMoved,
+			MessagesToMove:         aws.ToInt64(result.ApproximateNumberOfMessagesToMove),
+			FailureReason:          aws.ToString(result.FailureReason),
+			StartedTimestampMillis: result.StartedTimestamp,
+		})
+	}
+
+	return tasks, nil
+}
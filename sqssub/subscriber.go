@@ -0,0 +1,604 @@
+// Package sqssub provides an Amazon SQS subscriber with the same API shape as redissub.
+//
+// The subscriber long polls a queue, runs the handler for each received message and deletes the
+// processed ones in one batch:
+//
+//	cfg, err := config.LoadDefaultConfig(ctx)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	subscriber, err := sqssub.NewSubscriber(sqs.NewFromConfig(cfg), queueURL, handler,
+//	    sqssub.WithVisibilityTimeout(30*time.Second),
+//	    sqssub.WithRetryDelay(10*time.Second),
+//	)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	runner.New(runner.WithCoreService(subscriber)).Run()
+//
+// The messages of a receive are handled concurrently, so handlers must be safe for concurrent use.
+// While a handler runs, the subscriber keeps extending the visibility timeout of its message, so long
+// handlers are not redelivered to another consumer.
+//
+// Retries and the dead letter queue are features of the queue itself: a failed message is made visible
+// again after the retry delay, and SQS moves it to the dead letter queue of the queue's redrive policy
+// once it has been received maxReceiveCount times. Use ConfigureDeadLetterQueue to set the policy and
+// Redrive to move dead letters back once the cause is fixed. Without a redrive policy a failing message
+// is retried until it expires.
+package sqssub
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultShutdownTimeout   = 20 * time.Second
+	defaultExecTimeout       = 30 * time.Second
+	defaultVisibilityTimeout = 30 * time.Second
+	defaultWaitTime          = 20 * time.Second
+	defaultMaxMessages       = 10
+	receiveErrorBackoff      = time.Second
+	maxBatchSize             = 10
+)
+
+var (
+	ErrNilSQSClient             = errors.New("sqssub: sqs client cannot be nil")
+	ErrEmptyQueueURL            = errors.New("sqssub: queue URL cannot be empty")
+	ErrNilMessageHandler        = errors.New("sqssub: message handler cannot be nil")
+	ErrMessageHandlerNotDefined = errors.New("sqssub: message handler is not defined")
+	ErrExecTimeout              = errors.New("sqssub: message handler execution timed out")
+	ErrAlreadyRunning           = errors.New("sqssub: subscriber already running")
+	ErrInvalidSNSEnvelope       = errors.New("sqssub: message is not an SNS notification")
+)
+
+// SQSAPI is the part of *sqs.Client the subscriber and the redrive helpers use.
+type SQSAPI interface {
+	ReceiveMessage(ctx context.Context, params *sqs.ReceiveMessageInput,
+		optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error)
+	ChangeMessageVisibility(ctx context.Context, params *sqs.ChangeMessageVisibilityInput,
+		optFns ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityOutput, error)
+	DeleteMessageBatch(ctx context.Context, params *sqs.DeleteMessageBatchInput,
+		optFns ...func(*sqs.Options)) (*sqs.DeleteMessageBatchOutput, error)
+	GetQueueAttributes(ctx context.Context, params *sqs.GetQueueAttributesInput,
+		optFns ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error)
+	SetQueueAttributes(ctx context.Context, params *sqs.SetQueueAttributesInput,
+		optFns ...func(*sqs.Options)) (*sqs.SetQueueAttributesOutput, error)
+	StartMessageMoveTask(ctx context.Context, params *sqs.StartMessageMoveTaskInput,
+		optFns ...func(*sqs.Options)) (*sqs.StartMessageMoveTaskOutput, error)
+	ListMessageMoveTasks(ctx context.Context, params *sqs.ListMessageMoveTasksInput,
+		optFns ...func(*sqs.Options)) (*sqs.ListMessageMoveTasksOutput, error)
+}
+
+// MessageHandler has the same signature as redissub.MessageHandler.
+type MessageHandler func(ctx context.Context, payload message.Payload) error
+
+type Metrics interface {
+	MessageReceived(topic string)
+	MessageProcessed(topic string, duration time.Duration, err error)
+	MessageAcked(topic string)
+	MessageNacked(topic string)
+}
+
+type SubscriberConfig struct {
+	ShutdownTimeout   time.Duration // Maximum time to wait for graceful shutdown before force closing
+	ExecTimeout       time.Duration // Maximum time allowed for message handler execution
+	VisibilityTimeout time.Duration // Visibility timeout of received messages, extended while they are handled
+	WaitTime          time.Duration // Long polling wait time, up to 20 seconds
+	MaxMessages       int           // Messages per receive, up to 10
+	RetryDelay        time.Duration // Delay before a failed message is received again
+	SNSEnvelope       bool          // Unwrap SNS notifications delivered without raw message delivery
+	Metrics           Metrics
+}
+
+type SubscriberOption func(*SubscriberConfig)
+
+func WithMetrics(m Metrics) SubscriberOption {
+	return func(c *SubscriberConfig) {
+		c.Metrics = m
+	}
+}
+
+func WithShutdownTimeout(d time.Duration) SubscriberOption {
+	return func(c *SubscriberConfig) {
+		c.ShutdownTimeout = d
+	}
+}
+
+func WithExecTimeout(d time.Duration) SubscriberOption {
+	return func(c *SubscriberConfig) {
+		c.ExecTimeout = d
+	}
+}
+
+// WithVisibilityTimeout sets the visibility timeout of received messages. The subscriber extends it
+// every half timeout while the handler runs, so it only bounds how long a message stays hidden after
+// its consumer died.
+func WithVisibilityTimeout(d time.Duration) SubscriberOption {
+	return func(c *SubscriberConfig) {
+		if d >= time.Second {
+			c.VisibilityTimeout = d
+		}
+	}
+}
+
+// WithWaitTime sets how long a receive waits for messages, up to 20 seconds.
+func WithWaitTime(d time.Duration) SubscriberOption {
+	return func(c *SubscriberConfig) {
+		c.WaitTime = min(max(d, 0), defaultWaitTime)
+	}
+}
+
+// WithMaxMessages sets how many messages a receive returns at most, from 1 to 10.
+func WithMaxMessages(n int) SubscriberOption {
+	return func(c *SubscriberConfig) {
+		c.MaxMessages = min(max(n, 1), maxBatchSize)
+	}
+}
+
+// WithRetryDelay sets how long a failed message stays invisible before it is received again. It
+// defaults to zero, an immediate retry.
+func WithRetryDelay(d time.Duration) SubscriberOption {
+	return func(c *SubscriberConfig) {
+		c.RetryDelay = max(d, 0)
+	}
+}
+
+// WithSNSEnvelope unwraps the SNS notification JSON that SNS wraps messages in when the queue is
+// subscribed without raw message delivery, so handlers get the published message.
+func WithSNSEnvelope() SubscriberOption {
+	return func(c *SubscriberConfig) {
+		c.SNSEnvelope = true
+	}
+}
+
+type Subscriber struct {
+	client         SQSAPI
+	name           string
+	queueURL       string
+	queueName      string
+	shutdownSignal chan struct{}
+	stoppedSignal  chan struct{}
+	messageHandler MessageHandler
+	config         SubscriberConfig
+	healthy        atomic.Bool
+	running        atomic.Bool
+}
+
+func NewSubscriber(
+	client SQSAPI,
+	queueURL string,
+	messageHandler MessageHandler,
+	opts ...SubscriberOption,
+) (*Subscriber, error) {
+	if client == nil {
+		return nil, ErrNilSQSClient
+	}
+
+	if queueURL == "" {
+		return nil, ErrEmptyQueueURL
+	}
+
+	if messageHandler == nil {
+		return nil, ErrNilMessageHandler
+	}
+
+	config := defaultSubscriberConfig()
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	queueName := queueURL[strings.LastIndex(queueURL, "/")+1:]
+
+	//nolint:exhaustruct
+	sub := &Subscriber{
+		client:         client,
+		name:           "sqssub-" + queueName,
+		queueURL:       queueURL,
+		queueName:      queueName,
+		shutdownSignal: make(chan struct{}),
+		stoppedSignal:  make(chan struct{}),
+		messageHandler: messageHandler,
+		config:         config,
+	}
+	sub.healthy.Store(false)
+
+	return sub, nil
+}
+
+func defaultSubscriberConfig() SubscriberConfig {
+	return SubscriberConfig{
+		ShutdownTimeout:   defaultShutdownTimeout,
+		ExecTimeout:       defaultExecTimeout,
+		VisibilityTimeout: defaultVisibilityTimeout,
+		WaitTime:          defaultWaitTime,
+		MaxMessages:       defaultMaxMessages,
+		RetryDelay:        0,
+		SNSEnvelope:       false,
+		Metrics:           nil,
+	}
+}
+
+func (s *Subscriber) Name() string {
+	return s.name
+}
+
+func (s *Subscriber) IsHealthy() bool {
+	return s.healthy.Load()
+}
+
+// Topic returns the queue name, which labels the metrics.
+func (s *Subscriber) Topic() string {
+	return s.queueName
+}
+
+func (s *Subscriber) QueueURL() string {
+	return s.queueURL
+}
+
+func (s *Subscriber) Start(ctx context.Context) error {
+	if !s.running.CompareAndSwap(false, true) {
+		return ErrAlreadyRunning
+	}
+
+	defer close(s.stoppedSignal)
+
+	log.Info().
+		Str("source", "gframework").
+		Str("queue", s.queueName).
+		Msg("The subscription is being started")
+
+	// Stop interrupts a long poll in progress; messages being handled still finish with ctx.
+	receiveCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		select {
+		case <-s.shutdownSignal:
+			cancel()
+		case <-receiveCtx.Done():
+		}
+	}()
+
+	s.healthy.Store(true)
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.healthy.Store(false)
+			log.Info().
+				Str("source", "gframework").
+				Str("service_name", s.Name()).
+				Str("queue", s.queueName).
+				Msg("Subscriber stopped: context cancelled")
+
+			return ctx.Err()
+		case <-s.shutdownSignal:
+			s.healthy.Store(false)
+			log.Info().
+				Str("source", "gframework").
+				Str("service_name", s.Name()).
+				Str("queue", s.queueName).
+				Msg("Subscriber stopped: graceful shutdown initiated")
+
+			return nil
+		default:
+		}
+
+		messages, err := s.receive(receiveCtx)
+		if err != nil {
+			if receiveCtx.Err() != nil {
+				continue
+			}
+
+			s.healthy.Store(false)
+			log.Error().
+				Str("source", "gframework").
+				Err(err).
+				Str("queue", s.queueName).
+				Msg("Failed to receive messages")
+
+			select {
+			case <-receiveCtx.Done():
+			case <-time.After(receiveErrorBackoff):
+			}
+
+			continue
+		}
+
+		s.healthy.Store(true)
+		s.handleMessages(ctx, messages)
+	}
+}
+
+func (s *Subscriber) Stop() error {
+	if !s.running.CompareAndSwap(true, false) {
+		return nil
+	}
+
+	log.Info().
+		Str("source", "gframework").
+		Str("queue", s.queueName).
+		Msg("Stopping subscriber")
+
+	s.healthy.Store(false)
+	close(s.shutdownSignal)
+
+	select {
+	case <-s.stoppedSignal:
+		// Good, subscriber stopped cleanly
+	case <-time.After(s.config.ShutdownTimeout):
+		log.Error().
+			Str("source", "gframework").
+			Str("queue", s.queueName).
+			Dur("timeout", s.config.ShutdownTimeout).
+			Msg("Timeout waiting for subscriber to stop")
+	}
+
+	log.Info().
+		Str("source", "gframework").
+		Str("queue", s.queueName).
+		Msg("Subscriber stopped")
+
+	return nil
+}
+
+func (s *Subscriber) receive(ctx context.Context) ([]types.Message, error) {
+	//nolint:exhaustruct
+	output, err := s.client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+		QueueUrl:                    aws.String(s.queueURL),
+		MaxNumberOfMessages:         int32(s.config.MaxMessages), //nolint:gosec
+		WaitTimeSeconds:             int32(s.config.WaitTime.Seconds()),
+		VisibilityTimeout:           seconds(s.config.VisibilityTimeout),
+		MessageAttributeNames:       []string{"All"},
+		MessageSystemAttributeNames: []types.MessageSystemAttributeName{types.MessageSystemAttributeNameApproximateReceiveCount},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to receive from %s: %w", s.queueName, err)
+	}
+
+	return output.Messages, nil
+}
+
+// handleMessages handles messages concurrently and deletes the processed ones in one batch.
+func (s *Subscriber) handleMessages(ctx context.Context, messages []types.Message) {
+	if len(messages) == 0 {
+		return
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		processed = make([]types.Message, 0, len(messages))
+	)
+
+	for _, msg := range messages {
+		wg.Go(func() {
+			if s.handleMessage(ctx, msg) {
+				mu.Lock()
+				processed = append(processed, msg)
+				mu.Unlock()
+			}
+		})
+	}
+
+	wg.Wait()
+
+	s.deleteMessages(context.WithoutCancel(ctx), processed)
+}
+
+// handleMessage runs the handler and reports whether the message was processed.
+func (s *Subscriber) handleMessage(ctx context.Context, msg types.Message) bool {
+	if s.config.Metrics != nil {
+		s.config.Metrics.MessageReceived(s.queueName)
+	}
+
+	stopExtending := s.extendVisibility(ctx, msg)
+
+	start := time.Now()
+	processingErr := s.process(ctx, msg)
+
+	stopExtending()
+
+	if s.config.Metrics != nil {
+		s.config.Metrics.MessageProcessed(s.queueName, time.Since(start), processingErr)
+	}
+
+	if processingErr == nil {
+		return true
+	}
+
+	log.Error().
+		Str("source", "gframework").
+		Err(processingErr).
+		Str("queue", s.queueName).
+		Str("message_id", aws.ToString(msg.MessageId)).
+		Str("receive_count", msg.Attributes[string(types.MessageSystemAttributeNameApproximateReceiveCount)]).
+		Msg("The message processing has failed")
+
+	// Make the message visible again after the retry delay instead of the remaining visibility timeout.
+	if err := s.changeVisibility(context.WithoutCancel(ctx), msg, s.config.RetryDelay); err != nil {
+		log.Warn().
+			Str("source", "gframework").
+			Err(err).
+			Str("message_id", aws.ToString(msg.MessageId)).
+			Msg("Failed to reset the visibility of the failed message")
+	}
+
+	if s.config.Metrics != nil {
+		s.config.Metrics.MessageNacked(s.queueName)
+	}
+
+	return false
+}
+
+func (s *Subscriber) process(ctx context.Context, msg types.Message) error {
+	payload := []byte(aws.ToString(msg.Body))
+
+	if s.config.SNSEnvelope {
+		var err error
+		if payload, err = unwrapSNS(payload); err != nil {
+			return err
+		}
+	}
+
+	return s.executeWithTimeout(ctx, payload)
+}
+
+type snsNotification struct {
+	Type    string `json:"Type"`
+	Message string `json:"Message"`
+}
+
+func unwrapSNS(body []byte) ([]byte, error) {
+	var notification snsNotification
+	if err := json.Unmarshal(body, &notification); err != nil || notification.Type != "Notification" {
+		return nil, ErrInvalidSNSEnvelope
+	}
+
+	return []byte(notification.Message), nil
+}
+
+func (s *Subscriber) executeWithTimeout(ctx context.Context, payload []byte) error {
+	if s.messageHandler == nil {
+		return ErrMessageHandlerNotDefined
+	}
+
+	if s.config.ExecTimeout <= 0 {
+		return s.messageHandler(ctx, payload)
+	}
+
+	execCtx, cancel := context.WithTimeout(ctx, s.config.ExecTimeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- s.messageHandler(execCtx, payload)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-execCtx.Done():
+		if errors.Is(execCtx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("%w: exceeded %v", ErrExecTimeout, s.config.ExecTimeout)
+		}
+
+		return execCtx.Err()
+	}
+}
+
+// extendVisibility resets the visibility timeout of msg every half timeout until the returned function
+// is called.
+func (s *Subscriber) extendVisibility(ctx context.Context, msg types.Message) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(s.config.VisibilityTimeout / 2) //nolint:mnd
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.changeVisibility(ctx, msg, s.config.VisibilityTimeout); err != nil {
+					log.Warn().
+						Str("source", "gframework").
+						Err(err).
+						Str("message_id", aws.ToString(msg.MessageId)).
+						Msg("Failed to extend the visibility timeout")
+				}
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+func (s *Subscriber) changeVisibility(ctx context.Context, msg types.Message, timeout time.Duration) error {
+	_, err := s.client.ChangeMessageVisibility(ctx, &sqs.ChangeMessageVisibilityInput{
+		QueueUrl:          aws.String(s.queueURL),
+		ReceiptHandle:     msg.ReceiptHandle,
+		VisibilityTimeout: seconds(timeout),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to change visibility: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Subscriber) deleteMessages(ctx context.Context, messages []types.Message) {
+	if len(messages) == 0 {
+		return
+	}
+
+	entries := make([]types.DeleteMessageBatchRequestEntry, 0, len(messages))
+
+	for i, msg := range messages {
+		entries = append(entries, types.DeleteMessageBatchRequestEntry{
+			Id:            aws.String(strconv.Itoa(i)),
+			ReceiptHandle: msg.ReceiptHandle,
+		})
+	}
+
+	output, err := s.client.DeleteMessageBatch(ctx, &sqs.DeleteMessageBatchInput{
+		QueueUrl: aws.String(s.queueURL),
+		Entries:  entries,
+	})
+	if err != nil {
+		log.Error().
+			Str("source", "gframework").
+			Err(err).
+			Str("queue", s.queueName).
+			Int("messages", len(messages)).
+			Msg("Failed to delete processed messages")
+
+		return
+	}
+
+	for _, failed := range output.Failed {
+		log.Warn().
+			Str("source", "gframework").
+			Str("queue", s.queueName).
+			Str("code", aws.ToString(failed.Code)).
+			Str("reason", aws.ToString(failed.Message)).
+			Msg("Failed to delete a processed message")
+	}
+
+	if s.config.Metrics != nil {
+		for range len(messages) - len(output.Failed) {
+			s.config.Metrics.MessageAcked(s.queueName)
+		}
+	}
+}
+
+func seconds(d time.Duration) int32 {
+	return int32(d / time.Second) //nolint:gosec
+}
@@ -0,0 +1,374 @@
+//nolint:exhaustruct
+package sqssub_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/andyle182810/gframework/sqssub"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testTimeout  = 5 * time.Second
+	testQueueURL = "https://sqs.eu-west-1.amazonaws.com/123456789012/orders"
+)
+
+var errHandlerFailed = errors.New("handler failed")
+
+// fakeSQS serves queued messages and blocks receives on an empty queue like a long poll.
+type fakeSQS struct {
+	mu           sync.Mutex
+	pending      []types.Message
+	arrived      chan struct{}
+	visibilities map[string][]int32
+	deleted      []string
+	attributes   map[string]string
+	moveInput    *sqs.StartMessageMoveTaskInput
+}
+
+func newFakeSQS(bodies ...string) *fakeSQS {
+	f := &fakeSQS{
+		arrived:      make(chan struct{}, 1),
+		visibilities: make(map[string][]int32),
+		attributes: map[string]string{
+			string(types.QueueAttributeNameQueueArn): "arn:aws:sqs:eu-west-1:123456789012:orders",
+		},
+	}
+
+	for i, body := range bodies {
+		f.pending = append(f.pending, types.Message{
+			MessageId:     aws.String("message-" + strconv.Itoa(i)),
+			ReceiptHandle: aws.String("receipt-" + strconv.Itoa(i)),
+			Body:          aws.String(body),
+		})
+	}
+
+	return f
+}
+
+func (f *fakeSQS) ReceiveMessage(
+	ctx context.Context,
+	params *sqs.ReceiveMessageInput,
+	_ ...func(*sqs.Options),
+) (*sqs.ReceiveMessageOutput, error) {
+	for {
+		f.mu.Lock()
+		if len(f.pending) > 0 {
+			n := min(len(f.pending), int(params.MaxNumberOfMessages))
+			messages := f.pending[:n]
+			f.pending = f.pending[n:]
+			f.mu.Unlock()
+
+			return &sqs.ReceiveMessageOutput{Messages: messages}, nil
+		}
+		f.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-f.arrived:
+		}
+	}
+}
+
+func (f *fakeSQS) ChangeMessageVisibility(
+	_ context.Context,
+	params *sqs.ChangeMessageVisibilityInput,
+	_ ...func(*sqs.Options),
+) (*sqs.ChangeMessageVisibilityOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	handle := aws.ToString(params.ReceiptHandle)
+	f.visibilities[handle] = append(f.visibilities[handle], params.VisibilityTimeout)
+
+	return &sqs.ChangeMessageVisibilityOutput{}, nil
+}
+
+func (f *fakeSQS) DeleteMessageBatch(
+	_ context.Context,
+	params *sqs.DeleteMessageBatchInput,
+	_ ...func(*sqs.Options),
+) (*sqs.DeleteMessageBatchOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, entry := range params.Entries {
+		f.deleted = append(f.deleted, aws.ToString(entry.ReceiptHandle))
+	}
+
+	return &sqs.DeleteMessageBatchOutput{}, nil
+}
+
+func (f *fakeSQS) GetQueueAttributes(
+	_ context.Context,
+	_ *sqs.GetQueueAttributesInput,
+	_ ...func(*sqs.Options),
+) (*sqs.GetQueueAttributesOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return &sqs.GetQueueAttributesOutput{Attributes: f.attributes}, nil
+}
+
+func (f *fakeSQS) SetQueueAttributes(
+	_ context.Context,
+	params *sqs.SetQueueAttributesInput,
+	_ ...func(*sqs.Options),
+) (*sqs.SetQueueAttributesOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for k, v := range params.Attributes {
+		f.attributes[k] = v
+	}
+
+	return &sqs.SetQueueAttributesOutput{}, nil
+}
+
+func (f *fakeSQS) StartMessageMoveTask(
+	_ context.Context,
+	params *sqs.StartMessageMoveTaskInput,
+	_ ...func(*sqs.Options),
+) (*sqs.StartMessageMoveTaskOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.moveInput = params
+
+	return &sqs.StartMessageMoveTaskOutput{TaskHandle: aws.String("task-1")}, nil
+}
+
+func (f *fakeSQS) ListMessageMoveTasks(
+	_ context.Context,
+	_ *sqs.ListMessageMoveTasksInput,
+	_ ...func(*sqs.Options),
+) (*sqs.ListMessageMoveTasksOutput, error) {
+	return &sqs.ListMessageMoveTasksOutput{
+		Results: []types.ListMessageMoveTasksResultEntry{{
+			TaskHandle:                        aws.String("task-1"),
+			Status:                            aws.String("RUNNING"),
+			SourceArn:                         aws.String("arn:aws:sqs:eu-west-1:123456789012:orders-dlq"),
+			ApproximateNumberOfMessagesMoved:  3,
+			ApproximateNumberOfMessagesToMove: aws.Int64(5),
+		}},
+	}, nil
+}
+
+func (f *fakeSQS) deletedHandles() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]string(nil), f.deleted...)
+}
+
+func (f *fakeSQS) visibilityChanges(handle string) []int32 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]int32(nil), f.visibilities[handle]...)
+}
+
+func noopHandler(context.Context, message.Payload) error {
+	return nil
+}
+
+func startSubscriber(t *testing.T, sub *sqssub.Subscriber) {
+	t.Helper()
+
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- sub.Start(context.Background())
+	}()
+
+	require.Eventually(t, sub.IsHealthy, testTimeout, 10*time.Millisecond)
+
+	t.Cleanup(func() {
+		require.NoError(t, sub.Stop())
+		require.NoError(t, <-errCh)
+	})
+}
+
+func TestNewSubscriber(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		client      sqssub.SQSAPI
+		queueURL    string
+		handler     sqssub.MessageHandler
+		expectError error
+	}{
+		{name: "valid configuration", client: newFakeSQS(), queueURL: testQueueURL, handler: noopHandler},
+		{name: "nil client", client: nil, queueURL: testQueueURL, handler: noopHandler,
+			expectError: sqssub.ErrNilSQSClient},
+		{name: "empty queue URL", client: newFakeSQS(), queueURL: "", handler: noopHandler,
+			expectError: sqssub.ErrEmptyQueueURL},
+		{name: "nil handler", client: newFakeSQS(), queueURL: testQueueURL, handler: nil,
+			expectError: sqssub.ErrNilMessageHandler},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			sub, err := sqssub.NewSubscriber(tt.client, tt.queueURL, tt.handler)
+			if tt.expectError != nil {
+				require.ErrorIs(t, err, tt.expectError)
+				require.Nil(t, sub)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, "sqssub-orders", sub.Name())
+			require.Equal(t, "orders", sub.Topic())
+			require.Equal(t, testQueueURL, sub.QueueURL())
+			require.False(t, sub.IsHealthy())
+			require.NoError(t, sub.Stop(), "stopping a subscriber that is not running is a no-op")
+		})
+	}
+}
+
+func TestSubscriber_DeletesProcessedMessages(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeSQS("first", "second", "third")
+
+	var (
+		mu       sync.Mutex
+		received []string
+	)
+
+	sub, err := sqssub.NewSubscriber(client, testQueueURL, func(_ context.Context, payload message.Payload) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		received = append(received, string(payload))
+
+		if string(payload) == "second" {
+			return errHandlerFailed
+		}
+
+		return nil
+	}, sqssub.WithRetryDelay(5*time.Second))
+	require.NoError(t, err)
+	startSubscriber(t, sub)
+
+	require.ErrorIs(t, sub.Start(t.Context()), sqssub.ErrAlreadyRunning)
+
+	require.Eventually(t, func() bool {
+		return len(client.deletedHandles()) == 2
+	}, testTimeout, 10*time.Millisecond)
+
+	require.ElementsMatch(t, []string{"receipt-0", "receipt-2"}, client.deletedHandles())
+	require.Equal(t, []int32{5}, client.visibilityChanges("receipt-1"),
+		"a failed message becomes visible again after the retry delay")
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	require.ElementsMatch(t, []string{"first", "second", "third"}, received)
+}
+
+func TestSubscriber_ExtendsVisibilityWhileHandling(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeSQS("slow")
+
+	sub, err := sqssub.NewSubscriber(client, testQueueURL, func(context.Context, message.Payload) error {
+		time.Sleep(2500 * time.Millisecond)
+
+		return nil
+	}, sqssub.WithVisibilityTimeout(2*time.Second))
+	require.NoError(t, err)
+	startSubscriber(t, sub)
+
+	require.Eventually(t, func() bool {
+		return len(client.deletedHandles()) == 1
+	}, testTimeout, 10*time.Millisecond)
+
+	changes := client.visibilityChanges("receipt-0")
+	require.NotEmpty(t, changes)
+
+	for _, change := range changes {
+		require.EqualValues(t, 2, change)
+	}
+}
+
+func TestSubscriber_UnwrapsSNSEnvelope(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeSQS(
+		`{"Type":"Notification","MessageId":"1","TopicArn":"arn:aws:sns:eu-west-1:123456789012:orders",`+
+			`"Message":"{\"id\":1}"}`,
+		`{"id":2}`,
+	)
+
+	received := make(chan string, 2)
+
+	sub, err := sqssub.NewSubscriber(client, testQueueURL, func(_ context.Context, payload message.Payload) error {
+		received <- string(payload)
+
+		return nil
+	}, sqssub.WithSNSEnvelope(), sqssub.WithMaxMessages(1))
+	require.NoError(t, err)
+	startSubscriber(t, sub)
+
+	select {
+	case got := <-received:
+		require.JSONEq(t, `{"id":1}`, got)
+	case <-time.After(testTimeout):
+		t.Fatal("the notification was not delivered")
+	}
+
+	require.Eventually(t, func() bool {
+		return len(client.visibilityChanges("receipt-1")) == 1
+	}, testTimeout, 10*time.Millisecond, "a message without an envelope fails")
+	require.Equal(t, []string{"receipt-0"}, client.deletedHandles())
+}
+
+func TestRedriveHelpers(t *testing.T) {
+	t.Parallel()
+
+	client := newFakeSQS()
+
+	arn, err := sqssub.QueueARN(t.Context(), client, testQueueURL)
+	require.NoError(t, err)
+	require.Equal(t, "arn:aws:sqs:eu-west-1:123456789012:orders", arn)
+
+	require.ErrorIs(t, sqssub.ConfigureDeadLetterQueue(t.Context(), client, testQueueURL, "dlq", 0),
+		sqssub.ErrInvalidMaxReceiveCount)
+
+	dlqARN := "arn:aws:sqs:eu-west-1:123456789012:orders-dlq"
+	require.NoError(t, sqssub.ConfigureDeadLetterQueue(t.Context(), client, testQueueURL, dlqARN, 5))
+	require.JSONEq(t, `{"deadLetterTargetArn":"`+dlqARN+`","maxReceiveCount":"5"}`,
+		client.attributes[string(types.QueueAttributeNameRedrivePolicy)])
+
+	handle, err := sqssub.Redrive(t.Context(), client, dlqARN, "", 50)
+	require.NoError(t, err)
+	require.Equal(t, "task-1", handle)
+	require.Equal(t, dlqARN, aws.ToString(client.moveInput.SourceArn))
+	require.Nil(t, client.moveInput.DestinationArn, "messages go back to their source queues")
+	require.EqualValues(t, 50, aws.ToInt32(client.moveInput.MaxNumberOfMessagesPerSecond))
+
+	tasks, err := sqssub.RedriveTasks(t.Context(), client, dlqARN)
+	require.NoError(t, err)
+	require.Equal(t, []sqssub.RedriveTask{{
+		Handle:         "task-1",
+		Status:         "RUNNING",
+		SourceARN:      dlqARN,
+		MessagesMoved:  3,
+		MessagesToMove: 5,
+	}}, tasks)
+}
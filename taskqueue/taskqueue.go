@@ -22,18 +22,29 @@
 //
 // Tasks are stored in Redis as a list (main queue) and a sorted set (processing set with timestamps).
 // Worker failures are detected via a configurable timeout on the processing set entries.
+//
+// Push stores the trace context of ctx with each task, and the worker runs the executor in a consumer
+// span continuing that trace. Both are no-ops until a tracer provider and propagator are installed, e.g.
+// by tracing.New.
 package taskqueue
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/andyle182810/gframework/clock"
 	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -41,6 +52,9 @@ const (
 	defaultBufferSize   = 100
 	defaultExecTimeout  = 30 * time.Second
 	defaultPollInterval = time.Second
+	defaultRetryAfter   = 5 * time.Second
+
+	instrumentationName = "github.com/andyle182810/gframework/taskqueue"
 )
 
 var (
@@ -50,6 +64,7 @@ var (
 	ErrNilExecutor         = errors.New("taskqueue: task executor is nil")
 	ErrEmptyQueueKey       = errors.New("taskqueue: queue key is empty")
 	ErrMaxAgeTooSmall      = errors.New("taskqueue: maxAge must be greater than execTimeout")
+	ErrQueueFull           = errors.New("taskqueue: queue has reached its maximum length")
 )
 
 type Payload []byte
@@ -66,6 +81,7 @@ type Executor interface {
 type taskItem struct {
 	id      string
 	payload Payload
+	carrier propagation.MapCarrier // Trace context of the Push call, nil when not traced.
 }
 
 type Queue struct {
@@ -73,16 +89,21 @@ type Queue struct {
 	queueKey      string
 	processingKey string
 	payloadKey    string
+	traceKey      string
 	executor      Executor
 	workerCount   int
 	bufferSize    int
 	execTimeout   time.Duration
 	pollInterval  time.Duration
+	highWatermark int64
+	retryAfter    time.Duration
+	maxLength     int64
 	taskChan      chan taskItem
 	wg            sync.WaitGroup
 	cancel        context.CancelFunc
 	mu            sync.Mutex
 	running       atomic.Bool
+	clock         clock.Clock
 }
 
 type Option func(*Queue)
@@ -105,15 +126,20 @@ func New(client redis.UniversalClient, queueKey string, executor Executor, opts
 		queueKey:      queueKey,
 		processingKey: queueKey + ":processing",
 		payloadKey:    queueKey + ":payloads",
+		traceKey:      queueKey + ":traces",
 		executor:      executor,
 		workerCount:   defaultWorkerCount,
 		bufferSize:    defaultBufferSize,
 		execTimeout:   defaultExecTimeout,
 		pollInterval:  defaultPollInterval,
+		highWatermark: 0,
+		retryAfter:    defaultRetryAfter,
+		maxLength:     0,
 		taskChan:      make(chan taskItem, defaultBufferSize),
 		wg:            sync.WaitGroup{},
 		cancel:        nil,
 		mu:            sync.Mutex{},
+		clock:         clock.Real(),
 	}
 
 	for _, opt := range opts {
@@ -156,12 +182,61 @@ func WithPollInterval(interval time.Duration) Option {
 	}
 }
 
+// WithBackpressure makes ShouldThrottle report true once Depth reaches highWatermark, advising
+// producers to back off for retryAfter.
+func WithBackpressure(highWatermark int64, retryAfter time.Duration) Option {
+	return func(q *Queue) {
+		if highWatermark > 0 {
+			q.highWatermark = highWatermark
+		}
+
+		if retryAfter > 0 {
+			q.retryAfter = retryAfter
+		}
+	}
+}
+
+// WithMaxLength makes Push reject tasks with ErrQueueFull when the pending list would exceed maxLength.
+// The check is not atomic with the push, so concurrent producers may overshoot slightly.
+func WithMaxLength(maxLength int64) Option {
+	return func(q *Queue) {
+		if maxLength > 0 {
+			q.maxLength = maxLength
+		}
+	}
+}
+
+// WithClock sets the clock used for retry waits and task timestamps. It defaults to clock.Real.
+func WithClock(clk clock.Clock) Option {
+	return func(q *Queue) {
+		if clk != nil {
+			q.clock = clk
+		}
+	}
+}
+
 func (q *Queue) Push(ctx context.Context, tasks ...Task) error {
 	if len(tasks) == 0 {
 		return nil
 	}
 
-	_, err := q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+	if q.maxLength > 0 {
+		length, err := q.QueueLength(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get queue length: %w", err)
+		}
+
+		if length+int64(len(tasks)) > q.maxLength {
+			return fmt.Errorf("%w: %d pending, max %d", ErrQueueFull, length, q.maxLength)
+		}
+	}
+
+	traceContext, err := encodeTraceContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
 		queueArgs := make([]any, len(tasks))
 		for i, task := range tasks {
 			queueArgs[i] = task.ID
@@ -173,6 +248,10 @@ func (q *Queue) Push(ctx context.Context, tasks ...Task) error {
 			if len(task.Payload) > 0 {
 				pipe.HSet(ctx, q.payloadKey, task.ID, []byte(task.Payload))
 			}
+
+			if traceContext != nil {
+				pipe.HSet(ctx, q.traceKey, task.ID, traceContext)
+			}
 		}
 
 		return nil
@@ -248,7 +327,7 @@ func (q *Queue) fetcher(ctx context.Context) {
 
 				if !errors.Is(err, redis.Nil) {
 					log.Error().Str("source", "gframework").Err(err).Msg("Failed to fetch task")
-					time.Sleep(q.pollInterval)
+					q.clock.Sleep(q.pollInterval)
 				}
 
 				continue
@@ -275,15 +354,21 @@ func (q *Queue) fetchTask(ctx context.Context) (taskItem, error) {
 
 	taskID := result[1] // result[0] is the key name
 
-	// Fetch payload from hash (if exists)
-	payloadBytes, err := q.client.HGet(ctx, q.payloadKey, taskID).Bytes()
-	if err != nil && !errors.Is(err, redis.Nil) {
+	// Fetch payload and trace context from their hashes (if they exist)
+	pipe := q.client.Pipeline()
+	payloadCmd := pipe.HGet(ctx, q.payloadKey, taskID)
+	traceCmd := pipe.HGet(ctx, q.traceKey, taskID)
+
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
 		q.returnTask(ctx, taskID)
 
 		return taskItem{}, fmt.Errorf("failed to get payload: %w", err)
 	}
 
-	now := float64(time.Now().Unix())
+	payloadBytes, _ := payloadCmd.Bytes()
+	carrier := decodeTraceContext(traceCmd.Val())
+
+	now := float64(q.clock.Now().Unix())
 
 	err = q.client.ZAdd(ctx, q.processingKey, redis.Z{
 		Score:  now,
@@ -298,6 +383,7 @@ func (q *Queue) fetchTask(ctx context.Context) (taskItem, error) {
 	return taskItem{
 		id:      taskID,
 		payload: payloadBytes,
+		carrier: carrier,
 	}, nil
 }
 
@@ -337,12 +423,18 @@ func (q *Queue) processTask(ctx context.Context, workerID int, task taskItem) {
 		Str("task_id", task.id).
 		Msg("Processing task")
 
-	execCtx, cancel := context.WithTimeout(ctx, q.execTimeout)
+	spanCtx, span := q.startProcessSpan(ctx, task)
+	defer span.End()
+
+	execCtx, cancel := context.WithTimeout(spanCtx, q.execTimeout)
 	defer cancel()
 
 	err := q.executor.Execute(execCtx, task.id, task.payload)
 
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
 		if errors.Is(execCtx.Err(), context.DeadlineExceeded) {
 			log.Error().
 				Str("source", "gframework").
@@ -376,6 +468,58 @@ func (q *Queue) processTask(ctx context.Context, workerID int, task taskItem) {
 			log.Error().Str("source", "gframework").Err(err).Str("task_id", task.id).Msg("Failed to delete payload")
 		}
 	}
+
+	if task.carrier != nil {
+		if err := q.client.HDel(ctx, q.traceKey, task.id).Err(); err != nil {
+			log.Error().Str("source", "gframework").Err(err).Str("task_id", task.id).Msg("Failed to delete trace context")
+		}
+	}
+}
+
+func (q *Queue) startProcessSpan(ctx context.Context, task taskItem) (context.Context, trace.Span) {
+	if task.carrier != nil {
+		ctx = otel.GetTextMapPropagator().Extract(ctx, task.carrier)
+	}
+
+	return otel.Tracer(instrumentationName).Start(ctx, "process "+q.queueKey, //nolint:spancheck
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKey.String("taskqueue"),
+			semconv.MessagingOperationTypeProcess,
+			semconv.MessagingDestinationName(q.queueKey),
+			semconv.MessagingMessageID(task.id),
+		),
+	)
+}
+
+// encodeTraceContext returns the trace context of ctx as JSON, or nil when ctx carries none.
+func encodeTraceContext(ctx context.Context) ([]byte, error) {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+
+	if len(carrier) == 0 {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(carrier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode trace context: %w", err)
+	}
+
+	return data, nil
+}
+
+func decodeTraceContext(data string) propagation.MapCarrier {
+	if data == "" {
+		return nil
+	}
+
+	var carrier propagation.MapCarrier
+	if err := json.Unmarshal([]byte(data), &carrier); err != nil {
+		return nil
+	}
+
+	return carrier
 }
 
 func (q *Queue) QueueLength(ctx context.Context) (int64, error) {
@@ -386,12 +530,53 @@ func (q *Queue) ProcessingCount(ctx context.Context) (int64, error) {
 	return q.client.ZCard(ctx, q.processingKey).Result()
 }
 
+// Depth returns the number of pending and in-flight tasks.
+func (q *Queue) Depth(ctx context.Context) (int64, error) {
+	var (
+		pending    *redis.IntCmd
+		processing *redis.IntCmd
+	)
+
+	_, err := q.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pending = pipe.LLen(ctx, q.queueKey)
+		processing = pipe.ZCard(ctx, q.processingKey)
+
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get queue depth: %w", err)
+	}
+
+	return pending.Val() + processing.Val(), nil
+}
+
+// ShouldThrottle reports whether producers should back off and for how long. It fails open when the
+// depth cannot be read so that a Redis hiccup does not reject all traffic.
+func (q *Queue) ShouldThrottle(ctx context.Context) (bool, time.Duration) {
+	if q.highWatermark <= 0 {
+		return false, 0
+	}
+
+	depth, err := q.Depth(ctx)
+	if err != nil {
+		log.Warn().Str("source", "gframework").Err(err).Str("queue", q.queueKey).Msg("Failed to check queue depth")
+
+		return false, 0
+	}
+
+	if depth < q.highWatermark {
+		return false, 0
+	}
+
+	return true, q.retryAfter
+}
+
 func (q *Queue) RecoverStale(ctx context.Context, maxAge time.Duration) (int, error) {
 	if maxAge <= q.execTimeout {
 		return 0, ErrMaxAgeTooSmall
 	}
 
-	cutoff := float64(time.Now().Add(-maxAge).Unix())
+	cutoff := float64(q.clock.Now().Add(-maxAge).Unix())
 
 	staleTasks, err := q.client.ZRangeByScore(ctx, q.processingKey, &redis.ZRangeBy{
 		Min:    "-inf",
@@ -27,16 +27,7 @@ func (m *mockExecutor) Execute(ctx context.Context, taskID string, payload taskq
 func setupTestQueue(t *testing.T) *valkey.Valkey {
 	t.Helper()
 
-	container := testutil.SetupValkeyContainer(t)
-
-	port, err := strconv.Atoi(container.Port.Port())
-	require.NoError(t, err)
-
-	valkeyClient, err := valkey.New(&valkey.Config{
-		Host: container.Host,
-		Port: port,
-	})
-	require.NoError(t, err)
+	valkeyClient := testutil.SetupValkey(t)
 
 	return valkeyClient
 }
@@ -475,3 +466,42 @@ func TestQueueOptions(t *testing.T) {
 	err = queue.Stop()
 	require.NoError(t, err)
 }
+
+func TestQueueBackpressure(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	valkeyClient := setupTestQueue(t)
+
+	executor := &mockExecutor{
+		fn: func(_ context.Context, _ string, _ taskqueue.Payload) error {
+			return nil
+		},
+	}
+
+	queue, err := taskqueue.New(
+		valkeyClient,
+		"test:backpressure",
+		executor,
+		taskqueue.WithBackpressure(2, 3*time.Second),
+		taskqueue.WithMaxLength(3),
+	)
+	require.NoError(t, err)
+
+	throttle, retryAfter := queue.ShouldThrottle(ctx)
+	require.False(t, throttle)
+	require.Zero(t, retryAfter)
+
+	require.NoError(t, queue.Push(ctx, taskqueue.Task{ID: "task-1"}, taskqueue.Task{ID: "task-2"}))
+
+	depth, err := queue.Depth(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), depth)
+
+	throttle, retryAfter = queue.ShouldThrottle(ctx)
+	require.True(t, throttle)
+	require.Equal(t, 3*time.Second, retryAfter)
+
+	require.NoError(t, queue.Push(ctx, taskqueue.Task{ID: "task-3"}))
+	require.ErrorIs(t, queue.Push(ctx, taskqueue.Task{ID: "task-4"}), taskqueue.ErrQueueFull)
+}
@@ -0,0 +1,434 @@
+package testutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/clock"
+	"github.com/andyle182810/gframework/postgres"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultChaosSeed = 1
+
+	// pgConnectionFailure is the SQLSTATE of connection_failure, which postgres.WithRetry retries by default.
+	pgConnectionFailure = "08006"
+)
+
+var (
+	ErrChaosInjected          = errors.New("testutil: chaos injected error")
+	ErrChaosConnectionDropped = errors.New("testutil: chaos dropped connection")
+)
+
+// ChaosConfig controls the faults a Chaos injects into every call. The zero value injects nothing.
+type ChaosConfig struct {
+	Latency   time.Duration // Added to every call
+	Jitter    time.Duration // Random extra latency in [0, Jitter)
+	ErrorRate float64       // Fraction of calls, in [0, 1], that fail with Err
+	DropRate  float64       // Fraction of calls, in [0, 1], that fail as if the connection dropped
+	Err       error         // Error returned for injected failures, ErrChaosInjected if nil
+	// Seed seeds the random source, so that a test sees the same sequence of faults on every run.
+	// 0 uses a fixed default seed.
+	Seed uint64
+	// Clock is used to wait for the latency; nil means clock.Real.
+	Clock clock.Clock
+}
+
+type chaosFault int
+
+const (
+	chaosNone chaosFault = iota
+	chaosError
+	chaosDrop
+)
+
+// Chaos decides which calls of the wrapped Valkey client or Postgres pool fail or slow down. Besides the
+// random rates of its ChaosConfig, tests can script exact faults with FailNext and DropNext. It is safe
+// for concurrent use.
+//
+//	chaos := testutil.NewChaos(testutil.ChaosConfig{ErrorRate: 0.2, Latency: 10 * time.Millisecond})
+//	testutil.AddRedisChaos(t, client, chaos)
+//	db := &postgres.Postgres{DBPool: testutil.NewChaosDBPool(pg.DBPool, chaos)}
+type Chaos struct {
+	mu       sync.Mutex
+	cfg      ChaosConfig
+	rng      *rand.Rand
+	failNext int
+	dropNext int
+	calls    int
+	faults   int
+}
+
+func NewChaos(cfg ChaosConfig) *Chaos {
+	chaos := &Chaos{} //nolint:exhaustruct
+	chaos.SetConfig(cfg)
+
+	return chaos
+}
+
+// SetConfig replaces the configuration and reseeds the random source, e.g. to heal the backend halfway
+// through a test with the zero ChaosConfig.
+func (c *Chaos) SetConfig(cfg ChaosConfig) {
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = defaultChaosSeed
+	}
+
+	cfg.Clock = clock.OrReal(cfg.Clock)
+
+	if cfg.Err == nil {
+		cfg.Err = ErrChaosInjected
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.cfg = cfg
+	c.rng = rand.New(rand.NewPCG(seed, seed)) //nolint:gosec
+}
+
+// FailNext makes the next n calls fail with the configured error, regardless of the rates.
+func (c *Chaos) FailNext(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.failNext = n
+}
+
+// DropNext makes the next n calls fail as if the connection dropped, regardless of the rates.
+func (c *Chaos) DropNext(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.dropNext = n
+}
+
+// Calls returns the number of calls seen so far.
+func (c *Chaos) Calls() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.calls
+}
+
+// Faults returns the number of calls that failed because of an injected error or drop.
+func (c *Chaos) Faults() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.faults
+}
+
+// inject waits for the configured latency and returns the fault for the current call, with errDrop
+// standing in for a dropped connection.
+func (c *Chaos) inject(ctx context.Context, errDrop error) error {
+	c.mu.Lock()
+	delay, fault := c.next()
+	errInjected, clk := c.cfg.Err, c.cfg.Clock
+	c.mu.Unlock()
+
+	if delay > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-clk.After(delay):
+		}
+	}
+
+	switch fault {
+	case chaosError:
+		return errInjected
+	case chaosDrop:
+		return errDrop
+	case chaosNone:
+	}
+
+	return nil
+}
+
+// next must be called with c.mu held.
+func (c *Chaos) next() (time.Duration, chaosFault) {
+	c.calls++
+
+	delay := c.cfg.Latency
+	if c.cfg.Jitter > 0 {
+		delay += time.Duration(c.rng.Int64N(int64(c.cfg.Jitter)))
+	}
+
+	fault := chaosNone
+
+	switch {
+	case c.dropNext > 0:
+		c.dropNext--
+		fault = chaosDrop
+	case c.failNext > 0:
+		c.failNext--
+		fault = chaosError
+	default:
+		// Draw once per call so the sequence of faults only depends on the seed and the number of calls.
+		roll := c.rng.Float64()
+
+		switch {
+		case roll < c.cfg.DropRate:
+			fault = chaosDrop
+		case roll < c.cfg.DropRate+c.cfg.ErrorRate:
+			fault = chaosError
+		}
+	}
+
+	if fault != chaosNone {
+		c.faults++
+	}
+
+	return delay, fault
+}
+
+// AddRedisChaos installs chaos as a hook on client, so that every command and pipeline goes through it.
+// Dropped connections fail with a *net.OpError wrapping ErrChaosConnectionDropped. Hooks cannot be
+// removed from a client, so the faults stop when the test ends instead.
+func AddRedisChaos(t *testing.T, client redis.UniversalClient, chaos *Chaos) {
+	t.Helper()
+
+	hook := &redisChaosHook{chaos: chaos, enabled: true, mu: sync.RWMutex{}}
+	client.AddHook(hook)
+
+	t.Cleanup(func() {
+		hook.mu.Lock()
+		defer hook.mu.Unlock()
+
+		hook.enabled = false
+	})
+}
+
+type redisChaosHook struct {
+	mu      sync.RWMutex
+	chaos   *Chaos
+	enabled bool
+}
+
+func (h *redisChaosHook) inject(ctx context.Context) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if !h.enabled {
+		return nil
+	}
+
+	//nolint:exhaustruct
+	return h.chaos.inject(ctx, &net.OpError{Op: "read", Net: "tcp", Err: ErrChaosConnectionDropped})
+}
+
+func (h *redisChaosHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h *redisChaosHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if err := h.inject(ctx); err != nil {
+			cmd.SetErr(err)
+
+			return err
+		}
+
+		return next(ctx, cmd)
+	}
+}
+
+func (h *redisChaosHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if err := h.inject(ctx); err != nil {
+			for _, cmd := range cmds {
+				cmd.SetErr(err)
+			}
+
+			return err
+		}
+
+		return next(ctx, cmds)
+	}
+}
+
+// ChaosDBPool is a postgres.DBPool that passes every call, including calls on transactions it begins,
+// through a Chaos before forwarding it. Dropped connections fail with a *pgconn.PgError with code 08006
+// (connection_failure), which postgres.WithRetry retries by default.
+type ChaosDBPool struct {
+	postgres.DBPool
+
+	chaos *Chaos
+}
+
+var _ postgres.DBPool = (*ChaosDBPool)(nil)
+
+func NewChaosDBPool(pool postgres.DBPool, chaos *Chaos) *ChaosDBPool {
+	return &ChaosDBPool{DBPool: pool, chaos: chaos}
+}
+
+func (p *ChaosDBPool) inject(ctx context.Context) error {
+	return injectPostgres(ctx, p.chaos)
+}
+
+func injectPostgres(ctx context.Context, chaos *Chaos) error {
+	//nolint:exhaustruct
+	return chaos.inject(ctx, &pgconn.PgError{
+		Severity: "FATAL",
+		Code:     pgConnectionFailure,
+		Message:  ErrChaosConnectionDropped.Error(),
+	})
+}
+
+func (p *ChaosDBPool) Begin(ctx context.Context) (pgx.Tx, error) { //nolint:ireturn
+	if err := p.inject(ctx); err != nil {
+		return nil, err
+	}
+
+	tx, err := p.DBPool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &chaosTx{Tx: tx, chaos: p.chaos}, nil
+}
+
+func (p *ChaosDBPool) BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error) { //nolint:ireturn
+	if err := p.inject(ctx); err != nil {
+		return nil, err
+	}
+
+	tx, err := p.DBPool.BeginTx(ctx, txOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &chaosTx{Tx: tx, chaos: p.chaos}, nil
+}
+
+func (p *ChaosDBPool) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	if err := p.inject(ctx); err != nil {
+		return pgconn.CommandTag{}, err
+	}
+
+	return p.DBPool.Exec(ctx, sql, arguments...)
+}
+
+func (p *ChaosDBPool) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) { //nolint:ireturn
+	if err := p.inject(ctx); err != nil {
+		return nil, err
+	}
+
+	return p.DBPool.Query(ctx, sql, args...)
+}
+
+func (p *ChaosDBPool) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row { //nolint:ireturn
+	if err := p.inject(ctx); err != nil {
+		return chaosRow{err: err}
+	}
+
+	return p.DBPool.QueryRow(ctx, sql, args...)
+}
+
+func (p *ChaosDBPool) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults { //nolint:ireturn
+	if err := p.inject(ctx); err != nil {
+		return chaosBatchResults{err: err}
+	}
+
+	return p.DBPool.SendBatch(ctx, b)
+}
+
+func (p *ChaosDBPool) CopyFrom(
+	ctx context.Context,
+	tableName pgx.Identifier,
+	columnNames []string,
+	rowSrc pgx.CopyFromSource,
+) (int64, error) {
+	if err := p.inject(ctx); err != nil {
+		return 0, err
+	}
+
+	return p.DBPool.CopyFrom(ctx, tableName, columnNames, rowSrc)
+}
+
+func (p *ChaosDBPool) Ping(ctx context.Context) error {
+	if err := p.inject(ctx); err != nil {
+		return err
+	}
+
+	return p.DBPool.Ping(ctx)
+}
+
+type chaosTx struct {
+	pgx.Tx
+
+	chaos *Chaos
+}
+
+func (tx *chaosTx) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	if err := injectPostgres(ctx, tx.chaos); err != nil {
+		return pgconn.CommandTag{}, err
+	}
+
+	return tx.Tx.Exec(ctx, sql, arguments...)
+}
+
+func (tx *chaosTx) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) { //nolint:ireturn
+	if err := injectPostgres(ctx, tx.chaos); err != nil {
+		return nil, err
+	}
+
+	return tx.Tx.Query(ctx, sql, args...)
+}
+
+func (tx *chaosTx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row { //nolint:ireturn
+	if err := injectPostgres(ctx, tx.chaos); err != nil {
+		return chaosRow{err: err}
+	}
+
+	return tx.Tx.QueryRow(ctx, sql, args...)
+}
+
+func (tx *chaosTx) Commit(ctx context.Context) error {
+	if err := injectPostgres(ctx, tx.chaos); err != nil {
+		// A failed commit leaves the transaction unusable, so release it like pgx does.
+		_ = tx.Tx.Rollback(ctx)
+
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	return tx.Tx.Commit(ctx)
+}
+
+type chaosRow struct {
+	err error
+}
+
+func (r chaosRow) Scan(...any) error {
+	return r.err
+}
+
+type chaosBatchResults struct {
+	err error
+}
+
+func (b chaosBatchResults) Exec() (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, b.err
+}
+
+func (b chaosBatchResults) Query() (pgx.Rows, error) { //nolint:ireturn
+	return nil, b.err
+}
+
+func (b chaosBatchResults) QueryRow() pgx.Row { //nolint:ireturn
+	return chaosRow(b)
+}
+
+func (b chaosBatchResults) Close() error {
+	return b.err
+}
@@ -0,0 +1,265 @@
+package testutil_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/postgres"
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+)
+
+var errChaosTest = errors.New("chaos test error")
+
+func noChaos() testutil.ChaosConfig {
+	return testutil.ChaosConfig{
+		Latency:   0,
+		Jitter:    0,
+		ErrorRate: 0,
+		DropRate:  0,
+		Err:       nil,
+		Seed:      0,
+		Clock:     nil,
+	}
+}
+
+type stubDBPool struct {
+	postgres.DBPool
+
+	pings int
+	tx    *stubTx
+}
+
+func newStubDBPool() *stubDBPool {
+	return &stubDBPool{DBPool: nil, pings: 0, tx: &stubTx{Tx: nil, committed: false, rolledBack: false}}
+}
+
+func (p *stubDBPool) Ping(context.Context) error {
+	p.pings++
+
+	return nil
+}
+
+func (p *stubDBPool) Exec(context.Context, string, ...any) (pgconn.CommandTag, error) {
+	return pgconn.NewCommandTag("UPDATE 1"), nil
+}
+
+func (p *stubDBPool) Begin(context.Context) (pgx.Tx, error) { //nolint:ireturn
+	return p.tx, nil
+}
+
+type stubTx struct {
+	pgx.Tx
+
+	committed  bool
+	rolledBack bool
+}
+
+func (tx *stubTx) Exec(context.Context, string, ...any) (pgconn.CommandTag, error) {
+	return pgconn.NewCommandTag("INSERT 0 1"), nil
+}
+
+func (tx *stubTx) Commit(context.Context) error {
+	tx.committed = true
+
+	return nil
+}
+
+func (tx *stubTx) Rollback(context.Context) error {
+	tx.rolledBack = true
+
+	return nil
+}
+
+func TestAddRedisChaos_InjectsScriptedFaults(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	client := testutil.NewFakeValkey(t).Client
+	chaos := testutil.NewChaos(noChaos())
+	testutil.AddRedisChaos(t, client, chaos)
+
+	require.NoError(t, client.Ping(ctx).Err())
+
+	chaos.FailNext(1)
+	require.ErrorIs(t, client.Ping(ctx).Err(), testutil.ErrChaosInjected)
+	require.NoError(t, client.Ping(ctx).Err(), "only the next call should fail")
+
+	chaos.DropNext(1)
+
+	err := client.Ping(ctx).Err()
+
+	var opErr *net.OpError
+	require.ErrorAs(t, err, &opErr)
+	require.ErrorIs(t, err, testutil.ErrChaosConnectionDropped)
+
+	chaos.FailNext(1)
+
+	pipe := client.Pipeline()
+	pipe.Ping(ctx)
+	_, err = pipe.Exec(ctx)
+	require.ErrorIs(t, err, testutil.ErrChaosInjected)
+
+	require.Equal(t, 3, chaos.Faults())
+}
+
+func TestAddRedisChaos_StopsWhenTestEnds(t *testing.T) {
+	t.Parallel()
+
+	client := testutil.NewFakeValkey(t).Client
+	chaos := testutil.NewChaos(noChaos())
+
+	t.Run("with chaos", func(t *testing.T) {
+		testutil.AddRedisChaos(t, client, chaos)
+
+		chaos.FailNext(1)
+		require.ErrorIs(t, client.Ping(t.Context()).Err(), testutil.ErrChaosInjected)
+	})
+
+	calls := chaos.Calls()
+
+	chaos.FailNext(1)
+	require.NoError(t, client.Ping(t.Context()).Err())
+	require.Equal(t, calls, chaos.Calls(), "the hook should be disabled after the test")
+}
+
+func TestChaos_RatesAndHeal(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	pool := newStubDBPool()
+
+	cfg := noChaos()
+	cfg.ErrorRate = 1
+	cfg.Err = errChaosTest
+
+	chaos := testutil.NewChaos(cfg)
+	db := testutil.NewChaosDBPool(pool, chaos)
+
+	for range 3 {
+		require.ErrorIs(t, db.Ping(ctx), errChaosTest)
+	}
+
+	require.Zero(t, pool.pings, "failed calls must not reach the pool")
+
+	cfg = noChaos()
+	cfg.DropRate = 1
+	chaos.SetConfig(cfg)
+
+	var pgErr *pgconn.PgError
+	require.ErrorAs(t, db.Ping(ctx), &pgErr)
+	require.Equal(t, "08006", pgErr.Code)
+
+	chaos.SetConfig(noChaos())
+	require.NoError(t, db.Ping(ctx))
+	require.Equal(t, 1, pool.pings)
+	require.Equal(t, 5, chaos.Calls())
+	require.Equal(t, 4, chaos.Faults())
+}
+
+func TestChaos_SeedRepeatsFaults(t *testing.T) {
+	t.Parallel()
+
+	cfg := noChaos()
+	cfg.ErrorRate = 0.5
+	cfg.Seed = 42
+
+	faults := func() []bool {
+		db := testutil.NewChaosDBPool(newStubDBPool(), testutil.NewChaos(cfg))
+
+		sequence := make([]bool, 0, 20)
+		for range 20 {
+			sequence = append(sequence, db.Ping(t.Context()) != nil)
+		}
+
+		return sequence
+	}
+
+	first := faults()
+	require.Equal(t, first, faults())
+	require.Contains(t, first, true)
+	require.Contains(t, first, false)
+}
+
+func TestChaos_Latency(t *testing.T) {
+	t.Parallel()
+
+	clk := testutil.NewFakeClock(t)
+
+	cfg := noChaos()
+	cfg.Latency = time.Second
+	cfg.Clock = clk
+
+	db := testutil.NewChaosDBPool(newStubDBPool(), testutil.NewChaos(cfg))
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- db.Ping(t.Context())
+	}()
+
+	clk.BlockUntil(1)
+
+	select {
+	case err := <-done:
+		t.Fatalf("ping returned before the latency elapsed: %v", err)
+	default:
+	}
+
+	clk.Advance(time.Second)
+	require.NoError(t, <-done)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	require.ErrorIs(t, db.Ping(ctx), context.Canceled)
+}
+
+func TestChaosDBPool_InjectsIntoEveryCall(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	pool := newStubDBPool()
+	chaos := testutil.NewChaos(noChaos())
+	db := testutil.NewChaosDBPool(pool, chaos)
+
+	chaos.FailNext(1)
+
+	_, err := db.Exec(ctx, "UPDATE orders SET status = 'paid'")
+	require.ErrorIs(t, err, testutil.ErrChaosInjected)
+
+	tag, err := db.Exec(ctx, "UPDATE orders SET status = 'paid'")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), tag.RowsAffected())
+
+	chaos.FailNext(1)
+	require.ErrorIs(t, db.QueryRow(ctx, "SELECT 1").Scan(), testutil.ErrChaosInjected)
+
+	chaos.FailNext(1)
+
+	results := db.SendBatch(ctx, &pgx.Batch{QueuedQueries: nil})
+	_, err = results.Exec()
+	require.ErrorIs(t, err, testutil.ErrChaosInjected)
+	require.ErrorIs(t, results.Close(), testutil.ErrChaosInjected)
+
+	tx, err := db.Begin(ctx)
+	require.NoError(t, err)
+
+	chaos.FailNext(1)
+
+	_, err = tx.Exec(ctx, "INSERT INTO orders DEFAULT VALUES")
+	require.ErrorIs(t, err, testutil.ErrChaosInjected)
+
+	chaos.DropNext(1)
+
+	var pgErr *pgconn.PgError
+	require.ErrorAs(t, tx.Commit(ctx), &pgErr)
+	require.Equal(t, "08006", pgErr.Code)
+	require.False(t, pool.tx.committed)
+	require.True(t, pool.tx.rolledBack, "a failed commit should roll back the transaction")
+}
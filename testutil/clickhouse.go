@@ -0,0 +1,104 @@
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const (
+	defaultClickHouseImage             = "clickhouse/clickhouse-server:25.8-alpine"
+	defaultClickHouseUser              = "testuser"
+	defaultClickHousePassword          = "testpass"
+	defaultClickHouseDatabase          = "testdb"
+	defaultClickHousePort     nat.Port = "9000/tcp"
+)
+
+type ClickHouseTestContainer struct {
+	Container testcontainers.Container
+	User      string
+	Password  string
+	Host      string
+	Database  string
+	Port      nat.Port
+}
+
+// ConnectionString returns a native protocol DSN for clickhouse.Config.URL and the migration functions.
+func (c *ClickHouseTestContainer) ConnectionString() string {
+	return fmt.Sprintf("clickhouse://%s:%s@%s/%s",
+		c.User, c.Password, net.JoinHostPort(c.Host, c.Port.Port()), c.Database)
+}
+
+// SetupClickHouseContainer starts a ClickHouse container for the test.
+func SetupClickHouseContainer(t *testing.T) *ClickHouseTestContainer {
+	t.Helper()
+
+	ctx := t.Context()
+
+	container, err := startClickHouseContainer(ctx)
+
+	t.Cleanup(func() {
+		if container != nil {
+			_ = container.Container.Terminate(ctx)
+		}
+	})
+
+	require.NoError(t, err)
+
+	return container
+}
+
+func startClickHouseContainer(ctx context.Context) (*ClickHouseTestContainer, error) {
+	//nolint:exhaustruct
+	req := testcontainers.ContainerRequest{
+		Image:        defaultClickHouseImage,
+		ExposedPorts: []string{string(defaultClickHousePort)},
+		Env: map[string]string{
+			"CLICKHOUSE_USER":     defaultClickHouseUser,
+			"CLICKHOUSE_PASSWORD": defaultClickHousePassword,
+			"CLICKHOUSE_DB":       defaultClickHouseDatabase,
+		},
+		WaitingFor: wait.ForListeningPort(defaultClickHousePort).WithStartupTimeout(startupTimeout),
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+		ProviderType:     testcontainers.ProviderDocker,
+		Logger:           &log.Logger,
+		Reuse:            false,
+	})
+	if container == nil {
+		return nil, err
+	}
+
+	result := &ClickHouseTestContainer{
+		Container: container,
+		User:      defaultClickHouseUser,
+		Password:  defaultClickHousePassword,
+		Host:      "",
+		Database:  defaultClickHouseDatabase,
+		Port:      "",
+	}
+
+	if err != nil {
+		return result, err
+	}
+
+	if result.Host, err = container.Host(ctx); err != nil {
+		return result, err
+	}
+
+	if result.Port, err = container.MappedPort(ctx, defaultClickHousePort); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
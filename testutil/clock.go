@@ -0,0 +1,247 @@
+package testutil
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/clock"
+)
+
+// FakeClock is a clock.Clock that only moves when the test advances it. Timers and tickers fire during
+// Advance, in order of their deadlines, with Now set to each deadline while firing.
+type FakeClock struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+var _ clock.Clock = (*FakeClock)(nil)
+
+// NewFakeClock returns a fake clock starting at the current time, so that timestamps it produces are
+// still accepted by code using the real clock, such as token servers.
+func NewFakeClock(t *testing.T) *FakeClock {
+	t.Helper()
+
+	return NewFakeClockAt(t, time.Now())
+}
+
+func NewFakeClockAt(t *testing.T, start time.Time) *FakeClock {
+	t.Helper()
+
+	c := &FakeClock{ //nolint:exhaustruct
+		now: start,
+	}
+	c.cond = sync.NewCond(&c.mu)
+
+	return c
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *FakeClock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+func (c *FakeClock) Until(t time.Time) time.Duration {
+	return t.Sub(c.Now())
+}
+
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+// Sleep blocks until the clock has been advanced by d.
+func (c *FakeClock) Sleep(d time.Duration) {
+	<-c.After(d)
+}
+
+func (c *FakeClock) NewTimer(d time.Duration) clock.Timer { //nolint:ireturn
+	return fakeTimer{fakeWaiter: c.addWaiter(d, 0)}
+}
+
+func (c *FakeClock) NewTicker(d time.Duration) clock.Ticker { //nolint:ireturn
+	return fakeTicker{fakeWaiter: c.addWaiter(d, d)}
+}
+
+// Advance moves the clock forward by d, firing the timers and tickers that become due.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.advanceTo(c.now.Add(d))
+}
+
+// Set moves the clock to t, firing the timers and tickers that become due. Moving backwards fires nothing.
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if t.Before(c.now) {
+		c.now = t
+
+		return
+	}
+
+	c.advanceTo(t)
+}
+
+// BlockUntil waits until at least n timers and tickers are pending, e.g. until a goroutine under test
+// is waiting on the clock, so that a following Advance is not missed.
+func (c *FakeClock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for len(c.waiters) < n {
+		c.cond.Wait()
+	}
+}
+
+func (c *FakeClock) advanceTo(target time.Time) {
+	for {
+		next := c.nextDue(target)
+		if next == nil {
+			break
+		}
+
+		c.now = next.at
+		next.fire()
+	}
+
+	c.now = target
+}
+
+func (c *FakeClock) nextDue(target time.Time) *fakeWaiter {
+	var next *fakeWaiter
+
+	for _, w := range c.waiters {
+		if w.at.After(target) {
+			continue
+		}
+
+		if next == nil || w.at.Before(next.at) {
+			next = w
+		}
+	}
+
+	return next
+}
+
+func (c *FakeClock) addWaiter(d, period time.Duration) *fakeWaiter {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	w := &fakeWaiter{
+		clock:  c,
+		at:     c.now.Add(d),
+		period: period,
+		ch:     make(chan time.Time, 1),
+	}
+
+	c.register(w)
+
+	if d <= 0 && period == 0 {
+		w.fire()
+	}
+
+	return w
+}
+
+// register and unregister must be called with c.mu held.
+func (c *FakeClock) register(w *fakeWaiter) {
+	c.waiters = append(c.waiters, w)
+	c.cond.Broadcast()
+}
+
+func (c *FakeClock) unregister(w *fakeWaiter) bool {
+	for i, waiter := range c.waiters {
+		if waiter == w {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			c.cond.Broadcast()
+
+			return true
+		}
+	}
+
+	return false
+}
+
+type fakeWaiter struct {
+	clock  *FakeClock
+	at     time.Time
+	period time.Duration
+	ch     chan time.Time
+}
+
+// fire must be called with the clock's mu held. Like time.Ticker, ticks are dropped for slow receivers.
+func (w *fakeWaiter) fire() {
+	select {
+	case w.ch <- w.clock.now:
+	default:
+	}
+
+	if w.period > 0 {
+		w.at = w.at.Add(w.period)
+
+		return
+	}
+
+	w.clock.unregister(w)
+}
+
+func (w *fakeWaiter) C() <-chan time.Time {
+	return w.ch
+}
+
+func (w *fakeWaiter) stop() bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+
+	return w.clock.unregister(w)
+}
+
+func (w *fakeWaiter) reset(d time.Duration) bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+
+	active := w.clock.unregister(w)
+
+	if w.period > 0 {
+		w.period = d
+	}
+
+	w.at = w.clock.now.Add(d)
+	w.clock.register(w)
+
+	return active
+}
+
+type fakeTimer struct {
+	*fakeWaiter
+}
+
+func (t fakeTimer) Stop() bool {
+	return t.stop()
+}
+
+func (t fakeTimer) Reset(d time.Duration) bool {
+	return t.reset(d)
+}
+
+type fakeTicker struct {
+	*fakeWaiter
+}
+
+func (t fakeTicker) Stop() {
+	t.stop()
+}
+
+func (t fakeTicker) Reset(d time.Duration) {
+	t.reset(d)
+}
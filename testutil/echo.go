@@ -34,6 +34,7 @@ func SetupEchoContext(
 
 	iecho := echo.New()
 	iecho.Validator = validator.DefaultRestValidator()
+	iecho.Binder = validator.NewEchoBinder()
 	iecho.HTTPErrorHandler = middleware.ErrorHandler(iecho.HTTPErrorHandler)
 
 	requestPath := opts.Path
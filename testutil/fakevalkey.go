@@ -0,0 +1,116 @@
+package testutil
+
+import (
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/andyle182810/gframework/valkey"
+	"github.com/stretchr/testify/require"
+)
+
+const fakeValkeyTick = 50 * time.Millisecond
+
+// StartFakeValkey starts an in-memory Redis-compatible server for the test. Keys expire as the wall clock
+// advances, like on a real server, and FastForward jumps ahead to avoid sleeping in tests. Streams, Lua
+// scripts and pub/sub are supported, but consumer group blocking and some server commands behave
+// differently from Valkey, so keep integration tests against SetupValkeyContainer.
+func StartFakeValkey(t *testing.T) *miniredis.Miniredis {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		ticker := time.NewTicker(fakeValkeyTick)
+		defer ticker.Stop()
+
+		last := time.Now()
+
+		for {
+			select {
+			case now := <-ticker.C:
+				server.FastForward(now.Sub(last))
+				last = now
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	t.Cleanup(func() {
+		close(done)
+		<-stopped
+	})
+
+	return server
+}
+
+// NewFakeValkey returns a client connected to a fresh StartFakeValkey server, for unit tests that must
+// run with -short or without Docker.
+func NewFakeValkey(t *testing.T) *valkey.Valkey {
+	t.Helper()
+
+	client, _ := NewFakeValkeyWithServer(t)
+
+	return client
+}
+
+// NewFakeValkeyWithServer is NewFakeValkey that also returns the server, e.g. to fast-forward TTLs.
+func NewFakeValkeyWithServer(t *testing.T) (*valkey.Valkey, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := StartFakeValkey(t)
+
+	host, rawPort, err := net.SplitHostPort(server.Addr())
+	require.NoError(t, err)
+
+	port, err := strconv.Atoi(rawPort)
+	require.NoError(t, err)
+
+	client, err := valkey.New(&valkey.Config{ //nolint:exhaustruct
+		Host: host,
+		Port: port,
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = client.Stop()
+	})
+
+	return client, server
+}
+
+// SetupValkey returns a client for the test: backed by NewFakeValkey when running with -short, and by
+// SetupValkeyContainer otherwise.
+func SetupValkey(t *testing.T, opts ...ContainerOption) *valkey.Valkey {
+	t.Helper()
+
+	if testing.Short() {
+		return NewFakeValkey(t)
+	}
+
+	container := SetupValkeyContainer(t, opts...)
+
+	port, err := strconv.Atoi(container.Port.Port())
+	require.NoError(t, err)
+
+	client, err := valkey.New(&valkey.Config{ //nolint:exhaustruct
+		Host: container.Host,
+		Port: port,
+		DB:   container.DB,
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = client.Stop()
+	})
+
+	return client
+}
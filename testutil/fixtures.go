@@ -0,0 +1,277 @@
+package testutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	errUnsupportedFixture = errors.New("unsupported fixture file")
+	errInvalidFixture     = errors.New("fixture must map table names to lists of rows")
+	errNotStruct          = errors.New("insert requires a struct type")
+)
+
+// Querier is implemented by pgx.Tx, pgxpool.Pool and postgres.Postgres.
+type Querier interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+type TxBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// BeginTestTx begins a transaction that is rolled back when the test ends, so fixtures and rows inserted
+// through it never leak into other tests. Code under test must use the returned transaction.
+func BeginTestTx(t *testing.T, db TxBeginner) pgx.Tx { //nolint:ireturn
+	t.Helper()
+
+	tx, err := db.Begin(t.Context())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = tx.Rollback(context.Background())
+	})
+
+	return tx
+}
+
+// LoadFixtures applies fixture files in order. SQL files are executed as is; YAML files map table names
+// to rows, inserted in file order so that referenced rows can come first:
+//
+//	users:
+//	  - id: 1
+//	    email: alice@example.com
+//	orders:
+//	  - user_id: 1
+//	    total: 1500
+func LoadFixtures(t *testing.T, db Querier, paths ...string) {
+	t.Helper()
+
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		switch filepath.Ext(path) {
+		case ".sql":
+			_, err = db.Exec(t.Context(), string(content))
+			require.NoError(t, err, "fixture %s", path)
+		case ".yaml", ".yml":
+			require.NoError(t, loadYAMLFixture(t.Context(), db, content), "fixture %s", path)
+		default:
+			require.Fail(t, errUnsupportedFixture.Error(), path)
+		}
+	}
+}
+
+func loadYAMLFixture(ctx context.Context, db Querier, content []byte) error {
+	var document yaml.Node
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return err
+	}
+
+	if len(document.Content) == 0 {
+		return nil
+	}
+
+	tables := document.Content[0]
+	if tables.Kind != yaml.MappingNode {
+		return errInvalidFixture
+	}
+
+	for i := 0; i+1 < len(tables.Content); i += 2 {
+		table := tables.Content[i].Value
+
+		var rows []map[string]any
+		if err := tables.Content[i+1].Decode(&rows); err != nil {
+			return fmt.Errorf("%w: %s: %w", errInvalidFixture, table, err)
+		}
+
+		for _, row := range rows {
+			columns := slices.Sorted(maps.Keys(row))
+
+			values := make([]any, len(columns))
+			for j, column := range columns {
+				values[j] = row[column]
+			}
+
+			if _, err := db.Exec(ctx, insertSQL(table, columns, nil), values...); err != nil {
+				return fmt.Errorf("insert into %s: %w", table, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Insert inserts rows into table and returns them as stored, with defaults and generated columns such as
+// ids filled in. Columns come from the db tags of T, the same tags pgx and scany scan by; fields without
+// a db tag are ignored. Zero-valued fields are left out so that column defaults apply.
+func Insert[T any](t *testing.T, db Querier, table string, rows ...T) []T {
+	t.Helper()
+
+	fields, err := dbFields(reflect.TypeFor[T]())
+	require.NoError(t, err)
+
+	returning := make([]string, len(fields))
+	for i, field := range fields {
+		returning[i] = field.column
+	}
+
+	inserted := make([]T, 0, len(rows))
+
+	for _, row := range rows {
+		value := reflect.ValueOf(row)
+
+		var (
+			columns []string
+			args    []any
+		)
+
+		for _, field := range fields {
+			fieldValue := value.FieldByIndex(field.index)
+			if fieldValue.IsZero() {
+				continue
+			}
+
+			columns = append(columns, field.column)
+			args = append(args, fieldValue.Interface())
+		}
+
+		result, err := db.Query(t.Context(), insertSQL(table, columns, returning), args...)
+		require.NoError(t, err)
+
+		stored, err := pgx.CollectExactlyOneRow(result, pgx.RowToStructByNameLax[T])
+		require.NoError(t, err, "insert into %s", table)
+
+		inserted = append(inserted, stored)
+	}
+
+	return inserted
+}
+
+// Factory builds and inserts rows of T with unique defaults, e.g.
+//
+//	users := testutil.NewFactory("users", func(n int) User {
+//	    return User{Email: fmt.Sprintf("user%d@example.com", n), Name: "User"}
+//	})
+//	admin := users.Insert(t, tx, func(u *User) { u.Role = "admin" })
+type Factory[T any] struct {
+	table string
+	build func(n int) T
+	seq   atomic.Int64
+}
+
+// NewFactory returns a factory for table. build receives a sequence number unique per factory, for
+// values that must be unique.
+func NewFactory[T any](table string, build func(n int) T) *Factory[T] {
+	return &Factory[T]{
+		table: table,
+		build: build,
+		seq:   atomic.Int64{},
+	}
+}
+
+// Build returns a row with the defaults and overrides applied, without inserting it.
+func (f *Factory[T]) Build(overrides ...func(*T)) T { //nolint:ireturn
+	row := f.build(int(f.seq.Add(1)))
+
+	for _, override := range overrides {
+		override(&row)
+	}
+
+	return row
+}
+
+func (f *Factory[T]) Insert(t *testing.T, db Querier, overrides ...func(*T)) T { //nolint:ireturn
+	t.Helper()
+
+	return Insert(t, db, f.table, f.Build(overrides...))[0]
+}
+
+func (f *Factory[T]) InsertN(t *testing.T, db Querier, count int, overrides ...func(*T)) []T {
+	t.Helper()
+
+	rows := make([]T, count)
+	for i := range rows {
+		rows[i] = f.Build(overrides...)
+	}
+
+	return Insert(t, db, f.table, rows...)
+}
+
+type dbField struct {
+	column string
+	index  []int
+}
+
+func dbFields(typ reflect.Type) ([]dbField, error) {
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %s", errNotStruct, typ)
+	}
+
+	var fields []dbField
+
+	for _, field := range reflect.VisibleFields(typ) {
+		if !field.IsExported() {
+			continue
+		}
+
+		column := field.Tag.Get("db")
+		if column == "" || column == "-" {
+			continue
+		}
+
+		fields = append(fields, dbField{column: column, index: field.Index})
+	}
+
+	return fields, nil
+}
+
+func insertSQL(table string, columns, returning []string) string {
+	var query strings.Builder
+
+	query.WriteString("INSERT INTO ")
+	query.WriteString(pgx.Identifier(strings.Split(table, ".")).Sanitize())
+
+	if len(columns) == 0 {
+		query.WriteString(" DEFAULT VALUES")
+	} else {
+		placeholders := make([]string, len(columns))
+		for i := range columns {
+			placeholders[i] = "$" + strconv.Itoa(i+1)
+		}
+
+		query.WriteString(" (" + sanitizeColumns(columns) + ") VALUES (" + strings.Join(placeholders, ", ") + ")")
+	}
+
+	if len(returning) > 0 {
+		query.WriteString(" RETURNING " + sanitizeColumns(returning))
+	}
+
+	return query.String()
+}
+
+func sanitizeColumns(columns []string) string {
+	sanitized := make([]string, len(columns))
+	for i, column := range columns {
+		sanitized[i] = pgx.Identifier{column}.Sanitize()
+	}
+
+	return strings.Join(sanitized, ", ")
+}
@@ -0,0 +1,155 @@
+package testutil_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+)
+
+type fixtureUser struct {
+	ID    int64  `db:"id"`
+	Email string `db:"email"`
+	Role  string `db:"role"`
+	Note  string
+}
+
+type execCall struct {
+	sql  string
+	args []any
+}
+
+type recordingQuerier struct {
+	calls []execCall
+}
+
+func (q *recordingQuerier) Exec(_ context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	q.calls = append(q.calls, execCall{sql: sql, args: arguments})
+
+	return pgconn.NewCommandTag("INSERT 0 1"), nil
+}
+
+func (q *recordingQuerier) Query(context.Context, string, ...any) (pgx.Rows, error) { //nolint:ireturn
+	panic("recordingQuerier: unexpected Query")
+}
+
+type stubBeginner struct {
+	tx *stubTx
+}
+
+func (b stubBeginner) Begin(context.Context) (pgx.Tx, error) { //nolint:ireturn
+	return b.tx, nil
+}
+
+func writeFixture(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func TestLoadFixtures(t *testing.T) {
+	t.Parallel()
+
+	sqlFixture := writeFixture(t, "schema.sql", "CREATE TABLE users (id bigint)")
+	yamlFixture := writeFixture(t, "users.yaml", `
+users:
+  - id: 1
+    email: alice@example.com
+orders:
+  - user_id: 1
+    total: 1500
+`)
+
+	db := &recordingQuerier{calls: nil}
+	testutil.LoadFixtures(t, db, sqlFixture, yamlFixture)
+
+	require.Equal(t, []execCall{
+		{sql: "CREATE TABLE users (id bigint)", args: nil},
+		{sql: `INSERT INTO "users" ("email", "id") VALUES ($1, $2)`, args: []any{"alice@example.com", 1}},
+		{sql: `INSERT INTO "orders" ("total", "user_id") VALUES ($1, $2)`, args: []any{1500, 1}},
+	}, db.calls)
+}
+
+func TestBeginTestTx_RollsBackWhenTestEnds(t *testing.T) {
+	t.Parallel()
+
+	beginner := stubBeginner{tx: &stubTx{Tx: nil, committed: false, rolledBack: false}}
+
+	t.Run("with transaction", func(t *testing.T) {
+		tx := testutil.BeginTestTx(t, beginner)
+		require.Same(t, beginner.tx, tx)
+		require.False(t, beginner.tx.rolledBack)
+	})
+
+	require.True(t, beginner.tx.rolledBack)
+}
+
+func TestFactory_Build(t *testing.T) {
+	t.Parallel()
+
+	users := testutil.NewFactory("users", func(n int) fixtureUser {
+		return fixtureUser{ID: 0, Email: fmt.Sprintf("user%d@example.com", n), Role: "member", Note: ""}
+	})
+
+	first := users.Build()
+	admin := users.Build(func(u *fixtureUser) { u.Role = "admin" })
+
+	require.Equal(t, "user1@example.com", first.Email)
+	require.Equal(t, "member", first.Role)
+	require.Equal(t, "user2@example.com", admin.Email)
+	require.Equal(t, "admin", admin.Role)
+}
+
+func TestInsert(t *testing.T) {
+	t.Parallel()
+
+	container := testutil.SetupPostgresContainer(t)
+
+	conn, err := pgx.Connect(t.Context(), container.ConnectionString())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = conn.Close(context.Background())
+	})
+
+	_, err = conn.Exec(t.Context(), `CREATE TABLE users (
+		id bigserial PRIMARY KEY,
+		email text NOT NULL UNIQUE,
+		role text NOT NULL DEFAULT 'member'
+	)`)
+	require.NoError(t, err)
+
+	tx := testutil.BeginTestTx(t, conn)
+
+	inserted := testutil.Insert(t, tx, "users",
+		fixtureUser{ID: 0, Email: "alice@example.com", Role: "", Note: "ignored"},
+		fixtureUser{ID: 0, Email: "bob@example.com", Role: "admin", Note: ""},
+	)
+
+	require.Len(t, inserted, 2)
+	require.NotZero(t, inserted[0].ID)
+	require.Equal(t, "member", inserted[0].Role, "zero fields should take the column default")
+	require.Empty(t, inserted[0].Note)
+	require.Equal(t, "admin", inserted[1].Role)
+
+	users := testutil.NewFactory("users", func(n int) fixtureUser {
+		return fixtureUser{ID: 0, Email: fmt.Sprintf("user%d@example.com", n), Role: "", Note: ""}
+	})
+
+	rows := users.InsertN(t, tx, 3)
+	require.Len(t, rows, 3)
+	require.Equal(t, "user3@example.com", rows[2].Email)
+
+	var count int
+	require.NoError(t, tx.QueryRow(t.Context(), "SELECT count(*) FROM users").Scan(&count))
+	require.Equal(t, 5, count)
+}
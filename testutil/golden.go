@@ -0,0 +1,178 @@
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	goldenDirPerm  = 0o750
+	goldenFilePerm = 0o600
+
+	PlaceholderTimestamp = "<timestamp>"
+	PlaceholderUUID      = "<uuid>"
+	PlaceholderIgnored   = "<ignored>"
+)
+
+//nolint:gochecknoglobals
+var (
+	updateGolden = flag.Bool("update", false, "rewrite golden files with the actual output")
+
+	uuidPattern      = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	timestampPattern = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?$`)
+)
+
+type goldenConfig struct {
+	ignored  map[string]bool
+	keepUUID bool
+	keepTime bool
+}
+
+type GoldenOption func(*goldenConfig)
+
+// WithIgnoredFields replaces the values of the named object keys, at any depth, with PlaceholderIgnored,
+// e.g. for counters or generated codes that differ between runs.
+func WithIgnoredFields(keys ...string) GoldenOption {
+	return func(c *goldenConfig) {
+		for _, key := range keys {
+			c.ignored[key] = true
+		}
+	}
+}
+
+// WithUUIDs keeps UUID strings instead of replacing them with PlaceholderUUID.
+func WithUUIDs() GoldenOption {
+	return func(c *goldenConfig) {
+		c.keepUUID = true
+	}
+}
+
+// WithTimestamps keeps RFC 3339 timestamps instead of replacing them with PlaceholderTimestamp.
+func WithTimestamps() GoldenOption {
+	return func(c *goldenConfig) {
+		c.keepTime = true
+	}
+}
+
+// AssertGoldenJSON compares got with the snapshot in testdata/<name>.golden. got is a JSON document as
+// []byte, string or *httptest.ResponseRecorder, or any other value, which is encoded as JSON first.
+//
+// The document is normalized before comparing: keys are sorted, it is indented, and UUIDs and RFC 3339
+// timestamps are replaced with placeholders so that snapshots are stable across runs. Running the tests
+// with -update writes the snapshot instead:
+//
+//	go test ./... -run TestGetUser -update
+func AssertGoldenJSON(t *testing.T, name string, got any, opts ...GoldenOption) {
+	t.Helper()
+
+	cfg := goldenConfig{
+		ignored:  make(map[string]bool),
+		keepUUID: false,
+		keepTime: false,
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var document any
+
+	decoder := json.NewDecoder(bytes.NewReader(goldenJSONBytes(t, got)))
+	decoder.UseNumber()
+	require.NoError(t, decoder.Decode(&document), "Golden value should be valid JSON")
+
+	var normalized bytes.Buffer
+
+	encoder := json.NewEncoder(&normalized)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	require.NoError(t, encoder.Encode(cfg.normalize(document)))
+
+	assertGolden(t, name, normalized.Bytes())
+}
+
+// AssertGoldenFile compares the response body byte for byte with testdata/<name>.golden. Running the
+// tests with -update writes the body to the file instead.
+func AssertGoldenFile(t *testing.T, rec *httptest.ResponseRecorder, name string) {
+	t.Helper()
+	assertGolden(t, name, rec.Body.Bytes())
+}
+
+func goldenJSONBytes(t *testing.T, got any) []byte {
+	t.Helper()
+
+	switch value := got.(type) {
+	case []byte:
+		return value
+	case json.RawMessage:
+		return value
+	case string:
+		return []byte(value)
+	case *httptest.ResponseRecorder:
+		return value.Body.Bytes()
+	default:
+		data, err := json.Marshal(value)
+		require.NoError(t, err, "Failed to marshal golden value")
+
+		return data
+	}
+}
+
+func (c *goldenConfig) normalize(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		for key, field := range value {
+			if c.ignored[key] {
+				value[key] = PlaceholderIgnored
+
+				continue
+			}
+
+			value[key] = c.normalize(field)
+		}
+
+		return value
+	case []any:
+		for i, item := range value {
+			value[i] = c.normalize(item)
+		}
+
+		return value
+	case string:
+		switch {
+		case !c.keepUUID && uuidPattern.MatchString(value):
+			return PlaceholderUUID
+		case !c.keepTime && timestampPattern.MatchString(value):
+			return PlaceholderTimestamp
+		}
+
+		return value
+	default:
+		return value
+	}
+}
+
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+
+	if *updateGolden {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), goldenDirPerm))
+		require.NoError(t, os.WriteFile(path, got, goldenFilePerm))
+
+		return
+	}
+
+	want, err := os.ReadFile(path) //nolint:gosec
+	require.NoError(t, err, "Failed to read golden file, run the test with -update to create it")
+	require.Equal(t, string(want), string(got), "Output does not match golden file %s", path)
+}
@@ -4,10 +4,19 @@
 // It includes functions for:
 //
 //   - Setting up Valkey (Redis-compatible) and PostgreSQL Docker containers with automatic cleanup
+//   - An in-memory fake Valkey for unit tests run with -short or without Docker (NewFakeValkey, SetupValkey)
+//   - An in-memory redispub.Publisher that delivers to redissub handlers and captures dead letters (NewFakeBroker)
 //   - Creating cancellable contexts tied to test lifetimes
+//   - Serving httpserver routes on a random port with a matching httpclient (StartTestServer), and asserting
+//     responses against JSON or golden files (AssertJSONEqual, AssertGoldenFile)
+//   - Normalized JSON snapshots, rewritten with go test -update (AssertGoldenJSON)
 //   - Generating random test data (strings, integers, emails)
-//   - Running database migrations and cleanup
+//   - Running database migrations and cleanup, loading SQL/YAML fixtures and inserting rows with factories
+//     inside a per-test transaction (BeginTestTx, LoadFixtures, Insert, NewFactory)
 //   - Polling utilities for eventually consistent assertions
+//   - A fake clock.Clock that tests advance explicitly instead of sleeping (NewFakeClock)
+//   - Latency, error and connection-drop injection for Valkey clients and Postgres pools (NewChaos, AddRedisChaos,
+//     NewChaosDBPool)
 //
 // Basic usage:
 //
@@ -18,7 +27,9 @@
 //	// Use containers for integration testing
 //	client, _ := redis.NewClient(valkeyContainer.Address())
 //
-// Containers are automatically cleaned up when the test completes via t.Cleanup().
+// Containers are automatically cleaned up when the test completes via t.Cleanup(). Setting
+// GFRAMEWORK_TEST_REUSE=1 shares one container per package instead, isolating tests by database; see
+// EnvReuseContainers.
 package testutil
 
 import (
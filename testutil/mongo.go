@@ -0,0 +1,147 @@
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+	mongodriver "go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	defaultMongoImage             = "mongo:8"
+	defaultMongoDatabase          = "testdb"
+	defaultMongoPort     nat.Port = "27017/tcp"
+)
+
+// initiateReplicaSet turns the container into a single-member replica set, which transactions require,
+// and waits until it is writable.
+const initiateReplicaSet = `
+rs.initiate({_id: "rs0", members: [{_id: 0, host: "localhost:27017"}]});
+while (!db.hello().isWritablePrimary) { sleep(100); }
+`
+
+type MongoTestContainer struct {
+	Container testcontainers.Container
+	Host      string
+	Port      nat.Port
+	// Database is the database reserved for the test; always "testdb" unless EnvReuseContainers is set.
+	Database string
+}
+
+// URI connects directly to the container, as the replica set member is only known by its address
+// inside the container.
+func (c *MongoTestContainer) URI() string {
+	return fmt.Sprintf("mongodb://%s/%s?directConnection=true",
+		net.JoinHostPort(c.Host, c.Port.Port()), c.Database)
+}
+
+// SetupMongoContainer starts a MongoDB single-member replica set for the test, or reserves a database
+// for the test in the shared container when EnvReuseContainers is set.
+func SetupMongoContainer(t *testing.T, opts ...ContainerOption) *MongoTestContainer {
+	t.Helper()
+
+	if reuseContainers(opts) {
+		return setupSharedMongoDatabase(t)
+	}
+
+	ctx := t.Context()
+
+	container, err := startMongoContainer(ctx)
+
+	t.Cleanup(func() {
+		if container != nil {
+			_ = container.Container.Terminate(ctx)
+		}
+	})
+
+	require.NoError(t, err)
+
+	return container
+}
+
+func startMongoContainer(ctx context.Context) (*MongoTestContainer, error) {
+	//nolint:exhaustruct
+	req := testcontainers.ContainerRequest{
+		Image:        defaultMongoImage,
+		ExposedPorts: []string{string(defaultMongoPort)},
+		Cmd:          []string{"mongod", "--replSet", "rs0", "--bind_ip_all"},
+		WaitingFor:   wait.ForListeningPort(defaultMongoPort).WithStartupTimeout(startupTimeout),
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+		ProviderType:     testcontainers.ProviderDocker,
+		Logger:           &log.Logger,
+		Reuse:            false,
+	})
+	if container == nil {
+		return nil, err
+	}
+
+	result := &MongoTestContainer{
+		Container: container,
+		Host:      "",
+		Port:      "",
+		Database:  defaultMongoDatabase,
+	}
+
+	if err != nil {
+		return result, err
+	}
+
+	exitCode, _, err := container.Exec(ctx, []string{"mongosh", "--quiet", "--eval", initiateReplicaSet})
+	if err != nil {
+		return result, fmt.Errorf("failed to initiate replica set: %w", err)
+	}
+
+	if exitCode != 0 {
+		return result, fmt.Errorf("failed to initiate replica set: mongosh exited with %d", exitCode) //nolint:err113
+	}
+
+	if result.Host, err = container.Host(ctx); err != nil {
+		return result, err
+	}
+
+	if result.Port, err = container.MappedPort(ctx, defaultMongoPort); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+func setupSharedMongoDatabase(t *testing.T) *MongoTestContainer {
+	t.Helper()
+
+	shared, err := sharedMongo.get(startMongoContainer)
+	require.NoError(t, err)
+
+	isolated := *shared
+	isolated.Database = fmt.Sprintf("test_%d_%d", os.Getpid(), sharedDatabaseSeq.Add(1))
+
+	// MongoDB creates the database on first write, so there is only something to drop afterwards.
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), sharedCleanupTimeout)
+		defer cancel()
+
+		client, err := mongodriver.Connect(options.Client().ApplyURI(isolated.URI()))
+		if err != nil {
+			return
+		}
+
+		defer func() { _ = client.Disconnect(ctx) }()
+
+		_ = client.Database(isolated.Database).Drop(ctx)
+	})
+
+	return &isolated
+}
@@ -0,0 +1,102 @@
+package testutil
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const (
+	defaultNATSImage          = "nats:2-alpine"
+	defaultNATSPort  nat.Port = "4222/tcp"
+)
+
+type NATSTestContainer struct {
+	Container testcontainers.Container
+	Host      string
+	Port      nat.Port
+}
+
+// URL returns the client URL of the server.
+func (c *NATSTestContainer) URL() string {
+	return "nats://" + net.JoinHostPort(c.Host, c.Port.Port())
+}
+
+// Connect dials the server and closes the connection when the test ends.
+func (c *NATSTestContainer) Connect(t *testing.T) *nats.Conn {
+	t.Helper()
+
+	conn, err := nats.Connect(c.URL())
+	require.NoError(t, err)
+
+	t.Cleanup(conn.Close)
+
+	return conn
+}
+
+// SetupNATSContainer starts a NATS server with JetStream enabled for the test.
+func SetupNATSContainer(t *testing.T) *NATSTestContainer {
+	t.Helper()
+
+	ctx := t.Context()
+
+	container, err := startNATSContainer(ctx)
+
+	t.Cleanup(func() {
+		if container != nil {
+			_ = container.Container.Terminate(ctx)
+		}
+	})
+
+	require.NoError(t, err)
+
+	return container
+}
+
+func startNATSContainer(ctx context.Context) (*NATSTestContainer, error) {
+	//nolint:exhaustruct
+	req := testcontainers.ContainerRequest{
+		Image:        defaultNATSImage,
+		ExposedPorts: []string{string(defaultNATSPort)},
+		Cmd:          []string{"--jetstream"},
+		WaitingFor:   wait.ForLog("Server is ready").WithStartupTimeout(startupTimeout),
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+		ProviderType:     testcontainers.ProviderDocker,
+		Logger:           &log.Logger,
+		Reuse:            false,
+	})
+	if container == nil {
+		return nil, err
+	}
+
+	result := &NATSTestContainer{
+		Container: container,
+		Host:      "",
+		Port:      "",
+	}
+
+	if err != nil {
+		return result, err
+	}
+
+	if result.Host, err = container.Host(ctx); err != nil {
+		return result, err
+	}
+
+	if result.Port, err = container.MappedPort(ctx, defaultNATSPort); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
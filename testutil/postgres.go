@@ -52,11 +52,31 @@ func (c *PostgresTestContainer) DSN() string {
 		c.Host, c.Port.Port(), c.User, c.Password, c.Database)
 }
 
-func SetupPostgresContainer(t *testing.T) *PostgresTestContainer {
+// SetupPostgresContainer starts a Postgres container for the test, or creates a database for the test
+// in the shared container when EnvReuseContainers is set.
+func SetupPostgresContainer(t *testing.T, opts ...ContainerOption) *PostgresTestContainer {
 	t.Helper()
 
+	if reuseContainers(opts) {
+		return setupSharedPostgresDatabase(t)
+	}
+
 	ctx := t.Context()
 
+	container, err := startPostgresContainer(ctx)
+
+	t.Cleanup(func() {
+		if container != nil {
+			_ = container.Container.Terminate(ctx)
+		}
+	})
+
+	require.NoError(t, err)
+
+	return container
+}
+
+func startPostgresContainer(ctx context.Context) (*PostgresTestContainer, error) {
 	container, err := postgres.Run(
 		ctx,
 		defaultPostgresImage,
@@ -69,27 +89,32 @@ func SetupPostgresContainer(t *testing.T) *PostgresTestContainer {
 				WithStartupTimeout(startupTimeout),
 		),
 	)
+	if container == nil {
+		return nil, err
+	}
 
-	t.Cleanup(func() {
-		_ = container.Terminate(ctx)
-	})
-
-	require.NoError(t, err)
-
-	host, err := container.Host(ctx)
-	require.NoError(t, err)
-
-	mappedPort, err := container.MappedPort(ctx, defaultPostgresPort)
-	require.NoError(t, err)
-
-	return &PostgresTestContainer{
+	result := &PostgresTestContainer{
 		Container: container,
 		User:      defaultPostgresUser,
 		Password:  defaultPostgresPassword,
-		Host:      host,
+		Host:      "",
 		Database:  defaultPostgresDatabase,
-		Port:      mappedPort,
+		Port:      "",
+	}
+
+	if err != nil {
+		return result, err
+	}
+
+	if result.Host, err = container.Host(ctx); err != nil {
+		return result, err
 	}
+
+	if result.Port, err = container.MappedPort(ctx, defaultPostgresPort); err != nil {
+		return result, err
+	}
+
+	return result, nil
 }
 
 var errNoMigrationFile = errors.New("no migration files found")
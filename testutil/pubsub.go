@@ -0,0 +1,211 @@
+package testutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/andyle182810/gframework/redispub"
+	"github.com/andyle182810/gframework/redissub"
+)
+
+var ErrFakeBrokerClosed = errors.New("testutil: fake broker is closed")
+
+// DeadLetter is a message whose handler still failed after all retries.
+type DeadLetter struct {
+	Topic    string
+	DLQTopic string // Empty when the subscription has no DLQ topic.
+	Payload  string
+	Err      error
+}
+
+type fakeSubscription struct {
+	topic   string
+	handler redissub.MessageHandler
+	config  redissub.SubscriberConfig
+}
+
+// FakeBroker is an in-memory redispub.Publisher that delivers published messages to handlers subscribed
+// with the same redissub.MessageHandler and SubscriberOption types, so message handlers can be unit
+// tested without Valkey.
+//
+// Delivery is synchronous: PublishToTopic returns after every subscribed handler has run, including
+// retries, so tests can assert right after publishing. Retry delays are not waited for. Messages that
+// exhaust their retries are captured in DeadLetters.
+type FakeBroker struct {
+	mu            sync.Mutex
+	closed        bool
+	published     map[string][]string
+	subscriptions []*fakeSubscription
+	deadLetters   []DeadLetter
+}
+
+var _ redispub.Publisher = (*FakeBroker)(nil)
+
+func NewFakeBroker(t *testing.T) *FakeBroker {
+	t.Helper()
+
+	return &FakeBroker{ //nolint:exhaustruct
+		published: make(map[string][]string),
+	}
+}
+
+// Subscribe registers handler for topic like redissub.MultiSubscriber.Subscribe. Of opts, WithRetry,
+// WithExecTimeout and WithMetrics take effect.
+func (b *FakeBroker) Subscribe(topic string, handler redissub.MessageHandler, opts ...redissub.SubscriberOption) error {
+	if topic == "" {
+		return redissub.ErrEmptyTopic
+	}
+
+	if handler == nil {
+		return redissub.ErrNilMessageHandler
+	}
+
+	var config redissub.SubscriberConfig
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.subscriptions = append(b.subscriptions, &fakeSubscription{
+		topic:   topic,
+		handler: handler,
+		config:  config,
+	})
+
+	return nil
+}
+
+func (b *FakeBroker) PublishToTopic(ctx context.Context, topic string, messageContents ...string) error {
+	b.mu.Lock()
+
+	if b.closed {
+		b.mu.Unlock()
+
+		return fmt.Errorf("%w: %w to topic %s", redispub.ErrPublishFailed, ErrFakeBrokerClosed, topic)
+	}
+
+	b.published[topic] = append(b.published[topic], messageContents...)
+
+	var subscribers []*fakeSubscription
+
+	for _, sub := range b.subscriptions {
+		if sub.topic == topic {
+			subscribers = append(subscribers, sub)
+		}
+	}
+
+	b.mu.Unlock()
+
+	for _, content := range messageContents {
+		for _, sub := range subscribers {
+			b.deliver(ctx, sub, content)
+		}
+	}
+
+	return nil
+}
+
+func (b *FakeBroker) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+
+	return nil
+}
+
+// Published returns the messages published to topic, in order.
+func (b *FakeBroker) Published(topic string) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]string(nil), b.published[topic]...)
+}
+
+// DeadLetters returns the messages whose handlers failed after all retries, in order.
+func (b *FakeBroker) DeadLetters() []DeadLetter {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]DeadLetter(nil), b.deadLetters...)
+}
+
+// Reset forgets published messages and dead letters but keeps the subscriptions.
+func (b *FakeBroker) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.published = make(map[string][]string)
+	b.deadLetters = nil
+}
+
+func (b *FakeBroker) deliver(ctx context.Context, sub *fakeSubscription, content string) {
+	metrics := sub.config.Metrics
+
+	if metrics != nil {
+		metrics.MessageReceived(sub.topic)
+	}
+
+	attempts := 1
+	if sub.config.Retry != nil && sub.config.Retry.MaxRetries > 0 {
+		attempts += sub.config.Retry.MaxRetries
+	}
+
+	startedAt := time.Now()
+
+	var err error
+
+	for range attempts {
+		if err = sub.run(ctx, content); err == nil {
+			break
+		}
+	}
+
+	if metrics != nil {
+		metrics.MessageProcessed(sub.topic, time.Since(startedAt), err)
+	}
+
+	if err == nil {
+		if metrics != nil {
+			metrics.MessageAcked(sub.topic)
+		}
+
+		return
+	}
+
+	letter := DeadLetter{Topic: sub.topic, DLQTopic: "", Payload: content, Err: err}
+	if sub.config.Retry != nil {
+		letter.DLQTopic = sub.config.Retry.DLQTopic
+	}
+
+	b.mu.Lock()
+	b.deadLetters = append(b.deadLetters, letter)
+	b.mu.Unlock()
+
+	if metrics != nil {
+		if letter.DLQTopic != "" {
+			metrics.MessageSentToDLQ(sub.topic)
+		}
+
+		metrics.MessageNacked(sub.topic)
+	}
+}
+
+func (s *fakeSubscription) run(ctx context.Context, content string) error {
+	if s.config.ExecTimeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, s.config.ExecTimeout)
+		defer cancel()
+	}
+
+	return s.handler(ctx, message.Payload(content))
+}
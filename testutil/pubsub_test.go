@@ -0,0 +1,107 @@
+package testutil_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/andyle182810/gframework/redispub"
+	"github.com/andyle182810/gframework/redissub"
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+var errHandlerFailed = errors.New("handler failed")
+
+func TestFakeBroker_DeliversToSubscribersOfTopic(t *testing.T) {
+	t.Parallel()
+
+	broker := testutil.NewFakeBroker(t)
+
+	var orders, payments []string
+
+	require.NoError(t, broker.Subscribe("orders", func(_ context.Context, payload message.Payload) error {
+		orders = append(orders, string(payload))
+
+		return nil
+	}))
+	require.NoError(t, broker.Subscribe("payments", func(_ context.Context, payload message.Payload) error {
+		payments = append(payments, string(payload))
+
+		return nil
+	}))
+
+	require.NoError(t, broker.PublishToTopic(t.Context(), "orders", "order-1", "order-2"))
+
+	require.Equal(t, []string{"order-1", "order-2"}, orders)
+	require.Empty(t, payments)
+	require.Equal(t, []string{"order-1", "order-2"}, broker.Published("orders"))
+	require.Empty(t, broker.DeadLetters())
+}
+
+func TestFakeBroker_RetriesAndCapturesDeadLetters(t *testing.T) {
+	t.Parallel()
+
+	broker := testutil.NewFakeBroker(t)
+
+	attempts := 0
+
+	require.NoError(t, broker.Subscribe("orders", func(context.Context, message.Payload) error {
+		attempts++
+
+		return errHandlerFailed
+	}, redissub.WithRetry(2, time.Hour, "orders.dlq")))
+
+	require.NoError(t, broker.PublishToTopic(t.Context(), "orders", "order-1"))
+
+	require.Equal(t, 3, attempts, "the retry delay should not be waited for")
+	require.Equal(t, []testutil.DeadLetter{{
+		Topic:    "orders",
+		DLQTopic: "orders.dlq",
+		Payload:  "order-1",
+		Err:      errHandlerFailed,
+	}}, broker.DeadLetters())
+
+	broker.Reset()
+	require.Empty(t, broker.Published("orders"))
+	require.Empty(t, broker.DeadLetters())
+
+	require.NoError(t, broker.PublishToTopic(t.Context(), "orders", "order-2"))
+	require.Equal(t, 6, attempts, "Reset should keep the subscriptions")
+}
+
+func TestFakeBroker_ExecTimeout(t *testing.T) {
+	t.Parallel()
+
+	broker := testutil.NewFakeBroker(t)
+
+	var hasDeadline bool
+
+	require.NoError(t, broker.Subscribe("orders", func(ctx context.Context, _ message.Payload) error {
+		_, hasDeadline = ctx.Deadline()
+
+		return nil
+	}, redissub.WithExecTimeout(time.Second)))
+
+	require.NoError(t, broker.PublishToTopic(t.Context(), "orders", "order-1"))
+	require.True(t, hasDeadline)
+}
+
+func TestFakeBroker_Errors(t *testing.T) {
+	t.Parallel()
+
+	broker := testutil.NewFakeBroker(t)
+
+	require.ErrorIs(t, broker.Subscribe("", func(context.Context, message.Payload) error { return nil }),
+		redissub.ErrEmptyTopic)
+	require.ErrorIs(t, broker.Subscribe("orders", nil), redissub.ErrNilMessageHandler)
+
+	require.NoError(t, broker.Close())
+
+	err := broker.PublishToTopic(t.Context(), "orders", "order-1")
+	require.ErrorIs(t, err, redispub.ErrPublishFailed)
+	require.ErrorIs(t, err, testutil.ErrFakeBrokerClosed)
+	require.Empty(t, broker.Published("orders"))
+}
@@ -0,0 +1,114 @@
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/docker/go-connections/nat"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const (
+	defaultRabbitMQImage             = "rabbitmq:4-alpine"
+	defaultRabbitMQUser              = "testuser"
+	defaultRabbitMQPassword          = "testpass"
+	defaultRabbitMQPort     nat.Port = "5672/tcp"
+)
+
+type RabbitMQTestContainer struct {
+	Container testcontainers.Container
+	User      string
+	Password  string
+	Host      string
+	Port      nat.Port
+}
+
+// URL returns the AMQP URL of the broker's default virtual host.
+func (c *RabbitMQTestContainer) URL() string {
+	return fmt.Sprintf("amqp://%s:%s@%s/", c.User, c.Password, net.JoinHostPort(c.Host, c.Port.Port()))
+}
+
+// Connect dials the broker and closes the connection when the test ends.
+func (c *RabbitMQTestContainer) Connect(t *testing.T) *amqp.Connection {
+	t.Helper()
+
+	conn, err := amqp.Dial(c.URL())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return conn
+}
+
+// SetupRabbitMQContainer starts a RabbitMQ container for the test.
+func SetupRabbitMQContainer(t *testing.T) *RabbitMQTestContainer {
+	t.Helper()
+
+	ctx := t.Context()
+
+	container, err := startRabbitMQContainer(ctx)
+
+	t.Cleanup(func() {
+		if container != nil {
+			_ = container.Container.Terminate(ctx)
+		}
+	})
+
+	require.NoError(t, err)
+
+	return container
+}
+
+func startRabbitMQContainer(ctx context.Context) (*RabbitMQTestContainer, error) {
+	//nolint:exhaustruct
+	req := testcontainers.ContainerRequest{
+		Image:        defaultRabbitMQImage,
+		ExposedPorts: []string{string(defaultRabbitMQPort)},
+		Env: map[string]string{
+			"RABBITMQ_DEFAULT_USER": defaultRabbitMQUser,
+			"RABBITMQ_DEFAULT_PASS": defaultRabbitMQPassword,
+		},
+		WaitingFor: wait.ForLog("Server startup complete").WithStartupTimeout(startupTimeout),
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+		ProviderType:     testcontainers.ProviderDocker,
+		Logger:           &log.Logger,
+		Reuse:            false,
+	})
+	if container == nil {
+		return nil, err
+	}
+
+	result := &RabbitMQTestContainer{
+		Container: container,
+		User:      defaultRabbitMQUser,
+		Password:  defaultRabbitMQPassword,
+		Host:      "",
+		Port:      "",
+	}
+
+	if err != nil {
+		return result, err
+	}
+
+	if result.Host, err = container.Host(ctx); err != nil {
+		return result, err
+	}
+
+	if result.Port, err = container.MappedPort(ctx, defaultRabbitMQPort); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
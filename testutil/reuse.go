@@ -0,0 +1,162 @@
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	sharedValkeyDatabases = 256
+	sharedCleanupTimeout  = 10 * time.Second
+)
+
+// EnvReuseContainers opts into shared containers, e.g. GFRAMEWORK_TEST_REUSE=1 go test ./...
+//
+// Instead of a container per test, every test of a package shares one Postgres, one Valkey and one
+// MongoDB container. SetupPostgresContainer and SetupMongoContainer create a database per test and drop
+// it afterwards, and SetupValkeyContainer hands out a free database index per test and flushes it
+// afterwards, so tests stay isolated as long as they connect to PostgresTestContainer.Database,
+// MongoTestContainer.Database and ValkeyTestContainer.DB.
+// Pub/sub channels and server settings are not isolated; tests relying on them should pass
+// WithDedicatedContainer. Shared containers are removed by the testcontainers reaper when the test
+// binary exits.
+const EnvReuseContainers = "GFRAMEWORK_TEST_REUSE"
+
+type ContainerOption func(*containerOptions)
+
+type containerOptions struct {
+	dedicated bool
+}
+
+// WithDedicatedContainer starts a container for the test even when EnvReuseContainers is set.
+func WithDedicatedContainer() ContainerOption {
+	return func(o *containerOptions) {
+		o.dedicated = true
+	}
+}
+
+func reuseContainers(opts []ContainerOption) bool {
+	options := containerOptions{dedicated: false}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.dedicated {
+		return false
+	}
+
+	enabled, err := strconv.ParseBool(os.Getenv(EnvReuseContainers))
+
+	return err == nil && enabled
+}
+
+type sharedContainer[T any] struct {
+	once      sync.Once
+	container T
+	err       error
+}
+
+func (s *sharedContainer[T]) get(start func(context.Context) (T, error)) (T, error) {
+	s.once.Do(func() {
+		// Not tied to a test context: the container outlives the test that started it.
+		s.container, s.err = start(context.Background())
+	})
+
+	return s.container, s.err
+}
+
+var (
+	sharedPostgres    sharedContainer[*PostgresTestContainer]
+	sharedValkey      sharedContainer[*ValkeyTestContainer]
+	sharedMongo       sharedContainer[*MongoTestContainer]
+	sharedDatabaseSeq atomic.Uint64
+	sharedValkeyDBs   = make(chan int, sharedValkeyDatabases)
+	sharedValkeyInit  sync.Once
+)
+
+func setupSharedPostgresDatabase(t *testing.T) *PostgresTestContainer {
+	t.Helper()
+
+	shared, err := sharedPostgres.get(startPostgresContainer)
+	require.NoError(t, err)
+
+	database := fmt.Sprintf("test_%d_%d", os.Getpid(), sharedDatabaseSeq.Add(1))
+
+	admin, err := pgx.Connect(t.Context(), shared.ConnectionString())
+	require.NoError(t, err)
+
+	defer admin.Close(context.Background())
+
+	_, err = admin.Exec(t.Context(), "CREATE DATABASE "+pgx.Identifier{database}.Sanitize())
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), sharedCleanupTimeout)
+		defer cancel()
+
+		conn, err := pgx.Connect(ctx, shared.ConnectionString())
+		if err != nil {
+			return
+		}
+
+		defer conn.Close(ctx)
+
+		_, _ = conn.Exec(ctx, "DROP DATABASE IF EXISTS "+pgx.Identifier{database}.Sanitize()+" WITH (FORCE)")
+	})
+
+	isolated := *shared
+	isolated.Database = database
+
+	return &isolated
+}
+
+func setupSharedValkeyDatabase(t *testing.T) *ValkeyTestContainer {
+	t.Helper()
+
+	shared, err := sharedValkey.get(func(ctx context.Context) (*ValkeyTestContainer, error) {
+		return startValkeyContainer(ctx, "--databases", strconv.Itoa(sharedValkeyDatabases))
+	})
+	require.NoError(t, err)
+
+	sharedValkeyInit.Do(func() {
+		for db := range sharedValkeyDatabases {
+			sharedValkeyDBs <- db
+		}
+	})
+
+	var db int
+
+	select {
+	case db = <-sharedValkeyDBs:
+	case <-t.Context().Done():
+		t.Fatal("no free valkey database")
+	}
+
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), sharedCleanupTimeout)
+		defer cancel()
+
+		client := redis.NewClient(&redis.Options{Addr: shared.Address(), DB: db}) //nolint:exhaustruct
+		defer client.Close()
+
+		// A database that could not be flushed is not handed out again.
+		if client.FlushDB(ctx).Err() == nil {
+			sharedValkeyDBs <- db
+		}
+	})
+
+	isolated := *shared
+	isolated.DB = db
+
+	return &isolated
+}
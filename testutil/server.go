@@ -0,0 +1,137 @@
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andyle182810/gframework/httpclient"
+	"github.com/andyle182810/gframework/httpserver"
+	"github.com/andyle182810/gframework/middleware"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// RegisterRoutesFunc registers an application's routes, with the same signature the application uses
+// for its own httpserver.
+type RegisterRoutesFunc func(e *echo.Echo, root *echo.Group)
+
+// TestServer is an httpserver.Server listening on a random local port, with the same middleware as in
+// production.
+type TestServer struct {
+	Server *httpserver.Server
+	URL    string
+	// Client is an httpclient.Client for URL. Non-2xx responses are returned as *httpclient.ServiceError;
+	// use Request to assert on error responses.
+	Client *httpclient.Client
+}
+
+type testServerConfig struct {
+	server        *httpserver.Config
+	clientOptions []httpclient.Option
+}
+
+type TestServerOption func(*testServerConfig)
+
+// WithServerConfig sets the httpserver.Config, e.g. to enable CORS or lower the body limit. Host and
+// Port are ignored.
+func WithServerConfig(cfg *httpserver.Config) TestServerOption {
+	return func(c *testServerConfig) {
+		if cfg != nil {
+			c.server = cfg
+		}
+	}
+}
+
+// WithClientOptions adds options to the TestServer's Client, e.g. httpclient.WithDefaultHeaders.
+func WithClientOptions(opts ...httpclient.Option) TestServerOption {
+	return func(c *testServerConfig) {
+		c.clientOptions = append(c.clientOptions, opts...)
+	}
+}
+
+// StartTestServer boots an httpserver with the routes of register on a random port and closes it when
+// the test finishes.
+//
+//	srv := testutil.StartTestServer(t, app.registerRoutes)
+//	user, err := httpclient.GetJSON[GetUserResponse](ctx, srv.Client, "/v1/users/"+id)
+func StartTestServer(t *testing.T, register RegisterRoutesFunc, opts ...TestServerOption) *TestServer {
+	t.Helper()
+
+	cfg := testServerConfig{
+		server:        &httpserver.Config{}, //nolint:exhaustruct
+		clientOptions: nil,
+	}
+
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	srv := httpserver.New(cfg.server)
+	register(srv.Echo, srv.Root)
+
+	httpServer := httptest.NewServer(srv.Echo)
+	t.Cleanup(httpServer.Close)
+
+	return &TestServer{
+		Server: srv,
+		URL:    httpServer.URL,
+		Client: httpclient.New(httpServer.URL, cfg.clientOptions...),
+	}
+}
+
+// Request sends method to path with body encoded as JSON, if not nil, and records the response so it
+// can be checked with the Assert helpers. It sets a random X-Request-ID.
+func (s *TestServer) Request(t *testing.T, method, path string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var reader io.Reader = http.NoBody
+
+	if body != nil {
+		data, err := json.Marshal(body)
+		require.NoError(t, err, "Failed to marshal JSON body")
+
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(t.Context(), method, s.URL+path, reader)
+	require.NoError(t, err)
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(middleware.HeaderXRequestID, uuid.New().String())
+
+	return s.Do(t, req)
+}
+
+// Do sends req as is and records the response.
+func (s *TestServer) Do(t *testing.T, req *http.Request) *httptest.ResponseRecorder {
+	t.Helper()
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	rec := httptest.NewRecorder()
+	rec.Code = resp.StatusCode
+
+	for key, values := range resp.Header {
+		rec.Header()[key] = values
+	}
+
+	_, err = io.Copy(rec.Body, resp.Body)
+	require.NoError(t, err)
+
+	return rec
+}
+
+// AssertJSONEqual asserts that the response body is semantically equal to expected, ignoring key order
+// and whitespace, and prints a diff when it is not.
+func AssertJSONEqual(t *testing.T, rec *httptest.ResponseRecorder, expected string) {
+	t.Helper()
+	require.JSONEq(t, expected, rec.Body.String(), "Response body mismatch")
+}
@@ -1,6 +1,7 @@
 package testutil
 
 import (
+	"context"
 	"testing"
 
 	"github.com/docker/go-connections/nat"
@@ -18,17 +19,39 @@ type ValkeyTestContainer struct {
 	Container testcontainers.Container
 	Host      string
 	Port      nat.Port
+	// DB is the database index reserved for the test; always 0 unless EnvReuseContainers is set.
+	DB int
 }
 
 func (c *ValkeyTestContainer) Address() string {
 	return c.Host + ":" + c.Port.Port()
 }
 
-func SetupValkeyContainer(t *testing.T) *ValkeyTestContainer {
+// SetupValkeyContainer starts a Valkey container for the test, or reserves a database index for the test
+// in the shared container when EnvReuseContainers is set.
+func SetupValkeyContainer(t *testing.T, opts ...ContainerOption) *ValkeyTestContainer {
 	t.Helper()
 
+	if reuseContainers(opts) {
+		return setupSharedValkeyDatabase(t)
+	}
+
 	ctx := t.Context()
 
+	container, err := startValkeyContainer(ctx)
+
+	t.Cleanup(func() {
+		if container != nil {
+			_ = container.Container.Terminate(ctx)
+		}
+	})
+
+	require.NoError(t, err)
+
+	return container
+}
+
+func startValkeyContainer(ctx context.Context, args ...string) (*ValkeyTestContainer, error) {
 	//nolint:exhaustruct
 	req := testcontainers.ContainerRequest{
 		Image:        "valkey/valkey:latest",
@@ -36,6 +59,10 @@ func SetupValkeyContainer(t *testing.T) *ValkeyTestContainer {
 		WaitingFor:   wait.ForListeningPort(defaultValkeyPort).WithStartupTimeout(startupTimeout),
 	}
 
+	if len(args) > 0 {
+		req.Cmd = append([]string{"valkey-server"}, args...)
+	}
+
 	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
 		ContainerRequest: req,
 		Started:          true,
@@ -43,22 +70,28 @@ func SetupValkeyContainer(t *testing.T) *ValkeyTestContainer {
 		Logger:           &log.Logger,
 		Reuse:            false,
 	})
+	if container == nil {
+		return nil, err
+	}
 
-	t.Cleanup(func() {
-		_ = container.Terminate(ctx)
-	})
-
-	require.NoError(t, err)
+	result := &ValkeyTestContainer{
+		Container: container,
+		Host:      "",
+		Port:      "",
+		DB:        0,
+	}
 
-	host, err := container.Host(ctx)
-	require.NoError(t, err)
+	if err != nil {
+		return result, err
+	}
 
-	port, err := container.MappedPort(ctx, "6379")
-	require.NoError(t, err)
+	if result.Host, err = container.Host(ctx); err != nil {
+		return result, err
+	}
 
-	return &ValkeyTestContainer{
-		Container: container,
-		Host:      host,
-		Port:      port,
+	if result.Port, err = container.MappedPort(ctx, "6379"); err != nil {
+		return result, err
 	}
+
+	return result, nil
 }
@@ -0,0 +1,75 @@
+package tracing
+
+import (
+	"net/http"
+
+	"github.com/andyle182810/gframework/httpclient"
+	"github.com/labstack/echo/v5"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Middleware starts a server span for every request, continuing the trace of the caller's traceparent
+// header. The span is named after the route, e.g. "GET /v1/users/:id", and is marked as failed on 5xx
+// responses. Register it first, with Echo.Use, so the spans cover the other middleware and the request
+// context carries the span for logutil.FromContext.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx *echo.Context) error {
+			req := ctx.Request()
+			parent := otel.GetTextMapPropagator().Extract(req.Context(), propagation.HeaderCarrier(req.Header))
+
+			route := ctx.Path()
+			if route == "" {
+				route = req.URL.Path
+			}
+
+			spanCtx, span := tracer().Start(parent, req.Method+" "+route,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.HTTPRequestMethodKey.String(req.Method),
+					semconv.HTTPRoute(route),
+					semconv.URLPath(req.URL.Path),
+				),
+			)
+			defer span.End()
+
+			ctx.SetRequest(req.WithContext(spanCtx))
+
+			err := next(ctx)
+
+			_, status := echo.ResolveResponseStatus(ctx.Response(), err)
+			span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(status))
+
+				if err != nil {
+					span.RecordError(err)
+				}
+			}
+
+			return err
+		}
+	}
+}
+
+// Transport wraps base, or http.DefaultTransport when nil, so outbound requests get a client span and
+// carry the traceparent header.
+func Transport(base http.RoundTripper) http.RoundTripper { //nolint:ireturn
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return otelhttp.NewTransport(base)
+}
+
+// HTTPClientOption traces the calls of an httpclient.Client by replacing the transport of its
+// http.Client with Transport(nil). To keep a custom transport, wrap it with Transport instead.
+func HTTPClientOption() httpclient.Option {
+	return httpclient.WithTransport(Transport(nil))
+}
@@ -0,0 +1,232 @@
+// Package tracing configures an OpenTelemetry TracerProvider that exports spans over OTLP/HTTP and
+// provides the instrumentation for the gframework building blocks.
+//
+// New installs the provider and the W3C trace context and baggage propagators globally. redispub,
+// redissub and taskqueue propagate the trace context through their messages with the global
+// propagator and start their spans from the global provider, so they are traced as soon as New has
+// been called. The remaining building blocks are instrumented explicitly:
+//
+//	provider, err := tracing.New(ctx, tracing.Config{
+//	    Endpoint:    "http://otel-collector:4318/v1/traces",
+//	    ServiceName: "orders-api",
+//	    SampleRatio: 0.1,
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	srv.Echo.Use(tracing.Middleware())                                   // httpserver
+//	client := httpclient.New(baseURL, tracing.HTTPClientOption())         // httpclient
+//	pg, err := postgres.New(&postgres.Config{URL: dsn, Tracer: tracing.PgxTracer()})
+//	tracing.InstrumentValkey(valkeyClient)                                // valkey
+//
+//	runner.New(runner.WithInfrastructureService(provider), ...)
+//
+// Register the provider as an infrastructure service so the spans buffered at shutdown are exported
+// after the core services have stopped.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultShutdownTimeout = 10 * time.Second
+	instrumentationName    = "github.com/andyle182810/gframework/tracing"
+)
+
+var (
+	ErrEndpointRequired = errors.New("tracing: OTLP endpoint is required")
+	ErrInvalidRatio     = errors.New("tracing: sample ratio must be between 0 and 1")
+)
+
+type Config struct {
+	// Endpoint is the full URL of the collector, e.g. "https://otel-collector:4318/v1/traces".
+	// Plain http URLs disable TLS.
+	Endpoint string
+	// Headers are sent with every export, e.g. an authorization token.
+	Headers map[string]string
+	// ServiceName, ServiceVersion and Environment are reported as the service.name, service.version and
+	// deployment.environment.name resource attributes.
+	ServiceName    string
+	ServiceVersion string
+	Environment    string
+	// Attributes are added to the resource, e.g. the region or the pod name.
+	Attributes map[string]string
+	// SampleRatio is the fraction of new traces that are sampled. Zero samples every trace. Spans of
+	// requests with a sampled parent are always sampled.
+	SampleRatio float64
+	// Sampler overrides SampleRatio, e.g. with sdktrace.NeverSample().
+	Sampler sdktrace.Sampler
+	// Exporter overrides the OTLP exporter, e.g. with tracetest.NewInMemoryExporter in tests. Endpoint
+	// is not required then.
+	Exporter sdktrace.SpanExporter
+	// Timeout of an export. Defaults to the exporter's default of 10s.
+	Timeout time.Duration
+	// ShutdownTimeout bounds the final flush in Stop. Defaults to 10s.
+	ShutdownTimeout time.Duration
+}
+
+// Provider is the TracerProvider installed by New, as a runner service.
+type Provider struct {
+	provider        *sdktrace.TracerProvider
+	shutdownTimeout time.Duration
+}
+
+func New(ctx context.Context, cfg Config) (*Provider, error) {
+	if cfg.SampleRatio < 0 || cfg.SampleRatio > 1 {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRatio, cfg.SampleRatio)
+	}
+
+	exporter, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := newResource(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(newSampler(cfg)),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	shutdownTimeout := cfg.ShutdownTimeout
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = defaultShutdownTimeout
+	}
+
+	return &Provider{
+		provider:        provider,
+		shutdownTimeout: shutdownTimeout,
+	}, nil
+}
+
+func newExporter(ctx context.Context, cfg Config) (sdktrace.SpanExporter, error) { //nolint:ireturn
+	if cfg.Exporter != nil {
+		return cfg.Exporter, nil
+	}
+
+	if cfg.Endpoint == "" {
+		return nil, ErrEndpointRequired
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpointURL(cfg.Endpoint)}
+
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlptracehttp.WithHeaders(cfg.Headers))
+	}
+
+	if cfg.Timeout > 0 {
+		opts = append(opts, otlptracehttp.WithTimeout(cfg.Timeout))
+	}
+
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("tracing: failed to create OTLP exporter: %w", err)
+	}
+
+	return exporter, nil
+}
+
+func newResource(cfg Config) (*resource.Resource, error) {
+	attrs := make([]attribute.KeyValue, 0, len(cfg.Attributes)+3) //nolint:mnd
+
+	if cfg.ServiceName != "" {
+		attrs = append(attrs, semconv.ServiceName(cfg.ServiceName))
+	}
+
+	if cfg.ServiceVersion != "" {
+		attrs = append(attrs, semconv.ServiceVersion(cfg.ServiceVersion))
+	}
+
+	if cfg.Environment != "" {
+		attrs = append(attrs, semconv.DeploymentEnvironmentName(cfg.Environment))
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(cfg.Attributes)) {
+		attrs = append(attrs, attribute.String(key, cfg.Attributes[key]))
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(attrs...))
+	if err != nil {
+		return nil, fmt.Errorf("tracing: failed to build resource: %w", err)
+	}
+
+	return res, nil
+}
+
+func newSampler(cfg Config) sdktrace.Sampler { //nolint:ireturn
+	if cfg.Sampler != nil {
+		return cfg.Sampler
+	}
+
+	ratio := cfg.SampleRatio
+	if ratio == 0 {
+		ratio = 1
+	}
+
+	return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))
+}
+
+// TracerProvider returns the installed provider, for libraries that take one explicitly.
+func (p *Provider) TracerProvider() trace.TracerProvider { //nolint:ireturn
+	return p.provider
+}
+
+// ForceFlush exports all finished spans, e.g. before a short-lived job exits.
+func (p *Provider) ForceFlush(ctx context.Context) error {
+	if err := p.provider.ForceFlush(ctx); err != nil {
+		return fmt.Errorf("tracing: failed to flush spans: %w", err)
+	}
+
+	return nil
+}
+
+// Start is a no-op; the provider exports from the moment it is created.
+func (p *Provider) Start(_ context.Context) error {
+	return nil
+}
+
+// Stop exports the buffered spans and shuts the exporter down.
+func (p *Provider) Stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), p.shutdownTimeout)
+	defer cancel()
+
+	if err := p.provider.Shutdown(ctx); err != nil {
+		return fmt.Errorf("tracing: failed to shut down tracer provider: %w", err)
+	}
+
+	return nil
+}
+
+func (p *Provider) Name() string {
+	return "tracing"
+}
+
+func tracer() trace.Tracer { //nolint:ireturn
+	return otel.Tracer(instrumentationName)
+}
@@ -0,0 +1,231 @@
+//nolint:exhaustruct,paralleltest
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/andyle182810/gframework/httpclient"
+	"github.com/andyle182810/gframework/redispub"
+	"github.com/andyle182810/gframework/redissub"
+	"github.com/andyle182810/gframework/taskqueue"
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/andyle182810/gframework/tracing"
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v5"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const waitTimeout = 5 * time.Second
+
+// setupTracing installs a provider exporting to memory. Tests using it must not run in parallel, as
+// the provider is global.
+func setupTracing(t *testing.T) (*tracing.Provider, *tracetest.InMemoryExporter) {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+
+	provider, err := tracing.New(t.Context(), tracing.Config{
+		ServiceName: "tracing-test",
+		Exporter:    exporter,
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, provider.Stop())
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	})
+
+	return provider, exporter
+}
+
+func flushedSpans(t *testing.T, provider *tracing.Provider, exporter *tracetest.InMemoryExporter) tracetest.SpanStubs {
+	t.Helper()
+	require.NoError(t, provider.ForceFlush(t.Context()))
+
+	return exporter.GetSpans()
+}
+
+func findSpan(t *testing.T, spans tracetest.SpanStubs, name string) tracetest.SpanStub {
+	t.Helper()
+
+	for _, span := range spans {
+		if span.Name == name {
+			return span
+		}
+	}
+
+	require.Failf(t, "span not found", "no span named %q in %d spans", name, len(spans))
+
+	return tracetest.SpanStub{}
+}
+
+func TestNew_RequiresEndpoint(t *testing.T) {
+	_, err := tracing.New(t.Context(), tracing.Config{})
+
+	require.ErrorIs(t, err, tracing.ErrEndpointRequired)
+}
+
+func TestNew_RejectsInvalidSampleRatio(t *testing.T) {
+	_, err := tracing.New(t.Context(), tracing.Config{Endpoint: "http://localhost:4318/v1/traces", SampleRatio: 1.5})
+
+	require.ErrorIs(t, err, tracing.ErrInvalidRatio)
+}
+
+func TestMiddleware_ContinuesTraceOfHTTPClient(t *testing.T) {
+	provider, exporter := setupTracing(t)
+
+	e := echo.New()
+	e.Use(tracing.Middleware())
+	e.GET("/users/:id", func(c *echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]string{"id": c.Param("id")})
+	})
+
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	client := httpclient.New(server.URL, tracing.HTTPClientOption())
+
+	var user map[string]string
+	require.NoError(t, client.Get(t.Context(), "/users/42", &user))
+
+	spans := flushedSpans(t, provider, exporter)
+	serverSpan := findSpan(t, spans, "GET /users/:id")
+	clientSpan := findSpan(t, spans, "HTTP GET")
+
+	require.Equal(t, trace.SpanKindServer, serverSpan.SpanKind)
+	require.Equal(t, clientSpan.SpanContext.TraceID(), serverSpan.SpanContext.TraceID())
+	require.Equal(t, clientSpan.SpanContext.SpanID(), serverSpan.Parent.SpanID())
+	require.Equal(t, codes.Unset, serverSpan.Status.Code)
+}
+
+func TestMiddleware_MarksServerErrors(t *testing.T) {
+	provider, exporter := setupTracing(t)
+
+	e := echo.New()
+	e.Use(tracing.Middleware())
+	e.GET("/fail", func(*echo.Context) error {
+		return echo.NewHTTPError(http.StatusServiceUnavailable, "down")
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/fail", nil))
+
+	span := findSpan(t, flushedSpans(t, provider, exporter), "GET /fail")
+	require.Equal(t, codes.Error, span.Status.Code)
+}
+
+func TestPgxTracer(t *testing.T) {
+	provider, exporter := setupTracing(t)
+
+	tracer := tracing.PgxTracer()
+
+	ctx := tracer.TraceQueryStart(t.Context(), nil, pgx.TraceQueryStartData{SQL: "  select * from users"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: pgx.ErrNoRows})
+
+	ctx = tracer.TraceQueryStart(t.Context(), nil, pgx.TraceQueryStartData{SQL: "DELETE FROM users"})
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: errors.New("permission denied")})
+
+	spans := flushedSpans(t, provider, exporter)
+	require.Equal(t, codes.Unset, findSpan(t, spans, "postgres SELECT").Status.Code)
+	require.Equal(t, codes.Error, findSpan(t, spans, "postgres DELETE").Status.Code)
+}
+
+func TestInstrumentValkey(t *testing.T) {
+	provider, exporter := setupTracing(t)
+
+	client := testutil.SetupValkey(t)
+	tracing.InstrumentValkey(client)
+
+	require.NoError(t, client.Set(t.Context(), "key", "value", 0).Err())
+	require.ErrorIs(t, client.Get(t.Context(), "missing").Err(), redis.Nil)
+
+	spans := flushedSpans(t, provider, exporter)
+	require.Equal(t, codes.Unset, findSpan(t, spans, "valkey set").Status.Code)
+	require.Equal(t, codes.Unset, findSpan(t, spans, "valkey get").Status.Code)
+}
+
+func TestRedisPubSub_PropagatesTraceContext(t *testing.T) {
+	setupTracing(t)
+
+	client := testutil.SetupValkey(t)
+
+	received := make(chan trace.SpanContext, 1)
+	sub, err := redissub.NewSubscriber(client, "tracing-group", "tracing-topic",
+		func(ctx context.Context, _ message.Payload) error {
+			received <- trace.SpanContextFromContext(ctx)
+
+			return nil
+		},
+		redissub.WithBlockTime(100*time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	go func() {
+		_ = sub.Start(context.Background())
+	}()
+
+	t.Cleanup(func() { require.NoError(t, sub.Stop()) })
+	require.Eventually(t, sub.IsHealthy, waitTimeout, 10*time.Millisecond)
+
+	publisher, err := redispub.New(client, redispub.Options{})
+	require.NoError(t, err)
+
+	ctx, span := otel.Tracer("test").Start(t.Context(), "publish")
+	require.NoError(t, publisher.PublishToTopic(ctx, "tracing-topic", "hello"))
+	span.End()
+
+	select {
+	case got := <-received:
+		require.Equal(t, span.SpanContext().TraceID(), got.TraceID())
+	case <-time.After(waitTimeout):
+		t.Fatal("message was not delivered")
+	}
+}
+
+func TestTaskQueue_PropagatesTraceContext(t *testing.T) {
+	setupTracing(t)
+
+	client := testutil.SetupValkey(t)
+
+	received := make(chan trace.SpanContext, 1)
+	queue, err := taskqueue.New(client, "tracing:queue", executorFunc(func(ctx context.Context) {
+		received <- trace.SpanContextFromContext(ctx)
+	}), taskqueue.WithPollInterval(100*time.Millisecond))
+	require.NoError(t, err)
+
+	ctx, span := otel.Tracer("test").Start(t.Context(), "enqueue")
+	require.NoError(t, queue.Push(ctx, taskqueue.Task{ID: "task-1", Payload: []byte("payload")}))
+	span.End()
+
+	require.NoError(t, queue.Start(t.Context()))
+	t.Cleanup(func() { require.NoError(t, queue.Stop()) })
+
+	select {
+	case got := <-received:
+		require.Equal(t, span.SpanContext().TraceID(), got.TraceID())
+	case <-time.After(waitTimeout):
+		t.Fatal("task was not executed")
+	}
+}
+
+type executorFunc func(ctx context.Context)
+
+func (f executorFunc) Execute(ctx context.Context, _ string, _ taskqueue.Payload) error {
+	f(ctx)
+
+	return nil
+}
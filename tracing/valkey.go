@@ -0,0 +1,71 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentValkey adds a hook to client that records a client span for every command, named e.g.
+// "valkey get", and one span per pipeline. It accepts a *valkey.Valkey as well as any go-redis client.
+func InstrumentValkey(client redis.UniversalClient) {
+	client.AddHook(valkeyHook{})
+}
+
+type valkeyHook struct{}
+
+var _ redis.Hook = valkeyHook{}
+
+func (valkeyHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (valkeyHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		name := strings.ToLower(cmd.Name())
+
+		ctx, span := tracer().Start(ctx, "valkey "+name,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemNameRedis, semconv.DBOperationName(name)),
+		)
+		defer span.End()
+
+		err := next(ctx, cmd)
+		recordValkeyError(span, err)
+
+		return err
+	}
+}
+
+func (valkeyHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		ctx, span := tracer().Start(ctx, "valkey pipeline",
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				semconv.DBSystemNameRedis,
+				semconv.DBOperationName("pipeline"),
+				semconv.DBOperationBatchSize(len(cmds)),
+			),
+		)
+		defer span.End()
+
+		err := next(ctx, cmds)
+		recordValkeyError(span, err)
+
+		return err
+	}
+}
+
+func recordValkeyError(span trace.Span, err error) {
+	if err == nil || errors.Is(err, redis.Nil) {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
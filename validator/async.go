@@ -0,0 +1,201 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const defaultAsyncTimeout = 2 * time.Second
+
+var (
+	ErrAsyncValidation   = errors.New("validator: async validation could not be completed")
+	ErrEmptyTag          = errors.New("validator: tag is empty")
+	ErrNilAsyncValidator = errors.New("validator: async validation func is nil")
+)
+
+// AsyncFunc validates a field value using IO, e.g. a database lookup. Returning an error aborts the
+// validation with ErrAsyncValidation instead of reporting the field as invalid.
+type AsyncFunc func(ctx context.Context, value any, param string) (bool, error)
+
+type AsyncOption func(*asyncValidation)
+
+// WithAsyncTimeout bounds each invocation of the validation func.
+func WithAsyncTimeout(timeout time.Duration) AsyncOption {
+	return func(a *asyncValidation) {
+		if timeout > 0 {
+			a.timeout = timeout
+		}
+	}
+}
+
+// WithAsyncCache caches results per value and param for ttl. Failed lookups are never cached.
+func WithAsyncCache(ttl time.Duration) AsyncOption {
+	return func(a *asyncValidation) {
+		if ttl > 0 {
+			a.cacheTTL = ttl
+		}
+	}
+}
+
+// WithAsyncMessage sets the error message for the tag. The format receives the field name, e.g.
+// "%s is already registered".
+func WithAsyncMessage(format string) AsyncOption {
+	return func(a *asyncValidation) {
+		a.message = format
+	}
+}
+
+// ContextValidator is implemented by validators that can run async validations with a request context.
+type ContextValidator interface {
+	ValidateCtx(ctx context.Context, i any) error
+}
+
+type asyncCacheEntry struct {
+	valid     bool
+	expiresAt time.Time
+}
+
+type asyncValidation struct {
+	tag      string
+	fn       AsyncFunc
+	timeout  time.Duration
+	cacheTTL time.Duration
+	message  string
+	mu       sync.Mutex
+	cache    map[string]asyncCacheEntry
+}
+
+type asyncErrorsKey struct{}
+
+type asyncErrors struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+func (a *asyncErrors) add(err error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.errs = append(a.errs, err)
+}
+
+// RegisterAsyncValidation registers a tag backed by IO. Failures are reported through the same
+// ValidationErrors as built-in tags when validating with ValidateCtx.
+func (v *Validator) RegisterAsyncValidation(tag string, fn AsyncFunc, opts ...AsyncOption) error {
+	if tag == "" {
+		return ErrEmptyTag
+	}
+
+	if fn == nil {
+		return ErrNilAsyncValidator
+	}
+
+	async := &asyncValidation{ //nolint:exhaustruct
+		tag:     tag,
+		fn:      fn,
+		timeout: defaultAsyncTimeout,
+		cache:   make(map[string]asyncCacheEntry),
+	}
+
+	for _, opt := range opts {
+		opt(async)
+	}
+
+	if err := v.Validator.RegisterValidationCtx(tag, async.validate); err != nil {
+		return fmt.Errorf("validator: failed to register %q: %w", tag, err)
+	}
+
+	if async.message != "" {
+		v.setMessage(tag, async.message)
+	}
+
+	return nil
+}
+
+// ValidateCtx validates i like Validate, passing ctx to async validations.
+func (v *Validator) ValidateCtx(ctx context.Context, i any) error {
+	collector := &asyncErrors{} //nolint:exhaustruct
+	ctx = context.WithValue(ctx, asyncErrorsKey{}, collector)
+
+	err := v.Validator.StructCtx(ctx, i)
+
+	if len(collector.errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrAsyncValidation, errors.Join(collector.errs...))
+	}
+
+	if err != nil {
+		var validationErrs validator.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			return v.formatValidationErrors(validationErrs, reflect.TypeOf(i), v.resolveLocale(ctx))
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (a *asyncValidation) validate(ctx context.Context, fl validator.FieldLevel) bool {
+	value := fl.Field().Interface()
+	param := fl.Param()
+	key := fmt.Sprintf("%s|%v", param, value)
+
+	if valid, ok := a.cached(key); ok {
+		return valid
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, a.timeout)
+	defer cancel()
+
+	valid, err := a.fn(callCtx, value, param)
+	if err != nil {
+		if collector, ok := ctx.Value(asyncErrorsKey{}).(*asyncErrors); ok {
+			collector.add(fmt.Errorf("%s: %w", a.tag, err))
+		}
+
+		// Report the field as valid so that only the IO error surfaces.
+		return true
+	}
+
+	a.store(key, valid)
+
+	return valid
+}
+
+func (a *asyncValidation) cached(key string) (bool, bool) {
+	if a.cacheTTL <= 0 {
+		return false, false
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	entry, ok := a.cache[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		delete(a.cache, key)
+
+		return false, false
+	}
+
+	return entry.valid, true
+}
+
+func (a *asyncValidation) store(key string, valid bool) {
+	if a.cacheTTL <= 0 {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.cache[key] = asyncCacheEntry{
+		valid:     valid,
+		expiresAt: time.Now().Add(a.cacheTTL),
+	}
+}
@@ -0,0 +1,95 @@
+package validator_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/validator"
+	"github.com/stretchr/testify/require"
+)
+
+var errLookupFailed = errors.New("lookup failed")
+
+type SignupRequest struct {
+	Email   string `json:"email"   validate:"required,email,email_available"`
+	Country string `json:"country" validate:"required,country_exists"`
+}
+
+func newAsyncValidator(t *testing.T, lookups *atomic.Int32, lookupErr error) *validator.Validator {
+	t.Helper()
+
+	v := validator.DefaultRestValidator()
+
+	err := v.RegisterAsyncValidation("email_available", func(_ context.Context, value any, _ string) (bool, error) {
+		lookups.Add(1)
+
+		if lookupErr != nil {
+			return false, lookupErr
+		}
+
+		return value != "taken@example.com", nil
+	}, validator.WithAsyncCache(time.Minute), validator.WithAsyncMessage("%s is already registered"))
+	require.NoError(t, err)
+
+	err = v.RegisterAsyncValidation("country_exists", func(ctx context.Context, value any, _ string) (bool, error) {
+		if value == "SLOW" {
+			<-ctx.Done()
+
+			return false, ctx.Err()
+		}
+
+		return value == "VN" || value == "US", nil
+	}, validator.WithAsyncTimeout(10*time.Millisecond))
+	require.NoError(t, err)
+
+	return v
+}
+
+func TestValidateCtx_AsyncValidation(t *testing.T) {
+	t.Parallel()
+
+	var lookups atomic.Int32
+
+	v := newAsyncValidator(t, &lookups, nil)
+
+	require.NoError(t, v.ValidateCtx(t.Context(), SignupRequest{Email: "new@example.com", Country: "VN"}))
+
+	err := v.ValidateCtx(t.Context(), SignupRequest{Email: "taken@example.com", Country: "XX"})
+
+	var validationErrs validator.ValidationErrors
+	require.ErrorAs(t, err, &validationErrs)
+	require.Len(t, validationErrs, 2)
+	require.Equal(t, "email", validationErrs[0].Field)
+	require.Equal(t, "email is already registered", validationErrs[0].Message)
+	require.Equal(t, "country_exists", validationErrs[1].Tag)
+
+	require.Error(t, v.Validate(SignupRequest{Email: "taken@example.com", Country: "VN"}))
+	require.Equal(t, int32(2), lookups.Load())
+}
+
+func TestValidateCtx_AsyncErrors(t *testing.T) {
+	t.Parallel()
+
+	var lookups atomic.Int32
+
+	v := newAsyncValidator(t, &lookups, errLookupFailed)
+
+	err := v.ValidateCtx(t.Context(), SignupRequest{Email: "new@example.com", Country: "VN"})
+	require.ErrorIs(t, err, validator.ErrAsyncValidation)
+	require.ErrorIs(t, err, errLookupFailed)
+
+	err = v.ValidateCtx(t.Context(), SignupRequest{Email: "new@example.com", Country: "SLOW"})
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestRegisterAsyncValidation_InvalidInput(t *testing.T) {
+	t.Parallel()
+
+	v := validator.DefaultRestValidator()
+
+	require.ErrorIs(t, v.RegisterAsyncValidation("", nil), validator.ErrEmptyTag)
+	require.ErrorIs(t, v.RegisterAsyncValidation("exists", nil), validator.ErrNilAsyncValidator)
+}
@@ -0,0 +1,56 @@
+package validator
+
+import (
+	"reflect"
+
+	"github.com/labstack/echo/v5"
+)
+
+// EchoBinder binds path params, query params and the body like echo.DefaultBinder and then validates
+// the target with the Echo validator, so handlers calling c.Bind receive ValidationErrors with field
+// paths instead of unvalidated input. Validators implementing ContextValidator get the request
+// context, with the locale taken from the Accept-Language header unless one was already set.
+//
+//	e.Validator = validator.DefaultRestValidator()
+//	e.Binder = validator.NewEchoBinder()
+type EchoBinder struct {
+	binder echo.DefaultBinder
+}
+
+func NewEchoBinder() *EchoBinder {
+	return &EchoBinder{
+		binder: echo.DefaultBinder{},
+	}
+}
+
+func (b *EchoBinder) Bind(c *echo.Context, target any) error {
+	if err := b.binder.Bind(c, target); err != nil {
+		return err
+	}
+
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Struct || c.Echo().Validator == nil {
+		return nil
+	}
+
+	return ValidateEcho(c, target)
+}
+
+// ValidateEcho validates target with the Echo validator of c. Validators implementing ContextValidator
+// get the request context, so async validations are cancelled together with the request, with the
+// locale taken from the Accept-Language header unless one was already set.
+func ValidateEcho(c *echo.Context, target any) error {
+	ctxValidator, ok := c.Echo().Validator.(ContextValidator)
+	if !ok {
+		return c.Validate(target)
+	}
+
+	ctx := c.Request().Context()
+	if LocaleFromContext(ctx) == "" {
+		if acceptLanguage := c.Request().Header.Get("Accept-Language"); acceptLanguage != "" {
+			ctx = ContextWithLocale(ctx, acceptLanguage)
+		}
+	}
+
+	return ctxValidator.ValidateCtx(ctx, target)
+}
@@ -0,0 +1,106 @@
+package validator_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andyle182810/gframework/validator"
+	"github.com/labstack/echo/v5"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+type OrderItem struct {
+	SKU   string          `json:"sku"   validate:"required"`
+	Price decimal.Decimal `json:"price" validate:"decimal_gt=0"`
+}
+
+type CreateOrderRequest struct {
+	StoreID  string `param:"storeId"  validate:"required,uuid"`
+	DryRun   bool   `query:"dry_run"`
+	Customer struct {
+		Email string `json:"email" validate:"required,email"`
+	} `json:"customer"`
+	Items []OrderItem `json:"items" validate:"required,min=1,dive"`
+}
+
+func newBinderContext(t *testing.T, body, acceptLanguage string) *echo.Context {
+	t.Helper()
+
+	e := echo.New()
+	e.Validator = validator.DefaultRestValidator()
+	e.Binder = validator.NewEchoBinder()
+
+	req := httptest.NewRequest(http.MethodPost, "/stores/9b2f2c38-6c1e-4d59-9c0b-1f6d8f3f7a10/orders",
+		strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	if acceptLanguage != "" {
+		req.Header.Set("Accept-Language", acceptLanguage)
+	}
+
+	c := e.NewContext(req, httptest.NewRecorder())
+	c.SetPathValues(echo.PathValues{{Name: "storeId", Value: "9b2f2c38-6c1e-4d59-9c0b-1f6d8f3f7a10"}})
+
+	return c
+}
+
+func TestEchoBinder_BindsAndValidates(t *testing.T) {
+	t.Parallel()
+
+	c := newBinderContext(t, `{"customer":{"email":"a@example.com"},"items":[{"sku":"A","price":"1.5"}]}`, "")
+
+	var req CreateOrderRequest
+	require.NoError(t, c.Bind(&req))
+	require.Equal(t, "9b2f2c38-6c1e-4d59-9c0b-1f6d8f3f7a10", req.StoreID)
+	require.Equal(t, "a@example.com", req.Customer.Email)
+	require.Len(t, req.Items, 1)
+}
+
+func TestEchoBinder_ReportsFieldPaths(t *testing.T) {
+	t.Parallel()
+
+	body := `{"customer":{"email":"nope"},"items":[{"sku":"A","price":"1"},{"sku":"B","price":"2"},{"sku":"","price":"0"}]}`
+	c := newBinderContext(t, body, "")
+
+	var req CreateOrderRequest
+
+	err := c.Bind(&req)
+	require.NotContains(t, err.Error(), "Field validation for")
+	require.Equal(t, map[string]string{
+		"customer.email": "customer.email must be a valid email address",
+		"items[2].sku":   "items[2].sku is required",
+		"items[2].price": "items[2].price must be greater than 0",
+	}, validationMessages(t, err))
+}
+
+func TestEchoBinder_UsesAcceptLanguage(t *testing.T) {
+	t.Parallel()
+
+	c := newBinderContext(t, `{"customer":{"email":""},"items":[{"sku":"A","price":"1"}]}`, "vi-VN,vi;q=0.9")
+
+	restValidator, ok := c.Echo().Validator.(*validator.Validator)
+	require.True(t, ok)
+	require.NoError(t, restValidator.EnableTranslations(validator.LocaleVietnamese))
+
+	var req CreateOrderRequest
+
+	err := c.Bind(&req)
+	require.Equal(t, "customer.email không được bỏ trống", validationMessages(t, err)["customer.email"])
+}
+
+func TestEchoBinder_InvalidBody(t *testing.T) {
+	t.Parallel()
+
+	c := newBinderContext(t, `{"items":`, "")
+
+	var req CreateOrderRequest
+
+	err := c.Bind(&req)
+
+	var httpErr *echo.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusBadRequest, httpErr.Code)
+}
@@ -0,0 +1,245 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	tagMutuallyExclusive  = "mutually_exclusive"
+	tagRequiredWithoutAll = "required_without_all"
+)
+
+// crossFieldTags are the go-playground tags whose param names other fields of the struct.
+var crossFieldTags = map[string]bool{ //nolint:gochecknoglobals
+	"required_if":         true,
+	"required_unless":     true,
+	"required_with":       true,
+	"required_with_all":   true,
+	"required_without":    true,
+	tagRequiredWithoutAll: true,
+	"excluded_with":       true,
+	"excluded_without":    true,
+	"eqfield":             true,
+	"nefield":             true,
+	"gtfield":             true,
+	"gtefield":            true,
+	"ltfield":             true,
+	"ltefield":            true,
+	tagMutuallyExclusive:  true,
+}
+
+// MutuallyExclusive returns a struct-level rule allowing at most one of the named fields to be set,
+// e.g. a payment with either a card or a bank account. Every set field after the first is reported
+// with the mutually_exclusive tag. Register it with RegisterStructRules.
+func MutuallyExclusive(fields ...string) validator.StructLevelFunc {
+	return func(sl validator.StructLevel) {
+		reportConflicts(sl, setFields(sl.Current(), fields))
+	}
+}
+
+// ExactlyOneOf is like MutuallyExclusive but also requires one of the fields to be set. When none is,
+// every field is reported with the required_without_all tag.
+func ExactlyOneOf(fields ...string) validator.StructLevelFunc {
+	return func(sl validator.StructLevel) {
+		current := sl.Current()
+
+		set := setFields(current, fields)
+		if len(set) > 0 {
+			reportConflicts(sl, set)
+
+			return
+		}
+
+		for _, name := range fields {
+			others := make([]string, 0, len(fields)-1)
+
+			for _, other := range fields {
+				if other != name {
+					others = append(others, fieldJSONName(current.Type(), other))
+				}
+			}
+
+			reportField(sl, name, tagRequiredWithoutAll, strings.Join(others, " "))
+		}
+	}
+}
+
+// RegisterStructRules registers rules for the struct type of structType, e.g.
+//
+//	v.RegisterStructRules(PaymentRequest{}, validator.ExactlyOneOf("Card", "BankAccount"))
+//
+// The rules replace any struct-level validation registered for the type before.
+func (v *Validator) RegisterStructRules(structType any, rules ...validator.StructLevelFunc) {
+	v.Validator.RegisterStructValidation(func(sl validator.StructLevel) {
+		for _, rule := range rules {
+			rule(sl)
+		}
+	}, structType)
+}
+
+func setFields(current reflect.Value, fields []string) []string {
+	set := make([]string, 0, len(fields))
+
+	for _, name := range fields {
+		if field := current.FieldByName(name); field.IsValid() && !field.IsZero() {
+			set = append(set, name)
+		}
+	}
+
+	return set
+}
+
+func reportConflicts(sl validator.StructLevel, set []string) {
+	for i := 1; i < len(set); i++ {
+		others := make([]string, 0, len(set)-1)
+
+		for j, other := range set {
+			if j != i {
+				others = append(others, fieldJSONName(sl.Current().Type(), other))
+			}
+		}
+
+		reportField(sl, set[i], tagMutuallyExclusive, strings.Join(others, " "))
+	}
+}
+
+func reportField(sl validator.StructLevel, name, tag, param string) {
+	current := sl.Current()
+	sl.ReportError(current.FieldByName(name).Interface(), fieldJSONName(current.Type(), name), name, tag, param)
+}
+
+// fieldJSONName returns the JSON name of the field name of structType, or name when it has none.
+func fieldJSONName(structType reflect.Type, name string) string {
+	field, ok := structType.FieldByName(name)
+	if !ok {
+		return name
+	}
+
+	if jsonName := jsonTagName(field); jsonName != "" {
+		return jsonName
+	}
+
+	return name
+}
+
+// crossFieldParam returns the param of a cross-field tag with the referenced Go field names replaced
+// by their JSON names. root is the type of the validated value and is walked along the struct
+// namespace of err to find the struct that declares the field.
+func crossFieldParam(root reflect.Type, err validator.FieldError) string {
+	param := err.Param()
+	if !crossFieldTags[err.Tag()] || root == nil {
+		return param
+	}
+
+	parent := elemType(root)
+
+	segments := strings.Split(err.StructNamespace(), ".")
+	for _, segment := range segments[1 : len(segments)-1] {
+		if parent.Kind() != reflect.Struct {
+			return param
+		}
+
+		name, _, _ := strings.Cut(segment, "[")
+
+		field, ok := parent.FieldByName(name)
+		if !ok {
+			return param
+		}
+
+		parent = elemType(field.Type)
+	}
+
+	if parent.Kind() != reflect.Struct {
+		return param
+	}
+
+	// required_if and required_unless take field and value pairs.
+	step := 1
+	if tag := err.Tag(); tag == "required_if" || tag == "required_unless" {
+		step = 2
+	}
+
+	words := strings.Fields(param)
+	for i := 0; i < len(words); i += step {
+		if _, ok := parent.FieldByName(words[i]); ok {
+			words[i] = fieldJSONName(parent, words[i])
+		}
+	}
+
+	return strings.Join(words, " ")
+}
+
+func elemType(t reflect.Type) reflect.Type {
+	for {
+		switch t.Kind() { //nolint:exhaustive
+		case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+		default:
+			return t
+		}
+	}
+}
+
+func crossFieldMessage(field, param string, err validator.FieldError) (string, bool) { //nolint:cyclop
+	isTime := err.Type() == reflect.TypeFor[time.Time]()
+
+	switch err.Tag() {
+	case "required_if":
+		return fmt.Sprintf("%s is required when %s", field, conditions(param)), true
+	case "required_unless":
+		return fmt.Sprintf("%s is required unless %s", field, conditions(param)), true
+	case "required_with":
+		return fmt.Sprintf("%s is required when %s is present", field, param), true
+	case "required_with_all":
+		return fmt.Sprintf("%s is required when all of [%s] are present", field, param), true
+	case "required_without":
+		return fmt.Sprintf("%s is required when %s is missing", field, param), true
+	case tagRequiredWithoutAll:
+		return fmt.Sprintf("%s is required when none of [%s] are present", field, param), true
+	case "excluded_with":
+		return fmt.Sprintf("%s must be empty when %s is present", field, param), true
+	case "excluded_without":
+		return fmt.Sprintf("%s must be empty when %s is missing", field, param), true
+	case tagMutuallyExclusive:
+		return fmt.Sprintf("%s cannot be combined with [%s]", field, param), true
+	case "eqfield":
+		return fmt.Sprintf("%s must be equal to %s", field, param), true
+	case "nefield":
+		return fmt.Sprintf("%s must not be equal to %s", field, param), true
+	case "gtfield":
+		return comparison(field, param, isTime, "after", "greater than"), true
+	case "gtefield":
+		return comparison(field, param, isTime, "at or after", "greater than or equal to"), true
+	case "ltfield":
+		return comparison(field, param, isTime, "before", "less than"), true
+	case "ltefield":
+		return comparison(field, param, isTime, "at or before", "less than or equal to"), true
+	default:
+		return "", false
+	}
+}
+
+func comparison(field, param string, isTime bool, timeRelation, relation string) string {
+	if isTime {
+		return fmt.Sprintf("%s must be %s %s", field, timeRelation, param)
+	}
+
+	return fmt.Sprintf("%s must be %s %s", field, relation, param)
+}
+
+// conditions formats "status active type premium" as "status is active and type is premium".
+func conditions(param string) string {
+	words := strings.Fields(param)
+	parts := make([]string, 0, len(words)/2) //nolint:mnd
+
+	for i := 0; i+1 < len(words); i += 2 {
+		parts = append(parts, words[i]+" is "+words[i+1])
+	}
+
+	return strings.Join(parts, " and ")
+}
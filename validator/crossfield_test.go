@@ -0,0 +1,180 @@
+package validator_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/validator"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+type Period struct {
+	StartsAt time.Time `json:"starts_at" validate:"required"`
+	EndsAt   time.Time `json:"ends_at"   validate:"required,gtfield=StartsAt"`
+}
+
+type CampaignRequest struct {
+	Status       string          `json:"status"`
+	Reason       string          `json:"reason"        validate:"required_if=Status paused"`
+	Email        string          `json:"email"         validate:"required_without_all=Phone UserID"`
+	Phone        string          `json:"phone"`
+	UserID       string          `json:"user_id"`
+	MinBudget    decimal.Decimal `json:"min_budget"`
+	MaxBudget    decimal.Decimal `json:"max_budget"    validate:"gtefield=MinBudget"`
+	Periods      []Period        `json:"periods"       validate:"dive"`
+	Password     string          `json:"password"`
+	Confirmation string          `json:"confirmation"  validate:"eqfield=Password"`
+}
+
+func validCampaignRequest() CampaignRequest {
+	startsAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	return CampaignRequest{
+		Status:       "active",
+		Reason:       "",
+		Email:        "",
+		Phone:        "+84912345678",
+		UserID:       "",
+		MinBudget:    decimal.NewFromInt(10),
+		MaxBudget:    decimal.NewFromInt(10),
+		Periods:      []Period{{StartsAt: startsAt, EndsAt: startsAt.Add(time.Hour)}},
+		Password:     "secret",
+		Confirmation: "secret",
+	}
+}
+
+func TestCrossFieldMessages(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mutate   func(*CampaignRequest)
+		field    string
+		expected string
+	}{
+		{
+			name:     "required_if",
+			mutate:   func(r *CampaignRequest) { r.Status = "paused" },
+			field:    "reason",
+			expected: "reason is required when status is paused",
+		},
+		{
+			name:     "required_without_all",
+			mutate:   func(r *CampaignRequest) { r.Phone = "" },
+			field:    "email",
+			expected: "email is required when none of [phone user_id] are present",
+		},
+		{
+			name:     "gtefield with decimals",
+			mutate:   func(r *CampaignRequest) { r.MaxBudget = decimal.RequireFromString("9.99") },
+			field:    "max_budget",
+			expected: "max_budget must be greater than or equal to min_budget",
+		},
+		{
+			name: "gtfield with times in a slice",
+			mutate: func(r *CampaignRequest) {
+				r.Periods = append(r.Periods, Period{StartsAt: r.Periods[0].EndsAt, EndsAt: r.Periods[0].StartsAt})
+			},
+			field:    "periods[1].ends_at",
+			expected: "periods[1].ends_at must be after starts_at",
+		},
+		{
+			name:     "eqfield",
+			mutate:   func(r *CampaignRequest) { r.Confirmation = "other" },
+			field:    "confirmation",
+			expected: "confirmation must be equal to password",
+		},
+	}
+
+	v := validator.DefaultRestValidator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := validCampaignRequest()
+			require.NoError(t, v.Validate(&req))
+
+			tt.mutate(&req)
+
+			messages := validationMessages(t, v.Validate(&req))
+			require.Len(t, messages, 1)
+			require.Equal(t, tt.expected, messages[tt.field])
+		})
+	}
+}
+
+type PaymentRequest struct {
+	Card        string `json:"card"`
+	BankAccount string `json:"bank_account"`
+	Wallet      string `json:"wallet"`
+	Coupon      string `json:"coupon"`
+	GiftCard    string `json:"gift_card"`
+}
+
+func newPaymentValidator() *validator.Validator {
+	v := validator.DefaultRestValidator()
+	v.RegisterStructRules(PaymentRequest{},
+		validator.ExactlyOneOf("Card", "BankAccount", "Wallet"),
+		validator.MutuallyExclusive("Coupon", "GiftCard"),
+	)
+
+	return v
+}
+
+func TestStructRules(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		req      PaymentRequest
+		expected map[string]string
+	}{
+		{
+			name:     "valid",
+			req:      PaymentRequest{Card: "4242", Coupon: "SALE"},
+			expected: nil,
+		},
+		{
+			name: "none of exactly one",
+			req:  PaymentRequest{},
+			expected: map[string]string{
+				"card":         "card is required when none of [bank_account wallet] are present",
+				"bank_account": "bank_account is required when none of [card wallet] are present",
+				"wallet":       "wallet is required when none of [card bank_account] are present",
+			},
+		},
+		{
+			name: "several of exactly one",
+			req:  PaymentRequest{Card: "4242", Wallet: "momo"},
+			expected: map[string]string{
+				"wallet": "wallet cannot be combined with [card]",
+			},
+		},
+		{
+			name: "mutually exclusive",
+			req:  PaymentRequest{BankAccount: "123", Coupon: "SALE", GiftCard: "GIFT"},
+			expected: map[string]string{
+				"gift_card": "gift_card cannot be combined with [coupon]",
+			},
+		},
+	}
+
+	v := newPaymentValidator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := v.Validate(&tt.req)
+			if tt.expected == nil {
+				require.NoError(t, err)
+
+				return
+			}
+
+			require.Equal(t, tt.expected, validationMessages(t, err))
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package validator
+
+import (
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	defaultPasswordClasses = 3
+	ibanChecksumModulus    = 97
+)
+
+var slugRegexp = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+// ibanLengths is the IBAN length per country from the SWIFT IBAN registry.
+var ibanLengths = map[string]int{ //nolint:gochecknoglobals
+	"AD": 24, "AE": 23, "AL": 28, "AT": 20, "AZ": 28, "BA": 20, "BE": 16, "BG": 22, "BH": 22, "BI": 27,
+	"BR": 29, "BY": 28, "CH": 21, "CR": 22, "CY": 28, "CZ": 24, "DE": 22, "DJ": 27, "DK": 18, "DO": 28,
+	"EE": 20, "EG": 29, "ES": 24, "FI": 18, "FK": 18, "FO": 18, "FR": 27, "GB": 22, "GE": 22, "GI": 23,
+	"GL": 18, "GR": 27, "GT": 28, "HR": 21, "HU": 28, "IE": 22, "IL": 23, "IQ": 23, "IS": 26, "IT": 27,
+	"JO": 30, "KW": 30, "KZ": 20, "LB": 28, "LC": 32, "LI": 21, "LT": 20, "LU": 20, "LV": 21, "LY": 25,
+	"MC": 27, "MD": 24, "ME": 22, "MK": 19, "MN": 20, "MR": 27, "MT": 31, "MU": 30, "NI": 28, "NL": 18,
+	"NO": 15, "OM": 23, "PK": 24, "PL": 28, "PS": 29, "PT": 25, "QA": 29, "RO": 24, "RS": 22, "RU": 33,
+	"SA": 24, "SC": 31, "SD": 18, "SE": 24, "SI": 19, "SK": 24, "SM": 27, "SO": 23, "ST": 25, "SV": 28,
+	"TL": 23, "TN": 24, "TR": 26, "UA": 29, "VA": 22, "VG": 24, "XK": 20, "YE": 30,
+}
+
+// registerDomainValidations registers the tags that go-playground does not provide. e164, ulid,
+// timezone and country_code are built in and only get messages.
+func registerDomainValidations(v *validator.Validate) {
+	_ = v.RegisterValidation("iban", isIBAN)
+	_ = v.RegisterValidation("slug", isSlug)
+	_ = v.RegisterValidation("password_strength", isStrongPassword)
+	_ = v.RegisterValidation("decimal_gt", compareDecimal(func(cmp int) bool { return cmp > 0 }))
+	_ = v.RegisterValidation("decimal_gte", compareDecimal(func(cmp int) bool { return cmp >= 0 }))
+	_ = v.RegisterValidation("decimal_lt", compareDecimal(func(cmp int) bool { return cmp < 0 }))
+	_ = v.RegisterValidation("decimal_lte", compareDecimal(func(cmp int) bool { return cmp <= 0 }))
+}
+
+// isIBAN checks the country length and the ISO 7064 mod 97 checksum. Spaces are allowed, as in the
+// printed form "DE89 3704 0044 0532 0130 00".
+func isIBAN(fl validator.FieldLevel) bool {
+	iban := strings.ToUpper(strings.ReplaceAll(fl.Field().String(), " ", ""))
+
+	const minLength = 4
+	if len(iban) < minLength {
+		return false
+	}
+
+	length, ok := ibanLengths[iban[:2]]
+	if !ok || len(iban) != length {
+		return false
+	}
+
+	var digits strings.Builder
+
+	for _, r := range iban[4:] + iban[:4] {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r >= 'A' && r <= 'Z':
+			digits.WriteString(strconv.Itoa(int(r-'A') + 10)) //nolint:mnd // A=10 ... Z=35
+		default:
+			return false
+		}
+	}
+
+	number, ok := new(big.Int).SetString(digits.String(), 10) //nolint:mnd
+	if !ok {
+		return false
+	}
+
+	return new(big.Int).Mod(number, big.NewInt(ibanChecksumModulus)).Int64() == 1
+}
+
+func isSlug(fl validator.FieldLevel) bool {
+	return slugRegexp.MatchString(fl.Field().String())
+}
+
+// isStrongPassword requires characters from at least param (default 3) of the classes lowercase,
+// uppercase, digit and symbol, e.g. `validate:"min=12,password_strength=3"`.
+func isStrongPassword(fl validator.FieldLevel) bool {
+	required := defaultPasswordClasses
+
+	if param := fl.Param(); param != "" {
+		parsed, err := strconv.Atoi(param)
+		if err != nil {
+			return false
+		}
+
+		required = parsed
+	}
+
+	var lower, upper, digit, symbol bool
+
+	for _, r := range fl.Field().String() {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+
+	classes := 0
+
+	for _, present := range []bool{lower, upper, digit, symbol} {
+		if present {
+			classes++
+		}
+	}
+
+	return classes >= required
+}
+
+// compareDecimal compares decimal fields with the param without going through float64, so
+// `validate:"decimal_gt=0.1"` is exact. String fields are parsed as decimals.
+func compareDecimal(accept func(cmp int) bool) validator.Func {
+	return func(fl validator.FieldLevel) bool {
+		limit, err := decimal.NewFromString(fl.Param())
+		if err != nil {
+			return false
+		}
+
+		value, ok := decimalField(fl)
+		if !ok {
+			return false
+		}
+
+		return accept(value.Cmp(limit))
+	}
+}
+
+// decimalField returns the field as a decimal. The registered custom type func turns decimals into
+// float64 before validation, so the original value is read from the parent struct when possible.
+func decimalField(fl validator.FieldLevel) (decimal.Decimal, bool) {
+	if parent := reflect.Indirect(fl.Parent()); parent.Kind() == reflect.Struct {
+		field := reflect.Indirect(parent.FieldByName(fl.StructFieldName()))
+		if field.IsValid() && field.CanInterface() {
+			if value, ok := field.Interface().(decimal.Decimal); ok {
+				return value, true
+			}
+		}
+	}
+
+	field := fl.Field()
+
+	switch field.Kind() { //nolint:exhaustive
+	case reflect.Float32, reflect.Float64:
+		return decimal.NewFromFloat(field.Float()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return decimal.NewFromInt(field.Int()), true
+	case reflect.String:
+		value, err := decimal.NewFromString(field.String())
+
+		return value, err == nil
+	default:
+		return decimal.Decimal{}, false
+	}
+}
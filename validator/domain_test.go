@@ -0,0 +1,149 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/andyle182810/gframework/validator"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+type DomainRequest struct {
+	Phone    string          `json:"phone"    validate:"omitempty,e164"`
+	IBAN     string          `json:"iban"     validate:"omitempty,iban"`
+	ID       string          `json:"id"       validate:"omitempty,ulid"`
+	Slug     string          `json:"slug"     validate:"omitempty,slug"`
+	Timezone string          `json:"timezone" validate:"omitempty,timezone"`
+	Country  string          `json:"country"  validate:"omitempty,country_code"`
+	Password string          `json:"password" validate:"omitempty,password_strength=3"`
+	Amount   decimal.Decimal `json:"amount"   validate:"decimal_gt=0.1,decimal_lte=1000"`
+	Price    string          `json:"price"    validate:"omitempty,decimal_gte=0"`
+}
+
+func validDomainRequest() DomainRequest {
+	return DomainRequest{
+		Phone:    "+84912345678",
+		IBAN:     "DE89 3704 0044 0532 0130 00",
+		ID:       "01ARZ3NDEKTSV4RRFFQ69G5FAV",
+		Slug:     "summer-sale-2025",
+		Timezone: "Asia/Ho_Chi_Minh",
+		Country:  "VN",
+		Password: "Secret-pass",
+		Amount:   decimal.RequireFromString("0.1000000000000000001"),
+		Price:    "12.50",
+	}
+}
+
+func TestDomainValidations_Valid(t *testing.T) {
+	t.Parallel()
+
+	v := validator.DefaultRestValidator()
+	req := validDomainRequest()
+
+	require.NoError(t, v.Validate(&req))
+
+	req.Country = "VNM"
+	req.IBAN = "GB82WEST12345698765432"
+	require.NoError(t, v.Validate(&req))
+}
+
+func TestDomainValidations_Messages(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mutate   func(*DomainRequest)
+		field    string
+		expected string
+	}{
+		{
+			name:     "e164",
+			mutate:   func(r *DomainRequest) { r.Phone = "0912345678" },
+			field:    "phone",
+			expected: "phone must be a valid E.164 phone number",
+		},
+		{
+			name:     "iban checksum",
+			mutate:   func(r *DomainRequest) { r.IBAN = "DE89370400440532013001" },
+			field:    "iban",
+			expected: "iban must be a valid IBAN",
+		},
+		{
+			name:     "iban length",
+			mutate:   func(r *DomainRequest) { r.IBAN = "DE8937040044053201300" },
+			field:    "iban",
+			expected: "iban must be a valid IBAN",
+		},
+		{
+			name:     "ulid",
+			mutate:   func(r *DomainRequest) { r.ID = "not-a-ulid" },
+			field:    "id",
+			expected: "id must be a valid ULID",
+		},
+		{
+			name:     "slug",
+			mutate:   func(r *DomainRequest) { r.Slug = "Summer--Sale" },
+			field:    "slug",
+			expected: "slug must contain only lowercase letters, digits and single hyphens",
+		},
+		{
+			name:     "timezone",
+			mutate:   func(r *DomainRequest) { r.Timezone = "Mars/Olympus" },
+			field:    "timezone",
+			expected: "timezone must be a valid IANA time zone",
+		},
+		{
+			name:     "country code",
+			mutate:   func(r *DomainRequest) { r.Country = "XX" },
+			field:    "country",
+			expected: "country must be a valid ISO 3166-1 country code",
+		},
+		{
+			name:     "password strength",
+			mutate:   func(r *DomainRequest) { r.Password = "secretpassword1" },
+			field:    "password",
+			expected: "password must contain at least 3 of: lowercase letters, uppercase letters, digits, symbols",
+		},
+		{
+			name:     "decimal gt",
+			mutate:   func(r *DomainRequest) { r.Amount = decimal.RequireFromString("0.1") },
+			field:    "amount",
+			expected: "amount must be greater than 0.1",
+		},
+		{
+			name:     "decimal lte",
+			mutate:   func(r *DomainRequest) { r.Amount = decimal.RequireFromString("1000.0000000000000001") },
+			field:    "amount",
+			expected: "amount must be less than or equal to 1000",
+		},
+		{
+			name:     "decimal gte on string",
+			mutate:   func(r *DomainRequest) { r.Price = "-0.01" },
+			field:    "price",
+			expected: "price must be greater than or equal to 0",
+		},
+		{
+			name:     "decimal on unparsable string",
+			mutate:   func(r *DomainRequest) { r.Price = "abc" },
+			field:    "price",
+			expected: "price must be greater than or equal to 0",
+		},
+	}
+
+	v := validator.DefaultRestValidator()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			req := validDomainRequest()
+			tt.mutate(&req)
+
+			err := v.Validate(&req)
+
+			messages := validationMessages(t, err)
+			require.Len(t, messages, 1)
+			require.Equal(t, tt.expected, messages[tt.field])
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/ja"
+	"github.com/go-playground/locales/vi"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	jaTranslations "github.com/go-playground/validator/v10/translations/ja"
+	viTranslations "github.com/go-playground/validator/v10/translations/vi"
+	"golang.org/x/text/language"
+)
+
+const (
+	LocaleEnglish    = "en"
+	LocaleVietnamese = "vi"
+	LocaleJapanese   = "ja"
+)
+
+var ErrUnsupportedLocale = errors.New("validator: locale is not supported")
+
+type localeSupport struct {
+	translator locales.Translator
+	register   func(v *validator.Validate, trans ut.Translator) error
+}
+
+var supportedLocales = map[string]localeSupport{
+	LocaleEnglish:    {translator: en.New(), register: enTranslations.RegisterDefaultTranslations},
+	LocaleVietnamese: {translator: vi.New(), register: viTranslations.RegisterDefaultTranslations},
+	LocaleJapanese:   {translator: ja.New(), register: jaTranslations.RegisterDefaultTranslations},
+}
+
+type localeKey struct{}
+
+// ContextWithLocale selects the language of validation messages for ValidateCtx. locale is either a
+// locale such as "vi" or an Accept-Language header value such as "vi-VN,vi;q=0.9,en;q=0.8"; it is
+// matched against the locales enabled with EnableTranslations.
+func ContextWithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+func LocaleFromContext(ctx context.Context) string {
+	locale, _ := ctx.Value(localeKey{}).(string)
+
+	return locale
+}
+
+type translations struct {
+	locales    []string
+	matcher    language.Matcher
+	translator map[string]ut.Translator
+	messages   map[string]map[string]string
+}
+
+// EnableTranslations translates messages into the given locales (en, vi and ja are supported) when
+// the context passed to ValidateCtx carries a matching locale. Messages stay in English otherwise, and
+// for tags without a translation.
+func (v *Validator) EnableTranslations(localeNames ...string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.translations == nil {
+		v.translations = &translations{
+			locales:    []string{LocaleEnglish},
+			matcher:    nil,
+			translator: make(map[string]ut.Translator),
+			messages:   make(map[string]map[string]string),
+		}
+	}
+
+	for _, locale := range localeNames {
+		support, ok := supportedLocales[locale]
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrUnsupportedLocale, locale)
+		}
+
+		if _, ok := v.translations.translator[locale]; ok || locale == LocaleEnglish {
+			continue
+		}
+
+		uni := ut.New(support.translator, support.translator)
+		trans, _ := uni.GetTranslator(locale)
+
+		if err := support.register(v.Validator, trans); err != nil {
+			return fmt.Errorf("validator: failed to register %q translations: %w", locale, err)
+		}
+
+		v.translations.translator[locale] = trans
+		v.translations.locales = append(v.translations.locales, locale)
+	}
+
+	tags := make([]language.Tag, 0, len(v.translations.locales))
+	for _, locale := range v.translations.locales {
+		tags = append(tags, language.Make(locale))
+	}
+
+	v.translations.matcher = language.NewMatcher(tags)
+
+	return nil
+}
+
+// RegisterTranslation sets the message of tag in locale, e.g. for custom or async tags. The format
+// receives the field name, like WithAsyncMessage. The locale must have been enabled with
+// EnableTranslations.
+func (v *Validator) RegisterTranslation(locale, tag, format string) error {
+	if locale == LocaleEnglish {
+		v.setMessage(tag, format)
+
+		return nil
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.translations == nil || v.translations.translator[locale] == nil {
+		return fmt.Errorf("%w: %q", ErrUnsupportedLocale, locale)
+	}
+
+	if v.translations.messages[locale] == nil {
+		v.translations.messages[locale] = make(map[string]string)
+	}
+
+	v.translations.messages[locale][tag] = format
+
+	return nil
+}
+
+// resolveLocale returns the enabled locale that best matches the locale in ctx, or "" for English.
+func (v *Validator) resolveLocale(ctx context.Context) string {
+	requested := LocaleFromContext(ctx)
+	if requested == "" {
+		return ""
+	}
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	if v.translations == nil || len(v.translations.locales) == 1 {
+		return ""
+	}
+
+	_, index := language.MatchStrings(v.translations.matcher, requested)
+	if locale := v.translations.locales[index]; locale != LocaleEnglish {
+		return locale
+	}
+
+	return ""
+}
+
+// translate returns the message for err in locale, or false when there is no translation.
+func (v *Validator) translate(locale, field string, err validator.FieldError) (string, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	if format, ok := v.translations.messages[locale][err.Tag()]; ok {
+		return fmt.Sprintf(format, field), true
+	}
+
+	trans := v.translations.translator[locale]
+
+	msg := err.Translate(trans)
+	if msg == err.Error() {
+		return "", false
+	}
+
+	// Translations only know the field name, e.g. "price" instead of "items[2].price".
+	return strings.Replace(msg, err.Field(), field, 1), true
+}
@@ -0,0 +1,104 @@
+package validator_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/andyle182810/gframework/validator"
+	gvalidator "github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/require"
+)
+
+type LocalizedRequest struct {
+	Name string `json:"name" validate:"required"`
+	Code string `json:"code" validate:"required,promo_code"`
+}
+
+func newLocalizedValidator(t *testing.T) *validator.Validator {
+	t.Helper()
+
+	v := validator.DefaultRestValidator()
+	require.NoError(t, v.EnableTranslations(validator.LocaleVietnamese, validator.LocaleJapanese))
+
+	return v
+}
+
+func validationMessages(t *testing.T, err error) map[string]string {
+	t.Helper()
+
+	var validationErrs validator.ValidationErrors
+	require.ErrorAs(t, err, &validationErrs)
+
+	messages := make(map[string]string, len(validationErrs))
+	for _, validationErr := range validationErrs {
+		messages[validationErr.Field] = validationErr.Message
+	}
+
+	return messages
+}
+
+func TestValidateCtx_TranslatesMessages(t *testing.T) {
+	t.Parallel()
+
+	v := newLocalizedValidator(t)
+
+	type request struct {
+		Name string `json:"name" validate:"required"`
+	}
+
+	tests := []struct {
+		name     string
+		locale   string
+		expected string
+	}{
+		{name: "no locale", locale: "", expected: "name is required"},
+		{name: "english", locale: "en", expected: "name is required"},
+		{name: "vietnamese", locale: "vi", expected: "name không được bỏ trống"},
+		{name: "japanese", locale: "ja", expected: "nameは必須フィールドです"},
+		{name: "accept language", locale: "vi-VN,vi;q=0.9,en;q=0.8", expected: "name không được bỏ trống"},
+		{name: "unsupported falls back to english", locale: "fr-FR", expected: "name is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := validator.ContextWithLocale(context.Background(), tt.locale)
+
+			err := v.ValidateCtx(ctx, &request{})
+			require.Equal(t, tt.expected, validationMessages(t, err)["name"])
+		})
+	}
+}
+
+func TestValidateCtx_UntranslatedTagFallsBackToEnglish(t *testing.T) {
+	t.Parallel()
+
+	v := newLocalizedValidator(t)
+	err := v.RegisterCustomValidation("promo_code", func(_ gvalidator.FieldLevel) bool {
+		return false
+	})
+	require.NoError(t, err)
+
+	ctx := validator.ContextWithLocale(context.Background(), "vi")
+
+	err = v.ValidateCtx(ctx, &LocalizedRequest{Name: "a", Code: "x"})
+	require.Equal(t, "code failed validation on 'promo_code'", validationMessages(t, err)["code"])
+
+	require.NoError(t, v.RegisterTranslation(validator.LocaleVietnamese, "promo_code", "%s không hợp lệ"))
+
+	err = v.ValidateCtx(ctx, &LocalizedRequest{Name: "a", Code: "x"})
+	require.Equal(t, "code không hợp lệ", validationMessages(t, err)["code"])
+}
+
+func TestEnableTranslations_UnsupportedLocale(t *testing.T) {
+	t.Parallel()
+
+	v := validator.DefaultRestValidator()
+
+	err := v.EnableTranslations("xx")
+	require.ErrorIs(t, err, validator.ErrUnsupportedLocale)
+
+	err = v.RegisterTranslation(validator.LocaleJapanese, "promo_code", "%s")
+	require.ErrorIs(t, err, validator.ErrUnsupportedLocale)
+}
@@ -0,0 +1,119 @@
+package validator
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/labstack/echo/v5"
+)
+
+const (
+	SourceQuery  = "query"
+	SourceHeader = "header"
+	SourcePath   = "path"
+)
+
+type requestSource struct {
+	name   string
+	tag    string
+	label  string
+	bind   func(c *echo.Context, target any) error
+	target any
+}
+
+// ValidateRequest binds and validates the query params, headers and path params of the request into
+// query, header and path, any of which may be nil, e.g.
+//
+//	type ListHeaders struct {
+//	    TenantID string `header:"X-Tenant-ID" validate:"required,uuid"`
+//	}
+//
+// Failures of all parts are returned together as ValidationErrors with Source set and the fields
+// named by their query, header or param tag, e.g. "header X-Tenant-ID is required". Binding errors
+// are returned as is.
+func ValidateRequest(c *echo.Context, query, header, path any) error {
+	sources := []requestSource{
+		{name: SourceQuery, tag: "query", label: "query parameter", bind: echo.BindQueryParams, target: query},
+		{name: SourceHeader, tag: "header", label: "header", bind: echo.BindHeaders, target: header},
+		{name: SourcePath, tag: "param", label: "path parameter", bind: echo.BindPathValues, target: path},
+	}
+
+	var failures ValidationErrors
+
+	for _, source := range sources {
+		if source.target == nil {
+			continue
+		}
+
+		if err := source.bind(c, source.target); err != nil {
+			return err
+		}
+
+		err := ValidateEcho(c, source.target)
+		if err == nil {
+			continue
+		}
+
+		var validationErrs ValidationErrors
+		if !errors.As(err, &validationErrs) {
+			return err
+		}
+
+		failures = append(failures, source.annotate(validationErrs)...)
+	}
+
+	if len(failures) > 0 {
+		return failures
+	}
+
+	return nil
+}
+
+// annotate renames the fields of errs after their source tag and sets Source.
+func (s requestSource) annotate(errs ValidationErrors) ValidationErrors {
+	structType := elemType(reflect.TypeOf(s.target))
+
+	annotated := make(ValidationErrors, 0, len(errs))
+
+	for _, validationErr := range errs {
+		name := validationErr.Field
+
+		if structType.Kind() == reflect.Struct {
+			if tagged := s.taggedName(structType, validationErr.Field); tagged != "" {
+				name = tagged
+			}
+		}
+
+		validationErr.Message = strings.Replace(validationErr.Message, validationErr.Field, s.label+" "+name, 1)
+		validationErr.Field = name
+		validationErr.Source = s.name
+
+		annotated = append(annotated, validationErr)
+	}
+
+	return annotated
+}
+
+// taggedName returns the source tag of the field that ValidationError reports as field, which is its
+// JSON name or, without one, its Go name.
+func (s requestSource) taggedName(structType reflect.Type, field string) string {
+	for i := range structType.NumField() {
+		structField := structType.Field(i)
+
+		name := jsonTagName(structField)
+		if name == "" {
+			name = structField.Name
+		}
+
+		if name != field {
+			continue
+		}
+
+		tagged, _, _ := strings.Cut(structField.Tag.Get(s.tag), ",")
+
+		return tagged
+	}
+
+	return ""
+}
@@ -0,0 +1,125 @@
+package validator_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andyle182810/gframework/validator"
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/require"
+)
+
+type ListOrdersQuery struct {
+	Page   int    `query:"page"    validate:"omitempty,min=1"`
+	SortBy string `query:"sort_by" validate:"omitempty,oneof=created_at total"`
+}
+
+type ListOrdersHeaders struct {
+	TenantID string `header:"X-Tenant-ID" validate:"required,uuid"`
+}
+
+type ListOrdersPath struct {
+	StoreID string `param:"storeId" validate:"required,uuid"`
+}
+
+func newRequestContext(t *testing.T, target string, headers map[string]string, storeID string) *echo.Context {
+	t.Helper()
+
+	e := echo.New()
+	e.Validator = validator.DefaultRestValidator()
+
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	c := e.NewContext(req, httptest.NewRecorder())
+	c.SetPathValues(echo.PathValues{{Name: "storeId", Value: storeID}})
+
+	return c
+}
+
+func TestValidateRequest_Valid(t *testing.T) {
+	t.Parallel()
+
+	c := newRequestContext(t, "/stores/x/orders?page=2&sort_by=total",
+		map[string]string{"X-Tenant-ID": "3f8a1f0e-2c55-4f63-8d8e-6a1c2b9d4e10"},
+		"9b2f2c38-6c1e-4d59-9c0b-1f6d8f3f7a10")
+
+	var (
+		query   ListOrdersQuery
+		headers ListOrdersHeaders
+		path    ListOrdersPath
+	)
+
+	require.NoError(t, validator.ValidateRequest(c, &query, &headers, &path))
+	require.Equal(t, 2, query.Page)
+	require.Equal(t, "total", query.SortBy)
+	require.Equal(t, "3f8a1f0e-2c55-4f63-8d8e-6a1c2b9d4e10", headers.TenantID)
+	require.Equal(t, "9b2f2c38-6c1e-4d59-9c0b-1f6d8f3f7a10", path.StoreID)
+}
+
+func TestValidateRequest_ReportsSources(t *testing.T) {
+	t.Parallel()
+
+	c := newRequestContext(t, "/stores/x/orders?page=-1", nil, "not-a-uuid")
+
+	var (
+		query   ListOrdersQuery
+		headers ListOrdersHeaders
+		path    ListOrdersPath
+	)
+
+	err := validator.ValidateRequest(c, &query, &headers, &path)
+
+	var validationErrs validator.ValidationErrors
+	require.ErrorAs(t, err, &validationErrs)
+	require.Equal(t, validator.ValidationErrors{
+		{
+			Field:   "page",
+			Tag:     "min",
+			Value:   "-1",
+			Message: "query parameter page must be at least 1",
+			Source:  validator.SourceQuery,
+		},
+		{
+			Field:   "X-Tenant-ID",
+			Tag:     "required",
+			Value:   "",
+			Message: "header X-Tenant-ID is required",
+			Source:  validator.SourceHeader,
+		},
+		{
+			Field:   "storeId",
+			Tag:     "uuid",
+			Value:   "not-a-uuid",
+			Message: "path parameter storeId must be a valid UUID",
+			Source:  validator.SourcePath,
+		},
+	}, validationErrs)
+}
+
+func TestValidateRequest_SkipsNilParts(t *testing.T) {
+	t.Parallel()
+
+	c := newRequestContext(t, "/stores/x/orders", nil, "")
+
+	var query ListOrdersQuery
+
+	require.NoError(t, validator.ValidateRequest(c, &query, nil, nil))
+}
+
+func TestValidateRequest_BindError(t *testing.T) {
+	t.Parallel()
+
+	c := newRequestContext(t, "/stores/x/orders?page=abc", nil, "")
+
+	var query ListOrdersQuery
+
+	err := validator.ValidateRequest(c, &query, nil, nil)
+
+	var httpErr *echo.HTTPError
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusBadRequest, httpErr.Code)
+}
@@ -0,0 +1,323 @@
+package validator
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Schema is the JSON Schema fragment describing a request struct and the constraints of its validate
+// tags, for use in OpenAPI documents.
+type Schema struct {
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum     *float64           `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     *float64           `json:"exclusiveMaximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+//nolint:gochecknoglobals
+var (
+	timeType    = reflect.TypeFor[time.Time]()
+	decimalType = reflect.TypeFor[decimal.Decimal]()
+
+	schemaFormats = map[string]string{
+		"email":    "email",
+		"url":      "uri",
+		"uri":      "uri",
+		"uuid":     "uuid",
+		"uuid4":    "uuid",
+		"datetime": "date-time",
+		"ip":       "ip",
+		"ipv4":     "ipv4",
+		"ipv6":     "ipv6",
+		"hostname": "hostname",
+	}
+
+	schemaPatterns = map[string]string{
+		"alphanum": "^[a-zA-Z0-9]+$",
+		"numeric":  "^[-+]?[0-9]+(?:\\.[0-9]+)?$",
+		"e164":     "^\\+[1-9]?[0-9]{7,14}$",
+		"ulid":     "^[0-9A-HJKMNP-TV-Za-hjkmnp-tv-z]{26}$",
+		"slug":     slugRegexp.String(),
+	}
+)
+
+// RegisterSchema records the request struct of structType under name for Schemas, e.g.
+// v.RegisterSchema("CreateUserRequest", CreateUserRequest{}).
+func (v *Validator) RegisterSchema(name string, structType any) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.schemas == nil {
+		v.schemas = make(map[string]reflect.Type)
+	}
+
+	v.schemas[name] = reflect.TypeOf(structType)
+}
+
+// Schemas returns the schema of every struct registered with RegisterSchema by name, ready to be
+// placed under components.schemas of an OpenAPI document.
+func (v *Validator) Schemas() map[string]*Schema {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	schemas := make(map[string]*Schema, len(v.schemas))
+	for name, structType := range v.schemas {
+		schemas[name] = schemaForType(structType, map[reflect.Type]bool{})
+	}
+
+	return schemas
+}
+
+// SchemaOf returns the schema of value, typically a request struct. Fields are named like in
+// ValidationErrors and constraints are derived from the validate tags: required, min, max, len, gt,
+// gte, lt, lte and their decimal_ variants, oneof as enum, and formats such as email, uuid or e164.
+func SchemaOf(value any) *Schema {
+	return schemaForType(reflect.TypeOf(value), map[reflect.Type]bool{})
+}
+
+func schemaForType(t reflect.Type, visiting map[reflect.Type]bool) *Schema {
+	schema := &Schema{} //nolint:exhaustruct
+	if t == nil {
+		return schema
+	}
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		schema.Type, schema.Format = "string", "date-time"
+
+		return schema
+	case t == decimalType:
+		schema.Type, schema.Format = "string", "decimal"
+
+		return schema
+	}
+
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.Bool:
+		schema.Type = "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		schema.Type = "integer"
+	case reflect.Float32, reflect.Float64:
+		schema.Type = "number"
+	case reflect.String:
+		schema.Type = "string"
+	case reflect.Slice, reflect.Array:
+		schema.Type = "array"
+		schema.Items = schemaForType(t.Elem(), visiting)
+	case reflect.Map:
+		schema.Type = "object"
+		schema.AdditionalProperties = schemaForType(t.Elem(), visiting)
+	case reflect.Struct:
+		schema.Type = "object"
+
+		// Recursive types are described without their properties the second time round.
+		if visiting[t] {
+			return schema
+		}
+
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		schema.Properties = make(map[string]*Schema)
+		addStructProperties(schema, t, visiting)
+	}
+
+	return schema
+}
+
+func addStructProperties(schema *Schema, t reflect.Type, visiting map[reflect.Type]bool) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() || field.Tag.Get("json") == "-" {
+			continue
+		}
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+
+		if field.Anonymous && fieldType.Kind() == reflect.Struct && jsonTagName(field) == "" {
+			addStructProperties(schema, fieldType, visiting)
+
+			continue
+		}
+
+		name := jsonTagName(field)
+		if name == "" {
+			name = field.Name
+		}
+
+		property := schemaForType(field.Type, visiting)
+		if applyTags(property, fieldType, field.Tag.Get("validate")) {
+			schema.Required = append(schema.Required, name)
+		}
+
+		schema.Properties[name] = property
+	}
+}
+
+// applyTags adds the constraints of the validate tag to schema and reports whether it has required.
+// Constraints after dive apply to the items.
+func applyTags(schema *Schema, t reflect.Type, tag string) bool {
+	if tag == "" {
+		return false
+	}
+
+	fieldTags, itemTags, hasDive := strings.Cut(","+tag, ",dive")
+	if hasDive && schema.Items != nil {
+		itemType := t.Elem()
+		for itemType.Kind() == reflect.Pointer {
+			itemType = itemType.Elem()
+		}
+
+		applyTags(schema.Items, itemType, strings.TrimPrefix(itemTags, ","))
+	}
+
+	required := false
+
+	for rule := range strings.SplitSeq(fieldTags, ",") {
+		// Alternatives such as "email|e164" cannot be expressed without oneOf.
+		if rule == "" || strings.Contains(rule, "|") {
+			continue
+		}
+
+		name, param, _ := strings.Cut(rule, "=")
+		if name == "required" {
+			required = true
+
+			continue
+		}
+
+		applyRule(schema, name, strings.ReplaceAll(param, "0x2C", ","))
+	}
+
+	return required
+}
+
+func applyRule(schema *Schema, name, param string) { //nolint:cyclop
+	if format, ok := schemaFormats[name]; ok {
+		schema.Format = format
+
+		return
+	}
+
+	if pattern, ok := schemaPatterns[name]; ok {
+		schema.Pattern = pattern
+
+		return
+	}
+
+	switch name {
+	case "regexp":
+		schema.Pattern = param
+	case "oneof":
+		schema.Enum = enumValues(schema.Type, param)
+	case "len":
+		applyBound(schema, param, "gte")
+		applyBound(schema, param, "lte")
+	case "min", "gte", "decimal_gte":
+		applyBound(schema, param, "gte")
+	case "max", "lte", "decimal_lte":
+		applyBound(schema, param, "lte")
+	case "gt", "decimal_gt":
+		applyBound(schema, param, "gt")
+	case "lt", "decimal_lt":
+		applyBound(schema, param, "lt")
+	}
+}
+
+// applyBound sets a numeric bound, or a length or item count bound for strings and arrays.
+func applyBound(schema *Schema, param, relation string) {
+	value, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+
+	switch schema.Type {
+	case "array", "object":
+		setCount(&schema.MinItems, &schema.MaxItems, value, relation)
+	case "string":
+		if schema.Format != "decimal" {
+			setCount(&schema.MinLength, &schema.MaxLength, value, relation)
+
+			return
+		}
+
+		setNumber(schema, value, relation)
+	default:
+		setNumber(schema, value, relation)
+	}
+}
+
+func setNumber(schema *Schema, value float64, relation string) {
+	switch relation {
+	case "gte":
+		schema.Minimum = &value
+	case "lte":
+		schema.Maximum = &value
+	case "gt":
+		schema.ExclusiveMinimum = &value
+	case "lt":
+		schema.ExclusiveMaximum = &value
+	}
+}
+
+func setCount(minCount, maxCount **int, value float64, relation string) {
+	count := int(value)
+
+	switch relation {
+	case "gte":
+		*minCount = &count
+	case "lte":
+		*maxCount = &count
+	case "gt":
+		count++
+		*minCount = &count
+	case "lt":
+		count--
+		*maxCount = &count
+	}
+}
+
+func enumValues(schemaType, param string) []any {
+	words := strings.Fields(param)
+	values := make([]any, 0, len(words))
+
+	for _, word := range words {
+		word = strings.Trim(word, "'")
+
+		if schemaType == "integer" || schemaType == "number" {
+			if number, err := strconv.ParseFloat(word, 64); err == nil {
+				values = append(values, number)
+
+				continue
+			}
+		}
+
+		values = append(values, word)
+	}
+
+	return values
+}
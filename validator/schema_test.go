@@ -0,0 +1,78 @@
+package validator_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/validator"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+type SchemaAddress struct {
+	Country string `json:"country" validate:"required,country_code"`
+	Zip     string `json:"zip"     validate:"omitempty,len=5,numeric"`
+}
+
+type SchemaRequest struct {
+	Name     string          `json:"name"       validate:"required,min=2,max=100"`
+	Email    string          `json:"email"      validate:"required,email"`
+	Age      int             `json:"age"        validate:"gte=0,lte=150"`
+	Role     string          `json:"role"       validate:"oneof=admin member"`
+	Level    int             `json:"level"      validate:"oneof=1 2 3"`
+	Amount   decimal.Decimal `json:"amount"     validate:"decimal_gt=0"`
+	Phone    *string         `json:"phone"      validate:"omitempty,e164"`
+	Tags     []string        `json:"tags"       validate:"max=5,dive,min=1,max=20"`
+	Address  SchemaAddress   `json:"address"`
+	StartsAt time.Time       `json:"starts_at"`
+	Contact  string          `json:"contact"    validate:"email|e164"`
+	Internal string          `json:"-"`
+	Parent   *SchemaRequest  `json:"parent"`
+}
+
+func TestSchemaOf(t *testing.T) {
+	t.Parallel()
+
+	data, err := json.Marshal(validator.SchemaOf(SchemaRequest{}))
+	require.NoError(t, err)
+
+	require.JSONEq(t, `{
+		"type": "object",
+		"required": ["name", "email"],
+		"properties": {
+			"name": {"type": "string", "minLength": 2, "maxLength": 100},
+			"email": {"type": "string", "format": "email"},
+			"age": {"type": "integer", "minimum": 0, "maximum": 150},
+			"role": {"type": "string", "enum": ["admin", "member"]},
+			"level": {"type": "integer", "enum": [1, 2, 3]},
+			"amount": {"type": "string", "format": "decimal", "exclusiveMinimum": 0},
+			"phone": {"type": "string", "pattern": "^\\+[1-9]?[0-9]{7,14}$"},
+			"tags": {"type": "array", "maxItems": 5, "items": {"type": "string", "minLength": 1, "maxLength": 20}},
+			"address": {
+				"type": "object",
+				"required": ["country"],
+				"properties": {
+					"country": {"type": "string"},
+					"zip": {"type": "string", "minLength": 5, "maxLength": 5, "pattern": "^[-+]?[0-9]+(?:\\.[0-9]+)?$"}
+				}
+			},
+			"starts_at": {"type": "string", "format": "date-time"},
+			"contact": {"type": "string"},
+			"parent": {"type": "object"}
+		}
+	}`, string(data))
+}
+
+func TestSchemas(t *testing.T) {
+	t.Parallel()
+
+	v := validator.DefaultRestValidator()
+	v.RegisterSchema("SchemaAddress", SchemaAddress{})
+	v.RegisterSchema("SchemaRequest", &SchemaRequest{})
+
+	schemas := v.Schemas()
+	require.Len(t, schemas, 2)
+	require.Equal(t, []string{"country"}, schemas["SchemaAddress"].Required)
+	require.Equal(t, "email", schemas["SchemaRequest"].Properties["email"].Format)
+}
@@ -2,8 +2,9 @@
 // with support for decimal types and JSON-based field naming.
 //
 // The validator converts struct validation errors to a simple ValidationError slice with
-// JSON-friendly field names (from struct tags, not Go field names), which is safe for
-// inclusion in API error responses. It implements the Echo validator interface.
+// JSON-friendly field paths (from struct tags, not Go field names, e.g. "items[2].price"), which is
+// safe for inclusion in API error responses. It implements the Echo validator interface, and
+// EchoBinder binds and validates requests in one step.
 //
 // Basic usage:
 //
@@ -18,15 +19,36 @@
 //	    // err is ValidationErrors with JSON field names
 //	}
 //
-// Custom validation tags and rules can be registered via the underlying Validator field.
+// Besides the go-playground tags, DefaultRestValidator registers iban, slug, password_strength=N
+// (characters from N of lowercase, uppercase, digits and symbols) and decimal_gt, decimal_gte,
+// decimal_lt and decimal_lte, which compare decimals exactly instead of as float64.
+//
+// Cross-field tags such as required_without_all, gtfield and ltfield (which compare time.Time and
+// decimal fields) get messages naming the referenced fields by their JSON names. Groups of fields that
+// exclude each other are validated with MutuallyExclusive and ExactlyOneOf via RegisterStructRules.
+//
+// Custom validation tags and rules can be registered via the underlying Validator field. Tags that
+// need IO (database or remote lookups) are registered with RegisterAsyncValidation and run with the
+// request context through ValidateCtx.
+//
+// Messages are in English unless translations are enabled with EnableTranslations and the context
+// passed to ValidateCtx carries a locale set with ContextWithLocale. httpserver sets it from the
+// Accept-Language header:
+//
+//	v := validator.DefaultRestValidator()
+//	if err := v.EnableTranslations(validator.LocaleVietnamese, validator.LocaleJapanese); err != nil {
+//	    return err
+//	}
 package validator
 
 import (
-	"errors"
+	"context"
 	"fmt"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/shopspring/decimal"
@@ -34,6 +56,11 @@ import (
 
 type Validator struct {
 	Validator *validator.Validate
+
+	mu           sync.RWMutex
+	messages     map[string]string
+	translations *translations
+	schemas      map[string]reflect.Type
 }
 
 type ValidationError struct {
@@ -41,6 +68,9 @@ type ValidationError struct {
 	Tag     string `json:"tag"`
 	Value   string `json:"value"`
 	Message string `json:"message"`
+	// Source is the part of the request the field was read from when validated with ValidateRequest:
+	// "query", "header" or "path".
+	Source string `json:"source,omitempty"`
 }
 
 type ValidationErrors []ValidationError
@@ -57,16 +87,7 @@ func (v ValidationErrors) Error() string {
 func DefaultRestValidator() *Validator {
 	v := validator.New()
 
-	v.RegisterTagNameFunc(func(fld reflect.StructField) string {
-		const maxSplits = 2
-		name := strings.SplitN(fld.Tag.Get("json"), ",", maxSplits)[0]
-
-		if name == "-" {
-			return ""
-		}
-
-		return name
-	})
+	v.RegisterTagNameFunc(jsonTagName)
 
 	v.RegisterCustomTypeFunc(func(field reflect.Value) any {
 		if val, ok := field.Interface().(decimal.Decimal); ok {
@@ -90,43 +111,94 @@ func DefaultRestValidator() *Validator {
 		return re.MatchString(value)
 	})
 
-	return &Validator{Validator: v}
+	registerDomainValidations(v)
+
+	return &Validator{
+		Validator:    v,
+		mu:           sync.RWMutex{},
+		messages:     make(map[string]string),
+		translations: nil,
+		schemas:      make(map[string]reflect.Type),
+	}
 }
 
-func (v *Validator) Validate(i any) error {
-	if err := v.Validator.Struct(i); err != nil {
-		var validationErrs validator.ValidationErrors
-		if errors.As(err, &validationErrs) {
-			return v.formatValidationErrors(validationErrs)
-		}
+func jsonTagName(fld reflect.StructField) string {
+	const maxSplits = 2
+	name := strings.SplitN(fld.Tag.Get("json"), ",", maxSplits)[0]
 
-		return err
+	if name == "-" {
+		return ""
 	}
 
-	return nil
+	return name
 }
 
-func (v *Validator) formatValidationErrors(errs validator.ValidationErrors) ValidationErrors {
+func (v *Validator) Validate(i any) error {
+	return v.ValidateCtx(context.Background(), i)
+}
+
+// formatValidationErrors converts errs for the validated value of type root, which is used to report
+// the JSON names of fields referenced by cross-field tags.
+func (v *Validator) formatValidationErrors(
+	errs validator.ValidationErrors,
+	root reflect.Type,
+	locale string,
+) ValidationErrors {
 	validationErrs := make(ValidationErrors, 0, len(errs))
 
 	for _, err := range errs {
-		field := err.Field()
-		if field == "" {
-			field = err.StructField()
-		}
+		field := fieldPath(err)
 
 		validationErrs = append(validationErrs, ValidationError{
 			Field:   field,
 			Tag:     err.Tag(),
 			Value:   fmt.Sprintf("%v", err.Value()),
-			Message: v.generateErrorMessage(field, err),
+			Message: v.localizedErrorMessage(locale, field, root, err),
+			Source:  "",
 		})
 	}
 
 	return validationErrs
 }
 
-func (v *Validator) generateErrorMessage(field string, err validator.FieldError) string {
+// fieldPath returns the JSON path of the field, e.g. "items[2].price", by dropping the struct type
+// name the namespace starts with.
+func fieldPath(err validator.FieldError) string {
+	namespace := err.Namespace()
+	if _, path, found := strings.Cut(namespace, "."); found && path != "" {
+		return path
+	}
+
+	if field := err.Field(); field != "" {
+		return field
+	}
+
+	return err.StructField()
+}
+
+func (v *Validator) localizedErrorMessage(
+	locale, field string,
+	root reflect.Type,
+	err validator.FieldError,
+) string {
+	if locale != "" {
+		if msg, ok := v.translate(locale, field, err); ok {
+			return msg
+		}
+	}
+
+	return v.generateErrorMessage(field, root, err)
+}
+
+func (v *Validator) generateErrorMessage(field string, root reflect.Type, err validator.FieldError) string {
+	if format, ok := v.message(err.Tag()); ok {
+		return fmt.Sprintf(format, field)
+	}
+
+	if msg, ok := crossFieldMessage(field, crossFieldParam(root, err), err); ok {
+		return msg
+	}
+
 	msg := v.getSimpleErrorMessage(field, err.Tag())
 	if msg != "" {
 		return msg
@@ -151,6 +223,18 @@ func (v *Validator) getSimpleErrorMessage(field, tag string) string {
 		return field + " must be numeric"
 	case "uuid":
 		return field + " must be a valid UUID"
+	case "e164":
+		return field + " must be a valid E.164 phone number"
+	case "iban":
+		return field + " must be a valid IBAN"
+	case "ulid":
+		return field + " must be a valid ULID"
+	case "slug":
+		return field + " must contain only lowercase letters, digits and single hyphens"
+	case "timezone":
+		return field + " must be a valid IANA time zone"
+	case "country_code":
+		return field + " must be a valid ISO 3166-1 country code"
 	default:
 		return ""
 	}
@@ -179,11 +263,30 @@ func (v *Validator) getParameterizedErrorMessage(field string, err validator.Fie
 		return fmt.Sprintf("%s must be one of [%s]", field, param)
 	case "regexp":
 		return field + " must match the required pattern"
+	case "password_strength":
+		return passwordStrengthMessage(field, param)
+	case "decimal_gt":
+		return fmt.Sprintf("%s must be greater than %s", field, param)
+	case "decimal_gte":
+		return fmt.Sprintf("%s must be greater than or equal to %s", field, param)
+	case "decimal_lt":
+		return fmt.Sprintf("%s must be less than %s", field, param)
+	case "decimal_lte":
+		return fmt.Sprintf("%s must be less than or equal to %s", field, param)
 	default:
 		return fmt.Sprintf("%s failed validation on '%s'", field, err.Tag())
 	}
 }
 
+func passwordStrengthMessage(field, param string) string {
+	if param == "" {
+		param = strconv.Itoa(defaultPasswordClasses)
+	}
+
+	return fmt.Sprintf("%s must contain at least %s of: lowercase letters, uppercase letters, digits, symbols",
+		field, param)
+}
+
 func (v *Validator) RegisterCustomValidation(tag string, fn validator.Func) error {
 	return v.Validator.RegisterValidation(tag, fn)
 }
@@ -191,3 +294,23 @@ func (v *Validator) RegisterCustomValidation(tag string, fn validator.Func) erro
 func (v *Validator) RegisterStructValidation(fn validator.StructLevelFunc, types ...any) {
 	v.Validator.RegisterStructValidation(fn, types...)
 }
+
+func (v *Validator) setMessage(tag, format string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.messages == nil {
+		v.messages = make(map[string]string)
+	}
+
+	v.messages[tag] = format
+}
+
+func (v *Validator) message(tag string) (string, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	format, ok := v.messages[tag]
+
+	return format, ok
+}
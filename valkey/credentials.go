@@ -0,0 +1,68 @@
+package valkey
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CredentialsProvider supplies the username and password used to authenticate new connections.
+type CredentialsProvider interface {
+	Credentials(ctx context.Context) (username, password string, err error)
+}
+
+// CredentialsFunc adapts a function to CredentialsProvider.
+type CredentialsFunc func(ctx context.Context) (username, password string, err error)
+
+func (f CredentialsFunc) Credentials(ctx context.Context) (string, string, error) {
+	return f(ctx)
+}
+
+// CachedCredentialsProvider reuses credentials from another provider for a fixed TTL, so that opening
+// connections does not fetch a new token every time. Set the TTL below the token lifetime, e.g. 10
+// minutes for 15-minute IAM tokens.
+type CachedCredentialsProvider struct {
+	provider  CredentialsProvider
+	ttl       time.Duration
+	mu        sync.Mutex
+	username  string
+	password  string
+	expiresAt time.Time
+}
+
+func NewCachedCredentialsProvider(provider CredentialsProvider, ttl time.Duration) *CachedCredentialsProvider {
+	return &CachedCredentialsProvider{ //nolint:exhaustruct
+		provider: provider,
+		ttl:      ttl,
+	}
+}
+
+// Credentials returns the cached credentials, fetching new ones once they have expired. A failed
+// fetch is returned to the caller and retried on the next call.
+func (p *CachedCredentialsProvider) Credentials(ctx context.Context) (string, string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if time.Now().Before(p.expiresAt) {
+		return p.username, p.password, nil
+	}
+
+	username, password, err := p.provider.Credentials(ctx)
+	if err != nil {
+		return "", "", fmt.Errorf("valkey: failed to fetch credentials: %w", err)
+	}
+
+	p.username, p.password = username, password
+	p.expiresAt = time.Now().Add(p.ttl)
+
+	return username, password, nil
+}
+
+// Invalidate drops the cached credentials, e.g. after an authentication error.
+func (p *CachedCredentialsProvider) Invalidate() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.expiresAt = time.Time{}
+}
@@ -0,0 +1,96 @@
+//nolint:exhaustruct
+package valkey_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/andyle182810/gframework/valkey"
+	"github.com/stretchr/testify/require"
+)
+
+var errTokenSource = errors.New("token source unavailable")
+
+func TestCachedCredentialsProvider(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	var fetches atomic.Int32
+
+	provider := valkey.NewCachedCredentialsProvider(valkey.CredentialsFunc(
+		func(context.Context) (string, string, error) {
+			n := fetches.Add(1)
+
+			return "app", "token-" + strconv.Itoa(int(n)), nil
+		},
+	), 50*time.Millisecond)
+
+	username, password, err := provider.Credentials(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "app", username)
+	require.Equal(t, "token-1", password)
+
+	_, password, err = provider.Credentials(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "token-1", password)
+
+	time.Sleep(60 * time.Millisecond)
+
+	_, password, err = provider.Credentials(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "token-2", password)
+
+	provider.Invalidate()
+
+	_, password, err = provider.Credentials(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "token-3", password)
+}
+
+func TestCachedCredentialsProvider_Error(t *testing.T) {
+	t.Parallel()
+
+	provider := valkey.NewCachedCredentialsProvider(valkey.CredentialsFunc(
+		func(context.Context) (string, string, error) {
+			return "", "", errTokenSource
+		},
+	), time.Minute)
+
+	_, _, err := provider.Credentials(t.Context())
+	require.ErrorIs(t, err, errTokenSource)
+}
+
+func TestValkeyCredentialsProvider(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	container := testutil.SetupValkeyContainer(t)
+
+	port, err := strconv.Atoi(container.Port.Port())
+	require.NoError(t, err)
+
+	admin, err := valkey.New(&valkey.Config{Host: container.Host, Port: port, DB: container.DB})
+	require.NoError(t, err)
+	require.NoError(t, admin.Do(ctx, "ACL", "SETUSER", "app", "on", ">secret-token", "~*", "+@all").Err())
+
+	client, err := valkey.New(&valkey.Config{
+		Host: container.Host,
+		Port: port,
+		DB:   container.DB,
+		CredentialsProvider: valkey.CredentialsFunc(func(context.Context) (string, string, error) {
+			return "app", "secret-token", nil
+		}),
+	})
+	require.NoError(t, err)
+	require.NoError(t, client.Start(ctx))
+
+	whoami, err := client.Do(ctx, "ACL", "WHOAMI").Text()
+	require.NoError(t, err)
+	require.Equal(t, "app", whoami)
+}
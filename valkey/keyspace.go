@@ -0,0 +1,207 @@
+package valkey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	KeyspaceEventExpired = "expired"
+	KeyspaceEventDel     = "del"
+	KeyspaceEventSet     = "set"
+	KeyspaceEventEvicted = "evicted"
+
+	notifyKeyspaceEventsParam = "notify-keyspace-events"
+)
+
+var (
+	ErrNoKeyPatterns          = errors.New("valkey: at least one key pattern is required")
+	ErrNilKeyspaceHandler     = errors.New("valkey: keyspace handler cannot be nil")
+	ErrKeyspaceNotifyDisabled = errors.New("valkey: failed to enable keyspace notifications")
+)
+
+// notifyFlags maps events to the notify-keyspace-events flag class that emits them.
+var notifyFlags = map[string]byte{
+	KeyspaceEventExpired: 'x',
+	KeyspaceEventDel:     'g',
+	KeyspaceEventSet:     '$',
+	KeyspaceEventEvicted: 'e',
+}
+
+// KeyspaceEvent is a change to Key, e.g. {Key: "session:1", Event: "expired"}.
+type KeyspaceEvent struct {
+	Key   string
+	Event string
+}
+
+type KeyspaceHandler func(ctx context.Context, event KeyspaceEvent) error
+
+type KeyspaceOption func(*KeyspaceWatcher)
+
+// WithKeyspaceEvents selects the events passed to the handler. It defaults to expired and del.
+func WithKeyspaceEvents(events ...string) KeyspaceOption {
+	return func(w *KeyspaceWatcher) {
+		if len(events) > 0 {
+			w.events = events
+		}
+	}
+}
+
+// WithManagedNotifyConfig skips enabling notifications with CONFIG SET on Start, for servers where
+// notify-keyspace-events is managed outside the application, e.g. a cloud parameter group.
+func WithManagedNotifyConfig() KeyspaceOption {
+	return func(w *KeyspaceWatcher) {
+		w.configure = false
+	}
+}
+
+func WithKeyspacePubSubOptions(opts ...PubSubOption) KeyspaceOption {
+	return func(w *KeyspaceWatcher) {
+		w.pubSubOpts = append(w.pubSubOpts, opts...)
+	}
+}
+
+// KeyspaceWatcher dispatches keyspace notifications for keys matching glob patterns, e.g. to act on
+// expired sessions without polling. Notifications are fire-and-forget, so events that happen while
+// the watcher is disconnected are lost.
+type KeyspaceWatcher struct {
+	*PubSubSubscriber
+
+	client     redis.UniversalClient
+	handler    KeyspaceHandler
+	events     []string
+	configure  bool
+	pubSubOpts []PubSubOption
+}
+
+func NewKeyspaceWatcher(
+	client redis.UniversalClient,
+	patterns []string,
+	handler KeyspaceHandler,
+	opts ...KeyspaceOption,
+) (*KeyspaceWatcher, error) {
+	if client == nil {
+		return nil, ErrValkeyPoolNil
+	}
+
+	if len(patterns) == 0 {
+		return nil, ErrNoKeyPatterns
+	}
+
+	if handler == nil {
+		return nil, ErrNilKeyspaceHandler
+	}
+
+	watcher := &KeyspaceWatcher{
+		PubSubSubscriber: nil,
+		client:           client,
+		handler:          handler,
+		events:           []string{KeyspaceEventExpired, KeyspaceEventDel},
+		configure:        true,
+		pubSubOpts:       nil,
+	}
+
+	for _, opt := range opts {
+		opt(watcher)
+	}
+
+	prefix := fmt.Sprintf("__keyspace@%d__:", clientDB(client))
+
+	channels := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		channels = append(channels, prefix+pattern)
+	}
+
+	pubSubOpts := append([]PubSubOption{WithPubSubPatterns(), WithPubSubName("valkey-keyspace-watcher")}, watcher.pubSubOpts...)
+
+	subscriber, err := NewPubSubSubscriber(client, channels, func(ctx context.Context, channel string, payload []byte) error {
+		return watcher.dispatch(ctx, strings.TrimPrefix(channel, prefix), string(payload))
+	}, pubSubOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	watcher.PubSubSubscriber = subscriber
+
+	return watcher, nil
+}
+
+// Start enables the notifications the watcher needs, unless WithManagedNotifyConfig is set, and
+// handles events until ctx is cancelled or Stop is called.
+func (w *KeyspaceWatcher) Start(ctx context.Context) error {
+	if w.configure {
+		if err := w.enableNotifications(ctx); err != nil {
+			return err
+		}
+	}
+
+	return w.PubSubSubscriber.Start(ctx)
+}
+
+func (w *KeyspaceWatcher) dispatch(ctx context.Context, key, event string) error {
+	if !slices.Contains(w.events, event) {
+		return nil
+	}
+
+	return w.handler(ctx, KeyspaceEvent{Key: key, Event: event})
+}
+
+// enableNotifications adds the flags for the watched events to notify-keyspace-events, keeping the
+// flags that are already set.
+func (w *KeyspaceWatcher) enableNotifications(ctx context.Context) error {
+	current, err := w.client.ConfigGet(ctx, notifyKeyspaceEventsParam).Result()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrKeyspaceNotifyDisabled, err)
+	}
+
+	flags := current[notifyKeyspaceEventsParam]
+	required := "K"
+
+	for _, event := range w.events {
+		if flag, ok := notifyFlags[event]; ok {
+			required += string(flag)
+		}
+	}
+
+	merged := flags
+
+	for _, flag := range required {
+		// A is an alias for every event class except key miss and new key events.
+		coveredByAll := flag != 'K' && strings.ContainsRune(merged, 'A')
+		if !coveredByAll && !strings.ContainsRune(merged, flag) {
+			merged += string(flag)
+		}
+	}
+
+	if merged == flags {
+		return nil
+	}
+
+	if err := w.client.ConfigSet(ctx, notifyKeyspaceEventsParam, merged).Err(); err != nil {
+		return fmt.Errorf("%w: %w", ErrKeyspaceNotifyDisabled, err)
+	}
+
+	log.Info().
+		Str("source", "gframework").
+		Str("notify_keyspace_events", merged).
+		Msg("Keyspace notifications have been enabled")
+
+	return nil
+}
+
+func clientDB(client redis.UniversalClient) int {
+	switch c := client.(type) {
+	case *redis.Client:
+		return c.Options().DB
+	case *Valkey:
+		return c.Options().DB
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,94 @@
+//nolint:exhaustruct
+package valkey_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/andyle182810/gframework/valkey"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyspaceWatcher(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	container := testutil.SetupValkeyContainer(t)
+
+	port, err := strconv.Atoi(container.Port.Port())
+	require.NoError(t, err)
+
+	client, err := valkey.New(&valkey.Config{Host: container.Host, Port: port, DB: container.DB})
+	require.NoError(t, err)
+
+	events := make(chan valkey.KeyspaceEvent, 4)
+
+	watcher, err := valkey.NewKeyspaceWatcher(client, []string{"session:*"},
+		func(_ context.Context, event valkey.KeyspaceEvent) error {
+			events <- event
+
+			return nil
+		},
+	)
+	require.NoError(t, err)
+
+	errCh := make(chan error, 1)
+
+	go func() { errCh <- watcher.Start(ctx) }()
+
+	require.Eventually(t, watcher.IsHealthy, 5*time.Second, 10*time.Millisecond)
+
+	flags, err := client.ConfigGet(ctx, "notify-keyspace-events").Result()
+	require.NoError(t, err)
+	require.Contains(t, flags["notify-keyspace-events"], "K")
+
+	require.NoError(t, client.Set(ctx, "other:1", "value", 0).Err())
+	require.NoError(t, client.Del(ctx, "other:1").Err())
+	require.NoError(t, client.Set(ctx, "session:1", "value", 0).Err())
+	require.NoError(t, client.Del(ctx, "session:1").Err())
+	require.NoError(t, client.Set(ctx, "session:2", "value", 100*time.Millisecond).Err())
+
+	expected := []valkey.KeyspaceEvent{
+		{Key: "session:1", Event: valkey.KeyspaceEventDel},
+		{Key: "session:2", Event: valkey.KeyspaceEventExpired},
+	}
+
+	for _, want := range expected {
+		select {
+		case got := <-events:
+			require.Equal(t, want, got)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("event %v was not received", want)
+		}
+	}
+
+	require.NoError(t, watcher.Stop())
+	require.NoError(t, <-errCh)
+}
+
+func TestNewKeyspaceWatcher_Validation(t *testing.T) {
+	t.Parallel()
+
+	handler := func(context.Context, valkey.KeyspaceEvent) error { return nil }
+
+	_, err := valkey.NewKeyspaceWatcher(nil, []string{"session:*"}, handler)
+	require.ErrorIs(t, err, valkey.ErrValkeyPoolNil)
+
+	client, err := valkey.New(&valkey.Config{Host: "localhost", Port: 6379})
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = client.Close() })
+
+	_, err = valkey.NewKeyspaceWatcher(client, nil, handler)
+	require.ErrorIs(t, err, valkey.ErrNoKeyPatterns)
+
+	_, err = valkey.NewKeyspaceWatcher(client, []string{"session:*"}, nil)
+	require.ErrorIs(t, err, valkey.ErrNilKeyspaceHandler)
+
+	watcher, err := valkey.NewKeyspaceWatcher(client, []string{"session:*"}, handler)
+	require.NoError(t, err)
+	require.Equal(t, "valkey-keyspace-watcher", watcher.Name())
+}
@@ -0,0 +1,37 @@
+package valkey
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// KeyLocker claims keys for a single caller until they expire, e.g. so that only one replica runs a
+// given worker pool tick. It satisfies workerpool.TickLocker.
+type KeyLocker struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+func NewKeyLocker(client redis.UniversalClient, prefix string) (*KeyLocker, error) {
+	if client == nil {
+		return nil, ErrValkeyPoolNil
+	}
+
+	return &KeyLocker{
+		client: client,
+		prefix: prefix,
+	}, nil
+}
+
+// TryAcquire claims key for ttl and reports whether this caller got it.
+func (l *KeyLocker) TryAcquire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	acquired, err := l.client.SetNX(ctx, l.prefix+key, 1, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("valkey: failed to claim %s: %w", l.prefix+key, err)
+	}
+
+	return acquired, nil
+}
@@ -0,0 +1,43 @@
+//nolint:exhaustruct
+package valkey_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/andyle182810/gframework/valkey"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyLocker(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	container := testutil.SetupValkeyContainer(t)
+
+	port, err := strconv.Atoi(container.Port.Port())
+	require.NoError(t, err)
+
+	client, err := valkey.New(&valkey.Config{Host: container.Host, Port: port, DB: container.DB})
+	require.NoError(t, err)
+
+	first, err := valkey.NewKeyLocker(client.Client, "ticks:")
+	require.NoError(t, err)
+
+	second, err := valkey.NewKeyLocker(client.Client, "ticks:")
+	require.NoError(t, err)
+
+	claimed, err := first.TryAcquire(ctx, "pool:1", time.Minute)
+	require.NoError(t, err)
+	require.True(t, claimed)
+
+	claimed, err = second.TryAcquire(ctx, "pool:1", time.Minute)
+	require.NoError(t, err)
+	require.False(t, claimed)
+
+	claimed, err = second.TryAcquire(ctx, "pool:2", time.Minute)
+	require.NoError(t, err)
+	require.True(t, claimed)
+}
@@ -0,0 +1,163 @@
+package valkey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/andyle182810/gframework/metricserver"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	commandDial     = "dial"
+	commandPipeline = "pipeline"
+)
+
+// Metrics receives the outcome of every command. Pipelines are reported as a single "pipeline"
+// command and connection attempts as "dial". redis.Nil replies are not errors.
+type Metrics interface {
+	CommandFinished(command string, duration time.Duration, err error)
+}
+
+// metricsHook is a redis.Hook reporting to Metrics.
+type metricsHook struct {
+	metrics Metrics
+}
+
+func (h *metricsHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		startedAt := time.Now()
+		conn, err := next(ctx, network, addr)
+		h.metrics.CommandFinished(commandDial, time.Since(startedAt), err)
+
+		return conn, err
+	}
+}
+
+func (h *metricsHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		startedAt := time.Now()
+		err := next(ctx, cmd)
+		h.metrics.CommandFinished(strings.ToLower(cmd.Name()), time.Since(startedAt), commandError(err))
+
+		return err
+	}
+}
+
+func (h *metricsHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		startedAt := time.Now()
+		err := next(ctx, cmds)
+		h.metrics.CommandFinished(commandPipeline, time.Since(startedAt), commandError(err))
+
+		return err
+	}
+}
+
+func commandError(err error) error {
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+
+	return err
+}
+
+// PrometheusMetrics implements Metrics with Prometheus collectors.
+type PrometheusMetrics struct {
+	duration *prometheus.HistogramVec
+	errors   *prometheus.CounterVec
+}
+
+// NewPrometheusMetrics registers the command metrics with reg, e.g. the registerer of metricserver.
+// Set it as Config.Metrics.
+func NewPrometheusMetrics(reg prometheus.Registerer) (*PrometheusMetrics, error) {
+	m := &PrometheusMetrics{
+		duration: metricserver.NewHistogramVec("valkey", "command_duration_seconds",
+			"Duration of commands, including pipelines and connection attempts.",
+			metricserver.LatencyBuckets(), "command"),
+		errors: metricserver.NewCounterVec("valkey", "command_errors_total",
+			"Number of commands that failed.", "command"),
+	}
+
+	if err := metricserver.Register(reg, m.duration, m.errors); err != nil {
+		return nil, fmt.Errorf("valkey: failed to register metrics: %w", err)
+	}
+
+	return m, nil
+}
+
+func (m *PrometheusMetrics) CommandFinished(command string, duration time.Duration, err error) {
+	m.duration.WithLabelValues(command).Observe(duration.Seconds())
+
+	if err != nil {
+		m.errors.WithLabelValues(command).Inc()
+	}
+}
+
+// RegisterPoolMetrics registers collectors reading v's connection pool stats at scrape time, e.g. to
+// spot pool exhaustion from rising timeouts and misses.
+func RegisterPoolMetrics(reg prometheus.Registerer, v *Valkey) error {
+	if v == nil || v.Client == nil {
+		return ErrValkeyPoolNil
+	}
+
+	if err := reg.Register(newPoolCollector(v)); err != nil {
+		return fmt.Errorf("valkey: failed to register pool metrics: %w", err)
+	}
+
+	return nil
+}
+
+type poolCollector struct {
+	valkey     *Valkey
+	hits       *prometheus.Desc
+	misses     *prometheus.Desc
+	timeouts   *prometheus.Desc
+	totalConns *prometheus.Desc
+	idleConns  *prometheus.Desc
+	staleConns *prometheus.Desc
+}
+
+func newPoolCollector(v *Valkey) *poolCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName("gframework", "valkey", name), help, nil, nil)
+	}
+
+	return &poolCollector{
+		valkey:     v,
+		hits:       desc("pool_hits_total", "Number of times a free connection was found in the pool."),
+		misses:     desc("pool_misses_total", "Number of times a free connection was not found in the pool."),
+		timeouts:   desc("pool_timeouts_total", "Number of times waiting for a connection timed out."),
+		totalConns: desc("pool_connections", "Number of connections in the pool."),
+		idleConns:  desc("pool_idle_connections", "Number of idle connections in the pool."),
+		staleConns: desc("pool_stale_connections_total", "Number of stale connections removed from the pool."),
+	}
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.hits
+	ch <- c.misses
+	ch <- c.timeouts
+	ch <- c.totalConns
+	ch <- c.idleConns
+	ch <- c.staleConns
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.valkey.PoolStats()
+	if stats == nil {
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(stats.Hits))
+	ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(stats.Misses))
+	ch <- prometheus.MustNewConstMetric(c.timeouts, prometheus.CounterValue, float64(stats.Timeouts))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stats.TotalConns))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stats.IdleConns))
+	ch <- prometheus.MustNewConstMetric(c.staleConns, prometheus.CounterValue, float64(stats.StaleConns))
+}
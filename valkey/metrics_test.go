@@ -0,0 +1,72 @@
+//nolint:exhaustruct
+package valkey_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/andyle182810/gframework/valkey"
+	"github.com/prometheus/client_golang/prometheus"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusMetrics_RecordsFailedCommands(t *testing.T) {
+	t.Parallel()
+
+	reg := prometheus.NewRegistry()
+
+	metrics, err := valkey.NewPrometheusMetrics(reg)
+	require.NoError(t, err)
+
+	client, err := valkey.New(&valkey.Config{
+		Host:        "127.0.0.1",
+		Port:        1,
+		DialTimeout: 100 * time.Millisecond,
+		MaxRetries:  -1,
+		Metrics:     metrics,
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = client.Close() })
+
+	require.Error(t, client.Get(t.Context(), "key").Err())
+
+	require.Equal(t, 2, promtestutil.CollectAndCount(reg, "gframework_valkey_command_errors_total"))
+	require.Equal(t, 2, promtestutil.CollectAndCount(reg, "gframework_valkey_command_duration_seconds"))
+}
+
+func TestPrometheusMetrics_RecordsCommandsAndPoolStats(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	container := testutil.SetupValkeyContainer(t)
+
+	port, err := strconv.Atoi(container.Port.Port())
+	require.NoError(t, err)
+
+	reg := prometheus.NewRegistry()
+
+	metrics, err := valkey.NewPrometheusMetrics(reg)
+	require.NoError(t, err)
+
+	client, err := valkey.New(&valkey.Config{Host: container.Host, Port: port, DB: container.DB, Metrics: metrics})
+	require.NoError(t, err)
+	require.NoError(t, valkey.RegisterPoolMetrics(reg, client))
+
+	require.NoError(t, client.Set(ctx, "key", "value", time.Minute).Err())
+	require.ErrorIs(t, client.Get(ctx, "missing").Err(), redis.Nil)
+
+	require.Equal(t, 0, promtestutil.CollectAndCount(reg, "gframework_valkey_command_errors_total"))
+	require.GreaterOrEqual(t, promtestutil.CollectAndCount(reg, "gframework_valkey_command_duration_seconds"), 2)
+	require.Equal(t, 1, promtestutil.CollectAndCount(reg, "gframework_valkey_pool_connections"))
+}
+
+func TestRegisterPoolMetrics_NilClient(t *testing.T) {
+	t.Parallel()
+
+	require.ErrorIs(t, valkey.RegisterPoolMetrics(prometheus.NewRegistry(), nil), valkey.ErrValkeyPoolNil)
+}
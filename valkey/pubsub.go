@@ -0,0 +1,295 @@
+package valkey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultPubSubPingInterval    = 15 * time.Second
+	defaultPubSubReconnectDelay  = time.Second
+	defaultPubSubShutdownTimeout = 5 * time.Second
+)
+
+var (
+	ErrNoChannels           = errors.New("valkey: at least one channel is required")
+	ErrNilPubSubHandler     = errors.New("valkey: pub/sub handler cannot be nil")
+	ErrPubSubAlreadyRunning = errors.New("valkey: pub/sub subscriber is already running")
+	ErrPubSubHandlerPanic   = errors.New("valkey: pub/sub handler panicked")
+)
+
+// PubSubHandler handles a message published on channel. Errors and panics are logged; the message is
+// not redelivered.
+type PubSubHandler func(ctx context.Context, channel string, payload []byte) error
+
+type PubSubOption func(*PubSubSubscriber)
+
+// WithPubSubPatterns subscribes to the channels as glob patterns, e.g. "invalidate:*".
+func WithPubSubPatterns() PubSubOption {
+	return func(s *PubSubSubscriber) {
+		s.patterns = true
+	}
+}
+
+func WithPubSubName(name string) PubSubOption {
+	return func(s *PubSubSubscriber) {
+		if name != "" {
+			s.name = name
+		}
+	}
+}
+
+// WithPubSubPingInterval sets how long the subscriber waits for a message before pinging the server
+// to detect a dead connection. It defaults to 15 seconds.
+func WithPubSubPingInterval(d time.Duration) PubSubOption {
+	return func(s *PubSubSubscriber) {
+		if d > 0 {
+			s.pingInterval = d
+		}
+	}
+}
+
+// WithPubSubReconnectDelay sets the delay before reconnecting after a connection error. It defaults
+// to one second.
+func WithPubSubReconnectDelay(d time.Duration) PubSubOption {
+	return func(s *PubSubSubscriber) {
+		if d > 0 {
+			s.reconnectDelay = d
+		}
+	}
+}
+
+// PubSubSubscriber consumes Redis pub/sub channels, e.g. cache invalidation broadcasts. Unlike
+// redissub, delivery is fire-and-forget: messages published while the subscriber is disconnected are
+// lost. The subscriber reconnects and resubscribes automatically and reports itself unhealthy while
+// it is not subscribed. Messages are handled one at a time in the order they arrive.
+type PubSubSubscriber struct {
+	client         redis.UniversalClient
+	channels       []string
+	handler        PubSubHandler
+	name           string
+	patterns       bool
+	pingInterval   time.Duration
+	reconnectDelay time.Duration
+	healthy        atomic.Bool
+	running        atomic.Bool
+	mu             sync.Mutex
+	pubsub         *redis.PubSub
+	stop           chan struct{}
+	stopped        chan struct{}
+}
+
+func NewPubSubSubscriber(
+	client redis.UniversalClient,
+	channels []string,
+	handler PubSubHandler,
+	opts ...PubSubOption,
+) (*PubSubSubscriber, error) {
+	if client == nil {
+		return nil, ErrValkeyPoolNil
+	}
+
+	if len(channels) == 0 {
+		return nil, ErrNoChannels
+	}
+
+	if handler == nil {
+		return nil, ErrNilPubSubHandler
+	}
+
+	//nolint:exhaustruct
+	sub := &PubSubSubscriber{
+		client:         client,
+		channels:       channels,
+		handler:        handler,
+		name:           "valkey-pubsub-" + strings.Join(channels, ","),
+		patterns:       false,
+		pingInterval:   defaultPubSubPingInterval,
+		reconnectDelay: defaultPubSubReconnectDelay,
+	}
+
+	for _, opt := range opts {
+		opt(sub)
+	}
+
+	return sub, nil
+}
+
+func (s *PubSubSubscriber) Name() string {
+	return s.name
+}
+
+func (s *PubSubSubscriber) IsHealthy() bool {
+	return s.healthy.Load()
+}
+
+// Start subscribes and handles messages until ctx is cancelled or Stop is called.
+func (s *PubSubSubscriber) Start(ctx context.Context) error {
+	// Starting under s.mu keeps a concurrent Stop from seeing the subscriber running before the
+	// subscription exists, which would leave it running.
+	s.mu.Lock()
+	if !s.running.CompareAndSwap(false, true) {
+		s.mu.Unlock()
+
+		return ErrPubSubAlreadyRunning
+	}
+
+	s.stop = make(chan struct{})
+	s.stopped = make(chan struct{})
+	s.pubsub = s.subscribe(ctx)
+	pubsub, stop, stopped := s.pubsub, s.stop, s.stopped
+	s.mu.Unlock()
+
+	defer close(stopped)
+	defer s.healthy.Store(false)
+
+	log.Info().
+		Str("source", "gframework").
+		Str("service_name", s.name).
+		Strs("channels", s.channels).
+		Msg("Pub/sub subscriber is starting")
+
+	for {
+		received, err := pubsub.ReceiveTimeout(ctx, s.pingInterval)
+
+		select {
+		case <-stop:
+			return nil
+		default:
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if err != nil {
+			if isTimeout(err) {
+				if err = pubsub.Ping(ctx); err == nil {
+					continue
+				}
+			}
+
+			s.healthy.Store(false)
+
+			log.Warn().
+				Str("source", "gframework").
+				Err(err).
+				Str("service_name", s.name).
+				Dur("reconnect_delay", s.reconnectDelay).
+				Msg("Pub/sub connection failed, resubscribing")
+
+			select {
+			case <-stop:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(s.reconnectDelay):
+			}
+
+			continue
+		}
+
+		s.handleReceived(ctx, received)
+	}
+}
+
+func (s *PubSubSubscriber) Stop() error {
+	s.mu.Lock()
+	if !s.running.CompareAndSwap(true, false) {
+		s.mu.Unlock()
+
+		return nil
+	}
+
+	pubsub, stop, stopped := s.pubsub, s.stop, s.stopped
+	s.mu.Unlock()
+
+	close(stop)
+
+	if err := pubsub.Close(); err != nil {
+		log.Debug().Str("source", "gframework").Err(err).Str("service_name", s.name).Msg("Failed to close pub/sub")
+	}
+
+	select {
+	case <-stopped:
+	case <-time.After(defaultPubSubShutdownTimeout):
+		log.Error().
+			Str("source", "gframework").
+			Str("service_name", s.name).
+			Msg("Timeout waiting for pub/sub subscriber to stop")
+	}
+
+	log.Info().Str("source", "gframework").Str("service_name", s.name).Msg("Pub/sub subscriber stopped")
+
+	return nil
+}
+
+func (s *PubSubSubscriber) subscribe(ctx context.Context) *redis.PubSub {
+	if s.patterns {
+		return s.client.PSubscribe(ctx, s.channels...)
+	}
+
+	return s.client.Subscribe(ctx, s.channels...)
+}
+
+func (s *PubSubSubscriber) handleReceived(ctx context.Context, received any) {
+	switch msg := received.(type) {
+	case *redis.Subscription:
+		// go-redis resubscribes after a reconnect; the confirmation means messages flow again.
+		if msg.Kind == "subscribe" || msg.Kind == "psubscribe" {
+			s.healthy.Store(true)
+		}
+	case *redis.Pong:
+		s.healthy.Store(true)
+	case *redis.Message:
+		if err := s.handle(ctx, msg); err != nil {
+			log.Error().
+				Str("source", "gframework").
+				Err(err).
+				Str("service_name", s.name).
+				Str("channel", msg.Channel).
+				Msg("Pub/sub message handler failed")
+		}
+	default:
+		log.Debug().
+			Str("source", "gframework").
+			Str("service_name", s.name).
+			Str("type", fmt.Sprintf("%T", received)).
+			Msg("Ignoring unexpected pub/sub message")
+	}
+}
+
+// handle runs the handler for msg, recovering a panic so that it does not stop the receive loop.
+func (s *PubSubSubscriber) handle(ctx context.Context, msg *redis.Message) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			log.Error().
+				Str("source", "gframework").
+				Str("service_name", s.name).
+				Str("channel", msg.Channel).
+				Interface("panic", rec).
+				Bytes("stack", debug.Stack()).
+				Msg("Pub/sub message handler panicked")
+
+			err = fmt.Errorf("%w: %v", ErrPubSubHandlerPanic, rec)
+		}
+	}()
+
+	return s.handler(ctx, msg.Channel, []byte(msg.Payload))
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
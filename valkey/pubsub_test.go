@@ -0,0 +1,120 @@
+//nolint:exhaustruct
+package valkey_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/andyle182810/gframework/valkey"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPubSubSubscriber(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	// Pub/sub channels are shared by every database of an instance.
+	container := testutil.SetupValkeyContainer(t, testutil.WithDedicatedContainer())
+
+	port, err := strconv.Atoi(container.Port.Port())
+	require.NoError(t, err)
+
+	client, err := valkey.New(&valkey.Config{Host: container.Host, Port: port, DB: container.DB})
+	require.NoError(t, err)
+
+	received := make(chan string, 1)
+
+	sub, err := valkey.NewPubSubSubscriber(client.Client, []string{"invalidate:*"},
+		func(_ context.Context, channel string, payload []byte) error {
+			received <- channel + "=" + string(payload)
+
+			return nil
+		},
+		valkey.WithPubSubPatterns(),
+		valkey.WithPubSubName("cache-invalidation"),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "cache-invalidation", sub.Name())
+
+	errCh := make(chan error, 1)
+
+	go func() { errCh <- sub.Start(ctx) }()
+
+	require.Eventually(t, sub.IsHealthy, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, client.Publish(ctx, "invalidate:users", "user:1").Err())
+
+	select {
+	case msg := <-received:
+		require.Equal(t, "invalidate:users=user:1", msg)
+	case <-time.After(5 * time.Second):
+		t.Fatal("message was not received")
+	}
+
+	require.NoError(t, sub.Stop())
+	require.NoError(t, <-errCh)
+	require.False(t, sub.IsHealthy())
+}
+
+func TestPubSubSubscriber_RecoversHandlerPanics(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	client := testutil.NewFakeValkey(t).Client
+
+	received := make(chan string, 1)
+
+	sub, err := valkey.NewPubSubSubscriber(client, []string{"invalidate"},
+		func(_ context.Context, _ string, payload []byte) error {
+			if string(payload) == "boom" {
+				panic("handler bug")
+			}
+
+			received <- string(payload)
+
+			return nil
+		},
+	)
+	require.NoError(t, err)
+
+	errCh := make(chan error, 1)
+
+	go func() { errCh <- sub.Start(ctx) }()
+
+	require.Eventually(t, sub.IsHealthy, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, client.Publish(ctx, "invalidate", "boom").Err())
+	require.NoError(t, client.Publish(ctx, "invalidate", "user:1").Err())
+
+	select {
+	case payload := <-received:
+		require.Equal(t, "user:1", payload)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the subscriber stopped receiving after a handler panic")
+	}
+
+	require.NoError(t, sub.Stop())
+	require.NoError(t, <-errCh)
+}
+
+func TestNewPubSubSubscriber_Validation(t *testing.T) {
+	t.Parallel()
+
+	handler := func(context.Context, string, []byte) error { return nil }
+
+	_, err := valkey.NewPubSubSubscriber(nil, []string{"events"}, handler)
+	require.ErrorIs(t, err, valkey.ErrValkeyPoolNil)
+
+	client := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+	t.Cleanup(func() { _ = client.Close() })
+
+	_, err = valkey.NewPubSubSubscriber(client, nil, handler)
+	require.ErrorIs(t, err, valkey.ErrNoChannels)
+
+	_, err = valkey.NewPubSubSubscriber(client, []string{"events"}, nil)
+	require.ErrorIs(t, err, valkey.ErrNilPubSubHandler)
+}
@@ -0,0 +1,235 @@
+package valkey
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultRateLimitPrefix = "ratelimit:"
+	rateLimitNonceLength   = 8
+)
+
+var ErrInvalidRateLimit = errors.New("valkey: rate limit and window must be positive")
+
+type RateLimitAlgorithm int
+
+const (
+	// FixedWindow counts requests in consecutive windows starting with the first request. It is the
+	// cheapest algorithm but allows up to twice the limit across a window boundary.
+	FixedWindow RateLimitAlgorithm = iota
+	// SlidingWindow counts requests in the window ending now. It stores one entry per allowed request,
+	// so it suits low limits.
+	SlidingWindow
+	// TokenBucket refills limit tokens per window and allows bursts of up to limit requests.
+	TokenBucket
+)
+
+func (a RateLimitAlgorithm) String() string {
+	switch a {
+	case FixedWindow:
+		return "fixed_window"
+	case SlidingWindow:
+		return "sliding_window"
+	case TokenBucket:
+		return "token_bucket"
+	default:
+		return "unknown"
+	}
+}
+
+// All scripts take the limit and window in milliseconds and return
+// {allowed, remaining, retry_after_ms, reset_after_ms}. The sliding window and token bucket read the
+// server clock so that replicas agree on the time.
+
+var fixedWindowScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local current = redis.call("INCR", KEYS[1])
+if current == 1 then
+	redis.call("PEXPIRE", KEYS[1], window)
+end
+local ttl = redis.call("PTTL", KEYS[1])
+if ttl < 0 then
+	redis.call("PEXPIRE", KEYS[1], window)
+	ttl = window
+end
+if current <= limit then
+	return {1, limit - current, 0, ttl}
+end
+return {0, 0, ttl, ttl}
+`)
+
+var slidingWindowScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2]) * 1000
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now - window)
+local count = redis.call("ZCARD", KEYS[1])
+local allowed = 0
+if count < limit then
+	redis.call("ZADD", KEYS[1], now, now .. ":" .. ARGV[3])
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+	count = count + 1
+	allowed = 1
+end
+local oldest = redis.call("ZRANGE", KEYS[1], 0, 0, "WITHSCORES")
+local reset = math.ceil((tonumber(oldest[2]) + window - now) / 1000)
+if allowed == 1 then
+	return {1, limit - count, 0, reset}
+end
+return {0, 0, reset, reset}
+`)
+
+var tokenBucketScript = redis.NewScript(`
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + tonumber(time[2]) / 1000
+local rate = limit / window
+local state = redis.call("HMGET", KEYS[1], "tokens", "updated_at")
+local tokens = tonumber(state[1])
+local updated = tonumber(state[2])
+if tokens == nil or updated == nil then
+	tokens = limit
+	updated = now
+end
+tokens = math.min(limit, tokens + math.max(0, now - updated) * rate)
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) / rate)
+end
+redis.call("HSET", KEYS[1], "tokens", tokens, "updated_at", now)
+redis.call("PEXPIRE", KEYS[1], window)
+return {allowed, math.floor(tokens), retry, math.ceil((limit - tokens) / rate)}
+`)
+
+// RateLimitResult is the outcome of Allow. RetryAfter is zero when the request is allowed; ResetAfter
+// is the time until the full limit is available again.
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration
+	ResetAfter time.Duration
+}
+
+type RateLimiterOption func(*RateLimiter)
+
+// WithRateLimitAlgorithm selects the algorithm. It defaults to FixedWindow.
+func WithRateLimitAlgorithm(algorithm RateLimitAlgorithm) RateLimiterOption {
+	return func(l *RateLimiter) {
+		switch algorithm {
+		case FixedWindow, SlidingWindow, TokenBucket:
+			l.algorithm = algorithm
+		}
+	}
+}
+
+// WithRateLimitPrefix sets the prefix of the keys the limiter stores its state in. It defaults to
+// "ratelimit:".
+func WithRateLimitPrefix(prefix string) RateLimiterOption {
+	return func(l *RateLimiter) {
+		if prefix != "" {
+			l.prefix = prefix
+		}
+	}
+}
+
+// RateLimiter enforces request limits shared by all replicas. Each check is a single Lua script call,
+// so concurrent requests cannot exceed the limit.
+type RateLimiter struct {
+	client    redis.UniversalClient
+	algorithm RateLimitAlgorithm
+	prefix    string
+}
+
+func NewRateLimiter(client redis.UniversalClient, opts ...RateLimiterOption) (*RateLimiter, error) {
+	if client == nil {
+		return nil, ErrValkeyPoolNil
+	}
+
+	limiter := &RateLimiter{
+		client:    client,
+		algorithm: FixedWindow,
+		prefix:    defaultRateLimitPrefix,
+	}
+
+	for _, opt := range opts {
+		opt(limiter)
+	}
+
+	return limiter, nil
+}
+
+// Allow records a request for key and reports whether it is within limit requests per window.
+// Windows are rounded down to whole milliseconds.
+func (l *RateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (RateLimitResult, error) {
+	windowMs := window.Milliseconds()
+	if limit <= 0 || windowMs <= 0 {
+		return RateLimitResult{}, ErrInvalidRateLimit
+	}
+
+	redisKey := l.prefix + key
+	args := []any{limit, windowMs}
+
+	var script *redis.Script
+
+	switch l.algorithm {
+	case SlidingWindow:
+		nonce, err := rateLimitNonce()
+		if err != nil {
+			return RateLimitResult{}, err
+		}
+
+		script = slidingWindowScript
+		args = append(args, nonce)
+	case TokenBucket:
+		script = tokenBucketScript
+	case FixedWindow:
+		script = fixedWindowScript
+	}
+
+	values, err := script.Run(ctx, l.client, []string{redisKey}, args...).Int64Slice()
+	if err != nil {
+		return RateLimitResult{}, fmt.Errorf("valkey: failed to check rate limit %s: %w", redisKey, err)
+	}
+
+	return RateLimitResult{
+		Allowed:    values[0] == 1,
+		Limit:      limit,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+		ResetAfter: time.Duration(values[3]) * time.Millisecond,
+	}, nil
+}
+
+// Reset clears the state of key, e.g. after a successful login following failed attempts.
+func (l *RateLimiter) Reset(ctx context.Context, key string) error {
+	if err := l.client.Del(ctx, l.prefix+key).Err(); err != nil {
+		return fmt.Errorf("valkey: failed to reset rate limit %s: %w", l.prefix+key, err)
+	}
+
+	return nil
+}
+
+// rateLimitNonce makes sliding window entries of requests in the same microsecond unique.
+func rateLimitNonce() (string, error) {
+	nonce := make([]byte, rateLimitNonceLength)
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("valkey: failed to generate rate limit nonce: %w", err)
+	}
+
+	return hex.EncodeToString(nonce), nil
+}
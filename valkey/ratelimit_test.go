@@ -0,0 +1,98 @@
+//nolint:exhaustruct
+package valkey_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/andyle182810/gframework/valkey"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter_Algorithms(t *testing.T) {
+	t.Parallel()
+
+	container := testutil.SetupValkeyContainer(t)
+
+	port, err := strconv.Atoi(container.Port.Port())
+	require.NoError(t, err)
+
+	client, err := valkey.New(&valkey.Config{Host: container.Host, Port: port, DB: container.DB})
+	require.NoError(t, err)
+
+	algorithms := []valkey.RateLimitAlgorithm{valkey.FixedWindow, valkey.SlidingWindow, valkey.TokenBucket}
+
+	for _, algorithm := range algorithms {
+		t.Run(algorithm.String(), func(t *testing.T) {
+			t.Parallel()
+
+			ctx := t.Context()
+
+			limiter, err := valkey.NewRateLimiter(client.Client, valkey.WithRateLimitAlgorithm(algorithm))
+			require.NoError(t, err)
+
+			key := "tenant:" + algorithm.String()
+
+			for i := range 3 {
+				result, err := limiter.Allow(ctx, key, 3, time.Minute)
+				require.NoError(t, err)
+				require.True(t, result.Allowed)
+				require.Equal(t, 3, result.Limit)
+				require.Equal(t, 2-i, result.Remaining)
+				require.Zero(t, result.RetryAfter)
+			}
+
+			result, err := limiter.Allow(ctx, key, 3, time.Minute)
+			require.NoError(t, err)
+			require.False(t, result.Allowed)
+			require.Zero(t, result.Remaining)
+			require.Positive(t, result.RetryAfter)
+			require.LessOrEqual(t, result.RetryAfter, time.Minute)
+
+			require.NoError(t, limiter.Reset(ctx, key))
+
+			result, err = limiter.Allow(ctx, key, 3, time.Minute)
+			require.NoError(t, err)
+			require.True(t, result.Allowed)
+		})
+	}
+}
+
+func TestRateLimiter_WindowExpires(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	container := testutil.SetupValkeyContainer(t)
+
+	port, err := strconv.Atoi(container.Port.Port())
+	require.NoError(t, err)
+
+	client, err := valkey.New(&valkey.Config{Host: container.Host, Port: port, DB: container.DB})
+	require.NoError(t, err)
+
+	limiter, err := valkey.NewRateLimiter(client.Client, valkey.WithRateLimitAlgorithm(valkey.SlidingWindow))
+	require.NoError(t, err)
+
+	result, err := limiter.Allow(ctx, "login", 1, 200*time.Millisecond)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+
+	result, err = limiter.Allow(ctx, "login", 1, 200*time.Millisecond)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+
+	time.Sleep(250 * time.Millisecond)
+
+	result, err = limiter.Allow(ctx, "login", 1, 200*time.Millisecond)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+}
+
+func TestRateLimiter_Validation(t *testing.T) {
+	t.Parallel()
+
+	_, err := valkey.NewRateLimiter(nil)
+	require.ErrorIs(t, err, valkey.ErrValkeyPoolNil)
+}
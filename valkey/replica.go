@@ -0,0 +1,133 @@
+package valkey
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+type replica struct {
+	client  *redis.Client
+	healthy atomic.Bool
+}
+
+func newReplicas(primary *redis.Options, cfg *Config) []*replica {
+	replicas := make([]*replica, 0, len(cfg.ReadReplicaAddrs))
+
+	for _, addr := range cfg.ReadReplicaAddrs {
+		opt := *primary
+		opt.Addr = addr
+
+		r := &replica{client: newClient(&opt, cfg), healthy: atomic.Bool{}}
+		r.healthy.Store(true)
+
+		replicas = append(replicas, r)
+	}
+
+	return replicas
+}
+
+// ReadClient returns a client for read-only commands. Healthy replicas are used in turn; without
+// replicas, or when none is healthy, it returns the primary. Replicas replicate asynchronously, so
+// reads may briefly miss recent writes.
+func (v *Valkey) ReadClient() redis.UniversalClient {
+	count := len(v.replicas)
+
+	for range count {
+		candidate := v.replicas[v.nextReplica.Add(1)%uint64(count)]
+		if candidate.healthy.Load() {
+			return candidate.client
+		}
+	}
+
+	return v.Client
+}
+
+// HealthyReplicas returns the number of replicas ReadClient currently routes to.
+func (v *Valkey) HealthyReplicas() int {
+	healthy := 0
+
+	for _, r := range v.replicas {
+		if r.healthy.Load() {
+			healthy++
+		}
+	}
+
+	return healthy
+}
+
+func (v *Valkey) startReplicaChecks(ctx context.Context) {
+	if len(v.replicas) == 0 || v.stopChecks != nil {
+		return
+	}
+
+	v.checkReplicas(ctx)
+
+	checkCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	v.stopChecks = cancel
+	v.checksDone = make(chan struct{})
+
+	go func() {
+		defer close(v.checksDone)
+
+		ticker := time.NewTicker(v.checkInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-checkCtx.Done():
+				return
+			case <-ticker.C:
+				v.checkReplicas(checkCtx)
+			}
+		}
+	}()
+}
+
+func (v *Valkey) stopReplicaChecks() {
+	if v.stopChecks != nil {
+		v.stopChecks()
+		<-v.checksDone
+		v.stopChecks = nil
+	}
+
+	for _, r := range v.replicas {
+		if err := r.client.Close(); err != nil {
+			log.Warn().
+				Str("source", "gframework").
+				Err(err).
+				Str("replica_addr", r.client.Options().Addr).
+				Msg("Failed to close Valkey replica client")
+		}
+	}
+}
+
+func (v *Valkey) checkReplicas(ctx context.Context) {
+	for _, r := range v.replicas {
+		pingCtx, cancel := context.WithTimeout(ctx, defaultPingTimeout)
+		err := r.client.Ping(pingCtx).Err()
+
+		cancel()
+
+		healthy := err == nil
+		if r.healthy.Swap(healthy) == healthy {
+			continue
+		}
+
+		if healthy {
+			log.Info().
+				Str("source", "gframework").
+				Str("replica_addr", r.client.Options().Addr).
+				Msg("Valkey replica is healthy again")
+		} else {
+			log.Warn().
+				Str("source", "gframework").
+				Err(err).
+				Str("replica_addr", r.client.Options().Addr).
+				Msg("Valkey replica is unhealthy, routing reads elsewhere")
+		}
+	}
+}
@@ -0,0 +1,65 @@
+//nolint:exhaustruct
+package valkey_test
+
+import (
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/andyle182810/gframework/valkey"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValkeyReadClient_WithoutReplicas(t *testing.T) {
+	t.Parallel()
+
+	client, err := valkey.New(&valkey.Config{Host: "localhost", Port: 6379})
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = client.Close() })
+
+	require.Same(t, client.Client, client.ReadClient())
+	require.Zero(t, client.HealthyReplicas())
+}
+
+func TestValkeyReadClient_InvalidReplicaAddr(t *testing.T) {
+	t.Parallel()
+
+	_, err := valkey.New(&valkey.Config{Host: "localhost", Port: 6379, ReadReplicaAddrs: []string{"replica"}})
+	require.ErrorIs(t, err, valkey.ErrInvalidReplicaAddr)
+}
+
+func TestValkeyReadClient_SkipsUnhealthyReplicas(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	container := testutil.SetupValkeyContainer(t)
+
+	port, err := strconv.Atoi(container.Port.Port())
+	require.NoError(t, err)
+
+	replicaAddr := net.JoinHostPort(container.Host, container.Port.Port())
+
+	client, err := valkey.New(&valkey.Config{
+		Host:             container.Host,
+		Port:             port,
+		DB:               container.DB,
+		ReadReplicaAddrs: []string{replicaAddr, "127.0.0.1:1"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, client.Start(ctx))
+
+	t.Cleanup(func() { _ = client.Stop() })
+
+	require.Equal(t, 1, client.HealthyReplicas())
+
+	for range 4 {
+		reader, ok := client.ReadClient().(*redis.Client)
+		require.True(t, ok)
+		require.NotSame(t, client.Client, reader)
+		require.Equal(t, replicaAddr, reader.Options().Addr)
+		require.NoError(t, reader.Ping(ctx).Err())
+	}
+}
@@ -0,0 +1,162 @@
+package valkey
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	ErrScriptNotFound  = errors.New("valkey: script is not registered")
+	ErrDuplicateScript = errors.New("valkey: script is already registered")
+	ErrScriptLoad      = errors.New("valkey: failed to load script")
+)
+
+// builtinScripts are the scripts used by this package. Every registry preloads them so that their
+// first use does not pay for a NOSCRIPT round trip.
+func builtinScripts() map[string]*redis.Script {
+	return map[string]*redis.Script{
+		"valkey:ratelimit:fixed_window":   fixedWindowScript,
+		"valkey:ratelimit:sliding_window": slidingWindowScript,
+		"valkey:ratelimit:token_bucket":   tokenBucketScript,
+	}
+}
+
+// ResultDecoder converts the reply of a script, e.g. IntResult.
+type ResultDecoder[T any] func(cmd *redis.Cmd) (T, error)
+
+// ScriptFunc runs a registered script and decodes its reply.
+type ScriptFunc[T any] func(ctx context.Context, keys []string, args ...any) (T, error)
+
+func IntResult(cmd *redis.Cmd) (int64, error) {
+	return cmd.Int64()
+}
+
+func Int64SliceResult(cmd *redis.Cmd) ([]int64, error) {
+	return cmd.Int64Slice()
+}
+
+func TextResult(cmd *redis.Cmd) (string, error) {
+	return cmd.Text()
+}
+
+func BoolResult(cmd *redis.Cmd) (bool, error) {
+	return cmd.Bool()
+}
+
+// ScriptRegistry keeps the Lua scripts of an application in one place. Scripts are invoked with
+// EVALSHA and fall back to EVAL when the server does not know them, e.g. after a restart or failover.
+// The registry is a runner Service whose Start loads every script, so register it as infrastructure
+// after the valkey client.
+type ScriptRegistry struct {
+	client  redis.UniversalClient
+	mu      sync.RWMutex
+	scripts map[string]*redis.Script
+}
+
+func NewScriptRegistry(client redis.UniversalClient) (*ScriptRegistry, error) {
+	if client == nil {
+		return nil, ErrValkeyPoolNil
+	}
+
+	return &ScriptRegistry{
+		client:  client,
+		mu:      sync.RWMutex{},
+		scripts: builtinScripts(),
+	}, nil
+}
+
+// Register adds a script under name. Names are unique; to change a script, register it under a new
+// name so that callers of the old version keep working during a rollout.
+func (r *ScriptRegistry) Register(name, src string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.scripts[name]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateScript, name)
+	}
+
+	r.scripts[name] = redis.NewScript(src)
+
+	return nil
+}
+
+// RegisterScript registers src under name and returns a function that runs it and decodes the reply,
+// e.g. RegisterScript(registry, "quota:take", src, IntResult).
+func RegisterScript[T any](r *ScriptRegistry, name, src string, decode ResultDecoder[T]) (ScriptFunc[T], error) {
+	if err := r.Register(name, src); err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, keys []string, args ...any) (T, error) {
+		return decode(r.Run(ctx, name, keys, args...))
+	}, nil
+}
+
+// Run runs the script registered under name. The error of the returned command wraps
+// ErrScriptNotFound for unknown names.
+func (r *ScriptRegistry) Run(ctx context.Context, name string, keys []string, args ...any) *redis.Cmd {
+	r.mu.RLock()
+	script, ok := r.scripts[name]
+	r.mu.RUnlock()
+
+	if !ok {
+		cmd := redis.NewCmd(ctx)
+		cmd.SetErr(fmt.Errorf("%w: %s", ErrScriptNotFound, name))
+
+		return cmd
+	}
+
+	return script.Run(ctx, r.client, keys, args...)
+}
+
+// Load loads every registered script into the script cache of the server.
+func (r *ScriptRegistry) Load(ctx context.Context) error {
+	r.mu.RLock()
+	scripts := maps.Clone(r.scripts)
+	r.mu.RUnlock()
+
+	for name, script := range scripts {
+		if err := script.Load(ctx, r.client).Err(); err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrScriptLoad, name, err)
+		}
+	}
+
+	log.Info().
+		Str("source", "gframework").
+		Int("script_count", len(scripts)).
+		Msg("Lua scripts have been loaded")
+
+	return nil
+}
+
+// Versions returns the SHA1 digest of every registered script by name, e.g. to expose on a debug
+// endpoint.
+func (r *ScriptRegistry) Versions() map[string]string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	versions := make(map[string]string, len(r.scripts))
+	for name, script := range r.scripts {
+		versions[name] = script.Hash()
+	}
+
+	return versions
+}
+
+func (r *ScriptRegistry) Name() string {
+	return "valkey-scripts"
+}
+
+func (r *ScriptRegistry) Start(ctx context.Context) error {
+	return r.Load(ctx)
+}
+
+func (r *ScriptRegistry) Stop() error {
+	return nil
+}
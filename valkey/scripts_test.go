@@ -0,0 +1,87 @@
+//nolint:exhaustruct
+package valkey_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/andyle182810/gframework/valkey"
+	"github.com/stretchr/testify/require"
+)
+
+const takeScript = `
+local remaining = tonumber(redis.call("GET", KEYS[1]) or ARGV[1])
+if remaining <= 0 then
+	return 0
+end
+redis.call("SET", KEYS[1], remaining - 1)
+return 1
+`
+
+func TestScriptRegistry(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	container := testutil.SetupValkeyContainer(t)
+
+	port, err := strconv.Atoi(container.Port.Port())
+	require.NoError(t, err)
+
+	client, err := valkey.New(&valkey.Config{Host: container.Host, Port: port, DB: container.DB})
+	require.NoError(t, err)
+
+	registry, err := valkey.NewScriptRegistry(client.Client)
+	require.NoError(t, err)
+
+	take, err := valkey.RegisterScript(registry, "quota:take", takeScript, valkey.BoolResult)
+	require.NoError(t, err)
+
+	require.NoError(t, registry.Start(ctx))
+
+	for _, sha := range registry.Versions() {
+		exists, err := client.ScriptExists(ctx, sha).Result()
+		require.NoError(t, err)
+		require.Equal(t, []bool{true}, exists)
+	}
+
+	taken, err := take(ctx, []string{"quota:tenant"}, 1)
+	require.NoError(t, err)
+	require.True(t, taken)
+
+	taken, err = take(ctx, []string{"quota:tenant"}, 1)
+	require.NoError(t, err)
+	require.False(t, taken)
+
+	// EVALSHA falls back to EVAL once the script cache has been flushed.
+	require.NoError(t, client.ScriptFlush(ctx).Err())
+	require.NoError(t, client.Del(ctx, "quota:tenant").Err())
+
+	taken, err = take(ctx, []string{"quota:tenant"}, 1)
+	require.NoError(t, err)
+	require.True(t, taken)
+}
+
+func TestScriptRegistry_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := valkey.NewScriptRegistry(nil)
+	require.ErrorIs(t, err, valkey.ErrValkeyPoolNil)
+
+	client, err := valkey.New(&valkey.Config{Host: "localhost", Port: 6379})
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = client.Close() })
+
+	registry, err := valkey.NewScriptRegistry(client.Client)
+	require.NoError(t, err)
+	require.Contains(t, registry.Versions(), "valkey:ratelimit:token_bucket")
+
+	require.NoError(t, registry.Register("quota:take", takeScript))
+	require.ErrorIs(t, registry.Register("quota:take", takeScript), valkey.ErrDuplicateScript)
+
+	_, err = valkey.RegisterScript(registry, "valkey:ratelimit:token_bucket", takeScript, valkey.IntResult)
+	require.ErrorIs(t, err, valkey.ErrDuplicateScript)
+
+	require.ErrorIs(t, registry.Run(t.Context(), "missing", nil).Err(), valkey.ErrScriptNotFound)
+}
@@ -20,8 +20,18 @@
 //	    return err
 //	}
 //
-// For TLS connections, configure the TLS field in Config. The underlying redis.UniversalClient
-// is exposed via the Client field for direct access to all standard Redis operations.
+// Set Config.Metrics, e.g. to NewPrometheusMetrics, to record command latencies and errors, and use
+// RegisterPoolMetrics to export connection pool stats.
+//
+// For TLS connections, configure the TLS fields in Config. Managed offerings that rotate auth tokens
+// can set Config.CredentialsProvider, e.g. NewCachedCredentialsProvider wrapping an IAM token source,
+// so that new connections pick up fresh credentials without a restart.
+//
+// Config.ReadReplicaAddrs adds read replicas; ReadClient returns a healthy replica for read-only
+// commands and falls back to the primary when none is available.
+//
+// The underlying redis.UniversalClient is exposed via the Client field for direct access to all
+// standard Redis operations.
 package valkey
 
 import (
@@ -33,6 +43,7 @@ import (
 	"net"
 	"os"
 	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -40,19 +51,20 @@ import (
 )
 
 const (
-	defaultDialTimeout     = 5 * time.Second
-	defaultPingTimeout     = 3 * time.Second
-	defaultReadTimeout     = 3 * time.Second
-	defaultWriteTimeout    = 3 * time.Second
-	defaultMinRetryBackoff = 8 * time.Millisecond
-	defaultMaxRetryBackoff = 512 * time.Millisecond
-	initialPingTimeout     = 5 * time.Second
-	minPort                = 1
-	maxPort                = 65535
-	defaultPoolSize        = 10
-	defaultMaxIdleConns    = 5
-	defaultMinIdleConns    = 1
-	defaultMaxRetries      = 3
+	defaultDialTimeout          = 5 * time.Second
+	defaultPingTimeout          = 3 * time.Second
+	defaultReadTimeout          = 3 * time.Second
+	defaultWriteTimeout         = 3 * time.Second
+	defaultMinRetryBackoff      = 8 * time.Millisecond
+	defaultMaxRetryBackoff      = 512 * time.Millisecond
+	initialPingTimeout          = 5 * time.Second
+	minPort                     = 1
+	maxPort                     = 65535
+	defaultPoolSize             = 10
+	defaultMaxIdleConns         = 5
+	defaultMinIdleConns         = 1
+	defaultMaxRetries           = 3
+	defaultReplicaCheckInterval = 5 * time.Second
 )
 
 var (
@@ -64,11 +76,13 @@ var (
 	ErrConfigNil                = errors.New("valkey: configuration must not be nil")
 	ErrCAParseFailure           = errors.New("failed to parse CA certificate")
 	ErrHealthCheckNoActiveConns = errors.New("valkey health check failed: no active connections in pool")
+	ErrInvalidReplicaAddr       = errors.New("valkey: replica address must be host:port")
 )
 
 type Config struct {
 	Host            string
 	Port            int
+	Username        string
 	Password        string
 	DB              int
 	DialTimeout     time.Duration
@@ -86,10 +100,30 @@ type Config struct {
 	TLSCertFile     string
 	TLSKeyFile      string
 	TLSCAFile       string
+	// TLSServerName overrides the name the server certificate is verified against, e.g. when Host is an
+	// IP address or an alias not listed in the certificate's SANs.
+	TLSServerName string
+	// CredentialsProvider, when set, supplies the username and password for every new connection
+	// instead of Username and Password, e.g. for rotating cloud IAM auth tokens.
+	CredentialsProvider CredentialsProvider
+	// ReadReplicaAddrs lists host:port addresses of read replicas that ReadClient routes to. They use
+	// the same credentials, TLS and pool settings as the primary.
+	ReadReplicaAddrs []string
+	// ReplicaCheckInterval is how often replicas are pinged while the client is running. It defaults to
+	// 5 seconds.
+	ReplicaCheckInterval time.Duration
+	// Metrics, when set, receives the latency and outcome of every command.
+	Metrics Metrics
 }
 
 type Valkey struct {
 	*redis.Client
+
+	replicas      []*replica
+	nextReplica   atomic.Uint64
+	checkInterval time.Duration
+	stopChecks    context.CancelFunc
+	checksDone    chan struct{}
 }
 
 func (cfg *Config) Validate() error {
@@ -109,6 +143,12 @@ func (cfg *Config) Validate() error {
 		return ErrInvalidPoolSize
 	}
 
+	for _, addr := range cfg.ReadReplicaAddrs {
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			return fmt.Errorf("%w: %q", ErrInvalidReplicaAddr, addr)
+		}
+	}
+
 	return nil
 }
 
@@ -154,6 +194,10 @@ func (cfg *Config) WithDefaults() *Config {
 		cfg.MaxRetryBackoff = defaultMaxRetryBackoff
 	}
 
+	if cfg.ReplicaCheckInterval == 0 {
+		cfg.ReplicaCheckInterval = defaultReplicaCheckInterval
+	}
+
 	return cfg
 }
 
@@ -172,15 +216,30 @@ func New(cfg *Config) (*Valkey, error) {
 		return nil, fmt.Errorf("failed to build Valkey options: %w", err)
 	}
 
+	client := newClient(opt, cfg)
+
+	//nolint:exhaustruct
+	return &Valkey{
+		Client:        client,
+		replicas:      newReplicas(opt, cfg),
+		checkInterval: cfg.ReplicaCheckInterval,
+	}, nil
+}
+
+func newClient(opt *redis.Options, cfg *Config) *redis.Client {
 	client := redis.NewClient(opt)
+	if cfg.Metrics != nil {
+		client.AddHook(&metricsHook{metrics: cfg.Metrics})
+	}
 
-	return &Valkey{Client: client}, nil
+	return client
 }
 
 //nolint:exhaustruct
 func buildValkeyOptions(cfg *Config) (*redis.Options, error) {
 	opt := &redis.Options{
 		Addr:            net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		Username:        cfg.Username,
 		Password:        cfg.Password,
 		DB:              cfg.DB,
 		DialTimeout:     cfg.DialTimeout,
@@ -194,6 +253,10 @@ func buildValkeyOptions(cfg *Config) (*redis.Options, error) {
 		MaxRetryBackoff: cfg.MaxRetryBackoff,
 	}
 
+	if cfg.CredentialsProvider != nil {
+		opt.CredentialsProviderContext = cfg.CredentialsProvider.Credentials
+	}
+
 	if cfg.TLSEnabled {
 		tlsConfig, err := buildTLSConfig(cfg)
 		if err != nil {
@@ -211,6 +274,7 @@ func buildTLSConfig(cfg *Config) (*tls.Config, error) {
 	tlsConfig := &tls.Config{
 		InsecureSkipVerify: cfg.TLSSkipVerify,
 		MinVersion:         tls.VersionTLS12,
+		ServerName:         cfg.TLSServerName,
 	}
 
 	if cfg.TLSCertFile != "" && cfg.TLSKeyFile != "" {
@@ -247,6 +311,8 @@ func (v *Valkey) Start(ctx context.Context) error {
 		return fmt.Errorf("valkey ping failed: %w", err)
 	}
 
+	v.startReplicaChecks(ctx)
+
 	return nil
 }
 
@@ -260,6 +326,8 @@ func (v *Valkey) Stop() error {
 		Str("service_name", v.Name()).
 		Msg("The Valkey client pool is being closed")
 
+	v.stopReplicaChecks()
+
 	if err := v.Client.Close(); err != nil {
 		return fmt.Errorf("failed to close Valkey client: %w", err)
 	}
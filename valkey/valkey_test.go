@@ -26,7 +26,7 @@ func TestValkeyConnection(t *testing.T) {
 		Host:         container.Host,
 		Port:         port,
 		Password:     "",
-		DB:           0,
+		DB:           container.DB,
 		DialTimeout:  5 * time.Second,
 		MaxIdleConns: 5,
 		MinIdleConns: 1,
@@ -148,6 +148,7 @@ func TestValkeyHealthCheck(t *testing.T) {
 	opts := &valkey.Config{
 		Host: container.Host,
 		Port: port,
+		DB:   container.DB,
 	}
 
 	valkey, err := valkey.New(opts)
@@ -0,0 +1,528 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/andyle182810/gframework/logutil"
+	"github.com/andyle182810/gframework/middleware"
+	"github.com/andyle182810/gframework/pagination"
+	"github.com/andyle182810/gframework/postgres"
+	"github.com/andyle182810/gframework/taskqueue"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v5"
+	"github.com/rs/zerolog/log"
+)
+
+const deliveryColumns = `id, subscription_id, event_id, event_type, payload, status, attempts,
+	COALESCE(last_error, '') AS last_error, next_attempt_at, delivered_at, created_at, updated_at`
+
+// Publish creates a delivery of event for every enabled subscription of its type and sends them. In
+// the transaction of ctx, started with postgres.BeginCtx, the deliveries are stored only if the
+// transaction commits and sent at the next poll after the commit.
+func (d *Dispatcher) Publish(ctx context.Context, event Event) error {
+	if event.Type == "" {
+		return ErrEmptyEventType
+	}
+
+	if event.ID == "" {
+		event.ID = uuid.NewString()
+	}
+
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now().UTC()
+	}
+
+	body, err := json.Marshal(envelope{ID: event.ID, Type: event.Type, CreatedAt: event.CreatedAt, Data: event.Data})
+	if err != nil {
+		return fmt.Errorf("webhooks: failed to encode event %s: %w", event.ID, err)
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s (id, subscription_id, event_id, event_type, payload, status)
+		SELECT gen_random_uuid()::text, id, $1, $2, $3, $4 FROM %s
+		WHERE disabled_at IS NULL AND (cardinality(event_types) = 0 OR $2 = ANY(event_types))
+		ON CONFLICT (subscription_id, event_id) DO NOTHING
+		RETURNING id`, d.deliveries.Identifier(), d.subscriptions.Identifier())
+
+	rows, err := postgres.FromCtx(ctx, d.db).Query(ctx, query, event.ID, event.Type, body, DeliveryPending)
+	if err != nil {
+		return fmt.Errorf("webhooks: failed to publish event %s: %w", event.ID, err)
+	}
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return fmt.Errorf("webhooks: failed to publish event %s: %w", event.ID, err)
+	}
+
+	if _, inTx := postgres.TxFromCtx(ctx); !inTx {
+		for _, id := range ids {
+			d.dispatch(ctx, id, 0)
+		}
+	}
+
+	return nil
+}
+
+// Delivery returns the delivery id.
+func (d *Dispatcher) Delivery(ctx context.Context, id string) (*Delivery, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE id = $1`, deliveryColumns, d.deliveries.Identifier())
+
+	var delivery Delivery
+
+	if err := pgxscan.Get(ctx, d.db, &delivery, query, id); err != nil {
+		if pgxscan.NotFound(err) {
+			return nil, fmt.Errorf("%w: %s", ErrDeliveryNotFound, id)
+		}
+
+		return nil, fmt.Errorf("webhooks: failed to get delivery %s: %w", id, err)
+	}
+
+	return &delivery, nil
+}
+
+// Deliveries returns a page of the deliveries of the subscription, newest first, optionally only those
+// with status.
+func (d *Dispatcher) Deliveries(
+	ctx context.Context,
+	subscriptionID string,
+	status DeliveryStatus,
+	cursor pagination.Cursor,
+	limit int,
+) ([]Delivery, *pagination.Page, error) {
+	query, args, err := keyset.Query(
+		fmt.Sprintf(`SELECT %s FROM %s WHERE subscription_id = $1 AND ($2 = '' OR status = $2)`,
+			deliveryColumns, d.deliveries.Identifier()),
+		[]any{subscriptionID, string(status)}, cursor, limit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("webhooks: invalid cursor: %w", err)
+	}
+
+	var deliveries []Delivery
+
+	if err := pgxscan.Select(ctx, d.db, &deliveries, query, args...); err != nil {
+		return nil, nil, fmt.Errorf("webhooks: failed to list deliveries: %w", err)
+	}
+
+	deliveries, page := pagination.Paginate(deliveries, cursor, limit, func(delivery Delivery) []any {
+		return []any{delivery.CreatedAt, delivery.ID}
+	})
+
+	return deliveries, page, nil
+}
+
+// Attempts returns the attempts of the delivery id in the order they were made.
+func (d *Dispatcher) Attempts(ctx context.Context, deliveryID string) ([]Attempt, error) {
+	query := fmt.Sprintf(`SELECT attempt, status_code, error, response_body, duration_ms, attempted_at
+		FROM %s WHERE delivery_id = $1 ORDER BY id`, d.attempts.Identifier())
+
+	attempts := []Attempt{}
+
+	if err := pgxscan.Select(ctx, d.db, &attempts, query, deliveryID); err != nil {
+		return nil, fmt.Errorf("webhooks: failed to list the attempts of %s: %w", deliveryID, err)
+	}
+
+	return attempts, nil
+}
+
+// Replay sends a delivered or failed delivery again with a fresh budget of attempts, e.g. after the
+// receiver lost it or fixed its endpoint. The body and the event ID stay the same.
+func (d *Dispatcher) Replay(ctx context.Context, id string) error {
+	query := fmt.Sprintf(`UPDATE %s SET status = $2, attempts = 0, next_attempt_at = now(), delivered_at = NULL,
+			updated_at = now()
+		WHERE id = $1 AND status <> $2 RETURNING version`, d.deliveries.Identifier())
+
+	var version int64
+
+	err := d.db.QueryRow(ctx, query, id, DeliveryPending).Scan(&version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		if _, err := d.Delivery(ctx, id); err != nil {
+			return err
+		}
+
+		return fmt.Errorf("%w: %s", ErrDeliveryPending, id)
+	}
+
+	if err != nil {
+		return fmt.Errorf("webhooks: failed to replay delivery %s: %w", id, err)
+	}
+
+	d.dispatch(ctx, id, version)
+
+	return nil
+}
+
+func (d *Dispatcher) Name() string {
+	return "webhooks-dispatcher"
+}
+
+// Start runs the task queue workers and dispatches due deliveries until ctx is cancelled or Stop is
+// called.
+func (d *Dispatcher) Start(ctx context.Context) error {
+	if !d.running.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	d.mu.Lock()
+	d.stop = make(chan struct{})
+	d.stopped = make(chan struct{})
+	stop, stopped := d.stop, d.stopped
+	d.mu.Unlock()
+
+	defer close(stopped)
+
+	if err := d.queue.Start(ctx); err != nil {
+		return fmt.Errorf("webhooks: failed to start task queue: %w", err)
+	}
+
+	log.Info().Str("source", "gframework").Str("tables", d.tablePrefix).Msg("Webhook dispatcher is starting")
+
+	for {
+		dispatched, err := d.DispatchDue(ctx)
+		if err != nil {
+			log.Error().Str("source", "gframework").Err(err).Msg("Failed to dispatch webhook deliveries")
+		}
+
+		delay := d.pollInterval
+		if err == nil && dispatched == d.batchSize {
+			delay = 0
+		}
+
+		select {
+		case <-stop:
+			return nil
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (d *Dispatcher) Stop() error {
+	if !d.running.CompareAndSwap(true, false) {
+		return nil
+	}
+
+	d.mu.Lock()
+	stop, stopped := d.stop, d.stopped
+	d.mu.Unlock()
+
+	close(stop)
+	<-stopped
+
+	if err := d.queue.Stop(); err != nil {
+		return fmt.Errorf("webhooks: failed to stop task queue: %w", err)
+	}
+
+	log.Info().Str("source", "gframework").Str("tables", d.tablePrefix).Msg("Webhook dispatcher stopped")
+
+	return nil
+}
+
+// DispatchDue pushes the tasks of up to the batch size of due deliveries of enabled subscriptions:
+// retries whose backoff passed, deliveries published in a transaction and deliveries whose task was
+// lost. It returns the number of deliveries dispatched.
+func (d *Dispatcher) DispatchDue(ctx context.Context) (int, error) {
+	query := fmt.Sprintf(`UPDATE %[1]s SET next_attempt_at = now() + make_interval(secs => $1)
+		WHERE id IN (
+			SELECT delivery.id FROM %[1]s AS delivery
+			JOIN %[2]s AS subscription ON subscription.id = delivery.subscription_id
+			WHERE delivery.status = $2 AND delivery.next_attempt_at <= now() AND subscription.disabled_at IS NULL
+			ORDER BY delivery.next_attempt_at LIMIT $3 FOR UPDATE OF delivery SKIP LOCKED
+		)
+		RETURNING id, version`, d.deliveries.Identifier(), d.subscriptions.Identifier())
+
+	rows, err := d.db.Query(ctx, query, d.lease().Seconds(), DeliveryPending, d.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("webhooks: failed to claim due deliveries: %w", err)
+	}
+
+	tasks, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (taskqueue.Task, error) {
+		var ref taskRef
+
+		if err := row.Scan(&ref.ID, &ref.Version); err != nil {
+			return taskqueue.Task{}, err //nolint:exhaustruct
+		}
+
+		return ref.task()
+	})
+	if err != nil {
+		return 0, fmt.Errorf("webhooks: failed to read due deliveries: %w", err)
+	}
+
+	// A lost push is dispatched again once the lease set above expires.
+	if err := d.queue.Push(ctx, tasks...); err != nil {
+		return 0, fmt.Errorf("webhooks: failed to push due deliveries: %w", err)
+	}
+
+	return len(tasks), nil
+}
+
+func (d *Dispatcher) lease() time.Duration {
+	return d.requestTimeout + leaseMargin
+}
+
+// taskRef is the payload of a delivery task: the delivery and the version the task is valid for.
+type taskRef struct {
+	ID      string `json:"id"`
+	Version int64  `json:"version"`
+}
+
+func (r taskRef) task() (taskqueue.Task, error) {
+	payload, err := json.Marshal(r)
+	if err != nil {
+		return taskqueue.Task{}, fmt.Errorf("webhooks: failed to encode task: %w", err) //nolint:exhaustruct
+	}
+
+	return taskqueue.Task{ID: fmt.Sprintf("%s:%d", r.ID, r.Version), Payload: payload}, nil
+}
+
+// dispatch pushes the task of a delivery. A failed push is logged; the delivery is dispatched again at
+// a later poll.
+func (d *Dispatcher) dispatch(ctx context.Context, id string, version int64) {
+	task, err := taskRef{ID: id, Version: version}.task()
+	if err == nil {
+		err = d.queue.Push(ctx, task)
+	}
+
+	if err != nil {
+		logutil.FromContext(ctx).Warn().
+			Str("source", "gframework").
+			Err(err).
+			Str("delivery_id", id).
+			Msg("Failed to dispatch the webhook delivery, it is retried at the next poll")
+	}
+}
+
+// claimed is a delivery claimed for one attempt.
+type claimed struct {
+	id             string
+	subscriptionID string
+	attempt        int
+	version        int64
+	eventType      string
+	body           []byte
+	url            string
+	secrets        []string
+}
+
+// outcome is the result of one request of a delivery.
+type outcome struct {
+	statusCode int
+	err        string
+	response   string
+	duration   time.Duration
+}
+
+// Execute sends the delivery a task of the queue was dispatched for. Tasks of a version the delivery has
+// moved past, e.g. pushed twice or dispatched again after a lost push, are dropped, as are tasks of
+// deliveries whose subscription was disabled since.
+func (d *Dispatcher) Execute(ctx context.Context, _ string, payload taskqueue.Payload) error {
+	var ref taskRef
+
+	if err := json.Unmarshal(payload, &ref); err != nil {
+		return fmt.Errorf("webhooks: invalid task: %w", err)
+	}
+
+	delivery, err := d.claim(ctx, ref)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("webhooks: failed to claim delivery %s: %w", ref.ID, err)
+	}
+
+	result := d.send(ctx, delivery)
+
+	if errors.Is(ctx.Err(), context.Canceled) {
+		return d.release(context.WithoutCancel(ctx), delivery)
+	}
+
+	return d.record(ctx, delivery, result)
+}
+
+// claim fences the delivery of ref for one attempt: it bumps the version, so other tasks of the
+// delivery become stale, and leases it for the request timeout.
+func (d *Dispatcher) claim(ctx context.Context, ref taskRef) (*claimed, error) {
+	query := fmt.Sprintf(`UPDATE %[1]s AS delivery SET version = delivery.version + 1,
+			attempts = delivery.attempts + 1, next_attempt_at = now() + make_interval(secs => $3), updated_at = now()
+		FROM %[2]s AS subscription
+		WHERE delivery.id = $1 AND delivery.version = $2 AND delivery.status = $4
+			AND subscription.id = delivery.subscription_id AND subscription.disabled_at IS NULL
+		RETURNING subscription.id, delivery.attempts, delivery.version, delivery.event_type, delivery.payload::text,
+			subscription.url, subscription.secret,
+			CASE WHEN subscription.previous_secret_expires_at > now() THEN subscription.previous_secret END`,
+		d.deliveries.Identifier(), d.subscriptions.Identifier())
+
+	var (
+		delivery       = &claimed{id: ref.ID} //nolint:exhaustruct
+		body, secret   string
+		previousSecret *string
+	)
+
+	err := d.db.QueryRow(ctx, query, ref.ID, ref.Version, d.lease().Seconds(), DeliveryPending).Scan(
+		&delivery.subscriptionID, &delivery.attempt, &delivery.version, &delivery.eventType, &body,
+		&delivery.url, &secret, &previousSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery.body = []byte(body)
+	delivery.secrets = []string{secret}
+
+	if previousSecret != nil {
+		delivery.secrets = append(delivery.secrets, *previousSecret)
+	}
+
+	return delivery, nil
+}
+
+// send POSTs the delivery to the endpoint of its subscription.
+func (d *Dispatcher) send(ctx context.Context, delivery *claimed) outcome {
+	var result outcome
+
+	signature, err := Sign(delivery.body, time.Now(), delivery.secrets...)
+	if err != nil {
+		result.err = err.Error()
+
+		return result
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, d.requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, delivery.url, bytes.NewReader(delivery.body))
+	if err != nil {
+		result.err = err.Error()
+
+		return result
+	}
+
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(HeaderWebhookID, delivery.id)
+	req.Header.Set(HeaderWebhookEvent, delivery.eventType)
+	req.Header.Set(middleware.HeaderXSignature, signature)
+
+	start := time.Now()
+
+	resp, err := d.httpClient.Do(req)
+
+	result.duration = time.Since(start)
+
+	if err != nil {
+		result.err = err.Error()
+
+		return result
+	}
+
+	defer resp.Body.Close()
+
+	response, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseLength))
+
+	result.statusCode = resp.StatusCode
+	result.response = string(response)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		result.err = "unexpected status " + strconv.Itoa(resp.StatusCode)
+	}
+
+	return result
+}
+
+// record stores the attempt and schedules the retry of a failed delivery, or fails it once it ran out
+// of attempts. An attempt of a delivery claimed by another attempt since is dropped.
+func (d *Dispatcher) record(ctx context.Context, delivery *claimed, result outcome) error {
+	status, retryIn := DeliveryDelivered, time.Duration(0)
+
+	switch {
+	case result.err == "":
+	case delivery.attempt >= d.maxAttempts:
+		status = DeliveryFailed
+	default:
+		status, retryIn = DeliveryPending, d.backoff(delivery.attempt)
+	}
+
+	query := fmt.Sprintf(`WITH updated AS (
+			UPDATE %[1]s SET status = $3, last_error = NULLIF($4, ''), next_attempt_at = now() + make_interval(secs => $5),
+				delivered_at = CASE WHEN $6 THEN now() END, updated_at = now()
+			WHERE id = $1 AND version = $2
+			RETURNING id, attempts
+		)
+		INSERT INTO %[2]s (delivery_id, attempt, status_code, error, response_body, duration_ms)
+		SELECT id, attempts, $7, $4, $8, $9 FROM updated`, d.deliveries.Identifier(), d.attempts.Identifier())
+
+	tag, err := d.db.Exec(ctx, query, delivery.id, delivery.version, status, truncate(result.err, maxErrorLength),
+		retryIn.Seconds(), status == DeliveryDelivered, result.statusCode, result.response,
+		result.duration.Milliseconds())
+	if err != nil {
+		return fmt.Errorf("webhooks: failed to record the attempt of %s: %w", delivery.id, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		logutil.FromContext(ctx).Warn().
+			Str("source", "gframework").
+			Str("delivery_id", delivery.id).
+			Msg("The webhook delivery outlived its lease, its attempt is dropped")
+
+		return nil
+	}
+
+	if result.statusCode == http.StatusGone {
+		if err := d.Disable(ctx, delivery.subscriptionID); err != nil {
+			return err
+		}
+
+		logutil.FromContext(ctx).Warn().
+			Str("source", "gframework").
+			Str("subscription_id", delivery.subscriptionID).
+			Str("url", delivery.url).
+			Msg("The webhook endpoint is gone, its subscription is disabled")
+	}
+
+	if status == DeliveryFailed {
+		logutil.FromContext(ctx).Error().
+			Str("source", "gframework").
+			Str("delivery_id", delivery.id).
+			Str("subscription_id", delivery.subscriptionID).
+			Str("error", result.err).
+			Msg("The webhook delivery has failed, replay it once the endpoint is fixed")
+	}
+
+	return nil
+}
+
+// release gives back an attempt interrupted by the shutdown of the queue, without counting it.
+func (d *Dispatcher) release(ctx context.Context, delivery *claimed) error {
+	query := fmt.Sprintf(`UPDATE %s SET attempts = attempts - 1, next_attempt_at = now(), updated_at = now()
+		WHERE id = $1 AND version = $2`, d.deliveries.Identifier())
+
+	if _, err := d.db.Exec(ctx, query, delivery.id, delivery.version); err != nil {
+		return fmt.Errorf("webhooks: failed to release delivery %s: %w", delivery.id, err)
+	}
+
+	return nil
+}
+
+// backoff returns the delay after the failed attempt, doubling from the retry backoff up to the max.
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+	delay := d.retryBackoff
+
+	for range attempt - 1 {
+		if delay >= d.maxBackoff/2 {
+			return d.maxBackoff
+		}
+
+		delay *= 2
+	}
+
+	return min(delay, d.maxBackoff)
+}
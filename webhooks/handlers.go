@@ -0,0 +1,155 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+
+	"github.com/andyle182810/gframework/httpserver"
+	"github.com/andyle182810/gframework/pagination"
+	"github.com/labstack/echo/v5"
+)
+
+type ListSubscriptionsRequest struct {
+	pagination.Request
+}
+
+type ListDeliveriesRequest struct {
+	pagination.Request
+
+	SubscriptionID string         `param:"subscriptionId" validate:"required"`
+	Status         DeliveryStatus `query:"status"         validate:"omitempty,oneof=pending delivered failed"`
+}
+
+type SubscriptionRequest struct {
+	SubscriptionID string `param:"subscriptionId" validate:"required"`
+}
+
+type DeliveryRequest struct {
+	DeliveryID string `param:"deliveryId" validate:"required"`
+}
+
+// RegisterRoutes serves the management endpoints under /webhooks of group:
+//
+//	GET  /webhooks/subscriptions                                list subscriptions
+//	POST /webhooks/subscriptions/:subscriptionId/disable        disable a subscription
+//	POST /webhooks/subscriptions/:subscriptionId/enable         enable a subscription
+//	GET  /webhooks/subscriptions/:subscriptionId/deliveries     list deliveries, optionally by ?status=
+//	GET  /webhooks/deliveries/:deliveryId/attempts              list the attempts of a delivery
+//	POST /webhooks/deliveries/:deliveryId/replay                replay a delivery
+//
+// Lists are cursor paginated with the cursor and limit query params. The endpoints expose the URLs and
+// payloads of the subscriptions, so protect group, e.g. with middleware.JWT and
+// middleware.RequireAnyRealmRole.
+func (d *Dispatcher) RegisterRoutes(group *echo.Group) {
+	webhooks := group.Group("/webhooks")
+
+	webhooks.GET("/subscriptions", httpserver.Wrapper(d.listSubscriptions))
+	webhooks.POST("/subscriptions/:subscriptionId/disable", httpserver.Wrapper(d.disableSubscription))
+	webhooks.POST("/subscriptions/:subscriptionId/enable", httpserver.Wrapper(d.enableSubscription))
+	webhooks.GET("/subscriptions/:subscriptionId/deliveries", httpserver.Wrapper(d.listDeliveries))
+	webhooks.GET("/deliveries/:deliveryId/attempts", httpserver.Wrapper(d.listAttempts))
+	webhooks.POST("/deliveries/:deliveryId/replay", httpserver.Wrapper(d.replayDelivery))
+}
+
+func (d *Dispatcher) listSubscriptions(c *echo.Context, req *ListSubscriptionsRequest) (any, *echo.HTTPError) {
+	cursor, limit, err := req.Normalize()
+	if err != nil {
+		return nil, httpserver.BadRequestError(err, "Invalid cursor")
+	}
+
+	subs, page, err := d.Subscriptions(c.Request().Context(), cursor, limit)
+	if err != nil {
+		return nil, httpError(err, "Failed to list subscriptions")
+	}
+
+	return httpserver.NewCursorPaginatedResponse(subs, page), nil
+}
+
+func (d *Dispatcher) disableSubscription(c *echo.Context, req *SubscriptionRequest) (any, *echo.HTTPError) {
+	return d.subscriptionAction(c, req.SubscriptionID, d.Disable)
+}
+
+func (d *Dispatcher) enableSubscription(c *echo.Context, req *SubscriptionRequest) (any, *echo.HTTPError) {
+	return d.subscriptionAction(c, req.SubscriptionID, d.Enable)
+}
+
+func (d *Dispatcher) subscriptionAction(
+	c *echo.Context,
+	id string,
+	action func(ctx context.Context, id string) error,
+) (any, *echo.HTTPError) {
+	ctx := c.Request().Context()
+
+	if err := action(ctx, id); err != nil {
+		return nil, httpError(err, "Failed to update subscription")
+	}
+
+	sub, err := d.Subscription(ctx, id)
+	if err != nil {
+		return nil, httpError(err, "Failed to get subscription")
+	}
+
+	return httpserver.NewResponse(sub), nil
+}
+
+func (d *Dispatcher) listDeliveries(c *echo.Context, req *ListDeliveriesRequest) (any, *echo.HTTPError) {
+	cursor, limit, err := req.Normalize()
+	if err != nil {
+		return nil, httpserver.BadRequestError(err, "Invalid cursor")
+	}
+
+	ctx := c.Request().Context()
+
+	if _, err := d.Subscription(ctx, req.SubscriptionID); err != nil {
+		return nil, httpError(err, "Failed to get subscription")
+	}
+
+	deliveries, page, err := d.Deliveries(ctx, req.SubscriptionID, req.Status, cursor, limit)
+	if err != nil {
+		return nil, httpError(err, "Failed to list deliveries")
+	}
+
+	return httpserver.NewCursorPaginatedResponse(deliveries, page), nil
+}
+
+func (d *Dispatcher) listAttempts(c *echo.Context, req *DeliveryRequest) (any, *echo.HTTPError) {
+	ctx := c.Request().Context()
+
+	if _, err := d.Delivery(ctx, req.DeliveryID); err != nil {
+		return nil, httpError(err, "Failed to get delivery")
+	}
+
+	attempts, err := d.Attempts(ctx, req.DeliveryID)
+	if err != nil {
+		return nil, httpError(err, "Failed to list attempts")
+	}
+
+	return httpserver.NewResponse(attempts), nil
+}
+
+func (d *Dispatcher) replayDelivery(c *echo.Context, req *DeliveryRequest) (any, *echo.HTTPError) {
+	ctx := c.Request().Context()
+
+	if err := d.Replay(ctx, req.DeliveryID); err != nil {
+		return nil, httpError(err, "Failed to replay delivery")
+	}
+
+	delivery, err := d.Delivery(ctx, req.DeliveryID)
+	if err != nil {
+		return nil, httpError(err, "Failed to get delivery")
+	}
+
+	return httpserver.NewResponse(delivery), nil
+}
+
+// httpError maps the errors of the dispatcher to their status; other errors are internal.
+func httpError(err error, message string) *echo.HTTPError {
+	switch {
+	case errors.Is(err, ErrSubscriptionNotFound), errors.Is(err, ErrDeliveryNotFound):
+		return httpserver.NotFoundError(err)
+	case errors.Is(err, ErrDeliveryPending):
+		return httpserver.ConflictError(err)
+	default:
+		return httpserver.InternalError(err, message)
+	}
+}
@@ -0,0 +1,31 @@
+package webhooks
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andyle182810/gframework/middleware"
+)
+
+// Sign returns the X-Signature header of body sent at timestamp: "t=<unix>" followed by a "v1=<hex>"
+// element per secret, so a receiver holding any of the secrets verifies it, e.g. with
+// middleware.VerifyWebhook.
+func Sign(body []byte, timestamp time.Time, secrets ...string) (string, error) {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+
+	var builder strings.Builder
+
+	builder.WriteString("t=" + unix)
+
+	for _, secret := range secrets {
+		signature, err := middleware.SignWebhook([]byte(secret), middleware.WebhookAlgorithmSHA256, unix, body)
+		if err != nil {
+			return "", err
+		}
+
+		builder.WriteString(",v1=" + signature)
+	}
+
+	return builder.String(), nil
+}
@@ -0,0 +1,189 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/andyle182810/gframework/pagination"
+	"github.com/andyle182810/gframework/postgres"
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/google/uuid"
+)
+
+const (
+	secretPrefix = "whsec_"
+	secretBytes  = 32
+
+	subscriptionColumns = `id, url, event_types, description, disabled_at, created_at, updated_at`
+)
+
+// keyset orders subscriptions and deliveries newest first.
+var keyset = postgres.Keyset{Columns: []string{"created_at", "id"}, Descending: true}
+
+// Subscribe stores sub with a new ID and secret and returns it with the secret set.
+func (d *Dispatcher) Subscribe(ctx context.Context, sub Subscription) (*Subscription, error) {
+	if err := validateURL(sub.URL); err != nil {
+		return nil, err
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if sub.EventTypes == nil {
+		sub.EventTypes = []string{}
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s (id, url, event_types, description, secret)
+		VALUES ($1, $2, $3, $4, $5) RETURNING %s`, d.subscriptions.Identifier(), subscriptionColumns)
+
+	var created Subscription
+
+	err = pgxscan.Get(ctx, postgres.FromCtx(ctx, d.db), &created, query,
+		uuid.NewString(), sub.URL, sub.EventTypes, sub.Description, secret)
+	if err != nil {
+		return nil, fmt.Errorf("webhooks: failed to create subscription: %w", err)
+	}
+
+	created.Secret = secret
+
+	return &created, nil
+}
+
+// Subscription returns the subscription id, without its secret.
+func (d *Dispatcher) Subscription(ctx context.Context, id string) (*Subscription, error) {
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE id = $1`, subscriptionColumns, d.subscriptions.Identifier())
+
+	var sub Subscription
+
+	if err := pgxscan.Get(ctx, d.db, &sub, query, id); err != nil {
+		if pgxscan.NotFound(err) {
+			return nil, fmt.Errorf("%w: %s", ErrSubscriptionNotFound, id)
+		}
+
+		return nil, fmt.Errorf("webhooks: failed to get subscription %s: %w", id, err)
+	}
+
+	return &sub, nil
+}
+
+// Subscriptions returns a page of the subscriptions, newest first.
+func (d *Dispatcher) Subscriptions(
+	ctx context.Context,
+	cursor pagination.Cursor,
+	limit int,
+) ([]Subscription, *pagination.Page, error) {
+	query, args, err := keyset.Query(
+		fmt.Sprintf(`SELECT %s FROM %s`, subscriptionColumns, d.subscriptions.Identifier()), nil, cursor, limit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("webhooks: invalid cursor: %w", err)
+	}
+
+	var subs []Subscription
+
+	if err := pgxscan.Select(ctx, d.db, &subs, query, args...); err != nil {
+		return nil, nil, fmt.Errorf("webhooks: failed to list subscriptions: %w", err)
+	}
+
+	subs, page := pagination.Paginate(subs, cursor, limit, func(sub Subscription) []any {
+		return []any{sub.CreatedAt, sub.ID}
+	})
+
+	return subs, page, nil
+}
+
+// Disable stops creating deliveries for the subscription id and holds its pending deliveries until
+// Enable.
+func (d *Dispatcher) Disable(ctx context.Context, id string) error {
+	return d.setDisabled(ctx, id, true)
+}
+
+// Enable resumes the subscription id; its held deliveries are sent at the next poll.
+func (d *Dispatcher) Enable(ctx context.Context, id string) error {
+	return d.setDisabled(ctx, id, false)
+}
+
+func (d *Dispatcher) setDisabled(ctx context.Context, id string, disabled bool) error {
+	query := fmt.Sprintf(`UPDATE %s SET disabled_at = CASE WHEN $2 THEN COALESCE(disabled_at, now()) END,
+		updated_at = now() WHERE id = $1`, d.subscriptions.Identifier())
+
+	tag, err := d.db.Exec(ctx, query, id, disabled)
+	if err != nil {
+		return fmt.Errorf("webhooks: failed to update subscription %s: %w", id, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: %s", ErrSubscriptionNotFound, id)
+	}
+
+	return nil
+}
+
+// RotateSecret replaces the secret of the subscription id and returns the new one. Deliveries are
+// signed with the previous secret as well until grace has passed, so the receiver can switch to the new
+// secret in the meantime; a zero grace retires the previous secret at once.
+func (d *Dispatcher) RotateSecret(ctx context.Context, id string, grace time.Duration) (string, error) {
+	secret, err := newSecret()
+	if err != nil {
+		return "", err
+	}
+
+	query := fmt.Sprintf(`UPDATE %s SET previous_secret = secret,
+			previous_secret_expires_at = now() + make_interval(secs => $3), secret = $2, updated_at = now()
+		WHERE id = $1`, d.subscriptions.Identifier())
+
+	tag, err := d.db.Exec(ctx, query, id, secret, grace.Seconds())
+	if err != nil {
+		return "", fmt.Errorf("webhooks: failed to rotate the secret of %s: %w", id, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return "", fmt.Errorf("%w: %s", ErrSubscriptionNotFound, id)
+	}
+
+	return secret, nil
+}
+
+// Unsubscribe deletes the subscription id with its deliveries.
+func (d *Dispatcher) Unsubscribe(ctx context.Context, id string) error {
+	query := fmt.Sprintf(`DELETE FROM %s WHERE id = $1`, d.subscriptions.Identifier())
+
+	tag, err := d.db.Exec(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("webhooks: failed to delete subscription %s: %w", id, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: %s", ErrSubscriptionNotFound, id)
+	}
+
+	return nil
+}
+
+func validateURL(raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidURL, err)
+	}
+
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%w: %q", ErrInvalidURL, raw)
+	}
+
+	return nil
+}
+
+func newSecret() (string, error) {
+	buf := make([]byte, secretBytes)
+
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("webhooks: failed to generate secret: %w", err)
+	}
+
+	return secretPrefix + hex.EncodeToString(buf), nil
+}
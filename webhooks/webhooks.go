@@ -0,0 +1,373 @@
+// Package webhooks delivers events to the HTTP endpoints subscribed to them, e.g. the integrations of
+// customers, with signed payloads, retries and a record of every attempt.
+//
+// Subscriptions and deliveries are stored in Postgres and deliveries are sent by the workers of a
+// taskqueue.Queue. The Dispatcher is a runner service:
+//
+//	dispatcher, err := webhooks.New(pg, redisClient, "webhooks:deliveries")
+//	run.Add(dispatcher)
+//
+//	sub, err := dispatcher.Subscribe(ctx, webhooks.Subscription{
+//	    URL:        "https://partner.example.com/hooks",
+//	    EventTypes: []string{"order.shipped"},
+//	})
+//	// Hand sub.Secret to the receiver, it is not returned again.
+//
+// Publish creates a delivery for every enabled subscription of the event type, in the transaction of
+// ctx when there is one, so events are delivered only for committed changes:
+//
+//	err = postgres.BeginCtx(ctx, pg, func(ctx context.Context) error {
+//	    if err := orders.Ship(ctx, order); err != nil {
+//	        return err
+//	    }
+//
+//	    return dispatcher.Publish(ctx, webhooks.Event{Type: "order.shipped", Data: order})
+//	})
+//
+// A delivery POSTs the JSON envelope {"id", "type", "createdAt", "data"} with the X-Webhook-ID and
+// X-Webhook-Event headers and the X-Signature header "t=<unix>,v1=<hex>", the HMAC-SHA256 of
+// "<unix>.<body>", which middleware.VerifyWebhook checks. RotateSecret replaces the secret of a
+// subscription and keeps signing with the previous one too for a grace period, so receivers can switch
+// without rejecting deliveries.
+//
+// Deliveries answered with a non-2xx status are retried with exponential backoff until they run out of
+// attempts; a 410 Gone disables the subscription. Disabled subscriptions get no new deliveries and their
+// pending ones wait until Enable. RegisterRoutes serves the management endpoints for an operator UI or
+// support tooling; mount them on a group that requires authentication.
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/andyle182810/gframework/postgres"
+	"github.com/andyle182810/gframework/taskqueue"
+	"github.com/andyle182810/gframework/tracing"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	HeaderWebhookID    = "X-Webhook-ID"
+	HeaderWebhookEvent = "X-Webhook-Event"
+
+	defaultTablePrefix    = "webhook"
+	defaultRequestTimeout = 10 * time.Second
+	defaultMaxAttempts    = 8
+	defaultRetryBackoff   = 10 * time.Second
+	defaultMaxBackoff     = time.Hour
+	defaultPollInterval   = time.Second
+	defaultBatchSize      = 100
+	leaseMargin           = 10 * time.Second
+	maxErrorLength        = 1024
+	maxResponseLength     = 4096
+)
+
+var (
+	ErrNilDB                = errors.New("webhooks: database is nil")
+	ErrNilClient            = errors.New("webhooks: redis client is nil")
+	ErrInvalidTablePrefix   = errors.New("webhooks: invalid table prefix")
+	ErrInvalidURL           = errors.New("webhooks: URL must be absolute http or https")
+	ErrEmptyEventType       = errors.New("webhooks: event type is empty")
+	ErrSubscriptionNotFound = errors.New("webhooks: subscription not found")
+	ErrDeliveryNotFound     = errors.New("webhooks: delivery not found")
+	ErrDeliveryPending      = errors.New("webhooks: delivery is still pending")
+)
+
+type DeliveryStatus string
+
+const (
+	// DeliveryPending is a delivery waiting for its next attempt.
+	DeliveryPending DeliveryStatus = "pending"
+	// DeliveryDelivered is a delivery the endpoint answered with a 2xx status.
+	DeliveryDelivered DeliveryStatus = "delivered"
+	// DeliveryFailed is a delivery that ran out of attempts; Replay sends it again.
+	DeliveryFailed DeliveryStatus = "failed"
+)
+
+// Subscription is an endpoint receiving the events of its types.
+type Subscription struct {
+	ID  string `db:"id"  json:"id"`
+	URL string `db:"url" json:"url"`
+	// EventTypes are the types delivered to the endpoint; empty subscribes to every type.
+	EventTypes  []string `db:"event_types" json:"eventTypes"`
+	Description string   `db:"description" json:"description"`
+	// Secret signs the deliveries. It is only set by Subscribe and RotateSecret.
+	Secret     string     `db:"-"           json:"secret,omitempty"`
+	DisabledAt *time.Time `db:"disabled_at" json:"disabledAt,omitempty"`
+	CreatedAt  time.Time  `db:"created_at"  json:"createdAt"`
+	UpdatedAt  time.Time  `db:"updated_at"  json:"updatedAt"`
+}
+
+// Event is published to the subscriptions of its type.
+type Event struct {
+	// ID identifies the event to receivers, which deduplicate with it. It defaults to a new UUID;
+	// publishing an ID again creates no new deliveries.
+	ID   string
+	Type string
+	// Data is sent as the JSON encoding of the data field of the envelope.
+	Data any
+	// CreatedAt defaults to the time of Publish.
+	CreatedAt time.Time
+}
+
+// envelope is the body of a delivery.
+type envelope struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"createdAt"`
+	Data      any       `json:"data"`
+}
+
+// Delivery is an event sent to one subscription.
+type Delivery struct {
+	ID             string          `db:"id"              json:"id"`
+	SubscriptionID string          `db:"subscription_id" json:"subscriptionId"`
+	EventID        string          `db:"event_id"        json:"eventId"`
+	EventType      string          `db:"event_type"      json:"eventType"`
+	Payload        json.RawMessage `db:"payload"         json:"payload"`
+	Status         DeliveryStatus  `db:"status"          json:"status"`
+	// Attempts counts the attempts since the delivery was published or last replayed.
+	Attempts      int        `db:"attempts"        json:"attempts"`
+	LastError     string     `db:"last_error"      json:"lastError,omitempty"`
+	NextAttemptAt time.Time  `db:"next_attempt_at" json:"nextAttemptAt"`
+	DeliveredAt   *time.Time `db:"delivered_at"    json:"deliveredAt,omitempty"`
+	CreatedAt     time.Time  `db:"created_at"      json:"createdAt"`
+	UpdatedAt     time.Time  `db:"updated_at"      json:"updatedAt"`
+}
+
+// Attempt is one request of a delivery.
+type Attempt struct {
+	Attempt int `db:"attempt" json:"attempt"`
+	// StatusCode is zero when the request failed without a response.
+	StatusCode   int       `db:"status_code"   json:"statusCode"`
+	Error        string    `db:"error"         json:"error,omitempty"`
+	ResponseBody string    `db:"response_body" json:"responseBody,omitempty"`
+	DurationMS   int64     `db:"duration_ms"   json:"durationMs"`
+	AttemptedAt  time.Time `db:"attempted_at"  json:"attemptedAt"`
+}
+
+type Option func(*Dispatcher)
+
+// WithTablePrefix sets the prefix of the tables, optionally schema-qualified, e.g. "billing.webhook"
+// for billing.webhook_subscriptions. It defaults to "webhook".
+func WithTablePrefix(prefix string) Option {
+	return func(d *Dispatcher) {
+		d.tablePrefix = prefix
+	}
+}
+
+// WithHTTPClient sets the client sending the deliveries. It defaults to a client traced with
+// tracing.Transport.
+func WithHTTPClient(client *http.Client) Option {
+	return func(d *Dispatcher) {
+		if client != nil {
+			d.httpClient = client
+		}
+	}
+}
+
+// WithRequestTimeout sets the timeout of a delivery request. It defaults to ten seconds.
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(d *Dispatcher) {
+		if timeout > 0 {
+			d.requestTimeout = timeout
+		}
+	}
+}
+
+// WithMaxAttempts sets how often a delivery is attempted before it fails. It defaults to 8.
+func WithMaxAttempts(n int) Option {
+	return func(d *Dispatcher) {
+		if n > 0 {
+			d.maxAttempts = n
+		}
+	}
+}
+
+// WithRetryBackoff sets the delay before the first retry, doubled after every failed attempt up to
+// maxBackoff. It defaults to ten seconds, up to one hour.
+func WithRetryBackoff(initial, maxBackoff time.Duration) Option {
+	return func(d *Dispatcher) {
+		if initial > 0 {
+			d.retryBackoff = initial
+		}
+
+		if maxBackoff >= d.retryBackoff {
+			d.maxBackoff = maxBackoff
+		}
+	}
+}
+
+// WithPollInterval sets how often the deliveries are polled for due retries, deliveries published in a
+// transaction and deliveries whose task was lost. It defaults to one second.
+func WithPollInterval(interval time.Duration) Option {
+	return func(d *Dispatcher) {
+		if interval > 0 {
+			d.pollInterval = interval
+		}
+	}
+}
+
+// WithBatchSize sets the maximum number of due deliveries dispatched per poll. It defaults to 100.
+func WithBatchSize(n int) Option {
+	return func(d *Dispatcher) {
+		if n > 0 {
+			d.batchSize = n
+		}
+	}
+}
+
+// WithQueueOptions configures the task queue sending the deliveries, e.g. taskqueue.WithWorkerCount.
+func WithQueueOptions(opts ...taskqueue.Option) Option {
+	return func(d *Dispatcher) {
+		d.queueOptions = append(d.queueOptions, opts...)
+	}
+}
+
+// Dispatcher stores webhook subscriptions and delivers published events to them. Several dispatchers
+// can share the tables and the queue; each attempt of a delivery is claimed by one of them.
+type Dispatcher struct {
+	db             postgres.Conn
+	queue          *taskqueue.Queue
+	httpClient     *http.Client
+	tablePrefix    string
+	subscriptions  postgres.Table
+	deliveries     postgres.Table
+	attempts       postgres.Table
+	requestTimeout time.Duration
+	maxAttempts    int
+	retryBackoff   time.Duration
+	maxBackoff     time.Duration
+	pollInterval   time.Duration
+	batchSize      int
+	queueOptions   []taskqueue.Option
+	running        atomic.Bool
+	mu             sync.Mutex
+	stop           chan struct{}
+	stopped        chan struct{}
+}
+
+// New returns a dispatcher storing webhooks in db and sending deliveries with a task queue at queueKey
+// of client.
+func New(db postgres.Conn, client redis.UniversalClient, queueKey string, opts ...Option) (*Dispatcher, error) {
+	if db == nil {
+		return nil, ErrNilDB
+	}
+
+	if client == nil {
+		return nil, ErrNilClient
+	}
+
+	//nolint:exhaustruct
+	dispatcher := &Dispatcher{
+		db:             db,
+		httpClient:     &http.Client{Transport: tracing.Transport(nil)},
+		tablePrefix:    defaultTablePrefix,
+		requestTimeout: defaultRequestTimeout,
+		maxAttempts:    defaultMaxAttempts,
+		retryBackoff:   defaultRetryBackoff,
+		maxBackoff:     defaultMaxBackoff,
+		pollInterval:   defaultPollInterval,
+		batchSize:      defaultBatchSize,
+	}
+
+	for _, opt := range opts {
+		opt(dispatcher)
+	}
+
+	for _, t := range []struct {
+		target *postgres.Table
+		suffix string
+	}{
+		{&dispatcher.subscriptions, "_subscriptions"},
+		{&dispatcher.deliveries, "_deliveries"},
+		{&dispatcher.attempts, "_attempts"},
+	} {
+		tbl, err := postgres.NewTable(dispatcher.tablePrefix + t.suffix)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidTablePrefix, err)
+		}
+
+		*t.target = tbl
+	}
+
+	queue, err := taskqueue.New(client, queueKey, dispatcher, dispatcher.queueOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("webhooks: failed to create task queue: %w", err)
+	}
+
+	dispatcher.queue = queue
+
+	return dispatcher, nil
+}
+
+// Schema returns the DDL of the tables and their indexes, to include in the service's migrations.
+func (d *Dispatcher) Schema() string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %[1]s (
+	id                         TEXT PRIMARY KEY,
+	url                        TEXT NOT NULL,
+	event_types                TEXT[] NOT NULL DEFAULT '{}',
+	description                TEXT NOT NULL DEFAULT '',
+	secret                     TEXT NOT NULL,
+	previous_secret            TEXT,
+	previous_secret_expires_at TIMESTAMPTZ,
+	disabled_at                TIMESTAMPTZ,
+	created_at                 TIMESTAMPTZ NOT NULL DEFAULT now(),
+	updated_at                 TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE TABLE IF NOT EXISTS %[2]s (
+	id              TEXT PRIMARY KEY,
+	subscription_id TEXT NOT NULL REFERENCES %[1]s (id) ON DELETE CASCADE,
+	event_id        TEXT NOT NULL,
+	event_type      TEXT NOT NULL,
+	payload         JSONB NOT NULL,
+	status          TEXT NOT NULL,
+	attempts        INTEGER NOT NULL DEFAULT 0,
+	version         BIGINT NOT NULL DEFAULT 0,
+	last_error      TEXT,
+	next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+	delivered_at    TIMESTAMPTZ,
+	created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+	updated_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+	UNIQUE (subscription_id, event_id)
+);
+CREATE INDEX IF NOT EXISTS %[4]s ON %[2]s (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS %[5]s ON %[2]s (subscription_id, created_at, id);
+CREATE TABLE IF NOT EXISTS %[3]s (
+	id            BIGSERIAL PRIMARY KEY,
+	delivery_id   TEXT NOT NULL REFERENCES %[2]s (id) ON DELETE CASCADE,
+	attempt       INTEGER NOT NULL,
+	status_code   INTEGER NOT NULL DEFAULT 0,
+	error         TEXT NOT NULL DEFAULT '',
+	response_body TEXT NOT NULL DEFAULT '',
+	duration_ms   BIGINT NOT NULL,
+	attempted_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS %[6]s ON %[3]s (delivery_id, id);
+`, d.subscriptions.Identifier(), d.deliveries.Identifier(), d.attempts.Identifier(),
+		d.deliveries.Index("pending_idx"), d.deliveries.Index("subscription_idx"), d.attempts.Index("delivery_idx"))
+}
+
+// EnsureSchema creates the tables when they do not exist, for services without migrations and for
+// tests.
+func (d *Dispatcher) EnsureSchema(ctx context.Context) error {
+	if _, err := d.db.Exec(ctx, d.Schema()); err != nil {
+		return fmt.Errorf("webhooks: failed to create tables %s_*: %w", d.tablePrefix, err)
+	}
+
+	return nil
+}
+
+func truncate(message string, length int) string {
+	if len(message) > length {
+		return message[:length]
+	}
+
+	return message
+}
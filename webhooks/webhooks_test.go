@@ -0,0 +1,494 @@
+//nolint:exhaustruct
+package webhooks_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/middleware"
+	"github.com/andyle182810/gframework/pagination"
+	"github.com/andyle182810/gframework/postgres"
+	"github.com/andyle182810/gframework/taskqueue"
+	"github.com/andyle182810/gframework/testutil"
+	"github.com/andyle182810/gframework/webhooks"
+	"github.com/labstack/echo/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew_Validates(t *testing.T) {
+	t.Parallel()
+
+	client := testutil.NewFakeValkey(t)
+
+	_, err := webhooks.New(nil, client, "webhooks")
+	require.ErrorIs(t, err, webhooks.ErrNilDB)
+
+	_, err = webhooks.New(&postgres.Postgres{}, nil, "webhooks")
+	require.ErrorIs(t, err, webhooks.ErrNilClient)
+
+	_, err = webhooks.New(&postgres.Postgres{}, client, "")
+	require.ErrorIs(t, err, taskqueue.ErrEmptyQueueKey)
+
+	_, err = webhooks.New(&postgres.Postgres{}, client, "webhooks", webhooks.WithTablePrefix("hooks; DROP TABLE users"))
+	require.ErrorIs(t, err, webhooks.ErrInvalidTablePrefix)
+
+	dispatcher, err := webhooks.New(&postgres.Postgres{}, client, "webhooks", webhooks.WithTablePrefix("billing.hook"))
+	require.NoError(t, err)
+	require.Contains(t, dispatcher.Schema(), `CREATE TABLE IF NOT EXISTS "billing"."hook_subscriptions"`)
+	require.Contains(t, dispatcher.Schema(), `CREATE TABLE IF NOT EXISTS "billing"."hook_deliveries"`)
+	require.Contains(t, dispatcher.Schema(), `CREATE TABLE IF NOT EXISTS "billing"."hook_attempts"`)
+
+	require.ErrorIs(t, dispatcher.Publish(t.Context(), webhooks.Event{}), webhooks.ErrEmptyEventType)
+
+	_, err = dispatcher.Subscribe(t.Context(), webhooks.Subscription{URL: "ftp://example.com/hooks"})
+	require.ErrorIs(t, err, webhooks.ErrInvalidURL)
+
+	_, err = dispatcher.Subscribe(t.Context(), webhooks.Subscription{URL: "/hooks"})
+	require.ErrorIs(t, err, webhooks.ErrInvalidURL)
+}
+
+func TestSign(t *testing.T) {
+	t.Parallel()
+
+	body := []byte(`{"id":"evt-1","type":"order.created"}`)
+
+	header, err := webhooks.Sign(body, time.Now(), "whsec_new", "whsec_old")
+	require.NoError(t, err)
+	require.Equal(t, 2, strings.Count(header, ",v1="))
+
+	tests := []struct {
+		name     string
+		secret   string
+		header   string
+		expected int
+	}{
+		{name: "current secret", secret: "whsec_new", header: header, expected: http.StatusOK},
+		{name: "previous secret", secret: "whsec_old", header: header, expected: http.StatusOK},
+		{name: "unknown secret", secret: "whsec_other", header: header, expected: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := echo.New()
+			e.POST("/hooks", func(c *echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			}, middleware.VerifyWebhook(middleware.StaticWebhookSecret(tt.secret), "", middleware.WebhookAlgorithmSHA256, nil))
+
+			req := httptest.NewRequest(http.MethodPost, "/hooks", strings.NewReader(string(body)))
+			req.Header.Set(middleware.HeaderXSignature, tt.header)
+
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.expected, rec.Code)
+		})
+	}
+}
+
+// receiver is an endpoint that verifies the signature of the deliveries and answers with status.
+type receiver struct {
+	*httptest.Server
+
+	status atomic.Int32
+	mu     sync.Mutex
+	events []string
+}
+
+func newReceiver(t *testing.T, secrets func() []string) *receiver {
+	t.Helper()
+
+	r := &receiver{}
+	r.status.Store(http.StatusOK)
+
+	provider := func(_ *echo.Context) ([][]byte, error) {
+		keys := [][]byte{}
+		for _, secret := range secrets() {
+			keys = append(keys, []byte(secret))
+		}
+
+		return keys, nil
+	}
+
+	e := echo.New()
+	e.POST("/hooks", func(c *echo.Context) error {
+		if _, err := io.Copy(io.Discard, c.Request().Body); err != nil {
+			return err
+		}
+
+		r.mu.Lock()
+		r.events = append(r.events, c.Request().Header.Get(webhooks.HeaderWebhookEvent))
+		r.mu.Unlock()
+
+		return c.String(int(r.status.Load()), "ack")
+	}, middleware.VerifyWebhook(provider, "", middleware.WebhookAlgorithmSHA256, nil))
+
+	r.Server = httptest.NewServer(e)
+	t.Cleanup(r.Close)
+
+	return r
+}
+
+func (r *receiver) Events() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]string(nil), r.events...)
+}
+
+func setupPostgres(t *testing.T) *postgres.Postgres {
+	t.Helper()
+
+	container := testutil.SetupPostgresContainer(t)
+
+	pg, err := postgres.New(&postgres.Config{
+		URL:                   container.ConnectionString(),
+		MaxConnection:         5,
+		MinConnection:         1,
+		MaxConnectionIdleTime: time.Minute,
+		HealthCheckPeriod:     10 * time.Second,
+	})
+	require.NoError(t, err)
+
+	t.Cleanup(pg.Close)
+
+	return pg
+}
+
+// startDispatcher runs a dispatcher with its own tables, so the subtests do not send each other's
+// deliveries.
+func startDispatcher(
+	t *testing.T,
+	pg *postgres.Postgres,
+	prefix string,
+	opts ...webhooks.Option,
+) *webhooks.Dispatcher {
+	t.Helper()
+
+	opts = append([]webhooks.Option{
+		webhooks.WithTablePrefix(prefix),
+		webhooks.WithPollInterval(20 * time.Millisecond),
+		webhooks.WithRetryBackoff(10*time.Millisecond, 20*time.Millisecond),
+		webhooks.WithQueueOptions(taskqueue.WithPollInterval(10 * time.Millisecond)),
+	}, opts...)
+
+	dispatcher, err := webhooks.New(pg, testutil.NewFakeValkey(t), "webhooks:"+prefix, opts...)
+	require.NoError(t, err)
+	require.NoError(t, dispatcher.EnsureSchema(t.Context()))
+
+	go func() {
+		_ = dispatcher.Start(context.WithoutCancel(t.Context()))
+	}()
+
+	t.Cleanup(func() {
+		require.NoError(t, dispatcher.Stop())
+	})
+
+	return dispatcher
+}
+
+func waitForDelivery(
+	t *testing.T,
+	dispatcher *webhooks.Dispatcher,
+	subscriptionID string,
+	status webhooks.DeliveryStatus,
+) webhooks.Delivery {
+	t.Helper()
+
+	var delivery webhooks.Delivery
+
+	require.Eventually(t, func() bool {
+		deliveries, _, err := dispatcher.Deliveries(t.Context(), subscriptionID, status, pagination.Cursor{}, 10)
+		if err != nil || len(deliveries) == 0 {
+			return false
+		}
+
+		delivery = deliveries[0]
+
+		return true
+	}, 10*time.Second, 20*time.Millisecond)
+
+	return delivery
+}
+
+func TestDispatcher(t *testing.T) {
+	t.Parallel()
+
+	pg := setupPostgres(t)
+
+	t.Run("delivers signed events to the subscriptions of their type", func(t *testing.T) {
+		t.Parallel()
+
+		var secret atomic.Value
+
+		recv := newReceiver(t, func() []string { return []string{secret.Load().(string)} })
+		dispatcher := startDispatcher(t, pg, "webhook_delivered")
+
+		orders, err := dispatcher.Subscribe(t.Context(), webhooks.Subscription{
+			URL:        recv.URL + "/hooks",
+			EventTypes: []string{"order.created"},
+		})
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(orders.Secret, "whsec_"))
+
+		secret.Store(orders.Secret)
+
+		_, err = dispatcher.Subscribe(t.Context(), webhooks.Subscription{
+			URL:        recv.URL + "/hooks",
+			EventTypes: []string{"invoice.paid"},
+		})
+		require.NoError(t, err)
+
+		event := webhooks.Event{ID: "evt-1", Type: "order.created", Data: map[string]string{"orderId": "order-1"}}
+		require.NoError(t, dispatcher.Publish(t.Context(), event))
+		require.NoError(t, dispatcher.Publish(t.Context(), event))
+
+		delivery := waitForDelivery(t, dispatcher, orders.ID, webhooks.DeliveryDelivered)
+		require.Equal(t, "evt-1", delivery.EventID)
+		require.Equal(t, 1, delivery.Attempts)
+		require.NotNil(t, delivery.DeliveredAt)
+		require.Equal(t, []string{"order.created"}, recv.Events())
+
+		var body struct {
+			ID   string            `json:"id"`
+			Type string            `json:"type"`
+			Data map[string]string `json:"data"`
+		}
+
+		require.NoError(t, json.Unmarshal(delivery.Payload, &body))
+		require.Equal(t, "evt-1", body.ID)
+		require.Equal(t, "order-1", body.Data["orderId"])
+
+		attempts, err := dispatcher.Attempts(t.Context(), delivery.ID)
+		require.NoError(t, err)
+		require.Len(t, attempts, 1)
+		require.Equal(t, http.StatusOK, attempts[0].StatusCode)
+		require.Equal(t, "ack", attempts[0].ResponseBody)
+	})
+
+	t.Run("signs with the previous secret during the grace period", func(t *testing.T) {
+		t.Parallel()
+
+		var secret atomic.Value
+
+		recv := newReceiver(t, func() []string { return []string{secret.Load().(string)} })
+		dispatcher := startDispatcher(t, pg, "webhook_rotated")
+
+		sub, err := dispatcher.Subscribe(t.Context(), webhooks.Subscription{URL: recv.URL + "/hooks"})
+		require.NoError(t, err)
+
+		secret.Store(sub.Secret)
+
+		rotated, err := dispatcher.RotateSecret(t.Context(), sub.ID, time.Hour)
+		require.NoError(t, err)
+		require.NotEqual(t, sub.Secret, rotated)
+
+		require.NoError(t, dispatcher.Publish(t.Context(), webhooks.Event{Type: "order.created"}))
+		waitForDelivery(t, dispatcher, sub.ID, webhooks.DeliveryDelivered)
+
+		_, err = dispatcher.RotateSecret(t.Context(), "missing", time.Hour)
+		require.ErrorIs(t, err, webhooks.ErrSubscriptionNotFound)
+	})
+
+	t.Run("retries, fails and replays a delivery", func(t *testing.T) {
+		t.Parallel()
+
+		var secret atomic.Value
+
+		recv := newReceiver(t, func() []string { return []string{secret.Load().(string)} })
+		recv.status.Store(http.StatusInternalServerError)
+
+		dispatcher := startDispatcher(t, pg, "webhook_failed", webhooks.WithMaxAttempts(3))
+
+		sub, err := dispatcher.Subscribe(t.Context(), webhooks.Subscription{URL: recv.URL + "/hooks"})
+		require.NoError(t, err)
+
+		secret.Store(sub.Secret)
+
+		require.NoError(t, dispatcher.Publish(t.Context(), webhooks.Event{Type: "order.created"}))
+
+		delivery := waitForDelivery(t, dispatcher, sub.ID, webhooks.DeliveryFailed)
+		require.Equal(t, 3, delivery.Attempts)
+		require.Contains(t, delivery.LastError, "500")
+
+		attempts, err := dispatcher.Attempts(t.Context(), delivery.ID)
+		require.NoError(t, err)
+		require.Len(t, attempts, 3)
+
+		recv.status.Store(http.StatusOK)
+		require.NoError(t, dispatcher.Replay(t.Context(), delivery.ID))
+
+		delivery = waitForDelivery(t, dispatcher, sub.ID, webhooks.DeliveryDelivered)
+		require.Equal(t, 1, delivery.Attempts)
+
+		attempts, err = dispatcher.Attempts(t.Context(), delivery.ID)
+		require.NoError(t, err)
+		require.Len(t, attempts, 4)
+		require.ErrorIs(t, dispatcher.Replay(t.Context(), "missing"), webhooks.ErrDeliveryNotFound)
+	})
+
+	t.Run("holds the deliveries of a disabled subscription until it is enabled", func(t *testing.T) {
+		t.Parallel()
+
+		var secret atomic.Value
+
+		recv := newReceiver(t, func() []string { return []string{secret.Load().(string)} })
+		recv.status.Store(http.StatusInternalServerError)
+
+		dispatcher := startDispatcher(t, pg, "webhook_disabled", webhooks.WithMaxAttempts(100))
+
+		sub, err := dispatcher.Subscribe(t.Context(), webhooks.Subscription{URL: recv.URL + "/hooks"})
+		require.NoError(t, err)
+
+		secret.Store(sub.Secret)
+
+		require.NoError(t, dispatcher.Publish(t.Context(), webhooks.Event{ID: "evt-1", Type: "order.created"}))
+		require.Eventually(t, func() bool { return len(recv.Events()) > 0 }, 10*time.Second, 20*time.Millisecond)
+
+		require.NoError(t, dispatcher.Disable(t.Context(), sub.ID))
+		require.NoError(t, dispatcher.Publish(t.Context(), webhooks.Event{ID: "evt-2", Type: "order.created"}))
+
+		sent := len(recv.Events())
+		time.Sleep(200 * time.Millisecond)
+		require.LessOrEqual(t, len(recv.Events()), sent+1, "an attempt in flight may still finish")
+
+		recv.status.Store(http.StatusOK)
+		require.NoError(t, dispatcher.Enable(t.Context(), sub.ID))
+
+		delivery := waitForDelivery(t, dispatcher, sub.ID, webhooks.DeliveryDelivered)
+		require.Equal(t, "evt-1", delivery.EventID)
+
+		deliveries, _, err := dispatcher.Deliveries(t.Context(), sub.ID, "", pagination.Cursor{}, 10)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1, "events published while disabled are not delivered")
+	})
+
+	t.Run("disables a subscription whose endpoint is gone", func(t *testing.T) {
+		t.Parallel()
+
+		var secret atomic.Value
+
+		recv := newReceiver(t, func() []string { return []string{secret.Load().(string)} })
+		recv.status.Store(http.StatusGone)
+
+		dispatcher := startDispatcher(t, pg, "webhook_gone")
+
+		sub, err := dispatcher.Subscribe(t.Context(), webhooks.Subscription{URL: recv.URL + "/hooks"})
+		require.NoError(t, err)
+
+		secret.Store(sub.Secret)
+
+		require.NoError(t, dispatcher.Publish(t.Context(), webhooks.Event{Type: "order.created"}))
+
+		require.Eventually(t, func() bool {
+			current, err := dispatcher.Subscription(t.Context(), sub.ID)
+
+			return err == nil && current.DisabledAt != nil
+		}, 10*time.Second, 20*time.Millisecond)
+	})
+
+	t.Run("publishes in a transaction once it commits", func(t *testing.T) {
+		t.Parallel()
+
+		var secret atomic.Value
+
+		recv := newReceiver(t, func() []string { return []string{secret.Load().(string)} })
+		dispatcher := startDispatcher(t, pg, "webhook_transaction")
+
+		sub, err := dispatcher.Subscribe(t.Context(), webhooks.Subscription{URL: recv.URL + "/hooks"})
+		require.NoError(t, err)
+
+		secret.Store(sub.Secret)
+
+		errRollback := errors.New("rollback")
+
+		err = postgres.BeginCtx(t.Context(), pg, func(ctx context.Context) error {
+			require.NoError(t, dispatcher.Publish(ctx, webhooks.Event{ID: "evt-1", Type: "order.created"}))
+
+			return errRollback
+		})
+		require.ErrorIs(t, err, errRollback)
+
+		err = postgres.BeginCtx(t.Context(), pg, func(ctx context.Context) error {
+			return dispatcher.Publish(ctx, webhooks.Event{ID: "evt-2", Type: "order.created"})
+		})
+		require.NoError(t, err)
+
+		delivery := waitForDelivery(t, dispatcher, sub.ID, webhooks.DeliveryDelivered)
+		require.Equal(t, "evt-2", delivery.EventID)
+	})
+
+	t.Run("serves the management endpoints", func(t *testing.T) {
+		t.Parallel()
+
+		var secret atomic.Value
+
+		recv := newReceiver(t, func() []string { return []string{secret.Load().(string)} })
+		recv.status.Store(http.StatusBadRequest)
+
+		dispatcher := startDispatcher(t, pg, "webhook_handlers", webhooks.WithMaxAttempts(1))
+
+		sub, err := dispatcher.Subscribe(t.Context(), webhooks.Subscription{URL: recv.URL + "/hooks"})
+		require.NoError(t, err)
+
+		secret.Store(sub.Secret)
+
+		require.NoError(t, dispatcher.Publish(t.Context(), webhooks.Event{Type: "order.created"}))
+
+		delivery := waitForDelivery(t, dispatcher, sub.ID, webhooks.DeliveryFailed)
+
+		srv := testutil.StartTestServer(t, func(_ *echo.Echo, root *echo.Group) {
+			dispatcher.RegisterRoutes(root)
+		})
+
+		rec := srv.Request(t, http.MethodGet, "/webhooks/subscriptions?limit=10", nil)
+		testutil.AssertStatusCode(t, rec, http.StatusOK)
+		require.Contains(t, rec.Body.String(), sub.ID)
+		require.NotContains(t, rec.Body.String(), sub.Secret)
+
+		rec = srv.Request(t, http.MethodGet, "/webhooks/subscriptions/"+sub.ID+"/deliveries?status=failed", nil)
+		testutil.AssertStatusCode(t, rec, http.StatusOK)
+		require.Contains(t, rec.Body.String(), delivery.ID)
+
+		rec = srv.Request(t, http.MethodGet, "/webhooks/subscriptions/"+sub.ID+"/deliveries?status=unknown", nil)
+		testutil.AssertStatusCode(t, rec, http.StatusBadRequest)
+
+		rec = srv.Request(t, http.MethodGet, "/webhooks/subscriptions/missing/deliveries", nil)
+		testutil.AssertStatusCode(t, rec, http.StatusNotFound)
+
+		rec = srv.Request(t, http.MethodGet, "/webhooks/deliveries/"+delivery.ID+"/attempts", nil)
+		testutil.AssertStatusCode(t, rec, http.StatusOK)
+		require.Contains(t, rec.Body.String(), `"statusCode":400`)
+
+		rec = srv.Request(t, http.MethodPost, "/webhooks/subscriptions/"+sub.ID+"/disable", nil)
+		testutil.AssertStatusCode(t, rec, http.StatusOK)
+		require.Contains(t, rec.Body.String(), `"disabledAt"`)
+
+		rec = srv.Request(t, http.MethodPost, "/webhooks/subscriptions/"+sub.ID+"/enable", nil)
+		testutil.AssertStatusCode(t, rec, http.StatusOK)
+		require.NotContains(t, rec.Body.String(), `"disabledAt"`)
+
+		recv.status.Store(http.StatusOK)
+
+		rec = srv.Request(t, http.MethodPost, "/webhooks/deliveries/"+delivery.ID+"/replay", nil)
+		testutil.AssertStatusCode(t, rec, http.StatusOK)
+
+		rec = srv.Request(t, http.MethodPost, "/webhooks/deliveries/missing/replay", nil)
+		testutil.AssertStatusCode(t, rec, http.StatusNotFound)
+
+		waitForDelivery(t, dispatcher, sub.ID, webhooks.DeliveryDelivered)
+
+		rec = srv.Request(t, http.MethodPost, "/webhooks/subscriptions/missing/disable", nil)
+		testutil.AssertStatusCode(t, rec, http.StatusNotFound)
+	})
+}
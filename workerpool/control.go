@@ -0,0 +1,133 @@
+package workerpool
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const drainPollInterval = 10 * time.Millisecond
+
+// Pause halts the pool without stopping it: ticks are skipped and submitted tasks stay queued until
+// Resume. Executions already running continue. A paused pool reports unhealthy.
+func (pool *WorkerPool) Pause() {
+	pool.pauseMu.Lock()
+	defer pool.pauseMu.Unlock()
+
+	if pool.resumed != nil {
+		return
+	}
+
+	pool.resumed = make(chan struct{})
+	pool.paused.Store(true)
+
+	log.Warn().Str("source", "gframework").Str("pool_name", pool.name).Msg("Worker pool is paused")
+}
+
+func (pool *WorkerPool) Resume() {
+	pool.pauseMu.Lock()
+	defer pool.pauseMu.Unlock()
+
+	if pool.resumed == nil {
+		return
+	}
+
+	close(pool.resumed)
+	pool.resumed = nil
+	pool.paused.Store(false)
+
+	log.Info().Str("source", "gframework").Str("pool_name", pool.name).Msg("Worker pool is resumed")
+}
+
+func (pool *WorkerPool) IsPaused() bool {
+	return pool.paused.Load()
+}
+
+// IsHealthy reports whether the pool is running and not paused.
+func (pool *WorkerPool) IsHealthy() bool {
+	return pool.running.Load() && !pool.paused.Load()
+}
+
+// Drain blocks until no execution is in flight and, unless the pool is paused, every submitted task
+// has finished. Combined with Pause it waits for the pool to become idle.
+func (pool *WorkerPool) Drain(ctx context.Context) error {
+	ticker := pool.clock.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if pool.inFlight.Load() == 0 && (pool.paused.Load() || pool.pending.Load() == 0) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C():
+		}
+	}
+}
+
+// ControlHandler serves Pause, Resume and Drain for operational control: POST ?action=pause|resume|drain
+// responds with the pool state. Drain is bounded by the request context.
+func (pool *WorkerPool) ControlHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		switch req.URL.Query().Get("action") {
+		case "pause":
+			pool.Pause()
+		case "resume":
+			pool.Resume()
+		case "drain":
+			if err := pool.Drain(req.Context()); err != nil {
+				http.Error(w, err.Error(), http.StatusGatewayTimeout)
+
+				return
+			}
+		default:
+			http.Error(w, "action must be pause, resume or drain", http.StatusBadRequest)
+
+			return
+		}
+
+		stats := pool.Stats()
+
+		w.Header().Set("Content-Type", "application/json")
+
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"name":         pool.name,
+			"paused":       stats.Paused,
+			"in_flight":    stats.InFlight,
+			"queue_length": stats.QueueLength,
+		})
+	})
+}
+
+// waitResumed blocks while the pool is paused. It returns false when ctx is done or the worker is
+// removed by quit first.
+func (pool *WorkerPool) waitResumed(ctx context.Context, quit <-chan struct{}) bool {
+	pool.pauseMu.Lock()
+	resumed := pool.resumed
+	pool.pauseMu.Unlock()
+
+	if resumed == nil {
+		return true
+	}
+
+	select {
+	case <-resumed:
+		return true
+	case <-quit:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
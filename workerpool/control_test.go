@@ -0,0 +1,157 @@
+package workerpool_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/workerpool"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerPool_PauseSkipsTicksUntilResume(t *testing.T) {
+	t.Parallel()
+
+	executor := newMockExecutor()
+	pool := workerpool.New(executor, workerpool.WithTickInterval(5*time.Millisecond))
+
+	pool.Pause()
+	require.True(t, pool.IsPaused())
+
+	require.NoError(t, pool.Start(t.Context()))
+
+	defer func() { _ = pool.Stop() }()
+
+	require.False(t, pool.IsHealthy())
+
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, int32(0), executor.execCount.Load())
+
+	pool.Resume()
+	require.True(t, pool.IsHealthy())
+	require.Eventually(t, func() bool { return executor.execCount.Load() > 0 }, time.Second, 5*time.Millisecond)
+}
+
+func TestWorkerPool_PauseHoldsSubmittedTasks(t *testing.T) {
+	t.Parallel()
+
+	pool := workerpool.New(nil, workerpool.WithWorkerCount(1))
+	require.NoError(t, pool.Start(t.Context()))
+
+	defer func() { _ = pool.Stop() }()
+
+	pool.Pause()
+
+	var ran atomic.Bool
+
+	require.NoError(t, pool.Submit(t.Context(), func(context.Context) error {
+		ran.Store(true)
+
+		return nil
+	}))
+
+	time.Sleep(50 * time.Millisecond)
+	require.False(t, ran.Load())
+	require.True(t, pool.Stats().Paused)
+
+	pool.Resume()
+	require.Eventually(t, ran.Load, time.Second, 5*time.Millisecond)
+}
+
+func TestWorkerPool_ResizeWhilePausedKeepsSubmittedTasks(t *testing.T) {
+	t.Parallel()
+
+	pool := workerpool.New(nil, workerpool.WithWorkerCount(3))
+	require.NoError(t, pool.Start(t.Context()))
+
+	defer func() { _ = pool.Stop() }()
+
+	pool.Pause()
+
+	var ran atomic.Int32
+
+	for range 3 {
+		require.NoError(t, pool.Submit(t.Context(), func(context.Context) error {
+			ran.Add(1)
+
+			return nil
+		}))
+	}
+
+	require.Eventually(t, func() bool { return pool.QueueLength() == 0 }, time.Second, time.Millisecond,
+		"every worker should hold a task")
+
+	require.NoError(t, pool.Resize(1))
+	require.Eventually(t, func() bool { return pool.QueueLength() == 2 }, time.Second, time.Millisecond,
+		"removed workers should hand their tasks back")
+
+	pool.Resume()
+	require.Eventually(t, func() bool { return ran.Load() == 3 }, time.Second, 5*time.Millisecond)
+}
+
+func TestWorkerPool_DrainWaitsForInFlightWork(t *testing.T) {
+	t.Parallel()
+
+	pool := workerpool.New(nil, workerpool.WithWorkerCount(2))
+	require.NoError(t, pool.Start(t.Context()))
+
+	defer func() { _ = pool.Stop() }()
+
+	var finished atomic.Int32
+
+	for range 4 {
+		require.NoError(t, pool.Submit(t.Context(), func(context.Context) error {
+			time.Sleep(20 * time.Millisecond)
+			finished.Add(1)
+
+			return nil
+		}))
+	}
+
+	require.NoError(t, pool.Drain(t.Context()))
+	require.Equal(t, int32(4), finished.Load())
+
+	require.NoError(t, pool.Submit(t.Context(), func(ctx context.Context) error {
+		<-ctx.Done()
+
+		return nil
+	}))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, pool.Drain(ctx), context.DeadlineExceeded)
+}
+
+func TestWorkerPool_ControlHandler(t *testing.T) {
+	t.Parallel()
+
+	pool := workerpool.New(newMockExecutor())
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		status int
+		paused bool
+	}{
+		{name: "pause", method: http.MethodPost, target: "/admin/pool?action=pause", status: http.StatusOK, paused: true},
+		{name: "drain while paused", method: http.MethodPost, target: "/admin/pool?action=drain", status: http.StatusOK, paused: true},
+		{name: "resume", method: http.MethodPost, target: "/admin/pool?action=resume", status: http.StatusOK, paused: false},
+		{name: "unknown action", method: http.MethodPost, target: "/admin/pool?action=explode", status: http.StatusBadRequest, paused: false},
+		{name: "wrong method", method: http.MethodGet, target: "/admin/pool?action=pause", status: http.StatusMethodNotAllowed, paused: false},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequestWithContext(t.Context(), tt.method, tt.target, nil)
+		rec := httptest.NewRecorder()
+
+		pool.ControlHandler().ServeHTTP(rec, req)
+
+		require.Equal(t, tt.status, rec.Code, tt.name)
+		require.Equal(t, tt.paused, pool.IsPaused(), tt.name)
+	}
+}
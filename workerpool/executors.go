@@ -0,0 +1,109 @@
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrNilExecutor       = errors.New("worker pool executor is nil")
+	ErrDuplicateExecutor = errors.New("worker pool executor is already registered")
+)
+
+type registeredExecutor struct {
+	name     string
+	executor Executor
+	timeout  time.Duration
+	weight   int
+}
+
+type ExecutorOption func(*registeredExecutor)
+
+// WithExecutorTimeout overrides the pool's execution timeout for one executor.
+func WithExecutorTimeout(timeout time.Duration) ExecutorOption {
+	return func(e *registeredExecutor) {
+		if timeout > 0 {
+			e.timeout = timeout
+		}
+	}
+}
+
+// WithExecutorWeight dispatches weight executions of the executor per tick instead of one. The pool's
+// worker count still caps the total concurrency.
+func WithExecutorWeight(weight int) ExecutorOption {
+	return func(e *registeredExecutor) {
+		if weight > 0 {
+			e.weight = weight
+		}
+	}
+}
+
+func newRegisteredExecutor(name string, executor Executor, opts ...ExecutorOption) *registeredExecutor {
+	registered := &registeredExecutor{
+		name:     name,
+		executor: executor,
+		timeout:  0,
+		weight:   1,
+	}
+
+	for _, opt := range opts {
+		opt(registered)
+	}
+
+	return registered
+}
+
+// Register adds a named executor that runs on every tick alongside the others, so one pool can host
+// several periodic jobs. It must be called before Start.
+func (pool *WorkerPool) Register(name string, executor Executor, opts ...ExecutorOption) error {
+	if executor == nil {
+		return ErrNilExecutor
+	}
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if pool.running.Load() {
+		return ErrAlreadyRunning
+	}
+
+	for _, registered := range pool.executors {
+		if registered.name == name {
+			return fmt.Errorf("%w: %s", ErrDuplicateExecutor, name)
+		}
+	}
+
+	pool.executors = append(pool.executors, newRegisteredExecutor(name, executor, opts...))
+
+	return nil
+}
+
+// sendJobs hands one tick's executions to the workers. When block is false, executions that find no
+// idle worker are skipped and counted. ok is false when ctx is done.
+func (pool *WorkerPool) sendJobs(ctx context.Context, block bool) (int, bool) {
+	skipped := 0
+
+	for _, registered := range pool.executors {
+		for range registered.weight {
+			if !block {
+				select {
+				case pool.jobChan <- registered:
+				default:
+					skipped++
+				}
+
+				continue
+			}
+
+			select {
+			case pool.jobChan <- registered:
+			case <-ctx.Done():
+				return skipped, false
+			}
+		}
+	}
+
+	return skipped, ctx.Err() == nil
+}
@@ -0,0 +1,67 @@
+package workerpool_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/workerpool"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerPool_RegisterFansOutEachTick(t *testing.T) {
+	t.Parallel()
+
+	primary := newMockExecutor()
+	cleanup := newMockExecutor()
+	reports := newMockExecutor()
+
+	pool := workerpool.New(primary, workerpool.WithWorkerCount(4), workerpool.WithTickInterval(20*time.Millisecond))
+	require.NoError(t, pool.Register("cleanup", cleanup))
+	require.NoError(t, pool.Register("reports", reports, workerpool.WithExecutorWeight(2)))
+
+	require.NoError(t, pool.Start(t.Context()))
+	require.Eventually(t, func() bool {
+		return primary.execCount.Load() >= 2 && cleanup.execCount.Load() >= 2 && reports.execCount.Load() >= 4
+	}, time.Second, 5*time.Millisecond)
+	require.NoError(t, pool.Stop())
+
+	require.GreaterOrEqual(t, reports.execCount.Load(), cleanup.execCount.Load())
+}
+
+func TestWorkerPool_RegisterUsesExecutorTimeout(t *testing.T) {
+	t.Parallel()
+
+	slow := newMockExecutor()
+	slow.execDuration = time.Second
+
+	pool := workerpool.New(nil, workerpool.WithTickInterval(10*time.Millisecond))
+	require.NoError(t, pool.Register("slow", slow, workerpool.WithExecutorTimeout(20*time.Millisecond)))
+
+	require.NoError(t, pool.Start(t.Context()))
+	require.Eventually(t, func() bool {
+		stats := pool.Stats()
+
+		return len(stats.RecentErrors) > 0
+	}, time.Second, 5*time.Millisecond)
+	require.NoError(t, pool.Stop())
+
+	require.ErrorIs(t, pool.Stats().RecentErrors[0].Err, context.DeadlineExceeded)
+}
+
+func TestWorkerPool_RegisterValidation(t *testing.T) {
+	t.Parallel()
+
+	pool := workerpool.New(newMockExecutor(), workerpool.WithName("jobs"))
+
+	require.ErrorIs(t, pool.Register("cleanup", nil), workerpool.ErrNilExecutor)
+	require.NoError(t, pool.Register("cleanup", newMockExecutor()))
+	require.ErrorIs(t, pool.Register("cleanup", newMockExecutor()), workerpool.ErrDuplicateExecutor)
+	require.ErrorIs(t, pool.Register("jobs", newMockExecutor()), workerpool.ErrDuplicateExecutor)
+
+	require.NoError(t, pool.Start(t.Context()))
+
+	defer func() { _ = pool.Stop() }()
+
+	require.ErrorIs(t, pool.Register("late", newMockExecutor()), workerpool.ErrAlreadyRunning)
+}
@@ -0,0 +1,118 @@
+package workerpool
+
+import (
+	"context"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const maxJitter = 0.5
+
+// TickLocker claims a tick for a single replica, e.g. valkey.KeyLocker. TryAcquire must succeed for
+// exactly one caller per key until ttl expires.
+type TickLocker interface {
+	TryAcquire(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
+
+// WithJitter shifts every tick by a random offset within ±fraction of the tick interval, so replicas
+// of the same pool do not hit shared databases at the same instant. Ticks stay on a wall-clock aligned
+// grid, so jitter does not accumulate. fraction must be in (0, 0.5).
+func WithJitter(fraction float64) Option {
+	return func(pool *WorkerPool) {
+		if fraction > 0 && fraction < maxJitter {
+			pool.jitter = fraction
+		}
+	}
+}
+
+// WithTickLock makes only one replica execute each tick: the replica that claims the tick with locker
+// runs it and the others skip it. Ticks are aligned to the wall clock so that replicas agree on them.
+// If the locker fails, the tick runs anyway. Scheduled pools claim each activation the same way.
+func WithTickLock(locker TickLocker) Option {
+	return func(pool *WorkerPool) {
+		pool.tickLock = locker
+	}
+}
+
+// alignedDispatcher hands out a job at every multiple of the tick interval, shifted by jitter. It is
+// used instead of the plain ticker when jitter or a tick lock is configured.
+func (pool *WorkerPool) alignedDispatcher(ctx context.Context) {
+	defer pool.wg.Done()
+	defer close(pool.jobChan)
+
+	log.Info().Str("source", "gframework").Float64("jitter", pool.jitter).Msg("Dispatcher has started")
+
+	slot := pool.clock.Now().Truncate(pool.tickInterval)
+
+	for {
+		slot = slot.Add(pool.tickInterval)
+		fireAt := slot.Add(pool.jitterOffset())
+
+		timer := pool.clock.NewTimer(pool.clock.Until(fireAt))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			log.Info().Str("source", "gframework").Msg("Dispatcher is shutting down")
+
+			return
+		case <-timer.C():
+		}
+
+		if !pool.claimTick(ctx, slot) {
+			continue
+		}
+
+		if _, ok := pool.sendJobs(ctx, true); !ok {
+			log.Info().Str("source", "gframework").Msg("Dispatcher is shutting down")
+
+			return
+		}
+
+		// Skip slots that passed while all workers were busy instead of firing them back to back.
+		if now := pool.clock.Now(); now.After(slot.Add(pool.tickInterval)) {
+			missed := now.Sub(slot) / pool.tickInterval
+			slot = slot.Add(missed * pool.tickInterval)
+
+			for range int(missed) {
+				if pool.metrics != nil {
+					pool.metrics.TickSkipped(pool.name)
+				}
+			}
+		}
+	}
+}
+
+func (pool *WorkerPool) jitterOffset() time.Duration {
+	if pool.jitter == 0 {
+		return 0
+	}
+
+	//nolint:gosec // jitter does not need a cryptographic source
+	return time.Duration((rand.Float64()*2 - 1) * pool.jitter * float64(pool.tickInterval))
+}
+
+// claimTick reports whether this replica should run the tick at slot.
+func (pool *WorkerPool) claimTick(ctx context.Context, slot time.Time) bool {
+	if pool.tickLock == nil {
+		return true
+	}
+
+	key := pool.name + ":" + strconv.FormatInt(slot.Unix(), 10)
+
+	claimed, err := pool.tickLock.TryAcquire(ctx, key, 2*pool.tickInterval) //nolint:mnd
+	if err != nil {
+		log.Warn().Str("source", "gframework").Err(err).Str("pool_name", pool.name).Msg("Failed to claim tick, running it")
+
+		return true
+	}
+
+	if !claimed {
+		log.Debug().Str("source", "gframework").Str("pool_name", pool.name).Time("slot", slot).Msg("Tick claimed by another replica")
+	}
+
+	return claimed
+}
@@ -0,0 +1,94 @@
+package workerpool_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/workerpool"
+	"github.com/stretchr/testify/require"
+)
+
+var errLocker = errors.New("locker unavailable")
+
+type memoryTickLocker struct {
+	mu      sync.Mutex
+	claimed map[string]bool
+	err     error
+}
+
+func (l *memoryTickLocker) TryAcquire(_ context.Context, key string, _ time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.err != nil {
+		return false, l.err
+	}
+
+	if l.claimed[key] {
+		return false, nil
+	}
+
+	l.claimed[key] = true
+
+	return true, nil
+}
+
+func TestWorkerPool_TickLockRunsEachTickOnce(t *testing.T) {
+	t.Parallel()
+
+	locker := &memoryTickLocker{claimed: make(map[string]bool)}
+	first := newMockExecutor()
+	second := newMockExecutor()
+
+	pools := []*workerpool.WorkerPool{
+		workerpool.New(first, workerpool.WithName("sync"), workerpool.WithTickInterval(time.Second), workerpool.WithTickLock(locker)),
+		workerpool.New(second, workerpool.WithName("sync"), workerpool.WithTickInterval(time.Second), workerpool.WithTickLock(locker)),
+	}
+
+	for _, pool := range pools {
+		require.NoError(t, pool.Start(t.Context()))
+	}
+
+	time.Sleep(2500 * time.Millisecond)
+
+	for _, pool := range pools {
+		require.NoError(t, pool.Stop())
+	}
+
+	total := first.execCount.Load() + second.execCount.Load()
+	require.GreaterOrEqual(t, total, int32(2))
+	require.LessOrEqual(t, total, int32(3))
+}
+
+func TestWorkerPool_TickLockFailsOpen(t *testing.T) {
+	t.Parallel()
+
+	executor := newMockExecutor()
+	pool := workerpool.New(
+		executor,
+		workerpool.WithTickInterval(10*time.Millisecond),
+		workerpool.WithTickLock(&memoryTickLocker{claimed: nil, err: errLocker}),
+	)
+
+	require.NoError(t, pool.Start(t.Context()))
+	require.Eventually(t, func() bool { return executor.execCount.Load() > 0 }, time.Second, 5*time.Millisecond)
+	require.NoError(t, pool.Stop())
+}
+
+func TestWorkerPool_JitterKeepsTicking(t *testing.T) {
+	t.Parallel()
+
+	executor := newMockExecutor()
+	pool := workerpool.New(
+		executor,
+		workerpool.WithTickInterval(10*time.Millisecond),
+		workerpool.WithJitter(0.4),
+	)
+
+	require.NoError(t, pool.Start(t.Context()))
+	require.Eventually(t, func() bool { return executor.execCount.Load() >= 5 }, time.Second, 5*time.Millisecond)
+	require.NoError(t, pool.Stop())
+}
@@ -0,0 +1,88 @@
+package workerpool
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/andyle182810/gframework/metricserver"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics receives execution events of a pool, labeled by the pool name set with WithName.
+type Metrics interface {
+	ExecutionStarted(pool string)
+	ExecutionFinished(pool string, duration time.Duration, err error)
+	// TickSkipped is called for every tick or scheduled run that was dropped because all workers were busy.
+	TickSkipped(pool string)
+}
+
+func WithMetrics(m Metrics) Option {
+	return func(pool *WorkerPool) {
+		pool.metrics = m
+	}
+}
+
+// recordSkippedTicks reports the ticks the ticker dropped while the dispatcher waited for a free worker.
+func (pool *WorkerPool) recordSkippedTicks(tick time.Time) {
+	if pool.metrics == nil {
+		return
+	}
+
+	for range int(pool.clock.Since(tick) / pool.tickInterval) {
+		pool.metrics.TickSkipped(pool.name)
+	}
+}
+
+// PrometheusMetrics implements Metrics with Prometheus collectors. One instance can be shared by
+// several pools.
+type PrometheusMetrics struct {
+	executions   *prometheus.CounterVec
+	failures     *prometheus.CounterVec
+	inFlight     *prometheus.GaugeVec
+	duration     *prometheus.HistogramVec
+	skippedTicks *prometheus.CounterVec
+}
+
+// NewPrometheusMetrics registers the worker pool metrics with reg, e.g. the registerer of
+// metricserver.
+func NewPrometheusMetrics(reg prometheus.Registerer) (*PrometheusMetrics, error) {
+	const subsystem = "workerpool"
+
+	m := &PrometheusMetrics{
+		executions: metricserver.NewCounterVec(subsystem, "executions_total",
+			"Number of executor runs and submitted tasks.", "pool"),
+		failures: metricserver.NewCounterVec(subsystem, "failures_total",
+			"Number of executions that returned an error or panicked.", "pool"),
+		inFlight: metricserver.NewGaugeVec(subsystem, "in_flight",
+			"Number of executions currently running.", "pool"),
+		duration: metricserver.NewHistogramVec(subsystem, "execution_duration_seconds",
+			"Duration of executions.", metricserver.DurationBuckets(), "pool"),
+		skippedTicks: metricserver.NewCounterVec(subsystem, "skipped_ticks_total",
+			"Number of ticks or scheduled runs dropped because all workers were busy.", "pool"),
+	}
+
+	err := metricserver.Register(reg, m.executions, m.failures, m.inFlight, m.duration, m.skippedTicks)
+	if err != nil {
+		return nil, fmt.Errorf("workerpool: failed to register metrics: %w", err)
+	}
+
+	return m, nil
+}
+
+func (m *PrometheusMetrics) ExecutionStarted(pool string) {
+	m.inFlight.WithLabelValues(pool).Inc()
+}
+
+func (m *PrometheusMetrics) ExecutionFinished(pool string, duration time.Duration, err error) {
+	m.inFlight.WithLabelValues(pool).Dec()
+	m.executions.WithLabelValues(pool).Inc()
+	m.duration.WithLabelValues(pool).Observe(duration.Seconds())
+
+	if err != nil {
+		m.failures.WithLabelValues(pool).Inc()
+	}
+}
+
+func (m *PrometheusMetrics) TickSkipped(pool string) {
+	m.skippedTicks.WithLabelValues(pool).Inc()
+}
@@ -0,0 +1,87 @@
+package workerpool_test
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/workerpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusMetrics_RecordsExecutions(t *testing.T) {
+	t.Parallel()
+
+	reg := prometheus.NewRegistry()
+
+	metrics, err := workerpool.NewPrometheusMetrics(reg)
+	require.NoError(t, err)
+
+	pool := workerpool.New(nil, workerpool.WithName("mailer"), workerpool.WithMetrics(metrics))
+	require.NoError(t, pool.Start(t.Context()))
+
+	defer func() { _ = pool.Stop() }()
+
+	require.NoError(t, pool.Submit(t.Context(), func(context.Context) error { return nil }))
+	require.NoError(t, pool.Submit(t.Context(), func(context.Context) error { return errExecutor }))
+	require.Eventually(t, func() bool { return pool.Stats().Executions == 2 }, time.Second, 5*time.Millisecond)
+
+	expected := `
+# HELP gframework_workerpool_executions_total Number of executor runs and submitted tasks.
+# TYPE gframework_workerpool_executions_total counter
+gframework_workerpool_executions_total{pool="mailer"} 2
+# HELP gframework_workerpool_failures_total Number of executions that returned an error or panicked.
+# TYPE gframework_workerpool_failures_total counter
+gframework_workerpool_failures_total{pool="mailer"} 1
+# HELP gframework_workerpool_in_flight Number of executions currently running.
+# TYPE gframework_workerpool_in_flight gauge
+gframework_workerpool_in_flight{pool="mailer"} 0
+`
+
+	require.Eventually(t, func() bool {
+		return testutil.GatherAndCompare(reg, strings.NewReader(expected),
+			"gframework_workerpool_executions_total",
+			"gframework_workerpool_failures_total",
+			"gframework_workerpool_in_flight",
+		) == nil
+	}, time.Second, 5*time.Millisecond)
+
+	require.Equal(t, 1, testutil.CollectAndCount(reg, "gframework_workerpool_execution_duration_seconds"))
+}
+
+type countingMetrics struct {
+	skipped atomic.Int32
+}
+
+func (m *countingMetrics) ExecutionStarted(string) {}
+
+func (m *countingMetrics) ExecutionFinished(string, time.Duration, error) {}
+
+func (m *countingMetrics) TickSkipped(string) {
+	m.skipped.Add(1)
+}
+
+func TestWorkerPool_ReportsSkippedTicks(t *testing.T) {
+	t.Parallel()
+
+	metrics := &countingMetrics{}
+
+	executor := newMockExecutor()
+	executor.execDuration = 50 * time.Millisecond
+
+	pool := workerpool.New(
+		executor,
+		workerpool.WithName("slow"),
+		workerpool.WithTickInterval(5*time.Millisecond),
+		workerpool.WithMetrics(metrics),
+	)
+	require.NoError(t, pool.Start(t.Context()))
+
+	defer func() { _ = pool.Stop() }()
+
+	require.Eventually(t, func() bool { return metrics.skipped.Load() > 0 }, time.Second, 10*time.Millisecond)
+}
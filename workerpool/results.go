@@ -0,0 +1,61 @@
+package workerpool
+
+import (
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+const defaultResultBuffer = 100
+
+// ExecutionResult is the outcome of one execution. Name is the registered executor name, or the pool
+// name for submitted tasks.
+type ExecutionResult struct {
+	Name      string
+	StartedAt time.Time
+	Duration  time.Duration
+	Err       error
+	Panicked  bool
+}
+
+// WithResultBuffer sets how many results Results buffers before further results are dropped. It
+// defaults to 100.
+func WithResultBuffer(size int) Option {
+	return func(pool *WorkerPool) {
+		if size > 0 {
+			pool.resultBuffer = size
+		}
+	}
+}
+
+// Results returns the stream of execution results. Results are only published once Results has been
+// called, and are dropped when the buffer is full, so a slow consumer never blocks the workers. The
+// channel is never closed.
+func (pool *WorkerPool) Results() <-chan ExecutionResult {
+	pool.publishing.Store(true)
+
+	return pool.results
+}
+
+func (pool *WorkerPool) publishResult(name string, startedAt time.Time, err error, panicked bool) {
+	if !pool.publishing.Load() {
+		return
+	}
+
+	result := ExecutionResult{
+		Name:      name,
+		StartedAt: startedAt,
+		Duration:  pool.clock.Since(startedAt),
+		Err:       err,
+		Panicked:  panicked,
+	}
+
+	select {
+	case pool.results <- result:
+	default:
+		log.Warn().
+			Str("source", "gframework").
+			Str("executor_name", name).
+			Msg("Execution result dropped, consumer is too slow")
+	}
+}
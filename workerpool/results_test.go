@@ -0,0 +1,78 @@
+package workerpool_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/workerpool"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerPool_ResultsReportsExecutions(t *testing.T) {
+	t.Parallel()
+
+	errBoom := errors.New("boom")
+
+	executor := newMockExecutor()
+	executor.execErr = errBoom
+
+	pool := workerpool.New(executor, workerpool.WithName("reports"), workerpool.WithTickInterval(10*time.Millisecond))
+	results := pool.Results()
+
+	require.NoError(t, pool.Start(t.Context()))
+
+	defer func() { _ = pool.Stop() }()
+
+	select {
+	case result := <-results:
+		require.Equal(t, "reports", result.Name)
+		require.ErrorIs(t, result.Err, errBoom)
+		require.False(t, result.Panicked)
+		require.False(t, result.StartedAt.IsZero())
+	case <-time.After(time.Second):
+		t.Fatal("no execution result received")
+	}
+}
+
+func TestWorkerPool_ResultsReportsSubmittedPanics(t *testing.T) {
+	t.Parallel()
+
+	pool := workerpool.New(nil, workerpool.WithName("tasks"))
+	results := pool.Results()
+
+	require.NoError(t, pool.Start(t.Context()))
+
+	defer func() { _ = pool.Stop() }()
+
+	require.NoError(t, pool.Submit(t.Context(), func(context.Context) error {
+		panic("boom")
+	}))
+
+	select {
+	case result := <-results:
+		require.Equal(t, "tasks", result.Name)
+		require.ErrorIs(t, result.Err, workerpool.ErrTaskPanic)
+		require.True(t, result.Panicked)
+	case <-time.After(time.Second):
+		t.Fatal("no execution result received")
+	}
+}
+
+func TestWorkerPool_ResultsDropsWhenBufferIsFull(t *testing.T) {
+	t.Parallel()
+
+	executor := newMockExecutor()
+
+	pool := workerpool.New(executor, workerpool.WithTickInterval(5*time.Millisecond), workerpool.WithResultBuffer(1))
+	results := pool.Results()
+
+	require.NoError(t, pool.Start(t.Context()))
+	require.Eventually(t, func() bool {
+		return executor.execCount.Load() >= 3
+	}, time.Second, 5*time.Millisecond)
+	require.NoError(t, pool.Stop())
+
+	require.Len(t, results, 1)
+}
@@ -0,0 +1,161 @@
+package workerpool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// autoscaleTicks is the number of tick intervals between two autoscaler evaluations.
+const autoscaleTicks = 10
+
+var ErrInvalidWorkerCount = errors.New("worker pool worker count must be positive")
+
+type autoscaler struct {
+	minWorkers int
+	maxWorkers int
+}
+
+// WithAutoscale lets the pool adjust its worker count between minWorkers and maxWorkers. Every ten tick
+// intervals it sizes the pool to the number of workers needed to keep up with one execution per tick,
+// i.e. the average execution duration divided by the tick interval. It has no effect on scheduled pools.
+func WithAutoscale(minWorkers, maxWorkers int) Option {
+	return func(pool *WorkerPool) {
+		if minWorkers > 0 && maxWorkers >= minWorkers {
+			pool.autoscaler = &autoscaler{minWorkers: minWorkers, maxWorkers: maxWorkers}
+		}
+	}
+}
+
+// WorkerCount returns the configured number of workers.
+func (pool *WorkerPool) WorkerCount() int {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	return pool.workerCount
+}
+
+// Resize changes the number of workers at runtime. Removed workers finish their current execution
+// before exiting. On a stopped pool it sets the worker count used by the next Start.
+func (pool *WorkerPool) Resize(n int) error {
+	if n <= 0 {
+		return fmt.Errorf("%w: %d", ErrInvalidWorkerCount, n)
+	}
+
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	previous := pool.workerCount
+	pool.workerCount = n
+
+	if pool.workerCtx == nil {
+		return nil
+	}
+
+	for len(pool.workerQuits) < n {
+		pool.spawnWorker()
+	}
+
+	for len(pool.workerQuits) > n {
+		last := len(pool.workerQuits) - 1
+		close(pool.workerQuits[last])
+		pool.workerQuits = pool.workerQuits[:last]
+	}
+
+	if previous != n {
+		log.Info().
+			Str("source", "gframework").
+			Str("pool_name", pool.name).
+			Int("previous_worker_count", previous).
+			Int("worker_count", n).
+			Msg("Worker pool resized")
+	}
+
+	return nil
+}
+
+// ResizeHandler serves Resize for operational control: POST ?workers=<n> resizes the pool and responds
+// with the new worker count. Mount it on an internal admin route.
+func (pool *WorkerPool) ResizeHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		workers, err := strconv.Atoi(req.URL.Query().Get("workers"))
+		if err != nil {
+			http.Error(w, "invalid workers parameter", http.StatusBadRequest)
+
+			return
+		}
+
+		if err := pool.Resize(workers); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		_ = json.NewEncoder(w).Encode(map[string]any{"name": pool.name, "workers": workers})
+	})
+}
+
+// spawnWorker starts one more worker. The caller must hold pool.mu.
+func (pool *WorkerPool) spawnWorker() {
+	quit := make(chan struct{})
+	pool.workerQuits = append(pool.workerQuits, quit)
+
+	id := pool.nextWorkerID
+	pool.nextWorkerID++
+
+	pool.wg.Add(1)
+
+	go pool.worker(pool.workerCtx, id, quit)
+}
+
+func (pool *WorkerPool) recordDuration(startedAt time.Time) {
+	pool.execNanos.Add(int64(pool.clock.Since(startedAt)))
+	pool.execCount.Add(1)
+}
+
+func (pool *WorkerPool) autoscale(ctx context.Context) {
+	defer pool.wg.Done()
+
+	ticker := pool.clock.NewTicker(autoscaleTicks * pool.tickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			count := pool.execCount.Swap(0)
+			total := pool.execNanos.Swap(0)
+
+			if count == 0 {
+				continue
+			}
+
+			average := time.Duration(total / count)
+			if err := pool.Resize(pool.autoscaler.target(average, pool.tickInterval)); err != nil {
+				log.Error().Str("source", "gframework").Err(err).Msg("Worker pool autoscaling failed")
+			}
+		}
+	}
+}
+
+func (a *autoscaler) target(average, tickInterval time.Duration) int {
+	needed := int((average + tickInterval - 1) / tickInterval)
+
+	return min(max(needed, a.minWorkers), a.maxWorkers)
+}
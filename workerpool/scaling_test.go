@@ -0,0 +1,109 @@
+package workerpool_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/workerpool"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerPool_ResizeAddsAndRemovesWorkers(t *testing.T) {
+	t.Parallel()
+
+	pool := workerpool.New(nil, workerpool.WithWorkerCount(1))
+	require.NoError(t, pool.Start(t.Context()))
+
+	defer func() { _ = pool.Stop() }()
+
+	var active atomic.Int32
+
+	release := make(chan struct{})
+
+	for range 3 {
+		require.NoError(t, pool.Submit(t.Context(), func(ctx context.Context) error {
+			active.Add(1)
+			defer active.Add(-1)
+
+			select {
+			case <-release:
+			case <-ctx.Done():
+			}
+
+			return nil
+		}))
+	}
+
+	require.Eventually(t, func() bool { return active.Load() == 1 }, time.Second, 5*time.Millisecond)
+
+	require.NoError(t, pool.Resize(3))
+	require.Equal(t, 3, pool.WorkerCount())
+	require.Eventually(t, func() bool { return active.Load() == 3 }, time.Second, 5*time.Millisecond)
+
+	require.NoError(t, pool.Resize(1))
+	close(release)
+
+	require.Eventually(t, func() bool { return active.Load() == 0 }, time.Second, 5*time.Millisecond)
+	require.ErrorIs(t, pool.Resize(0), workerpool.ErrInvalidWorkerCount)
+}
+
+func TestWorkerPool_ResizeStoppedPool(t *testing.T) {
+	t.Parallel()
+
+	pool := workerpool.New(newMockExecutor())
+
+	require.NoError(t, pool.Resize(4))
+	require.Equal(t, 4, pool.WorkerCount())
+}
+
+func TestWorkerPool_AutoscaleGrowsSlowPool(t *testing.T) {
+	t.Parallel()
+
+	executor := newMockExecutor()
+	executor.execDuration = 25 * time.Millisecond
+
+	pool := workerpool.New(
+		executor,
+		workerpool.WithTickInterval(5*time.Millisecond),
+		workerpool.WithAutoscale(1, 8),
+	)
+	require.NoError(t, pool.Start(t.Context()))
+
+	defer func() { _ = pool.Stop() }()
+
+	require.Eventually(t, func() bool { return pool.WorkerCount() > 1 }, 2*time.Second, 10*time.Millisecond)
+	require.LessOrEqual(t, pool.WorkerCount(), 8)
+}
+
+func TestWorkerPool_ResizeHandler(t *testing.T) {
+	t.Parallel()
+
+	pool := workerpool.New(newMockExecutor())
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		status int
+	}{
+		{name: "resizes", method: http.MethodPost, target: "/admin/workers?workers=3", status: http.StatusOK},
+		{name: "invalid count", method: http.MethodPost, target: "/admin/workers?workers=0", status: http.StatusBadRequest},
+		{name: "not a number", method: http.MethodPost, target: "/admin/workers?workers=many", status: http.StatusBadRequest},
+		{name: "wrong method", method: http.MethodGet, target: "/admin/workers?workers=3", status: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequestWithContext(t.Context(), tt.method, tt.target, nil)
+		rec := httptest.NewRecorder()
+
+		pool.ResizeHandler().ServeHTTP(rec, req)
+
+		require.Equal(t, tt.status, rec.Code, tt.name)
+	}
+
+	require.Equal(t, 3, pool.WorkerCount())
+}
@@ -0,0 +1,214 @@
+package workerpool
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxScheduleSearch bounds the search for the next activation, e.g. for "0 0 30 2 *" which never fires.
+const maxScheduleSearch = 5 * 366 * 24 * time.Hour
+
+var ErrInvalidSchedule = errors.New("worker pool schedule is invalid")
+
+// OverlapPolicy decides what happens when a scheduled run is due while every worker is still busy.
+type OverlapPolicy int
+
+const (
+	// OverlapSkip drops the run and waits for the next activation.
+	OverlapSkip OverlapPolicy = iota
+	// OverlapQueue keeps a single pending run that starts as soon as a worker is free.
+	OverlapQueue
+)
+
+type scheduleField struct {
+	min, max int
+	names    map[string]int
+}
+
+//nolint:gochecknoglobals
+var (
+	minuteField = scheduleField{min: 0, max: 59, names: nil}
+	hourField   = scheduleField{min: 0, max: 23, names: nil}
+	domField    = scheduleField{min: 1, max: 31, names: nil}
+	monthField  = scheduleField{min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dowField = scheduleField{min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+	scheduleDescriptors = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+)
+
+// Schedule is a parsed standard five-field cron expression: minute, hour, day of month, month and day
+// of week.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool
+}
+
+// ParseSchedule parses a cron expression such as "*/5 * * * *" or "0 9 * * mon-fri". Lists, ranges,
+// steps, month and weekday names and the @hourly/@daily/@weekly/@monthly/@yearly descriptors are
+// supported.
+func ParseSchedule(expr string) (*Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if descriptor, ok := scheduleDescriptors[strings.ToLower(expr)]; ok {
+		expr = descriptor
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 { //nolint:mnd
+		return nil, fmt.Errorf("%w: %q: expected 5 fields, got %d", ErrInvalidSchedule, expr, len(fields))
+	}
+
+	specs := []scheduleField{minuteField, hourField, domField, monthField, dowField}
+	bits := make([]uint64, len(fields))
+
+	for i, field := range fields {
+		parsed, err := parseScheduleField(field, specs[i])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %w", ErrInvalidSchedule, expr, err)
+		}
+
+		bits[i] = parsed
+	}
+
+	// Both 0 and 7 mean Sunday.
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1
+	}
+
+	return &Schedule{
+		minute:  bits[0],
+		hour:    bits[1],
+		dom:     bits[2],
+		month:   bits[3],
+		dow:     bits[4],
+		domStar: fields[2] == "*" || fields[2] == "?",
+		dowStar: fields[4] == "*" || fields[4] == "?",
+	}, nil
+}
+
+// Next returns the first activation strictly after t, in t's location, or the zero time when the
+// schedule never fires.
+func (s *Schedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(maxScheduleSearch)
+
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Truncate(time.Minute).Add(time.Minute)
+		default:
+			return t
+		}
+	}
+
+	return time.Time{}
+}
+
+// dayMatches applies cron's day rule: when both day of month and day of week are restricted, either
+// one matching is enough.
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+
+	return domMatch || dowMatch
+}
+
+func parseScheduleField(field string, spec scheduleField) (uint64, error) {
+	var bits uint64
+
+	for part := range strings.SplitSeq(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+
+		if hasStep {
+			parsed, err := strconv.Atoi(stepPart)
+			if err != nil || parsed <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepPart)
+			}
+
+			step = parsed
+		}
+
+		low, high, err := parseScheduleRange(rangePart, spec, hasStep)
+		if err != nil {
+			return 0, err
+		}
+
+		for value := low; value <= high; value += step {
+			bits |= 1 << uint(value)
+		}
+	}
+
+	return bits, nil
+}
+
+func parseScheduleRange(part string, spec scheduleField, hasStep bool) (int, int, error) {
+	if part == "*" || part == "?" {
+		return spec.min, spec.max, nil
+	}
+
+	lowPart, highPart, isRange := strings.Cut(part, "-")
+
+	low, err := parseScheduleValue(lowPart, spec)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	high := low
+
+	switch {
+	case isRange:
+		high, err = parseScheduleValue(highPart, spec)
+		if err != nil {
+			return 0, 0, err
+		}
+	case hasStep:
+		// "5/15" means every 15 starting at 5.
+		high = spec.max
+	}
+
+	if low > high {
+		return 0, 0, fmt.Errorf("invalid range %q", part)
+	}
+
+	return low, high, nil
+}
+
+func parseScheduleValue(value string, spec scheduleField) (int, error) {
+	if named, ok := spec.names[strings.ToLower(value)]; ok {
+		return named, nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < spec.min || parsed > spec.max {
+		return 0, fmt.Errorf("value %q out of range %d-%d", value, spec.min, spec.max)
+	}
+
+	return parsed, nil
+}
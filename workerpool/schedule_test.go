@@ -0,0 +1,82 @@
+package workerpool_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/workerpool"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedule_Next(t *testing.T) {
+	t.Parallel()
+
+	from := time.Date(2025, time.March, 14, 10, 7, 30, 0, time.UTC) // Friday
+
+	tests := []struct {
+		name     string
+		expr     string
+		expected time.Time
+	}{
+		{name: "every five minutes", expr: "*/5 * * * *", expected: time.Date(2025, time.March, 14, 10, 10, 0, 0, time.UTC)},
+		{name: "every minute", expr: "* * * * *", expected: time.Date(2025, time.March, 14, 10, 8, 0, 0, time.UTC)},
+		{name: "hourly descriptor", expr: "@hourly", expected: time.Date(2025, time.March, 14, 11, 0, 0, 0, time.UTC)},
+		{name: "daily at nine", expr: "0 9 * * *", expected: time.Date(2025, time.March, 15, 9, 0, 0, 0, time.UTC)},
+		{name: "weekdays by name", expr: "30 8 * * mon-fri", expected: time.Date(2025, time.March, 17, 8, 30, 0, 0, time.UTC)},
+		{name: "sunday as seven", expr: "0 0 * * 7", expected: time.Date(2025, time.March, 16, 0, 0, 0, 0, time.UTC)},
+		{name: "list and range", expr: "0,30 10-11 * * *", expected: time.Date(2025, time.March, 14, 10, 30, 0, 0, time.UTC)},
+		{name: "month by name", expr: "0 0 1 jun *", expected: time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "day of month or week", expr: "0 0 1 * mon", expected: time.Date(2025, time.March, 17, 0, 0, 0, 0, time.UTC)},
+		{name: "step from offset", expr: "5/20 * * * *", expected: time.Date(2025, time.March, 14, 10, 25, 0, 0, time.UTC)},
+		{name: "leap day", expr: "0 0 29 2 *", expected: time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			schedule, err := workerpool.ParseSchedule(tt.expr)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, schedule.Next(from))
+		})
+	}
+}
+
+func TestSchedule_NextUsesLocation(t *testing.T) {
+	t.Parallel()
+
+	loc := time.FixedZone("UTC+7", 7*60*60)
+	from := time.Date(2025, time.March, 14, 10, 0, 0, 0, time.UTC)
+
+	schedule, err := workerpool.ParseSchedule("0 9 * * *")
+	require.NoError(t, err)
+
+	next := schedule.Next(from.In(loc))
+	require.Equal(t, time.Date(2025, time.March, 15, 9, 0, 0, 0, loc), next)
+	require.Equal(t, time.Date(2025, time.March, 15, 2, 0, 0, 0, time.UTC), next.UTC())
+}
+
+func TestSchedule_NeverFires(t *testing.T) {
+	t.Parallel()
+
+	schedule, err := workerpool.ParseSchedule("0 0 30 2 *")
+	require.NoError(t, err)
+	require.True(t, schedule.Next(time.Now()).IsZero())
+}
+
+func TestParseSchedule_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "*/0 * * * *", "5-1 * * * *", "* * * foo *"} {
+		_, err := workerpool.ParseSchedule(expr)
+		require.ErrorIs(t, err, workerpool.ErrInvalidSchedule, expr)
+	}
+}
+
+func TestWorkerPool_InvalidScheduleFailsStart(t *testing.T) {
+	t.Parallel()
+
+	pool := workerpool.New(newMockExecutor(), workerpool.WithSchedule("not a cron"))
+
+	require.ErrorIs(t, pool.Start(t.Context()), workerpool.ErrInvalidSchedule)
+}
@@ -0,0 +1,88 @@
+package workerpool
+
+import (
+	"time"
+)
+
+const defaultErrorHistory = 10
+
+// ErrorHandler is called for every failed execution of the executor or a submitted task. panicked
+// reports whether the error was recovered from a panic.
+type ErrorHandler func(err error, panicked bool)
+
+type ErrorRecord struct {
+	Err      error
+	Panicked bool
+	Time     time.Time
+}
+
+// Stats is a snapshot of the pool's execution counters. RecentErrors holds the most recent errors,
+// oldest first.
+type Stats struct {
+	Executions   uint64
+	Failures     uint64
+	Panics       uint64
+	InFlight     int
+	QueueLength  int
+	Paused       bool
+	RecentErrors []ErrorRecord
+}
+
+// WithErrorHandler registers handler to be called for every failed execution, e.g. to report it to
+// an error tracker.
+func WithErrorHandler(handler ErrorHandler) Option {
+	return func(pool *WorkerPool) {
+		pool.errorHandler = handler
+	}
+}
+
+// WithErrorHistory sets how many recent errors Stats keeps. It defaults to 10.
+func WithErrorHistory(size int) Option {
+	return func(pool *WorkerPool) {
+		if size > 0 {
+			pool.errorHistory = size
+		}
+	}
+}
+
+func (pool *WorkerPool) Stats() Stats {
+	pool.statsMu.Lock()
+	defer pool.statsMu.Unlock()
+
+	return Stats{
+		Executions:   pool.executions.Load(),
+		Failures:     pool.failures.Load(),
+		Panics:       pool.panics.Load(),
+		InFlight:     int(pool.inFlight.Load()),
+		QueueLength:  len(pool.taskQueue),
+		Paused:       pool.paused.Load(),
+		RecentErrors: append([]ErrorRecord(nil), pool.recentErrors...),
+	}
+}
+
+func (pool *WorkerPool) recordResult(err error, panicked bool) {
+	pool.executions.Add(1)
+
+	if err == nil {
+		return
+	}
+
+	pool.failures.Add(1)
+
+	if panicked {
+		pool.panics.Add(1)
+	}
+
+	pool.statsMu.Lock()
+
+	pool.recentErrors = append(pool.recentErrors, ErrorRecord{Err: err, Panicked: panicked, Time: pool.clock.Now()})
+	if overflow := len(pool.recentErrors) - pool.errorHistory; overflow > 0 {
+		pool.recentErrors = append(pool.recentErrors[:0], pool.recentErrors[overflow:]...)
+	}
+
+	pool.statsMu.Unlock()
+
+	if pool.errorHandler != nil {
+		pool.errorHandler(err, panicked)
+	}
+}
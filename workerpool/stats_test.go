@@ -0,0 +1,76 @@
+package workerpool_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andyle182810/gframework/workerpool"
+	"github.com/stretchr/testify/require"
+)
+
+type panickingExecutor struct{}
+
+func (panickingExecutor) Execute(context.Context) error {
+	panic("executor exploded")
+}
+
+func TestWorkerPool_RecoversExecutorPanics(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		panicked []bool
+	)
+
+	pool := workerpool.New(
+		panickingExecutor{},
+		workerpool.WithTickInterval(10*time.Millisecond),
+		workerpool.WithErrorHandler(func(err error, wasPanic bool) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			require.ErrorIs(t, err, workerpool.ErrTaskPanic)
+
+			panicked = append(panicked, wasPanic)
+		}),
+	)
+
+	require.NoError(t, pool.Start(t.Context()))
+	require.Eventually(t, func() bool { return pool.Stats().Panics >= 2 }, time.Second, 5*time.Millisecond)
+	require.NoError(t, pool.Stop())
+
+	stats := pool.Stats()
+	require.Equal(t, stats.Executions, stats.Failures)
+	require.Equal(t, stats.Failures, stats.Panics)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	require.NotEmpty(t, panicked)
+	require.NotContains(t, panicked, false)
+}
+
+func TestWorkerPool_StatsKeepsRecentErrors(t *testing.T) {
+	t.Parallel()
+
+	pool := workerpool.New(nil, workerpool.WithWorkerCount(1), workerpool.WithErrorHistory(2))
+	require.NoError(t, pool.Start(t.Context()))
+
+	defer func() { _ = pool.Stop() }()
+
+	for range 3 {
+		require.NoError(t, pool.Submit(t.Context(), func(context.Context) error { return errExecutor }))
+	}
+
+	require.NoError(t, pool.Submit(t.Context(), func(context.Context) error { return nil }))
+	require.Eventually(t, func() bool { return pool.Stats().Executions == 4 }, time.Second, 5*time.Millisecond)
+
+	stats := pool.Stats()
+	require.Equal(t, uint64(3), stats.Failures)
+	require.Equal(t, uint64(0), stats.Panics)
+	require.Len(t, stats.RecentErrors, 2)
+	require.ErrorIs(t, stats.RecentErrors[1].Err, errExecutor)
+	require.False(t, stats.RecentErrors[1].Panicked)
+}
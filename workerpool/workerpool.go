@@ -19,35 +19,106 @@
 //
 // Each worker runs independently; if one times out or fails, others continue executing.
 // The pool prevents concurrent overlapping executions of the same worker (each waits for the previous to finish).
+//
+// WithSchedule replaces the tick interval with a cron expression evaluated in the WithTimezone location;
+// WithOverlapPolicy decides whether a run that is due while all workers are busy is skipped or queued.
+//
+// Panics in the executor or submitted tasks are recovered and reported as errors wrapping ErrTaskPanic to
+// the WithErrorHandler callback; Stats returns execution counters and the most recent errors.
+//
+// WithMetrics reports executions, failures, in-flight work, durations and skipped ticks per pool name, e.g.
+// to Prometheus via NewPrometheusMetrics.
+//
+// Register adds further named executors to a pool; every tick fans out to all of them, each with its own
+// timeout and concurrency weight.
+//
+// WithJitter spreads ticks of replicas apart and WithTickLock lets only one replica run each tick.
+//
+// Results streams the outcome of every execution, e.g. to alert on failures or chain follow-up work.
+//
+// Pause, Resume and Drain halt and drain a running pool without stopping it, e.g. during incident response.
+//
+// Ad-hoc work, e.g. from HTTP handlers, can be submitted to the same workers with Submit. Submissions are
+// buffered in a bounded queue (see WithQueueSize) and rejected with ErrQueueFull when it is full. Pass a nil
+// Executor to use the pool for submitted tasks only.
 package workerpool
 
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/andyle182810/gframework/clock"
 	"github.com/rs/zerolog/log"
 )
 
-var ErrAlreadyRunning = errors.New("worker pool is already running")
+const defaultQueueSize = 100
+
+var (
+	ErrAlreadyRunning = errors.New("worker pool is already running")
+	ErrNotRunning     = errors.New("worker pool is not running")
+	ErrQueueFull      = errors.New("worker pool queue is full")
+	ErrNilTask        = errors.New("worker pool task is nil")
+	ErrTaskPanic      = errors.New("worker pool execution panicked")
+)
 
 type Executor interface {
 	Execute(ctx context.Context) error
 }
 
+// Task is a unit of work submitted with Submit.
+type Task func(ctx context.Context) error
+
+type submittedTask struct {
+	ctx  context.Context //nolint:containedctx
+	task Task
+}
+
 type WorkerPool struct {
 	name         string
-	executor     Executor
+	executors    []*registeredExecutor
 	workerCount  int
 	tickInterval time.Duration
 	execTimeout  time.Duration
-	jobChan      chan struct{}
+	queueSize    int
+	schedule     *Schedule
+	scheduleErr  error
+	location     *time.Location
+	overlap      OverlapPolicy
+	errorHandler ErrorHandler
+	errorHistory int
+	executions   atomic.Uint64
+	failures     atomic.Uint64
+	panics       atomic.Uint64
+	statsMu      sync.Mutex
+	recentErrors []ErrorRecord
+	jobChan      chan *registeredExecutor
+	taskQueue    chan submittedTask
 	cancel       context.CancelFunc
 	wg           sync.WaitGroup
 	mu           sync.Mutex
 	running      atomic.Bool
+	workerCtx    context.Context //nolint:containedctx
+	workerQuits  []chan struct{}
+	nextWorkerID int
+	autoscaler   *autoscaler
+	metrics      Metrics
+	jitter       float64
+	tickLock     TickLocker
+	inFlight     atomic.Int64
+	pending      atomic.Int64
+	paused       atomic.Bool
+	pauseMu      sync.Mutex
+	resumed      chan struct{}
+	results      chan ExecutionResult
+	resultBuffer int
+	publishing   atomic.Bool
+	execNanos    atomic.Int64
+	execCount    atomic.Int64
+	clock        clock.Clock
 }
 
 type Option func(*WorkerPool)
@@ -55,20 +126,46 @@ type Option func(*WorkerPool)
 func New(executor Executor, opts ...Option) *WorkerPool {
 	pool := &WorkerPool{ //nolint:exhaustruct
 		name:         "worker-pool",
-		executor:     executor,
+		executors:    nil,
 		workerCount:  1,
 		tickInterval: time.Second,
 		execTimeout:  0,
+		queueSize:    defaultQueueSize,
+		schedule:     nil,
+		scheduleErr:  nil,
+		location:     time.Local,
+		overlap:      OverlapSkip,
+		errorHandler: nil,
+		errorHistory: defaultErrorHistory,
+		recentErrors: nil,
 		jobChan:      nil,
+		taskQueue:    nil,
 		cancel:       nil,
 		wg:           sync.WaitGroup{},
 		mu:           sync.Mutex{},
+		workerCtx:    nil,
+		workerQuits:  nil,
+		autoscaler:   nil,
+		metrics:      nil,
+		jitter:       0,
+		tickLock:     nil,
+		resumed:      nil,
+		results:      nil,
+		resultBuffer: defaultResultBuffer,
+		clock:        clock.Real(),
 	}
 
 	for _, opt := range opts {
 		opt(pool)
 	}
 
+	pool.taskQueue = make(chan submittedTask, pool.queueSize)
+	pool.results = make(chan ExecutionResult, pool.resultBuffer)
+
+	if executor != nil {
+		pool.executors = append(pool.executors, newRegisteredExecutor(pool.name, executor))
+	}
+
 	return pool
 }
 
@@ -96,6 +193,50 @@ func WithExecutionTimeout(timeout time.Duration) Option {
 	}
 }
 
+// WithSchedule runs the executor on a cron schedule such as "*/5 * * * *" instead of every tick interval,
+// so runs align to wall-clock times and do not drift. An invalid expression makes Start fail.
+func WithSchedule(expr string) Option {
+	return func(pool *WorkerPool) {
+		pool.schedule, pool.scheduleErr = ParseSchedule(expr)
+	}
+}
+
+// WithTimezone sets the location the schedule is evaluated in. It defaults to time.Local.
+func WithTimezone(loc *time.Location) Option {
+	return func(pool *WorkerPool) {
+		if loc != nil {
+			pool.location = loc
+		}
+	}
+}
+
+// WithOverlapPolicy sets what happens when a scheduled run is due while all workers are busy. It
+// defaults to OverlapSkip.
+func WithOverlapPolicy(policy OverlapPolicy) Option {
+	return func(pool *WorkerPool) {
+		pool.overlap = policy
+	}
+}
+
+// WithQueueSize bounds the number of submitted tasks waiting for a worker.
+func WithQueueSize(size int) Option {
+	return func(pool *WorkerPool) {
+		if size > 0 {
+			pool.queueSize = size
+		}
+	}
+}
+
+// WithClock sets the clock driving ticks, schedules and execution timing. It defaults to clock.Real;
+// tests pass a fake clock to fire ticks without waiting.
+func WithClock(clk clock.Clock) Option {
+	return func(pool *WorkerPool) {
+		if clk != nil {
+			pool.clock = clk
+		}
+	}
+}
+
 func WithName(name string) Option {
 	return func(pool *WorkerPool) {
 		if name != "" {
@@ -109,16 +250,21 @@ func (pool *WorkerPool) Name() string {
 }
 
 func (pool *WorkerPool) Start(ctx context.Context) error {
+	if pool.scheduleErr != nil {
+		return pool.scheduleErr
+	}
+
 	if !pool.running.CompareAndSwap(false, true) {
 		return ErrAlreadyRunning
 	}
 
 	pool.mu.Lock()
-	pool.jobChan = make(chan struct{})
+	pool.jobChan = make(chan *registeredExecutor)
 
 	workerCtx, cancel := context.WithCancel(ctx)
 	pool.cancel = cancel
-	pool.mu.Unlock()
+	pool.workerCtx = workerCtx
+	pool.workerQuits = nil
 
 	log.Info().
 		Str("source", "gframework").
@@ -127,19 +273,75 @@ func (pool *WorkerPool) Start(ctx context.Context) error {
 		Dur("exec_timeout", pool.execTimeout).
 		Msg("Worker pool is starting")
 
-	for workerID := range pool.workerCount {
+	for range pool.workerCount {
+		pool.spawnWorker()
+	}
+	pool.mu.Unlock()
+
+	if pool.autoscaler != nil && len(pool.executors) > 0 && pool.schedule == nil {
 		pool.wg.Add(1)
 
-		go pool.worker(workerCtx, workerID)
+		go pool.autoscale(workerCtx)
 	}
 
-	pool.wg.Add(1)
+	switch {
+	case len(pool.executors) == 0:
+	case pool.schedule != nil:
+		pool.wg.Add(1)
+
+		go pool.scheduledDispatcher(workerCtx)
+	case pool.jitter > 0 || pool.tickLock != nil:
+		pool.wg.Add(1)
+
+		go pool.alignedDispatcher(workerCtx)
+	default:
+		pool.wg.Add(1)
 
-	go pool.dispatcher(workerCtx)
+		go pool.dispatcher(workerCtx)
+	}
 
 	return nil
 }
 
+// Submit queues task to run on one of the pool's workers and returns without waiting for it. The task
+// receives a context that keeps the values of ctx but is only cancelled when the pool stops or the
+// execution timeout is hit, so it may outlive the request that submitted it. Submit returns
+// ErrQueueFull when the queue is at capacity, letting callers apply backpressure, e.g. with a 503.
+func (pool *WorkerPool) Submit(ctx context.Context, task Task) error {
+	if task == nil {
+		return ErrNilTask
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Holding pool.mu keeps Stop from draining the queue between the running check and the enqueue,
+	// which would strand the task.
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	if !pool.running.Load() {
+		return ErrNotRunning
+	}
+
+	pool.pending.Add(1)
+
+	select {
+	case pool.taskQueue <- submittedTask{ctx: context.WithoutCancel(ctx), task: task}:
+		return nil
+	default:
+		pool.pending.Add(-1)
+
+		return ErrQueueFull
+	}
+}
+
+// QueueLength returns the number of submitted tasks waiting for a worker.
+func (pool *WorkerPool) QueueLength() int {
+	return len(pool.taskQueue)
+}
+
 func (pool *WorkerPool) Stop() error {
 	if !pool.running.CompareAndSwap(true, false) {
 		return nil
@@ -147,12 +349,21 @@ func (pool *WorkerPool) Stop() error {
 
 	log.Info().Str("source", "gframework").Msg("Worker pool is stopping")
 
+	pool.mu.Lock()
 	if pool.cancel != nil {
 		pool.cancel()
 	}
 
+	pool.workerCtx = nil
+	pool.workerQuits = nil
+	pool.mu.Unlock()
+
 	pool.wg.Wait()
 
+	if dropped := pool.drainQueue(); dropped > 0 {
+		log.Warn().Str("source", "gframework").Int("dropped_tasks", dropped).Msg("Worker pool dropped queued tasks")
+	}
+
 	log.Info().Str("source", "gframework").Msg("Worker pool has stopped")
 
 	return nil
@@ -161,7 +372,7 @@ func (pool *WorkerPool) Stop() error {
 func (pool *WorkerPool) dispatcher(ctx context.Context) {
 	defer pool.wg.Done()
 
-	ticker := time.NewTicker(pool.tickInterval)
+	ticker := pool.clock.NewTicker(pool.tickInterval)
 	defer ticker.Stop()
 
 	log.Info().Str("source", "gframework").Msg("Dispatcher has started")
@@ -174,21 +385,80 @@ func (pool *WorkerPool) dispatcher(ctx context.Context) {
 			log.Info().Str("source", "gframework").Msg("Dispatcher is shutting down")
 
 			return
-		case <-ticker.C:
-			select {
-			case pool.jobChan <- struct{}{}:
-			case <-ctx.Done():
+		case tick := <-ticker.C():
+			if _, ok := pool.sendJobs(ctx, true); !ok {
 				close(pool.jobChan)
 
 				log.Info().Str("source", "gframework").Msg("Dispatcher is shutting down")
 
 				return
 			}
+
+			pool.recordSkippedTicks(tick)
+		}
+	}
+}
+
+// scheduledDispatcher hands out a job at every activation of the schedule.
+func (pool *WorkerPool) scheduledDispatcher(ctx context.Context) {
+	defer pool.wg.Done()
+	defer close(pool.jobChan)
+
+	log.Info().Str("source", "gframework").Str("location", pool.location.String()).Msg("Scheduled dispatcher has started")
+
+	for {
+		next := pool.schedule.Next(pool.clock.Now().In(pool.location))
+		if next.IsZero() {
+			log.Warn().Str("source", "gframework").Msg("Schedule has no future activations, dispatcher is stopping")
+
+			<-ctx.Done()
+
+			return
+		}
+
+		timer := pool.clock.NewTimer(pool.clock.Until(next))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			log.Info().Str("source", "gframework").Msg("Dispatcher is shutting down")
+
+			return
+		case <-timer.C():
+		}
+
+		if !pool.claimTick(ctx, next) {
+			continue
+		}
+
+		if !pool.dispatch(ctx, next) {
+			log.Info().Str("source", "gframework").Msg("Dispatcher is shutting down")
+
+			return
+		}
+	}
+}
+
+// dispatch hands a scheduled run to a worker according to the overlap policy. It returns false when
+// ctx is done.
+func (pool *WorkerPool) dispatch(ctx context.Context, scheduledAt time.Time) bool {
+	skipped, ok := pool.sendJobs(ctx, pool.overlap == OverlapQueue)
+	if skipped > 0 {
+		log.Warn().
+			Str("source", "gframework").
+			Time("scheduled_at", scheduledAt).
+			Int("skipped_executions", skipped).
+			Msg("All workers are busy, skipping scheduled run")
+
+		if pool.metrics != nil {
+			pool.metrics.TickSkipped(pool.name)
 		}
 	}
+
+	return ok
 }
 
-func (pool *WorkerPool) worker(ctx context.Context, id int) {
+func (pool *WorkerPool) worker(ctx context.Context, id int, quit <-chan struct{}) {
 	defer pool.wg.Done()
 
 	log.Info().
@@ -198,6 +468,13 @@ func (pool *WorkerPool) worker(ctx context.Context, id int) {
 
 	for {
 		select {
+		case <-quit:
+			log.Info().
+				Str("source", "gframework").
+				Int("worker_id", id).
+				Msg("Worker is removed by resize")
+
+			return
 		case <-ctx.Done():
 			log.Info().
 				Str("source", "gframework").
@@ -205,7 +482,7 @@ func (pool *WorkerPool) worker(ctx context.Context, id int) {
 				Msg("Worker is shutting down")
 
 			return
-		case _, ok := <-pool.jobChan:
+		case job, ok := <-pool.jobChan:
 			if !ok {
 				log.Info().
 					Str("source", "gframework").
@@ -215,36 +492,136 @@ func (pool *WorkerPool) worker(ctx context.Context, id int) {
 				return
 			}
 
-			pool.executeWithTimeout(ctx, id)
+			pool.executeWithTimeout(ctx, id, job)
+		case submitted := <-pool.taskQueue:
+			if pool.waitResumed(ctx, quit) {
+				pool.runTask(ctx, id, submitted)
+			} else if ctx.Err() == nil && pool.requeue(ctx, submitted) {
+				log.Info().
+					Str("source", "gframework").
+					Int("worker_id", id).
+					Msg("Worker is removed by resize")
+
+				return
+			}
+
+			pool.pending.Add(-1)
 		}
 	}
 }
 
-func (pool *WorkerPool) executeWithTimeout(ctx context.Context, workerID int) {
-	var execCtx context.Context
+func (pool *WorkerPool) runTask(ctx context.Context, workerID int, submitted submittedTask) {
+	taskCtx, cancel := pool.execContext(submitted.ctx, pool.execTimeout)
+	defer cancel()
+
+	stop := context.AfterFunc(ctx, cancel)
+	defer stop()
+
+	if err := pool.safeRun(taskCtx, pool.name, submitted.task); err != nil {
+		log.Error().
+			Str("source", "gframework").
+			Err(err).
+			Int("worker_id", workerID).
+			Msg("Submitted task failed")
+	}
+}
+
+// requeue hands back a task taken by a worker that was removed while the pool is paused, so that the
+// remaining workers run it on Resume. It returns false when ctx is done first and the task is dropped.
+func (pool *WorkerPool) requeue(ctx context.Context, submitted submittedTask) bool {
+	select {
+	case pool.taskQueue <- submitted:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (pool *WorkerPool) drainQueue() int {
+	dropped := 0
+
+	for {
+		select {
+		case <-pool.taskQueue:
+			pool.pending.Add(-1)
 
-	var cancel context.CancelFunc
+			dropped++
+		default:
+			return dropped
+		}
+	}
+}
 
-	if pool.execTimeout > 0 {
-		execCtx, cancel = context.WithTimeout(ctx, pool.execTimeout)
-	} else {
-		execCtx, cancel = context.WithCancel(ctx)
+func (pool *WorkerPool) execContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
 	}
 
+	return context.WithCancel(ctx)
+}
+
+// safeRun runs task, converting a panic into an error, and records the outcome in the pool's stats and
+// result stream under name.
+func (pool *WorkerPool) safeRun(ctx context.Context, name string, task Task) (err error) {
+	panicked := false
+	startedAt := pool.clock.Now()
+
+	pool.inFlight.Add(1)
+	defer pool.inFlight.Add(-1)
+
+	if pool.metrics != nil {
+		pool.metrics.ExecutionStarted(pool.name)
+	}
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			panicked = true
+			err = fmt.Errorf("%w: %v", ErrTaskPanic, rec)
+		}
+
+		pool.recordResult(err, panicked)
+		pool.publishResult(name, startedAt, err, panicked)
+
+		if pool.metrics != nil {
+			pool.metrics.ExecutionFinished(pool.name, pool.clock.Since(startedAt), err)
+		}
+	}()
+
+	return task(ctx)
+}
+
+func (pool *WorkerPool) executeWithTimeout(ctx context.Context, workerID int, job *registeredExecutor) {
+	if pool.paused.Load() {
+		log.Debug().Str("source", "gframework").Int("worker_id", workerID).Msg("Worker pool is paused, skipping tick")
+
+		return
+	}
+
+	timeout := job.timeout
+	if timeout == 0 {
+		timeout = pool.execTimeout
+	}
+
+	execCtx, cancel := pool.execContext(ctx, timeout)
 	defer cancel()
 
+	startedAt := pool.clock.Now()
+	defer pool.recordDuration(startedAt)
+
 	log.Debug().
 		Str("source", "gframework").
 		Int("worker_id", workerID).
-		Dur("timeout", pool.execTimeout).
+		Str("executor_name", job.name).
+		Dur("timeout", timeout).
 		Msg("Starting execution for worker")
 
-	err := pool.executor.Execute(execCtx)
+	err := pool.safeRun(execCtx, job.name, job.executor.Execute)
 	if err != nil {
 		log.Error().
 			Str("source", "gframework").
 			Err(err).
 			Int("worker_id", workerID).
+			Str("executor_name", job.name).
 			Msg("Executor failed")
 	}
 }
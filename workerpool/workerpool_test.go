@@ -3,10 +3,12 @@ package workerpool_test
 import (
 	"context"
 	"errors"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/andyle182810/gframework/testutil"
 	"github.com/andyle182810/gframework/workerpool"
 	"github.com/stretchr/testify/require"
 )
@@ -332,3 +334,169 @@ func TestWorkerPool_NoTimeoutAllowsLongRunningTasksToComplete(t *testing.T) {
 		t.Errorf("expected executor to complete without timeout, got %d", count)
 	}
 }
+
+type ctxKey struct{}
+
+func TestWorkerPool_SubmitRunsTasksWithRequestValues(t *testing.T) {
+	t.Parallel()
+
+	pool := workerpool.New(nil, workerpool.WithWorkerCount(2))
+	require.NoError(t, pool.Start(t.Context()))
+
+	defer func() { _ = pool.Stop() }()
+
+	requestCtx, cancel := context.WithCancel(context.WithValue(t.Context(), ctxKey{}, "req-1"))
+
+	result := make(chan string, 1)
+
+	require.NoError(t, pool.Submit(requestCtx, func(ctx context.Context) error {
+		value, _ := ctx.Value(ctxKey{}).(string)
+		result <- value
+
+		return nil
+	}))
+
+	cancel()
+
+	select {
+	case value := <-result:
+		require.Equal(t, "req-1", value)
+	case <-time.After(time.Second):
+		t.Fatal("submitted task did not run")
+	}
+}
+
+func TestWorkerPool_SubmitReturnsErrQueueFull(t *testing.T) {
+	t.Parallel()
+
+	pool := workerpool.New(nil, workerpool.WithWorkerCount(1), workerpool.WithQueueSize(1))
+	require.NoError(t, pool.Start(t.Context()))
+
+	defer func() { _ = pool.Stop() }()
+
+	release := make(chan struct{})
+	running := make(chan struct{})
+
+	require.NoError(t, pool.Submit(t.Context(), func(ctx context.Context) error {
+		close(running)
+
+		select {
+		case <-release:
+		case <-ctx.Done():
+		}
+
+		return nil
+	}))
+
+	<-running
+
+	require.NoError(t, pool.Submit(t.Context(), func(context.Context) error { return nil }))
+	require.ErrorIs(t, pool.Submit(t.Context(), func(context.Context) error { return nil }), workerpool.ErrQueueFull)
+	require.Equal(t, 1, pool.QueueLength())
+
+	close(release)
+}
+
+func TestWorkerPool_SubmitRecoversPanics(t *testing.T) {
+	t.Parallel()
+
+	pool := workerpool.New(nil, workerpool.WithWorkerCount(1))
+	require.NoError(t, pool.Start(t.Context()))
+
+	defer func() { _ = pool.Stop() }()
+
+	require.NoError(t, pool.Submit(t.Context(), func(context.Context) error { panic("boom") }))
+
+	done := make(chan struct{})
+	require.NoError(t, pool.Submit(t.Context(), func(context.Context) error {
+		close(done)
+
+		return nil
+	}))
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("worker did not survive a panicking task")
+	}
+}
+
+func TestWorkerPool_SubmitRequiresRunningPool(t *testing.T) {
+	t.Parallel()
+
+	pool := workerpool.New(nil)
+
+	require.ErrorIs(t, pool.Submit(t.Context(), func(context.Context) error { return nil }), workerpool.ErrNotRunning)
+	require.ErrorIs(t, pool.Submit(t.Context(), nil), workerpool.ErrNilTask)
+}
+
+func TestWorkerPool_SubmitRacingStopLeavesNoQueuedTask(t *testing.T) {
+	t.Parallel()
+
+	for range 50 {
+		pool := workerpool.New(nil, workerpool.WithWorkerCount(1))
+		require.NoError(t, pool.Start(t.Context()))
+
+		pool.Pause()
+
+		var wg sync.WaitGroup
+
+		for range 4 {
+			wg.Go(func() {
+				task := func(context.Context) error { return nil }
+
+				for !errors.Is(pool.Submit(t.Context(), task), workerpool.ErrNotRunning) {
+				}
+			})
+		}
+
+		require.NoError(t, pool.Stop())
+		wg.Wait()
+
+		require.Zero(t, pool.QueueLength(), "a task queued after Stop drained the queue is never run")
+	}
+}
+
+func TestWorkerPool_StopCancelsSubmittedTasks(t *testing.T) {
+	t.Parallel()
+
+	pool := workerpool.New(nil, workerpool.WithWorkerCount(1))
+	require.NoError(t, pool.Start(t.Context()))
+
+	running := make(chan struct{})
+
+	var cancelled atomic.Bool
+
+	require.NoError(t, pool.Submit(t.Context(), func(ctx context.Context) error {
+		close(running)
+		<-ctx.Done()
+		cancelled.Store(true)
+
+		return ctx.Err()
+	}))
+
+	<-running
+	require.NoError(t, pool.Stop())
+	require.True(t, cancelled.Load())
+}
+
+func TestWorkerPool_TicksFollowClock(t *testing.T) {
+	t.Parallel()
+
+	executor := newMockExecutor()
+	clk := testutil.NewFakeClock(t)
+
+	pool := workerpool.New(executor, workerpool.WithTickInterval(time.Hour), workerpool.WithClock(clk))
+
+	require.NoError(t, pool.Start(t.Context()))
+	t.Cleanup(func() { _ = pool.Stop() })
+
+	clk.BlockUntil(1)
+	require.Equal(t, int32(0), executor.execCount.Load())
+
+	clk.Advance(time.Hour)
+	require.Eventually(t, func() bool { return executor.execCount.Load() == 1 }, time.Second, time.Millisecond)
+
+	clk.Advance(time.Hour)
+	require.Eventually(t, func() bool { return executor.execCount.Load() == 2 }, time.Second, time.Millisecond)
+}